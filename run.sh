@@ -17,9 +17,12 @@ echo "Removing old image..."
 docker rmi "$IMAGE_NAME" 2>/dev/null || true
 
 echo "Building fresh image..."
-docker build --no-cache -t "$IMAGE_NAME" .
+docker build --no-cache -t "$IMAGE_NAME" \
+    --build-arg BUILD_COMMIT="$(git rev-parse --short HEAD)" \
+    --build-arg BUILD_DATE="$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+    .
 
 echo "Running container..."
-docker run -d --name "$IMAGE_NAME" --env-file .env "$IMAGE_NAME"
+docker run -d --name "$IMAGE_NAME" --restart unless-stopped --env-file .env "$IMAGE_NAME"
 
 echo "Container started. View logs with: docker logs -f $IMAGE_NAME"
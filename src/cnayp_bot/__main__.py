@@ -1,8 +1,58 @@
 """Entry point for python -m cnayp_bot."""
 
+import argparse
 import asyncio
+import sys
+from datetime import date
+from zoneinfo import ZoneInfo
+
+
+def parse_args() -> argparse.Namespace:
+    """Parse command-line arguments."""
+    parser = argparse.ArgumentParser(prog="cnayp_bot", description="CNAYP Discord bot")
+    subparsers = parser.add_subparsers(dest="command")
+
+    simulate = subparsers.add_parser(
+        "simulate", help="Print what the scheduler would do in a date range"
+    )
+    simulate.add_argument("--from", dest="start", type=date.fromisoformat, required=True)
+    simulate.add_argument("--to", dest="end", type=date.fromisoformat, required=True)
+    simulate.add_argument("--timezone", type=ZoneInfo, default=ZoneInfo("UTC"))
+
+    bootstrap = subparsers.add_parser(
+        "bootstrap", help="Create the recommended channels, role, and starter schedules"
+    )
+    bootstrap.add_argument("--guild", type=int, required=True, help="Guild ID to set up")
+
+    check = subparsers.add_parser(
+        "check", help="Validate the settings and schedules, reporting every problem"
+    )
+    check.add_argument(
+        "--offline", action="store_true", help="Don't check channel names against Discord"
+    )
+
+    return parser.parse_args()
 
-from .main import main
 
 if __name__ == "__main__":
-    asyncio.run(main())
+    args = parse_args()
+
+    # Imported per command, since importing the rest of the bot loads the settings,
+    # which `check` has to report on rather than fail with
+    match args.command:
+        case "simulate":
+            from .simulate import run as run_simulation
+
+            run_simulation(args.start, args.end, args.timezone)
+        case "bootstrap":
+            from .bootstrap import run as run_bootstrap
+
+            asyncio.run(run_bootstrap(args.guild))
+        case "check":
+            from .check import run as run_check
+
+            sys.exit(run_check(args.offline))
+        case _:
+            from .main import main
+
+            asyncio.run(main())
@@ -0,0 +1,172 @@
+"""Set up a guild with the channels, role, and config the bot expects.
+
+Used by `python -m cnayp_bot bootstrap --guild <id>` and the `/setup` command
+so other communities can adopt the bot without configuring Discord by hand.
+Every step is idempotent: existing channels, roles, and files are kept.
+"""
+
+import logging
+from pathlib import Path
+
+import discord
+from discord.ext import commands
+
+from .bot import create_bot
+from .config import settings
+from .services.schedules import save_schedule_config, starter_schedule_config
+
+logger = logging.getLogger(__name__)
+
+CATEGORY_NAME = "Community Events"
+DEFAULT_LOG_CHANNEL = "bot-log"
+ROLE_PICKER = "role"
+BOOTSTRAP = "bootstrap"
+REASON = "CNAYP bot setup"
+
+
+async def bootstrap_guild(bot: commands.Bot, guild: discord.Guild) -> list[str]:
+    """Create whatever is missing from the recommended guild setup.
+
+    Only uses REST calls, so it works both from a command and from the CLI
+    where the bot never connects to the gateway.
+
+    Returns:
+        One line per step describing what was done.
+    """
+    if settings.observer_mode:
+        bot.observer.record("bootstrap guild", guild=guild.id)
+        return ["Observer mode is on, so nothing was changed"]
+
+    report = []
+    channels = await guild.fetch_channels()
+    roles = await guild.fetch_roles()
+
+    role = discord.utils.get(roles, name=settings.notification_role)
+    if role is None:
+        role = await guild.create_role(
+            name=settings.notification_role, mentionable=True, reason=REASON
+        )
+        report.append(f"Created role @{role.name}")
+    else:
+        report.append(f"Role @{role.name} already exists")
+
+    category = discord.utils.get(channels, name=CATEGORY_NAME)
+    if not isinstance(category, discord.CategoryChannel):
+        category = await guild.create_category(CATEGORY_NAME, reason=REASON)
+        report.append(f"Created category {CATEGORY_NAME}")
+
+    notify_channel = await _ensure_text_channel(
+        guild, channels, category, settings.discord_notify_channel, report
+    )
+    await _ensure_text_channel(
+        guild, channels, category, settings.discord_announcements_channel, report
+    )
+    await _ensure_text_channel(
+        guild,
+        channels,
+        category,
+        settings.discord_errors_channel or DEFAULT_LOG_CHANNEL,
+        report,
+        overwrites={
+            guild.default_role: discord.PermissionOverwrite(view_channel=False),
+            discord.Object(bot.user.id, type=discord.Member): discord.PermissionOverwrite(
+                view_channel=True, send_messages=True, embed_links=True
+            ),
+        },
+    )
+    if not settings.discord_errors_channel:
+        report.append(f"Set DISCORD_ERRORS_CHANNEL={DEFAULT_LOG_CHANNEL} to report errors there")
+
+    if discord.utils.get(channels, name=settings.discord_voice_channel) is None:
+        await guild.create_voice_channel(
+            settings.discord_voice_channel, category=category, reason=REASON
+        )
+        report.append(f"Created voice channel {settings.discord_voice_channel}")
+
+    report.append(await _post_role_picker(bot, guild, notify_channel, role))
+
+    bot.tree.copy_global_to(guild=guild)
+    synced = await bot.tree.sync(guild=guild)
+    report.append(f"Registered {len(synced)} slash commands")
+
+    schedules_path = Path(settings.schedules_file)
+    if schedules_path.exists():
+        report.append(f"Kept existing {schedules_path}")
+    else:
+        config = starter_schedule_config(
+            notify_channel=settings.discord_notify_channel,
+            voice_channel=settings.discord_voice_channel,
+            timezone=settings.default_timezone,
+        )
+        save_schedule_config(config, schedules_path)
+        report.append(f"Wrote starter schedules to {schedules_path}")
+
+    logger.info("Bootstrapped guild %s (%d)", guild.name, guild.id)
+    return report
+
+
+async def _ensure_text_channel(
+    guild: discord.Guild,
+    channels: list[discord.abc.GuildChannel],
+    category: discord.CategoryChannel,
+    name: str,
+    report: list[str],
+    overwrites: dict | None = None,
+) -> discord.TextChannel:
+    """Return the text channel called `name`, creating it if needed."""
+    channel = discord.utils.get(channels, name=name)
+    if isinstance(channel, discord.TextChannel):
+        report.append(f"#{name} already exists")
+        return channel
+
+    channel = await guild.create_text_channel(
+        name, category=category, overwrites=overwrites or {}, reason=REASON
+    )
+    report.append(f"Created #{name}")
+    return channel
+
+
+async def _post_role_picker(
+    bot: commands.Bot,
+    guild: discord.Guild,
+    channel: discord.TextChannel,
+    role: discord.Role,
+) -> str:
+    """Post the notification role picker unless it's still there."""
+    key = str(guild.id)
+    picker = bot.store.get(BOOTSTRAP, key)
+    if picker:
+        try:
+            await channel.fetch_message(picker["message_id"])
+            return f"Role picker already posted in #{channel.name}"
+        except discord.NotFound:
+            pass
+
+    embed = discord.Embed(
+        title="🔔 Event notifications",
+        description=f"Press the button to get or drop <@&{role.id}> and hear about new events.",
+        color=discord.Color.blue(),
+    )
+    view = discord.ui.View(timeout=None)
+    view.add_item(
+        bot.components.button(
+            ROLE_PICKER,
+            str(role.id),
+            label="Toggle notifications",
+            emoji="🔔",
+            style=discord.ButtonStyle.primary,
+        )
+    )
+    message = await bot.messenger.send(channel, embed=embed, view=view)
+    bot.store.set(BOOTSTRAP, key, {"channel_id": channel.id, "message_id": message.id})
+    return f"Posted role picker in #{channel.name}"
+
+
+async def run(guild_id: int) -> None:
+    """Bootstrap a guild from the command line and print what was done."""
+    bot = create_bot()
+    async with bot:
+        await bot.login(settings.discord_bot_token)
+        guild = await bot.fetch_guild(guild_id)
+        for line in await bootstrap_guild(bot, guild):
+            print(f"- {line}")
@@ -1,15 +1,156 @@
 """CNAYP Discord Bot."""
 
+import asyncio
+import hashlib
+import json
 import logging
+from datetime import timedelta
+from pathlib import Path
+from typing import Any
 
 import discord
+from discord import app_commands
 from discord.ext import commands
 
+from .cogs.scheduler import _held_in
 from .config import settings
+from .helpers.embeds import FIELD_NAME_LIMIT, EmbedBuilder
+from .helpers.i18n import CatalogTranslator, context_locale, translate
+from .services.absences import Absences
+from .services.activity import ActivityTracker
+from .services.alertmanager import AlertGroups
+from .services.audit import AuditTrail
 from .services.calendar import CalendarService
+from .services.canary import Canary
+from .services.checklists import Checklists
+from .services.components import ComponentRouter
+from .services.config_snapshots import ConfigSnapshots
+from .services.cover_images import CoverImages
+from .services.crash_reports import watch_task
+from .services.discord_api import configure_http
+from .services.dm_reminders import DmReminders
+from .services.edit_history import EditHistory
+from .services.experiments import AnnouncementExperiments
+from .services.feeds import FeedSubscriptions
+from .services.governor import CLOSED, OPEN, CircuitBreaker, Priority, RateGovernor
+from .services.history import EventHistory
+from .services.in_flight import InFlightWork
+from .services.interest import InterestTracker
+from .services.leader import LeaderElection, owns_guild
+from .services.maintenance import Maintenance
+from .services.meetings import MeetingLinks
+from .services.message_cache import MessageCache
+from .services.messenger import Messenger
+from .services.observer import Observer
+from .services.partials import TemplatePartials
+from .services.partners import PartnerAnnouncements
+from .services.peers import PeerNetwork
+from .services.recaps import RecapCollection
+from .services.role_grants import RoleGrants
+from .services.rsvps import RsvpList
+from .services.schedule_sheet import ImportedSchedules
+from .services.schedules import ScheduleService, is_visible_to
+from .services.slot_finder import SlotFinder
+from .services.slowmode import SlowmodeOverrides
+from .services.statuspage import IncidentTracker
+from .services.store import Store
+from .services.sponsors import SponsorRotation
+from .services.submissions import SubmissionQueue
+from .services.template_values import TemplateValues
+from .services.topic_votes import TopicVotes
+from .services.verification import VerificationGate
+from .services.welcome import WelcomeSequence
 
 logger = logging.getLogger(__name__)
 
+MAX_LISTED_EVENTS = 10
+
+# Digest of the slash commands last registered, so unchanged commands aren't resynced
+SLASH_COMMANDS = "slash_commands"
+
+# Discord events a standby replica still handles, logging its own connection
+STANDBY_EVENTS = frozenset({"connect", "disconnect", "ready", "resumed"})
+
+EXTENSIONS = (
+    "cnayp_bot.cogs.errors",
+    "cnayp_bot.cogs.help",
+    "cnayp_bot.cogs.leader",
+    "cnayp_bot.cogs.maintenance",
+    "cnayp_bot.cogs.canary",
+    "cnayp_bot.cogs.scheduler",
+    "cnayp_bot.cogs.schedules",
+    "cnayp_bot.cogs.config",
+    "cnayp_bot.cogs.schedule_sheet",
+    "cnayp_bot.cogs.help_digest",
+    "cnayp_bot.cogs.digest",
+    "cnayp_bot.cogs.reminders",
+    "cnayp_bot.cogs.absences",
+    "cnayp_bot.cogs.checklists",
+    "cnayp_bot.cogs.recaps",
+    "cnayp_bot.cogs.voice_names",
+    "cnayp_bot.cogs.topics",
+    "cnayp_bot.cogs.topic_votes",
+    "cnayp_bot.cogs.slot_finder",
+    "cnayp_bot.cogs.onboarding",
+    "cnayp_bot.cogs.screening",
+    "cnayp_bot.cogs.welcome",
+    "cnayp_bot.cogs.verification",
+    "cnayp_bot.cogs.roles",
+    "cnayp_bot.cogs.rsvps",
+    "cnayp_bot.cogs.stats",
+    "cnayp_bot.cogs.botstats",
+    "cnayp_bot.cogs.export",
+    "cnayp_bot.cogs.console",
+    "cnayp_bot.cogs.attendance",
+    "cnayp_bot.cogs.activity",
+    "cnayp_bot.cogs.tags",
+    "cnayp_bot.cogs.submissions",
+    "cnayp_bot.cogs.releases",
+    "cnayp_bot.cogs.alerts",
+    "cnayp_bot.cogs.status_pages",
+    "cnayp_bot.cogs.feeds",
+    "cnayp_bot.cogs.updates",
+    "cnayp_bot.cogs.presence",
+    "cnayp_bot.cogs.sponsors",
+    "cnayp_bot.cogs.partners",
+    "cnayp_bot.cogs.peers",
+    "cnayp_bot.cogs.watchdog",
+    # Before automod, so messages are cached before it can quarantine them
+    "cnayp_bot.cogs.message_log",
+    "cnayp_bot.cogs.automod",
+    "cnayp_bot.cogs.automod_rules",
+)
+
+
+class CNAYPTree(app_commands.CommandTree):
+    """Command tree that only records slash commands in observer mode.
+
+    Standby replicas leave slash commands to the leader, which gets them too.
+    """
+
+    async def interaction_check(self, interaction: discord.Interaction) -> bool:
+        """Skip slash commands on standby replicas and in observer mode."""
+        if interaction.client.leader.is_standby:
+            return False
+        if not settings.observer_mode:
+            return True
+
+        interaction.client.observer.record(
+            "run command",
+            command=interaction.command.qualified_name if interaction.command else None,
+            user=interaction.user.id,
+            channel=interaction.channel_id,
+        )
+        return False
+
+    async def on_error(
+        self, interaction: discord.Interaction, error: app_commands.AppCommandError
+    ) -> None:
+        """Log errors, except for the commands a standby replica left to the leader."""
+        if isinstance(error, app_commands.CheckFailure) and interaction.client.leader.is_standby:
+            return
+        await super().on_error(interaction, error)
+
 
 class CNAYPBot(commands.Bot):
     """Main bot class for CNAYP Discord."""
@@ -18,61 +159,262 @@ class CNAYPBot(commands.Bot):
         intents = discord.Intents.default()
         intents.message_content = True
         intents.guilds = True
+        # Scheduled event interest is only reported for members in the cache
+        intents.members = True
 
-        super().__init__(command_prefix="!", intents=intents)
+        super().__init__(
+            command_prefix="!",
+            intents=intents,
+            tree_cls=CNAYPTree,
+            shard_id=settings.shard_id,
+            shard_count=settings.shard_count,
+            heartbeat_timeout=settings.gateway_heartbeat_timeout,
+        )
+        # Before logging in, so every request goes to the configured version
+        configure_http(self.http, settings.discord_api_version, settings.discord_user_agent)
         self.calendar = CalendarService()
+        self.store = Store(Path(settings.store_path))
+        self.schedules = ScheduleService(
+            Path(settings.schedules_file), ConfigSnapshots(self.store)
+        )
+        self.partials = TemplatePartials(Path(settings.templates_dir))
+        self.cover_images = CoverImages(Path(settings.schedules_file).parent)
+        self.messenger = Messenger(self)
+        self.governor = RateGovernor(
+            CircuitBreaker(
+                settings.circuit_failure_threshold,
+                timedelta(seconds=settings.circuit_cooldown_seconds),
+                on_change=self._on_circuit_change,
+            )
+        )
+        self.observer = Observer(self.store)
+        self.maintenance = Maintenance(self.store)
+        # The loops' work the shutdown waits for
+        self.in_flight = InFlightWork()
+        self.leader = LeaderElection(
+            Path(settings.leader_lease_path) if settings.leader_lease_path else None,
+            settings.instance_id,
+            timedelta(seconds=settings.leader_lease_seconds),
+            eligible=owns_guild(settings.discord_guild_id, settings.shard_id, settings.shard_count),
+        )
+        self.experiments = AnnouncementExperiments(self.store)
+        self.interest = InterestTracker(self.store)
+        self.dm_reminders = DmReminders(self.store)
+        self.activity = ActivityTracker(self.store)
+        self.submissions = SubmissionQueue(self.store)
+        self.absences = Absences(self.store)
+        self.welcome = WelcomeSequence(self.store)
+        self.verification = VerificationGate(self.store)
+        self.role_grants = RoleGrants(self.store)
+        self.rsvps = RsvpList(self.store)
+        self.slowmode = SlowmodeOverrides(self.store)
+        self.sponsors = SponsorRotation(self.store)
+        self.peers = PeerNetwork(settings.peer_name, settings.peer_bots)
+        self.imported_schedules = ImportedSchedules(self.store)
+        self.history = EventHistory(self.store)
+        self.alert_groups = AlertGroups(self.store)
+        self.incidents = IncidentTracker(self.store)
+        self.feeds = FeedSubscriptions(self.store)
+        self.message_cache = MessageCache(settings.message_cache_size)
+        self.edit_history = EditHistory(self.store, timedelta(days=settings.edit_log_days))
+        self.topic_votes = TopicVotes(self.store)
+        self.slot_finder = SlotFinder(self.store)
+        self.checklists = Checklists(self.store)
+        self.recaps = RecapCollection(self.store)
+        self.partners = PartnerAnnouncements(self.store)
+        self.audit = AuditTrail(self.store)
+        self.meetings = MeetingLinks(self.store)
+        self.template_values = TemplateValues(self.store)
+        self.canary = Canary(
+            self.store,
+            settings.canary_channel,
+            settings.canary_features,
+            timedelta(days=settings.canary_days),
+        )
+        secret = settings.component_secret or hashlib.sha256(
+            settings.discord_bot_token.encode()
+        ).hexdigest()
+        self.components = ComponentRouter(secret.encode())
+        # Set on the first on_ready, so later ones are known to be reconnects
+        self._was_ready = False
 
     async def setup_hook(self) -> None:
         """Called when the bot is starting up."""
-        await self.load_extension("cnayp_bot.cogs.scheduler")
-        logger.info("Loaded scheduler cog")
+        self.governor.install(self.http)
+        if settings.observer_mode:
+            self.observer.guard_http(self.http)
+            logger.warning("Observer mode: recording actions without writing to Discord")
+
+        for extension in EXTENSIONS:
+            await self.load_extension(extension)
+            logger.info("Loaded extension %s", extension)
+        await self.tree.set_translator(CatalogTranslator())
+
+        if settings.sync_commands and not settings.observer_mode:
+            try:
+                await self.sync_commands()
+            except discord.HTTPException as e:
+                logger.error("Failed to register slash commands: %s", e)
+
+    def _on_circuit_change(self, state: str) -> None:
+        """Tell ops when the Discord API circuit opens, and when it closes again."""
+        if state not in (OPEN, CLOSED):
+            return
+        watch_task(asyncio.create_task(self._alert_circuit(state)), "circuit_breaker")
+
+    async def _alert_circuit(self, state: str) -> None:
+        """Post the circuit's new state in the ops channel."""
+        # Critical, so the alert goes out while the circuit holds other requests back
+        self.governor.tag("circuit_breaker", Priority.CRITICAL)
+        if state == OPEN:
+            message = (
+                f"🔌 Discord's API keeps failing, so background work is paused and other "
+                f"messages are held back for {settings.circuit_cooldown_seconds} seconds."
+            )
+        else:
+            message = "✅ Discord's API is answering again, resuming."
+        await self.messenger.alert_ops(message)
+        await self.messenger.audit_log(message)
+
+    async def sync_commands(self) -> None:
+        """Register the slash commands in each guild, if they changed since the last sync.
+
+        Guild commands show up right away, unlike global ones, and Discord
+        limits how often they can be registered, so unchanged commands are
+        skipped.
+        """
+        for guild_id in settings.discord_guild_ids:
+            guild = discord.Object(guild_id)
+            self.tree.copy_global_to(guild=guild)
+            # Translated, so the commands are registered again when the catalog changes
+            payload = [
+                await command.get_translated_payload(self.tree, self.tree.translator)
+                for command in self.tree.get_commands(guild=guild)
+            ]
+            digest = hashlib.sha256(json.dumps(payload, sort_keys=True).encode()).hexdigest()
+            if self.store.get(SLASH_COMMANDS, str(guild.id)) == digest:
+                logger.info("Slash commands unchanged in guild %d, not registering them", guild.id)
+                continue
+
+            synced = await self.tree.sync(guild=guild)
+            self.store.set(SLASH_COMMANDS, str(guild.id), digest)
+            logger.info("Registered %d slash commands in guild %d", len(synced), guild.id)
+
+    def dispatch(self, event_name: str, /, *args: Any, **kwargs: Any) -> None:
+        """Pass Discord events to listeners and commands, unless this replica is a standby.
+
+        The leader gets the same events, so a standby only keeps its cache up
+        to date, ready to take over.
+        """
+        if self.leader.is_standby and event_name not in STANDBY_EVENTS:
+            return
+        super().dispatch(event_name, *args, **kwargs)
+
+    async def on_interaction(self, interaction: discord.Interaction) -> None:
+        """Route button and select interactions to their registered handlers."""
+        if interaction.type != discord.InteractionType.component:
+            return
+
+        if settings.observer_mode:
+            custom_id = (interaction.data or {}).get("custom_id", "")
+            self.observer.record("handle component", custom_id=custom_id, user=interaction.user.id)
+            return
+
+        await self.components.dispatch(interaction)
+
+    async def invoke(self, ctx: commands.Context) -> None:
+        """Run a command, or only record it in observer mode."""
+        if settings.observer_mode and ctx.command:
+            self.observer.record(
+                "run command",
+                command=ctx.command.qualified_name,
+                user=ctx.author.id,
+                channel=ctx.channel.id,
+            )
+            return
+
+        await super().invoke(ctx)
 
     async def on_ready(self) -> None:
-        """Called when the bot is ready."""
+        """Called when the bot is ready, and again when it reconnects with a new session."""
         logger.info("Bot is ready! Logged in as %s", self.user)
-        logger.info("Connected to guild: %d", settings.discord_guild_id)
+        for guild_id in settings.discord_guild_ids:
+            logger.info("Connected to guild: %d", guild_id)
+
+        if self._was_ready:
+            # Events sent while disconnected were missed, unlike after a resume
+            await self.messenger.audit_log(
+                f"🔁 `{settings.instance_id}` reconnected to Discord with a new session; "
+                "anything that happened while it was disconnected was missed."
+            )
+        self._was_ready = True
+
+    async def on_resumed(self) -> None:
+        """Called when the bot reconnects and resumes its session, missing nothing."""
+        logger.info("Resumed the gateway session")
+        await self.messenger.audit_log(
+            f"🔁 `{settings.instance_id}` lost its connection to Discord and resumed it."
+        )
 
 
 def create_bot() -> CNAYPBot:
     """Create and configure the bot instance."""
     bot = CNAYPBot()
 
-    @bot.command()
-    async def ping(ctx: commands.Context) -> None:
-        """Respond with pong."""
-        await ctx.send("Pong!")
-
-    @bot.command(name="events")
+    @bot.hybrid_command(name="events")
     async def list_events(ctx: commands.Context, days: int = 7) -> None:
-        """List upcoming events from Google Calendar.
+        """List upcoming events from Google Calendar, the schedules file, and submissions.
+
+        Only the events held in the guild the command is used in are listed (in
+        DMs, the primary guild's), and private events only to members holding
+        their audience role.
 
         Usage: !events [days]
         Example: !events 14 (shows events for next 14 days)
+        Example: /horario 14 (the same in a Spanish Discord client)
         """
+        locale = context_locale(ctx)
         hours = days * 24
         events = bot.calendar.get_upcoming_events(hours_ahead=hours)
+        events += bot.schedules.get_upcoming_events(hours_ahead=hours)
+        events += bot.submissions.get_upcoming_events(hours_ahead=hours)
+        guild_id = ctx.guild.id if ctx.guild else settings.discord_guild_id
+        role_names = {role.name for role in getattr(ctx.author, "roles", [])}
+        events = [
+            event
+            for event in events
+            if _held_in(event, guild_id) and is_visible_to(event, role_names)
+        ]
+        events.sort(key=lambda event: event.start_time)
 
         if not events:
-            await ctx.send(f"No events scheduled in the next {days} days.")
+            await ctx.send(
+                translate("No events scheduled in the next {days} days.", locale).format(days=days)
+            )
             return
 
-        embed = discord.Embed(
-            title=f"Upcoming Events ({days} days)",
-            color=discord.Color.blue(),
+        builder = (
+            EmbedBuilder()
+            .set_title(translate("Upcoming Events ({days} days)", locale).format(days=days))
+            .set_color(discord.Color.blue())
         )
 
-        for event in events[:10]:  # Limit to 10 events
+        for event in events[:MAX_LISTED_EVENTS]:
             time_str = f"<t:{int(event.start_time.timestamp())}:F>"
             relative_str = f"<t:{int(event.start_time.timestamp())}:R>"
-            embed.add_field(
-                name=event.name,
-                value=f"{time_str}\n{relative_str}\nDuration: {event.duration_minutes} min",
-                inline=False,
+            name = event.name[:FIELD_NAME_LIMIT]
+            duration = translate("Duration: {minutes} min", locale).format(
+                minutes=event.duration_minutes
             )
+            value = f"{time_str}\n{relative_str}\n{duration}"
+            if not builder.can_add_field(name, value):
+                break
+            builder.add_field(name=name, value=value)
 
-        if len(events) > 10:
-            embed.set_footer(text=f"Showing 10 of {len(events)} events")
+        if builder.field_count < len(events):
+            footer = translate("Showing {shown} of {total} events", locale)
+            builder.set_footer(text=footer.format(shown=builder.field_count, total=len(events)))
 
-        await ctx.send(embed=embed)
+        await ctx.send(embed=builder.build())
 
     return bot
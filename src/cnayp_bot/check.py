@@ -0,0 +1,170 @@
+"""Validate the configuration without starting the bot.
+
+Used by `python -m cnayp_bot check` before deploying a config change. Every
+problem found is reported at once, rather than the first one at startup or
+a missing channel when an event comes up: unreadable config files, unknown or
+invalid settings, malformed schedules (bad timezones, times, or days), missing
+cover image files, and, unless offline, channels the config names that aren't
+in their guild.
+"""
+
+import asyncio
+from collections import defaultdict
+from collections.abc import Iterator
+from pathlib import Path
+
+import discord
+from pydantic import ValidationError
+from pydantic_settings import DotEnvSettingsSource, EnvSettingsSource
+
+from .config import ConfigFileSource, Settings
+from .helpers.config_file import read_config_file
+from .models import ScheduleConfig
+from .services.cover_images import CoverImageError, CoverImages, image_type, is_url
+
+
+def run(offline: bool = False) -> int:
+    """Print the configuration's problems, and return the exit status: 1 if there are any."""
+    problems = check(offline)
+    for problem in problems:
+        print(f"✗ {problem}")
+    if problems:
+        print(f"{len(problems)} problem{'s' if len(problems) != 1 else ''} found")
+        return 1
+    print("✓ The configuration is valid")
+    return 0
+
+
+def check(offline: bool = False) -> list[str]:
+    """Return the configuration's problems, one line each."""
+    problems: list[str] = []
+
+    source = ConfigFileSource(Settings, EnvSettingsSource(Settings), DotEnvSettingsSource(Settings))
+    config_file = source.path()
+    if config_file:
+        try:
+            table = source()
+        except (OSError, ValueError) as error:
+            # Nothing else can be read from a file that doesn't parse
+            return [f"{config_file}: {error}"]
+        problems += [
+            f"{config_file} [settings]: unknown setting {key}"
+            for key in sorted(table.keys() - Settings.model_fields.keys())
+        ]
+
+    settings = None
+    try:
+        settings = Settings()
+    except ValidationError as error:
+        problems += [f"settings: {line}" for line in _errors(error)]
+
+    schedules_file = Path(settings.schedules_file if settings else config_file or "schedules.json")
+    config = None
+    try:
+        # Read here rather than with the schedule service, whose module needs valid settings
+        if schedules_file.exists():
+            config = ScheduleConfig.model_validate(read_config_file(schedules_file))
+    except ValidationError as error:
+        problems += [f"{schedules_file}: {line}" for line in _errors(error)]
+    except (OSError, ValueError) as error:
+        problems.append(f"{schedules_file}: {error}")
+
+    if config:
+        problems += _check_images(config, CoverImages(schedules_file.parent))
+    if settings and config and not offline:
+        problems += asyncio.run(_check_channels(settings, config))
+    return problems
+
+
+def _errors(error: ValidationError) -> Iterator[str]:
+    """Format a validation error's errors as `location: message`."""
+    for item in error.errors():
+        location = ".".join(str(part) for part in item["loc"])
+        message = item["msg"].removeprefix("Value error, ")
+        yield f"{location}: {message}" if location else message
+
+
+def _check_images(config: ScheduleConfig, images: CoverImages) -> list[str]:
+    """Report cover image files that can't be read or aren't images; URLs aren't fetched."""
+    problems = []
+    for schedule in config.schedules:
+        if not schedule.image or is_url(schedule.image):
+            continue
+        path = images.path(schedule.image)
+        try:
+            image_type(path.read_bytes())
+        except OSError as error:
+            problems.append(f"schedule {schedule.name!r} image: {path}: {error.strerror or error}")
+        except CoverImageError as error:
+            problems.append(f"schedule {schedule.name!r} image: {error}")
+    return problems
+
+
+def _channel_references(
+    settings: Settings, config: ScheduleConfig
+) -> Iterator[tuple[int, str, str]]:
+    """Yield the guild, name, and where it's set of every channel the config names."""
+    primary = settings.discord_guild_id
+    for name, value in (
+        ("DISCORD_NOTIFY_CHANNEL", settings.discord_notify_channel),
+        ("DISCORD_VOICE_CHANNEL", settings.discord_voice_channel),
+        ("DISCORD_ERRORS_CHANNEL", settings.discord_errors_channel),
+        ("DISCORD_OPS_CHANNEL", settings.discord_ops_channel),
+        ("STAFF_CHANNEL", settings.staff_channel),
+    ):
+        if value:
+            yield primary, value, name
+    for schedule in config.schedules:
+        guild_id = schedule.guild_id or primary
+        where = f"schedule {schedule.name!r}"
+        yield guild_id, schedule.voice_channel, f"{where} voice_channel"
+        yield guild_id, schedule.notify_channel, f"{where} notify_channel"
+        if schedule.slowmode_channel:
+            yield guild_id, schedule.slowmode_channel, f"{where} slowmode_channel"
+        for mirror in schedule.mirrors:
+            yield mirror.guild_id, mirror.voice_channel, f"{where} mirror voice_channel"
+            yield mirror.guild_id, mirror.notify_channel, f"{where} mirror notify_channel"
+    if config.digest_channel:
+        yield primary, config.digest_channel, "digest_channel"
+    if config.weekly_digest_channel:
+        yield primary, config.weekly_digest_channel, "weekly_digest_channel"
+    if config.sponsorship.channel:
+        yield primary, config.sponsorship.channel, "sponsorship channel"
+    for partner in config.partners:
+        yield partner.guild_id, partner.channel, f"partner {partner.name!r} channel"
+
+
+async def _check_channels(settings: Settings, config: ScheduleConfig) -> list[str]:
+    """Fetch the channels of each guild the config names, and report the missing ones."""
+    references = defaultdict(list)
+    for guild_id, name, where in _channel_references(settings, config):
+        references[guild_id].append((name, where))
+
+    problems = []
+    client = discord.Client(intents=discord.Intents.none())
+    try:
+        await client.login(settings.discord_bot_token)
+    except discord.LoginFailure:
+        await client.close()
+        return ["settings: the Discord bot token was rejected, so channels weren't checked"]
+
+    try:
+        for guild_id, names in references.items():
+            try:
+                guild = await client.fetch_guild(guild_id)
+                # Threads too, where private events can be announced
+                channels = {
+                    channel.name
+                    for channel in [*await guild.fetch_channels(), *await guild.active_threads()]
+                }
+            except discord.HTTPException:
+                problems.append(f"guild {guild_id}: not found, or the bot isn't in it")
+                continue
+            problems += [
+                f"{where}: no channel {name!r} in {guild.name}"
+                for name, where in names
+                if name not in channels
+            ]
+    finally:
+        await client.close()
+    return problems
@@ -0,0 +1,117 @@
+"""Away notices for schedule owners, so their events never silently lose a host."""
+
+import logging
+from datetime import date, datetime, time, timedelta
+
+import discord
+from discord.ext import commands
+
+from ..services.calendar import CalendarEvent
+from .reminders import user_timezone
+
+logger = logging.getLogger(__name__)
+
+
+class AbsencesCog(commands.Cog):
+    """Lets schedule owners announce when they're away.
+
+    While an owner is away, the daily digest flags their events, and the
+    schedule's co-hosts are sent a DM listing the events they may need to lead.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.hybrid_command(name="away")
+    @commands.guild_only()
+    async def away(self, ctx: commands.Context, start: str, end: str, *, reason: str) -> None:
+        """Tell co-hosts you can't host your events between two dates (inclusive).
+
+        Usage: !away <from> <to> <reason>
+        Example: !away 2025-03-10 2025-03-16 Vacation
+        """
+        owned = [
+            schedule
+            for schedule in self.bot.schedules.config.schedules
+            if ctx.author.id in schedule.owners
+        ]
+        if not owned:
+            await ctx.send("Only schedule owners can set an away notice.")
+            return
+
+        try:
+            first_day = date.fromisoformat(start)
+            last_day = date.fromisoformat(end)
+        except ValueError:
+            await ctx.send("Dates must look like `2025-03-10`.")
+            return
+        if last_day < first_day:
+            await ctx.send("The end date is before the start date.")
+            return
+
+        tz = user_timezone(self.bot, ctx.author.id)
+        away_from = datetime.combine(first_day, time.min, tzinfo=tz)
+        away_until = datetime.combine(last_day + timedelta(days=1), time.min, tzinfo=tz)
+        self.bot.absences.set(ctx.author.id, away_from, away_until, reason)
+        logger.info("%s is away from %s to %s: %s", ctx.author, first_day, last_day, reason)
+
+        events = [
+            event
+            for event in self.bot.schedules.get_events_between(away_from, away_until)
+            if ctx.author.id in event.schedule.owners
+        ]
+        notified = await self._notify_co_hosts(ctx.author, events, reason)
+
+        summary = f"Away from {first_day} to {last_day}. Flagged {len(events)} events"
+        summary += f" and notified {notified} co-hosts." if notified else "."
+        await ctx.send(summary)
+
+    @commands.hybrid_command(name="back")
+    @commands.guild_only()
+    async def back(self, ctx: commands.Context) -> None:
+        """Remove your away notice.
+
+        Usage: !back
+        """
+        if self.bot.absences.clear(ctx.author.id):
+            await ctx.send("Welcome back! Your away notice was removed.")
+        else:
+            await ctx.send("You don't have an away notice.")
+
+    async def _notify_co_hosts(
+        self, owner: discord.abc.User, events: list[CalendarEvent], reason: str
+    ) -> int:
+        """DM each co-host the events they may need to lead.
+
+        Returns:
+            The number of co-hosts reached.
+        """
+        by_co_host: dict[int, list[CalendarEvent]] = {}
+        for event in events:
+            for co_host in event.schedule.co_hosts:
+                if co_host != owner.id:
+                    by_co_host.setdefault(co_host, []).append(event)
+
+        notified = 0
+        for co_host, co_host_events in by_co_host.items():
+            lines = [
+                f"• <t:{int(event.start_time.timestamp())}:F> **{event.name}**"
+                for event in co_host_events
+            ]
+            message = (
+                f"**{owner.display_name}** is away ({reason}) and can't host:\n"
+                + "\n".join(lines)
+                + "\nPlease lead these sessions or let members know they're canceled."
+            )
+            try:
+                user = self.bot.get_user(co_host) or await self.bot.fetch_user(co_host)
+                if await self.bot.messenger.send(user, message):
+                    notified += 1
+            except discord.HTTPException as e:
+                logger.warning("Failed to notify co-host %d: %s", co_host, e)
+        return notified
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the absences cog."""
+    await bot.add_cog(AbsencesCog(bot))
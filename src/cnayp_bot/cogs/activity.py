@@ -0,0 +1,311 @@
+"""Channel activity tracking, moderator reports, and monthly inactivity reports."""
+
+import logging
+from datetime import date, datetime, timedelta
+from typing import Literal
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.charts import bar_chart
+from ..helpers.embeds import EmbedBuilder
+from ..services.governor import Priority
+from ..services.guild_cache import guild_members
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+PERIOD_DAYS = {"daily": 1, "weekly": 7, "monthly": 30}
+
+# Rows shown in each chart of the report
+TOP_ROWS = 8
+
+# Members who asked to be left out of inactivity reports
+INACTIVITY_OPT_OUT = "inactivity_opt_out"
+# Month ("YYYY-MM") the last inactivity report was posted for
+INACTIVITY_REPORTS = "inactivity_reports"
+
+
+class ActivityCog(commands.Cog):
+    """Counts messages, active members, emoji, and reactions per channel.
+
+    It also remembers the day each member last posted, reacted, or joined a
+    voice channel, and with `inactivity_days` set, lists the members inactive
+    that long in the staff channel once a month. Nobody is removed: the list
+    is for organizers to reach out or prune by hand.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.flush_loop.start()
+        if settings.inactivity_days:
+            self.inactivity_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.flush_loop.cancel()
+        self.inactivity_loop.cancel()
+        self.bot.activity.flush(self._today())
+
+    def _today(self) -> date:
+        """Return today's date in the default timezone."""
+        return datetime.now(ZoneInfo(settings.default_timezone)).date()
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Count messages members post in the guild."""
+        if message.author.bot or not message.guild:
+            return
+        if message.guild.id != settings.discord_guild_id:
+            return
+
+        self.bot.activity.record_message(
+            self._today(), message.channel.id, message.author.id, message.content
+        )
+
+    @commands.Cog.listener()
+    async def on_raw_reaction_add(self, payload: discord.RawReactionActionEvent) -> None:
+        """Count reactions members add in the guild."""
+        if payload.guild_id != settings.discord_guild_id:
+            return
+        if payload.member and payload.member.bot:
+            return
+
+        self.bot.activity.record_reaction(self._today(), str(payload.emoji), payload.user_id)
+
+    @commands.Cog.listener()
+    async def on_voice_state_update(
+        self, member: discord.Member, before: discord.VoiceState, after: discord.VoiceState
+    ) -> None:
+        """Remember members joining voice channels in the guild."""
+        if member.bot or member.guild.id != settings.discord_guild_id:
+            return
+        if after.channel and after.channel != before.channel:
+            self.bot.activity.record_voice(self._today(), member.id)
+
+    @tasks.loop(minutes=1)
+    async def flush_loop(self) -> None:
+        """Write buffered activity counts to the store."""
+        if not self.bot.leader.is_leader:
+            return
+        try:
+            self.bot.activity.flush(self._today())
+        except Exception as e:
+            logger.exception("Error flushing activity: %s", e)
+
+    @tasks.loop(hours=1)
+    @drained("inactivity report")
+    async def inactivity_loop(self) -> None:
+        """Post the inactivity report once a month, as soon as the month starts."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+        month = self._today().strftime("%Y-%m")
+        if self.bot.store.get(INACTIVITY_REPORTS, "month") == month:
+            return
+
+        self.bot.governor.tag("inactivity", Priority.BACKGROUND)
+        try:
+            if await self.post_inactivity_report(settings.inactivity_days):
+                self.bot.store.set(INACTIVITY_REPORTS, "month", month)
+        except Exception as e:
+            logger.exception("Error posting the inactivity report: %s", e)
+
+    @inactivity_loop.before_loop
+    async def before_inactivity_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def post_inactivity_report(self, days: int) -> bool:
+        """Post the members inactive for `days` in the staff channel.
+
+        Returns:
+            False if nothing was posted: the staff channel is missing, or
+            activity hasn't been tracked for `days` yet.
+        """
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        name = settings.staff_channel or settings.discord_ops_channel
+        channel = guild and name and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Staff channel not found: %s", name)
+            return False
+
+        self.bot.activity.flush(self._today())
+        report = await self.inactivity_report(guild, days)
+        if report is None:
+            logger.info("Activity hasn't been tracked for %d days, no inactivity report", days)
+            return False
+
+        await self.bot.messenger.send_parts(
+            channel, report, allowed_mentions=discord.AllowedMentions.none()
+        )
+        logger.info("Posted the inactivity report in #%s", channel.name)
+        return True
+
+    async def inactivity_report(self, guild: discord.Guild, days: int) -> str | None:
+        """List the members with no activity in `days`, longest inactive first.
+
+        Bots, members who joined less than `days` ago, members with an exempt
+        role, and members who opted out are left out.
+
+        Returns:
+            The report, or None if activity hasn't been tracked that long.
+        """
+        since = self.bot.activity.last_active_since()
+        cutoff = self._today() - timedelta(days=days)
+        if since is None or since > cutoff:
+            return None
+
+        exempt = set(settings.inactivity_exempt_roles)
+        candidates = [
+            member.id
+            for member in await guild_members(guild)
+            if not member.bot
+            and member.joined_at
+            and member.joined_at.date() <= cutoff
+            and not exempt & {role.name for role in member.roles}
+            and not self.bot.store.get(INACTIVITY_OPT_OUT, str(member.id))
+        ]
+        inactive = self.bot.activity.inactive(candidates, cutoff)
+        if not inactive:
+            return f"✅ Every member was active in the last {days} days."
+
+        lines = [
+            f"📉 **{len(inactive)} of {len(candidates)} members** had no messages, reactions, "
+            f"or voice activity in the last {days} days. Nobody was removed; reach out or "
+            "prune by hand:"
+        ]
+        for member_id, day in sorted(inactive.items(), key=lambda item: item[1] or date.min):
+            last = f"last active {day:%b %d, %Y}" if day else f"not seen since {since:%b %d, %Y}"
+            lines.append(f"- <@{member_id}> ({last})")
+        return "\n".join(lines)
+
+    @commands.hybrid_group(name="activity")
+    @commands.guild_only()
+    async def activity(self, ctx: commands.Context) -> None:
+        """Server activity reports.
+
+        Usage: !activity report [daily|weekly|monthly] | inactive [days] | optout <on|off>
+        """
+        await ctx.send_help(ctx.command)
+
+    @activity.command(name="report")
+    @commands.has_permissions(manage_messages=True)
+    async def report(
+        self, ctx: commands.Context, period: Literal["daily", "weekly", "monthly"] = "weekly"
+    ) -> None:
+        """Show the busiest channels, active members, and top emoji (moderators only).
+
+        Usage: !activity report [daily|weekly|monthly]
+        Example: !activity report weekly
+        """
+        self.bot.activity.flush(self._today())
+        end = self._today()
+        start = end - timedelta(days=PERIOD_DAYS[period] - 1)
+        report = self.bot.activity.report(start, end)
+
+        if not report.messages and not report.reactions:
+            await ctx.send(f"No activity recorded since {start:%b %d}.")
+            return
+
+        def channel_name(channel_id: int) -> str:
+            channel = ctx.guild.get_channel(channel_id)
+            return f"#{channel.name}" if channel else f"#{channel_id}"
+
+        busiest = report.messages.most_common(TOP_ROWS)
+        messages = [(channel_name(channel_id), count) for channel_id, count in busiest]
+        members = sorted(
+            (
+                (channel_name(channel_id), len(report.channel_users[channel_id]))
+                for channel_id, _ in busiest
+            ),
+            key=lambda row: row[1],
+            reverse=True,
+        )
+        interest = self._event_interest(start, end)
+
+        builder = (
+            EmbedBuilder()
+            .set_title(f"Activity Report ({period})")
+            .set_description(
+                f"{start:%b %d} – {end:%b %d}: {sum(report.messages.values())} messages "
+                f"from {report.active_users} members"
+            )
+            .set_color(discord.Color.green())
+        )
+        self._add_chart(builder, "Messages per channel", messages)
+        self._add_chart(builder, "Active members per channel", members)
+        self._add_chart(builder, "Top emoji in messages", report.emoji.most_common(TOP_ROWS))
+        self._add_chart(builder, "Top reactions", report.reactions.most_common(TOP_ROWS))
+        self._add_chart(builder, "Interest in events", interest[:TOP_ROWS])
+
+        await ctx.send(embed=builder.build())
+
+    @activity.command(name="inactive")
+    @commands.has_permissions(manage_guild=True)
+    async def inactive(self, ctx: commands.Context, days: int | None = None) -> None:
+        """Post the inactivity report in the staff channel now (requires Manage Server).
+
+        Usage: !activity inactive [days]
+        Example: !activity inactive 60
+        """
+        days = days or settings.inactivity_days
+        if days <= 0:
+            await ctx.send("Give the number of days, or set `INACTIVITY_DAYS`.")
+            return
+
+        since = self.bot.activity.last_active_since() or self._today()
+        if since > self._today() - timedelta(days=days):
+            tracked = (self._today() - since).days
+            await ctx.send(f"❌ Activity has only been tracked for {tracked} days, not {days}.")
+            return
+
+        if await self.post_inactivity_report(days):
+            await ctx.send("✅ Posted the inactivity report in the staff channel.")
+        else:
+            await ctx.send("❌ The staff channel wasn't found.")
+
+    @activity.command(name="optout")
+    async def optout(self, ctx: commands.Context, enabled: bool) -> None:
+        """Leave yourself out of inactivity reports, e.g. if you mostly read, or back in.
+
+        Usage: !activity optout <on|off>
+        """
+        if enabled:
+            self.bot.store.set(INACTIVITY_OPT_OUT, str(ctx.author.id), True)
+            await ctx.send("You won't be listed in inactivity reports.", ephemeral=True)
+        else:
+            self.bot.store.delete(INACTIVITY_OPT_OUT, str(ctx.author.id))
+            await ctx.send("You can be listed in inactivity reports again.", ephemeral=True)
+
+    def _event_interest(self, start: date, end: date) -> list[tuple[str, int]]:
+        """Total interested members per event series for occurrences in [start, end]."""
+        tz = ZoneInfo(settings.default_timezone)
+        totals = {
+            series: sum(
+                count
+                for start_time, count in occurrences
+                if start <= start_time.astimezone(tz).date() <= end
+            )
+            for series, occurrences in self.bot.interest.series_counts().items()
+        }
+        return sorted(
+            ((series, total) for series, total in totals.items() if total),
+            key=lambda row: row[1],
+            reverse=True,
+        )
+
+    def _add_chart(self, builder: EmbedBuilder, name: str, rows: list[tuple[str, int]]) -> None:
+        """Add a bar chart field if there's anything to show and it fits."""
+        value = "\n".join(bar_chart(rows))
+        if rows and builder.can_add_field(name, value):
+            builder.add_field(name=name, value=value)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the activity cog."""
+    await bot.add_cog(ActivityCog(bot))
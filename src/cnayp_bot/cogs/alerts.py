@@ -0,0 +1,151 @@
+"""Prometheus alerts from Alertmanager, posted for the ops team."""
+
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import aiohttp
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.alerts import render_alerts, silence_matchers
+from ..services.alertmanager import AlertmanagerClient, group_id
+from ..services.governor import Priority
+
+logger = logging.getLogger(__name__)
+
+# Component handler for the silence buttons, and the silences they offer
+SILENCE = "alert_silence"
+SILENCE_HOURS = (1, 24)
+
+
+class AlertsCog(commands.Cog):
+    """Posts Alertmanager notifications as color-coded embeds in the alerts channel.
+
+    Each alert group gets one message, edited as alerts fire and resolve. While
+    a group fires, its message has buttons that silence the group's labels in
+    Alertmanager for an hour or a day.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.alertmanager = (
+            AlertmanagerClient(settings.alertmanager_url) if settings.alertmanager_url else None
+        )
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(SILENCE, self.silence)
+
+    @commands.Cog.listener()
+    async def on_alertmanager_alerts(self, payload: dict) -> None:
+        """Post or update the message of a notified alert group."""
+        self.bot.governor.tag("alerts", Priority.CRITICAL)
+        try:
+            await self.post_alerts(payload)
+        except Exception as e:
+            logger.exception("Error posting alerts: %s", e)
+
+    async def post_alerts(self, payload: dict) -> None:
+        """Show a notification in its group's message, posting one if needed."""
+        name = (
+            settings.alerts_channel
+            or settings.discord_ops_channel
+            or settings.discord_errors_channel
+        )
+        if not name:
+            logger.warning("No alerts channel configured, dropping alerts")
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Alerts channel not found: %s", name)
+            return
+
+        group = group_id(payload.get("groupKey", ""))
+        resolved = payload.get("status") == "resolved"
+        embed = render_alerts(payload)
+        view = None if resolved else self._silence_view(group)
+
+        entry = self.bot.alert_groups.get(group)
+        message = await self._fetch_message(entry) if entry else None
+        if message:
+            await self.bot.messenger.edit(message, embed=embed, view=view)
+        else:
+            message = await self.bot.messenger.send(channel, embed=embed, view=view)
+
+        if resolved:
+            self.bot.alert_groups.remove(group)
+        elif message:
+            self.bot.alert_groups.set(group, channel.id, message.id, silence_matchers(payload))
+
+    def _silence_view(self, group: str) -> discord.ui.View | None:
+        """Build the silence buttons, if Alertmanager's API is configured."""
+        if not self.alertmanager:
+            return None
+
+        view = discord.ui.View(timeout=None)
+        for hours in SILENCE_HOURS:
+            view.add_item(
+                self.bot.components.button(
+                    SILENCE, f"{group}:{hours}", label=f"Silence {hours}h", emoji="🔕"
+                )
+            )
+        return view
+
+    async def _fetch_message(self, entry: dict) -> discord.Message | None:
+        """Fetch a group's message, or None if it was deleted."""
+        channel = self.bot.get_channel(entry["channel_id"])
+        if not channel:
+            return None
+        try:
+            return await channel.fetch_message(entry["message_id"])
+        except discord.HTTPException:
+            return None
+
+    async def silence(self, interaction: discord.Interaction, payload: str) -> None:
+        """Silence the alert group encoded in a silence button."""
+        if not interaction.permissions.manage_guild:
+            await interaction.response.send_message(
+                "Only members who can manage the server can silence alerts.", ephemeral=True
+            )
+            return
+
+        group, _, hours = payload.partition(":")
+        entry = self.bot.alert_groups.get(group)
+        if entry is None or not self.alertmanager:
+            await interaction.response.send_message(
+                "These alerts already resolved.", ephemeral=True
+            )
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        until = now + timedelta(hours=int(hours))
+        await interaction.response.defer()
+        try:
+            silence_id = await self.alertmanager.silence(
+                entry["matchers"],
+                now,
+                until,
+                author=str(interaction.user),
+                comment=f"Silenced from Discord by {interaction.user}",
+            )
+        except aiohttp.ClientError as e:
+            logger.error("Failed to create a silence for %s: %s", entry["matchers"], e)
+            await interaction.followup.send(f"❌ Couldn't create the silence: {e}", ephemeral=True)
+            return
+
+        labels = ", ".join(f"`{key}={value}`" for key, value in entry["matchers"].items())
+        logger.info("%s silenced %s until %s", interaction.user, entry["matchers"], until)
+        await interaction.followup.send(
+            f"🔕 {interaction.user.mention} silenced {labels} until "
+            f"<t:{int(until.timestamp())}:t> (silence `{silence_id}`).",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the alerts cog."""
+    await bot.add_cog(AlertsCog(bot))
@@ -0,0 +1,141 @@
+"""Event attendance from voice channels, pushed to a Google Sheet for reports."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.embeds import FIELD_VALUE_LIMIT, EmbedBuilder
+from ..services.history import RETENTION, attendance_rows, minutes_attended
+from ..services.sheets import SheetsService
+
+logger = logging.getLogger(__name__)
+
+
+class AttendanceCog(commands.Cog):
+    """Counts members who join an event's voice channel while it runs.
+
+    The scheduler adds each occurrence to `bot.history` when it starts; this
+    adds everyone who joins afterwards, and when they leave. With a sheet
+    configured, the whole history is written to its tab on an interval.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.sheets = SheetsService()
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.attendance_sheet_id:
+            logger.info("Attendance sheet not configured, sheet push disabled")
+            return
+
+        self.sheet_loop.change_interval(hours=settings.attendance_sheet_hours)
+        self.sheet_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.sheet_loop.cancel()
+
+    @commands.Cog.listener()
+    async def on_voice_state_update(
+        self,
+        member: discord.Member,
+        before: discord.VoiceState,
+        after: discord.VoiceState,
+    ) -> None:
+        """Record a member joining or leaving the voice channel of a running event."""
+        if member.bot or not self.bot.leader.is_leader:
+            return
+        if before.channel == after.channel:
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        if before.channel is not None:
+            self.bot.history.leave(before.channel.id, member.id, now)
+        if after.channel is not None:
+            self.bot.history.attend(after.channel.id, member.id, now)
+
+    @commands.hybrid_command(name="attendance")
+    @commands.guild_only()
+    @commands.has_permissions(manage_events=True)
+    async def attendance(self, ctx: commands.Context, *, event: str) -> None:
+        """Show who attended an event and for how long (requires Manage Events).
+
+        Usage: !attendance <event name or ID>
+        Example: !attendance KCNA Study
+        """
+        found = self.bot.history.find(event)
+        if found is None:
+            await ctx.send(
+                f"No recorded event matches `{event}`.",
+                allowed_mentions=discord.AllowedMentions.none(),
+            )
+            return
+
+        _, entry = found
+        start = int(datetime.fromisoformat(entry["start"]).timestamp())
+        minutes = minutes_attended(entry)
+        builder = (
+            EmbedBuilder()
+            .set_title(f"👥 {entry['name']}")
+            .set_description(f"<t:{start}:F>")
+            .set_color(discord.Color.blue())
+            .add_field(name="Attendees", value=str(len(entry["attendees"])), inline=True)
+            .add_field(name="Interested", value=str(entry["interested"]), inline=True)
+        )
+        if entry["going"] is not None:
+            builder.add_field(name="RSVPs", value=str(entry["going"]), inline=True)
+
+        # Longest stays first; members from before visits were tracked have no times
+        lines = []
+        for member_id in sorted(entry["attendees"], key=lambda m: -minutes.get(m, 0)):
+            stay = f" - {minutes[member_id]} min" if member_id in minutes else ""
+            lines.append(f"<@{member_id}>{stay}")
+        value = ""
+        for shown, line in enumerate(lines):
+            if len(value) + len(line) + 1 > FIELD_VALUE_LIMIT - 20:
+                value += f"…and {len(lines) - shown} more"
+                break
+            value += line + "\n"
+        if value:
+            builder.add_field(name="Time in the call", value=value)
+
+        await ctx.send(embed=builder.build(), allowed_mentions=discord.AllowedMentions.none())
+
+    @tasks.loop(hours=24)
+    async def sheet_loop(self) -> None:
+        """Write the event history to the attendance sheet."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        try:
+            self.push_sheet()
+        except Exception as e:
+            logger.exception("Error pushing the attendance sheet: %s", e)
+
+    @sheet_loop.before_loop
+    async def before_sheet_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+        logger.info("Attendance sheet push started every %d hours", settings.attendance_sheet_hours)
+
+    def push_sheet(self) -> None:
+        """Replace the attendance tab with every occurrence in the history."""
+        since = datetime.now(ZoneInfo("UTC")) - RETENTION
+        rows = attendance_rows(self.bot.history.occurrences(since))
+        if settings.observer_mode:
+            self.bot.observer.record("push attendance sheet", rows=len(rows) - 1)
+            return
+
+        self.sheets.replace_values(
+            settings.attendance_sheet_id, settings.attendance_sheet_tab, rows
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the attendance cog."""
+    await bot.add_cog(AttendanceCog(bot))
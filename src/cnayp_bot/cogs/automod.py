@@ -0,0 +1,136 @@
+"""Automatic moderation of messages with dangerous links."""
+
+import logging
+from datetime import datetime
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.embeds import EmbedBuilder
+from ..helpers.permissions import MissingPermissionsError, check_channel_permissions
+from ..services.linkscan import (
+    BlocklistProvider,
+    LinkProvider,
+    LinkScanner,
+    SafeBrowsingProvider,
+    Verdict,
+    extract_urls,
+)
+
+logger = logging.getLogger(__name__)
+
+# Longest excerpt of a quarantined message shown to mods
+EXCERPT_LENGTH = 500
+
+
+class AutomodCog(commands.Cog):
+    """Quarantines messages linking to dangerous URLs.
+
+    Links are checked with the local blocklist and Google Safe Browsing, when
+    configured. A flagged message is deleted and mods get the scan verdict.
+    Members who can manage messages are trusted and never scanned. Only the
+    leader scans, so replicas don't report the same message twice.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.scanner: LinkScanner | None = None
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        providers: list[LinkProvider] = []
+        if settings.link_blocklist_file:
+            providers.append(BlocklistProvider.from_file(Path(settings.link_blocklist_file)))
+        if settings.safe_browsing_api_key:
+            providers.append(SafeBrowsingProvider(settings.safe_browsing_api_key))
+
+        if not providers:
+            logger.info("No link blocklist or Safe Browsing key, link scanning disabled")
+            return
+
+        self.scanner = LinkScanner(providers)
+        logger.info("Scanning links with %s", ", ".join(provider.name for provider in providers))
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Scan the links in members' messages."""
+        if not self.scanner or message.author.bot or not message.guild:
+            return
+        if message.guild.id != settings.discord_guild_id or not self.bot.leader.is_leader:
+            return
+        if message.channel.permissions_for(message.author).manage_messages:
+            return
+
+        urls = extract_urls(message.content)
+        if not urls:
+            return
+
+        verdicts = await self.scanner.scan(urls, datetime.now(ZoneInfo("UTC")))
+        if verdicts:
+            self.bot.governor.tag("automod")
+            await self._quarantine(message, verdicts)
+
+    async def _quarantine(self, message: discord.Message, verdicts: list[Verdict]) -> None:
+        """Delete a message with dangerous links and alert mods."""
+        logger.warning(
+            "Dangerous link from %s in #%s: %s",
+            message.author,
+            message.channel,
+            ", ".join(f"{verdict.url} ({verdict.reason})" for verdict in verdicts),
+        )
+
+        # Quarantines are reported below, not as deleted messages
+        self.bot.message_cache.pop(message.id)
+        deleted = False
+        if settings.observer_mode:
+            self.bot.observer.record("delete message", message=message.id, reason="dangerous link")
+        else:
+            try:
+                check_channel_permissions(message.channel, "manage_messages")
+                await message.delete()
+                deleted = True
+            except MissingPermissionsError as e:
+                logger.error("Can't delete dangerous link: %s", e)
+            except discord.NotFound:
+                deleted = True
+            except discord.HTTPException as e:
+                logger.error("Failed to delete dangerous link: %s", e)
+
+        excerpt = message.content
+        if len(excerpt) > EXCERPT_LENGTH:
+            excerpt = excerpt[: EXCERPT_LENGTH - 1] + "…"
+        author = message.author
+        lines = [f"`{verdict.url}`: {verdict.reason} ({verdict.provider})" for verdict in verdicts]
+        embed = (
+            EmbedBuilder()
+            .set_title(f"🚫 Dangerous link {'removed' if deleted else 'not removed'}")
+            .set_description(excerpt)
+            .set_color(discord.Color.red())
+            .add_field(name="Author", value=f"{author.mention} ({author.id})", inline=True)
+            .add_field(name="Channel", value=message.channel.mention, inline=True)
+            .add_field(name="Verdict", value="\n".join(lines))
+            .build()
+        )
+
+        if not settings.mod_channel:
+            await self.bot.messenger.alert_ops(
+                f"🚫 Dangerous link from {message.author} in {message.channel.mention}:\n"
+                + "\n".join(lines)
+            )
+            return
+
+        channel = discord.utils.get(message.guild.text_channels, name=settings.mod_channel)
+        if not channel:
+            logger.error("Mod channel not found: %s", settings.mod_channel)
+            return
+        await self.bot.messenger.send(
+            channel, embed=embed, allowed_mentions=discord.AllowedMentions.none()
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the automod cog."""
+    await bot.add_cog(AutomodCog(bot))
@@ -0,0 +1,163 @@
+"""Native Discord AutoMod rules managed from a file in version control."""
+
+import logging
+from pathlib import Path
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.permissions import MissingPermissionsError, check_can_manage_automod
+from ..services.automod_rules import (
+    RuleState,
+    current_state,
+    desired_state,
+    load_automod_config,
+    plan_changes,
+    rule_actions,
+    rule_trigger,
+)
+
+logger = logging.getLogger(__name__)
+
+REASON = "AutoMod rules file"
+
+
+class AutomodRulesCog(commands.Cog):
+    """Keeps the guild's AutoMod keyword and mention spam rules in line with the rules file.
+
+    Rules are matched by name, created or edited on startup and every hour
+    after, so changes made in Discord's settings are undone. Rules that aren't
+    in the file are left alone. Only the leader reconciles.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.last_error: str | None = None
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.automod_rules_file:
+            logger.info("No AutoMod rules file, not managing AutoMod")
+            return
+
+        self.reconcile_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.reconcile_loop.cancel()
+
+    @tasks.loop(hours=1)
+    async def reconcile_loop(self) -> None:
+        """Create and edit the guild's rules to match the file."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("automod")
+        try:
+            await self.reconcile()
+        except Exception as e:
+            logger.exception("Error reconciling AutoMod rules: %s", e)
+
+    @reconcile_loop.before_loop
+    async def before_reconcile_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def reconcile(self) -> None:
+        """Create, edit, and replace rules so the guild's match the file."""
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if not guild:
+            return
+
+        try:
+            config = load_automod_config(Path(settings.automod_rules_file))
+            desired = {rule.name: desired_state(rule, guild) for rule in config.rules}
+        except (OSError, ValueError) as e:
+            logger.error("Invalid AutoMod rules file %s: %s", settings.automod_rules_file, e)
+            # Only once per error, not every hour until it's fixed
+            if str(e) != self.last_error:
+                await self.bot.messenger.alert_ops(f"⚠️ AutoMod rules weren't applied: {e}")
+            self.last_error = str(e)
+            return
+        self.last_error = None
+        if not desired:
+            return
+
+        try:
+            timeouts = any(state.timeout_minutes for state in desired.values())
+            check_can_manage_automod(guild, timeouts=timeouts)
+        except MissingPermissionsError as e:
+            logger.error("Can't manage AutoMod rules: %s", e)
+            return
+
+        rules = {rule.name: rule for rule in await guild.fetch_automod_rules()}
+        plan = plan_changes(desired, {name: current_state(rule) for name, rule in rules.items()})
+
+        for name in plan.replace:
+            if settings.observer_mode:
+                self.bot.observer.record("delete automod rule", name=name)
+                continue
+            # Discord can't change a rule's trigger, so it's deleted and created again
+            try:
+                await rules[name].delete(reason=REASON)
+            except discord.HTTPException as e:
+                logger.error("Failed to replace AutoMod rule %s: %s", name, e)
+                continue
+            plan.create.append(name)
+
+        for name in plan.create:
+            await self._create(guild, name, desired[name])
+        for name in plan.edit:
+            await self._edit(rules[name], desired[name])
+
+    async def _create(self, guild: discord.Guild, name: str, state: RuleState) -> None:
+        """Create a rule in the guild."""
+        if settings.observer_mode:
+            self.bot.observer.record("create automod rule", name=name)
+            return
+
+        try:
+            await guild.create_automod_rule(
+                name=name,
+                event_type=discord.AutoModRuleEventType.message_send,
+                trigger=rule_trigger(state),
+                actions=rule_actions(state),
+                enabled=state.enabled,
+                exempt_roles=[discord.Object(role_id) for role_id in state.exempt_role_ids],
+                exempt_channels=[
+                    discord.Object(channel_id) for channel_id in state.exempt_channel_ids
+                ],
+                reason=REASON,
+            )
+        except discord.HTTPException as e:
+            logger.error("Failed to create AutoMod rule %s: %s", name, e)
+            return
+        logger.info("Created AutoMod rule %s", name)
+
+    async def _edit(self, rule: discord.AutoModRule, state: RuleState) -> None:
+        """Edit a guild's rule to match the file."""
+        if settings.observer_mode:
+            self.bot.observer.record("edit automod rule", name=rule.name)
+            return
+
+        try:
+            await rule.edit(
+                trigger=rule_trigger(state),
+                actions=rule_actions(state),
+                enabled=state.enabled,
+                exempt_roles=[discord.Object(role_id) for role_id in state.exempt_role_ids],
+                exempt_channels=[
+                    discord.Object(channel_id) for channel_id in state.exempt_channel_ids
+                ],
+                reason=REASON,
+            )
+        except discord.HTTPException as e:
+            logger.error("Failed to edit AutoMod rule %s: %s", rule.name, e)
+            return
+        logger.info("Edited AutoMod rule %s to match the rules file", rule.name)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the AutoMod rules cog."""
+    await bot.add_cog(AutomodRulesCog(bot))
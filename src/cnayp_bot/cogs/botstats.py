@@ -0,0 +1,105 @@
+"""Bot health and Discord rate limit headroom."""
+
+import logging
+import time
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..helpers.charts import bar
+from ..services.governor import GLOBAL_LIMIT, INVALID_LIMIT
+
+logger = logging.getLogger(__name__)
+
+# Written on every /ping to check the store can still be saved
+HEALTH = "health"
+
+
+class BotStatsCog(commands.Cog):
+    """Reports the bot's uptime, latency, and REST usage."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.started = datetime.now(ZoneInfo("UTC"))
+
+    @commands.hybrid_command(name="ping")
+    async def ping(self, ctx: commands.Context) -> None:
+        """Show gateway and REST latency, store health, uptime, and shard.
+
+        Tells slowness of the bot apart from slowness of Discord: a slow REST
+        round trip with a fast heartbeat points at Discord's API, a slow store
+        write at the bot's host.
+
+        Usage: !ping
+        """
+        started = time.perf_counter()
+        message = await ctx.send("🏓 Pong!")
+        rest = time.perf_counter() - started
+
+        started = time.perf_counter()
+        try:
+            self.bot.store.set(HEALTH, "ping", datetime.now(ZoneInfo("UTC")).isoformat())
+            store = f"✅ {(time.perf_counter() - started) * 1000:.0f} ms write"
+        except OSError as e:
+            logger.error("Store write failed during /ping: %s", e)
+            store = f"❌ {e.strerror or e}"
+
+        shard = ctx.guild.shard_id if ctx.guild else 0
+        embed = discord.Embed(title="🏓 Pong!", color=discord.Color.dark_grey())
+        embed.add_field(name="Gateway heartbeat", value=f"{self.bot.latency * 1000:.0f} ms")
+        embed.add_field(name="REST round trip", value=f"{rest * 1000:.0f} ms")
+        embed.add_field(name="Store", value=store)
+        embed.add_field(name="Up since", value=f"<t:{int(self.started.timestamp())}:R>")
+        embed.add_field(name="Shard", value=f"{shard + 1} of {self.bot.shard_count or 1}")
+        embed.add_field(name="Leader", value="Yes" if self.bot.leader.is_leader else "No")
+        await message.edit(content=None, embed=embed)
+
+    @commands.hybrid_command(name="botstats")
+    @commands.guild_only()
+    async def botstats(self, ctx: commands.Context) -> None:
+        """Show uptime, gateway latency, and rate limit headroom.
+
+        Usage: !botstats
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        governor = self.bot.governor
+        headroom = governor.headroom(now)
+
+        embed = discord.Embed(title="Bot Stats", color=discord.Color.dark_grey())
+        embed.add_field(name="Up since", value=f"<t:{int(self.started.timestamp())}:R>")
+        embed.add_field(name="Latency", value=f"{self.bot.latency * 1000:.0f} ms")
+        embed.add_field(
+            name="Global rate limit",
+            value=(
+                f"`{bar(headroom.requests_per_second, GLOBAL_LIMIT):<12}` "
+                f"{headroom.requests_per_second}/{GLOBAL_LIMIT} req/s"
+            ),
+            inline=False,
+        )
+        embed.add_field(
+            name="Invalid requests (10 min)",
+            value=(
+                f"`{bar(headroom.invalid_requests, INVALID_LIMIT):<12}` "
+                f"{headroom.invalid_requests}/{INVALID_LIMIT} "
+                f"({governor.invalid_total} since startup)"
+            ),
+            inline=False,
+        )
+
+        subsystems = sorted(governor.requests, key=governor.requests.__getitem__, reverse=True)
+        lines = [
+            f"**{name}**: {governor.requests[name]} requests, {governor.throttled[name]} throttled"
+            for name in subsystems
+        ]
+        embed.add_field(
+            name="Requests since startup", value="\n".join(lines) or "None yet.", inline=False
+        )
+
+        await ctx.send(embed=embed)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the bot stats cog."""
+    await bot.add_cog(BotStatsCog(bot))
@@ -0,0 +1,82 @@
+"""Commands showing and ending the canary periods of soft-launched features."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from discord.ext import commands
+
+logger = logging.getLogger(__name__)
+
+
+class CanaryCog(commands.Cog):
+    """Shows which features post in the canary channel, and until when.
+
+    Features listed in `canary_features` post in `canary_channel` for
+    `canary_days` after their first post, then move to their real channels on
+    their own. `!canary promote` moves one early, once its output looks right.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.hybrid_group(name="canary")
+    @commands.guild_only()
+    async def canary(self, ctx: commands.Context) -> None:
+        """Show or end the canary periods of soft-launched features.
+
+        Usage: !canary status | promote <feature>
+        """
+        await ctx.send_help(ctx.command)
+
+    @canary.command(name="status")
+    @commands.has_permissions(manage_guild=True)
+    async def canary_status(self, ctx: commands.Context) -> None:
+        """List the features in canary mode and when each moves on (requires Manage Server).
+
+        Usage: !canary status
+        """
+        canary = self.bot.canary
+        if not canary.channel or not canary.features:
+            await ctx.send("No features are in canary mode.")
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        lines = [f"🐤 Features soft-launched in #{canary.channel}:"]
+        for feature in canary.features:
+            until = canary.until(feature)
+            if until is None:
+                lines.append(f"- **{feature}**: waiting for its first post")
+                continue
+            state = "in its real channels since" if until <= now else "moves to its real channels"
+            lines.append(f"- **{feature}**: {state} <t:{int(until.timestamp())}:R>")
+        await ctx.send("\n".join(lines))
+
+    @canary.command(name="promote")
+    @commands.has_permissions(manage_guild=True)
+    async def canary_promote(self, ctx: commands.Context, feature: str) -> None:
+        """End a feature's canary period now (requires Manage Server).
+
+        Usage: !canary promote <feature>
+        Example: !canary promote digest
+        """
+        canary = self.bot.canary
+        feature = feature.lower()
+        if feature not in canary.features:
+            await ctx.send(f"❌ **{feature}** isn't in canary mode.")
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        until = canary.until(feature)
+        if until is not None and until <= now:
+            await ctx.send(f"**{feature}** already posts in its real channels.")
+            return
+
+        canary.promote(feature, now)
+        logger.info("%s promoted %s out of canary mode", ctx.author, feature)
+        await ctx.send(f"✅ **{feature}** now posts in its real channels.")
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the canary cog."""
+    await bot.add_cog(CanaryCog(bot))
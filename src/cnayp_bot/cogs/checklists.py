@@ -0,0 +1,266 @@
+"""Pre-event checklists posted to the owners of each schedule."""
+
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.embeds import EmbedBuilder
+from ..helpers.permissions import MissingPermissionsError, check_channel_permissions
+from ..models import Schedule
+from ..services.calendar import CalendarEvent
+from ..services.governor import Priority
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+# Component handler for the buttons that tick checklist items
+CHECKLIST = "checklist"
+
+# Longest name Discord allows for a thread, and for a button label
+THREAD_NAME_LIMIT = 100
+LABEL_LIMIT = 80
+
+
+class ChecklistsCog(commands.Cog):
+    """Posts each schedule's checklist ahead of its occurrences.
+
+    `checklist_days` before an occurrence, its schedule's checklist is posted
+    in a thread for the schedule's organizers, opened in the checklist
+    channel, with a button per item. Owners, co-hosts, and anyone who can
+    manage events tick the items off. `checklist_nag_hours` before the
+    event, the owners are pinged with the items still open.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(CHECKLIST, self.toggle_item)
+        self.checklist_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.checklist_loop.cancel()
+
+    @tasks.loop(minutes=15)
+    @drained("checklists")
+    async def checklist_loop(self) -> None:
+        """Post the checklists and the reminders that are due."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("checklists", Priority.BACKGROUND)
+        now = datetime.now(ZoneInfo("UTC"))
+        checklists = self.bot.checklists
+        try:
+            lead = max(
+                (s.checklist_days for s in self.bot.schedules.config.schedules if s.checklist),
+                default=0,
+            )
+            for event in self.bot.schedules.get_events_between(now, now + timedelta(days=lead)):
+                schedule = event.schedule
+                if not schedule.checklist or checklists.find(event.id):
+                    continue
+                if event.start_time - timedelta(days=schedule.checklist_days) <= now:
+                    await self.post_checklist(event, schedule)
+
+            nag = timedelta(hours=settings.checklist_nag_hours)
+            for checklist_id in checklists.due_nags(now, nag):
+                await self.nag(checklist_id)
+            checklists.forget_finished(now)
+        except Exception as e:
+            logger.exception("Error in checklist loop: %s", e)
+
+    @checklist_loop.before_loop
+    async def before_checklist_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def post_checklist(self, event: CalendarEvent, schedule: Schedule) -> None:
+        """Post an occurrence's checklist in its schedule's thread."""
+        if settings.observer_mode:
+            self.bot.observer.record("post checklist", event=event.id, schedule=schedule.name)
+            return
+
+        thread = await self._thread(schedule)
+        if not thread:
+            return
+
+        checklist_id = self.bot.checklists.open(
+            event.id, event.name, event.start_time, schedule.checklist
+        )
+        checklist = self.bot.checklists.get(checklist_id)
+        message = await self.bot.messenger.send(
+            thread,
+            _ping(
+                schedule,
+                f"Checklist for **{event.name}** (<t:{int(event.start_time.timestamp())}:F>):",
+            ),
+            embed=_build_embed(checklist),
+            view=self._build_view(checklist_id, checklist),
+            allowed_mentions=discord.AllowedMentions(users=True, everyone=False, roles=False),
+        )
+        if not message:
+            self.bot.checklists.discard(checklist_id)
+            return
+        self.bot.checklists.set_message(checklist_id, thread.id, message.id)
+        logger.info("Posted the checklist for %s", event.id)
+
+    async def _thread(self, schedule: Schedule) -> discord.Thread | None:
+        """Return the thread for a schedule's organizers, opening it the first time."""
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if not guild:
+            return None
+
+        thread_id = self.bot.checklists.thread(schedule.name)
+        if thread_id:
+            try:
+                return await self._fetch_thread(thread_id)
+            except discord.NotFound:
+                logger.warning("Checklist thread of %s is gone, opening a new one", schedule.name)
+            except discord.HTTPException as e:
+                logger.error("Failed to fetch the checklist thread of %s: %s", schedule.name, e)
+                return None
+
+        name = (
+            settings.checklist_channel
+            or settings.staff_channel
+            or settings.discord_ops_channel
+            or settings.discord_errors_channel
+        )
+        channel = name and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Checklist channel not found: %s", name)
+            return None
+
+        try:
+            check_channel_permissions(channel, "create_public_threads", "send_messages_in_threads")
+            thread = await channel.create_thread(
+                name=f"{schedule.name} organizers"[:THREAD_NAME_LIMIT],
+                type=discord.ChannelType.public_thread,
+                reason=f"Checklists for {schedule.name}",
+            )
+        except MissingPermissionsError as e:
+            logger.error("Can't open the checklist thread of %s: %s", schedule.name, e)
+            return None
+        except discord.HTTPException as e:
+            logger.error("Failed to open the checklist thread of %s: %s", schedule.name, e)
+            return None
+
+        self.bot.checklists.set_thread(schedule.name, thread.id)
+        return thread
+
+    async def _fetch_thread(self, thread_id: int) -> discord.Thread:
+        """Return a thread, fetching it if it's archived and so not cached.
+
+        Raises:
+            discord.HTTPException: If the thread can't be fetched.
+        """
+        thread = self.bot.get_channel(thread_id)
+        return thread or await self.bot.fetch_channel(thread_id)
+
+    async def nag(self, checklist_id: str) -> None:
+        """Remind the owners of the items still open on a checklist."""
+        checklist = self.bot.checklists.get(checklist_id)
+        self.bot.checklists.mark_nagged(checklist_id)
+        try:
+            thread = await self._fetch_thread(checklist["thread_id"])
+        except discord.HTTPException as e:
+            logger.error("Failed to fetch the checklist thread for %s: %s", checklist["name"], e)
+            return
+
+        start = datetime.fromisoformat(checklist["start"])
+        left = "\n".join(f"- {item['text']}" for item in checklist["items"] if not item["done_by"])
+        await self.bot.messenger.send(
+            thread,
+            _ping(
+                self._schedule(checklist["name"]),
+                f"⏰ **{checklist['name']}** starts <t:{int(start.timestamp())}:R> and these "
+                f"are still open:\n{left}",
+            ),
+            allowed_mentions=discord.AllowedMentions(users=True, everyone=False, roles=False),
+            reference=thread.get_partial_message(checklist["message_id"]),
+        )
+
+    async def toggle_item(self, interaction: discord.Interaction, payload: str) -> None:
+        """Tick a checklist item off, or back on."""
+        checklist_id, _, index = payload.partition(":")
+        checklist = self.bot.checklists.get(checklist_id)
+        if not checklist:
+            await interaction.response.send_message(
+                "This checklist is finished.", ephemeral=True
+            )
+            return
+
+        schedule = self._schedule(checklist["name"])
+        organizers = [*schedule.owners, *schedule.co_hosts] if schedule else []
+        if interaction.user.id not in organizers and not interaction.permissions.manage_events:
+            await interaction.response.send_message(
+                "Only the schedule's owners and co-hosts can tick items off.", ephemeral=True
+            )
+            return
+
+        self.bot.checklists.toggle(checklist_id, int(index), interaction.user.id)
+        checklist = self.bot.checklists.get(checklist_id)
+        await interaction.response.edit_message(
+            embed=_build_embed(checklist), view=self._build_view(checklist_id, checklist)
+        )
+
+    def _schedule(self, name: str) -> Schedule | None:
+        """Return the schedule of a checklist, by name."""
+        for schedule in self.bot.schedules.config.schedules:
+            if schedule.name == name:
+                return schedule
+        return None
+
+    def _build_view(self, checklist_id: str, checklist: dict) -> discord.ui.View:
+        """Build a button per checklist item, green once it's done."""
+        view = discord.ui.View(timeout=None)
+        for index, item in enumerate(checklist["items"]):
+            view.add_item(
+                self.bot.components.button(
+                    CHECKLIST,
+                    f"{checklist_id}:{index}",
+                    label=item["text"][:LABEL_LIMIT],
+                    emoji="✅" if item["done_by"] else "⬜",
+                    style=(
+                        discord.ButtonStyle.success
+                        if item["done_by"]
+                        else discord.ButtonStyle.secondary
+                    ),
+                )
+            )
+        return view
+
+
+def _ping(schedule: Schedule | None, content: str) -> str:
+    """Prefix a message with mentions of the schedule's owners."""
+    owners = " ".join(f"<@{owner}>" for owner in schedule.owners) if schedule else ""
+    return f"{owners} {content}" if owners else content
+
+
+def _build_embed(checklist: dict) -> discord.Embed:
+    """List the checklist items and who ticked each off."""
+    done = sum(1 for item in checklist["items"] if item["done_by"])
+    lines = [
+        f"✅ {item['text']} (<@{item['done_by']}>)" if item["done_by"] else f"⬜ {item['text']}"
+        for item in checklist["items"]
+    ]
+    return (
+        EmbedBuilder()
+        .set_title(f"📋 {checklist['name']}")
+        .set_description("\n".join(lines))
+        .set_color(discord.Color.green() if done == len(lines) else discord.Color.orange())
+        .set_footer(text=f"{done}/{len(lines)} done")
+        .build()
+    )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the checklists cog."""
+    await bot.add_cog(ChecklistsCog(bot))
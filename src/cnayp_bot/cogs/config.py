@@ -0,0 +1,226 @@
+"""Commands changing the bot's settings at runtime: template values and rollbacks."""
+
+import json
+import logging
+import re
+from datetime import datetime
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.chunking import MESSAGE_LIMIT
+from ..helpers.diff import render_diff
+from ..services.template_values import MAX_VALUE_NAME_LENGTH, VALUE_LIMIT
+
+logger = logging.getLogger(__name__)
+
+# Component handler for the button confirming a rollback
+CONFIG_ROLLBACK = "config_rollback"
+
+# Snapshots listed by `!config snapshots`
+LISTED_SNAPSHOTS = 10
+
+_VALUE_NAME = re.compile(r"^[\w-]+$")
+
+
+class ConfigCog(commands.Cog):
+    """Sets the guild's template values, and rolls back changes to the schedules file.
+
+    Announcement, reminder, and follow-up templates include a value with
+    `{$name}`, so changing the link here changes the next message of every
+    schedule using it without editing the schedules file.
+
+    The schedules file is snapshotted before each change the bot makes to it,
+    and `!config rollback` restores a snapshot after showing what it changes.
+    The file is shared by every guild, so rollbacks are only allowed in the
+    primary guild.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(CONFIG_ROLLBACK, self.confirm_rollback)
+
+    @commands.hybrid_group(name="config")
+    @commands.guild_only()
+    @commands.has_permissions(manage_guild=True)
+    async def config(self, ctx: commands.Context) -> None:
+        """Change the bot's settings for this server (requires Manage Server).
+
+        Usage: !config kv list | set | unset, !config snapshots | rollback
+        """
+        await ctx.send_help(ctx.command)
+
+    @config.group(name="kv")
+    async def kv(self, ctx: commands.Context) -> None:
+        """List, set, and remove the values templates include with {$name}.
+
+        Usage: !config kv list | set | unset
+        """
+        await ctx.send_help(ctx.command)
+
+    @kv.command(name="list")
+    async def kv_list(self, ctx: commands.Context) -> None:
+        """List the values set for this server's templates.
+
+        Usage: !config kv list
+        """
+        values = self.bot.template_values.all(ctx.guild.id)
+        if not values:
+            await ctx.send("No template values yet. Set one with `!config kv set`.", ephemeral=True)
+            return
+
+        lines = [f"`{{${name}}}` {text}" for name, text in sorted(values.items())]
+        await ctx.send(
+            "\n".join(lines), allowed_mentions=discord.AllowedMentions.none(), ephemeral=True
+        )
+
+    @kv.command(name="set")
+    async def kv_set(self, ctx: commands.Context, name: str, *, value: str) -> None:
+        """Set a value templates include with {$name}, replacing the one set before.
+
+        Usage: !config kv set <name> <value>
+        Example: !config kv set meeting_link https://zoom.us/j/123456789
+        """
+        name = name.lower()
+        if len(name) > MAX_VALUE_NAME_LENGTH or not _VALUE_NAME.match(name):
+            await ctx.send(
+                f"Value names use letters, digits, dashes, and underscores "
+                f"(up to {MAX_VALUE_NAME_LENGTH} characters).",
+                ephemeral=True,
+            )
+            return
+        if len(value) > VALUE_LIMIT:
+            await ctx.send(f"Values can be at most {VALUE_LIMIT} characters.", ephemeral=True)
+            return
+
+        self.bot.template_values.set(ctx.guild.id, name, value)
+        logger.info("%s set template value %s in %s", ctx.author, name, ctx.guild)
+        await ctx.send(f"Saved `{{${name}}}`, used from the next message on.", ephemeral=True)
+
+    @kv.command(name="unset", aliases=["remove"])
+    async def kv_unset(self, ctx: commands.Context, name: str) -> None:
+        """Remove a value; templates including it leave it out.
+
+        Usage: !config kv unset <name>
+        """
+        if not self.bot.template_values.delete(ctx.guild.id, name.lower()):
+            await ctx.send(f"No value named `{name}`.", ephemeral=True)
+            return
+
+        logger.info("%s removed template value %s in %s", ctx.author, name, ctx.guild)
+        await ctx.send(f"Removed `{{${name.lower()}}}`.", ephemeral=True)
+
+
+    @config.command(name="snapshots")
+    async def config_snapshots(self, ctx: commands.Context) -> None:
+        """List the latest snapshots of the schedules file, to roll back to.
+
+        Usage: !config snapshots
+        """
+        snapshots = self.bot.schedules.snapshots.list()[:LISTED_SNAPSHOTS]
+        if not snapshots:
+            await ctx.send("No snapshots yet, the schedules haven't changed.", ephemeral=True)
+            return
+
+        lines = [
+            f"`#{snapshot_id}` <t:{int(datetime.fromisoformat(snapshot['at']).timestamp())}:R> "
+            f"before {snapshot['reason']}"
+            for snapshot_id, snapshot in snapshots
+        ]
+        await ctx.send(
+            "\n".join(lines)[:MESSAGE_LIMIT],
+            allowed_mentions=discord.AllowedMentions.none(),
+            ephemeral=True,
+        )
+
+    @config.command(name="rollback")
+    async def config_rollback(self, ctx: commands.Context, snapshot: int) -> None:
+        """Show what restoring a snapshot of the schedules file changes, to confirm it.
+
+        Usage: !config rollback <snapshot>
+        Example: !config rollback 12
+        """
+        if ctx.guild.id != settings.discord_guild_id:
+            await ctx.send(
+                "The schedules file is shared by every server, roll it back from the main one.",
+                ephemeral=True,
+            )
+            return
+
+        try:
+            config = self.bot.schedules.snapshots.config(snapshot)
+        except ValueError as e:
+            await ctx.send(
+                f"❌ Snapshot #{snapshot} can't be restored: {e}"[:MESSAGE_LIMIT], ephemeral=True
+            )
+            return
+        if config is None:
+            await ctx.send(
+                f"❌ There's no snapshot #{snapshot}. See `!config snapshots`.", ephemeral=True
+            )
+            return
+
+        before = _dump(self.bot.schedules.config.model_dump(mode="json"))
+        after = _dump(config.model_dump(mode="json"))
+        if before == after:
+            await ctx.send(f"The schedules already match snapshot #{snapshot}.", ephemeral=True)
+            return
+
+        heading = f"Restoring snapshot #{snapshot} changes the schedules file:\n"
+        view = discord.ui.View(timeout=None)
+        view.add_item(
+            self.bot.components.button(
+                CONFIG_ROLLBACK,
+                str(snapshot),
+                label=f"Restore #{snapshot}",
+                style=discord.ButtonStyle.danger,
+            )
+        )
+        await ctx.send(
+            heading + render_diff(before, after, MESSAGE_LIMIT - len(heading)),
+            allowed_mentions=discord.AllowedMentions.none(),
+            view=view,
+            ephemeral=True,
+        )
+
+    async def confirm_rollback(self, interaction: discord.Interaction, payload: str) -> None:
+        """Restore the snapshot of the rollback button pressed."""
+        if not interaction.permissions.manage_guild:
+            await interaction.response.send_message(
+                "Only admins who can manage the server can roll back the schedules.",
+                ephemeral=True,
+            )
+            return
+
+        try:
+            config = self.bot.schedules.snapshots.config(int(payload))
+        except ValueError as e:
+            config = None
+            logger.error("Snapshot #%s can't be restored: %s", payload, e)
+        if config is None:
+            await interaction.response.send_message(
+                f"Snapshot #{payload} can't be restored anymore.", ephemeral=True
+            )
+            return
+
+        self.bot.schedules.replace(config, f"{interaction.user} rolled back to #{payload}")
+        logger.info("%s rolled the schedules back to snapshot #%s", interaction.user, payload)
+        await interaction.response.edit_message(
+            content=f"✅ Restored snapshot #{payload}. Undo it with the newest "
+            "snapshot in `!config snapshots`.",
+            view=None,
+        )
+
+
+def _dump(config: dict) -> str:
+    """Format a config as the schedules file is written, to diff two versions."""
+    return json.dumps(config, indent=2, ensure_ascii=False)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the config cog."""
+    await bot.add_cog(ConfigCog(bot))
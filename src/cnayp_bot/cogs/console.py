@@ -0,0 +1,218 @@
+"""Debug console maintainers use from a locked-down channel to inspect the live bot."""
+
+import json
+import logging
+import shlex
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.permissions import is_public
+from ..services.schedules import schedule_occurrences
+from .scheduler import DISCORD_EVENTS
+
+logger = logging.getLogger(__name__)
+
+# Longest output shown, leaving room for the code block around it
+OUTPUT_LIMIT = 1900
+
+# Days ahead `trigger schedule` looks for a schedule's next occurrence
+TRIGGER_DAYS = 8
+
+# Audit entries `audit` shows by default
+AUDIT_ENTRIES = 10
+
+HELP = """\
+state namespaces                      store namespaces and their sizes
+state keys <namespace>                keys of a namespace
+state get <namespace> [key]           a value, or the whole namespace
+cache dump channels|roles|events      the guild's cached channels or roles, or known events
+trigger schedule <number|name> [--dry-run]
+                                      create (or preview) a schedule's next occurrence
+audit [count]                         the latest audit entries"""
+
+
+class ConsoleError(Exception):
+    """Raised when a console operation can't run, with the message to show."""
+
+
+def parse_operation(text: str) -> tuple[list[str], bool]:
+    """Split an operation into its words, and whether `--dry-run` was given.
+
+    Raises:
+        ConsoleError: If the quoting doesn't parse.
+    """
+    try:
+        words = shlex.split(text)
+    except ValueError as e:
+        raise ConsoleError(f"Can't parse the operation: {e}") from e
+    return [word for word in words if word != "--dry-run"], "--dry-run" in words
+
+
+class ConsoleCog(commands.Cog):
+    """Runs read-mostly operations typed in `debug_channel`, for live debugging.
+
+    Only messages from the maintainers in `debug_admin_ids` who are also
+    admins count, and only while the channel is hidden from @everyone.
+    There's no eval: each operation is one of `HELP`. Every invocation,
+    including denied ones, goes to the audit trail.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Run an operation typed in the debug channel."""
+        if not settings.debug_channel or not settings.debug_admin_ids:
+            return
+        if message.author.bot or not message.guild:
+            return
+        if getattr(message.channel, "name", None) != settings.debug_channel:
+            return
+        text = message.content.strip()
+        # Commands still work in the channel
+        if not text or text.startswith("!") or not self.bot.leader.is_leader:
+            return
+
+        audit = self.bot.audit
+        author = message.author
+        if author.id not in settings.debug_admin_ids or not author.guild_permissions.administrator:
+            audit.record(author.id, str(author), text, "denied: not a maintainer")
+            return
+        if is_public(message.channel):
+            audit.record(author.id, str(author), text, "denied: the channel is public")
+            await message.channel.send("❌ The debug channel must be hidden from @everyone.")
+            return
+
+        try:
+            words, dry_run = parse_operation(text)
+            output = await self.run(words, dry_run, message.guild)
+            outcome = "ok"
+        except ConsoleError as e:
+            output = str(e)
+            outcome = f"error: {e}"
+        except Exception as e:
+            logger.exception("Console operation %r failed: %s", text, e)
+            output = f"{type(e).__name__}: {e}"
+            outcome = f"failed: {output}"
+        audit.record(author.id, str(author), text, outcome)
+
+        if len(output) > OUTPUT_LIMIT:
+            output = output[: OUTPUT_LIMIT - 20] + "\n… (truncated)"
+        # A zero-width space keeps backticks in the output from closing the block
+        output = output.replace("```", "`\u200b``")
+        await message.channel.send(
+            f"```\n{output}\n```", allowed_mentions=discord.AllowedMentions.none()
+        )
+
+    async def run(self, words: list[str], dry_run: bool, guild: discord.Guild) -> str:
+        """Run an operation and return its output.
+
+        Raises:
+            ConsoleError: If the operation is unknown or its arguments are wrong.
+        """
+        match words:
+            case ["help"]:
+                return HELP
+            case ["state", "namespaces"]:
+                return _json(self.bot.store.namespaces())
+            case ["state", "keys", namespace]:
+                return "\n".join(self.bot.store.items(namespace)) or f"{namespace} is empty"
+            case ["state", "get", namespace]:
+                return _json(self.bot.store.items(namespace))
+            case ["state", "get", namespace, key]:
+                value = self.bot.store.get(namespace, key)
+                if value is None:
+                    raise ConsoleError(f"No {key} in {namespace}")
+                return _json(value)
+            case ["cache", "dump", "channels"]:
+                return "\n".join(
+                    f"{channel.id} {channel.type} #{channel.name}" for channel in guild.channels
+                )
+            case ["cache", "dump", "roles"]:
+                return "\n".join(f"{role.id} @{role.name}" for role in guild.roles)
+            case ["cache", "dump", "events"]:
+                events = sorted(
+                    self._scheduler().known_events.values(), key=lambda event: event.start_time
+                )
+                lines = [
+                    f"{event.id} {event.start_time:%Y-%m-%d %H:%M} UTC {event.name}"
+                    for event in events
+                ]
+                return "\n".join(lines) or "No known events"
+            case ["trigger", "schedule", *name] if name:
+                return await self._trigger(" ".join(name), dry_run)
+            case ["audit"]:
+                return _audit_lines(self.bot.audit.recent(AUDIT_ENTRIES))
+            case ["audit", count] if count.isdigit():
+                return _audit_lines(self.bot.audit.recent(int(count)))
+        raise ConsoleError(f"Unknown operation, try one of:\n{HELP}")
+
+    def _scheduler(self) -> commands.Cog:
+        """Return the scheduler cog.
+
+        Raises:
+            ConsoleError: If it isn't loaded.
+        """
+        scheduler = self.bot.get_cog("SchedulerCog")
+        if not scheduler:
+            raise ConsoleError("The scheduler isn't loaded")
+        return scheduler
+
+    async def _trigger(self, name: str, dry_run: bool) -> str:
+        """Create a schedule's next Discord event now, or show what would be announced.
+
+        Schedules are given by name or by their number in the schedules file,
+        counting from 1.
+        """
+        schedules = self.bot.schedules.config.schedules
+        if name.isdigit() and 1 <= int(name) <= len(schedules):
+            schedule = schedules[int(name) - 1]
+        else:
+            schedule = next((s for s in schedules if s.name.lower() == name.lower()), None)
+        if schedule is None:
+            raise ConsoleError(f"No schedule {name}")
+
+        now = datetime.now(ZoneInfo("UTC"))
+        occurrences = schedule_occurrences(
+            schedule, now, now + timedelta(days=TRIGGER_DAYS), self.bot.schedules.holidays
+        )
+        if not occurrences:
+            raise ConsoleError(f"{schedule.name} doesn't run in the next {TRIGGER_DAYS} days")
+
+        event = occurrences[0]
+        scheduler = self._scheduler()
+        if dry_run:
+            announcements = await scheduler.preview_announcements(event)
+            return f"Would create {event.id} at {event.start_time:%Y-%m-%d %H:%M} UTC:\n\n" + (
+                "\n\n".join(announcements)
+            )
+
+        scheduler.known_events[event.id] = event
+        await scheduler.check_and_create_discord_event(event)
+        tracked = self.bot.store.get(DISCORD_EVENTS, event.id)
+        if tracked is None:
+            return f"{event.id} wasn't created: it's outside the creation window, or see the logs"
+        return f"{event.id}: {_json(tracked)}"
+
+
+def _json(value: object) -> str:
+    """Format a value from the store for reading."""
+    return json.dumps(value, indent=2, sort_keys=True, default=str, ensure_ascii=False)
+
+
+def _audit_lines(entries: list[dict]) -> str:
+    """Format audit entries one per line, newest first."""
+    return "\n".join(
+        f"{entry['time'][:19]} {entry['user']}: {entry['action']} → {entry['outcome']}"
+        for entry in entries
+    ) or "The audit trail is empty"
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the console cog."""
+    await bot.add_cog(ConsoleCog(bot))
@@ -0,0 +1,483 @@
+"""Daily digest of the day's events, kept up to date in place, and a weekly overview."""
+
+import logging
+import math
+from datetime import date, datetime, time, timedelta
+from itertools import groupby
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.embeds import EmbedBuilder
+from ..helpers.sanitize import (
+    EVENT_DESCRIPTION_LIMIT,
+    EVENT_NAME_LIMIT,
+    sanitize_template,
+    sanitize_text,
+)
+from ..helpers.timezones import reference_times
+from ..scheduling import LOOKAHEAD_HOURS, digest_due, in_quiet_hours, weekly_digest_due
+from ..services.calendar import CalendarEvent
+from ..services.governor import Priority
+from ..services.in_flight import drained
+from ..services.schedules import is_private
+from .reminders import REMINDERS, add_reminder
+from .rsvps import join_message
+from .scheduler import _held_in
+
+logger = logging.getLogger(__name__)
+
+# Store namespace holding the digest posted today, and the date of the last weekly overview
+DIGEST = "schedule_digest"
+CURRENT = "current"
+WEEKLY = "weekly"
+
+# Days the weekly overview covers, from the day it's posted
+WEEK_DAYS = 7
+
+# Component handler for the RSVP and reminder menus under the digest
+DIGEST_ACTIONS = "digest"
+
+# How long before an event a reminder set from the digest is sent
+REMINDER_LEAD = timedelta(minutes=10)
+
+# Discord's limits for select menus
+SELECT_OPTIONS_LIMIT = 25
+SELECT_VALUE_LIMIT = 100
+
+
+class DigestCog(commands.Cog):
+    """Posts the day's events every morning and edits the post when they change.
+
+    The posted message IDs are persisted, so a restart or a schedule change
+    updates the existing digest instead of posting a second one. A day with
+    more events than fit in one embed continues in follow-up messages.
+
+    Menus under the digest let members RSVP to the day's events that take
+    RSVPs, or get a DM shortly before one starts.
+
+    Once a week, on `weekly_digest_day`, it also posts an overview of the
+    coming 7 days grouped by day, in each guild the bot serves. Times are
+    Discord timestamps, so each member sees them in their own timezone.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(DIGEST_ACTIONS, self.digest_action)
+        config = self.bot.schedules.config
+        if config.digest_time and config.digest_channel:
+            self.digest_loop.start()
+        else:
+            logger.info("Digest time or channel not configured, daily digest disabled")
+
+        weekly_channel = config.weekly_digest_channel or config.digest_channel
+        if config.weekly_digest_day and config.weekly_digest_time and weekly_channel:
+            self.weekly_loop.start()
+        else:
+            logger.info("Weekly digest day, time, or channel not configured, not posting it")
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.digest_loop.cancel()
+        self.weekly_loop.cancel()
+
+    @tasks.loop(minutes=1)
+    @drained("daily digest")
+    async def digest_loop(self) -> None:
+        """Post today's digest when it's due outside quiet hours, and keep it current afterwards."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("digest", Priority.BACKGROUND)
+        try:
+            now = datetime.now(ZoneInfo(settings.default_timezone))
+            posted = self.bot.store.get(DIGEST, CURRENT)
+            last_posted = date.fromisoformat(posted["date"]) if posted else None
+
+            due = digest_due(now, self.bot.schedules.config.digest_time, last_posted)
+            if due and in_quiet_hours(now, settings.quiet_hours_start, settings.quiet_hours_end):
+                return
+            if due or last_posted == now.date():
+                await self.update_digest(now)
+        except Exception as e:
+            logger.exception("Error in digest loop: %s", e)
+
+    @digest_loop.before_loop
+    async def before_digest_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+        logger.info("Daily digest loop started for #%s", self.bot.schedules.config.digest_channel)
+
+    @tasks.loop(minutes=1)
+    @drained("weekly digest")
+    async def weekly_loop(self) -> None:
+        """Post the week's overview once on the configured day, from its time on."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("weekly_digest", Priority.BACKGROUND)
+        try:
+            config = self.bot.schedules.config
+            now = datetime.now(ZoneInfo(settings.default_timezone))
+            posted = self.bot.store.get(DIGEST, WEEKLY)
+            last_posted = date.fromisoformat(posted) if posted else None
+            day, digest_time = config.weekly_digest_day, config.weekly_digest_time
+            quiet = in_quiet_hours(now, settings.quiet_hours_start, settings.quiet_hours_end)
+            if weekly_digest_due(now, day, digest_time, last_posted) and not quiet:
+                # Only try once a week, even if sending fails
+                self.bot.store.set(DIGEST, WEEKLY, now.date().isoformat())
+                for guild_id in settings.discord_guild_ids:
+                    await self.post_weekly_digest(now, guild_id)
+        except Exception as e:
+            logger.exception("Error in weekly digest loop: %s", e)
+
+    @weekly_loop.before_loop
+    async def before_weekly_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    @commands.group(name="digest", invoke_without_command=True)
+    async def digest(self, ctx: commands.Context) -> None:
+        """Manage the daily events digest and the weekly overview.
+
+        Usage: !digest now | week
+        """
+        await ctx.send_help(ctx.command)
+
+    @digest.command(name="now")
+    @commands.has_permissions(manage_guild=True)
+    async def digest_now(self, ctx: commands.Context) -> None:
+        """Regenerate today's digest now, editing it if it was already posted.
+
+        Usage: !digest now
+        """
+        if not self.bot.schedules.config.digest_channel:
+            await ctx.send("No `digest_channel` is set in the schedules file.")
+            return
+
+        now = datetime.now(ZoneInfo(settings.default_timezone))
+        message = await self.update_digest(now, force=True)
+        await ctx.send(f"Digest updated: {message.jump_url}" if message else "Digest updated.")
+
+    @digest.command(name="week")
+    @commands.has_permissions(manage_guild=True)
+    async def digest_week(self, ctx: commands.Context) -> None:
+        """Post the overview of the coming 7 days in this guild now, besides the scheduled one.
+
+        Usage: !digest week
+        """
+        config = self.bot.schedules.config
+        if not (config.weekly_digest_channel or config.digest_channel):
+            await ctx.send("No `weekly_digest_channel` or `digest_channel` is set.")
+            return
+
+        now = datetime.now(ZoneInfo(settings.default_timezone))
+        message = await self.post_weekly_digest(now, ctx.guild.id)
+        await ctx.send(
+            f"Weekly digest posted: {message.jump_url}" if message else "Weekly digest posted."
+        )
+
+    async def update_digest(self, now: datetime, force: bool = False) -> discord.Message | None:
+        """Post today's digest, or edit the posted one if its events changed.
+
+        Returns:
+            The digest message, or None if it wasn't touched or couldn't be sent.
+        """
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel_name = self.bot.canary.route(
+            "digest", self.bot.schedules.config.digest_channel, now
+        )
+        channel = guild and discord.utils.get(guild.text_channels, name=channel_name)
+        if not channel:
+            logger.error("Digest channel not found: %s", channel_name)
+            return None
+
+        events = self._digest_events(now)
+        pages = self._build_embeds(now, events)
+        view = self._build_view(now, events)
+        description = "\n".join(page.description or "" for page in pages)
+        # The RSVP menu changes when an event listed in it is announced
+        rsvp_events = [event.id for event in events if self.bot.rsvps.get(event.id)]
+        posted = self.bot.store.get(DIGEST, CURRENT)
+        is_today = posted is not None and posted["date"] == now.date().isoformat()
+        unchanged = is_today and posted["description"] == description
+        if unchanged and posted.get("rsvp_events", []) == rsvp_events and not force:
+            return None
+
+        message_ids = []
+        if is_today and posted["message_id"]:
+            message_ids = [posted["message_id"], *posted.get("more_message_ids", [])]
+        messages = await self._fetch_messages(channel, message_ids)
+
+        if messages and len(messages) == len(message_ids) == len(pages):
+            for message, page in zip(messages, pages, strict=True):
+                # The menus are on the last page
+                menus = view if message is messages[-1] else discord.utils.MISSING
+                await self.bot.messenger.edit(message, embed=page, view=menus)
+            logger.info("Edited digest for %s", now.date())
+        else:
+            # Reposted as a whole, so the pages stay in order
+            await self._delete_messages(channel, messages)
+            messages = await self.bot.messenger.send_parts(
+                channel, embeds=pages, silent="digest" in settings.silent_messages, view=view
+            )
+            logger.info("Posted digest for %s in %d messages", now.date(), len(pages))
+            if messages:
+                await self.bot.messenger.audit_log(
+                    f"📰 Posted the daily digest in #{channel.name} with {len(events)} events."
+                )
+
+        self.bot.store.set(
+            DIGEST,
+            CURRENT,
+            {
+                "date": now.date().isoformat(),
+                "message_id": messages[0].id if messages else None,
+                "more_message_ids": [message.id for message in messages[1:]],
+                "description": description,
+                "rsvp_events": rsvp_events,
+            },
+        )
+        return messages[0] if messages else None
+
+    async def post_weekly_digest(self, now: datetime, guild_id: int) -> discord.Message | None:
+        """Post the overview of a guild's events in the 7 days from `now`'s day on.
+
+        Returns:
+            The first message of the overview, or None if it couldn't be sent.
+        """
+        config = self.bot.schedules.config
+        channel_name = config.weekly_digest_channel or config.digest_channel
+        guild = self.bot.get_guild(guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=channel_name)
+        if not channel:
+            logger.error(
+                "Weekly digest channel not found in guild %d: %s", guild_id, channel_name
+            )
+            return None
+
+        pages = self._build_weekly_embeds(now, self._digest_events(now, WEEK_DAYS, guild_id))
+        messages = await self.bot.messenger.send_parts(
+            channel, embeds=pages, silent="digest" in settings.silent_messages
+        )
+        logger.info("Posted weekly digest in guild %d in %d messages", guild_id, len(pages))
+        if messages:
+            await self.bot.messenger.audit_log(f"📰 Posted the weekly digest in #{channel.name}.")
+        return messages[0] if messages else None
+
+    async def _fetch_messages(
+        self, channel: discord.TextChannel, message_ids: list[int]
+    ) -> list[discord.Message]:
+        """Fetch the posted digest messages that weren't deleted."""
+        messages = []
+        for message_id in message_ids:
+            try:
+                messages.append(await channel.fetch_message(message_id))
+            except discord.NotFound:
+                logger.warning("Digest message %d was deleted, posting a new digest", message_id)
+        return messages
+
+    async def _delete_messages(
+        self, channel: discord.TextChannel, messages: list[discord.Message]
+    ) -> None:
+        """Delete the pages of an outdated digest."""
+        for message in messages:
+            if settings.observer_mode:
+                self.bot.observer.record("delete message", message=message.id, reason="digest")
+                continue
+            try:
+                await message.delete()
+            except discord.HTTPException as e:
+                logger.warning("Failed to delete old digest page in #%s: %s", channel, e)
+
+    def _digest_events(
+        self, now: datetime, days: int = 1, guild_id: int | None = None
+    ) -> list[CalendarEvent]:
+        """Return the events the digest lists, by start time, from `now`'s day for `days` days.
+
+        Only the public events held in the guild (the primary one by default)
+        are listed, including those mirrored there.
+        """
+        day_start = datetime.combine(now.date(), time.min, tzinfo=now.tzinfo)
+        end = day_start + timedelta(days=days)
+        scheduler = self.bot.get_cog("SchedulerCog")
+        if not scheduler:
+            return []
+        found = {event.id: event for event in scheduler.get_events_between(day_start, end)}
+        # The scheduler only knows the next two days, so a longer digest looks further itself;
+        # events it knows about but didn't return were cancelled
+        if end - now > timedelta(hours=LOOKAHEAD_HOURS):
+            for event in self._upcoming_events(now, end):
+                if event.id not in scheduler.known_events:
+                    found[event.id] = event
+
+        events = []
+        for event in sorted(found.values(), key=lambda event: event.start_time):
+            if _held_in(event, guild_id or settings.discord_guild_id) and not is_private(event):
+                events.append(event)
+        return events
+
+    def _upcoming_events(self, now: datetime, end: datetime) -> list[CalendarEvent]:
+        """Return the calendar's, schedules', and submissions' events from now until `end`."""
+        hours = math.ceil((end - now).total_seconds() / 3600)
+        events = self.bot.calendar.get_upcoming_events(hours_ahead=hours)
+        events += self.bot.schedules.get_events_between(now, end)
+        events += self.bot.submissions.get_events_between(now, end)
+        return [event for event in events if event.start_time < end]
+
+    def _build_embeds(self, now: datetime, events: list[CalendarEvent]) -> list[discord.Embed]:
+        """Build the digest embeds listing the day's events."""
+        config = self.bot.schedules.config
+        title = f"Today's Events — {now:%A, %B} {now.day}"
+        if config.digest_title_template:
+            title = sanitize_template(config.digest_title_template).format(
+                date=now.date().isoformat()
+            )
+        empty = config.digest_empty_text or "No events today."
+        return (
+            EmbedBuilder()
+            .set_title(title)
+            .set_description("\n".join(self.digest_line(event) for event in events) or empty)
+            .set_color(discord.Color.blue())
+            .build_pages()
+        )
+
+    def _build_weekly_embeds(
+        self, now: datetime, events: list[CalendarEvent]
+    ) -> list[discord.Embed]:
+        """Build the weekly overview embeds, listing the events under a heading per day."""
+        lines = []
+        for day, day_events in groupby(
+            events, key=lambda event: event.start_time.astimezone(now.tzinfo).date()
+        ):
+            if lines:
+                lines.append("")
+            lines.append(f"**{day:%A, %B} {day.day}**")
+            lines.extend(self.digest_line(event) for event in day_events)
+
+        end = now.date() + timedelta(days=WEEK_DAYS - 1)
+        return (
+            EmbedBuilder()
+            .set_title(f"This Week's Events — {now:%B} {now.day} to {end:%B} {end.day}")
+            .set_description("\n".join(lines) or f"No events in the next {WEEK_DAYS} days.")
+            .set_color(discord.Color.blue())
+            .build_pages()
+        )
+
+    def digest_line(self, event: CalendarEvent) -> str:
+        """Return an event's line in the digest, from `digest_line_template` if it's set."""
+        start = int(event.start_time.timestamp())
+        local_times = reference_times(
+            event.start_time, settings.reference_timezones, ZoneInfo(settings.default_timezone)
+        )
+        template = self.bot.schedules.config.digest_line_template
+        if template:
+            line = sanitize_template(template).format(
+                name=sanitize_text(event.name, EVENT_NAME_LIMIT),
+                description=sanitize_text(event.description, EVENT_DESCRIPTION_LIMIT),
+                time=f"<t:{start}:t>",
+                relative=f"<t:{start}:R>",
+                duration=event.duration_minutes,
+                local_times=local_times,
+            )
+        else:
+            line = f"• <t:{start}:t> **{event.name}** ({event.duration_minutes} min)"
+            if local_times:
+                line += f" — {local_times}"
+        if event.schedule and self.bot.absences.away_owners(event.schedule, event.start_time):
+            line += " — ⚠️ host away, session led by co-host or canceled"
+        return line
+
+    def _build_view(self, now: datetime, events: list[CalendarEvent]) -> discord.ui.View | None:
+        """Build the menus for RSVPing to the day's events and setting reminders for them."""
+        upcoming = [
+            event
+            for event in events
+            if event.start_time > now and len(event.id) <= SELECT_VALUE_LIMIT
+        ][:SELECT_OPTIONS_LIMIT]
+        taking_rsvps = [event for event in upcoming if self.bot.rsvps.get(event.id)]
+
+        view = discord.ui.View(timeout=None)
+        if taking_rsvps:
+            view.add_item(self._select("rsvp", "✅ RSVP to an event…", taking_rsvps, now))
+        if upcoming:
+            view.add_item(self._select("remind", "⏰ Remind me before an event…", upcoming, now))
+        return view if view.children else None
+
+    def _select(
+        self, action: str, placeholder: str, events: list[CalendarEvent], now: datetime
+    ) -> discord.ui.Select:
+        """Create a digest menu with one option per event."""
+        options = [
+            discord.SelectOption(
+                label=event.name[:100],
+                value=event.id,
+                description=f"{event.start_time.astimezone(now.tzinfo):%H:%M} "
+                f"({event.duration_minutes} min)",
+            )
+            for event in events
+        ]
+        return discord.ui.Select(
+            custom_id=self.bot.components.custom_id(DIGEST_ACTIONS, action),
+            placeholder=placeholder,
+            options=options,
+        )
+
+    async def digest_action(self, interaction: discord.Interaction, payload: str) -> None:
+        """RSVP to, or set a reminder for, the event picked in a digest menu."""
+        event_id = (interaction.data or {}).get("values", [""])[0]
+        scheduler = self.bot.get_cog("SchedulerCog")
+        event = scheduler.known_events.get(event_id) if scheduler else None
+        if event is None or event.start_time <= datetime.now(ZoneInfo("UTC")):
+            await interaction.response.send_message(
+                "That event already started or was canceled.", ephemeral=True
+            )
+            return
+
+        if payload == "rsvp":
+            await self._rsvp(interaction, event)
+        else:
+            await self._remind(interaction, event)
+
+    async def _rsvp(self, interaction: discord.Interaction, event: CalendarEvent) -> None:
+        """RSVP a member going, as the button on the event's announcement does."""
+        rsvps = self.bot.rsvps.get(event.id)
+        if not rsvps:
+            await interaction.response.send_message(
+                "RSVPs for this event are closed.", ephemeral=True
+            )
+            return
+
+        place = self.bot.rsvps.join(event.id, interaction.user.id)
+        await interaction.response.send_message(
+            join_message(rsvps["name"], rsvps["capacity"], place), ephemeral=True
+        )
+        rsvp_cog = self.bot.get_cog("RsvpCog")
+        if rsvp_cog:
+            await rsvp_cog.refresh_announcement(event.id)
+
+    async def _remind(self, interaction: discord.Interaction, event: CalendarEvent) -> None:
+        """DM a member `REMINDER_LEAD` before an event, or right away if it's sooner."""
+        start = int(event.start_time.timestamp())
+        message = f"**{event.name}** starts <t:{start}:R>!"
+        user_id = interaction.user.id
+        reminders = self.bot.store.items(REMINDERS).values()
+        if not any(r["user_id"] == user_id and r["message"] == message for r in reminders):
+            due = max(event.start_time - REMINDER_LEAD, datetime.now(ZoneInfo("UTC")))
+            add_reminder(self.bot, user_id, None, due, message)
+
+        await interaction.response.send_message(
+            f"Okay, I'll DM you about **{event.name}** {REMINDER_LEAD.seconds // 60} minutes "
+            "before it starts.",
+            ephemeral=True,
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the digest cog."""
+    await bot.add_cog(DigestCog(bot))
@@ -0,0 +1,65 @@
+"""Command error handling."""
+
+from discord.ext import commands
+
+from ..helpers.command_docs import command_usages, parse_command_doc
+from ..helpers.permissions import MissingPermissionsError
+from ..services.errors import report_error
+from ..services.maintenance import MaintenanceError
+
+
+class ErrorsCog(commands.Cog):
+    """Replies to failed commands without exposing raw error details."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.Cog.listener()
+    async def on_command_error(self, ctx: commands.Context, error: commands.CommandError) -> None:
+        """Handle errors raised by prefix commands."""
+        if isinstance(error, commands.CommandNotFound):
+            return
+
+        if isinstance(error, commands.UserInputError):
+            command = ctx.command
+            usages = command_usages(
+                parse_command_doc(command.help),
+                ctx.clean_prefix,
+                command.qualified_name,
+                command.signature,
+            )
+            usage = "\n".join(f"`{usage}`" for usage in usages)
+            await ctx.send(f"{error}\nUsage: {usage}")
+            return
+
+        if isinstance(error, MaintenanceError):
+            await ctx.send(str(error))
+            return
+
+        if isinstance(error, commands.CheckFailure):
+            await ctx.send("You don't have permission to use this command.")
+            return
+
+        original = getattr(error, "original", error)
+        if isinstance(original, MissingPermissionsError):
+            await ctx.send(f"I can't do that: {original}.")
+            return
+
+        correlation_id = await report_error(
+            self.bot,
+            original,
+            operation=f"{ctx.clean_prefix}{ctx.command.qualified_name if ctx.command else '?'}",
+            context={
+                "User": f"{ctx.author} ({ctx.author.id})",
+                "Channel": f"#{ctx.channel} ({ctx.channel.id})",
+                "Message": ctx.message.jump_url,
+            },
+        )
+        await ctx.send(
+            f"Something went wrong. Please share this reference with an admin: `{correlation_id}`"
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the errors cog."""
+    await bot.add_cog(ErrorsCog(bot))
@@ -0,0 +1,148 @@
+"""Channel transcript and event attendance export for record-keeping."""
+
+import io
+import json
+import logging
+from datetime import datetime
+from typing import Literal
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..helpers.permissions import check_channel_permissions
+from ..helpers.timeparse import parse_duration
+from ..helpers.transcript import TranscriptMessage, render_html, render_json
+from ..services.governor import Priority
+from ..services.history import COLUMNS, attendance_rows, render_csv
+
+logger = logging.getLogger(__name__)
+
+# Upper bound on exported messages, so a huge channel can't stall the bot
+MAX_EXPORT_MESSAGES = 20000
+
+
+class ExportFlags(commands.FlagConverter, prefix="--", delimiter=" "):
+    """Options for `!export channel`."""
+
+    since: str = commands.flag(default="30d", description="How far back to export, e.g. 30d")
+    format: Literal["json", "html"] = commands.flag(
+        default="json", description="Transcript format"
+    )
+
+
+class AttendanceFlags(commands.FlagConverter, prefix="--", delimiter=" "):
+    """Options for `!export attendance`."""
+
+    since: str = commands.flag(default="90d", description="How far back to export, e.g. 90d")
+    format: Literal["csv", "json"] = commands.flag(default="csv", description="File format")
+
+
+def _transcript_message(message: discord.Message) -> TranscriptMessage:
+    """Convert a Discord message into a transcript entry."""
+    return TranscriptMessage(
+        id=message.id,
+        author=str(message.author),
+        author_id=message.author.id,
+        created_at=message.created_at,
+        content=message.content,
+        attachments=[attachment.url for attachment in message.attachments],
+        reply_to=message.reference.message_id if message.reference else None,
+    )
+
+
+class ExportCog(commands.Cog):
+    """Exports channel history as an attached transcript, and event attendance."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.hybrid_group(name="export")
+    @commands.guild_only()
+    async def export(self, ctx: commands.Context) -> None:
+        """Export server content.
+
+        Usage: !export channel #name [--since 30d] [--format json|html] | !export attendance
+        """
+        await ctx.send_help(ctx.command)
+
+    @export.command(name="channel")
+    @commands.has_permissions(administrator=True)
+    async def export_channel(
+        self, ctx: commands.Context, channel: discord.TextChannel, *, flags: ExportFlags
+    ) -> None:
+        """Export a channel's messages as a JSON or HTML transcript (admins only).
+
+        Usage: !export channel #name [--since 30d] [--format json|html]
+        Example: !export channel #planning --since 2w --format html
+        """
+        self.bot.governor.tag("export", Priority.BACKGROUND)
+        check_channel_permissions(channel, "view_channel", "read_message_history")
+        try:
+            since = datetime.now(ZoneInfo("UTC")) - parse_duration(flags.since)
+        except ValueError:
+            await ctx.send(f"Invalid duration `{flags.since}`. Try `30d` or `2w`.")
+            return
+
+        async with ctx.typing():
+            messages = []
+            # history() paginates through the channel 100 messages per request
+            async for message in channel.history(
+                limit=MAX_EXPORT_MESSAGES, after=since, oldest_first=True
+            ):
+                messages.append(_transcript_message(message))
+
+            render = render_html if flags.format == "html" else render_json
+            data = render(channel.name, since, messages).encode()
+
+        if len(data) > ctx.guild.filesize_limit:
+            await ctx.send(
+                f"The transcript is {len(data) // 1_000_000} MB, over this server's upload "
+                "limit. Try a shorter `--since`."
+            )
+            return
+
+        filename = f"{channel.name}-{since:%Y%m%d}.{flags.format}"
+        logger.info(
+            "Exported %d messages from #%s for %s", len(messages), channel.name, ctx.author
+        )
+        note = ""
+        if len(messages) == MAX_EXPORT_MESSAGES:
+            note = f" (stopped at {MAX_EXPORT_MESSAGES})"
+        await ctx.send(
+            f"Exported {len(messages)} messages from {channel.mention}{note}.",
+            file=discord.File(io.BytesIO(data), filename=filename),
+        )
+
+    @export.command(name="attendance")
+    @commands.has_permissions(manage_events=True)
+    async def export_attendance(self, ctx: commands.Context, *, flags: AttendanceFlags) -> None:
+        """Export event occurrences with interest, RSVPs, and attendance (requires Manage Events).
+
+        Usage: !export attendance [--since 90d] [--format csv|json]
+        Example: !export attendance --since 90d --format csv
+        """
+        try:
+            since = datetime.now(ZoneInfo("UTC")) - parse_duration(flags.since)
+        except ValueError:
+            await ctx.send(f"Invalid duration `{flags.since}`. Try `90d` or `12w`.")
+            return
+
+        rows = attendance_rows(self.bot.history.occurrences(since))
+        if flags.format == "json":
+            data = json.dumps([dict(zip(COLUMNS, row, strict=True)) for row in rows[1:]], indent=2)
+        else:
+            data = render_csv(rows)
+
+        logger.info("Exported %d event occurrences for %s", len(rows) - 1, ctx.author)
+        await ctx.send(
+            f"Exported {len(rows) - 1} event occurrences since <t:{int(since.timestamp())}:D>.",
+            file=discord.File(
+                io.BytesIO(data.encode()), filename=f"attendance-{since:%Y%m%d}.{flags.format}"
+            ),
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the export cog."""
+    await bot.add_cog(ExportCog(bot))
@@ -0,0 +1,140 @@
+"""Feed subscriptions, delivering new items of RSS and Atom feeds by DM."""
+
+import logging
+
+import aiohttp
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.embeds import TITLE_LIMIT
+from ..helpers.sanitize import sanitize_text
+from ..services.feeds import FeedItem, fetch_feed
+from ..services.governor import Priority
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+
+class FeedsCog(commands.Cog):
+    """Lets members subscribe to the feeds in `FEEDS`, and DMs them new items.
+
+    Each feed with subscribers is polled every `feed_poll_minutes`; members
+    who don't accept DMs are skipped.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.feeds:
+            logger.info("No feeds configured, feed subscriptions disabled")
+            return
+
+        self.feed_loop.change_interval(minutes=settings.feed_poll_minutes)
+        self.feed_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.feed_loop.cancel()
+
+    @commands.hybrid_group(name="subscribe")
+    async def subscribe(self, ctx: commands.Context) -> None:
+        """Get things delivered to you by DM.
+
+        Usage: !subscribe feed <name>
+        """
+        await ctx.send_help(ctx.command)
+
+    @subscribe.command(name="feed")
+    async def subscribe_feed(self, ctx: commands.Context, name: str) -> None:
+        """Get a feed's new items by DM.
+
+        Usage: !subscribe feed <name>
+        Example: !subscribe feed kubernetes-blog
+        """
+        if name not in settings.feeds:
+            await ctx.send(self._unknown_feed(name), ephemeral=True)
+            return
+
+        if not self.bot.feeds.subscribe(ctx.author.id, name):
+            await ctx.send(f"You're already subscribed to `{name}`.", ephemeral=True)
+            return
+        logger.info("%s subscribed to feed %s", ctx.author, name)
+        await ctx.send(
+            f"Okay, I'll DM you new items of `{name}`. Stop with `/unsubscribe feed {name}`.",
+            ephemeral=True,
+        )
+
+    @commands.hybrid_group(name="unsubscribe")
+    async def unsubscribe(self, ctx: commands.Context) -> None:
+        """Stop getting things delivered to you by DM.
+
+        Usage: !unsubscribe feed <name>
+        """
+        await ctx.send_help(ctx.command)
+
+    @unsubscribe.command(name="feed")
+    async def unsubscribe_feed(self, ctx: commands.Context, name: str) -> None:
+        """Stop getting a feed's new items.
+
+        Usage: !unsubscribe feed <name>
+        Example: !unsubscribe feed kubernetes-blog
+        """
+        if not self.bot.feeds.unsubscribe(ctx.author.id, name):
+            await ctx.send(f"You aren't subscribed to `{name}`.", ephemeral=True)
+            return
+        logger.info("%s unsubscribed from feed %s", ctx.author, name)
+        await ctx.send(f"Okay, no more items of `{name}`.", ephemeral=True)
+
+    @tasks.loop(minutes=15)
+    @drained("feeds")
+    async def feed_loop(self) -> None:
+        """Fetch the feeds members subscribed to, and DM them the new items."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("feeds", Priority.BACKGROUND)
+        for name, url in settings.feeds.items():
+            subscribers = self.bot.feeds.subscribers(name)
+            if not subscribers:
+                continue
+            try:
+                items = await fetch_feed(url)
+            except (aiohttp.ClientError, TimeoutError, ValueError) as e:
+                logger.warning("Failed to fetch feed %s: %s", name, e)
+                continue
+
+            for item in self.bot.feeds.new_items(name, items):
+                for user_id in subscribers:
+                    await self.deliver(user_id, name, item)
+
+    @feed_loop.before_loop
+    async def before_feed_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+        logger.info("Feed loop started for %s", ", ".join(settings.feeds))
+
+    async def deliver(self, user_id: int, feed: str, item: FeedItem) -> None:
+        """DM a subscriber a new item of a feed."""
+        title = sanitize_text(item.title, TITLE_LIMIT) or "New item"
+        try:
+            user = self.bot.get_user(user_id) or await self.bot.fetch_user(user_id)
+            await self.bot.messenger.send(user, f"📰 **{feed}**: {title}\n{item.link}".strip())
+        except discord.Forbidden:
+            logger.info("User %d doesn't accept DMs, not sent feed %s", user_id, feed)
+        except discord.HTTPException as e:
+            logger.error("Failed to send feed %s to %d: %s", feed, user_id, e)
+
+    def _unknown_feed(self, name: str) -> str:
+        """Tell a member a feed doesn't exist, and which ones do."""
+        if not settings.feeds:
+            return "No feeds are set up."
+        feeds = ", ".join(f"`{feed}`" for feed in settings.feeds)
+        return f"There's no feed `{name}`. Feeds: {feeds}"
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the feeds cog."""
+    await bot.add_cog(FeedsCog(bot))
@@ -0,0 +1,131 @@
+"""`!help` generated from the registered commands and their docstrings."""
+
+from collections.abc import Mapping
+
+import discord
+from discord.ext import commands
+
+from ..helpers.command_docs import command_usages, parse_command_doc
+from ..helpers.embeds import FIELD_NAME_LIMIT, EmbedBuilder
+
+HELP_DOC = """Show the commands you can use, or how to use one.
+
+Usage: !help [command]
+Example: !help schedules create
+"""
+
+
+def _category(cog: commands.Cog | None) -> str:
+    """Heading for a cog's commands, e.g. "Schedules" for `SchedulesCog`."""
+    if cog is None:
+        return "General"
+    return cog.qualified_name.removesuffix("Cog") or cog.qualified_name
+
+
+class EmbedHelpCommand(commands.HelpCommand):
+    """Lists commands with their usage and aliases, hiding those the member can't run."""
+
+    def __init__(self) -> None:
+        super().__init__(command_attrs={"help": HELP_DOC, "aliases": ["commands"]})
+
+    def _usages(self, command: commands.Command) -> list[str]:
+        """Usages of a command, with the prefix the member used."""
+        return command_usages(
+            parse_command_doc(command.help),
+            self.context.clean_prefix,
+            command.qualified_name,
+            command.signature,
+        )
+
+    def _line(self, command: commands.Command) -> str:
+        """One line for a command in a list: its name and summary."""
+        summary = parse_command_doc(command.help).summary
+        name = f"`{self.context.clean_prefix}{command.qualified_name}`"
+        return f"{name} — {summary}" if summary else name
+
+    async def _send(self, builder: EmbedBuilder) -> None:
+        """Send the help, over several embeds if it's long."""
+        destination = self.get_destination()
+        for embed in builder.set_color(discord.Color.blurple()).build_pages():
+            await destination.send(embed=embed)
+
+    async def send_bot_help(
+        self, mapping: Mapping[commands.Cog | None, list[commands.Command]]
+    ) -> None:
+        """List every command the member can run, grouped by cog."""
+        builder = EmbedBuilder().set_title("Commands")
+        for cog, cog_commands in sorted(mapping.items(), key=lambda item: _category(item[0])):
+            runnable = await self.filter_commands(cog_commands, sort=True)
+            if runnable:
+                lines = "\n".join(self._line(command) for command in runnable)
+                builder.add_field(_category(cog)[:FIELD_NAME_LIMIT], lines)
+
+        builder.set_footer(f"Use {self.context.clean_prefix}help <command> for more about one.")
+        await self._send(builder)
+
+    async def send_cog_help(self, cog: commands.Cog) -> None:
+        """List a cog's commands the member can run."""
+        runnable = await self.filter_commands(cog.get_commands(), sort=True)
+        builder = EmbedBuilder().set_title(_category(cog))
+        builder.set_description(
+            "\n".join(self._line(command) for command in runnable) or "No commands you can use."
+        )
+        await self._send(builder)
+
+    def _command_builder(self, command: commands.Command) -> EmbedBuilder:
+        """Embed describing one command: its usage, examples, and aliases."""
+        doc = parse_command_doc(command.help)
+        builder = (
+            EmbedBuilder()
+            .set_title(f"{self.context.clean_prefix}{command.qualified_name}")
+            .set_description("\n\n".join(part for part in (doc.summary, doc.details) if part))
+        )
+        builder.add_field("Usage", "\n".join(f"`{usage}`" for usage in self._usages(command)))
+        if doc.examples:
+            builder.add_field("Examples", "\n".join(f"`{example}`" for example in doc.examples))
+        if command.aliases:
+            parent = f"{command.full_parent_name} " if command.parent else ""
+            aliases = (f"`{self.context.clean_prefix}{parent}{alias}`" for alias in command.aliases)
+            builder.add_field("Aliases", ", ".join(aliases))
+        return builder
+
+    async def send_command_help(self, command: commands.Command) -> None:
+        """Describe one command."""
+        await self._send(self._command_builder(command))
+
+    async def send_group_help(self, group: commands.Group) -> None:
+        """Describe a command group and list its subcommands the member can run."""
+        builder = self._command_builder(group)
+        runnable = await self.filter_commands(group.commands, sort=True)
+        if runnable:
+            builder.add_field("Subcommands", "\n".join(self._line(command) for command in runnable))
+        await self._send(builder)
+
+    async def send_error_message(self, error: str) -> None:
+        """Tell the member the command they asked about doesn't exist."""
+        await self.get_destination().send(
+            f"{error} Use `{self.context.clean_prefix}help` to see the commands.",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+
+class HelpCog(commands.Cog):
+    """Replaces the plain-text default `!help` with embeds built from the commands."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self._default_help = bot.help_command
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.help_command = EmbedHelpCommand()
+        self.bot.help_command.cog = self
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.bot.help_command = self._default_help
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the help cog."""
+    await bot.add_cog(HelpCog(bot))
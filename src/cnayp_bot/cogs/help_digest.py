@@ -0,0 +1,176 @@
+"""Periodic digest of unanswered questions in the help channel."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.embeds import EmbedBuilder
+from ..helpers.help_questions import (
+    digest_lines,
+    has_reply,
+    help_digest_due,
+    question_window,
+    unanswered_messages,
+)
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+# "last_run" -> when the previous digest was collected, posted or not
+HELP_DIGEST = "help_digest"
+LAST_RUN = "last_run"
+
+
+class HelpDigestCog(commands.Cog):
+    """Posts a digest of help channel questions that nobody answered."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.help_channel:
+            logger.info("Help channel not configured, unanswered digest disabled")
+            return
+
+        self.digest_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.digest_loop.cancel()
+
+    @tasks.loop(minutes=10)
+    @drained("unanswered questions digest")
+    async def digest_loop(self) -> None:
+        """Post the unanswered questions digest every `HELP_DIGEST_HOURS`."""
+        if not self.bot.leader.is_leader:
+            return
+
+        try:
+            now = datetime.now(ZoneInfo("UTC"))
+            last_run = self.bot.store.get(HELP_DIGEST, LAST_RUN)
+            if last_run is None:
+                self.bot.store.set(HELP_DIGEST, LAST_RUN, now.isoformat())
+                return
+            last_run = datetime.fromisoformat(last_run)
+            if not help_digest_due(now, last_run, settings.help_digest_hours):
+                return
+
+            await self.post_digest(now, last_run)
+            self.bot.store.set(HELP_DIGEST, LAST_RUN, now.isoformat())
+        except Exception as e:
+            logger.exception("Error in help digest loop: %s", e)
+
+    @digest_loop.before_loop
+    async def before_digest_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+        logger.info("Help digest loop started for #%s", settings.help_channel)
+
+    async def post_digest(self, now: datetime, last_run: datetime) -> None:
+        """Collect the questions left unanswered since the last run and post them."""
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if not guild:
+            logger.error("Guild not found: %d", settings.discord_guild_id)
+            return
+
+        channel = discord.utils.get(guild.channels, name=settings.help_channel)
+        if not isinstance(channel, discord.TextChannel | discord.ForumChannel):
+            logger.error("Help channel not found: %s", settings.help_channel)
+            return
+
+        asked_after, answered_before = question_window(
+            now, last_run, settings.help_unanswered_minutes
+        )
+
+        if isinstance(channel, discord.ForumChannel):
+            questions = await self._unanswered_forum_posts(channel, asked_after, answered_before)
+            target = None
+        else:
+            questions = await self._unanswered_messages(channel, asked_after, answered_before)
+            target = channel
+
+        logger.info("Found %d unanswered questions in #%s", len(questions), channel.name)
+        if not questions:
+            return
+
+        if target is None:
+            # Forum channels can't receive messages directly, fall back to the notify channel
+            target = discord.utils.get(guild.text_channels, name=settings.discord_notify_channel)
+            if not target:
+                logger.error("Notify channel not found: %s", settings.discord_notify_channel)
+                return
+
+        await self.bot.messenger.send(
+            target,
+            " ".join(f"<@&{role_id}>" for role_id in settings.helper_role_ids) or None,
+            embed=self._build_embed(questions),
+            allowed_mentions=discord.AllowedMentions(roles=True),
+        )
+        logger.info("Sent unanswered questions digest to #%s", target.name)
+
+    async def _unanswered_messages(
+        self, channel: discord.TextChannel, asked_after: datetime, answered_before: datetime
+    ) -> list[discord.Message]:
+        """Find messages without replies, reactions, or thread activity."""
+        # history() paginates through the channel 100 messages per request
+        history = [
+            message
+            async for message in channel.history(limit=None, after=asked_after, oldest_first=True)
+        ]
+        unanswered = []
+        for message in unanswered_messages(history, answered_before):
+            if message.thread and await self._thread_has_reply(message.thread, message.author.id):
+                continue
+            unanswered.append(message)
+
+        return unanswered
+
+    async def _unanswered_forum_posts(
+        self, channel: discord.ForumChannel, asked_after: datetime, answered_before: datetime
+    ) -> list[discord.Message]:
+        """Find forum posts where nobody but the author has written."""
+        unanswered = []
+
+        for thread in channel.threads:
+            if not thread.created_at or not asked_after < thread.created_at <= answered_before:
+                continue
+            if await self._thread_has_reply(thread, thread.owner_id):
+                continue
+
+            try:
+                starter = await thread.fetch_message(thread.id)
+            except discord.NotFound:
+                continue
+            if not starter.reactions:
+                unanswered.append(starter)
+
+        return unanswered
+
+    async def _thread_has_reply(self, thread: discord.Thread, author_id: int | None) -> bool:
+        """Check whether anyone other than the question author posted in a thread."""
+        messages = [message async for message in thread.history(limit=None)]
+        return has_reply(messages, thread.id, author_id)
+
+    def _build_embed(self, questions: list[discord.Message]) -> discord.Embed:
+        """Build the digest embed listing unanswered questions."""
+        lines = digest_lines(questions)
+        builder = (
+            EmbedBuilder()
+            .set_title("Unanswered Questions")
+            .set_description("\n".join(lines))
+            .set_color(discord.Color.orange())
+        )
+        if len(lines) < len(questions):
+            builder.set_footer(text=f"Showing {len(lines)} of {len(questions)} questions")
+
+        return builder.build()
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the help digest cog."""
+    await bot.add_cog(HelpDigestCog(bot))
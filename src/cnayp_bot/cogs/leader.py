@@ -0,0 +1,63 @@
+"""Leader election between replicas."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from discord.ext import commands, tasks
+
+from ..config import settings
+
+logger = logging.getLogger(__name__)
+
+
+class LeaderCog(commands.Cog):
+    """Keeps this replica's leader lease renewed when several replicas run.
+
+    The scheduler, reminder, and digest loops check `bot.leader.is_leader`
+    and skip their work on followers, so announcements are sent once. The
+    replicas share the store, which a new leader reads again before its loops
+    run, picking up what the old leader sent.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.was_leader = False
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.leader_lease_path:
+            logger.info("No leader lease configured, running as the only instance")
+            return
+
+        # Renew three times per lease, so one missed renewal doesn't lose it
+        self.lease_loop.change_interval(seconds=settings.leader_lease_seconds / 3)
+        self.lease_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.lease_loop.cancel()
+        self.bot.leader.release(datetime.now(ZoneInfo("UTC")))
+
+    @tasks.loop(seconds=10)
+    async def lease_loop(self) -> None:
+        """Take or renew the lease, logging leadership changes."""
+        try:
+            is_leader = self.bot.leader.renew(datetime.now(ZoneInfo("UTC")))
+        except Exception as e:
+            logger.exception("Error in leader lease loop: %s", e)
+            return
+
+        if is_leader != self.was_leader:
+            instance = self.bot.leader.instance_id
+            if is_leader:
+                self.bot.store.reload()
+                logger.info("Instance %s is now the leader", instance)
+            else:
+                logger.warning("Instance %s is no longer the leader", instance)
+            self.was_leader = is_leader
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the leader cog."""
+    await bot.add_cog(LeaderCog(bot))
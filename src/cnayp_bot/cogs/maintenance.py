@@ -0,0 +1,101 @@
+"""Maintenance mode for config migrations and other downtime."""
+
+import logging
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.presence import PRESENCE_LIMIT
+from ..services.maintenance import MaintenanceError
+
+logger = logging.getLogger(__name__)
+
+
+class MaintenanceCog(commands.Cog):
+    """Pauses the scheduler and non-admin commands while maintenance mode is on.
+
+    The scheduler, digest, and presence loops check `bot.maintenance` and skip
+    their work; commands from non-admins get the maintenance notice instead.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def bot_check(self, ctx: commands.Context) -> bool:
+        """Reject commands from non-admins during maintenance."""
+        if not self.bot.maintenance.active:
+            return True
+        if ctx.guild and ctx.author.guild_permissions.administrator:
+            return True
+        message = self.bot.maintenance.message
+        raise MaintenanceError(f"🛠️ The bot is under maintenance: {message}")
+
+    @commands.Cog.listener()
+    async def on_ready(self) -> None:
+        """Restore the maintenance presence after a restart."""
+        if self.bot.maintenance.active:
+            await self._update_presence()
+
+    @commands.hybrid_group(name="maintenance")
+    @commands.guild_only()
+    async def maintenance(self, ctx: commands.Context) -> None:
+        """Turn maintenance mode on or off.
+
+        Usage: !maintenance on <message> | !maintenance off
+        """
+        await ctx.send_help(ctx.command)
+
+    @maintenance.command(name="on")
+    @commands.has_permissions(administrator=True)
+    async def maintenance_on(self, ctx: commands.Context, *, message: str) -> None:
+        """Pause the scheduler and non-admin commands, showing a notice (admins only).
+
+        Usage: !maintenance on <message>
+        Example: !maintenance on Migrating schedules, back in 30 minutes
+        """
+        self.bot.maintenance.start(message, ctx.author.id)
+        await self._update_presence()
+        logger.warning("Maintenance mode on by %s: %s", ctx.author, message)
+        await ctx.send(
+            "🛠️ Maintenance mode is on. The scheduler and non-admin commands are paused."
+        )
+
+    @maintenance.command(name="off")
+    @commands.has_permissions(administrator=True)
+    async def maintenance_off(self, ctx: commands.Context) -> None:
+        """Resume the scheduler and commands (admins only).
+
+        Usage: !maintenance off
+        """
+        if not self.bot.maintenance.active:
+            await ctx.send("Maintenance mode isn't on.")
+            return
+
+        self.bot.maintenance.stop()
+        await self._update_presence()
+        logger.warning("Maintenance mode off by %s", ctx.author)
+        await ctx.send("✅ Maintenance mode is off. Everything is running again.")
+
+    async def _update_presence(self) -> None:
+        """Show Do Not Disturb with the notice during maintenance, or go back online."""
+        if self.bot.maintenance.active:
+            status = discord.Status.dnd
+            text = f"🛠️ {self.bot.maintenance.message}"[:PRESENCE_LIMIT]
+            activity = discord.CustomActivity(name=text)
+        else:
+            # The presence loop puts the rotating messages back on its next run
+            status = discord.Status.online
+            activity = None
+
+        if settings.observer_mode:
+            text = activity.name if activity else None
+            self.bot.observer.record("set presence", status=status.name, text=text)
+            return
+
+        await self.bot.change_presence(status=status, activity=activity)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the maintenance cog."""
+    await bot.add_cog(MaintenanceCog(bot))
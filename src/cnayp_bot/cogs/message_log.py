@@ -0,0 +1,253 @@
+"""Deleted message logs, edit history, and ghost ping callouts."""
+
+import logging
+from dataclasses import replace
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.diff import render_diff
+from ..helpers.embeds import DESCRIPTION_LIMIT, FIELD_VALUE_LIMIT, EmbedBuilder
+from ..services.message_cache import MessageSnapshot
+
+logger = logging.getLogger(__name__)
+
+
+def snapshot(message: discord.Message) -> MessageSnapshot:
+    """Snapshot what a message says and who it mentions."""
+    return MessageSnapshot(
+        id=message.id,
+        channel_id=message.channel.id,
+        author_id=message.author.id,
+        author=str(message.author),
+        content=message.content,
+        created_at=message.created_at,
+        user_mentions=[user.id for user in message.mentions],
+        role_mentions=[role.id for role in message.role_mentions],
+        mentions_everyone=message.mention_everyone,
+        attachments=[attachment.filename for attachment in message.attachments],
+    )
+
+
+class MessageLogCog(commands.Cog):
+    """Logs deleted and edited messages in the mod channel and calls out ghost pings.
+
+    Members' messages are snapshotted in `bot.message_cache` as they're sent
+    and edited, since Discord's delete and update events don't say what a
+    message said before. A message deleted soon after mentioning someone is a
+    ghost ping, and the members it pinged are told who did. Edits are diffed
+    and recorded in the edit log channels only. Only the leader reports, so
+    replicas don't report the same change twice.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Remember members' messages."""
+        if message.author.bot or not message.guild:
+            return
+        if message.guild.id != settings.discord_guild_id:
+            return
+        self.bot.message_cache.add(snapshot(message))
+
+    @commands.Cog.listener()
+    async def on_raw_message_edit(self, payload: discord.RawMessageUpdateEvent) -> None:
+        """Update an edited message, and log the edit in moderated channels.
+
+        The mentions edited out of a message are kept, so deleting it later
+        still counts as a ghost ping.
+        """
+        known = self.bot.message_cache.get(payload.message_id)
+        data = payload.data
+        content = data.get("content")
+        # Updates that only add link previews leave the content as it was
+        if known is None or content is None or content == known.content:
+            return
+
+        user_mentions = [int(user["id"]) for user in data.get("mentions", [])]
+        role_mentions = [int(role_id) for role_id in data.get("mention_roles", [])]
+        edited = replace(
+            known,
+            content=content,
+            user_mentions=list(dict.fromkeys(known.user_mentions + user_mentions)),
+            role_mentions=list(dict.fromkeys(known.role_mentions + role_mentions)),
+            mentions_everyone=known.mentions_everyone or data.get("mention_everyone", False),
+        )
+        self.bot.message_cache.add(edited)
+
+        if payload.channel_id not in settings.edit_log_channels or not self.bot.leader.is_leader:
+            return
+        if self._exempt(known.author_id):
+            return
+
+        self.bot.governor.tag("message_log")
+        now = datetime.now(ZoneInfo("UTC"))
+        self.bot.edit_history.record(
+            known.id, known.channel_id, known.author_id, known.content, content, now
+        )
+        try:
+            await self._log_edit(known, content)
+        except Exception as e:
+            logger.exception("Error logging edit of message %d: %s", known.id, e)
+
+    @commands.Cog.listener()
+    async def on_raw_message_delete(self, payload: discord.RawMessageDeleteEvent) -> None:
+        """Log a deleted message and call out ghost pings."""
+        deleted = self.bot.message_cache.pop(payload.message_id)
+        if deleted is None or not self.bot.leader.is_leader:
+            return
+
+        ghost_ping = settings.ghost_ping_callouts and deleted.is_ghost_ping(
+            datetime.now(ZoneInfo("UTC"))
+        )
+        self.bot.governor.tag("message_log")
+        try:
+            await self._log_deletion(deleted, ghost_ping)
+            if ghost_ping:
+                await self._call_out(deleted)
+        except Exception as e:
+            logger.exception("Error reporting deleted message %d: %s", deleted.id, e)
+
+    async def _log_deletion(self, deleted: MessageSnapshot, ghost_ping: bool) -> None:
+        """Post a deleted message's content and author in the mod channel."""
+        if not settings.mod_channel:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=settings.mod_channel)
+        if not channel:
+            logger.error("Mod channel not found: %s", settings.mod_channel)
+            return
+
+        content = deleted.content or "*No text.*"
+        if len(content) > DESCRIPTION_LIMIT:
+            content = content[: DESCRIPTION_LIMIT - 1] + "…"
+        builder = (
+            EmbedBuilder()
+            .set_title("👻 Ghost ping deleted" if ghost_ping else "🗑️ Message deleted")
+            .set_description(content)
+            .set_color(discord.Color.orange() if ghost_ping else discord.Color.light_grey())
+            .add_field(
+                name="Author", value=f"<@{deleted.author_id}> ({deleted.author})", inline=True
+            )
+            .add_field(name="Channel", value=f"<#{deleted.channel_id}>", inline=True)
+            .add_field(
+                name="Sent", value=f"<t:{int(deleted.created_at.timestamp())}:R>", inline=True
+            )
+        )
+        if ghost_ping:
+            builder.add_field(
+                name="Mentioned", value=_mentions(deleted)[:FIELD_VALUE_LIMIT], inline=False
+            )
+        if deleted.attachments:
+            builder.add_field(
+                name="Attachments",
+                value=", ".join(deleted.attachments)[:FIELD_VALUE_LIMIT],
+                inline=False,
+            )
+        await self.bot.messenger.send(
+            channel, embed=builder.build(), allowed_mentions=discord.AllowedMentions.none()
+        )
+
+    def _exempt(self, author_id: int) -> bool:
+        """Check whether a member has a role exempt from the edit log."""
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        member = guild and guild.get_member(author_id)
+        if not member:
+            return False
+        return any(role.id in settings.edit_log_exempt_role_ids for role in member.roles)
+
+    async def _log_edit(self, known: MessageSnapshot, content: str) -> None:
+        """Post the diff of an edited message in the mod channel."""
+        if not settings.mod_channel:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=settings.mod_channel)
+        if not channel:
+            logger.error("Mod channel not found: %s", settings.mod_channel)
+            return
+
+        link = f"https://discord.com/channels/{guild.id}/{known.channel_id}/{known.id}"
+        embed = (
+            EmbedBuilder()
+            .set_title("✏️ Message edited", url=link)
+            .set_description(render_diff(known.content, content, DESCRIPTION_LIMIT))
+            .set_color(discord.Color.blue())
+            .add_field(name="Author", value=f"<@{known.author_id}> ({known.author})", inline=True)
+            .add_field(name="Channel", value=f"<#{known.channel_id}>", inline=True)
+            .set_footer(text=f"!edits {known.id} for the full history")
+            .build()
+        )
+        await self.bot.messenger.send(
+            channel, embed=embed, allowed_mentions=discord.AllowedMentions.none()
+        )
+
+    @commands.command(name="edits")
+    @commands.has_permissions(manage_messages=True)
+    async def edits(self, ctx: commands.Context, message_id: int) -> None:
+        """Show the recorded edits of a message (moderators only).
+
+        Usage: !edits <message_id>
+        Example: !edits 1234567890123456789
+        """
+        entry = self.bot.edit_history.get(message_id)
+        if not entry:
+            await ctx.send(
+                f"No edits recorded for message {message_id}. Only edits in the edit log "
+                f"channels from the last {settings.edit_log_days} days are kept."
+            )
+            return
+
+        builder = (
+            EmbedBuilder()
+            .set_title(f"Edits of message {message_id}")
+            .set_description(f"By <@{entry['author_id']}> in <#{entry['channel_id']}>")
+            .set_color(discord.Color.blue())
+        )
+        for edit in reversed(entry["edits"]):
+            at = int(datetime.fromisoformat(edit["at"]).timestamp())
+            name = f"<t:{at}:f>"
+            value = render_diff(edit["before"], edit["after"], FIELD_VALUE_LIMIT)
+            if not builder.can_add_field(name, value):
+                break
+            builder.add_field(name=name, value=value, inline=False)
+        await ctx.send(embed=builder.build(), allowed_mentions=discord.AllowedMentions.none())
+
+    async def _call_out(self, deleted: MessageSnapshot) -> None:
+        """Tell the pinged members who mentioned them, in the message's channel."""
+        channel = self.bot.get_channel(deleted.channel_id)
+        if not channel:
+            return
+
+        logger.info("Ghost ping by %s in #%s", deleted.author, channel)
+        await self.bot.messenger.send(
+            channel,
+            f"👻 **Ghost ping:** <@{deleted.author_id}> mentioned {_mentions(deleted)} "
+            "in a message that was deleted.",
+            # Ping the members again, but not the author, roles, or everyone
+            allowed_mentions=discord.AllowedMentions(
+                everyone=False,
+                roles=False,
+                users=[discord.Object(user_id) for user_id in deleted.pinged],
+            ),
+        )
+
+
+def _mentions(deleted: MessageSnapshot) -> str:
+    """List whoever a message mentioned."""
+    mentions = [f"<@{user_id}>" for user_id in deleted.pinged]
+    mentions += [f"<@&{role_id}>" for role_id in deleted.role_mentions]
+    if deleted.mentions_everyone:
+        mentions.append("@everyone")
+    return ", ".join(mentions)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the message log cog."""
+    await bot.add_cog(MessageLogCog(bot))
@@ -0,0 +1,55 @@
+"""Guild setup command and the notification role picker."""
+
+import logging
+
+import discord
+from discord.ext import commands
+
+from ..bootstrap import REASON, ROLE_PICKER, bootstrap_guild
+
+logger = logging.getLogger(__name__)
+
+
+class OnboardingCog(commands.Cog):
+    """Sets up new guilds and handles the notification role picker."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(ROLE_PICKER, self.toggle_role)
+
+    @commands.hybrid_command(name="setup")
+    @commands.guild_only()
+    @commands.has_permissions(administrator=True)
+    async def setup_guild(self, ctx: commands.Context) -> None:
+        """Create the recommended channels, role, role picker, and starter schedules.
+
+        Usage: !setup
+        """
+        async with ctx.typing():
+            report = await bootstrap_guild(self.bot, ctx.guild)
+        await ctx.send("\n".join(f"• {line}" for line in report))
+
+    async def toggle_role(self, interaction: discord.Interaction, payload: str) -> None:
+        """Give or remove the role encoded in a role picker button."""
+        role = interaction.guild and interaction.guild.get_role(int(payload))
+        if role is None:
+            await interaction.response.send_message("That role no longer exists.", ephemeral=True)
+            return
+
+        if role in interaction.user.roles:
+            await interaction.user.remove_roles(role, reason=REASON)
+            message = f"You'll no longer be notified via {role.mention}."
+        else:
+            await interaction.user.add_roles(role, reason=REASON)
+            message = f"You'll now be notified via {role.mention}."
+
+        logger.info("Toggled role %s for %s", role.name, interaction.user)
+        await interaction.response.send_message(message, ephemeral=True)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the onboarding cog."""
+    await bot.add_cog(OnboardingCog(bot))
@@ -0,0 +1,91 @@
+"""Partner communities our public sessions are announced in."""
+
+import logging
+
+import discord
+from discord.ext import commands
+
+logger = logging.getLogger(__name__)
+
+
+class PartnersCog(commands.Cog):
+    """Commands for the partners in the schedules file's `partners`.
+
+    The scheduler announces their schedules' events in each partner's
+    channel; here organizers check where announcements go, and partners
+    opt out, or back in, from their own server.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.hybrid_group(name="partners")
+    @commands.guild_only()
+    async def partners(self, ctx: commands.Context) -> None:
+        """Partner communities our public sessions are announced in.
+
+        Usage: !partners list | optout <on|off>
+        """
+        await ctx.send_help(ctx.command)
+
+    @partners.command(name="list")
+    @commands.has_permissions(manage_events=True)
+    async def list_partners(self, ctx: commands.Context) -> None:
+        """Show the partners, the schedules announced to each, and whether they get them.
+
+        Usage: !partners list
+        """
+        partners = self.bot.schedules.config.partners
+        if not partners:
+            await ctx.send("No partners are set up in the schedules file.", ephemeral=True)
+            return
+
+        lines = []
+        for partner in partners:
+            guild = self.bot.get_guild(partner.guild_id)
+            if self.bot.partners.opted_out(partner.guild_id):
+                status = "opted out"
+            elif not guild:
+                status = "the bot isn't in their server"
+            elif not discord.utils.get(guild.text_channels, name=partner.channel):
+                status = f"#{partner.channel} not found"
+            else:
+                status = f"announced in #{partner.channel}"
+            lines.append(f"- **{partner.name}**: {', '.join(partner.schedules)} ({status})")
+        await ctx.send(
+            "\n".join(lines), ephemeral=True, allowed_mentions=discord.AllowedMentions.none()
+        )
+
+    @partners.command(name="optout")
+    @commands.has_permissions(manage_guild=True)
+    async def optout(self, ctx: commands.Context, enabled: bool) -> None:
+        """Stop announcements of our sessions in this server, or resume them (Manage Server).
+
+        Usage: !partners optout <on|off>
+        Example: !partners optout on
+        """
+        partner = next(
+            (p for p in self.bot.schedules.config.partners if p.guild_id == ctx.guild.id), None
+        )
+        if not partner:
+            await ctx.send("This server isn't one of our partners.", ephemeral=True)
+            return
+
+        self.bot.partners.set_opted_out(ctx.guild.id, enabled)
+        logger.info(
+            "%s %s partner announcements in %s",
+            ctx.author,
+            "stopped" if enabled else "resumed",
+            partner.name,
+        )
+        if enabled:
+            await ctx.send("Our sessions won't be announced here anymore.", ephemeral=True)
+        else:
+            await ctx.send(
+                f"Our sessions will be announced in #{partner.channel} again.", ephemeral=True
+            )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the partners cog."""
+    await bot.add_cog(PartnersCog(bot))
@@ -0,0 +1,118 @@
+"""Tasks exchanged with the other bots of the CNAYP fleet."""
+
+import json
+import logging
+from datetime import datetime, timedelta
+from typing import Any
+from zoneinfo import ZoneInfo
+
+from discord.ext import commands
+
+from ..services.peers import PeerError
+
+logger = logging.getLogger(__name__)
+
+# Longest a peer can pause pings for in one request
+MAX_PAUSE_MINUTES = 240
+
+
+class PeersCog(commands.Cog):
+    """Runs the tasks peer bots may ask for, and lets admins send tasks to them.
+
+    The moderation bot, for example, asks this bot to pause @everyone, @here,
+    and role pings during an incident with the `pause_pings` task.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.peers.register("pause_pings", self.pause_pings)
+        self.bot.peers.register("resume_pings", self.resume_pings)
+
+    async def pause_pings(self, sender: str, params: dict[str, Any]) -> dict[str, Any]:
+        """Pause mass pings for `minutes`, giving a `reason`.
+
+        Raises:
+            PeerError: If `minutes` is missing or out of range.
+        """
+        try:
+            minutes = int(params.get("minutes", 60))
+        except (TypeError, ValueError) as e:
+            raise PeerError("minutes must be a number") from e
+        if not 1 <= minutes <= MAX_PAUSE_MINUTES:
+            raise PeerError(f"minutes must be between 1 and {MAX_PAUSE_MINUTES}")
+
+        reason = str(params.get("reason") or f"Requested by {sender}")
+        until = datetime.now(ZoneInfo("UTC")) + timedelta(minutes=minutes)
+        self.bot.messenger.pause_pings(until, reason)
+        await self.bot.messenger.alert_ops(
+            f"🔕 {sender} paused pings until <t:{int(until.timestamp())}:t>: {reason}"
+        )
+        return {"paused_until": until.isoformat()}
+
+    async def resume_pings(self, sender: str, params: dict[str, Any]) -> dict[str, Any]:
+        """Lift a pause on mass pings."""
+        self.bot.messenger.resume_pings()
+        await self.bot.messenger.alert_ops(f"🔔 {sender} resumed pings")
+        return {"paused_until": None}
+
+    @commands.hybrid_group(name="peers")
+    @commands.guild_only()
+    @commands.has_permissions(administrator=True)
+    async def peers(self, ctx: commands.Context) -> None:
+        """List peer bots, or send one a task (admins only).
+
+        Usage: !peers list | !peers send <peer> <action> [params]
+        """
+        await ctx.send_help(ctx.command)
+
+    @peers.command(name="list")
+    @commands.has_permissions(administrator=True)
+    async def peers_list(self, ctx: commands.Context) -> None:
+        """List the configured peer bots and the tasks they can ask for.
+
+        Usage: !peers list
+        """
+        network = self.bot.peers
+        if not network.peers:
+            await ctx.send("No peer bots are configured.")
+            return
+
+        names = ", ".join(f"**{name}**" for name in sorted(network.peers))
+        actions = ", ".join(f"`{action}`" for action in network.actions) or "none"
+        await ctx.send(f"This bot is **{network.name}**. Peers: {names}\nAccepted tasks: {actions}")
+
+    @peers.command(name="send")
+    @commands.has_permissions(administrator=True)
+    async def peers_send(
+        self, ctx: commands.Context, peer: str, action: str, *, params: str = "{}"
+    ) -> None:
+        """Ask a peer bot to run a task, with its params as a JSON object.
+
+        Usage: !peers send <peer> <action> [params]
+        Example: !peers send moderation lock_channel {"channel": "general"}
+        """
+        try:
+            parsed = json.loads(params)
+        except ValueError:
+            parsed = None
+        if not isinstance(parsed, dict):
+            await ctx.send("Params must be a JSON object.")
+            return
+
+        try:
+            result = await self.bot.peers.send(peer, action, parsed)
+        except PeerError as e:
+            logger.warning("Peer task %s for %s failed: %s", action, peer, e)
+            await ctx.send(f"❌ {e}")
+            return
+
+        logger.info("Sent %s to %s for %s", action, peer, ctx.author)
+        await ctx.send(f"✅ {peer} ran `{action}`: `{json.dumps(result)}`")
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the peers cog."""
+    await bot.add_cog(PeersCog(bot))
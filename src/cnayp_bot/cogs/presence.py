@@ -0,0 +1,103 @@
+"""Bot presence rotating through upcoming event details."""
+
+import logging
+from datetime import datetime, time, timedelta
+from string import Formatter
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.presence import PRESENCE_FIELDS, render_presence
+from ..services.calendar import CalendarEvent
+from ..services.schedules import is_private
+
+logger = logging.getLogger(__name__)
+
+
+def _activity(kind: str, text: str) -> discord.BaseActivity:
+    """Build the Discord activity for a rendered presence."""
+    if kind == "custom":
+        return discord.CustomActivity(name=text)
+    return discord.Activity(type=getattr(discord.ActivityType, kind), name=text)
+
+
+class PresenceCog(commands.Cog):
+    """Cycles the bot's presence through `PRESENCE_MESSAGES` on a timer.
+
+    Messages that mention the next event are skipped while nothing is coming up.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.messages: list[str] = []
+        self.index = 0
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        for message in settings.presence_messages:
+            fields = {field for _, field, _, _ in Formatter().parse(message) if field is not None}
+            if fields - PRESENCE_FIELDS:
+                logger.error("Skipping presence message with unknown placeholders: %s", message)
+                continue
+            self.messages.append(message)
+
+        if not self.messages:
+            logger.info("No presence messages configured, presence rotation disabled")
+            return
+
+        self.presence_loop.change_interval(minutes=settings.presence_interval_minutes)
+        self.presence_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.presence_loop.cancel()
+
+    @tasks.loop(minutes=5)
+    async def presence_loop(self) -> None:
+        """Show the next presence message that applies right now."""
+        if self.bot.maintenance.active:
+            return
+
+        try:
+            now = datetime.now(ZoneInfo(settings.default_timezone))
+            next_monday = now.date() + timedelta(days=7 - now.weekday())
+            week_end = datetime.combine(next_monday, time.min, tzinfo=now.tzinfo)
+            events = self._upcoming_events(now)
+
+            for _ in self.messages:
+                template = self.messages[self.index % len(self.messages)]
+                self.index += 1
+                rendered = render_presence(template, events, now, week_end)
+                if rendered:
+                    break
+            else:
+                return
+
+            kind, text = rendered
+            if settings.observer_mode:
+                self.bot.observer.record("set presence", kind=kind, text=text)
+                return
+
+            await self.bot.change_presence(activity=_activity(kind, text))
+        except Exception as e:
+            logger.exception("Error in presence loop: %s", e)
+
+    @presence_loop.before_loop
+    async def before_presence_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    def _upcoming_events(self, now: datetime) -> list[CalendarEvent]:
+        """Return public events in the next week from every event source."""
+        end = now + timedelta(days=7)
+        events = self.bot.calendar.get_events_between(now, end)
+        events += self.bot.schedules.get_events_between(now, end)
+        events += self.bot.submissions.get_events_between(now, end)
+        return [event for event in events if not is_private(event)]
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the presence cog."""
+    await bot.add_cog(PresenceCog(bot))
@@ -0,0 +1,271 @@
+"""Recap threads collecting the photos and links attendees share after events."""
+
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.permissions import (
+    MissingPermissionsError,
+    PublicChannelError,
+    check_channel_permissions,
+    is_public,
+)
+from ..services.calendar import CalendarEvent
+from ..services.governor import Priority
+from ..services.in_flight import drained
+from ..services.recaps import COLLECT_FOR, extract_links
+from ..services.schedules import is_private
+from .checklists import THREAD_NAME_LIMIT
+
+logger = logging.getLogger(__name__)
+
+# Events that ended this long ago or less get a recap thread; later ones are skipped,
+# e.g. after downtime, rather than prompting for photos of an event long over
+OPEN_WITHIN = timedelta(hours=1)
+
+# Images in the summary's gallery, as many as Discord shows side by side
+GALLERY_SIZE = 4
+
+# Longest message Discord allows
+MESSAGE_LIMIT = 2000
+
+
+class RecapsCog(commands.Cog):
+    """Opens a recap thread after each event of schedules with `recap` on.
+
+    The thread, reused by every occurrence of the schedule, asks attendees
+    for their screenshots, photos, and links. What's posted in the next
+    48 hours is compiled into one summary message with a gallery of the
+    photos, pinned in the thread.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.recap_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.recap_loop.cancel()
+
+    @tasks.loop(minutes=5)
+    @drained("recaps")
+    async def recap_loop(self) -> None:
+        """Open the recaps of events that just ended, and post the ones that are due."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("recaps", Priority.BACKGROUND)
+        now = datetime.now(ZoneInfo("UTC"))
+        try:
+            for event in self.bot.schedules.get_events_between(now - timedelta(days=1), now):
+                if not event.schedule or not event.schedule.recap:
+                    continue
+                if now - OPEN_WITHIN < event.end_time <= now and not self.bot.recaps.get(event.id):
+                    await self.open_recap(event)
+
+            for event_id in self.bot.recaps.due(now):
+                await self.post_summary(event_id)
+        except Exception as e:
+            logger.exception("Error in recap loop: %s", e)
+
+    @recap_loop.before_loop
+    async def before_recap_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Collect the photos, files, and links members share in an open recap."""
+        if message.author.bot or not isinstance(message.channel, discord.Thread):
+            return
+        if not self.bot.leader.is_leader:
+            return
+
+        event_id = self.bot.recaps.collecting(message.channel.id, datetime.now(ZoneInfo("UTC")))
+        if not event_id:
+            return
+
+        images = sum(1 for attachment in message.attachments if _is_image(attachment))
+        files = len(message.attachments) - images
+        links = extract_links(message.content)
+        if images or files or links:
+            self.bot.recaps.add(event_id, message.author.id, message.id, images, files, links)
+
+    @commands.Cog.listener()
+    async def on_raw_message_delete(self, payload: discord.RawMessageDeleteEvent) -> None:
+        """Leave a deleted message out of its recap."""
+        event_id = self.bot.recaps.collecting(payload.channel_id, datetime.now(ZoneInfo("UTC")))
+        if event_id:
+            self.bot.recaps.remove(event_id, payload.message_id)
+
+    async def open_recap(self, event: CalendarEvent) -> None:
+        """Ask attendees of an event that just ended to share their photos and links."""
+        if settings.observer_mode:
+            self.bot.observer.record("open recap", event=event.id, schedule=event.schedule.name)
+            return
+
+        thread = await self._thread(event)
+        if not thread:
+            return
+
+        closes = int((event.end_time + COLLECT_FOR).timestamp())
+        message = await self.bot.messenger.send(
+            thread,
+            f"📸 Thanks for joining **{event.name}**! Drop your screenshots, photos, and links "
+            f"here, and they'll be compiled into a recap <t:{closes}:R>.",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+        if not message:
+            return
+        self.bot.recaps.open(event.id, event.name, thread.id, message.id, event.end_time)
+        logger.info("Opened the recap of %s", event.id)
+
+    async def _thread(self, event: CalendarEvent) -> discord.Thread | None:
+        """Return the recap thread of an event's schedule, opening it the first time."""
+        schedule = event.schedule
+        thread_id = self.bot.recaps.thread(schedule.name)
+        if thread_id:
+            try:
+                return await self._fetch_thread(thread_id)
+            except discord.NotFound:
+                logger.warning("Recap thread of %s is gone, opening a new one", schedule.name)
+            except discord.HTTPException as e:
+                logger.error("Failed to fetch the recap thread of %s: %s", schedule.name, e)
+                return None
+
+        guild = self.bot.get_guild(schedule.guild_id or settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=schedule.notify_channel)
+        if not channel:
+            logger.error("Recap channel not found: %s", schedule.notify_channel)
+            return None
+        if is_private(event) and is_public(channel):
+            error = PublicChannelError(channel)
+            logger.error("Not opening the recap thread of %s: %s", schedule.name, error)
+            return None
+
+        try:
+            check_channel_permissions(channel, "create_public_threads", "send_messages_in_threads")
+            thread = await channel.create_thread(
+                name=f"{schedule.name} recaps"[:THREAD_NAME_LIMIT],
+                type=discord.ChannelType.public_thread,
+                reason=f"Recaps of {schedule.name}",
+            )
+        except MissingPermissionsError as e:
+            logger.error("Can't open the recap thread of %s: %s", schedule.name, e)
+            return None
+        except discord.HTTPException as e:
+            logger.error("Failed to open the recap thread of %s: %s", schedule.name, e)
+            return None
+
+        self.bot.recaps.set_thread(schedule.name, thread.id)
+        return thread
+
+    async def _fetch_thread(self, thread_id: int) -> discord.Thread:
+        """Return a thread, fetching it if it's archived and so not cached.
+
+        Raises:
+            discord.HTTPException: If the thread can't be fetched.
+        """
+        thread = self.bot.get_channel(thread_id)
+        return thread or await self.bot.fetch_channel(thread_id)
+
+    async def post_summary(self, event_id: str) -> None:
+        """Compile what was shared in a recap into one message, and pin it."""
+        recap = self.bot.recaps.get(event_id)
+        self.bot.recaps.close(event_id)
+        if not recap["items"]:
+            logger.info("Nothing was shared in the recap of %s", event_id)
+            return
+
+        try:
+            thread = await self._fetch_thread(recap["thread_id"])
+        except discord.HTTPException as e:
+            logger.error("Failed to fetch the recap thread of %s: %s", recap["name"], e)
+            return
+
+        message = await self.bot.messenger.send(
+            thread,
+            _summary(recap, thread),
+            embeds=await self._gallery(recap, thread),
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+        if not message:
+            return
+        try:
+            await message.pin(reason=f"Recap of {recap['name']}")
+        except discord.HTTPException as e:
+            logger.warning("Failed to pin the recap of %s: %s", recap["name"], e)
+        logger.info("Posted the recap of %s", event_id)
+
+    async def _gallery(self, recap: dict, thread: discord.Thread) -> list[discord.Embed]:
+        """Return an embed per photo for the summary, up to `GALLERY_SIZE`.
+
+        The messages are fetched again, since attachment URLs expire. The
+        embeds share the prompt's link, which makes Discord show them as one
+        gallery.
+        """
+        link = thread.get_partial_message(recap["prompt_id"]).jump_url
+        embeds: list[discord.Embed] = []
+        for item in recap["items"]:
+            if len(embeds) >= GALLERY_SIZE:
+                break
+            if not item["images"]:
+                continue
+            try:
+                message = await thread.fetch_message(item["message_id"])
+            except discord.HTTPException:
+                continue
+            for attachment in message.attachments:
+                if _is_image(attachment) and len(embeds) < GALLERY_SIZE:
+                    embed = discord.Embed(title=f"Recap of {recap['name']}", url=link)
+                    embeds.append(embed.set_image(url=attachment.url))
+        return embeds
+
+
+def _is_image(attachment: discord.Attachment) -> bool:
+    """Check whether an attachment is a photo or screenshot."""
+    return (attachment.content_type or "").startswith("image/")
+
+
+def _count(count: int, noun: str) -> str:
+    """Return e.g. "1 photo" or "3 photos"."""
+    return f"{count} {noun}{'' if count == 1 else 's'}"
+
+
+def _summary(recap: dict, thread: discord.Thread) -> str:
+    """Return the text of a recap's summary: totals, then a line per shared message."""
+    items = recap["items"]
+    images = sum(item["images"] for item in items)
+    files = sum(item["files"] for item in items)
+    links = sum(len(item["links"]) for item in items)
+    members = len({item["user_id"] for item in items})
+    counts = ", ".join(
+        _count(count, noun)
+        for count, noun in ((images, "photo"), (files, "file"), (links, "link"))
+        if count
+    )
+    text = f"🖼️ **Recap of {recap['name']}**: {counts} from {_count(members, 'member')}"
+
+    for index, item in enumerate(items):
+        attached = ", ".join(
+            _count(count, noun)
+            for count, noun in ((item["images"], "photo"), (item["files"], "file"))
+            if count
+        )
+        parts = [f"<{link}>" for link in item["links"]]
+        if attached:
+            jump_url = thread.get_partial_message(item["message_id"]).jump_url
+            parts.insert(0, f"[{attached}]({jump_url})")
+        line = f"\n- <@{item['user_id']}>: {' · '.join(parts)}"
+        more = f"\n…and {len(items) - index} more above."
+        if len(text) + len(line) + len(more) > MESSAGE_LIMIT:
+            return text + more
+        text += line
+    return text
@@ -0,0 +1,92 @@
+"""GitHub release notes posted with a discussion thread."""
+
+import logging
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.changelog import render_changelog
+from ..helpers.chunking import split_message
+from ..helpers.embeds import DESCRIPTION_LIMIT, TITLE_LIMIT, EmbedBuilder
+from ..helpers.permissions import MissingPermissionsError, check_channel_permissions
+from ..services.governor import Priority
+
+logger = logging.getLogger(__name__)
+
+# Longest name Discord allows for a thread
+THREAD_NAME_LIMIT = 100
+
+
+class ReleasesCog(commands.Cog):
+    """Posts releases of the configured GitHub repositories.
+
+    The API server receives GitHub's release webhooks and dispatches them here.
+    Each release is posted as an embed with the start of its changelog, and a
+    thread is opened on it for discussion, holding the rest of the changelog.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.Cog.listener()
+    async def on_github_release(self, repo: str, release: dict) -> None:
+        """Post a release published in a configured repository."""
+        channels = {
+            name.lower(): channel for name, channel in settings.github_release_channels.items()
+        }
+        channel_name = channels.get(repo.lower())
+        if not channel_name:
+            return
+
+        self.bot.governor.tag("releases", Priority.BACKGROUND)
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=channel_name)
+        if not channel:
+            logger.error("Release channel not found: %s", channel_name)
+            return
+
+        tag = release.get("tag_name", "")
+        title = f"🚀 {repo} {release.get('name') or tag}"[:TITLE_LIMIT]
+        changelog = render_changelog(release.get("body") or "", repo)
+        parts = split_message(changelog, DESCRIPTION_LIMIT)
+        color = discord.Color.orange() if release.get("prerelease") else discord.Color.green()
+        builder = (
+            EmbedBuilder()
+            .set_title(title, url=release.get("html_url"))
+            .set_description(parts[0] if parts else "No release notes.")
+            .set_color(color)
+        )
+        if author := release.get("author"):
+            builder.set_footer(f"Released by {author['login']}")
+
+        message = await self.bot.messenger.send(
+            channel, embed=builder.build(), allowed_mentions=discord.AllowedMentions.none()
+        )
+        if not message:
+            return
+        logger.info("Posted release %s of %s", tag, repo)
+
+        try:
+            check_channel_permissions(channel, "create_public_threads", "send_messages_in_threads")
+            thread = await message.create_thread(
+                name=f"{repo} {tag}"[:THREAD_NAME_LIMIT], reason=f"Release {tag} of {repo}"
+            )
+        except MissingPermissionsError as e:
+            logger.error("Can't open a thread for release %s of %s: %s", tag, repo, e)
+            return
+        except discord.HTTPException as e:
+            logger.error("Failed to open a thread for release %s of %s: %s", tag, repo, e)
+            return
+
+        # The embed holds the first part, the thread the rest in message-sized pieces
+        for part in parts[1:]:
+            for piece in split_message(part):
+                await self.bot.messenger.send(
+                    thread, piece, allowed_mentions=discord.AllowedMentions.none()
+                )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the releases cog."""
+    await bot.add_cog(ReleasesCog(bot))
@@ -0,0 +1,164 @@
+"""Personal reminders and user timezone preferences."""
+
+import logging
+import uuid
+from datetime import datetime
+from typing import Annotated, Literal
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.converters import Timezone
+from ..helpers.timeparse import parse_time_prefix
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+TIMEZONES = "timezones"
+# Reminder ID -> {"user_id", "channel_id": where to remind, or None to DM, "due", "message"}
+REMINDERS = "reminders"
+
+
+def user_timezone(bot: commands.Bot, user_id: int) -> ZoneInfo:
+    """Return a user's stored timezone, or the default timezone."""
+    name = bot.store.get(TIMEZONES, str(user_id), settings.default_timezone)
+    return ZoneInfo(name)
+
+
+def add_reminder(
+    bot: commands.Bot, user_id: int, channel_id: int | None, due: datetime, message: str
+) -> None:
+    """Remind a user at `due` in a channel, or by DM without one."""
+    bot.store.set(
+        REMINDERS,
+        uuid.uuid4().hex,
+        {"user_id": user_id, "channel_id": channel_id, "due": due.isoformat(), "message": message},
+    )
+
+
+class RemindersCog(commands.Cog):
+    """Lets members set their timezone, schedule personal reminders, and turn event DMs off."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.delivery_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.delivery_loop.cancel()
+
+    @commands.command(name="timezone")
+    async def timezone(
+        self, ctx: commands.Context, zone: Annotated[ZoneInfo | None, Timezone] = None
+    ) -> None:
+        """Show or set your timezone.
+
+        Usage: !timezone [name]
+        Example: !timezone America/Lima
+        """
+        if zone is None:
+            current = user_timezone(self.bot, ctx.author.id)
+            await ctx.send(f"Your timezone is `{current.key}`.")
+            return
+
+        self.bot.store.set(TIMEZONES, str(ctx.author.id), zone.key)
+        await ctx.send(f"Timezone set to `{zone.key}`.")
+
+    @commands.hybrid_command(name="eventdms")
+    async def eventdms(
+        self, ctx: commands.Context, setting: Literal["on", "off"] | None = None
+    ) -> None:
+        """Turn on or off the DMs reminding you of events you're interested in or going to.
+
+        Usage: !eventdms [on|off]
+        Example: !eventdms off
+        """
+        if setting is None:
+            opted_out = self.bot.dm_reminders.opted_out(ctx.author.id)
+            state = "off" if opted_out else "on"
+            await ctx.send(f"Event reminder DMs are {state} for you.", ephemeral=True)
+            return
+
+        self.bot.dm_reminders.set_opted_out(ctx.author.id, setting == "off")
+        if setting == "off":
+            await ctx.send("Okay, I won't DM you about events anymore.", ephemeral=True)
+        else:
+            minutes = settings.dm_reminder_minutes
+            await ctx.send(
+                f"Okay, I'll DM you {minutes} minutes before events you're interested in "
+                "or going to.",
+                ephemeral=True,
+            )
+
+    @commands.command(name="remindme")
+    async def remindme(self, ctx: commands.Context, *, text: str) -> None:
+        """Remind yourself about something.
+
+        Usage: !remindme <when> <message>
+        Example: !remindme in 45 min check the oven
+        Example: !remindme tomorrow 7pm join the study session
+        """
+        now = datetime.now(user_timezone(self.bot, ctx.author.id))
+        try:
+            due, message = parse_time_prefix(text, now)
+        except ValueError:
+            await ctx.send("I couldn't understand when. Try `in 45 min` or `tomorrow 7pm`.")
+            return
+
+        if due <= now:
+            await ctx.send("That time is in the past.")
+            return
+
+        add_reminder(self.bot, ctx.author.id, ctx.channel.id, due, message or "Reminder!")
+        await ctx.send(f"Okay, I'll remind you <t:{int(due.timestamp())}:R>.")
+
+    @tasks.loop(seconds=30)
+    @drained("personal reminders")
+    async def delivery_loop(self) -> None:
+        """Deliver reminders that are due."""
+        if not self.bot.leader.is_leader:
+            return
+        try:
+            now = datetime.now(ZoneInfo("UTC"))
+            for reminder_id, reminder in self.bot.store.items(REMINDERS).items():
+                if datetime.fromisoformat(reminder["due"]) <= now:
+                    await self._deliver(reminder)
+                    self.bot.store.delete(REMINDERS, reminder_id)
+        except Exception as e:
+            logger.exception("Error in reminder delivery loop: %s", e)
+
+    @delivery_loop.before_loop
+    async def before_delivery_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def _deliver(self, reminder: dict) -> None:
+        """Send a reminder in the channel where it was requested, or by DM."""
+        try:
+            if reminder["channel_id"] is None:
+                user_id = reminder["user_id"]
+                user = self.bot.get_user(user_id) or await self.bot.fetch_user(user_id)
+                await self.bot.messenger.send(user, f"⏰ {reminder['message']}")
+                return
+
+            channel = self.bot.get_channel(reminder["channel_id"])
+            if not channel:
+                logger.warning("Reminder channel not found: %d", reminder["channel_id"])
+                return
+            await self.bot.messenger.send(
+                channel,
+                f"<@{reminder['user_id']}> ⏰ {reminder['message']}",
+                allowed_mentions=discord.AllowedMentions(users=True),
+            )
+        except discord.HTTPException as e:
+            logger.error("Failed to deliver reminder to %d: %s", reminder["user_id"], e)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the reminders cog."""
+    await bot.add_cog(RemindersCog(bot))
@@ -0,0 +1,137 @@
+"""Temporary role grants, revoked by the scheduler when they expire."""
+
+import logging
+from datetime import datetime, timedelta
+from typing import Annotated
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.converters import Duration
+from ..helpers.permissions import check_can_manage_roles
+
+logger = logging.getLogger(__name__)
+
+REASON = "Temporary role grant"
+
+
+class GrantFlags(commands.FlagConverter, prefix="--", delimiter=" "):
+    """Options for `!role grant`."""
+
+    duration: Annotated[timedelta, Duration] = commands.flag(
+        name="for", description="How long to keep the role, e.g. 7d"
+    )
+
+
+class RolesCog(commands.Cog):
+    """Grants roles for a limited time, e.g. to event speakers or trial moderators.
+
+    Grants are kept in the store and the scheduler loop revokes them once they
+    expire, so they survive restarts.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.hybrid_group(name="role")
+    @commands.guild_only()
+    async def role(self, ctx: commands.Context) -> None:
+        """Grant roles temporarily.
+
+        Usage: !role grant @user <role> --for 7d | !role revoke @user <role> | !role grants
+        """
+        await ctx.send_help(ctx.command)
+
+    @role.command(name="grant")
+    @commands.has_permissions(manage_roles=True)
+    async def role_grant(
+        self,
+        ctx: commands.Context,
+        member: discord.Member,
+        role: discord.Role,
+        *,
+        flags: GrantFlags,
+    ) -> None:
+        """Give a member a role that's revoked automatically (requires Manage Roles).
+
+        Usage: !role grant @user <role> --for <duration>
+        Example: !role grant @ana Speaker --for 7d
+        """
+        if member.pending:
+            await ctx.send(
+                f"{member.mention} hasn't accepted the rules yet, so they can't be given roles.",
+                allowed_mentions=discord.AllowedMentions.none(),
+            )
+            return
+        if not self._can_assign(ctx.author, role):
+            await ctx.send(f"You can only grant roles ranked below your own, not {role.mention}.")
+            return
+        check_can_manage_roles(ctx.guild, role)
+
+        if role not in member.roles:
+            await member.add_roles(role, reason=f"{REASON} by {ctx.author}")
+        expires = datetime.now(ZoneInfo("UTC")) + flags.duration
+        self.bot.role_grants.grant(member.id, role.id, expires, ctx.author.id, ctx.guild.id)
+
+        logger.info("Granted %s to %s until %s by %s", role.name, member, expires, ctx.author)
+        await ctx.send(
+            f"Gave {member.mention} {role.mention} until <t:{int(expires.timestamp())}:f>.",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+    @role.command(name="revoke")
+    @commands.has_permissions(manage_roles=True)
+    async def role_revoke(
+        self, ctx: commands.Context, member: discord.Member, role: discord.Role
+    ) -> None:
+        """Take back a temporary role before it expires (requires Manage Roles).
+
+        Usage: !role revoke @user <role>
+        """
+        if not self._can_assign(ctx.author, role):
+            await ctx.send(f"You can only revoke roles ranked below your own, not {role.mention}.")
+            return
+
+        if not self.bot.role_grants.remove(member.id, role.id):
+            await ctx.send(
+                f"{member.mention} has no temporary {role.mention} grant.",
+                allowed_mentions=discord.AllowedMentions.none(),
+            )
+            return
+
+        check_can_manage_roles(ctx.guild, role)
+        await member.remove_roles(role, reason=f"{REASON} revoked by {ctx.author}")
+        logger.info("Revoked %s from %s by %s", role.name, member, ctx.author)
+        await ctx.send(
+            f"Took {role.mention} back from {member.mention}.",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+    @role.command(name="grants")
+    @commands.has_permissions(manage_roles=True)
+    async def role_grants(self, ctx: commands.Context) -> None:
+        """List this server's temporary roles and when they expire (requires Manage Roles).
+
+        Usage: !role grants
+        """
+        lines = [
+            f"<@{grant.member_id}>: <@&{grant.role_id}> until "
+            f"<t:{int(grant.expires.timestamp())}:f>"
+            for grant in self.bot.role_grants.all()
+            if (grant.guild_id or settings.discord_guild_id) == ctx.guild.id
+        ]
+        await ctx.send(
+            "\n".join(lines) or "No temporary roles.",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+    def _can_assign(self, author: discord.Member, role: discord.Role) -> bool:
+        """Whether a member may hand out a role, which must rank below their own."""
+        return author == author.guild.owner or role < author.top_role
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the roles cog."""
+    await bot.add_cog(RolesCog(bot))
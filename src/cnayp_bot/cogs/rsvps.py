@@ -0,0 +1,154 @@
+"""RSVP buttons on event announcements, and the waitlists of events with limited seats."""
+
+import logging
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..services.components import ComponentRouter
+
+logger = logging.getLogger(__name__)
+
+# Component handler for the RSVP buttons
+RSVP = "rsvp"
+
+
+def rsvp_view(
+    components: ComponentRouter,
+    capacity: int | None,
+    going: int = 0,
+    waitlisted: int = 0,
+    maybe: int = 0,
+    declined: int = 0,
+) -> discord.ui.View:
+    """Build the RSVP buttons for an announcement, showing the answers so far."""
+    view = discord.ui.View(timeout=None)
+    if capacity is None:
+        label, emoji = f"Going ({going})", "✅"
+    elif going < capacity:
+        label, emoji = f"RSVP ({going}/{capacity})", "🎟️"
+    else:
+        label, emoji = f"Join waitlist ({waitlisted} waiting)", "🎟️"
+    view.add_item(
+        components.button(RSVP, "join", label=label, emoji=emoji, style=discord.ButtonStyle.success)
+    )
+    view.add_item(components.button(RSVP, "maybe", label=f"Maybe ({maybe})", emoji="🤔"))
+    view.add_item(
+        components.button(RSVP, "declined", label=f"Can't go ({declined})", emoji="❌")
+    )
+    return view
+
+
+def current_rsvp_view(components: ComponentRouter, rsvps: dict) -> discord.ui.View:
+    """Build the RSVP buttons of an event's announcement from its answers."""
+    return rsvp_view(
+        components,
+        rsvps["capacity"],
+        len(rsvps["going"]),
+        len(rsvps["waitlist"]),
+        len(rsvps["maybe"]),
+        len(rsvps["declined"]),
+    )
+
+
+def join_message(name: str, capacity: int | None, place: int) -> str:
+    """Tell a member who RSVPed going whether they got a seat or a waitlist place."""
+    if place == 0 and capacity is None:
+        return f"You're going to **{name}**. See you there!"
+    if place == 0:
+        return f"You have a seat at **{name}**. See you there!"
+    return (
+        f"**{name}** is full. You're #{place} on the waitlist, and you'll get a DM "
+        "if a seat opens up."
+    )
+
+
+class RsvpCog(commands.Cog):
+    """Handles RSVPs on event announcements.
+
+    Members answer going, maybe, or can't go. For events with a capacity, once
+    every seat is taken members join a waitlist, and when someone with a seat
+    changes their answer, the first member waiting gets it and is told by DM.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(RSVP, self.rsvp)
+
+    async def rsvp(self, interaction: discord.Interaction, payload: str) -> None:
+        """Record an answer to the event announced in the button's message.
+
+        Announcements posted before maybe and can't-go answers have a "leave"
+        button instead, which cancels the RSVP.
+        """
+        event_id = interaction.message and self.bot.rsvps.find(interaction.message.id)
+        if not event_id:
+            await interaction.response.send_message(
+                "RSVPs for this event are closed.", ephemeral=True
+            )
+            return
+
+        rsvps = self.bot.rsvps.get(event_id)
+        name = rsvps["name"]
+        member_id = interaction.user.id
+        promoted = None
+        if payload == "join":
+            place = self.bot.rsvps.join(event_id, member_id)
+            message = join_message(name, rsvps["capacity"], place)
+        elif payload in ("maybe", "declined"):
+            promoted = self.bot.rsvps.answer(event_id, member_id, payload)
+            if payload == "maybe":
+                message = f"Marked you as maybe for **{name}**."
+            else:
+                message = f"Got it, you can't make **{name}**."
+        elif member_id in rsvps["going"] or member_id in rsvps["waitlist"]:
+            promoted = self.bot.rsvps.leave(event_id, member_id)
+            message = f"You're no longer going to **{name}**."
+        else:
+            message = f"You haven't RSVPed to **{name}**."
+
+        await interaction.response.edit_message(
+            view=current_rsvp_view(self.bot.components, self.bot.rsvps.get(event_id))
+        )
+        await interaction.followup.send(message, ephemeral=True)
+
+        if promoted:
+            await self.notify_promoted(promoted, name, interaction.message.jump_url)
+
+    async def refresh_announcement(self, event_id: str) -> None:
+        """Update the answer counts on an event's announcement after an RSVP made elsewhere."""
+        rsvps = self.bot.rsvps.get(event_id)
+        channel = rsvps and rsvps.get("channel_id") and self.bot.get_channel(rsvps["channel_id"])
+        if not channel:
+            return
+        if settings.observer_mode:
+            self.bot.observer.record("edit message", message=rsvps["message_id"], view="rsvp")
+            return
+
+        try:
+            message = channel.get_partial_message(rsvps["message_id"])
+            await message.edit(view=current_rsvp_view(self.bot.components, rsvps))
+        except discord.HTTPException as e:
+            logger.warning("Failed to update the RSVP counts of %s: %s", rsvps["name"], e)
+
+    async def notify_promoted(self, member_id: int, name: str, link: str) -> None:
+        """DM a waitlisted member that they got a seat."""
+        logger.info("Promoted %d from the waitlist of %s", member_id, name)
+        try:
+            user = self.bot.get_user(member_id) or await self.bot.fetch_user(member_id)
+            await self.bot.messenger.send(
+                user, f"🎟️ A seat opened up at **{name}**, and it's yours! {link}"
+            )
+        except discord.Forbidden:
+            logger.info("User %d doesn't accept DMs, not told about their seat", member_id)
+        except discord.HTTPException as e:
+            logger.error("Failed to tell %d about their seat: %s", member_id, e)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the RSVP cog."""
+    await bot.add_cog(RsvpCog(bot))
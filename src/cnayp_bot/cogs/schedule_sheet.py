@@ -0,0 +1,122 @@
+"""Schedules synced from a Google Sheet kept by organizers."""
+
+import logging
+
+import aiohttp
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..services.governor import Priority
+from ..services.schedule_sheet import (
+    describe_changes,
+    fetch_sheet,
+    merge_schedules,
+    parse_schedule_sheet,
+)
+
+logger = logging.getLogger(__name__)
+
+
+class ScheduleSheetCog(commands.Cog):
+    """Syncs the schedules file with the organizers' sheet on an interval.
+
+    A sheet with errors isn't applied at all, so a typo can't remove a
+    schedule; the errors are posted in the staff channel once, until they
+    change. Applied changes are summarized there too. `!schedules sync` in
+    the schedules cog runs a sync right away.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.schedule_sheet_url:
+            logger.info("Schedule sheet not configured, schedule import disabled")
+            return
+
+        self.sync_loop.change_interval(minutes=settings.schedule_sheet_minutes)
+        self.sync_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.sync_loop.cancel()
+
+    @tasks.loop(minutes=15)
+    async def sync_loop(self) -> None:
+        """Import the sheet into the schedules file."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("schedule_sheet", Priority.BACKGROUND)
+        try:
+            await self.sync()
+        except aiohttp.ClientError as e:
+            logger.warning("Failed to download the schedule sheet: %s", e)
+        except Exception as e:
+            logger.exception("Error in schedule sheet loop: %s", e)
+
+    @sync_loop.before_loop
+    async def before_sync_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+        logger.info("Schedule sheet sync started every %d minutes", settings.schedule_sheet_minutes)
+
+    async def sync(self) -> tuple[list[str], list[str]]:
+        """Download the sheet and apply it unless it has errors.
+
+        Returns:
+            The changes applied and the sheet's errors.
+
+        Raises:
+            aiohttp.ClientError: If the sheet can't be downloaded.
+        """
+        sheet = parse_schedule_sheet(await fetch_sheet(settings.schedule_sheet_url))
+        if sheet.errors:
+            if self.bot.imported_schedules.errors_changed(sheet.errors):
+                logger.warning("Schedule sheet has %d errors", len(sheet.errors))
+                await self._post(
+                    "⚠️ **The schedule sheet has errors, so it wasn't imported:**",
+                    [f"- {error}" for error in sheet.errors],
+                )
+            return [], sheet.errors
+        self.bot.imported_schedules.errors_changed([])
+
+        current = self.bot.schedules.config
+        config = merge_schedules(current, sheet, self.bot.imported_schedules.names)
+        changes = describe_changes(current.schedules, config.schedules)
+        names = {schedule.name.lower() for schedule in sheet.schedules}
+        self.bot.imported_schedules.set_names(names)
+        if not changes:
+            return [], []
+
+        self.bot.schedules.replace(config, "schedule sheet sync")
+        logger.info("Imported %d schedule changes from the sheet", len(changes))
+        await self._post("📋 **Schedules updated from the sheet:**", changes)
+        return changes, []
+
+    async def _post(self, title: str, lines: list[str]) -> None:
+        """Post a summary in the staff channel, or the ops channel without one."""
+        name = (
+            settings.staff_channel
+            or settings.discord_ops_channel
+            or settings.discord_errors_channel
+        )
+        if not name:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Staff channel not found: %s", name)
+            return
+
+        await self.bot.messenger.send_parts(
+            channel, "\n".join([title, *lines]), allowed_mentions=discord.AllowedMentions.none()
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the schedule sheet cog."""
+    await bot.add_cog(ScheduleSheetCog(bot))
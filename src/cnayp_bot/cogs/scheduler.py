@@ -1,18 +1,308 @@
-"""Scheduler cog for managing Discord events from Google Calendar."""
+"""Scheduler cog for managing Discord events from Google Calendar and schedules."""
 
 import logging
+from collections.abc import Awaitable, Callable
 from datetime import datetime, timedelta
+from functools import partial
 from zoneinfo import ZoneInfo
 
 import discord
 from discord.ext import commands, tasks
 
 from ..config import settings
+from ..helpers.discord_enums import EventStatus
+from ..helpers.embeds import DESCRIPTION_LIMIT, FIELD_VALUE_LIMIT, EmbedBuilder
+from ..helpers.permissions import (
+    MissingPermissionsError,
+    PublicChannelError,
+    check_can_manage_events,
+    check_can_manage_roles,
+    check_channel_permissions,
+    check_private_channel,
+    is_public,
+)
+from ..helpers.sanitize import (
+    EVENT_DESCRIPTION_LIMIT,
+    EVENT_NAME_LIMIT,
+    sanitize_template,
+    sanitize_text,
+)
+from ..helpers.timezones import reference_times
+from ..models import EventSubmission, ScheduleMirror
+from ..scheduling import (
+    LOOKAHEAD_HOURS,
+    dm_reminder_due,
+    followup_stale,
+    followup_time,
+    has_started,
+    held_reminder_batches,
+    host_check_due,
+    in_quiet_hours,
+    minutes_until,
+    next_status,
+    overlaps,
+    reminder_batches,
+    retry_delay,
+    should_create_discord_event,
+)
 from ..services.calendar import CalendarEvent, CalendarService
+from ..services.cover_images import CoverImageError, data_uri
+from ..services.discord_api import route
+from ..services.experiments import is_experiment
+from ..services.governor import Priority
+from ..services.guild_cache import ChannelDirectory
+from ..services.in_flight import drained
+from ..services.meetings import MeetingError
+from ..services.partners import partners_of
+from ..services.retry_queue import RetryQueue, is_transient, new_nonce
+from ..services.schedules import is_private, recurrence_pattern, recurrence_rule
+from ..services.sponsors import sponsor_line
+from ..services.submissions import is_submission
 from ..services.webhook import WebhookServer
+from .reminders import REMINDERS, add_reminder
+from .rsvps import join_message, rsvp_view
 
 logger = logging.getLogger(__name__)
 
+# Calendar event ID, plus "@guild ID" for mirrors ->
+# {"id": Discord scheduled event ID, "status": ..., "end": ..., "guild": guild ID}
+DISCORD_EVENTS = "discord_events"
+
+# How long finished events are remembered, so they're never created twice
+DISCORD_EVENT_RETENTION = timedelta(days=1)
+
+# Tracking key -> {"attempts": ..., "first": ..., "retry_at": ..., "end": ..., "escalated": ...}
+CREATE_FAILURES = "discord_event_failures"
+
+# Schedule name, plus "@guild ID" for mirrors -> {"id": recurring Discord event ID, "pattern": ...}
+RECURRING_EVENTS = "discord_recurring_events"
+
+# Status of occurrences of a recurring Discord event, which Discord moves along by itself
+RECURRING = "recurring"
+
+# Calendar event ID -> {"due": ..., "channel_id": ..., "content": ...}, posted after the event
+FOLLOWUPS = "followups"
+
+# Failed notifications waiting to be sent again; more are given up right away
+RETRY_QUEUE_SIZE = 100
+
+# Component handler for the buttons on reminders
+REMINDER_ACTIONS = "reminder"
+
+# Reminder message ID -> {"events": [calendar event IDs], "end": ...}, for its buttons
+REMINDER_MESSAGES = "reminder_messages"
+
+# How much later "Remind me again" DMs a member
+SNOOZE = timedelta(minutes=10)
+
+# Calendar event ID -> {"start": ...}, for events whose reminder was due during quiet hours
+HELD_REMINDERS = "held_reminders"
+
+# "event ID:minutes" -> {"end": ...}, for reminders already sent, or held back
+SENT_REMINDERS = "sent_reminders"
+
+# Calendar event ID -> {"end": ...}, for events whose start was announced
+START_NOTIFICATIONS = "start_notifications"
+
+# Calendar event ID -> {"end": ...}, for events checked for a host in the call
+HOST_CHECKS = "host_checks"
+
+
+def _voice_channel(event: CalendarEvent) -> str:
+    """Return the voice channel name an event takes place in."""
+    if event.schedule:
+        return event.schedule.voice_channel
+    return event.voice_channel or settings.discord_voice_channel
+
+
+def voice_room(event: CalendarEvent) -> tuple[int, str]:
+    """Return the guild and voice channel name an event takes place in."""
+    return _guild_id(event), _voice_channel(event)
+
+
+def _notify_channel(event: CalendarEvent) -> str:
+    """Return the text channel name an event is announced in."""
+    return event.schedule.notify_channel if event.schedule else settings.discord_notify_channel
+
+
+def _guild_id(event: CalendarEvent) -> int:
+    """Return the guild an event is created in, the primary one unless its schedule says."""
+    if event.schedule and event.schedule.guild_id:
+        return event.schedule.guild_id
+    return settings.discord_guild_id
+
+
+def _held_in(event: CalendarEvent, guild_id: int) -> bool:
+    """Check whether an event is created in a guild, as its own or as a mirror."""
+    mirrors = event.schedule.mirrors if event.schedule else []
+    return _guild_id(event) == guild_id or any(mirror.guild_id == guild_id for mirror in mirrors)
+
+
+def _event_source(event: CalendarEvent) -> str:
+    """Describe where an event comes from, for the audit log."""
+    if is_submission(event):
+        return "from an approved submission"
+    if event.schedule:
+        return f"from the `{event.schedule.name}` schedule"
+    return "from Google Calendar"
+
+
+def _audience_role(event: CalendarEvent) -> str:
+    """Return the role a private event is announced to, or "" for a public event."""
+    return event.schedule.audience_role if is_private(event) else ""
+
+
+def _silent(event: CalendarEvent, kind: str) -> bool:
+    """Check whether a notice about an event is sent without push notifications."""
+    if event.schedule and event.schedule.silent is not None:
+        return kind in event.schedule.silent
+    return kind in settings.silent_messages
+
+
+def _with_meeting(description: str, meeting_url: str | None) -> str:
+    """Add a hybrid event's join link to its Discord event description."""
+    if not meeting_url:
+        return description
+    line = f"\n\nJoin online: {meeting_url}"
+    return description[: EVENT_DESCRIPTION_LIMIT - len(line)] + line
+
+
+def _reminder_ping(event: CalendarEvent) -> str | int:
+    """Return who an event's reminders ping, its schedule's choice or `REMINDER_PING`."""
+    if event.schedule and event.schedule.reminder_ping is not None:
+        return event.schedule.reminder_ping
+    return settings.reminder_ping
+
+
+def _in_quiet_hours(event: CalendarEvent, now: datetime) -> bool:
+    """Check whether it's quiet hours in an event's timezone."""
+    return in_quiet_hours(
+        now.astimezone(ZoneInfo(event.timezone)),
+        settings.quiet_hours_start,
+        settings.quiet_hours_end,
+    )
+
+
+def _reminder_channel(event: CalendarEvent) -> tuple[int, str, str, str | int, str]:
+    """Return the guild and name of the channel an event's reminders are sent in, and who to.
+
+    Reminders are combined per key, so events pinging differently get their
+    own, and so does an event with its own reminder template.
+    """
+    template = event.id if event.schedule and event.schedule.reminder_template else ""
+    return (
+        _guild_id(event),
+        _notify_channel(event),
+        _audience_role(event),
+        _reminder_ping(event),
+        template,
+    )
+
+
+def _ping(
+    channel: discord.TextChannel, mode: str | int, audience_role: str = ""
+) -> tuple[str | None, discord.AllowedMentions]:
+    """Return the mention a notification starts with, and the mentions it may ping.
+
+    Only the chosen mention pings, so names in event descriptions never do.
+    Private events ping their audience role instead of @everyone, @here, or
+    another role. `mode` can also be the ID of the role to ping.
+    """
+    if audience_role and mode != "none":
+        mode = "role"
+    if mode in ("everyone", "here"):
+        return f"@{mode}", discord.AllowedMentions(everyone=True, roles=False, users=False)
+    role = None
+    if isinstance(mode, int):
+        role = channel.guild.get_role(mode)
+    elif mode == "role":
+        name = audience_role or settings.notification_role
+        role = discord.utils.get(channel.guild.roles, name=name)
+    if role:
+        return role.mention, discord.AllowedMentions(everyone=False, roles=[role], users=False)
+    return None, discord.AllowedMentions.none()
+
+
+def _mention_list(member_ids: list[int]) -> str:
+    """Mention members in one embed field, ending with how many didn't fit."""
+    mentions = []
+    for index, member_id in enumerate(member_ids):
+        rest = f"and {len(member_ids) - index} more"
+        if len(" ".join([*mentions, f"<@{member_id}>", rest])) > FIELD_VALUE_LIMIT:
+            return " ".join([*mentions, rest])
+        mentions.append(f"<@{member_id}>")
+    return " ".join(mentions)
+
+
+def _template_fields(
+    event: CalendarEvent,
+    name: str,
+    description: str,
+    voice_channel_id: int | None,
+    event_url: str,
+    meeting_url: str | None,
+) -> dict[str, str | int]:
+    """Return what the placeholders of announcement and follow-up templates are filled with."""
+    start = int(event.start_time.timestamp())
+    return {
+        "name": name,
+        "description": description,
+        "when": f"<t:{start}:F>",
+        "relative": f"<t:{start}:R>",
+        "timezone": event.timezone,
+        "duration": event.duration_minutes,
+        "where": f"<#{voice_channel_id}>",
+        "link": event_url,
+        "meeting": meeting_url or "",
+        "local_times": reference_times(
+            event.start_time, settings.reference_timezones, ZoneInfo(event.timezone)
+        ),
+    }
+
+
+def _announcement(fields: dict[str, str | int], template: str | None) -> str:
+    """Return an event's announcement, from its template if it has one."""
+    if template:
+        return sanitize_template(template).format(**fields)
+
+    online = f"**Online:** {fields['meeting']}\n" if fields["meeting"] else ""
+    local_times = f"**Local times:** {fields['local_times']}\n" if fields["local_times"] else ""
+    return (
+        f"================\n"
+        f"**New Event Alert!**\n"
+        f"**{fields['name']}**\n"
+        f"{fields['description']}\n"
+        f"**When:** {fields['when']} ({fields['relative']})\n"
+        f"**Timezone:** {fields['timezone']}\n"
+        f"{local_times}"
+        f"**Duration:** {fields['duration']} minutes\n"
+        f"**Where:** {fields['where']}\n"
+        f"{online}\n"
+        f"See you there!👇\n"
+        f"{fields['link']}"
+    )
+
+
+def _time_text(minutes: int) -> str:
+    """Return how long before an event a reminder is sent, e.g. "1 hour"."""
+    if minutes >= 60:
+        hours = minutes // 60
+        return "1 hour" if hours == 1 else f"{hours} hours"
+    return f"{minutes} minutes"
+
+
+def _log_fields(event: CalendarEvent, guild_id: int | None = None) -> dict:
+    """Return the structured log fields of an event, in a guild if given."""
+    fields = {"event_id": event.id, "guild_id": guild_id}
+    if event.schedule:
+        fields["schedule"] = event.schedule.name
+    return fields
+
+
+def _tracking_key(event_id: str, mirror: ScheduleMirror | None = None) -> str:
+    """Return the key an event's Discord scheduled event is tracked under in a guild."""
+    return f"{event_id}@{mirror.guild_id}" if mirror else event_id
+
 
 class SchedulerCog(commands.Cog):
     """Manages Discord events and notifications from Google Calendar."""
@@ -21,11 +311,11 @@ class SchedulerCog(commands.Cog):
         self.bot = bot
         self.calendar = CalendarService()
         self.webhook_server: WebhookServer | None = None
-        self.channel_cache: dict[str, int] = {}
-        self.created_discord_events: set[str] = set()  # calendar_event_id
-        self.sent_reminders: set[str] = set()  # "event_id:minutes"
-        self.sent_start_notifications: set[str] = set()  # event_id
+        self.channels = ChannelDirectory()
         self.known_events: dict[str, CalendarEvent] = {}  # event_id -> event
+        self.retries = RetryQueue(
+            RETRY_QUEUE_SIZE, timedelta(minutes=settings.notification_retry_minutes)
+        )
 
     async def cog_load(self) -> None:
         """Called when the cog is loaded."""
@@ -34,13 +324,16 @@ class SchedulerCog(commands.Cog):
         else:
             logger.info("Webhook disabled, using polling mode")
 
+        self.bot.components.register(REMINDER_ACTIONS, self.reminder_action)
         self.scheduler_loop.start()
         self.reminder_loop.start()
+        self.retry_loop.start()
 
     async def cog_unload(self) -> None:
         """Called when the cog is unloaded."""
         self.scheduler_loop.cancel()
         self.reminder_loop.cancel()
+        self.retry_loop.cancel()
 
         if self.webhook_server:
             self.calendar.stop_watch()
@@ -63,50 +356,193 @@ class SchedulerCog(commands.Cog):
 
     async def _on_calendar_change(self) -> None:
         """Handle calendar change notification from webhook."""
+        if not self.bot.leader.is_leader:
+            return
         logger.info("Calendar change detected via webhook")
         await self._process_calendar_changes()
 
     async def _process_calendar_changes(self) -> None:
         """Fetch and process calendar changes."""
-        events = self.calendar.get_changes()
+        events, cancelled_ids = self.calendar.get_changes()
 
         for event in events:
             self.known_events[event.id] = event
             await self.check_and_create_discord_event(event)
 
+        for event_id in cancelled_ids:
+            self.known_events.pop(event_id, None)
+            await self.cancel_discord_event(event_id)
+
     @tasks.loop(minutes=1)
+    @drained("scheduler")
     async def scheduler_loop(self) -> None:
         """Main scheduler loop for fetching events."""
+        if self.bot.maintenance.active:
+            logger.info("Maintenance mode, scheduler loop paused")
+            return
+        if not self.bot.leader.is_leader:
+            return
+
+        # Creating events goes ahead even while Discord's API is failing
+        self.bot.governor.tag("scheduler", Priority.CRITICAL)
         try:
             logger.info("Scheduler loop running")
+            scheduled = self.bot.schedules.get_upcoming_events(hours_ahead=LOOKAHEAD_HOURS)
+            events = [
+                *scheduled,
+                *self.bot.submissions.get_upcoming_events(hours_ahead=LOOKAHEAD_HOURS),
+            ]
             if settings.webhook_enabled and settings.webhook_url:
-                # In webhook mode, only renew watch if needed
+                # In webhook mode, calendar changes are pushed; only renew watch if needed
                 await self._check_watch_renewal()
             else:
-                # Polling mode: fetch events directly
-                events = self.calendar.get_upcoming_events(hours_ahead=48)
-                logger.info("Fetched %d upcoming events from calendar", len(events))
-                for event in events:
-                    logger.info("Event: %s at %s", event.name, event.start_time)
-                    self.known_events[event.id] = event
-                    await self.check_and_create_discord_event(event)
+                # Polling mode: fetch calendar events directly
+                calendar_events = self.calendar.get_upcoming_events(hours_ahead=LOOKAHEAD_HOURS)
+                await self._check_missing_events({event.id for event in calendar_events})
+                events += calendar_events
+            await self._drop_removed_occurrences({event.id for event in scheduled})
+            logger.info("Fetched %d upcoming events", len(events))
+            for event in events:
+                logger.info(
+                    "Event: %s at %s", event.name, event.start_time, extra=_log_fields(event)
+                )
+                self.known_events[event.id] = event
+                await self.reschedule_discord_event(event)
+                await self.check_and_create_discord_event(event)
+            self._forget_finished_discord_events()
+            self.bot.partners.forget_finished(datetime.now(ZoneInfo("UTC")))
+            self.bot.meetings.forget_finished(datetime.now(ZoneInfo("UTC")))
+            self.bot.submissions.forget_finished(datetime.now(ZoneInfo("UTC")))
+            self.bot.rsvps.forget_finished(datetime.now(ZoneInfo("UTC")))
+            self.bot.dm_reminders.forget_finished(datetime.now(ZoneInfo("UTC")))
+            for name in self.bot.schedules.consume_finished(datetime.now(ZoneInfo("UTC"))):
+                logger.info("One-off schedule %s is over, disabled it", name)
         except Exception as e:
             logger.exception("Error in scheduler loop: %s", e)
 
+        # Separate from events, so a calendar outage doesn't keep roles around
+        try:
+            await self._revoke_expired_roles()
+        except Exception as e:
+            logger.exception("Error revoking expired roles: %s", e)
+
     @tasks.loop(minutes=1)
+    @drained("reminders")
     async def reminder_loop(self) -> None:
         """Check for reminders and start notifications."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("scheduler")
         try:
             logger.info("Reminder loop running, checking %d events", len(self.known_events))
-            for event in self.known_events.values():
+            # Copy, since the scheduler loop and webhook can change events while this awaits
+            events = [
+                event
+                for event in list(self.known_events.values())
+                if self._discord_event_status(event.id) != EventStatus.CANCELED
+            ]
+            await self.send_due_reminders(events)
+            await self.send_dm_reminders(events)
+            await self.send_due_followups()
+            for event in events:
                 now = datetime.now(ZoneInfo("UTC"))
-                minutes_until = round((event.start_time - now).total_seconds() / 60)
-                logger.info("Event '%s': %d minutes until start", event.name, minutes_until)
-                await self.check_and_send_reminder(event)
+                until = minutes_until(event, now)
+                logger.info("Event '%s': %d minutes until start", event.name, until)
                 await self.check_and_send_start_notification(event)
+                await self.check_host_joined(event)
+                await self.update_discord_event_status(event)
+            await self.update_slowmode(events)
         except Exception as e:
             logger.exception("Error in reminder loop: %s", e)
 
+    @tasks.loop(seconds=10)
+    @drained("notification retries")
+    async def retry_loop(self) -> None:
+        """Send again the notifications whose earlier attempt failed, once they're due."""
+        if self.bot.maintenance.active or not self.retries:
+            return
+
+        self.bot.governor.tag("notification_retries")
+        for pending in self.retries.due(datetime.now(ZoneInfo("UTC"))):
+            try:
+                await pending.send()
+            except Exception as e:
+                if not is_transient(e):
+                    logger.exception("Failed to send %s: %s", pending.description, e)
+                elif not self.retries.retry_later(pending, datetime.now(ZoneInfo("UTC"))):
+                    await self._give_up_notification(pending.description, e)
+                continue
+            logger.info("Sent %s after %d attempts", pending.description, pending.attempts + 1)
+
+    async def _send_notification(
+        self, description: str, send: Callable[[str], Awaitable[discord.Message | None]]
+    ) -> discord.Message | None:
+        """Send a notification, queueing it to be sent again if Discord or the network fails.
+
+        `send` takes the message nonce, the same for every attempt, so a
+        notification Discord posted before the request timed out isn't posted
+        again.
+
+        Returns:
+            The message, or None if it wasn't sent right away.
+
+        Raises:
+            discord.HTTPException: If it failed in a way retrying won't fix.
+        """
+        attempt = partial(send, new_nonce())
+        try:
+            return await attempt()
+        except Exception as e:
+            if not is_transient(e):
+                raise
+            logger.warning("Failed to send %s, retrying: %s", description, e)
+            if not self.retries.add(description, attempt, datetime.now(ZoneInfo("UTC"))):
+                await self._give_up_notification(description, e)
+            return None
+
+    async def _give_up_notification(self, description: str, error: Exception) -> None:
+        """Tell ops that a notification couldn't be sent in its retry window."""
+        logger.error("Gave up sending %s: %s", description, error)
+        await self.bot.messenger.alert_ops(
+            f"⚠️ Gave up sending {description} after retrying for "
+            f"{settings.notification_retry_minutes} minutes: {error or type(error).__name__}"
+        )
+
+    async def _revoke_expired_roles(self) -> None:
+        """Take back temporary roles whose grant expired, in the guild they were granted in."""
+        for grant in self.bot.role_grants.expired(datetime.now(ZoneInfo("UTC"))):
+            guild = self.bot.get_guild(grant.guild_id or settings.discord_guild_id)
+            if not guild:
+                # Kept, in case the guild is only unavailable for now
+                continue
+
+            member = guild.get_member(grant.member_id)
+            role = guild.get_role(grant.role_id)
+            if member is None or role is None or role not in member.roles:
+                # Left, deleted, or already removed by hand
+                self.bot.role_grants.remove(grant.member_id, grant.role_id)
+                continue
+
+            if settings.observer_mode:
+                self.bot.observer.record("remove role", member=member.id, role=role.name)
+                self.bot.role_grants.remove(grant.member_id, grant.role_id)
+                continue
+
+            try:
+                check_can_manage_roles(guild, role)
+                await member.remove_roles(role, reason="Temporary role grant expired")
+            except MissingPermissionsError as e:
+                # Kept, so it's revoked once the bot's permissions are fixed
+                logger.error("Can't revoke expired role %s from %s: %s", role.name, member, e)
+                continue
+            except discord.HTTPException as e:
+                logger.error("Failed to revoke expired role %s from %s: %s", role.name, member, e)
+                continue
+
+            self.bot.role_grants.remove(grant.member_id, grant.role_id)
+            logger.info("Revoked expired role %s from %s", role.name, member)
+
     async def _check_watch_renewal(self) -> None:
         """Renew watch channel if it's about to expire."""
         watch = self.calendar.get_watch_channel()
@@ -130,7 +566,9 @@ class SchedulerCog(commands.Cog):
         await self.bot.wait_until_ready()
 
         # Initial fetch to populate known events
-        events = self.calendar.get_upcoming_events(hours_ahead=48)
+        events = self.calendar.get_upcoming_events(hours_ahead=LOOKAHEAD_HOURS)
+        events += self.bot.schedules.get_upcoming_events(hours_ahead=LOOKAHEAD_HOURS)
+        events += self.bot.submissions.get_upcoming_events(hours_ahead=LOOKAHEAD_HOURS)
         for event in events:
             self.known_events[event.id] = event
 
@@ -143,174 +581,1467 @@ class SchedulerCog(commands.Cog):
         await self.bot.wait_until_ready()
         logger.info("Reminder loop started")
 
-    async def resolve_channel_id(self, channel_name: str) -> int | None:
-        """Resolve a channel name to its ID, with caching."""
-        if channel_name in self.channel_cache:
-            return self.channel_cache[channel_name]
+    @retry_loop.before_loop
+    async def before_retry_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
 
-        guild = self.bot.get_guild(settings.discord_guild_id)
+    def get_current_event(self) -> CalendarEvent | None:
+        """Return the public event currently in progress, if any."""
+        now = datetime.now(ZoneInfo("UTC"))
+        for event in self.known_events.values():
+            if is_private(event) or self._discord_event_status(event.id) == EventStatus.CANCELED:
+                continue
+            if event.start_time <= now < event.end_time:
+                return event
+        return None
+
+    def get_events_between(self, start: datetime, end: datetime) -> list[CalendarEvent]:
+        """Return known, non-cancelled events starting in [start, end), by start time."""
+        events = [
+            event
+            for event in self.known_events.values()
+            if start <= event.start_time < end
+            and self._discord_event_status(event.id) != EventStatus.CANCELED
+        ]
+        return sorted(events, key=lambda event: event.start_time)
+
+    async def resolve_channel_id(
+        self, channel_name: str, guild_id: int | None = None
+    ) -> int | None:
+        """Resolve a channel name in a guild (the primary one by default) to its ID."""
+        guild_id = guild_id or settings.discord_guild_id
+        guild = self.bot.get_guild(guild_id)
         if not guild:
-            logger.error("Guild not found: %d", settings.discord_guild_id)
+            logger.error("Guild not found: %d", guild_id)
             return None
 
-        for channel in guild.channels:
-            self.channel_cache[channel.name] = channel.id
+        return await self.channels.resolve(guild, channel_name, datetime.now(ZoneInfo("UTC")))
+
+    async def create_one_off(self, submission: EventSubmission) -> str:
+        """Schedule an ad-hoc event through the same pipeline as recurring events.
+
+        The event is kept as an approved submission, so it survives restarts
+        and gets its Discord event, announcement, reminders, and start
+        notification like any other. Scheduling the same name and start twice
+        returns the first.
+
+        Returns:
+            The submission ID of the event.
+
+        Raises:
+            ValueError: If the event already started or its voice channel doesn't exist.
+        """
+        if submission.time <= datetime.now(ZoneInfo("UTC")):
+            raise ValueError("The event must start in the future")
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if guild and not discord.utils.get(guild.voice_channels, name=submission.channel):
+            raise ValueError(f"Unknown voice channel: {submission.channel}")
+
+        submission_id = self.bot.submissions.find_approved(submission)
+        if submission_id:
+            return submission_id
+
+        submission_id = self.bot.submissions.add(submission)
+        self.bot.submissions.decide(submission_id, approved=True)
+        logger.info("Scheduled one-off event %s: %s", submission_id, submission.name)
+        await self.schedule_submission(submission_id)
+        return submission_id
+
+    async def schedule_submission(self, submission_id: str) -> None:
+        """Create an approved submission's Discord event now if it's due.
+
+        Otherwise the scheduler loop picks it up once it's within the lookahead.
+        """
+        event = self.bot.submissions.event(submission_id)
+        if event is None or self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
 
-        return self.channel_cache.get(channel_name)
+        self.known_events[event.id] = event
+        await self.check_and_create_discord_event(event)
 
     async def check_and_create_discord_event(self, event: CalendarEvent) -> None:
-        """Create a Discord scheduled event if not already created."""
-        if event.id in self.created_discord_events:
+        """Create Discord scheduled events in the primary and mirror guilds if not yet created."""
+        if not should_create_discord_event(event, datetime.now(ZoneInfo("UTC"))):
             return
 
-        now = datetime.now(ZoneInfo("UTC"))
-        time_until_event = event.start_time - now
+        await self._create_discord_event(event)
+        for mirror in event.schedule.mirrors if event.schedule else []:
+            await self._create_discord_event(event, mirror)
+
+    async def _create_discord_event(
+        self, event: CalendarEvent, mirror: ScheduleMirror | None = None
+    ) -> None:
+        """Create and announce an event in the primary guild, or in a mirror guild."""
+        guild_id = mirror.guild_id if mirror else _guild_id(event)
+        key = _tracking_key(event.id, mirror)
+        if self.bot.store.get(DISCORD_EVENTS, key) is not None:
+            return
 
-        if time_until_event > timedelta(hours=24) or time_until_event < timedelta(minutes=0):
+        failure = self.bot.store.get(CREATE_FAILURES, key)
+        if failure and datetime.now(ZoneInfo("UTC")) < datetime.fromisoformat(failure["retry_at"]):
             return
 
-        voice_channel_id = await self.resolve_channel_id(settings.discord_voice_channel)
+        voice_channel_name = mirror.voice_channel if mirror else _voice_channel(event)
+        voice_channel_id = await self.resolve_channel_id(voice_channel_name, guild_id)
         if not voice_channel_id:
-            logger.error("Failed to resolve voice channel: %s", settings.discord_voice_channel)
+            logger.error(
+                "Failed to resolve voice channel: %s",
+                voice_channel_name,
+                extra=_log_fields(event, guild_id),
+            )
             return
 
-        notify_channel_id = await self.resolve_channel_id(settings.discord_notify_channel)
+        notify_channel_name = mirror.notify_channel if mirror else _notify_channel(event)
+        # The canary channel may be public, so private announcements skip it
+        if not is_private(event):
+            notify_channel_name = self.bot.canary.route(
+                "announcements", notify_channel_name, datetime.now(ZoneInfo("UTC"))
+            )
+        notify_channel_id = await self.resolve_channel_id(notify_channel_name, guild_id)
         if not notify_channel_id:
-            logger.error("Failed to resolve notify channel: %s", settings.discord_notify_channel)
+            logger.error(
+                "Failed to resolve notify channel: %s",
+                notify_channel_name,
+                extra=_log_fields(event, guild_id),
+            )
             return
 
-        guild = self.bot.get_guild(settings.discord_guild_id)
+        guild = self.bot.get_guild(guild_id)
         if not guild:
-            logger.error("Guild not found")
+            logger.error("Guild not found", extra=_log_fields(event, guild_id))
             return
 
         voice_channel = guild.get_channel(voice_channel_id)
         if not voice_channel:
-            logger.error("Voice channel not found")
+            logger.error("Voice channel not found", extra=_log_fields(event, guild_id))
             return
 
+        name = mirror.name if mirror and mirror.name else event.name
+        description = mirror.description if mirror and mirror.description else event.description
+        # Names and descriptions come from organizers, the calendar, and submitters
+        name = sanitize_text(name, EVENT_NAME_LIMIT)
+        description = sanitize_text(description, EVENT_DESCRIPTION_LIMIT)
+        notify_channel = self.bot.get_channel(notify_channel_id)
+
         try:
-            discord_event = await guild.create_scheduled_event(
-                name=event.name,
-                description=event.description or "Event from Google Calendar",
-                start_time=event.start_time,
-                end_time=event.end_time,
-                channel=voice_channel,
-                privacy_level=discord.PrivacyLevel.guild_only,
-            )
-            self.created_discord_events.add(event.id)
-            logger.info("Created Discord event: %s (starts %s)", event.name, event.start_time)
-        except discord.HTTPException as e:
-            logger.error("Failed to create Discord event: %s", e)
+            check_can_manage_events(guild, voice_channel)
+            # A Discord event is visible to whoever can see its voice channel
+            if is_private(event):
+                check_private_channel(voice_channel)
+                if notify_channel:
+                    check_private_channel(notify_channel)
+        except (MissingPermissionsError, PublicChannelError) as e:
+            logger.error(
+                "Can't create Discord event for %s: %s", name, e, extra=_log_fields(event, guild_id)
+            )
+            await self._record_create_failure(event, key, name, e)
             return
 
-        notify_channel = self.bot.get_channel(notify_channel_id)
+        try:
+            meeting_url = None if settings.observer_mode else await self.bot.meetings.link(event)
+        except MeetingError as e:
+            logger.error(
+                "Can't create Discord event for %s: %s", name, e, extra=_log_fields(event, guild_id)
+            )
+            await self._record_create_failure(event, key, name, e)
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record(
+                "create scheduled event",
+                name=name,
+                start=event.start_time.isoformat(),
+                channel=voice_channel.name,
+                guild=guild_id,
+            )
+            self._track_discord_event(event, None, mirror)
+            discord_event_id = None
+            event_url = "(not created in observer mode)"
+        else:
+            cover = await self._cover_image(event, name, guild_id)
+            try:
+                if event.schedule and event.schedule.native_recurrence:
+                    discord_event_id = await self._recurring_discord_event(
+                        event, guild, voice_channel, name, description, mirror, cover
+                    )
+                    self._track_discord_event(event, discord_event_id, mirror, RECURRING)
+                else:
+                    discord_event = await guild.create_scheduled_event(
+                        name=name,
+                        description=_with_meeting(
+                            description or "Event from Google Calendar", meeting_url
+                        ),
+                        start_time=event.start_time,
+                        end_time=event.end_time,
+                        channel=voice_channel,
+                        privacy_level=discord.PrivacyLevel.guild_only,
+                        image=cover or discord.utils.MISSING,
+                    )
+                    discord_event_id = discord_event.id
+                    self._track_discord_event(event, discord_event_id, mirror)
+                    logger.info(
+                        "Created Discord event: %s in %s (starts %s)",
+                        name,
+                        guild,
+                        event.start_time,
+                        extra=_log_fields(event, guild_id),
+                    )
+                self.bot.store.delete(CREATE_FAILURES, key)
+            except discord.HTTPException as e:
+                logger.error(
+                    "Failed to create Discord event: %s",
+                    e,
+                    extra=_log_fields(event, guild_id) | {"status": e.status},
+                )
+                await self._record_create_failure(event, key, name, e)
+                return
+            event_url = f"https://discord.com/events/{guild_id}/{discord_event_id}"
+
+        where = f" in {guild.name}" if guild_id != settings.discord_guild_id else ""
+        await self.bot.messenger.audit_log(
+            f"📅 Created the Discord event **{name}** for "
+            f"<t:{int(event.start_time.timestamp())}:F>{where}, {_event_source(event)}."
+        )
+        if mirror is None:
+            await self._warn_conflicts(event, voice_channel)
+
+        fields = _template_fields(
+            event, name, description, voice_channel_id, event_url, meeting_url
+        )
+        if mirror is None and event.schedule and not is_private(event):
+            # Partners can't open a channel of another guild, so it's named instead
+            where = f"#{voice_channel.name} on {guild.name}"
+            await self.announce_to_partners(event, fields | {"where": where})
+
         if not notify_channel:
             return
 
-        notification = (
-            f"================\n"
-            f"**New Event Alert!**\n"
-            f"**{event.name}**\n"
-            f"{event.description}\n"
-            f"**When:** <t:{int(event.start_time.timestamp())}:F> (<t:{int(event.start_time.timestamp())}:R>)\n"
-            f"**Timezone:** {event.timezone}\n"
-            f"**Duration:** {event.duration_minutes} minutes\n"
-            f"**Where:** <#{voice_channel_id}>\n\n"
-            f"See you there!👇\n"
-            f"https://discord.com/events/{settings.discord_guild_id}/{discord_event.id}"
+        # Experiments only run in the primary guild; mirrors have their own translated template
+        if mirror:
+            templates = [mirror.announcement_template] if mirror.announcement_template else []
+        else:
+            templates = event.schedule.announcement_templates if event.schedule else []
+        experiment = mirror is None and is_experiment(event.schedule)
+        variant = 0
+        if experiment:
+            variant = self.bot.experiments.next_variant(event.schedule)
+        notification = _announcement(
+            fields, self._expand(templates[variant], guild_id) if templates else None
         )
 
-        await notify_channel.send(notification, allowed_mentions=discord.AllowedMentions(everyone=True))
-        logger.info("Sent event notification for: %s", event.name)
+        # Sponsors are only shown to members of the primary guild, their blurbs aren't translated
+        sponsorship = self.bot.schedules.config.sponsorship
+        sponsor = None
+        if mirror is None and not is_private(event) and sponsorship.announcements:
+            sponsor = self.bot.sponsors.next(sponsorship.sponsors)
+        if sponsor:
+            notification += f"\n\n{sponsor_line(sponsor)}"
 
-    async def check_and_send_reminder(self, event: CalendarEvent) -> None:
-        """Send reminder if we're at a reminder interval."""
-        now = datetime.now(ZoneInfo("UTC"))
-        minutes_until = round((event.start_time - now).total_seconds() / 60)
+        # Only the primary guild's announcement takes RSVPs, so there's one list of seats
+        capacity = event.schedule.capacity if event.schedule and mirror is None else None
+        takes_rsvps = mirror is None and (capacity is not None or settings.rsvp_all_events)
+        view = rsvp_view(self.bot.components, capacity) if takes_rsvps else None
+
+        # Announcements only ping a private event's audience; mentions in templates are text
+        allowed_mentions = discord.AllowedMentions.none()
+        if is_private(event):
+            ping, allowed_mentions = _ping(notify_channel, "role", _audience_role(event))
+            notification = f"{ping}\n{notification}" if ping else notification
+
+        async def announce(nonce: str) -> discord.Message | None:
+            message = await self.bot.messenger.send(
+                notify_channel,
+                notification,
+                allowed_mentions=allowed_mentions,
+                view=view,
+                silent=_silent(event, "announcement"),
+                nonce=nonce,
+            )
+            logger.info(
+                "Sent event notification for: %s", name, extra=_log_fields(event, guild_id)
+            )
+            if message and takes_rsvps:
+                self.bot.rsvps.open(
+                    event.id, message.id, name, capacity, event.end_time, notify_channel.id
+                )
+            if message and sponsor:
+                self.bot.sponsors.record_impression(sponsor, sponsorship.sponsors)
+
+            if message and discord_event_id and experiment:
+                self.bot.experiments.record_announcement(
+                    event.id,
+                    event.schedule,
+                    variant,
+                    notify_channel_id,
+                    message.id,
+                    discord_event_id,
+                )
+            return message
 
-        for reminder_mins in settings.reminder_minutes:
-            reminder_key = f"{event.id}:{reminder_mins}"
+        await self._send_notification(f"the announcement of **{name}**", announce)
 
-            if reminder_key in self.sent_reminders:
+    async def announce_to_partners(self, event: CalendarEvent, fields: dict) -> None:
+        """Announce a public event in the partner communities its schedule is shared with.
+
+        Partners only get the announcement: no Discord event, RSVP buttons,
+        sponsor, or pings. Partners who opted out are skipped.
+        """
+        partners = self.bot.partners
+        for partner in partners_of(self.bot.schedules.config.partners, event.schedule):
+            guild_id = partner.guild_id
+            if partners.announced(event.id, guild_id) or partners.opted_out(guild_id):
                 continue
 
-            # Trigger when at or just past the reminder threshold (within 1 min window)
-            diff = reminder_mins - minutes_until
-            if 0 <= diff <= 1:
-                logger.info(
-                    "Sending reminder for '%s' (%d min before, diff=%d)",
-                    event.name, reminder_mins, diff
+            guild = self.bot.get_guild(guild_id)
+            channel = guild and discord.utils.get(guild.text_channels, name=partner.channel)
+            if not channel:
+                logger.error(
+                    "Channel of partner %s not found: %s",
+                    partner.name,
+                    partner.channel,
+                    extra=_log_fields(event, guild_id),
                 )
-                await self.send_reminder(event, reminder_mins)
-                self.sent_reminders.add(reminder_key)
+                continue
+
+            template = partner.announcement_template
+            message = await self.bot.messenger.send(
+                channel,
+                _announcement(
+                    fields, self._expand(template, settings.discord_guild_id) if template else None
+                ),
+                allowed_mentions=discord.AllowedMentions.none(),
+            )
+            # Like the primary announcement, a failed one isn't retried
+            partners.record(event.id, guild_id, message.id if message else None, event.end_time)
+            logger.info(
+                "Announced %s to partner %s",
+                event.name,
+                partner.name,
+                extra=_log_fields(event, guild_id),
+            )
+
+    async def preview_announcements(self, event: CalendarEvent) -> list[str]:
+        """Render an occurrence's announcement as it would be posted, one per A/B variant.
+
+        Nothing is created for the preview, so the Discord event and meeting
+        links are placeholders until the event is announced.
+        """
+        guild_id = _guild_id(event)
+        voice_channel_id = await self.resolve_channel_id(_voice_channel(event), guild_id)
+        tracked = self.bot.store.get(DISCORD_EVENTS, event.id)
+        event_url = (
+            f"https://discord.com/events/{guild_id}/{tracked['id']}"
+            if tracked and tracked["id"]
+            else "(Discord event link)"
+        )
+        meeting_url = "(meeting link)" if event.schedule and event.schedule.meeting else None
+        fields = _template_fields(
+            event,
+            sanitize_text(event.name, EVENT_NAME_LIMIT),
+            sanitize_text(event.description, EVENT_DESCRIPTION_LIMIT),
+            voice_channel_id,
+            event_url,
+            meeting_url,
+        )
+        templates = event.schedule.announcement_templates if event.schedule else []
+        return [
+            _announcement(fields, self._expand(template, guild_id) if template else None)
+            for template in templates or [None]
+        ]
+
+    async def _recurring_discord_event(
+        self,
+        event: CalendarEvent,
+        guild: discord.Guild,
+        voice_channel: discord.abc.GuildChannel,
+        name: str,
+        description: str,
+        mirror: ScheduleMirror | None,
+        cover: bytes | None = None,
+    ) -> int:
+        """Return the schedule's recurring Discord event, creating it on its first occurrence.
+
+        The event is recreated when the schedule's days, time, or duration change.
+
+        Raises:
+            discord.HTTPException: If creating the event fails.
+        """
+        key = _tracking_key(event.schedule.name, mirror)
+        pattern = recurrence_pattern(event.schedule)
+        series = self.bot.store.get(RECURRING_EVENTS, key)
+        if series and series["pattern"] == pattern:
+            return series["id"]
+
+        if series:
+            try:
+                scheduled = await self._fetch_scheduled_event(series["id"], guild.id)
+                if scheduled:
+                    await scheduled.delete(reason="Schedule changed")
+            except discord.HTTPException as e:
+                logger.warning("Failed to delete outdated recurring event for %s: %s", name, e)
+
+        payload = {
+            "name": name,
+            "description": description or "Event from Google Calendar",
+            "channel_id": voice_channel.id,
+            "entity_type": discord.EntityType.voice.value,
+            "privacy_level": discord.PrivacyLevel.guild_only.value,
+            "scheduled_start_time": event.start_time.isoformat(),
+            "scheduled_end_time": event.end_time.isoformat(),
+            "recurrence_rule": recurrence_rule(event.schedule, event.start_time),
+        }
+        if cover:
+            payload["image"] = data_uri(cover)
+        # Sent through the raw route to include the recurrence rule
+        data = await self.bot.http.request(
+            route("POST", "/guilds/{guild_id}/scheduled-events", guild_id=guild.id),
+            json=payload,
+            reason="Recurring schedule",
+        )
+        discord_event_id = int(data["id"])
+        self.bot.store.set(RECURRING_EVENTS, key, {"id": discord_event_id, "pattern": pattern})
+        logger.info(
+            "Created recurring Discord event: %s in %s",
+            name,
+            guild,
+            extra=_log_fields(event, guild.id),
+        )
+        return discord_event_id
+
+    async def _warn_conflicts(
+        self, event: CalendarEvent, voice_channel: discord.VoiceChannel
+    ) -> None:
+        """Warn in the audit log about other events in the voice channel at the same time.
+
+        Both events are still created; organizers decide which one moves.
+        """
+        for other in self.known_events.values():
+            if other.id == event.id or voice_room(other) != voice_room(event):
+                continue
+            if not overlaps(event, other):
+                continue
+            if self._discord_event_status(other.id) == EventStatus.CANCELED:
+                continue
+            logger.warning(
+                "%s overlaps %s in %s",
+                event.name,
+                other.name,
+                voice_channel.name,
+                extra=_log_fields(event),
+            )
+            await self.bot.messenger.audit_log(
+                f"⚠️ **{event.name}** (<t:{int(event.start_time.timestamp())}:F>) overlaps "
+                f"**{other.name}** (<t:{int(other.start_time.timestamp())}:F>) in "
+                f"{voice_channel.mention}. See `!schedules conflicts`."
+            )
+
+    async def _cover_image(self, event: CalendarEvent, name: str, guild_id: int) -> bytes | None:
+        """Return the cover image of a schedule's Discord events, or None without one.
+
+        An image that can't be loaded doesn't hold the event back; it's created
+        without a cover, and the problem logged.
+        """
+        source = event.schedule.image if event.schedule else ""
+        if not source:
+            return None
+
+        try:
+            return await self.bot.cover_images.load(source)
+        except CoverImageError as e:
+            logger.warning(
+                "Creating %s without a cover image: %s", name, e, extra=_log_fields(event, guild_id)
+            )
+            return None
+
+    async def _record_create_failure(
+        self, event: CalendarEvent, key: str, name: str, error: Exception
+    ) -> None:
+        """Schedule the next attempt at creating a Discord event, escalating once retries run out.
+
+        Attempts back off up to an hour apart and continue after escalating, so
+        the event is still created if organizers fix the cause in time.
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        failure = self.bot.store.get(CREATE_FAILURES, key) or {
+            "attempts": 0,
+            "first": now.isoformat(),
+            "end": event.end_time.isoformat(),
+            "escalated": False,
+        }
+        failure["attempts"] += 1
+        failure["retry_at"] = (now + retry_delay(failure["attempts"])).isoformat()
+        failing_for = now - datetime.fromisoformat(failure["first"])
+        escalate = not failure["escalated"] and failing_for >= timedelta(
+            hours=settings.event_retry_hours
+        )
+        # Retries continue during a Discord API incident, but escalating then is noise
+        if escalate and self.bot.incidents.discord_incident:
+            logger.info("Not escalating %s during a Discord incident", name)
+            escalate = False
+        if escalate:
+            failure["escalated"] = True
+        self.bot.store.set(CREATE_FAILURES, key, failure)
+
+        if escalate:
+            await self._escalate_create_failure(event, name, failure["attempts"], error)
+
+    async def _escalate_create_failure(
+        self, event: CalendarEvent, name: str, attempts: int, error: Exception
+    ) -> None:
+        """Tell the schedule's owners and the ops channel that an event can't be created."""
+        start = int(event.start_time.timestamp())
+        message = (
+            f"⚠️ The Discord event for **{name}** (<t:{start}:F>) still can't be created "
+            f"after {attempts} attempts over {settings.event_retry_hours:g} hours: {error}\n"
+            f"I'll keep retrying every hour until it starts."
+        )
+        logger.warning(
+            "Escalating failed Discord event creation for %s: %s",
+            name,
+            error,
+            extra=_log_fields(event),
+        )
+        await self.bot.messenger.alert_ops(message)
+        await self._notify_owners(event, message)
+
+    async def _notify_owners(self, event: CalendarEvent, message: str) -> None:
+        """DM the owners of an event's schedule."""
+        for owner in event.schedule.owners if event.schedule else []:
+            try:
+                user = self.bot.get_user(owner) or await self.bot.fetch_user(owner)
+                await self.bot.messenger.send(user, message)
+            except discord.HTTPException as e:
+                logger.warning("Failed to notify schedule owner %d: %s", owner, e)
+
+    async def send_due_reminders(self, events: list[CalendarEvent]) -> None:
+        """Send due reminders, one combined message per channel and offset.
+
+        Reminders due during quiet hours, in their event's timezone, are held
+        back, then sent as one per channel when they end, for the events that
+        haven't started by then. Held reminders are kept in the store, so a
+        restart doesn't lose them.
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        batches = reminder_batches(
+            events,
+            now,
+            settings.reminder_minutes,
+            set(self.bot.store.items(SENT_REMINDERS)),
+            _reminder_channel,
+            ZoneInfo(settings.default_timezone),
+        )
+        for (channel, minutes), batch in batches.items():
+            for event in (event for event in batch if _in_quiet_hours(event, now)):
+                logger.info("Holding back the reminder for %s until quiet hours end", event.name)
+                held = {"start": event.start_time.isoformat()}
+                self.bot.store.set(HELD_REMINDERS, event.id, held)
+            due = [event for event in batch if not _in_quiet_hours(event, now)]
+            if due:
+                names = ", ".join(event.name for event in due)
+                logger.info("Sending reminder for %s (%d min before)", names, minutes)
+                guild_id, channel_name, audience_role, ping, _ = channel
+                await self.send_reminder(channel_name, due, minutes, guild_id, audience_role, ping)
+            for event in batch:
+                sent = {"end": event.end_time.isoformat()}
+                self.bot.store.set(SENT_REMINDERS, f"{event.id}:{minutes}", sent)
+
+        await self._send_held_reminders(now)
+
+    async def _send_held_reminders(self, now: datetime) -> None:
+        """Send the reminders held back during quiet hours that ended, dropping started events.
+
+        Held events the scheduler doesn't know yet, e.g. right after a restart,
+        wait for the next run, until their start passes.
+        """
+        held = {
+            event_id: datetime.fromisoformat(reminder["start"])
+            for event_id, reminder in self.bot.store.items(HELD_REMINDERS).items()
+        }
+        batches, dropped = held_reminder_batches(
+            held, self.known_events, now, _reminder_channel, _in_quiet_hours
+        )
+        for event_id in dropped:
+            logger.info("Dropped the reminder for %s held back by quiet hours", event_id)
+            self.bot.store.delete(HELD_REMINDERS, event_id)
+
+        for (guild_id, channel_name, audience_role, ping, _), events in batches.items():
+            minutes = minutes_until(events[0], now)
+            names = ", ".join(event.name for event in events)
+            logger.info("Sending reminder for %s held back by quiet hours", names)
+            await self.send_reminder(channel_name, events, minutes, guild_id, audience_role, ping)
+            for event in events:
+                self.bot.store.delete(HELD_REMINDERS, event.id)
+
+    async def send_reminder(
+        self,
+        channel_name: str,
+        events: list[CalendarEvent],
+        minutes_before: int,
+        guild_id: int | None = None,
+        audience_role: str = "",
+        ping: str | int | None = None,
+    ) -> None:
+        """Send one reminder pinging the notification role, or the private events' audience.
 
-    async def send_reminder(self, event: CalendarEvent, minutes_before: int) -> None:
-        """Send a reminder for an upcoming event."""
-        notify_channel_id = await self.resolve_channel_id(settings.discord_notify_channel)
+        `ping` overrides `REMINDER_PING`. A single event whose schedule has a
+        reminder template gets that text instead of the reminder card.
+        """
+        notify_channel_id = await self.resolve_channel_id(channel_name, guild_id)
         if not notify_channel_id:
             return
 
         channel = self.bot.get_channel(notify_channel_id)
         if not channel:
             return
+        if audience_role and is_public(channel):
+            logger.error("Not reminding of private events: %s", PublicChannelError(channel))
+            return
+
+        mention, allowed_mentions = _ping(
+            channel, settings.reminder_ping if ping is None else ping, audience_role
+        )
+        template = events[0].schedule.reminder_template if events[0].schedule else ""
+        if len(events) == 1 and template:
+            text = await self.render_template(events[0], template)
+            content, embed = f"{mention}\n{text}" if mention else text, None
+        else:
+            content, embed = mention, await self.reminder_embed(events, minutes_before, guild_id)
+        view = self._reminder_view(events)
+
+        async def remind(nonce: str) -> discord.Message | None:
+            message = await self.bot.messenger.send(
+                channel,
+                content,
+                embed=embed,
+                allowed_mentions=allowed_mentions,
+                view=view,
+                silent=all(_silent(event, "reminder") for event in events),
+                nonce=nonce,
+            )
+            logger.info("Sent %s reminder for %d events", _time_text(minutes_before), len(events))
+            if message:
+                self.bot.store.set(
+                    REMINDER_MESSAGES,
+                    str(message.id),
+                    {
+                        "events": [event.id for event in events],
+                        "end": max(event.end_time for event in events).isoformat(),
+                    },
+                )
+            return message
+
+        names = ", ".join(f"**{event.name}**" for event in events)
+        await self._send_notification(
+            f"the {_time_text(minutes_before)} reminder for {names}", remind
+        )
+
+    def _reminder_view(self, events: list[CalendarEvent]) -> discord.ui.View:
+        """Build a reminder's buttons: snooze, plus RSVPs if it's of one event taking them."""
+        components = self.bot.components
+        view = discord.ui.View(timeout=None)
+        view.add_item(
+            components.button(
+                REMINDER_ACTIONS,
+                "snooze",
+                label=f"Remind me again in {SNOOZE.seconds // 60} min",
+                emoji="🔁",
+            )
+        )
+        if len(events) == 1 and self.bot.rsvps.get(events[0].id):
+            view.add_item(
+                components.button(
+                    REMINDER_ACTIONS,
+                    "going",
+                    label="I'm coming",
+                    emoji="✅",
+                    style=discord.ButtonStyle.success,
+                )
+            )
+            view.add_item(
+                components.button(REMINDER_ACTIONS, "declined", label="Can't make it", emoji="❌")
+            )
+        return view
 
-        voice_channel_id = await self.resolve_channel_id(settings.discord_voice_channel)
+    async def reminder_action(self, interaction: discord.Interaction, payload: str) -> None:
+        """Snooze, or RSVP to, the events of the reminder whose button was pressed."""
+        now = datetime.now(ZoneInfo("UTC"))
+        tracked = interaction.message and self.bot.store.get(
+            REMINDER_MESSAGES, str(interaction.message.id)
+        )
+        events = [
+            self.known_events[event_id]
+            for event_id in (tracked["events"] if tracked else [])
+            if event_id in self.known_events
+        ]
+        events = [event for event in events if event.start_time > now]
+        if not events:
+            await interaction.response.send_message(
+                "That event already started or was canceled.", ephemeral=True
+            )
+            return
 
-        if minutes_before >= 60:
-            hours = minutes_before // 60
-            time_text = "1 hour" if hours == 1 else f"{hours} hours"
+        if payload == "snooze":
+            await self._snooze(interaction, events, now)
         else:
-            time_text = f"{minutes_before} minutes"
+            await self._rsvp_from_reminder(interaction, events[0], payload == "going")
+
+    async def _snooze(
+        self, interaction: discord.Interaction, events: list[CalendarEvent], now: datetime
+    ) -> None:
+        """DM a member about events again in `SNOOZE`, or at the start if that's sooner.
 
-        msg = (
-            f"================\n"
-            f"**Reminder:** {event.name} starts in {time_text}!\n"
-            f"**Duration:** {event.duration_minutes} minutes\n"
-            f"{event.description}\n\n"
-            f"Join us in <#{voice_channel_id}>"
+        Snoozing again moves the member's reminder instead of adding another.
+        """
+        message = " ".join(
+            f"**{event.name}** starts <t:{int(event.start_time.timestamp())}:R>!"
+            for event in events
         )
+        user_id = interaction.user.id
+        for reminder_id, reminder in self.bot.store.items(REMINDERS).items():
+            if reminder["user_id"] == user_id and reminder["message"] == message:
+                self.bot.store.delete(REMINDERS, reminder_id)
+        due = min(now + SNOOZE, min(event.start_time for event in events))
+        add_reminder(self.bot, user_id, None, due, message)
 
-        await channel.send(msg, allowed_mentions=discord.AllowedMentions(everyone=True))
-        logger.info("Sent %s reminder for %s", time_text, event.name)
+        await interaction.response.send_message(
+            f"Okay, I'll DM you again <t:{int(due.timestamp())}:R>.", ephemeral=True
+        )
 
-    async def check_and_send_start_notification(self, event: CalendarEvent) -> None:
-        """Send notification when event is starting."""
-        if event.id in self.sent_start_notifications:
+    async def _rsvp_from_reminder(
+        self, interaction: discord.Interaction, event: CalendarEvent, going: bool
+    ) -> None:
+        """RSVP a member going or not, as the buttons on the event's announcement do."""
+        rsvps = self.bot.rsvps.get(event.id)
+        if not rsvps:
+            await interaction.response.send_message(
+                "RSVPs for this event are closed.", ephemeral=True
+            )
             return
 
+        name = rsvps["name"]
+        promoted = None
+        if going:
+            place = self.bot.rsvps.join(event.id, interaction.user.id)
+            message = join_message(name, rsvps["capacity"], place)
+        else:
+            promoted = self.bot.rsvps.answer(event.id, interaction.user.id, "declined")
+            message = f"Got it, you can't make **{name}**."
+        await interaction.response.send_message(message, ephemeral=True)
+
+        rsvp_cog = self.bot.get_cog("RsvpCog")
+        if rsvp_cog:
+            await rsvp_cog.refresh_announcement(event.id)
+            if promoted:
+                await rsvp_cog.notify_promoted(promoted, name, interaction.message.jump_url)
+
+    async def send_dm_reminders(self, events: list[CalendarEvent]) -> None:
+        """DM the members interested in or going to events about to start.
+
+        Each member is DMed once per occurrence, even after a restart, unless
+        they turned the DMs off with `/eventdms off`.
+        """
+        minutes = settings.dm_reminder_minutes
         now = datetime.now(ZoneInfo("UTC"))
-        minutes_until = round((event.start_time - now).total_seconds() / 60)
+        for event in events:
+            if not minutes or not dm_reminder_due(event, now, minutes):
+                continue
+
+            rsvps = self.bot.rsvps.get(event.id) or {"going": [], "maybe": []}
+            candidates = [*self.bot.interest.users(event.id), *rsvps["going"], *rsvps["maybe"]]
+            recipients = self.bot.dm_reminders.recipients(event.id, candidates)
+            if not recipients:
+                continue
+
+            content = await self._dm_reminder(event)
+            for user_id in recipients:
+                try:
+                    user = self.bot.get_user(user_id) or await self.bot.fetch_user(user_id)
+                    await self.bot.messenger.send(user, content)
+                except discord.Forbidden:
+                    logger.info("Not reminding %d of %s, their DMs are closed", user_id, event.name)
+                except discord.HTTPException as e:
+                    logger.warning("Failed to DM %d a reminder of %s: %s", user_id, event.name, e)
+                # Also after a failure, so a member isn't retried every minute until the start
+                self.bot.dm_reminders.mark_sent(event.id, event.end_time, user_id)
+            logger.info("DMed %d members a reminder", len(recipients), extra=_log_fields(event))
+
+    async def _dm_reminder(self, event: CalendarEvent) -> str:
+        """Build the DM reminding a member of an event, linking its Discord event."""
+        voice_channel_id = await self.resolve_channel_id(_voice_channel(event), _guild_id(event))
+        where = f" in <#{voice_channel_id}>" if voice_channel_id else ""
+        lines = [f"⏰ **{event.name}** starts <t:{int(event.start_time.timestamp())}:R>{where}."]
+        tracked = self.bot.store.get(DISCORD_EVENTS, event.id)
+        if tracked and tracked["id"]:
+            lines.append(f"https://discord.com/events/{_guild_id(event)}/{tracked['id']}")
+        lines.append(
+            "-# You're getting this because you're interested in or going to the event. "
+            "Turn these DMs off with `/eventdms off`."
+        )
+        return "\n".join(lines)
+
+    async def reminder_embed(
+        self, events: list[CalendarEvent], minutes_before: int, guild_id: int | None = None
+    ) -> discord.Embed:
+        """Build the reminder card for one event, or for several in the same channel."""
+        time_text = _time_text(minutes_before)
+        builder = EmbedBuilder().set_color(discord.Color.orange())
+        if len(events) == 1:
+            event = events[0]
+            voice_channel_id = await self.resolve_channel_id(_voice_channel(event), guild_id)
+            start = int(event.start_time.timestamp())
+            (
+                builder.set_title(f"⏰ {event.name} starts in {time_text}!")
+                .set_description(event.description[:DESCRIPTION_LIMIT])
+                .add_field(name="When", value=f"<t:{start}:F> (<t:{start}:R>)")
+                .add_field(name="Duration", value=f"{event.duration_minutes} minutes", inline=True)
+                .add_field(name="Where", value=f"<#{voice_channel_id}>", inline=True)
+                .set_timestamp(event.start_time)
+            )
+            rsvps = self.bot.rsvps.get(event.id)
+            if rsvps and rsvps["going"]:
+                builder.add_field(
+                    name=f"Going ({len(rsvps['going'])})", value=_mention_list(rsvps["going"])
+                )
+        else:
+            lines = []
+            for event in events:
+                voice_channel_id = await self.resolve_channel_id(_voice_channel(event), guild_id)
+                rsvps = self.bot.rsvps.get(event.id)
+                going = f", {len(rsvps['going'])} going" if rsvps else ""
+                lines.append(
+                    f"• **{event.name}** <t:{int(event.start_time.timestamp())}:R> "
+                    f"({event.duration_minutes} min{going}) in <#{voice_channel_id}>"
+                )
+            builder.set_title(f"⏰ {len(events)} events coming up today!").set_description(
+                "\n".join(lines)[:DESCRIPTION_LIMIT]
+            )
+        return builder.build()
+
+    async def check_and_send_start_notification(self, event: CalendarEvent) -> None:
+        """Send notification when event is starting."""
+        if self.bot.store.get(START_NOTIFICATIONS, event.id):
+            return
 
-        # Trigger only when event has started (minutes_until <= 0)
-        if minutes_until <= 0:
+        if has_started(event, datetime.now(ZoneInfo("UTC"))):
             await self.send_start_notification(event)
-            self.sent_start_notifications.add(event.id)
+            self.bot.store.set(
+                START_NOTIFICATIONS, event.id, {"end": event.end_time.isoformat()}
+            )
+            await self.record_occurrence(event)
+            await self.record_experiment_results(event)
+            await self.schedule_followup(event)
 
     async def send_start_notification(self, event: CalendarEvent) -> None:
         """Send notification that an event is starting."""
-        notify_channel_id = await self.resolve_channel_id(settings.discord_notify_channel)
+        guild_id = _guild_id(event)
+        notify_channel_id = await self.resolve_channel_id(_notify_channel(event), guild_id)
         if not notify_channel_id:
             return
 
         channel = self.bot.get_channel(notify_channel_id)
         if not channel:
             return
+        if is_private(event) and is_public(channel):
+            error = PublicChannelError(channel)
+            logger.error(
+                "Not announcing the start of %s: %s", event.name, error, extra=_log_fields(event)
+            )
+            return
 
-        voice_channel_id = await self.resolve_channel_id(settings.discord_voice_channel)
+        voice_channel_id = await self.resolve_channel_id(_voice_channel(event), guild_id)
 
-        msg = (
-            f"================\n"
-            f"**{event.name} is starting now!**\n"
-            f"{event.description}\n\n"
-            f"**Duration:** {event.duration_minutes} minutes\n"
-            f"**Timezone:** {event.timezone}\n\n"
-            f"Join us in <#{voice_channel_id}>"
-            f"**@everyone**\n"
+        builder = (
+            EmbedBuilder()
+            .set_title(f"🔴 {event.name} is starting now!")
+            .set_description(event.description[:DESCRIPTION_LIMIT])
+            .set_color(discord.Color.green())
+            .add_field(name="Duration", value=f"{event.duration_minutes} minutes", inline=True)
+            .add_field(name="Timezone", value=event.timezone, inline=True)
+            .add_field(name="Where", value=f"<#{voice_channel_id}>", inline=True)
+            .set_timestamp(event.start_time)
         )
+        tracked = self.bot.store.get(DISCORD_EVENTS, event.id)
+        if tracked and tracked["id"]:
+            event_url = f"https://discord.com/events/{guild_id}/{tracked['id']}"
+            builder.add_field(name="Discord event", value=event_url)
+        embed = builder.build()
+
+        ping, allowed_mentions = _ping(channel, settings.start_ping, _audience_role(event))
+
+        async def announce_start(nonce: str) -> discord.Message | None:
+            message = await self.bot.messenger.send(
+                channel,
+                ping,
+                embed=embed,
+                allowed_mentions=allowed_mentions,
+                silent=_silent(event, "start"),
+                nonce=nonce,
+            )
+            logger.info("Sent start notification for %s", event.name, extra=_log_fields(event))
+            return message
+
+        await self._send_notification(
+            f"the start notification for **{event.name}**", announce_start
+        )
+
+    async def check_host_joined(self, event: CalendarEvent) -> None:
+        """Tell the owners when none of them joined the voice channel a while after the start.
+
+        Meanwhile `host_holding_message` is posted, so attendees know the
+        event is still on instead of leaving an empty call.
+        """
+        owners = event.schedule.owners if event.schedule else []
+        if not owners or not settings.host_check_minutes:
+            return
+        if self.bot.store.get(HOST_CHECKS, event.id):
+            return
+        if not host_check_due(event, datetime.now(ZoneInfo("UTC")), settings.host_check_minutes):
+            return
+        self.bot.store.set(HOST_CHECKS, event.id, {"end": event.end_time.isoformat()})
+
+        guild_id = _guild_id(event)
+        voice_channel_id = await self.resolve_channel_id(_voice_channel(event), guild_id)
+        voice_channel = self.bot.get_channel(voice_channel_id) if voice_channel_id else None
+        if not voice_channel:
+            return
+        if any(member.id in owners for member in getattr(voice_channel, "members", [])):
+            return
+
+        logger.warning(
+            "No host has joined %s in %s", event.name, voice_channel.name, extra=_log_fields(event)
+        )
+        start = int(event.start_time.timestamp())
+        await self._notify_owners(
+            event,
+            f"⏳ **{event.name}** started <t:{start}:R>, but no host is in "
+            f"<#{voice_channel_id}> yet. Attendees may be waiting!",
+        )
+
+        if not settings.host_holding_message:
+            return
+        notify_channel_id = await self.resolve_channel_id(_notify_channel(event), guild_id)
+        channel = self.bot.get_channel(notify_channel_id) if notify_channel_id else None
+        if not channel or (is_private(event) and is_public(channel)):
+            return
+        await self.bot.messenger.send(
+            channel,
+            f"⏳ **{event.name}**: {settings.host_holding_message}",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+    def _expand(self, template: str, guild_id: int) -> str:
+        """Fill in a template's partials, then the values set for the guild."""
+        return self.bot.template_values.expand(self.bot.partials.expand(template), guild_id)
+
+    async def render_template(self, event: CalendarEvent, template: str) -> str:
+        """Fill in a reminder or follow-up template of an event that was announced.
+
+        The Discord event and meeting links are the ones created for the
+        announcement, or empty if there aren't any.
+        """
+        guild_id = _guild_id(event)
+        try:
+            meeting_url = None if settings.observer_mode else await self.bot.meetings.link(event)
+        except MeetingError:
+            meeting_url = None
+        voice_channel_id = await self.resolve_channel_id(_voice_channel(event), guild_id)
+        tracked = self.bot.store.get(DISCORD_EVENTS, event.id)
+        event_url = (
+            f"https://discord.com/events/{guild_id}/{tracked['id']}"
+            if tracked and tracked["id"]
+            else ""
+        )
+        return sanitize_template(self._expand(template, guild_id)).format(
+            **_template_fields(
+                event,
+                sanitize_text(event.name, EVENT_NAME_LIMIT),
+                sanitize_text(event.description, EVENT_DESCRIPTION_LIMIT),
+                voice_channel_id,
+                event_url,
+                meeting_url,
+            )
+        )
+
+    async def schedule_followup(self, event: CalendarEvent) -> None:
+        """Fill in the schedule's follow-up, to post when the event has been over for a while.
+
+        It's written while the event is still on, when its meeting link is
+        known, and kept in the store so a restart doesn't lose it.
+        """
+        followup = event.schedule.followup if event.schedule else None
+        if not followup or self.bot.store.get(FOLLOWUPS, event.id):
+            return
+
+        guild_id = _guild_id(event)
+        notify_channel_id = await self.resolve_channel_id(_notify_channel(event), guild_id)
+        channel = self.bot.get_channel(notify_channel_id) if notify_channel_id else None
+        if not channel:
+            return
+        if is_private(event) and is_public(channel):
+            error = PublicChannelError(channel)
+            logger.error(
+                "Not scheduling the follow-up of %s: %s",
+                event.name,
+                error,
+                extra=_log_fields(event),
+            )
+            return
+
+        content = await self.render_template(event, followup.template)
+        due = followup_time(event, followup.delay_minutes)
+        self.bot.store.set(
+            FOLLOWUPS,
+            event.id,
+            {"due": due.isoformat(), "channel_id": channel.id, "content": content},
+        )
+        logger.info("Follow-up for %s scheduled at %s", event.name, due, extra=_log_fields(event))
+
+    async def send_due_followups(self) -> None:
+        """Post the follow-ups whose events have been over for their delay."""
+        now = datetime.now(ZoneInfo("UTC"))
+        for event_id, followup in self.bot.store.items(FOLLOWUPS).items():
+            due = datetime.fromisoformat(followup["due"])
+            if due > now:
+                continue
+            self.bot.store.delete(FOLLOWUPS, event_id)
+            if followup_stale(due, now):
+                logger.warning("Dropping the follow-up of %s, due at %s", event_id, due)
+                continue
+
+            channel = self.bot.get_channel(followup["channel_id"])
+            if not channel:
+                continue
+            content = followup["content"]
+            occurrence = self.bot.history.get(event_id)
+            if occurrence and occurrence["attendees"]:
+                content += f"\n\n👥 {len(occurrence['attendees'])} attended"
+            # Templates come from organizers; mentions in them are only text
+            await self.bot.messenger.send(
+                channel, content, allowed_mentions=discord.AllowedMentions.none()
+            )
+            logger.info("Sent follow-up for %s", event_id)
+
+    async def record_occurrence(self, event: CalendarEvent) -> None:
+        """Add a starting event to the history, with its RSVPs and who's already in the call."""
+        voice_channel_id = await self.resolve_channel_id(_voice_channel(event), _guild_id(event))
+        if not voice_channel_id:
+            return
+
+        channel = self.bot.get_channel(voice_channel_id)
+        present = [member.id for member in getattr(channel, "members", []) if not member.bot]
+        self.bot.history.start(
+            event.id,
+            event.name,
+            event.start_time,
+            event.end_time,
+            voice_channel_id,
+            interested=len(self.bot.interest.users(event.id)),
+            rsvps=self.bot.rsvps.get(event.id),
+            present=present,
+        )
+
+    async def record_experiment_results(self, event: CalendarEvent) -> None:
+        """Measure reactions and RSVPs on an experiment announcement once the event starts."""
+        announcement = self.bot.experiments.get(event.id)
+        if not announcement or "reactions" in announcement:
+            return
+
+        guild = self.bot.get_guild(_guild_id(event))
+        channel = self.bot.get_channel(announcement["channel_id"])
+        if not guild or not channel:
+            return
+
+        try:
+            message = await channel.fetch_message(announcement["message_id"])
+            discord_event = await guild.fetch_scheduled_event(
+                announcement["discord_event_id"], with_counts=True
+            )
+        except discord.HTTPException as e:
+            logger.error(
+                "Failed to measure announcement for %s: %s",
+                event.name,
+                e,
+                extra=_log_fields(event) | {"status": e.status},
+            )
+            return
+
+        # The bot's own reactions (e.g. RSVP prompts) aren't engagement
+        reactions = sum(reaction.count - reaction.me for reaction in message.reactions)
+        self.bot.experiments.record_results(event.id, reactions, discord_event.user_count or 0)
+
+    def _track_discord_event(
+        self,
+        event: CalendarEvent,
+        discord_event_id: int | None,
+        mirror: ScheduleMirror | None = None,
+        status: str = EventStatus.SCHEDULED,
+    ) -> None:
+        """Remember the Discord scheduled event created for a calendar event in a guild."""
+        tracked = {
+            "id": discord_event_id,
+            "status": status,
+            "start": event.start_time.isoformat(),
+            "end": event.end_time.isoformat(),
+        }
+        guild_id = mirror.guild_id if mirror else _guild_id(event)
+        if guild_id != settings.discord_guild_id:
+            tracked["guild"] = guild_id
+        self.bot.store.set(DISCORD_EVENTS, _tracking_key(event.id, mirror), tracked)
+
+    def _discord_event_status(self, event_id: str) -> str | None:
+        """Return the status of the Discord scheduled event for a calendar event."""
+        tracked = self.bot.store.get(DISCORD_EVENTS, event_id)
+        return tracked["status"] if tracked else None
+
+    def _set_discord_event_status(self, event_id: str, status: str) -> None:
+        """Update the locally known status of a Discord scheduled event."""
+        tracked = self.bot.store.get(DISCORD_EVENTS, event_id)
+        if tracked and tracked["status"] != status:
+            self.bot.store.set(DISCORD_EVENTS, event_id, tracked | {"status": status})
+            logger.info("Discord event for %s is now %s", event_id, status)
+
+    def _calendar_event_id(self, discord_event_id: int) -> str | None:
+        """Find the tracking key of the calendar event a Discord scheduled event was created for."""
+        for event_id, tracked in self.bot.store.items(DISCORD_EVENTS).items():
+            if tracked["id"] == discord_event_id:
+                return event_id
+        return None
+
+    def _forget_finished_discord_events(self) -> None:
+        """Drop what's kept about finished events: tracked events, failures, and notices sent."""
+        cutoff = datetime.now(ZoneInfo("UTC")) - DISCORD_EVENT_RETENTION
+        namespaces = (
+            DISCORD_EVENTS,
+            CREATE_FAILURES,
+            REMINDER_MESSAGES,
+            SENT_REMINDERS,
+            START_NOTIFICATIONS,
+            HOST_CHECKS,
+        )
+        for namespace in namespaces:
+            for key, tracked in self.bot.store.items(namespace).items():
+                if datetime.fromisoformat(tracked["end"]) < cutoff:
+                    self.bot.store.delete(namespace, key)
+
+    async def _fetch_scheduled_event(
+        self, discord_event_id: int, guild_id: int | None = None
+    ) -> discord.ScheduledEvent | None:
+        """Get a Discord scheduled event from the cache or the API."""
+        guild_id = guild_id or settings.discord_guild_id
+        guild = self.bot.get_guild(guild_id)
+        if not guild:
+            logger.error("Guild not found: %d", guild_id)
+            return None
+
+        scheduled = guild.get_scheduled_event(discord_event_id)
+        return scheduled or await guild.fetch_scheduled_event(discord_event_id)
+
+    async def update_discord_event_status(self, event: CalendarEvent) -> None:
+        """Start the event's Discord scheduled events at its start time and end them at its end."""
+        await self._update_discord_event_status(event, None)
+        for mirror in event.schedule.mirrors if event.schedule else []:
+            await self._update_discord_event_status(event, mirror)
+
+    async def _update_discord_event_status(
+        self, event: CalendarEvent, mirror: ScheduleMirror | None
+    ) -> None:
+        """Move the Discord scheduled event in one guild to the event's current status."""
+        key = _tracking_key(event.id, mirror)
+        tracked = self.bot.store.get(DISCORD_EVENTS, key)
+        if not tracked:
+            return
+
+        status = next_status(event, datetime.now(ZoneInfo("UTC")), tracked["status"])
+        if status is None:
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record("set event status", name=event.name, status=status)
+            self._set_discord_event_status(key, status)
+            return
+
+        try:
+            scheduled = await self._fetch_scheduled_event(tracked["id"], tracked.get("guild"))
+            if not scheduled:
+                return
+            if status == EventStatus.ACTIVE:
+                await scheduled.start(reason="Event start time reached")
+            else:
+                await scheduled.end(reason="Event end time reached")
+        except discord.NotFound:
+            logger.warning("Discord event for %s was deleted", event.name, extra=_log_fields(event))
+            status = EventStatus.CANCELED
+        except discord.HTTPException as e:
+            logger.error(
+                "Failed to set Discord event %s to %s: %s",
+                event.name,
+                status,
+                e,
+                extra=_log_fields(event) | {"status": e.status},
+            )
+            return
+
+        self._set_discord_event_status(key, status)
+
+    async def reschedule_discord_event(self, event: CalendarEvent) -> None:
+        """Move the event's Discord scheduled events if its time changed since they were created."""
+        await self._reschedule_discord_event(event, None)
+        for mirror in event.schedule.mirrors if event.schedule else []:
+            await self._reschedule_discord_event(event, mirror)
+
+    async def _reschedule_discord_event(
+        self, event: CalendarEvent, mirror: ScheduleMirror | None
+    ) -> None:
+        """Edit the start and end of the Discord scheduled event in one guild to the event's."""
+        key = _tracking_key(event.id, mirror)
+        tracked = self.bot.store.get(DISCORD_EVENTS, key)
+        # Recurring events follow their schedule's pattern instead, and events
+        # tracked before their start was aren't known to have moved
+        if not tracked or tracked["status"] != EventStatus.SCHEDULED or "start" not in tracked:
+            return
+        start = datetime.fromisoformat(tracked["start"])
+        end = datetime.fromisoformat(tracked["end"])
+        if start == event.start_time and end == event.end_time:
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record(
+                "edit scheduled event", name=event.name, start=event.start_time.isoformat()
+            )
+        else:
+            try:
+                scheduled = await self._fetch_scheduled_event(tracked["id"], tracked.get("guild"))
+                if scheduled:
+                    await scheduled.edit(
+                        start_time=event.start_time,
+                        end_time=event.end_time,
+                        reason="Event time changed",
+                    )
+            except discord.NotFound:
+                logger.warning(
+                    "Discord event for %s was deleted", event.name, extra=_log_fields(event)
+                )
+                self._set_discord_event_status(key, EventStatus.CANCELED)
+                return
+            except discord.HTTPException as e:
+                logger.error(
+                    "Failed to move Discord event %s: %s",
+                    event.name,
+                    e,
+                    extra=_log_fields(event) | {"status": e.status},
+                )
+                return
+
+        self.bot.store.set(
+            DISCORD_EVENTS,
+            key,
+            tracked | {"start": event.start_time.isoformat(), "end": event.end_time.isoformat()},
+        )
+        logger.info(
+            "Moved Discord event for %s to %s",
+            event.name,
+            event.start_time,
+            extra=_log_fields(event),
+        )
+
+    async def update_slowmode(self, events: list[CalendarEvent]) -> None:
+        """Slow down chat in the channels of live events, and restore it once they end."""
+        now = datetime.now(ZoneInfo("UTC"))
+        for event in events:
+            schedule = event.schedule
+            if not schedule or not schedule.slowmode_seconds:
+                continue
+            if not has_started(event, now) or now >= event.end_time:
+                continue
+
+            name = schedule.slowmode_channel or _notify_channel(event)
+            channel_id = await self.resolve_channel_id(name, _guild_id(event))
+            channel = channel_id and self.bot.get_channel(channel_id)
+            if not channel:
+                logger.error("Slowmode channel not found: %s", name)
+                continue
+            delay = schedule.slowmode_seconds
+            if self.bot.slowmode.start(channel.id, channel.slowmode_delay, delay, event.end_time):
+                await self._set_slowmode(channel, delay, f"{event.name} is live")
+
+        for channel_id, original in self.bot.slowmode.expired(now).items():
+            channel = self.bot.get_channel(channel_id)
+            # Kept until restored, so a failed edit is retried next time
+            if channel is None or await self._set_slowmode(channel, original, "Event ended"):
+                self.bot.slowmode.remove(channel_id)
+
+    async def _set_slowmode(self, channel: discord.TextChannel, delay: int, reason: str) -> bool:
+        """Change a channel's slowmode.
+
+        Returns:
+            True if the slowmode was changed.
+        """
+        if settings.observer_mode:
+            self.bot.observer.record("set slowmode", channel=channel.name, seconds=delay)
+            return True
+
+        try:
+            check_channel_permissions(channel, "manage_channels")
+            await channel.edit(slowmode_delay=delay, reason=reason)
+        except MissingPermissionsError as e:
+            logger.error("Can't set slowmode: %s", e)
+            return False
+        except discord.HTTPException as e:
+            logger.error("Failed to set slowmode in #%s: %s", channel.name, e)
+            return False
+
+        logger.info("Set slowmode in #%s to %ds: %s", channel.name, delay, reason)
+        return True
+
+    async def cancel_discord_event(
+        self, event_id: str, reason: str = "Cancelled in Google Calendar"
+    ) -> None:
+        """Cancel the Discord scheduled event for a calendar event that was cancelled."""
+        tracked = self.bot.store.get(DISCORD_EVENTS, event_id)
+        if not tracked or tracked["status"] != EventStatus.SCHEDULED:
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record(
+                "set event status", event=event_id, status=EventStatus.CANCELED
+            )
+        else:
+            try:
+                scheduled = await self._fetch_scheduled_event(tracked["id"], tracked.get("guild"))
+                if scheduled:
+                    await scheduled.cancel(reason=reason)
+            except discord.NotFound:
+                pass
+            except discord.HTTPException as e:
+                logger.error(
+                    "Failed to cancel Discord event for %s: %s",
+                    event_id,
+                    e,
+                    extra={"event_id": event_id, "status": e.status},
+                )
+                return
+
+        self._set_discord_event_status(event_id, EventStatus.CANCELED)
+        # Not created in observer mode, so there's no event to link to
+        guild_id = tracked.get("guild", settings.discord_guild_id)
+        link = ""
+        if tracked["id"]:
+            link = f" (<https://discord.com/events/{guild_id}/{tracked['id']}>)"
+        await self.bot.messenger.audit_log(
+            f"🚫 Cancelled the Discord event for `{event_id}`{link}: {reason}."
+        )
+
+    async def _drop_removed_occurrences(self, scheduled_ids: set[str]) -> None:
+        """Drop upcoming schedule occurrences no longer in the schedules file, cancelling them.
+
+        Removing or disabling a schedule, moving it to other days, skipping a
+        date with `!schedules skip`, or adding a holiday takes the occurrence
+        out of reminders and the digest, and cancels its Discord events.
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        for event in list(self.known_events.values()):
+            if event.schedule is None or is_submission(event):
+                continue
+            if event.id in scheduled_ids or event.start_time <= now:
+                continue
+            logger.info(
+                "%s on %s is no longer scheduled",
+                event.name,
+                event.start_time,
+                extra=_log_fields(event),
+            )
+            self.known_events.pop(event.id, None)
+            reason = "Removed from the schedules file"
+            await self.cancel_discord_event(event.id, reason=reason)
+            for mirror in event.schedule.mirrors:
+                await self.cancel_discord_event(_tracking_key(event.id, mirror), reason=reason)
+
+    async def _check_missing_events(self, fetched_ids: set[str]) -> None:
+        """Drop upcoming calendar events that vanished from the calendar, cancelling them."""
+        now = datetime.now(ZoneInfo("UTC"))
+        for event in list(self.known_events.values()):
+            if event.schedule is not None or is_submission(event):
+                continue
+            if event.id in fetched_ids or event.start_time <= now:
+                continue
+            if self.calendar.is_cancelled(event.id):
+                self.known_events.pop(event.id, None)
+                await self.cancel_discord_event(event.id)
+
+    @commands.Cog.listener("on_guild_channel_create")
+    @commands.Cog.listener("on_thread_create")
+    async def on_channel_create(self, channel: discord.abc.GuildChannel | discord.Thread) -> None:
+        """Resolve a new channel's name from now on."""
+        self.channels.update(channel)
+
+    @commands.Cog.listener("on_guild_channel_update")
+    @commands.Cog.listener("on_thread_update")
+    async def on_channel_update(
+        self,
+        before: discord.abc.GuildChannel | discord.Thread,
+        after: discord.abc.GuildChannel | discord.Thread,
+    ) -> None:
+        """Resolve a renamed channel by its new name only."""
+        if before.name != after.name:
+            self.channels.update(after)
+
+    @commands.Cog.listener("on_guild_channel_delete")
+    @commands.Cog.listener("on_thread_delete")
+    async def on_channel_delete(self, channel: discord.abc.GuildChannel | discord.Thread) -> None:
+        """Stop resolving a deleted channel's name."""
+        self.channels.remove(channel)
+
+    @commands.Cog.listener()
+    async def on_scheduled_event_update(
+        self, before: discord.ScheduledEvent, after: discord.ScheduledEvent
+    ) -> None:
+        """Follow status changes made by hand in the Discord UI."""
+        event_id = self._calendar_event_id(after.id)
+        if event_id is None or before.status == after.status:
+            return
+        if self._discord_event_status(event_id) == RECURRING:
+            return
+
+        self._set_discord_event_status(event_id, EventStatus(after.status.name))
+
+    @commands.Cog.listener()
+    async def on_scheduled_event_delete(self, scheduled: discord.ScheduledEvent) -> None:
+        """Treat a Discord event deleted by hand as cancelled."""
+        # A deleted recurring event is created again on the schedule's next occurrence
+        for key, series in self.bot.store.items(RECURRING_EVENTS).items():
+            if series["id"] == scheduled.id:
+                self.bot.store.delete(RECURRING_EVENTS, key)
+
+        event_id = self._calendar_event_id(scheduled.id)
+        if event_id is None:
+            return
+
+        self._set_discord_event_status(event_id, EventStatus.CANCELED)
+
+    @commands.Cog.listener()
+    async def on_scheduled_event_user_add(
+        self, scheduled: discord.ScheduledEvent, user: discord.User
+    ) -> None:
+        """Record a member marking a bot-created event as interested."""
+        key = self._calendar_event_id(scheduled.id)
+        if key is None:
+            return
+
+        # Interest in a mirrored event counts toward the calendar event
+        event_id = key.partition("@")[0]
+        event = self.known_events.get(event_id)
+        series = event.name if event else scheduled.name
+        self.bot.interest.add(event_id, series, scheduled.start_time, user.id)
 
-        await channel.send(msg, allowed_mentions=discord.AllowedMentions(everyone=True))
-        logger.info("Sent start notification for %s", event.name)
+    @commands.Cog.listener()
+    async def on_scheduled_event_user_remove(
+        self, scheduled: discord.ScheduledEvent, user: discord.User
+    ) -> None:
+        """Record a member no longer being interested in a bot-created event."""
+        key = self._calendar_event_id(scheduled.id)
+        if key is not None:
+            self.bot.interest.remove(key.partition("@")[0], user.id)
 
 
 async def setup(bot: commands.Bot) -> None:
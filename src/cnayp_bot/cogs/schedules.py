@@ -0,0 +1,575 @@
+"""Commands listing, adding, editing, and removing the recurring schedules."""
+
+import logging
+from datetime import date, datetime, time, timedelta
+from zoneinfo import ZoneInfo
+
+import aiohttp
+import discord
+from discord.ext import commands, tasks
+from pydantic import ValidationError
+
+from ..config import settings
+from ..helpers.chunking import MESSAGE_LIMIT
+from ..helpers.embeds import FIELD_NAME_LIMIT, EmbedBuilder
+from ..models import Schedule
+from ..scheduling import CONFLICT_LOOKAHEAD, find_conflicts, reminder_offsets
+from ..services.calendar import CalendarEvent
+from ..services.governor import Priority
+from ..services.holidays import fetch_holidays
+from ..services.schedule_sheet import COLUMNS, describe_changes, parse_row, schedule_cells
+from ..services.schedules import schedule_occurrences, schedule_when
+from .scheduler import voice_room
+
+logger = logging.getLogger(__name__)
+
+# How far ahead the next occurrence of each schedule is looked up, covering
+# monthly cron schedules
+NEXT_OCCURRENCE_DAYS = 35
+
+
+class SchedulesCog(commands.Cog):
+    """Lists, adds, edits, and removes the schedules in the schedules file.
+
+    Changes made here are saved to the schedules file. Schedules added here
+    are kept by sheet imports unless the sheet has a schedule of the same
+    name, and edits to imported schedules last until the sheet changes them.
+
+    Edits made to the file by hand are picked up every
+    `schedules_watch_seconds`, or right away with `!schedules reload`. A file
+    that doesn't load is reported in the staff channel and the schedules
+    loaded before are kept.
+
+    With several guilds, each guild's commands see and add only the schedules
+    that run in it.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.watch_loop.change_interval(seconds=settings.schedules_watch_seconds)
+        self.watch_loop.start()
+        self.holiday_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.watch_loop.cancel()
+        self.holiday_loop.cancel()
+
+    @tasks.loop(seconds=30)
+    async def watch_loop(self) -> None:
+        """Reload the schedules file when it's edited.
+
+        Every instance reloads, since each keeps its own copy of the
+        schedules; only the leader reports errors.
+        """
+        if not self.bot.schedules.changed_on_disk():
+            return
+
+        self.bot.governor.tag("schedules_watch", Priority.BACKGROUND)
+        try:
+            await self.reload()
+        except Exception as e:
+            logger.exception("Error in schedules watch loop: %s", e)
+
+    @watch_loop.before_loop
+    async def before_watch_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    @tasks.loop(hours=6)
+    async def holiday_loop(self) -> None:
+        """Download the holiday calendar, so no schedule runs on its days.
+
+        Every instance downloads it, since each keeps its own copy of the
+        schedules. If the download fails, the holidays downloaded before are kept.
+        """
+        url = self.bot.schedules.config.holiday_calendar_url
+        if not url:
+            self.bot.schedules.calendar_holidays = set()
+            return
+
+        try:
+            self.bot.schedules.calendar_holidays = await fetch_holidays(url)
+            logger.info("Loaded %d holidays", len(self.bot.schedules.calendar_holidays))
+        except aiohttp.ClientError as e:
+            logger.error("Failed to download the holiday calendar: %s", e)
+        except Exception as e:
+            logger.exception("Error in holiday loop: %s", e)
+
+    async def reload(self) -> tuple[list[str], str | None]:
+        """Load the schedules file again, reporting it in the staff channel if invalid.
+
+        Returns:
+            The changes loaded and, if the file is invalid, what's wrong with it.
+        """
+        before = self.bot.schedules.config.schedules
+        try:
+            self.bot.schedules.reload()
+        except (OSError, ValueError) as e:
+            error = _describe_error(e)
+            logger.error("Failed to reload %s: %s", settings.schedules_file, error)
+            if self.bot.leader.is_leader:
+                await self._report(error)
+                await self.bot.messenger.audit_log(
+                    f"⚠️ Didn't reload `{settings.schedules_file}`, it has errors; "
+                    "the schedules loaded before are still in use."
+                )
+            return [], error
+
+        changes = describe_changes(before, self.bot.schedules.config.schedules)
+        logger.info("Reloaded %s with %d changes", settings.schedules_file, len(changes))
+        if changes and self.bot.leader.is_leader:
+            await self.bot.messenger.audit_log(
+                f"🔄 Reloaded `{settings.schedules_file}` with {len(changes)} changes:\n"
+                + "\n".join(changes)
+            )
+            await self._warn_conflicts()
+        return changes, None
+
+    async def _warn_conflicts(self) -> None:
+        """Warn in the audit log about schedules overlapping in a voice channel soon."""
+        now = datetime.now(ZoneInfo("UTC"))
+        events = self.bot.schedules.get_events_between(now, now + CONFLICT_LOOKAHEAD)
+        conflicts = find_conflicts(events, voice_room)
+        if not conflicts:
+            return
+
+        days = CONFLICT_LOOKAHEAD.days
+        logger.warning("%d schedule occurrences overlap in the next %d days", len(conflicts), days)
+        await self.bot.messenger.audit_log(
+            f"⚠️ {len(conflicts)} schedule occurrences overlap in a voice channel in the next "
+            f"{days} days; both events of each are still created:\n"
+            + "\n".join(_conflict_line(event, other) for event, other in conflicts)
+        )
+
+    async def _report(self, error: str) -> None:
+        """Post a schedules file error in the staff channel, or the ops channel without one."""
+        name = (
+            settings.staff_channel
+            or settings.discord_ops_channel
+            or settings.discord_errors_channel
+        )
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = name and guild and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Staff channel not found: %s", name)
+            return
+
+        await self.bot.messenger.send_parts(
+            channel,
+            f"⚠️ **`{settings.schedules_file}` has errors, so the schedules loaded before are "
+            f"still in use:**\n{error}",
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+    @commands.hybrid_group(name="schedules", aliases=["schedule"])
+    @commands.guild_only()
+    async def schedules(self, ctx: commands.Context) -> None:
+        """List, add, edit, remove, and import the recurring schedules.
+
+        Usage: !schedules list | ical | create | edit | remove | skip | conflicts | reload | sync
+        """
+        await ctx.send_help(ctx.command)
+
+    @schedules.command(name="list")
+    async def schedules_list(self, ctx: commands.Context) -> None:
+        """List the recurring schedules and when each next runs.
+
+        Usage: !schedules list
+        """
+        schedules = [
+            schedule
+            for schedule in self.bot.schedules.config.schedules
+            if _in_guild(schedule, ctx.guild.id)
+        ]
+        if not schedules:
+            await ctx.send("No recurring schedules yet. Add one with `!schedules create`.")
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        end = now + timedelta(days=NEXT_OCCURRENCE_DAYS)
+        builder = (
+            EmbedBuilder()
+            .set_title(f"Schedules ({len(schedules)})")
+            .set_color(discord.Color.blue())
+        )
+        for schedule in schedules:
+            lines = [
+                f"{schedule_when(schedule)} ({schedule.timezone}), {schedule.duration_minutes} min"
+            ]
+            occurrences = schedule_occurrences(schedule, now, end, self.bot.schedules.holidays)
+            if not schedule.enabled:
+                lines.append("Disabled")
+            elif occurrences:
+                lines.append(f"Next: <t:{int(occurrences[0].start_time.timestamp())}:R>")
+            name = schedule.name[:FIELD_NAME_LIMIT]
+            value = "\n".join(lines)
+            if not builder.can_add_field(name, value):
+                break
+            builder.add_field(name=name, value=value, inline=False)
+
+        if builder.field_count < len(schedules):
+            builder.set_footer(text=f"Showing {builder.field_count} of {len(schedules)}")
+        await ctx.send(embed=builder.build())
+
+    @schedules.command(name="ical")
+    async def schedules_ical(self, ctx: commands.Context) -> None:
+        """Get the link to subscribe to the schedules from Google or Apple Calendar.
+
+        Usage: !schedules ical
+        """
+        if not settings.api_public_url:
+            await ctx.send("The calendar feed isn't set up.", ephemeral=True)
+            return
+
+        url = f"{settings.api_public_url.rstrip('/')}/calendar/{ctx.guild.id}.ics"
+        await ctx.send(
+            f"📅 Subscribe to the schedules in your calendar app with <{url}>\n"
+            "Google Calendar: Other calendars → + → From URL. "
+            "Apple Calendar: File → New Calendar Subscription.",
+            ephemeral=True,
+        )
+
+    @schedules.command(name="create", aliases=["add"])
+    @commands.has_permissions(manage_guild=True)
+    async def schedules_create(
+        self,
+        ctx: commands.Context,
+        name: str,
+        days: str,
+        time: str,
+        duration: int = 60,
+        *,
+        description: str = "",
+    ) -> None:
+        """Add a weekly schedule or a one-off event (requires Manage Server).
+
+        Schedules use the default channels and timezone.
+
+        Usage: !schedules create <name> <days or date> <time> [duration] [description]
+        Example: !schedules create "KCNA Study" monday,wednesday 19:00 90 Weekly study group
+        Example: !schedules create "Community Call" 2025-03-14 18:00 60 Quarterly AMA
+        """
+        config = self.bot.schedules.config
+        if any(schedule.name.lower() == name.lower() for schedule in config.schedules):
+            await ctx.send(f"❌ There's already a schedule named **{name}**.")
+            return
+
+        # Schedules of the primary guild leave their guild unset
+        guild_id = "" if ctx.guild.id == settings.discord_guild_id else str(ctx.guild.id)
+        try:
+            schedule = parse_row(
+                {
+                    "name": name,
+                    "days": days,
+                    "time": time,
+                    "duration_minutes": str(duration),
+                    "description": description,
+                    "guild_id": guild_id,
+                }
+            )
+        except ValueError as e:
+            await ctx.send(f"❌ {e}")
+            return
+
+        self.bot.schedules.replace(
+            config.model_copy(update={"schedules": [*config.schedules, schedule]}),
+            f"{ctx.author} added {schedule.name}",
+        )
+        logger.info("%s added the schedule %s", ctx.author, schedule.name)
+        await ctx.send(
+            f"✅ Added **{schedule.name}**: {schedule_when(schedule)} ({schedule.timezone}), "
+            f"announced in #{schedule.notify_channel}."
+        )
+
+    @schedules.command(name="edit")
+    @commands.has_permissions(manage_guild=True)
+    async def schedules_edit(
+        self, ctx: commands.Context, name: str, field: str, *, value: str = ""
+    ) -> None:
+        """Change one field of a schedule (requires Manage Server).
+
+        Fields are the schedule sheet's columns: description, voice_channel,
+        notify_channel, days, date, cron, time, every_weeks, starting, timezone,
+        duration, enabled, capacity, owners, co_hosts, and guild_id. An empty
+        value resets the field to its default.
+
+        Usage: !schedules edit <name> <field> [value]
+        Example: !schedules edit "KCNA Study" time 7:30 PM
+        """
+        schedule = self._find(name, ctx.guild.id)
+        if not schedule:
+            await ctx.send(f"❌ There's no schedule named **{name}**.")
+            return
+
+        key = COLUMNS.get(field.lower())
+        if not key or key == "name":
+            editable = ", ".join(sorted(column for column in COLUMNS if COLUMNS[column] != "name"))
+            await ctx.send(f"❌ Unknown field {field!r}, use one of: {editable}")
+            return
+
+        try:
+            parsed = parse_row(schedule_cells(schedule) | {key: value})
+        except ValueError as e:
+            await ctx.send(f"❌ {e}")
+            return
+
+        updated = schedule.model_copy(update={key: getattr(parsed, key)})
+        self._save(
+            [updated if s is schedule else s for s in self.bot.schedules.config.schedules],
+            f"{ctx.author} set {key} of {schedule.name}",
+        )
+        logger.info("%s set %s of the schedule %s", ctx.author, key, schedule.name)
+        await ctx.send(f"✅ Set {key} of **{schedule.name}** to `{getattr(updated, key)}`.")
+
+    @schedules.command(name="remove", aliases=["delete"])
+    @commands.has_permissions(manage_guild=True)
+    async def schedules_remove(self, ctx: commands.Context, *, name: str) -> None:
+        """Remove a schedule (requires Manage Server).
+
+        Usage: !schedules remove <name>
+        Example: !schedules remove KCNA Study
+        """
+        schedule = self._find(name, ctx.guild.id)
+        if not schedule:
+            await ctx.send(f"❌ There's no schedule named **{name}**.")
+            return
+
+        self._save(
+            [s for s in self.bot.schedules.config.schedules if s is not schedule],
+            f"{ctx.author} removed {schedule.name}",
+        )
+        logger.info("%s removed the schedule %s", ctx.author, schedule.name)
+        message = f"✅ Removed **{schedule.name}**."
+        if schedule.name.lower() in self.bot.imported_schedules.names:
+            message += " It's in the schedule sheet, so delete it there too or it comes back."
+        await ctx.send(message)
+
+    @schedules.command(name="skip", aliases=["cancel"])
+    @commands.has_permissions(manage_guild=True)
+    async def schedules_skip(self, ctx: commands.Context, name: str, day: str) -> None:
+        """Skip one day of a schedule, e.g. a holiday (requires Manage Server).
+
+        Nothing is announced or reminded that day, and the Discord event is
+        cancelled if it was already created.
+
+        Usage: !schedules skip <name> <YYYY-MM-DD>
+        Example: !schedules skip "KCNA Study" 2025-12-24
+        """
+        schedule = self._find(name, ctx.guild.id)
+        if not schedule:
+            await ctx.send(f"❌ There's no schedule named **{name}**.")
+            return
+
+        try:
+            skipped = date.fromisoformat(day)
+        except ValueError:
+            await ctx.send(f"❌ {day!r} isn't a date, write it like 2025-12-24.")
+            return
+        if skipped in schedule.exclude_dates:
+            await ctx.send(f"**{schedule.name}** is already skipped on {skipped.isoformat()}.")
+            return
+
+        tz = ZoneInfo(schedule.timezone)
+        day_start = datetime.combine(skipped, time.min, tzinfo=tz)
+        runs = any(
+            event.start_time.astimezone(tz).date() == skipped
+            for event in schedule_occurrences(schedule, day_start, day_start + timedelta(days=1))
+        )
+        if skipped < datetime.now(tz).date() or not runs:
+            await ctx.send(f"❌ **{schedule.name}** doesn't run on {skipped.isoformat()}.")
+            return
+
+        updated = schedule.model_copy(
+            update={"exclude_dates": sorted([*schedule.exclude_dates, skipped])}
+        )
+        self._save(
+            [updated if s is schedule else s for s in self.bot.schedules.config.schedules],
+            f"{ctx.author} skipped {schedule.name} on {skipped.isoformat()}",
+        )
+        logger.info("%s skipped %s of the schedule %s", ctx.author, skipped, schedule.name)
+        await ctx.send(f"✅ Skipping **{schedule.name}** on {skipped.isoformat()}.")
+
+    @schedules.command(name="conflicts")
+    async def schedules_conflicts(self, ctx: commands.Context) -> None:
+        """List the events overlapping in the same voice channel in the next two weeks.
+
+        Covers the schedules, Google Calendar, and approved submissions.
+
+        Usage: !schedules conflicts
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        end = now + CONFLICT_LOOKAHEAD
+        events = [
+            *self.bot.schedules.get_events_between(now, end),
+            *self.bot.calendar.get_events_between(now, end),
+            *self.bot.submissions.get_events_between(now, end),
+        ]
+        conflicts = [
+            (event, other)
+            for event, other in find_conflicts(events, voice_room)
+            if voice_room(event)[0] == ctx.guild.id
+        ]
+        days = CONFLICT_LOOKAHEAD.days
+        if not conflicts:
+            await ctx.send(f"✅ No events overlap in a voice channel in the next {days} days.")
+            return
+
+        lines = [f"⚠️ {len(conflicts)} overlapping events in the next {days} days:"]
+        lines += [_conflict_line(event, other) for event, other in conflicts]
+        await ctx.send(
+            "\n".join(lines)[:MESSAGE_LIMIT], allowed_mentions=discord.AllowedMentions.none()
+        )
+
+    @schedules.command(name="reload")
+    @commands.has_permissions(manage_guild=True)
+    async def schedules_reload(self, ctx: commands.Context) -> None:
+        """Reload the schedules file after editing it (requires Manage Server).
+
+        Usage: !schedules reload
+        """
+        changes, error = await self.reload()
+        if error:
+            await ctx.send(
+                f"❌ The schedules file has errors, nothing was changed:\n{error}"[:MESSAGE_LIMIT],
+                allowed_mentions=discord.AllowedMentions.none(),
+            )
+        elif changes:
+            await ctx.send(
+                "\n".join([f"✅ Reloaded {len(changes)} changes:", *changes])[:MESSAGE_LIMIT],
+                allowed_mentions=discord.AllowedMentions.none(),
+            )
+        else:
+            await ctx.send("✅ The schedules file hasn't changed.")
+
+    @schedules.command(name="sync")
+    @commands.has_permissions(manage_guild=True)
+    async def schedules_sync(self, ctx: commands.Context) -> None:
+        """Import the schedule sheet now instead of waiting (requires Manage Server).
+
+        Usage: !schedules sync
+        """
+        sheet = self.bot.get_cog("ScheduleSheetCog")
+        if not settings.schedule_sheet_url or not sheet:
+            await ctx.send("No schedule sheet is configured.")
+            return
+
+        try:
+            changes, errors = await sheet.sync()
+        except aiohttp.ClientError as e:
+            await ctx.send(f"❌ Couldn't download the sheet: {e}")
+            return
+
+        if errors:
+            await ctx.send(f"❌ The sheet has {len(errors)} errors, nothing was changed.")
+        elif changes:
+            await ctx.send(f"✅ Applied {len(changes)} changes from the sheet.")
+        else:
+            await ctx.send("✅ The schedules already match the sheet.")
+
+    @commands.hybrid_command(name="preview")
+    @commands.guild_only()
+    @commands.has_permissions(manage_guild=True)
+    async def preview(self, ctx: commands.Context, *, schedule: str) -> None:
+        """Preview the next announcement, reminders, and digest entry (requires Manage Server).
+
+        They're rendered with the current templates, partials, channels, and
+        RSVPs, and only shown to you as the slash command.
+
+        Usage: !preview <schedule>
+        Example: !preview KCNA Study
+        """
+        found = self._find(schedule, ctx.guild.id)
+        if found is None:
+            await ctx.send(f"❌ No schedule named **{schedule}**.", ephemeral=True)
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        occurrences = schedule_occurrences(
+            found, now, now + timedelta(days=NEXT_OCCURRENCE_DAYS), self.bot.schedules.holidays
+        )
+        scheduler = self.bot.get_cog("SchedulerCog")
+        if not occurrences or not scheduler:
+            await ctx.send(
+                f"**{found.name}** doesn't run in the next {NEXT_OCCURRENCE_DAYS} days.",
+                ephemeral=True,
+            )
+            return
+
+        event = occurrences[0]
+        announcements = await scheduler.preview_announcements(event)
+        for variant, announcement in enumerate(announcements):
+            label = f" (variant {'AB'[variant]})" if len(announcements) > 1 else ""
+            heading = f"**📣 Announcement{label} in #{found.notify_channel}:**"
+            await ctx.send(
+                f"{heading}\n{announcement}"[:MESSAGE_LIMIT],
+                allowed_mentions=discord.AllowedMentions.none(),
+                ephemeral=True,
+            )
+
+        offsets = sorted(reminder_offsets(event, settings.reminder_minutes), reverse=True)
+        before = ", ".join(str(minutes) for minutes in offsets)
+        heading = f"**⏰ Reminders ({before} min before):**"
+        reminders = []
+        if found.reminder_template:
+            heading += f"\n{await scheduler.render_template(event, found.reminder_template)}"
+        else:
+            reminders = [
+                await scheduler.reminder_embed([event], minutes, found.guild_id)
+                for minutes in offsets
+            ]
+        digest = self.bot.get_cog("DigestCog")
+        digest_line = f"\n**🗓️ Digest entry:**\n{digest.digest_line(event)}" if digest else ""
+        await ctx.send(
+            f"{heading}{digest_line}"[:MESSAGE_LIMIT],
+            embeds=reminders[:10],
+            allowed_mentions=discord.AllowedMentions.none(),
+            ephemeral=True,
+        )
+
+    def _find(self, name: str, guild_id: int) -> Schedule | None:
+        """Return a schedule of a guild by name, ignoring case."""
+        for schedule in self.bot.schedules.config.schedules:
+            if schedule.name.lower() == name.lower() and _in_guild(schedule, guild_id):
+                return schedule
+        return None
+
+    def _save(self, schedules: list[Schedule], reason: str) -> None:
+        """Replace the schedules and write them to the schedules file."""
+        config = self.bot.schedules.config
+        self.bot.schedules.replace(config.model_copy(update={"schedules": schedules}), reason)
+
+
+def _in_guild(schedule: Schedule, guild_id: int) -> bool:
+    """Check whether a schedule's events run in a guild."""
+    return (schedule.guild_id or settings.discord_guild_id) == guild_id
+
+
+def _conflict_line(event: CalendarEvent, other: CalendarEvent) -> str:
+    """Describe two events overlapping in a voice channel, in one line."""
+    return (
+        f"• **{event.name}** {_time_range(event)} and **{other.name}** {_time_range(other)} "
+        f"in 🔊 {voice_room(event)[1]}"
+    )
+
+
+def _time_range(event: CalendarEvent) -> str:
+    """Format when an event runs in each reader's local time."""
+    return f"<t:{int(event.start_time.timestamp())}:f>–<t:{int(event.end_time.timestamp())}:t>"
+
+
+def _describe_error(error: OSError | ValueError) -> str:
+    """List what's wrong with a schedules file that failed to load, one line each."""
+    if isinstance(error, ValidationError):
+        return "\n".join(
+            f"- {'.'.join(str(part) for part in e['loc'])}: {e['msg']}" for e in error.errors()
+        )
+    return f"- {error}"
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the schedules cog."""
+    await bot.add_cog(SchedulesCog(bot))
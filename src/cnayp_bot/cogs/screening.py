@@ -0,0 +1,124 @@
+"""Membership screening: holding back onboarding until new members accept the rules."""
+
+import logging
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..services.guild_cache import guild_members
+
+logger = logging.getLogger(__name__)
+
+# Most pending members listed by !screening
+MAX_LISTED = 20
+
+# Member ID -> {"joined": ...}, for members of the primary guild still pending screening
+PENDING_MEMBERS = "screening_pending"
+
+
+class ScreeningCog(commands.Cog):
+    """Tells the other cogs when new members pass membership screening.
+
+    In guilds with membership screening, members join as pending and can't
+    talk or see most channels until they accept the rules. The welcome DMs
+    and the verification gate listen for `on_member_screened` instead of
+    `on_member_join`, dispatched once a member accepts, or right away for
+    members who join without screening. Pending members are kept in the
+    store, so those who accept while the bot is offline are passed on when
+    it's ready again.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.Cog.listener()
+    async def on_member_join(self, member: discord.Member) -> None:
+        """Pass on members who joined without screening."""
+        if member.bot or member.guild.id != settings.discord_guild_id:
+            return
+        if member.pending:
+            logger.info("%s joined and is pending membership screening", member)
+            self._remember(member)
+            return
+        self.bot.dispatch("member_screened", member)
+
+    @commands.Cog.listener()
+    async def on_member_update(self, before: discord.Member, after: discord.Member) -> None:
+        """Pass on members who just accepted the rules."""
+        if after.bot or after.guild.id != settings.discord_guild_id:
+            return
+        if before.pending and not after.pending:
+            logger.info("%s passed membership screening", after)
+            self.bot.store.delete(PENDING_MEMBERS, str(after.id))
+            self.bot.dispatch("member_screened", after)
+
+    @commands.Cog.listener()
+    async def on_ready(self) -> None:
+        """Pass on members who accepted the rules while the bot was offline.
+
+        Members who joined while it was offline and are still pending are
+        remembered from now on; those who joined and accepted meanwhile can't
+        be told apart from earlier members, so they aren't passed on.
+        """
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if not guild:
+            return
+
+        members = {member.id: member for member in await guild_members(guild) if not member.bot}
+        for member_id in self.bot.store.items(PENDING_MEMBERS):
+            member = members.get(int(member_id))
+            if member and member.pending:
+                continue
+            self.bot.store.delete(PENDING_MEMBERS, member_id)
+            if member:
+                logger.info("%s passed membership screening while the bot was offline", member)
+                self.bot.dispatch("member_screened", member)
+
+        for member in members.values():
+            if member.pending:
+                self._remember(member)
+
+    def _remember(self, member: discord.Member) -> None:
+        """Keep a pending member in the store until they accept the rules or leave."""
+        if self.bot.store.get(PENDING_MEMBERS, str(member.id)) is None:
+            joined = member.joined_at or discord.utils.utcnow()
+            self.bot.store.set(PENDING_MEMBERS, str(member.id), {"joined": joined.isoformat()})
+
+    @commands.hybrid_command(name="screening")
+    @commands.guild_only()
+    @commands.has_permissions(manage_guild=True)
+    async def screening(self, ctx: commands.Context) -> None:
+        """List the members who haven't accepted the rules yet (requires Manage Server).
+
+        Usage: !screening
+        """
+        pending = sorted(
+            (
+                member
+                for member in await guild_members(ctx.guild)
+                if member.pending and not member.bot
+            ),
+            key=lambda member: member.joined_at or discord.utils.utcnow(),
+        )
+        if not pending:
+            await ctx.send("✅ Nobody is waiting on membership screening.")
+            return
+
+        lines = [
+            f"• {member.mention}, joined <t:{int(member.joined_at.timestamp())}:R>"
+            if member.joined_at
+            else f"• {member.mention}"
+            for member in pending[:MAX_LISTED]
+        ]
+        if len(pending) > MAX_LISTED:
+            lines.append(f"…and {len(pending) - MAX_LISTED} more")
+        await ctx.send(
+            f"**{len(pending)} members haven't accepted the rules yet:**\n" + "\n".join(lines),
+            allowed_mentions=discord.AllowedMentions.none(),
+        )
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the screening cog."""
+    await bot.add_cog(ScreeningCog(bot))
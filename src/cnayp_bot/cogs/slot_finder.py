@@ -0,0 +1,266 @@
+"""/findtime polls that find a time the members of a role can meet."""
+
+import logging
+from datetime import datetime, timedelta
+from typing import Annotated
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.converters import Duration
+from ..helpers.embeds import EmbedBuilder
+from ..models import EventSubmission
+from ..services.slot_finder import SLOTS_PER_DAY, propose_slots, rank_slots
+from .reminders import user_timezone
+
+logger = logging.getLogger(__name__)
+
+# Component handlers for the slot buttons, the results button, and the create buttons
+SLOT = "slot"
+SLOT_RESULTS = "slot_results"
+SLOT_CREATE = "slot_create"
+
+# Slot buttons on a poll, leaving the last row of five for the results button
+MAX_SLOTS = 20
+
+# Best slots shown in the results, each with a button to create the event
+TOP_SLOTS = 3
+
+
+class SlotFinderCog(commands.Cog):
+    """Finds a time the members of a role can meet.
+
+    /findtime proposes the slots that fall within waking hours for the most
+    members, going by the timezones they set with !timezone. Members press
+    the buttons of the slots they can make, and the organizer checks the best
+    slots and can create a one-off event from one of them, through the
+    scheduler's `create_one_off`.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(SLOT, self.toggle_slot)
+        self.bot.components.register(SLOT_RESULTS, self.show_results)
+        self.bot.components.register(SLOT_CREATE, self.create_event)
+
+    @commands.hybrid_command(name="findtime")
+    @commands.guild_only()
+    @commands.has_permissions(manage_events=True)
+    async def findtime(
+        self,
+        ctx: commands.Context,
+        role: discord.Role,
+        duration: Annotated[timedelta, Duration],
+        *,
+        name: str = "",
+    ) -> None:
+        """Find a time the members of a role can meet (requires Manage Events).
+
+        Usage: !findtime <role> <duration> [event name]
+        Example: !findtime @SIG-Security 60m Threat modeling session
+        """
+        if not timedelta(minutes=15) <= duration <= timedelta(hours=12):
+            await ctx.send("❌ Meetings can last between 15 minutes and 12 hours.")
+            return
+
+        members = [member for member in role.members if not member.bot]
+        if not members:
+            await ctx.send(f"❌ Nobody has the **{role.name}** role.")
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        self.bot.slot_finder.forget_finished(now)
+        slots = propose_slots(
+            [user_timezone(self.bot, member.id) for member in members],
+            now,
+            duration,
+            settings.slot_finder_days,
+            min(MAX_SLOTS, settings.slot_finder_days * SLOTS_PER_DAY),
+        )
+        if not slots:
+            await ctx.send("❌ No slot falls within waking hours for any member.")
+            return
+
+        poll_id = self.bot.slot_finder.create(
+            name or f"{role.name} meeting", role.id, ctx.author.id, ctx.channel.id, duration, slots
+        )
+        poll = self.bot.slot_finder.get(poll_id)
+        message = await self.bot.messenger.send(
+            ctx.channel,
+            role.mention,
+            embed=self._build_embed(poll),
+            view=self._build_view(poll_id, len(slots)),
+            allowed_mentions=discord.AllowedMentions(roles=[role]),
+        )
+        if not message:
+            await ctx.send("❌ Couldn't post the poll here.")
+            return
+
+        self.bot.slot_finder.set_message(poll_id, message.id)
+        logger.info("%s started a time poll for %s", ctx.author, role.name)
+        if ctx.interaction:
+            await ctx.send("The poll is up.", ephemeral=True)
+
+    async def toggle_slot(self, interaction: discord.Interaction, payload: str) -> None:
+        """Mark the member as available for a slot, or unmark them."""
+        poll_id, _, index = payload.partition(":")
+        poll = self.bot.slot_finder.get(poll_id)
+        if not poll:
+            await interaction.response.send_message("This poll is over.", ephemeral=True)
+            return
+
+        if not any(role.id == poll["role_id"] for role in interaction.user.roles):
+            await interaction.response.send_message(
+                "This poll is for the members of another role.", ephemeral=True
+            )
+            return
+
+        available = self.bot.slot_finder.toggle(poll_id, int(index), interaction.user.id)
+        poll = self.bot.slot_finder.get(poll_id)
+        embed = interaction.message.embeds[0]
+        await interaction.response.edit_message(
+            embed=embed.set_field_at(0, name="Slots", value=_slot_lines(poll), inline=False)
+        )
+        start = datetime.fromisoformat(poll["slots"][int(index)])
+        verdict = "can" if available else "can't"
+        await interaction.followup.send(
+            f"Noted, you {verdict} make <t:{int(start.timestamp())}:F>.", ephemeral=True
+        )
+
+    async def show_results(self, interaction: discord.Interaction, payload: str) -> None:
+        """Show the organizer the best slots, with buttons to create the event."""
+        poll = self.bot.slot_finder.get(payload)
+        if not poll:
+            await interaction.response.send_message("This poll is over.", ephemeral=True)
+            return
+        if not _can_organize(interaction, poll):
+            await interaction.response.send_message(
+                "Only the organizer can see the results.", ephemeral=True
+            )
+            return
+
+        ranked = rank_slots(poll["available"])[:TOP_SLOTS]
+        if not ranked:
+            await interaction.response.send_message("Nobody has answered yet.", ephemeral=True)
+            return
+
+        lines = []
+        view = discord.ui.View(timeout=None)
+        for place, index in enumerate(ranked, start=1):
+            start = datetime.fromisoformat(poll["slots"][index])
+            people = ", ".join(f"<@{user_id}>" for user_id in poll["available"][index])
+            lines.append(f"**{place}.** <t:{int(start.timestamp())}:F>: {people}")
+            if not poll["submission_id"]:
+                view.add_item(
+                    self.bot.components.button(
+                        SLOT_CREATE,
+                        f"{payload}:{index}",
+                        label=f"Create event at #{place}",
+                        emoji="📅",
+                        style=discord.ButtonStyle.success,
+                    )
+                )
+        if poll["submission_id"]:
+            lines.append("\nThe event was already created.")
+        await interaction.response.send_message("\n".join(lines), view=view, ephemeral=True)
+
+    async def create_event(self, interaction: discord.Interaction, payload: str) -> None:
+        """Create a one-off event at one of the best slots."""
+        poll_id, _, index = payload.partition(":")
+        poll = self.bot.slot_finder.get(poll_id)
+        if not poll:
+            await interaction.response.send_message("This poll is over.", ephemeral=True)
+            return
+        if not _can_organize(interaction, poll):
+            await interaction.response.send_message(
+                "Only the organizer can create the event.", ephemeral=True
+            )
+            return
+        if poll["submission_id"]:
+            await interaction.response.send_message(
+                "The event was already created.", ephemeral=True
+            )
+            return
+
+        submission = EventSubmission(
+            name=poll["name"],
+            time=datetime.fromisoformat(poll["slots"][int(index)]),
+            duration=poll["duration"],
+            channel=settings.discord_voice_channel,
+            submitted_by=str(interaction.user),
+        )
+        await interaction.response.defer()
+        try:
+            submission_id = await self.bot.get_cog("SchedulerCog").create_one_off(submission)
+        except ValueError as e:
+            await interaction.edit_original_response(content=f"❌ {e}", view=None)
+            return
+        self.bot.slot_finder.set_submission(poll_id, submission_id)
+        logger.info("%s created %s from a time poll", interaction.user, submission.name)
+
+        start = int(submission.time.timestamp())
+        await interaction.edit_original_response(
+            content=f"✅ Created **{submission.name}** at <t:{start}:F>.", view=None
+        )
+        channel = self.bot.get_channel(poll["channel_id"])
+        if channel:
+            await self.bot.messenger.send(
+                channel,
+                f"📅 **{submission.name}** is on for <t:{start}:F>, in {submission.channel}.",
+                reference=channel.get_partial_message(poll["message_id"]),
+            )
+
+    def _build_embed(self, poll: dict) -> discord.Embed:
+        """Build the poll embed, listing the slots."""
+        return (
+            EmbedBuilder()
+            .set_title(f"🗓️ {poll['name']}")
+            .set_description(
+                f"When can you meet for {poll['duration']} minutes? Press the number of "
+                "every slot you can make, and again to take it back. Times are shown in "
+                "your own timezone."
+            )
+            .set_color(discord.Color.blurple())
+            .add_field(name="Slots", value=_slot_lines(poll), inline=False)
+            .build()
+        )
+
+    def _build_view(self, poll_id: str, slots: int) -> discord.ui.View:
+        """Build a button per slot, and the organizer's results button."""
+        view = discord.ui.View(timeout=None)
+        for index in range(slots):
+            view.add_item(
+                self.bot.components.button(SLOT, f"{poll_id}:{index}", label=str(index + 1))
+            )
+        results = self.bot.components.button(
+            SLOT_RESULTS, poll_id, label="Results", emoji="📊", style=discord.ButtonStyle.primary
+        )
+        results.row = 4
+        view.add_item(results)
+        return view
+
+
+def _slot_lines(poll: dict) -> str:
+    """List the slots with how many members can make each."""
+    return "\n".join(
+        f"**{index}.** <t:{int(datetime.fromisoformat(slot).timestamp())}:f> "
+        f"({len(available)} can make it)"
+        for index, (slot, available) in enumerate(
+            zip(poll["slots"], poll["available"], strict=True), start=1
+        )
+    )
+
+
+def _can_organize(interaction: discord.Interaction, poll: dict) -> bool:
+    """Check whether a user started the poll or can manage events."""
+    return interaction.user.id == poll["organizer_id"] or interaction.permissions.manage_events
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the slot finder cog."""
+    await bot.add_cog(SlotFinderCog(bot))
@@ -0,0 +1,89 @@
+"""Scheduled sponsor posts."""
+
+import logging
+from datetime import date, datetime, time
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..services.in_flight import drained
+from ..services.schedules import WEEKDAYS
+from ..services.sponsors import LAST_POST, SPONSORS, sponsor_line
+
+logger = logging.getLogger(__name__)
+
+
+class SponsorsCog(commands.Cog):
+    """Posts the next sponsor's blurb on the configured days.
+
+    Blurbs appended to event announcements are handled by the scheduler;
+    both share one rotation, and impressions show up in /stats.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        sponsorship = self.bot.schedules.config.sponsorship
+        if not sponsorship.sponsors or not sponsorship.channel or not sponsorship.time:
+            logger.info("Sponsor channel or time not configured, sponsor posts disabled")
+            return
+
+        self.post_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.post_loop.cancel()
+
+    @tasks.loop(minutes=1)
+    @drained("sponsor posts")
+    async def post_loop(self) -> None:
+        """Post the next sponsor's blurb once on each configured day."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("sponsors")
+        try:
+            sponsorship = self.bot.schedules.config.sponsorship
+            now = datetime.now(ZoneInfo(settings.default_timezone))
+            last_post = self.bot.store.get(SPONSORS, LAST_POST)
+            if last_post and date.fromisoformat(last_post) == now.date():
+                return
+            if WEEKDAYS[now.weekday()] not in {day.lower() for day in sponsorship.days}:
+                return
+            hour, minute = (int(part) for part in sponsorship.time.split(":"))
+            if now.time() < time(hour, minute):
+                return
+
+            await self.post(now)
+        except Exception as e:
+            logger.exception("Error in sponsor post loop: %s", e)
+
+    @post_loop.before_loop
+    async def before_post_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def post(self, now: datetime) -> None:
+        """Post the next sponsor's blurb in the sponsor channel."""
+        sponsorship = self.bot.schedules.config.sponsorship
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=sponsorship.channel)
+        if not channel:
+            logger.error("Sponsor channel not found: %s", sponsorship.channel)
+            return
+
+        # Only try once a day, even if sending fails
+        self.bot.store.set(SPONSORS, LAST_POST, now.date().isoformat())
+        sponsor = self.bot.sponsors.next(sponsorship.sponsors)
+        if await self.bot.messenger.send(channel, sponsor_line(sponsor)):
+            self.bot.sponsors.record_impression(sponsor, sponsorship.sponsors)
+            logger.info("Posted sponsor blurb for %s", sponsor.name)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the sponsors cog."""
+    await bot.add_cog(SponsorsCog(bot))
@@ -0,0 +1,87 @@
+"""Engagement statistics for organizers."""
+
+import discord
+from discord.ext import commands
+
+from ..helpers.embeds import FIELD_NAME_LIMIT, EmbedBuilder
+
+# Most recent occurrences shown per series
+RECENT_OCCURRENCES = 5
+
+
+class StatsCog(commands.Cog):
+    """Reports how members respond to events and announcements."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    @commands.hybrid_command(name="stats")
+    @commands.guild_only()
+    async def stats(self, ctx: commands.Context) -> None:
+        """Show interest per event series, announcement experiment results, and sponsor impressions.
+
+        Usage: !stats
+        """
+        fields = self._interest_fields() + self._experiment_fields() + self._sponsor_fields()
+        if not fields:
+            await ctx.send("No stats yet. Interest is counted once events are created.")
+            return
+
+        builder = (
+            EmbedBuilder()
+            .set_title("Event Stats")
+            .set_description(
+                "Interested members per occurrence, and announcement variants averaged "
+                "per announcement when the event starts."
+            )
+            .set_color(discord.Color.purple())
+        )
+
+        for name, value in fields:
+            if not builder.can_add_field(name, value):
+                break
+            builder.add_field(name=name, value=value)
+
+        if builder.field_count < len(fields):
+            builder.set_footer(text=f"Showing {builder.field_count} of {len(fields)} sections")
+
+        await ctx.send(embed=builder.build())
+
+    def _interest_fields(self) -> list[tuple[str, str]]:
+        """Build one field per series with its recent interest counts."""
+        fields = []
+        for series, occurrences in sorted(self.bot.interest.series_counts().items()):
+            recent = occurrences[-RECENT_OCCURRENCES:]
+            counts = " · ".join(f"{start:%b %d}: {count}" for start, count in recent)
+            average = sum(count for _, count in occurrences) / len(occurrences)
+            value = f"{counts}\nAverage: {average:.1f} over {len(occurrences)} occurrences"
+            fields.append((f"⭐ {series}"[:FIELD_NAME_LIMIT], value))
+        return fields
+
+    def _experiment_fields(self) -> list[tuple[str, str]]:
+        """Build one field per schedule with its A/B variant results."""
+        fields = []
+        for schedule_name, variants in sorted(self.bot.experiments.summary().items()):
+            value = "\n".join(
+                f"**{stats.label}:** {stats.reaction_rate:.1f} reactions, "
+                f"{stats.rsvp_rate:.1f} RSVPs ({stats.measured} of {stats.announcements} measured)"
+                for stats in variants
+            )
+            fields.append((f"🧪 {schedule_name}"[:FIELD_NAME_LIMIT], value))
+        return fields
+
+    def _sponsor_fields(self) -> list[tuple[str, str]]:
+        """Build a field with how often each sponsor was shown."""
+        impressions = self.bot.sponsors.impressions()
+        if not impressions:
+            return []
+        value = "\n".join(
+            f"**{name}:** {count} impressions"
+            for name, count in sorted(impressions.items(), key=lambda item: -item[1])
+        )
+        return [("🤝 Sponsors", value)]
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the stats cog."""
+    await bot.add_cog(StatsCog(bot))
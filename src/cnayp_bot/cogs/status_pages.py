@@ -0,0 +1,137 @@
+"""Incident notices from status pages, including Discord's own."""
+
+import logging
+
+import aiohttp
+import discord
+from discord.ext import commands, tasks
+
+from ..config import StatusPage, settings
+from ..helpers.embeds import DESCRIPTION_LIMIT, FIELD_VALUE_LIMIT, TITLE_LIMIT, EmbedBuilder
+from ..services.governor import Priority
+from ..services.in_flight import drained
+from ..services.statuspage import (
+    Change,
+    Incident,
+    fetch_incidents,
+    is_api_incident,
+    is_discord_page,
+)
+
+logger = logging.getLogger(__name__)
+
+IMPACT_COLORS = {
+    "critical": discord.Color.red,
+    "major": discord.Color.orange,
+    "minor": discord.Color.gold,
+    "maintenance": discord.Color.blue,
+}
+CHANGE_EMOJI = {"started": "🚨", "updated": "🔄", "resolved": "✅"}
+
+
+class StatusPagesCog(commands.Cog):
+    """Polls the configured status pages and posts incident notices.
+
+    While Discord's status page reports an incident affecting its API or
+    gateway, `bot.incidents.discord_incident` is set, and the scheduler and
+    watchdog hold back their alerts: failures then are Discord's, not ours.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.status_pages:
+            logger.info("No status pages configured, incident notices disabled")
+            return
+
+        self.status_loop.change_interval(minutes=settings.status_poll_minutes)
+        self.status_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.status_loop.cancel()
+
+    @tasks.loop(minutes=2)
+    @drained("status pages")
+    async def status_loop(self) -> None:
+        """Check every status page for new incidents and updates."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("status_pages", Priority.BACKGROUND)
+        for name, page in settings.status_pages.items():
+            try:
+                incidents = await fetch_incidents(page)
+            except (aiohttp.ClientError, TimeoutError, ValueError) as e:
+                logger.warning("Failed to fetch status page %s: %s", name, e)
+                continue
+
+            try:
+                await self.check_page(name, page, incidents)
+            except Exception as e:
+                logger.exception("Error checking status page %s: %s", name, e)
+
+    @status_loop.before_loop
+    async def before_status_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+        logger.info("Status page loop started for %s", ", ".join(settings.status_pages))
+
+    async def check_page(self, name: str, page: StatusPage, incidents: list[Incident]) -> None:
+        """Post a page's incident changes and track Discord API incidents."""
+        if is_discord_page(page):
+            api_incidents = [incident for incident in incidents if is_api_incident(incident)]
+            self.bot.incidents.discord_incident = api_incidents[0] if api_incidents else None
+
+        for change, incident in self.bot.incidents.changes(name, incidents):
+            logger.info("Status page %s: incident %s %s", name, incident.name, change)
+            await self.post_notice(name, change, incident)
+
+    async def post_notice(self, page: str, change: Change, incident: Incident) -> None:
+        """Post an incident notice in the status channel, or the ops channel."""
+        channel_name = (
+            settings.status_channel
+            or settings.discord_ops_channel
+            or settings.discord_errors_channel
+        )
+        if not channel_name:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=channel_name)
+        if not channel:
+            logger.error("Status channel not found: %s", channel_name)
+            return
+
+        color = (
+            discord.Color.green()
+            if change == "resolved"
+            else IMPACT_COLORS.get(incident.impact, discord.Color.light_grey)()
+        )
+        builder = (
+            EmbedBuilder()
+            .set_title(
+                f"{CHANGE_EMOJI[change]} {page}: {incident.name}"[:TITLE_LIMIT],
+                url=incident.url or None,
+            )
+            .set_color(color)
+            .add_field(name="Status", value=incident.status.capitalize(), inline=True)
+        )
+        if incident.update:
+            builder.set_description(incident.update[:DESCRIPTION_LIMIT])
+        if incident.impact != "none":
+            builder.add_field(name="Impact", value=incident.impact.capitalize(), inline=True)
+        if incident.components:
+            builder.add_field(
+                name="Affected",
+                value=", ".join(incident.components)[:FIELD_VALUE_LIMIT],
+                inline=False,
+            )
+        await self.bot.messenger.send(channel, embed=builder.build())
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the status pages cog."""
+    await bot.add_cog(StatusPagesCog(bot))
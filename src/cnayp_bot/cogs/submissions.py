@@ -0,0 +1,188 @@
+"""Event submissions from external systems, approved by organizers."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.embeds import EmbedBuilder
+from ..models import EventSubmission
+from ..services.api import ApiServer
+from ..services.ical import schedules_ical
+
+logger = logging.getLogger(__name__)
+
+# Component handler for the approve and reject buttons
+SUBMISSION = "submission"
+
+
+class SubmissionsCog(commands.Cog):
+    """Serves the HTTP API and the approval queue in the submissions channel.
+
+    Each submission is posted with Approve and Reject buttons. Approved events
+    are picked up by the scheduler like any other event. GitHub releases
+    received by the API are dispatched as `github_release` events, Alertmanager
+    notifications as `alertmanager_alerts` events, and tasks from peer bots are
+    run by `bot.peers`. With a public URL, each guild's schedules are also
+    served as an iCal feed.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.api_server: ApiServer | None = None
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(SUBMISSION, self.decide)
+
+        if not (
+            settings.api_token
+            or settings.github_webhook_secret
+            or settings.peer_bots
+            or settings.api_public_url
+        ):
+            logger.info(
+                "API token, GitHub webhook, peers, and public URL not configured, "
+                "API server disabled"
+            )
+            return
+
+        self.api_server = ApiServer(
+            on_event_submission=self.queue_submission,
+            on_github_release=self.dispatch_release,
+            on_alerts=self.dispatch_alerts,
+            on_peer_task=self.bot.peers.receive,
+            metrics=self.bot.governor.metrics,
+            calendar_feed=self.calendar_feed if settings.api_public_url else None,
+            is_ready=lambda: not self.bot.leader.is_standby,
+        )
+        await self.api_server.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        if self.api_server:
+            await self.api_server.stop()
+
+    async def queue_submission(self, submission: EventSubmission) -> str:
+        """Queue a submission and post it for review.
+
+        Raises:
+            ValueError: If submissions are off or the event's voice channel doesn't exist.
+        """
+        if not settings.submissions_channel:
+            raise ValueError("Event submissions are disabled")
+
+        await self.bot.wait_until_ready()
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if not guild:
+            raise RuntimeError(f"Guild not found: {settings.discord_guild_id}")
+
+        if not discord.utils.get(guild.voice_channels, name=submission.channel):
+            raise ValueError(f"Unknown voice channel: {submission.channel}")
+
+        channel = discord.utils.get(guild.text_channels, name=settings.submissions_channel)
+        if not channel:
+            raise RuntimeError(f"Submissions channel not found: {settings.submissions_channel}")
+
+        submission_id = self.bot.submissions.add(submission)
+        view = discord.ui.View(timeout=None)
+        view.add_item(
+            self.bot.components.button(
+                SUBMISSION,
+                f"{submission_id}:approve",
+                label="Approve",
+                emoji="✅",
+                style=discord.ButtonStyle.success,
+            )
+        )
+        view.add_item(
+            self.bot.components.button(
+                SUBMISSION,
+                f"{submission_id}:reject",
+                label="Reject",
+                emoji="✖️",
+                style=discord.ButtonStyle.danger,
+            )
+        )
+        message = await self.bot.messenger.send(
+            channel, embed=self._build_embed(submission, "Pending review"), view=view
+        )
+        if message:
+            self.bot.submissions.set_message(submission_id, message.id)
+        return submission_id
+
+    async def dispatch_release(self, repo: str, release: dict) -> None:
+        """Hand a published GitHub release to the cogs listening for it."""
+        await self.bot.wait_until_ready()
+        self.bot.dispatch("github_release", repo, release)
+
+    async def dispatch_alerts(self, payload: dict) -> None:
+        """Hand an Alertmanager notification to the cogs listening for it."""
+        await self.bot.wait_until_ready()
+        self.bot.dispatch("alertmanager_alerts", payload)
+
+    def calendar_feed(self, guild_id: int) -> str | None:
+        """Render the public schedules of a guild the bot is in as an iCal calendar."""
+        guild = self.bot.get_guild(guild_id)
+        if not guild:
+            return None
+
+        schedules = [
+            schedule
+            for schedule in self.bot.schedules.config.schedules
+            if (schedule.guild_id or settings.discord_guild_id) == guild_id
+        ]
+        return schedules_ical(
+            schedules, self.bot.schedules.holidays, datetime.now(ZoneInfo("UTC")), guild.name
+        )
+
+    async def decide(self, interaction: discord.Interaction, payload: str) -> None:
+        """Approve or reject the submission encoded in a review button."""
+        if not interaction.permissions.manage_events:
+            await interaction.response.send_message(
+                "Only organizers who can manage events can review submissions.", ephemeral=True
+            )
+            return
+
+        submission_id, _, action = payload.partition(":")
+        approved = action == "approve"
+        submission = self.bot.submissions.decide(submission_id, approved)
+        if submission is None:
+            await interaction.response.send_message(
+                "This submission was already reviewed.", ephemeral=True
+            )
+            return
+
+        verdict = "Approved" if approved else "Rejected"
+        logger.info("%s submission %s by %s", verdict, submission_id, interaction.user)
+        await interaction.response.edit_message(
+            embed=self._build_embed(submission, f"{verdict} by {interaction.user}"), view=None
+        )
+        scheduler = self.bot.get_cog("SchedulerCog")
+        if approved and scheduler:
+            await scheduler.schedule_submission(submission_id)
+
+    def _build_embed(self, submission: EventSubmission, status: str) -> discord.Embed:
+        """Build the review embed for a submission."""
+        start = int(submission.time.timestamp())
+        builder = (
+            EmbedBuilder()
+            .set_title(f"📥 Proposed event: {submission.name}")
+            .set_description(submission.description or "No description.")
+            .set_color(discord.Color.orange())
+            .add_field(name="When", value=f"<t:{start}:F> (<t:{start}:R>)")
+            .add_field(name="Duration", value=f"{submission.duration} minutes", inline=True)
+            .add_field(name="Channel", value=submission.channel, inline=True)
+            .set_footer(text=status)
+        )
+        if submission.submitted_by:
+            builder.add_field(name="Submitted by", value=submission.submitted_by, inline=True)
+        return builder.build()
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the submissions cog."""
+    await bot.add_cog(SubmissionsCog(bot))
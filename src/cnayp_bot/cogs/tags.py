@@ -0,0 +1,165 @@
+"""FAQ tags and duplicate-question suggestions in help channels."""
+
+import logging
+import re
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.similarity import best_match
+
+logger = logging.getLogger(__name__)
+
+TAGS = "tags"
+FAQ_OPT_OUT = "faq_opt_out"
+
+OPT_OUT_EMOJI = "🔕"
+SNIPPET_LENGTH = 200
+MAX_TAG_NAME_LENGTH = 32
+
+# Suggestions remembered for the opt-out reaction, oldest dropped first
+MAX_TRACKED_SUGGESTIONS = 200
+
+_TAG_NAME = re.compile(r"^[a-z0-9][a-z0-9-]*$")
+
+
+class TagsCog(commands.Cog):
+    """Stores FAQ answers as tags and suggests them for repeated questions."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.suggestions: dict[int, int] = {}  # suggestion message ID -> asker ID
+
+    @commands.group(name="tag", invoke_without_command=True)
+    async def tag(self, ctx: commands.Context, name: str | None = None) -> None:
+        """Show a FAQ tag.
+
+        Usage: !tag <name>
+        Example: !tag kubectl-install
+        """
+        if name is None:
+            await ctx.send_help(ctx.command)
+            return
+
+        content = self.bot.store.get(TAGS, name.lower())
+        if content is None:
+            await ctx.send(f"No tag named `{name}`. See `!tag list`.")
+            return
+        await ctx.send(content)
+
+    @tag.command(name="list")
+    async def tag_list(self, ctx: commands.Context) -> None:
+        """List FAQ tags.
+
+        Usage: !tag list
+        """
+        names = sorted(self.bot.store.items(TAGS))
+        await ctx.send(", ".join(f"`{name}`" for name in names) if names else "No tags yet.")
+
+    @tag.command(name="add")
+    @commands.has_permissions(manage_messages=True)
+    async def tag_add(self, ctx: commands.Context, name: str, *, content: str) -> None:
+        """Create or replace a FAQ tag (moderators only).
+
+        Usage: !tag add <name> <content>
+        Example: !tag add kcna-exam The KCNA exam costs...
+        """
+        name = name.lower()
+        if len(name) > MAX_TAG_NAME_LENGTH or not _TAG_NAME.match(name):
+            await ctx.send(
+                f"Tag names use lowercase letters, digits, and dashes "
+                f"(up to {MAX_TAG_NAME_LENGTH} characters)."
+            )
+            return
+
+        self.bot.store.set(TAGS, name, content)
+        await ctx.send(f"Saved tag `{name}`.")
+
+    @tag.command(name="remove")
+    @commands.has_permissions(manage_messages=True)
+    async def tag_remove(self, ctx: commands.Context, name: str) -> None:
+        """Delete a FAQ tag (moderators only).
+
+        Usage: !tag remove <name>
+        """
+        if self.bot.store.get(TAGS, name.lower()) is None:
+            await ctx.send(f"No tag named `{name}`.")
+            return
+
+        self.bot.store.delete(TAGS, name.lower())
+        await ctx.send(f"Removed tag `{name}`.")
+
+    @tag.command(name="suggestions")
+    async def tag_suggestions(self, ctx: commands.Context, enabled: bool) -> None:
+        """Turn FAQ suggestions on your help questions on or off.
+
+        Usage: !tag suggestions <on|off>
+        """
+        if enabled:
+            self.bot.store.delete(FAQ_OPT_OUT, str(ctx.author.id))
+            await ctx.send("You'll get FAQ suggestions on your questions again.")
+        else:
+            self.bot.store.set(FAQ_OPT_OUT, str(ctx.author.id), True)
+            await ctx.send("You won't get FAQ suggestions anymore.")
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Suggest a matching FAQ tag for questions in configured help channels."""
+        if message.author.bot or not message.content:
+            return
+
+        channel = message.channel
+        threshold = settings.faq_channels.get(channel.id)
+        if threshold is None and isinstance(channel, discord.Thread):
+            # Forum posts and question threads use their parent channel's setting
+            threshold = settings.faq_channels.get(channel.parent_id)
+        if threshold is None or message.content.startswith(self.bot.command_prefix):
+            return
+        if self.bot.store.get(FAQ_OPT_OUT, str(message.author.id)):
+            return
+
+        # Tag names are usually the best summary of the question, so they're matched too
+        tags = {
+            name: f"{name.replace('-', ' ')} {content}"
+            for name, content in self.bot.store.items(TAGS).items()
+        }
+        match = best_match(message.content, tags)
+        if match is None or match[1] < threshold:
+            return
+
+        name, score = match
+        content = self.bot.store.get(TAGS, name).replace("\n", " ")
+        if len(content) > SNIPPET_LENGTH:
+            content = content[: SNIPPET_LENGTH - 1] + "…"
+        suggestion = await self.bot.messenger.send(
+            channel,
+            f"This might already be answered in the **{name}** FAQ (`!tag {name}`):\n"
+            f"> {content}\n"
+            f"-# React {OPT_OUT_EMOJI} to stop getting these suggestions.",
+            allowed_mentions=discord.AllowedMentions.none(),
+            reference=message,
+        )
+        logger.info("Suggested tag %s to %s (similarity %.2f)", name, message.author, score)
+
+        if suggestion:
+            self.suggestions[suggestion.id] = message.author.id
+            if len(self.suggestions) > MAX_TRACKED_SUGGESTIONS:
+                del self.suggestions[next(iter(self.suggestions))]
+
+    @commands.Cog.listener()
+    async def on_raw_reaction_add(self, payload: discord.RawReactionActionEvent) -> None:
+        """Opt the asker out of suggestions when they react to one with the opt-out emoji."""
+        if str(payload.emoji) != OPT_OUT_EMOJI:
+            return
+        if self.suggestions.get(payload.message_id) != payload.user_id:
+            return
+
+        self.bot.store.set(FAQ_OPT_OUT, str(payload.user_id), True)
+        del self.suggestions[payload.message_id]
+        logger.info("User %d opted out of FAQ suggestions", payload.user_id)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the tags cog."""
+    await bot.add_cog(TagsCog(bot))
@@ -0,0 +1,298 @@
+"""Topic suggestions and reaction votes for upcoming events."""
+
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.embeds import EmbedBuilder
+from ..services.calendar import CalendarEvent
+from ..services.governor import Priority
+from ..services.in_flight import drained
+from ..services.schedules import is_private
+from ..services.topic_votes import pick_winner
+from .scheduler import DISCORD_EVENTS, RECURRING
+
+logger = logging.getLogger(__name__)
+
+# How far ahead /topics open looks for the event
+SEARCH_DAYS = 14
+
+# Discord's limit on scheduled event descriptions
+EVENT_DESCRIPTION_LIMIT = 1000
+
+
+class TopicVotesCog(commands.Cog):
+    """Collects topic suggestions for an event and puts them to a vote.
+
+    Organizers open suggestions with /topics open, and members reply to that
+    message with a topic. `topic_vote_hours` before the event, the suggestions
+    are posted as a vote with one reaction each. `topic_vote_close_minutes`
+    before it starts, the most voted topic is announced and added to the
+    Discord event description.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.vote_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.vote_loop.cancel()
+
+    @commands.hybrid_group(name="topics")
+    async def topics(self, ctx: commands.Context) -> None:
+        """Topic suggestions and votes for events.
+
+        Usage: !topics open <event>
+        """
+        await ctx.send_help(ctx.command)
+
+    @topics.command(name="open")
+    @commands.has_permissions(manage_events=True)
+    async def topics_open(self, ctx: commands.Context, *, event: str) -> None:
+        """Take topic suggestions for an upcoming event (requires Manage Events).
+
+        Usage: !topics open <event>
+        Example: !topics open KCNA Study
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        found = self._find_event(event, now)
+        if not found:
+            await ctx.send(f"❌ No event matching '{event}' in the next {SEARCH_DAYS} days.")
+            return
+
+        vote_at = found.start_time - timedelta(hours=settings.topic_vote_hours)
+        if vote_at <= now:
+            await ctx.send(
+                f"❌ **{found.name}** starts too soon to take suggestions: votes are posted "
+                f"{settings.topic_vote_hours:g} hours before events."
+            )
+            return
+        if self.bot.topic_votes.get(found.id):
+            await ctx.send(f"Suggestions for **{found.name}** are already open.")
+            return
+
+        embed = (
+            EmbedBuilder()
+            .set_title(f"💡 Topic suggestions: {found.name}")
+            .set_description(
+                f"What should we cover at **{found.name}** "
+                f"(<t:{int(found.start_time.timestamp())}:F>)? Reply to this message with "
+                "a topic. One suggestion per member; replying again replaces yours.\n\n"
+                f"Voting opens <t:{int(vote_at.timestamp())}:R>, and the winning topic goes "
+                "in the event description."
+            )
+            .set_color(discord.Color.blurple())
+            .build()
+        )
+        message = await self.bot.messenger.send(ctx.channel, embed=embed)
+        if not message:
+            await ctx.send("❌ Couldn't post the suggestions message here.")
+            return
+
+        self.bot.topic_votes.open(
+            found.id, found.name, found.start_time, ctx.channel.id, message.id
+        )
+        logger.info("%s opened topic suggestions for %s", ctx.author, found.name)
+        if ctx.interaction:
+            await ctx.send("Suggestions are open.", ephemeral=True)
+
+    def _find_event(self, query: str, now: datetime) -> CalendarEvent | None:
+        """Return the next upcoming event whose name contains `query`."""
+        end = now + timedelta(days=SEARCH_DAYS)
+        events = self.bot.calendar.get_events_between(now, end)
+        events += self.bot.schedules.get_events_between(now, end)
+        events += self.bot.submissions.get_events_between(now, end)
+        matches = [
+            event
+            for event in events
+            if query.lower() in event.name.lower() and not is_private(event)
+        ]
+        return min(matches, key=lambda event: event.start_time, default=None)
+
+    @commands.Cog.listener()
+    async def on_message(self, message: discord.Message) -> None:
+        """Take replies to a suggestions message as topic suggestions."""
+        if message.author.bot or not message.reference or not message.content.strip():
+            return
+        if not self.bot.leader.is_leader:
+            return
+
+        event_id = self.bot.topic_votes.find(message.reference.message_id)
+        if not event_id:
+            return
+
+        result = self.bot.topic_votes.suggest(
+            event_id,
+            message.author.id,
+            message.content.strip(),
+            limit=len(settings.topic_vote_emoji),
+        )
+        if result == "closed":
+            await self.bot.messenger.send(
+                message.channel,
+                "Voting already started, so suggestions are closed.",
+                reference=message,
+            )
+        elif result == "full":
+            await self.bot.messenger.send(
+                message.channel,
+                "There are already as many suggestions as the vote can hold.",
+                reference=message,
+            )
+        else:
+            try:
+                await message.add_reaction("📝")
+            except discord.HTTPException as e:
+                logger.warning("Failed to acknowledge a topic suggestion: %s", e)
+
+    @tasks.loop(minutes=1)
+    @drained("topic votes")
+    async def vote_loop(self) -> None:
+        """Post the votes and count the results that are due."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("topic_votes", Priority.BACKGROUND)
+        now = datetime.now(ZoneInfo("UTC"))
+        votes = self.bot.topic_votes
+        try:
+            for event_id in votes.due_votes(now, timedelta(hours=settings.topic_vote_hours)):
+                await self.post_vote(event_id)
+            close = timedelta(minutes=settings.topic_vote_close_minutes)
+            for event_id in votes.due_results(now, close):
+                await self.count_votes(event_id)
+            votes.forget_finished(now)
+        except Exception as e:
+            logger.exception("Error in topic vote loop: %s", e)
+
+    @vote_loop.before_loop
+    async def before_vote_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def post_vote(self, event_id: str) -> None:
+        """Post the suggestions of an event as a reaction vote."""
+        votes = self.bot.topic_votes.get(event_id)
+        channel = self.bot.get_channel(votes["channel_id"])
+        if not channel:
+            logger.error("Topic vote channel for %s is gone", votes["name"])
+            self.bot.topic_votes.close(event_id, None)
+            return
+
+        suggestions = votes["suggestions"]
+        if not suggestions:
+            await self.bot.messenger.send(
+                channel, f"Nobody suggested a topic for **{votes['name']}**, so there's no vote."
+            )
+            self.bot.topic_votes.close(event_id, None)
+            return
+
+        emoji = settings.topic_vote_emoji[: len(suggestions)]
+        closes_at = datetime.fromisoformat(votes["start"]) - timedelta(
+            minutes=settings.topic_vote_close_minutes
+        )
+        lines = [
+            f"{symbol} {suggestion['text']} (<@{suggestion['author_id']}>)"
+            for symbol, suggestion in zip(emoji, suggestions, strict=True)
+        ]
+        embed = (
+            EmbedBuilder()
+            .set_title(f"🗳️ Topic vote: {votes['name']}")
+            .set_description(
+                "React to vote for the topics you'd like, as many as you want.\n\n"
+                + "\n".join(lines)
+                + f"\n\nVoting closes <t:{int(closes_at.timestamp())}:R>."
+            )
+            .set_color(discord.Color.blurple())
+            .build()
+        )
+        message = await self.bot.messenger.send(
+            channel, embed=embed, allowed_mentions=discord.AllowedMentions.none()
+        )
+        if not message:
+            return
+
+        for symbol in emoji:
+            try:
+                await message.add_reaction(symbol)
+            except discord.HTTPException as e:
+                logger.warning("Failed to add vote reaction %s: %s", symbol, e)
+        self.bot.topic_votes.start_vote(event_id, message.id, emoji)
+        logger.info("Posted the topic vote for %s", votes["name"])
+
+    async def count_votes(self, event_id: str) -> None:
+        """Announce an event's winning topic and add it to the Discord event."""
+        votes = self.bot.topic_votes.get(event_id)
+        channel = self.bot.get_channel(votes["channel_id"])
+        try:
+            message = channel and await channel.fetch_message(votes["vote_message_id"])
+        except discord.HTTPException as e:
+            logger.error("Failed to fetch the topic vote for %s: %s", votes["name"], e)
+            message = None
+        if not message:
+            self.bot.topic_votes.close(event_id, None)
+            return
+
+        # The bot's own reactions seed the vote and don't count
+        counts = {
+            str(reaction.emoji): reaction.count - reaction.me for reaction in message.reactions
+        }
+        winner = pick_winner(
+            votes["suggestions"], [counts.get(symbol, 0) for symbol in votes["emoji"]]
+        )
+        self.bot.topic_votes.close(event_id, winner["text"] if winner else None)
+        if not winner:
+            await self.bot.messenger.send(
+                channel, f"Nobody voted on a topic for **{votes['name']}**.", reference=message
+            )
+            return
+
+        logger.info("Topic for %s: %s", votes["name"], winner["text"])
+        await self.bot.messenger.send(
+            channel,
+            f"🏆 The topic for **{votes['name']}** is **{winner['text']}**, "
+            f"suggested by <@{winner['author_id']}>!",
+            allowed_mentions=discord.AllowedMentions(users=True, everyone=False, roles=False),
+            reference=message,
+        )
+        await self._set_event_topic(event_id, winner["text"])
+
+    async def _set_event_topic(self, event_id: str, topic: str) -> None:
+        """Add the winning topic to the description of the event's Discord event."""
+        tracked = self.bot.store.get(DISCORD_EVENTS, event_id)
+        # A recurring Discord event shares its description with every occurrence
+        if not tracked or not tracked["id"] or tracked["status"] == RECURRING:
+            logger.info("No single Discord event to add the topic of %s to", event_id)
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record("set event topic", event=tracked["id"], topic=topic)
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        if not guild:
+            return
+
+        line = f"🗳️ Topic: {topic}"
+        try:
+            scheduled = guild.get_scheduled_event(tracked["id"])
+            scheduled = scheduled or await guild.fetch_scheduled_event(tracked["id"])
+            description = scheduled.description or ""
+            room = EVENT_DESCRIPTION_LIMIT - len(line) - 2
+            description = f"{description[:room]}\n\n{line}" if description else line
+            await scheduled.edit(description=description, reason="Topic vote")
+        except discord.HTTPException as e:
+            logger.error("Failed to add the topic to the Discord event of %s: %s", event_id, e)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the topic votes cog."""
+    await bot.add_cog(TopicVotesCog(bot))
@@ -0,0 +1,127 @@
+"""Channel topics kept up to date with upcoming events."""
+
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.permissions import MissingPermissionsError, check_channel_permissions
+from ..helpers.ratelimit import SlidingWindowLimiter
+from ..helpers.topics import render_topic
+from ..services.calendar import CalendarEvent
+from ..services.governor import Priority
+from ..services.schedules import is_private
+from .digest import CURRENT, DIGEST
+from .voice_names import RENAME_LIMIT, RENAME_WINDOW
+
+logger = logging.getLogger(__name__)
+
+
+class TopicsCog(commands.Cog):
+    """Re-renders configured channel topics and edits them when they change.
+
+    Topic edits share Discord's limit of 2 channel edits per 10 minutes, so a
+    channel whose limit is used up is retried on a later refresh.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.limiter = SlidingWindowLimiter(RENAME_LIMIT, RENAME_WINDOW)
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.channel_topics:
+            logger.info("No channel topics configured, topic updates disabled")
+            return
+
+        self.topic_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.topic_loop.cancel()
+
+    @tasks.loop(minutes=5)
+    async def topic_loop(self) -> None:
+        """Edit topics whose rendered text changed."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("topics", Priority.BACKGROUND)
+        try:
+            now = datetime.now(ZoneInfo(settings.default_timezone))
+            events = self._upcoming_events(now)
+            week_theme = self._week_theme(now)
+            digest_link = self._digest_link()
+
+            for channel_id, template in settings.channel_topics.items():
+                topic = render_topic(template, events, now, week_theme, digest_link)
+                await self._update_topic(channel_id, topic)
+        except Exception as e:
+            logger.exception("Error in topic loop: %s", e)
+
+    @topic_loop.before_loop
+    async def before_topic_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def _update_topic(self, channel_id: int, topic: str) -> None:
+        """Set a channel's topic if it changed and the rate limit allows it."""
+        channel = self.bot.get_channel(channel_id)
+        if not isinstance(channel, discord.TextChannel):
+            logger.error("Text channel not found: %d", channel_id)
+            return
+        if channel.topic == topic:
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        if self.limiter.delay(channel_id, now) > timedelta(0):
+            logger.info("Topic update of #%s postponed by the rate limit", channel.name)
+            return
+
+        try:
+            check_channel_permissions(channel, "view_channel", "manage_channels")
+        except MissingPermissionsError as e:
+            logger.error("Can't update the topic of #%s: %s", channel.name, e)
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record("set topic", channel=channel.name, topic=topic)
+            return
+
+        try:
+            await channel.edit(topic=topic, reason="Channel topic update")
+            self.limiter.record(channel_id, now)
+            logger.info("Updated the topic of #%s", channel.name)
+        except discord.HTTPException as e:
+            logger.error("Failed to update the topic of #%s: %s", channel.name, e)
+
+    def _upcoming_events(self, now: datetime) -> list[CalendarEvent]:
+        """Return public events in the next week from every event source."""
+        end = now + timedelta(days=7)
+        events = self.bot.calendar.get_events_between(now, end)
+        events += self.bot.schedules.get_events_between(now, end)
+        events += self.bot.submissions.get_events_between(now, end)
+        return [event for event in events if not is_private(event)]
+
+    def _week_theme(self, now: datetime) -> str:
+        """Return the theme of the current week from the schedules file, if any."""
+        monday = now.date() - timedelta(days=now.weekday())
+        return self.bot.schedules.config.week_themes.get(monday, "")
+
+    def _digest_link(self) -> str:
+        """Return a link to the latest daily digest, if one was posted."""
+        posted = self.bot.store.get(DIGEST, CURRENT)
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel_name = self.bot.schedules.config.digest_channel
+        channel = guild and discord.utils.get(guild.text_channels, name=channel_name)
+        if not posted or not posted["message_id"] or not channel:
+            return ""
+        return f"https://discord.com/channels/{guild.id}/{channel.id}/{posted['message_id']}"
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the topics cog."""
+    await bot.add_cog(TopicsCog(bot))
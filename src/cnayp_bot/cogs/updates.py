@@ -0,0 +1,90 @@
+"""/version, and notices when a newer release of the bot is out."""
+
+import logging
+
+import aiohttp
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..services.governor import Priority
+from ..services.in_flight import drained
+from ..services.updates import UPDATES, build_info, fetch_latest_release, is_newer
+
+logger = logging.getLogger(__name__)
+
+
+class UpdatesCog(commands.Cog):
+    """Reports the running build and watches for newer releases.
+
+    With `update_check_repo` set, the repository's latest GitHub release is
+    checked every `update_check_hours`, and the ops channel is told once
+    about each release newer than the running version.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.update_check_repo:
+            logger.info("No update check repository configured, update notices disabled")
+            return
+
+        self.update_loop.change_interval(hours=settings.update_check_hours)
+        self.update_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.update_loop.cancel()
+
+    @commands.hybrid_command(name="version")
+    async def version(self, ctx: commands.Context) -> None:
+        """Show the bot's version, commit, and build date.
+
+        Usage: !version
+        """
+        info = build_info()
+        embed = discord.Embed(title="CNAYP Bot", color=discord.Color.dark_grey())
+        embed.add_field(name="Version", value=info["version"])
+        embed.add_field(name="Commit", value=f"`{info['commit']}`")
+        embed.add_field(name="Built", value=info["date"])
+        await ctx.send(embed=embed)
+
+    @tasks.loop(hours=6)
+    @drained("update check")
+    async def update_loop(self) -> None:
+        """Tell the ops channel about a newer release of the bot."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("updates", Priority.BACKGROUND)
+        repo = settings.update_check_repo
+        try:
+            release = await fetch_latest_release(repo)
+        except (aiohttp.ClientError, TimeoutError) as e:
+            logger.warning("Failed to check %s for a newer release: %s", repo, e)
+            return
+
+        current = build_info()["version"]
+        tag = release and release["tag_name"]
+        if not tag or not is_newer(tag, current):
+            return
+        if self.bot.store.get(UPDATES, "notified") == tag:
+            return
+
+        logger.info("Release %s of %s is out, running %s", tag, repo, current)
+        await self.bot.messenger.alert_ops(
+            f"⬆️ Version {tag} of the bot is out (running {current}): {release['html_url']}"
+        )
+        self.bot.store.set(UPDATES, "notified", tag)
+
+    @update_loop.before_loop
+    async def before_update_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the updates cog."""
+    await bot.add_cog(UpdatesCog(bot))
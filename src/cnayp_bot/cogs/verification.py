@@ -0,0 +1,302 @@
+"""Verification gate holding new members until they accept the rules."""
+
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.permissions import (
+    MissingPermissionsError,
+    check_can_manage_roles,
+    missing_permissions,
+)
+from ..services.governor import Priority
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+# Component handler for the Verify button
+VERIFY = "verify"
+VERIFY_EMOJI = "✅"
+REASON = "Verification gate"
+
+
+class VerificationCog(commands.Cog):
+    """Holds new members in the unverified role until they verify.
+
+    Members are held once they pass membership screening, if the guild has
+    it, and verify with the button on the gate message or by reacting ✅ to
+    it, which swaps the unverified role for the verified one. Members who
+    don't are reminded by DM after the timeout, then kicked after the
+    configured days.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        self.bot.components.register(VERIFY, self.verify_button)
+
+        if not settings.unverified_role:
+            logger.info("No unverified role configured, verification gate disabled")
+            return
+
+        self.verification_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.verification_loop.cancel()
+
+    @commands.hybrid_command(name="verification")
+    @commands.guild_only()
+    @commands.has_permissions(administrator=True)
+    async def post_gate(self, ctx: commands.Context) -> None:
+        """Post the verification gate message in the verification channel.
+
+        Usage: !verification
+        """
+        if not settings.unverified_role:
+            await ctx.send("The verification gate is off. Set UNVERIFIED_ROLE to turn it on.")
+            return
+
+        channel = discord.utils.get(ctx.guild.text_channels, name=settings.verification_channel)
+        if not channel:
+            await ctx.send(f"Channel #{settings.verification_channel} not found.")
+            return
+
+        embed = discord.Embed(
+            title="✅ Verification",
+            description=(
+                f"Read the rules, then press **Verify** or react {VERIFY_EMOJI} "
+                "to unlock the rest of the server."
+            ),
+            color=discord.Color.green(),
+        )
+        view = discord.ui.View(timeout=None)
+        view.add_item(
+            self.bot.components.button(
+                VERIFY, label="Verify", emoji=VERIFY_EMOJI, style=discord.ButtonStyle.success
+            )
+        )
+        message = await self.bot.messenger.send(channel, embed=embed, view=view)
+        if not message:
+            await ctx.send(f"Couldn't post the verification gate in {channel.mention}.")
+            return
+
+        try:
+            await message.add_reaction(VERIFY_EMOJI)
+        except discord.HTTPException as e:
+            logger.warning("Failed to add the verification reaction: %s", e)
+
+        self.bot.verification.set_message(ctx.guild.id, channel.id, message.id)
+        await ctx.send(f"Posted the verification gate in {channel.mention}.")
+
+    @commands.Cog.listener()
+    async def on_member_screened(self, member: discord.Member) -> None:
+        """Hold new members at the gate once they accepted the rules."""
+        if not settings.unverified_role:
+            return
+
+        role = discord.utils.get(member.guild.roles, name=settings.unverified_role)
+        if role is None:
+            logger.error("Unverified role not found: %s", settings.unverified_role)
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record("add role", member=member.id, role=role.name)
+        else:
+            try:
+                check_can_manage_roles(member.guild, role)
+                await member.add_roles(role, reason=REASON)
+            except MissingPermissionsError as e:
+                logger.error("Can't hold %s at the verification gate: %s", member, e)
+                return
+            except discord.HTTPException as e:
+                logger.error("Failed to give %s the unverified role: %s", member, e)
+                return
+
+        self.bot.verification.add(member.id, datetime.now(ZoneInfo("UTC")))
+
+    @commands.Cog.listener()
+    async def on_member_remove(self, member: discord.Member) -> None:
+        """Forget unverified members who left."""
+        if member.guild.id == settings.discord_guild_id:
+            self.bot.verification.remove(member.id)
+
+    @commands.Cog.listener()
+    async def on_raw_reaction_add(self, payload: discord.RawReactionActionEvent) -> None:
+        """Verify members who react ✅ to the gate message."""
+        if not settings.unverified_role or str(payload.emoji) != VERIFY_EMOJI:
+            return
+        if payload.guild_id != settings.discord_guild_id:
+            return
+
+        gate = self.bot.verification.message(payload.guild_id)
+        if not gate or gate["message_id"] != payload.message_id:
+            return
+        if payload.member and not payload.member.bot and self._is_unverified(payload.member):
+            await self._verify(payload.member)
+
+    async def verify_button(self, interaction: discord.Interaction, payload: str) -> None:
+        """Verify the member who pressed the gate's Verify button."""
+        member = interaction.user
+        if not settings.unverified_role or not isinstance(member, discord.Member):
+            await interaction.response.send_message("Verification is off.", ephemeral=True)
+            return
+
+        if not self._is_unverified(member):
+            message = "You're already verified."
+        elif await self._verify(member):
+            message = f"You're verified. Welcome to {member.guild.name}!"
+        else:
+            message = "Verification failed. Please ask an organizer for help."
+        await interaction.response.send_message(message, ephemeral=True)
+
+    @tasks.loop(minutes=30)
+    @drained("verification")
+    async def verification_loop(self) -> None:
+        """Remind, then kick, members who haven't verified in time."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("verification", Priority.BACKGROUND)
+        try:
+            guild = self.bot.get_guild(settings.discord_guild_id)
+            if not guild:
+                return
+
+            now = datetime.now(ZoneInfo("UTC"))
+            if settings.verification_kick_days:
+                kick_after = timedelta(days=settings.verification_kick_days)
+                for member_id in self.bot.verification.expired(now, kick_after):
+                    await self._kick(guild, member_id)
+
+            timeout = timedelta(hours=settings.verification_timeout_hours)
+            for member_id in self.bot.verification.due_reminders(now, timeout):
+                await self._remind(guild, member_id)
+        except Exception as e:
+            logger.exception("Error in verification loop: %s", e)
+
+    @verification_loop.before_loop
+    async def before_verification_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    def _is_unverified(self, member: discord.Member) -> bool:
+        """Whether a member is held at the gate."""
+        if self.bot.verification.is_pending(member.id):
+            return True
+        return any(role.name == settings.unverified_role for role in member.roles)
+
+    async def _verify(self, member: discord.Member) -> bool:
+        """Swap the unverified role for the verified one.
+
+        Returns:
+            True if the member was let through.
+        """
+        guild = member.guild
+        unverified = discord.utils.get(guild.roles, name=settings.unverified_role)
+        verified = settings.verified_role and discord.utils.get(
+            guild.roles, name=settings.verified_role
+        )
+        if settings.verified_role and not verified:
+            logger.error("Verified role not found: %s", settings.verified_role)
+            return False
+
+        if settings.observer_mode:
+            self.bot.observer.record("verify member", member=member.id)
+        else:
+            try:
+                check_can_manage_roles(guild, *(role for role in (unverified, verified) if role))
+                if verified:
+                    await member.add_roles(verified, reason=REASON)
+                if unverified in member.roles:
+                    await member.remove_roles(unverified, reason=REASON)
+            except MissingPermissionsError as e:
+                logger.error("Can't verify %s: %s", member, e)
+                return False
+            except discord.HTTPException as e:
+                logger.error("Failed to verify %s: %s", member, e)
+                return False
+
+        self.bot.verification.remove(member.id)
+        logger.info("Verified %s", member)
+        return True
+
+    def _pending_member(self, guild: discord.Guild, member_id: int) -> discord.Member | None:
+        """Return an unverified member, forgetting those who left or were let in by hand."""
+        member = guild.get_member(member_id)
+        if member is None or not any(
+            role.name == settings.unverified_role for role in member.roles
+        ):
+            self.bot.verification.remove(member_id)
+            return None
+        return member
+
+    async def _remind(self, guild: discord.Guild, member_id: int) -> None:
+        """DM an unverified member how to verify."""
+        member = self._pending_member(guild, member_id)
+        if member is None:
+            return
+
+        gate = self.bot.verification.message(guild.id)
+        where = (
+            f"https://discord.com/channels/{guild.id}/{gate['channel_id']}/{gate['message_id']}"
+            if gate
+            else f"#{settings.verification_channel}"
+        )
+        message = (
+            f"Hi {member.display_name}! You haven't verified in **{guild.name}** yet. "
+            f"Press Verify on {where} to unlock the server."
+        )
+        if settings.verification_kick_days:
+            message += (
+                f" Members who don't verify are removed after "
+                f"{settings.verification_kick_days:g} days."
+            )
+
+        try:
+            await self.bot.messenger.send(member, message)
+        except discord.Forbidden:
+            logger.info("%s doesn't accept DMs, skipping their verification reminder", member)
+        except discord.HTTPException as e:
+            logger.error("Failed to remind %s to verify: %s", member, e)
+            return
+
+        self.bot.verification.mark_reminded(member_id)
+
+    async def _kick(self, guild: discord.Guild, member_id: int) -> None:
+        """Kick a member who didn't verify in time."""
+        member = self._pending_member(guild, member_id)
+        if member is None:
+            return
+
+        reason = f"Didn't verify within {settings.verification_kick_days:g} days"
+        if settings.observer_mode:
+            self.bot.observer.record("kick member", member=member_id, reason=reason)
+            self.bot.verification.remove(member_id)
+            return
+
+        missing = missing_permissions(guild.me.guild_permissions, "kick_members")
+        if missing:
+            error = MissingPermissionsError(missing, guild.name)
+            logger.error("Can't kick unverified %s: %s", member, error)
+            return
+
+        try:
+            await member.kick(reason=reason)
+        except discord.HTTPException as e:
+            logger.error("Failed to kick unverified %s: %s", member, e)
+            return
+
+        self.bot.verification.remove(member_id)
+        logger.info("Kicked %s: %s", member, reason)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the verification cog."""
+    await bot.add_cog(VerificationCog(bot))
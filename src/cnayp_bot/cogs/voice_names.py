@@ -0,0 +1,150 @@
+"""Voice channel auto-naming with live occupancy."""
+
+import asyncio
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..helpers.permissions import MissingPermissionsError, check_channel_permissions
+from ..helpers.ratelimit import SlidingWindowLimiter
+from ..services.governor import Priority
+
+logger = logging.getLogger(__name__)
+
+# Discord allows only 2 channel name changes per 10 minutes per channel
+RENAME_LIMIT = 2
+RENAME_WINDOW = timedelta(minutes=10)
+MAX_CHANNEL_NAME_LENGTH = 100
+
+
+def format_channel_name(base_name: str, event_name: str | None, count: int) -> str:
+    """Build the channel name for the given occupancy.
+
+    Empty channels go back to their base name.
+    """
+    if count == 0:
+        return base_name
+
+    name = settings.voice_autoname_format.format(name=event_name or base_name, count=count)
+    return name[:MAX_CHANNEL_NAME_LENGTH]
+
+
+class VoiceNamesCog(commands.Cog):
+    """Renames configured voice channels to show occupancy or the current event."""
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.limiter = SlidingWindowLimiter(RENAME_LIMIT, RENAME_WINDOW)
+        self.pending_updates: dict[int, asyncio.Task] = {}
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.voice_autoname_channels:
+            logger.info("No voice channels configured for auto-naming")
+            return
+
+        self.refresh_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.refresh_loop.cancel()
+        for task in self.pending_updates.values():
+            task.cancel()
+
+    @commands.Cog.listener()
+    async def on_voice_state_update(
+        self,
+        member: discord.Member,
+        before: discord.VoiceState,
+        after: discord.VoiceState,
+    ) -> None:
+        """Schedule a rename when someone joins or leaves a configured channel."""
+        if before.channel == after.channel:
+            return
+
+        for channel in (before.channel, after.channel):
+            if channel and channel.id in settings.voice_autoname_channels:
+                self.schedule_update(channel.id)
+
+    @tasks.loop(minutes=1)
+    async def refresh_loop(self) -> None:
+        """Pick up event start/end changes that don't come with a voice update."""
+        if not self.bot.leader.is_leader:
+            return
+        for channel_id in settings.voice_autoname_channels:
+            self.schedule_update(channel_id)
+
+    @refresh_loop.before_loop
+    async def before_refresh_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    def schedule_update(self, channel_id: int) -> None:
+        """Queue a rename for a channel unless one is already waiting.
+
+        The pending rename computes the name when it runs, so bursts of joins
+        collapse into a single edit.
+        """
+        task = self.pending_updates.get(channel_id)
+        if task and not task.done():
+            return
+
+        self.pending_updates[channel_id] = asyncio.create_task(self._update_channel(channel_id))
+
+    async def _update_channel(self, channel_id: int) -> None:
+        """Rename a channel once the rate limit allows it."""
+        self.bot.governor.tag("voice_names", Priority.BACKGROUND)
+        now = datetime.now(ZoneInfo("UTC"))
+        delay = self.limiter.delay(channel_id, now)
+        if delay > timedelta(0):
+            logger.info("Rename of channel %d delayed by %s", channel_id, delay)
+            await asyncio.sleep(delay.total_seconds())
+
+        channel = self.bot.get_channel(channel_id)
+        if not isinstance(channel, discord.VoiceChannel):
+            logger.error("Voice channel not found: %d", channel_id)
+            return
+
+        base_name = settings.voice_autoname_channels[channel_id]
+        count = len([member for member in channel.members if not member.bot])
+        name = format_channel_name(base_name, self._current_event_name(base_name), count)
+        if name == channel.name:
+            return
+
+        try:
+            check_channel_permissions(channel, "view_channel", "manage_channels")
+        except MissingPermissionsError as e:
+            logger.error("Can't rename voice channel %d: %s", channel_id, e)
+            return
+
+        if settings.observer_mode:
+            self.bot.observer.record("rename channel", channel=channel.name, name=name)
+            return
+
+        try:
+            await channel.edit(name=name, reason="Voice channel occupancy update")
+            self.limiter.record(channel_id, datetime.now(ZoneInfo("UTC")))
+            logger.info("Renamed voice channel %d to %s", channel_id, name)
+        except discord.HTTPException as e:
+            logger.error("Failed to rename voice channel %d: %s", channel_id, e)
+
+    def _current_event_name(self, base_name: str) -> str | None:
+        """Return the name of the event running in the channel, if any."""
+        if base_name != settings.discord_voice_channel:
+            return None
+
+        scheduler = self.bot.get_cog("SchedulerCog")
+        if not scheduler:
+            return None
+
+        event = scheduler.get_current_event()
+        return event.name if event else None
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the voice names cog."""
+    await bot.add_cog(VoiceNamesCog(bot))
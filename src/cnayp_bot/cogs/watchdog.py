@@ -0,0 +1,121 @@
+"""Watchdog for expected actions that silently didn't happen."""
+
+import logging
+from datetime import date, datetime
+from zoneinfo import ZoneInfo
+
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..scheduling import WATCHDOG_GRACE, digest_overdue, discord_event_overdue, in_quiet_hours
+from ..services.in_flight import drained
+from .digest import CURRENT, DIGEST
+from .scheduler import DISCORD_EVENTS
+
+logger = logging.getLogger(__name__)
+
+
+class WatchdogCog(commands.Cog):
+    """Checks that the digest was posted and Discord events were created on time.
+
+    Many failures are only logged, such as a missing channel or permission.
+    The watchdog looks at the outcome instead and alerts the ops channel once
+    per missed action.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self.alerted: set[str] = set()  # "digest:date" or "event:event_id"
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.discord_ops_channel and not settings.discord_errors_channel:
+            logger.info("No ops or errors channel configured, watchdog disabled")
+            return
+
+        self.watchdog_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.watchdog_loop.cancel()
+
+    @tasks.loop(minutes=1)
+    @drained("watchdog")
+    async def watchdog_loop(self) -> None:
+        """Alert about overdue digests and Discord events."""
+        # The checked actions are paused in maintenance and only run on the leader
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        # Overdue work during a Discord API incident is Discord's, not ours
+        if self.bot.incidents.discord_incident:
+            logger.info(
+                "Watchdog paused during Discord incident: %s",
+                self.bot.incidents.discord_incident.name,
+            )
+            return
+
+        self.bot.governor.tag("watchdog")
+        try:
+            await self._check_digest()
+            await self._check_discord_events()
+        except Exception as e:
+            logger.exception("Error in watchdog loop: %s", e)
+
+    @watchdog_loop.before_loop
+    async def before_watchdog_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def _check_digest(self) -> None:
+        """Alert if today's digest wasn't posted by its time plus the grace period."""
+        config = self.bot.schedules.config
+        if not config.digest_time or not config.digest_channel:
+            return
+
+        now = datetime.now(ZoneInfo(settings.default_timezone))
+        posted = self.bot.store.get(DIGEST, CURRENT)
+        last_posted = date.fromisoformat(posted["date"]) if posted else None
+        # The digest is held back during quiet hours, and posted when they end
+        quiet = any(
+            in_quiet_hours(moment, settings.quiet_hours_start, settings.quiet_hours_end)
+            for moment in (now, now - WATCHDOG_GRACE)
+        )
+        if digest_overdue(now, config.digest_time, last_posted) and not quiet:
+            await self._alert(
+                f"digest:{now.date()}",
+                f"The daily digest should have been posted in #{config.digest_channel} "
+                f"by {config.digest_time} + {WATCHDOG_GRACE.seconds // 60} min, but wasn't.",
+            )
+
+    async def _check_discord_events(self) -> None:
+        """Alert for upcoming events whose Discord scheduled event wasn't created in time."""
+        scheduler = self.bot.get_cog("SchedulerCog")
+        if not scheduler:
+            return
+
+        now = datetime.now(ZoneInfo("UTC"))
+        for event in list(scheduler.known_events.values()):
+            if not discord_event_overdue(event, now):
+                continue
+            if self.bot.store.get(DISCORD_EVENTS, event.id) is not None:
+                continue
+            start = int(event.start_time.timestamp())
+            await self._alert(
+                f"event:{event.id}",
+                f"The Discord event for **{event.name}** (<t:{start}:F>) should exist by now, "
+                f"but wasn't created. Check the logs for why.",
+            )
+
+    async def _alert(self, key: str, message: str) -> None:
+        """Log and post an alert to the ops channel, once per key."""
+        if key in self.alerted:
+            return
+        self.alerted.add(key)
+        logger.warning("Watchdog: %s", message)
+        await self.bot.messenger.alert_ops(f"🐶 Watchdog: {message}")
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the watchdog cog."""
+    await bot.add_cog(WatchdogCog(bot))
@@ -0,0 +1,103 @@
+"""Welcome DMs sent to new members over their first days."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands, tasks
+
+from ..config import settings
+from ..services.governor import Priority
+from ..services.in_flight import drained
+
+logger = logging.getLogger(__name__)
+
+
+class WelcomeCog(commands.Cog):
+    """Sends each new member the configured sequence of welcome DMs.
+
+    The sequence starts once a member passes membership screening, counting
+    days from then. Progress is kept in the store, so a restart resumes the
+    sequence. Members who leave or have DMs closed are dropped from it.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+
+    async def cog_load(self) -> None:
+        """Called when the cog is loaded."""
+        if not settings.welcome_messages:
+            logger.info("No welcome messages configured, welcome DMs disabled")
+            return
+
+        self.welcome_loop.start()
+
+    async def cog_unload(self) -> None:
+        """Called when the cog is unloaded."""
+        self.welcome_loop.cancel()
+
+    @commands.Cog.listener()
+    async def on_member_screened(self, member: discord.Member) -> None:
+        """Enroll new members in the welcome sequence once they accepted the rules."""
+        if not settings.welcome_messages:
+            return
+
+        self.bot.welcome.start(member.id, datetime.now(ZoneInfo("UTC")))
+
+    @commands.Cog.listener()
+    async def on_member_remove(self, member: discord.Member) -> None:
+        """Drop members who left from the welcome sequence."""
+        if member.guild.id == settings.discord_guild_id:
+            self.bot.welcome.stop(member.id)
+
+    @tasks.loop(minutes=5)
+    @drained("welcome DMs")
+    async def welcome_loop(self) -> None:
+        """Send the welcome DMs that are due."""
+        if self.bot.maintenance.active or not self.bot.leader.is_leader:
+            return
+
+        self.bot.governor.tag("welcome", Priority.BACKGROUND)
+        try:
+            guild = self.bot.get_guild(settings.discord_guild_id)
+            if not guild:
+                return
+
+            now = datetime.now(ZoneInfo("UTC"))
+            for member_id, day in self.bot.welcome.due(now, settings.welcome_messages):
+                await self._send_step(guild, member_id, day)
+        except Exception as e:
+            logger.exception("Error in welcome loop: %s", e)
+
+    @welcome_loop.before_loop
+    async def before_welcome_loop(self) -> None:
+        """Wait for the bot to be ready before starting the loop."""
+        await self.bot.wait_until_ready()
+
+    async def _send_step(self, guild: discord.Guild, member_id: int, day: int) -> None:
+        """DM a member one step of the sequence."""
+        member = guild.get_member(member_id)
+        if member is None:
+            # Left while the bot was offline
+            self.bot.welcome.stop(member_id)
+            return
+
+        message = settings.welcome_messages[day].format(name=member.display_name, guild=guild.name)
+        try:
+            await self.bot.messenger.send(member, message)
+        except discord.Forbidden:
+            logger.info("%s doesn't accept DMs, ending their welcome sequence", member)
+            self.bot.welcome.stop(member_id)
+            return
+        except discord.HTTPException as e:
+            logger.error("Failed to send welcome DM to %s: %s", member, e)
+            return
+
+        logger.info("Sent day %d welcome DM to %s", day, member)
+        self.bot.welcome.mark_sent(member_id, day, settings.welcome_messages)
+
+
+async def setup(bot: commands.Bot) -> None:
+    """Set up the welcome cog."""
+    await bot.add_cog(WelcomeCog(bot))
@@ -1,28 +1,441 @@
 """Configuration using Pydantic Settings."""
 
-from pydantic_settings import BaseSettings, SettingsConfigDict
+import socket
+from datetime import time
+from pathlib import Path
+from string import Formatter
+from typing import Any, Literal, Self
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+from pydantic import BaseModel, Field, field_validator, model_validator
+from pydantic.fields import FieldInfo
+from pydantic_settings import BaseSettings, PydanticBaseSettingsSource, SettingsConfigDict
+
+from .helpers.config_file import SETTINGS_TABLE, load_toml
+from .helpers.snowflake import Snowflake
+
+
+class PeerBot(BaseModel):
+    """Another bot of the fleet this bot exchanges tasks with."""
+
+    url: str  # Base URL of the peer's API server
+    secret: str  # Shared with the peer, signing requests both ways
+
+
+class StatusPage(BaseModel):
+    """A public status page polled for incidents."""
+
+    url: str  # Base URL, e.g. https://discordstatus.com
+    provider: Literal["statuspage", "instatus"] = "statuspage"
+
+
+class ConfigFileSource(PydanticBaseSettingsSource):
+    """The `[settings]` table of the TOML config file named by CONFIG_FILE."""
+
+    def __init__(
+        self, settings_cls: type[BaseSettings], *path_sources: PydanticBaseSettingsSource
+    ) -> None:
+        super().__init__(settings_cls)
+        # CONFIG_FILE itself comes from the environment or .env
+        self.path_sources = path_sources
+
+    def get_field_value(self, field: FieldInfo, field_name: str) -> tuple[Any, str, bool]:
+        # Unused, __call__ returns the whole table
+        return None, field_name, False
+
+    def path(self) -> Path | None:
+        """Return the config file's path, if it's set."""
+        paths = (source().get("config_file") for source in self.path_sources)
+        return next((Path(path) for path in paths if path), None)
+
+    def __call__(self) -> dict[str, Any]:
+        path = self.path()
+        return load_toml(path).get(SETTINGS_TABLE, {}) if path else {}
 
 
 class Settings(BaseSettings):
-    """Bot configuration from environment variables."""
+    """Bot configuration from environment variables, .env, and the config file, in that order."""
 
     model_config = SettingsConfigDict(env_file=".env", env_file_encoding="utf-8", extra="ignore")
 
-    discord_bot_token: str
-    discord_guild_id: int
+    # TOML file with a [settings] table of these settings, which environment variables
+    # override, and the schedules (see helpers/config_file.py); validate it with
+    # `python -m cnayp_bot check`
+    config_file: str | None = None
+
+    # The token, or a file holding it, e.g. a Docker or Kubernetes secret
+    discord_bot_token: str = ""
+    discord_bot_token_file: str | None = None
+    discord_guild_id: Snowflake
+    # Other guilds the bot also serves, e.g. a second community server: slash
+    # commands are registered in each, and schedules with their `guild_id` run there
+    discord_extra_guild_ids: list[Snowflake] = []
     discord_notify_channel: str = "events"
     discord_voice_channel: str = "K8s | KCNA"
+    discord_errors_channel: str | None = None
+    # Watchdog and escalation alerts for organizers (errors channel if unset)
+    discord_ops_channel: str | None = None
+    # Link scan reports (ops channel if unset) and deleted message logs for moderators
+    mod_channel: str | None = None
+    # Short entries on what the bot did and why: Discord events created and
+    # cancelled, digests posted, schedules reloaded, API outages, and reconnects
+    audit_log_channel: str | None = None
+    discord_announcements_channel: str = "announcements"
+    notification_role: str = "Event Notifications"
+
+    # Mass-mention guard: @everyone/@here/role pings allowed per channel per hour
+    mention_limit_per_hour: int = 6
+    mention_guard_action: Literal["downgrade", "block"] = "downgrade"
+    # Who reminders and start notifications ping: "role" (the notification role),
+    # "everyone", "here", or "none"
+    reminder_ping: Literal["role", "everyone", "here", "none"] = "role"
+    start_ping: Literal["role", "everyone", "here", "none"] = "everyone"
+    # Messages sent as @silent, without push notifications; schedules can override
+    # this for their announcements, reminders, and start notifications
+    silent_messages: list[Literal["announcement", "reminder", "start", "digest"]] = ["digest"]
+    # Minutes after the start by which one of a schedule's owners should be in the
+    # voice channel, or they're DMed (0 turns this off), and the holding message
+    # posted in the notification channel meanwhile (none if unset)
+    host_check_minutes: int = 5
+    host_holding_message: str | None = None
+    # Minutes before an event the members interested in it or going to it are DMed
+    # a reminder (0 turns this off); members turn them off with /eventdms off
+    dm_reminder_minutes: int = Field(default=15, ge=0)
+    # Quiet hours, e.g. 23:00 to 08:00, possibly spanning midnight: reminders due during
+    # them in their event's timezone are held back until they end, if their events haven't
+    # started by then, and so are the daily and weekly digests, in DEFAULT_TIMEZONE
+    quiet_hours_start: time | None = None
+    quiet_hours_end: time | None = None
+    # Messages sent in a channel per minute at most, further ones waiting for a slot,
+    # e.g. when many schedules fire together (0: no limit)
+    channel_messages_per_minute: int = Field(default=10, ge=0)
+
+    # Put Going, Maybe, and Can't go buttons on every announcement, not only on
+    # events with a capacity
+    rsvp_all_events: bool = False
+
+    # Register the slash commands in the guild on startup, when they changed
+    sync_commands: bool = True
+
+    # Build info baked into the Docker image, shown by /version
+    build_commit: str = ""
+    build_date: str = ""
+    # GitHub repository ("owner/name") whose releases are checked every
+    # `update_check_hours`, telling the ops channel when a newer version is out
+    update_check_repo: str | None = None
+    update_check_hours: int = 6
+
+    # Lowest level logged, and "json" for one JSON object per line with fields such as
+    # guild_id and event_id, e.g. for Loki, instead of plain text
+    log_level: Literal["DEBUG", "INFO", "WARNING", "ERROR"] = "INFO"
+    log_format: Literal["text", "json"] = "text"
+
+    # Record what the bot would do instead of writing to Discord (for shadow runs)
+    observer_mode: bool = False
+    # Crash reports sent to Sentry, tagged by subsystem (needs the "sentry" extra)
+    sentry_dsn: str | None = None
+    sentry_environment: str = "production"
+    # Features ("announcements", "digest") posting in the canary channel for
+    # `canary_days` after their first post, before moving to their real channels
+    canary_channel: str | None = None
+    canary_features: list[str] = []
+    canary_days: int = 7
+
+    # Running several replicas: optional sharding, or a lease file on a shared volume
+    # electing the one replica that runs the scheduler and digests and answers commands,
+    # the others standing by; STORE_PATH is then a .db file beside it
+    shard_id: int | None = None
+    shard_count: int | None = None
+    leader_lease_path: str | None = None
+    leader_lease_seconds: int = 30
+    instance_id: str = Field(default_factory=socket.gethostname)
+    # Seconds without a heartbeat ACK before the gateway connection is treated as
+    # a zombie, closed, and reopened; Discord asks for a heartbeat every ~41 seconds
+    gateway_heartbeat_timeout: float = 45.0
+    # Seconds the shutdown on SIGTERM or SIGINT waits for running reminders, digests,
+    # and event creation to finish; keep it under the container's stop timeout
+    shutdown_timeout_seconds: float = Field(default=8, ge=0)
+    # Discord API failures in a row (server errors, 429s, network errors) after which
+    # background requests are dropped and normal ones held back for the cooldown (0: never)
+    circuit_failure_threshold: int = 5
+    circuit_cooldown_seconds: int = 60
+
+    # Discord REST API version, e.g. to try the next one in staging, and the User-Agent
+    # of requests, to tell instances apart; Discord wants it to start with
+    # "DiscordBot (<url>, <version>)", and discord.py's own is used when unset
+    discord_api_version: int = Field(default=10, ge=10)
+    discord_user_agent: str | None = None
+
+    # Signs button/select custom IDs; derived from the bot token when unset
+    component_secret: str | None = None
 
     google_calendar_id: str
     google_service_account_file: str | None = None
 
+    # Meetings of hybrid events: a Zoom Server-to-Server OAuth app and the user
+    # hosting its meetings, and the Workspace user Google Meet spaces are created
+    # as (needs domain-wide delegation for the service account)
+    zoom_account_id: str | None = None
+    zoom_client_id: str | None = None
+    zoom_client_secret: str | None = None
+    zoom_user: str = "me"
+    google_meet_user: str | None = None
+
     # Webhook settings for real-time calendar notifications
     webhook_enabled: bool = False
     webhook_host: str = "0.0.0.0"
     webhook_port: int = 8080
     webhook_url: str | None = None
 
+    # Event submission API (POST /api/events), queued for approval in the submissions channel
+    api_token: str | None = None
+    api_host: str = "0.0.0.0"
+    api_port: int = 8081
+    # Public URL the API server is reached at, e.g. "https://events.example.com"; when
+    # set, each guild's public schedules are served as an iCal feed at
+    # /calendar/<guild ID>.ics for calendar apps to subscribe to
+    api_public_url: str | None = None
+    # GitHub webhook (POST /api/github) secret, and the channel each repository's
+    # releases are posted in, e.g. {"kubernetes/kubernetes": "k8s-releases"}
+    github_webhook_secret: str | None = None
+    github_release_channels: dict[str, str] = {}
+    # Alertmanager webhook (POST /api/alertmanager, authorized with API_TOKEN): alerts
+    # are posted in the alerts channel (ops channel if unset), and the Silence
+    # buttons create silences through the Alertmanager API at this URL
+    alerts_channel: str | None = None
+    alertmanager_url: str | None = None
+    # Status pages polled for incidents, by name, e.g. {"Discord": {"url":
+    # "https://discordstatus.com"}}; notices go to the status channel (ops channel if
+    # unset), and Discord API incidents hold back scheduler and watchdog alerts
+    status_pages: dict[str, StatusPage] = {}
+    status_channel: str | None = None
+    status_poll_minutes: int = 2
+    # RSS or Atom feeds members subscribe to with /subscribe feed, by name, e.g.
+    # {"kubernetes-blog": "https://kubernetes.io/feed.xml"}; feeds with subscribers
+    # are polled every `feed_poll_minutes`, and new items DMed to them
+    feeds: dict[str, str] = {}
+    feed_poll_minutes: int = Field(default=15, gt=0)
+    # Other bots of the fleet exchanging tasks through POST /api/peer/tasks, by name,
+    # and the name this bot signs its own requests with
+    peer_bots: dict[str, PeerBot] = {}
+    peer_name: str = "events"
+    submissions_channel: str | None = None
+
     reminder_minutes: list[int] = [45, 10]
 
+    # Hours a failing Discord event creation is retried before owners are alerted
+    event_retry_hours: float = 6
+    # Minutes an announcement, reminder, or start notification that failed with
+    # a server or network error is retried before the ops channel is alerted; 0 never retries
+    notification_retry_minutes: int = Field(default=10, ge=0)
+
+    # Recurring events defined locally, in addition to Google Calendar (the config
+    # file when it's set, else schedules.json)
+    schedules_file: str = "schedules.json"
+    # How often the schedules file is checked for edits, which are then reloaded
+    schedules_watch_seconds: int = 30
+    # Partials (`name.md`) announcement templates include with {>name}
+    templates_dir: str = "templates"
+    # A Google Sheet (shared with anyone with the link) or CSV URL organizers keep
+    # schedules in, synced into the schedules file every few minutes
+    schedule_sheet_url: str | None = None
+    schedule_sheet_minutes: int = 15
+    # Schedule import summaries, and sheet and schedules file errors, for
+    # organizers (ops channel if unset)
+    staff_channel: str | None = None
+    # Members without messages, reactions, or voice activity in this many days are
+    # listed in the staff channel each month, for organizers to follow up on by hand
+    # (0: no report); members with an exempt role, or who opted out, aren't listed
+    inactivity_days: int = Field(default=0, ge=0)
+    inactivity_exempt_roles: list[str] = []
+    # Private channel where the maintainers in `debug_admin_ids`, who must also be
+    # admins, type console operations to debug the live bot (off unless both are set)
+    debug_channel: str | None = None
+    debug_admin_ids: list[Snowflake] = []
+    # Event history (occurrences, RSVPs, attendance) written to a tab of a Google
+    # Sheet the Google credentials can edit, every few hours
+    attendance_sheet_id: str | None = None
+    attendance_sheet_tab: str = "Attendance"
+    attendance_sheet_hours: int = 24
+
+    # Persistent state (a SQLite database for .db paths, else a JSON file) and
+    # user-facing time defaults
+    store_path: str = "data/store.db"
+    default_timezone: str = "America/Lima"
+    # Up to 3 timezones each event's time is also shown in, in announcements and the
+    # digest, e.g. ["America/Lima", "Europe/Madrid", "America/New_York"]
+    reference_timezones: list[str] = Field(default=[], max_length=3)
+
+    # Digest of unanswered questions in the help channel
+    help_channel: str | None = None
+    help_unanswered_minutes: int = 120
+    help_digest_hours: int = 24
+    helper_role_ids: list[Snowflake] = []
+
+    # FAQ suggestions: channel ID -> minimum similarity (0-1) before suggesting a tag
+    faq_channels: dict[Snowflake, float] = {}
+
+    # Voice channel auto-naming: channel ID -> base name shown when empty
+    voice_autoname_channels: dict[Snowflake, str] = {}
+    voice_autoname_format: str = "🎤 {name} — {count} in call"
+
+    # Channel topics kept up to date: channel ID -> template with {next_event},
+    # {next_time}, {week_theme}, and {digest_link}
+    channel_topics: dict[Snowflake, str] = {}
+
+    # Topic votes: suggestions opened with /topics open are put to a reaction vote
+    # `topic_vote_hours` before the event, one emoji per suggestion, and the
+    # winner goes in the Discord event description `topic_vote_close_minutes`
+    # before it starts
+    topic_vote_hours: float = 24
+    topic_vote_close_minutes: int = 60
+    topic_vote_emoji: list[str] = [
+        "1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"
+    ]  # fmt: skip
+
+    # /findtime polls propose slots over the next `slot_finder_days` days
+    slot_finder_days: int = 7
+
+    # Schedule checklists: the channel their organizer threads are opened in (staff
+    # channel if unset), and hours before the event owners are pinged about open items
+    checklist_channel: str | None = None
+    checklist_nag_hours: int = 24
+
+    # Rotating bot presence; a leading "Watching", "Playing", "Listening to", or
+    # "Competing in" picks the activity type, anything else is a custom status
+    presence_messages: list[str] = [
+        "Watching {week_count} events this week",
+        "Next: {next_event} in {next_in}",
+    ]
+    presence_interval_minutes: int = 5
+
+    # Welcome DMs to new members: days after joining -> message ({name}, {guild})
+    welcome_messages: dict[int, str] = {}
+
+    # Verification gate: new members get the unverified role until they press
+    # Verify or react ✅ on the gate message posted by /verification in the
+    # verification channel, then get the verified role, if any
+    unverified_role: str | None = None
+    verified_role: str | None = None
+    verification_channel: str = "rules"
+    # Unverified members are reminded by DM after the timeout, and kicked after
+    # the given days (0 never kicks)
+    verification_timeout_hours: float = 24
+    verification_kick_days: float = 7
+
+    # Link scanning: a file of blocked domains, one per line, and a Google Safe
+    # Browsing API key. Messages with flagged links are deleted.
+    link_blocklist_file: str | None = None
+    safe_browsing_api_key: str | None = None
+    # Discord's native AutoMod keyword and mention spam rules, kept in this JSON
+    # file and applied to the guild on startup and every hour
+    automod_rules_file: str | None = None
+
+    # Recent messages remembered to log them once deleted (0 disables), and
+    # whether deleted messages that mentioned members are called out as ghost pings
+    message_cache_size: int = 5000
+    ghost_ping_callouts: bool = True
+    # Edits in these channels are diffed in the mod channel and kept for
+    # `edit_log_days`, except those by members with an exempt role
+    edit_log_channels: list[Snowflake] = []
+    edit_log_exempt_role_ids: list[Snowflake] = []
+    edit_log_days: int = 30
+
+    @classmethod
+    def settings_customise_sources(
+        cls,
+        settings_cls: type[BaseSettings],
+        init_settings: PydanticBaseSettingsSource,
+        env_settings: PydanticBaseSettingsSource,
+        dotenv_settings: PydanticBaseSettingsSource,
+        file_secret_settings: PydanticBaseSettingsSource,
+    ) -> tuple[PydanticBaseSettingsSource, ...]:
+        """Read the config file after the environment and .env, which override it."""
+        config_file = ConfigFileSource(settings_cls, init_settings, env_settings, dotenv_settings)
+        return init_settings, env_settings, dotenv_settings, config_file, file_secret_settings
+
+    @property
+    def discord_guild_ids(self) -> list[int]:
+        """The primary guild, then the extra ones."""
+        return [self.discord_guild_id, *self.discord_extra_guild_ids]
+
+    @field_validator("welcome_messages")
+    @classmethod
+    def check_welcome_fields(cls, messages: dict[int, str]) -> dict[int, str]:
+        """Reject welcome messages with placeholders that can't be filled."""
+        for message in messages.values():
+            for _, field, _, _ in Formatter().parse(message):
+                if field is not None and field not in ("name", "guild"):
+                    raise ValueError(f"Unknown placeholder {{{field}}}, use {{name}} or {{guild}}")
+        return messages
+
+    @field_validator("reference_timezones")
+    @classmethod
+    def check_reference_timezones(cls, timezones: list[str]) -> list[str]:
+        """Reject timezones that aren't in the IANA database."""
+        for timezone in timezones:
+            try:
+                ZoneInfo(timezone)
+            except (ZoneInfoNotFoundError, ValueError):
+                raise ValueError(
+                    f"Unknown timezone {timezone!r}, use an IANA name like America/Lima"
+                ) from None
+        return timezones
+
+    @model_validator(mode="after")
+    def check_quiet_hours(self) -> Self:
+        """Reject quiet hours with only a start or an end."""
+        if (self.quiet_hours_start is None) != (self.quiet_hours_end is None):
+            raise ValueError("Set both QUIET_HOURS_START and QUIET_HOURS_END, or neither")
+        return self
+
+    @model_validator(mode="after")
+    def check_replicas(self) -> Self:
+        """Reject a leader lease the replicas can't fail over with.
+
+        A new leader must know what the old one already sent, so the store is
+        a SQLite database on the lease's shared volume. Shards each hold their
+        own guilds, so no other shard can take over for them.
+        """
+        if not self.leader_lease_path:
+            return self
+        if self.shard_count:
+            raise ValueError(
+                "LEADER_LEASE_PATH can't be combined with sharding, run one replica per shard"
+            )
+        store_path, lease_path = Path(self.store_path), Path(self.leader_lease_path)
+        if store_path.suffix != ".db" or store_path.parent != lease_path.parent:
+            raise ValueError(
+                f"With LEADER_LEASE_PATH, set STORE_PATH to a .db file in {lease_path.parent}, "
+                "so every replica shares it"
+            )
+        return self
+
+    @model_validator(mode="after")
+    def resolve_files(self) -> Self:
+        """Read the token from its file, and keep the schedules in the config file by default."""
+        if self.discord_bot_token_file:
+            try:
+                token = Path(self.discord_bot_token_file).read_text(encoding="utf-8")
+            except OSError as error:
+                raise ValueError(
+                    f"Can't read DISCORD_BOT_TOKEN_FILE {self.discord_bot_token_file}: "
+                    f"{error.strerror}"
+                ) from None
+            self.discord_bot_token = token.strip()
+        if not self.discord_bot_token:
+            raise ValueError("Set DISCORD_BOT_TOKEN, or DISCORD_BOT_TOKEN_FILE to its file")
+        if self.config_file and "schedules_file" not in self.model_fields_set:
+            self.schedules_file = self.config_file
+        return self
+
+
+settings: Settings
+
 
-settings = Settings()
+def __getattr__(name: str) -> Any:
+    """Load the settings on first import, so `check` can import this module when they're invalid."""
+    if name == "settings":
+        globals()["settings"] = Settings()
+        return globals()["settings"]
+    raise AttributeError(f"module {__name__!r} has no attribute {name!r}")
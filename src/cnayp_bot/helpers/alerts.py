@@ -0,0 +1,139 @@
+"""Alertmanager webhook notifications rendered as Discord embeds."""
+
+from datetime import datetime
+from typing import Any
+
+import discord
+
+from .embeds import (
+    DESCRIPTION_LIMIT,
+    FIELD_NAME_LIMIT,
+    TITLE_LIMIT,
+    EmbedBuilder,
+)
+
+# Alerts listed in one embed, and the length of each; the rest are counted in the footer
+MAX_ALERTS = 10
+ALERT_LENGTH = 512
+# Footer space kept free for the count of alerts that didn't fit
+MORE_NOTE_LENGTH = 32
+FOOTER_LENGTH = 512
+
+SEVERITY_COLORS = {
+    "critical": discord.Color.red,
+    "error": discord.Color.red,
+    "warning": discord.Color.orange,
+    "info": discord.Color.blue,
+}
+SEVERITY_EMOJI = {"critical": "🔴", "error": "🔴", "warning": "🟠", "info": "🔵"}
+
+
+def silence_matchers(payload: dict[str, Any]) -> dict[str, str]:
+    """Return the labels a silence of the whole group should match.
+
+    Alertmanager groups by `groupLabels`; when it groups by nothing (or by
+    everything), the labels every alert shares are used instead.
+    """
+    return dict(payload.get("groupLabels") or payload.get("commonLabels") or {})
+
+
+def severity(payload: dict[str, Any]) -> str:
+    """Return the most severe `severity` label of the group's firing alerts."""
+    order = list(SEVERITY_COLORS)
+    levels = [
+        alert.get("labels", {}).get("severity", "").lower()
+        for alert in payload.get("alerts", [])
+        if alert.get("status") == "firing"
+    ]
+    known = [level for level in levels if level in order]
+    return min(known, key=order.index) if known else "warning"
+
+
+def alert_title(payload: dict[str, Any]) -> str:
+    """Title a notification, e.g. "🔥 FIRING (2): HighLatency"."""
+    alerts = payload.get("alerts", [])
+    firing = sum(alert.get("status") == "firing" for alert in alerts)
+    labels = payload.get("commonLabels", {})
+    name = labels.get("alertname") or ", ".join(
+        f"{key}={value}" for key, value in payload.get("groupLabels", {}).items()
+    )
+    if payload.get("status") == "resolved":
+        return f"✅ RESOLVED: {name or 'alerts'}"
+    return f"🔥 FIRING ({firing}): {name or 'alerts'}"
+
+
+def alert_line(alert: dict[str, Any]) -> tuple[str, str]:
+    """Return the field name and value describing one alert."""
+    labels = alert.get("labels", {})
+    annotations = alert.get("annotations", {})
+    if alert.get("status") == "resolved":
+        emoji = "✅"
+    else:
+        emoji = SEVERITY_EMOJI.get(labels.get("severity", "").lower(), "🟠")
+
+    target = labels.get("instance") or labels.get("service") or labels.get("job")
+    name = f"{emoji} {labels.get('alertname', 'Alert')}" + (f" · {target}" if target else "")
+
+    lines = [annotations.get("summary") or annotations.get("description") or "No summary."]
+    started = _parse_time(alert.get("startsAt"))
+    ended = _parse_time(alert.get("endsAt"))
+    if alert.get("status") == "resolved" and ended:
+        lines.append(f"Resolved <t:{int(ended.timestamp())}:R>")
+    elif started:
+        lines.append(f"Since <t:{int(started.timestamp())}:R>")
+    if alert.get("generatorURL"):
+        lines.append(f"[Source]({alert['generatorURL']})")
+
+    return _truncate(name, FIELD_NAME_LIMIT), _truncate("\n".join(lines), ALERT_LENGTH)
+
+
+def render_alerts(payload: dict[str, Any]) -> discord.Embed:
+    """Render an Alertmanager notification as a color-coded embed.
+
+    Firing groups take the color of their most severe alert, resolved groups
+    are green. Firing alerts are listed before resolved ones.
+    """
+    alerts = sorted(payload.get("alerts", []), key=lambda alert: alert.get("status") != "firing")
+    resolved = payload.get("status") == "resolved"
+    color = discord.Color.green() if resolved else SEVERITY_COLORS[severity(payload)]()
+
+    annotations = payload.get("commonAnnotations", {})
+    description = annotations.get("description") or annotations.get("summary") or ""
+    labels = ", ".join(f"{key}={value}" for key, value in silence_matchers(payload).items())
+    builder = (
+        EmbedBuilder()
+        .set_title(
+            _truncate(alert_title(payload), TITLE_LIMIT), url=payload.get("externalURL") or None
+        )
+        .set_description(_truncate(description, DESCRIPTION_LIMIT))
+        .set_color(color)
+        .set_footer(text=_truncate(labels, FOOTER_LENGTH))
+    )
+
+    shown = 0
+    for alert in alerts[:MAX_ALERTS]:
+        name, value = alert_line(alert)
+        if not builder.can_add_field(name, value + " " * MORE_NOTE_LENGTH):
+            break
+        builder.add_field(name=name, value=value, inline=False)
+        shown += 1
+
+    if len(alerts) > shown:
+        more = f"…and {len(alerts) - shown} more alerts"
+        builder.set_footer(text=f"{more} · {_truncate(labels, FOOTER_LENGTH)}")
+    return builder.build()
+
+
+def _truncate(text: str, limit: int) -> str:
+    """Cut text to `limit` characters, marking the cut."""
+    return text if len(text) <= limit else text[: limit - 1] + "…"
+
+
+def _parse_time(value: str | None) -> datetime | None:
+    """Parse an Alertmanager timestamp; unset end times are the zero time."""
+    if not value or value.startswith("0001-"):
+        return None
+    try:
+        return datetime.fromisoformat(value)
+    except ValueError:
+        return None
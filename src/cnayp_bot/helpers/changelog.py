@@ -0,0 +1,51 @@
+"""GitHub release notes rendered for Discord."""
+
+import re
+
+from .chunking import FENCE
+
+_COMMENT = re.compile(r"<!--.*?-->", re.DOTALL)
+_HEADING = re.compile(r"^(#{1,6})\s+(.+?)\s*#*\s*$")
+_IMAGE = re.compile(r"!\[([^\]]*)\]\((\S+?)(?:\s+\"[^\"]*\")?\)")
+_HTML_TAG = re.compile(r"</?(?:details|summary|p|div|sub|sup|br)\b[^>]*>", re.IGNORECASE)
+_REFERENCE = re.compile(
+    r"(?<![(\[<])https://github\.com/([\w.-]+/[\w.-]+)/(?:pull|issues)/(\d+)(?![\w/#])"
+)
+_MENTION = re.compile(r"(?<![\w/`\[])@([A-Za-z0-9][A-Za-z0-9-]{0,38})(?![\w-])")
+_BLANK_LINES = re.compile(r"\n{3,}")
+
+
+def _render_line(line: str, repo: str) -> str:
+    """Convert one line of GitHub markdown outside code blocks."""
+    if heading := _HEADING.match(line):
+        level, text = len(heading.group(1)), heading.group(2)
+        return f"__**{text}**__" if level <= 2 else f"**{text}**"
+
+    line = _HTML_TAG.sub("", line)
+    line = _IMAGE.sub(lambda m: f"[{m.group(1) or 'image'}]({m.group(2)})", line)
+
+    def reference(match: re.Match) -> str:
+        # Same-repo references read like GitHub shows them, e.g. #123
+        prefix = "" if match.group(1).lower() == repo.lower() else match.group(1)
+        return f"[{prefix}#{match.group(2)}]({match.group(0)})"
+
+    line = _REFERENCE.sub(reference, line)
+    return _MENTION.sub(lambda m: f"[@{m.group(1)}](https://github.com/{m.group(1)})", line)
+
+
+def render_changelog(markdown: str, repo: str) -> str:
+    """Convert a GitHub release body to Discord markdown.
+
+    Headings become bold lines, images become links, HTML comments and layout
+    tags are dropped, and issue, pull request, and user references become short
+    links. Code blocks are left untouched.
+    """
+    lines = []
+    in_code = False
+    for line in _COMMENT.sub("", markdown.replace("\r\n", "\n")).split("\n"):
+        if line.lstrip().startswith(FENCE):
+            in_code = not in_code
+            lines.append(line)
+        else:
+            lines.append(line if in_code else _render_line(line, repo))
+    return _BLANK_LINES.sub("\n\n", "\n".join(lines)).strip()
@@ -0,0 +1,21 @@
+"""Text bar charts for embeds."""
+
+BAR_WIDTH = 12
+_FULL = "█"
+_PARTIALS = " ▏▎▍▌▋▊▉"
+
+
+def bar(value: int, maximum: int, width: int = BAR_WIDTH) -> str:
+    """Draw a bar `width` characters long at full scale, with eighth-block precision."""
+    if maximum <= 0 or value <= 0:
+        return ""
+
+    eighths = round(value / maximum * width * 8)
+    full, remainder = divmod(eighths, 8)
+    return (_FULL * full + _PARTIALS[remainder]).rstrip() or _PARTIALS[1]
+
+
+def bar_chart(rows: list[tuple[str, int]], width: int = BAR_WIDTH) -> list[str]:
+    """Render labelled values as bars scaled to the largest value, one line per row."""
+    maximum = max((value for _, value in rows), default=0)
+    return [f"`{bar(value, maximum, width):<{width}}` {value} {label}" for label, value in rows]
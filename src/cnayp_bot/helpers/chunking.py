@@ -0,0 +1,43 @@
+"""Splitting content too long for one Discord message."""
+
+# https://discord.com/developers/docs/resources/message#create-message
+MESSAGE_LIMIT = 2000
+FENCE = "```"
+
+
+def split_message(text: str, limit: int = MESSAGE_LIMIT) -> list[str]:
+    """Split text into chunks of at most `limit` characters at line breaks.
+
+    A code block split across chunks is closed at the end of one chunk and
+    reopened at the start of the next. Lines too long for any chunk are cut.
+    """
+    chunks: list[str] = []
+    lines: list[str] = []
+    fence: str | None = None  # Opening line of the code block being split
+
+    def flush() -> None:
+        nonlocal lines
+        content = lines[1:] if fence and lines and lines[0] == fence else lines
+        if any(line.strip() for line in content):
+            chunks.append("\n".join(lines) + (f"\n{FENCE}" if fence else ""))
+        lines = [fence] if fence else []
+
+    for line in text.split("\n"):
+        while True:
+            used = sum(len(part) + 1 for part in lines)
+            room = limit - used - (len(FENCE) + 1 if fence else 0)
+            if len(line) <= room:
+                lines.append(line)
+                break
+            if lines != ([fence] if fence else []):
+                flush()
+                continue
+            lines.append(line[:room])
+            line = line[room:]
+            flush()
+
+        if line.lstrip().startswith(FENCE):
+            fence = None if fence else line.strip()
+
+    flush()
+    return chunks
@@ -0,0 +1,62 @@
+"""Usage and examples read from command docstrings, for help and error replies."""
+
+import re
+from dataclasses import dataclass, field
+
+USAGE = "Usage:"
+EXAMPLE = "Example:"
+
+# Commands in a usage line, which start it or follow a "|"
+_COMMAND_START = re.compile(r"(^|\| )!")
+
+
+@dataclass(frozen=True)
+class CommandDoc:
+    """A command's docstring split into its summary, details, usages, and examples."""
+
+    summary: str
+    details: str = ""
+    usages: list[str] = field(default_factory=list)
+    examples: list[str] = field(default_factory=list)
+
+
+def parse_command_doc(text: str | None) -> CommandDoc:
+    """Split a command docstring into its parts.
+
+    The first line is the summary; `Usage:` and `Example:` lines, which may
+    appear more than once, are collected; everything else is kept as details.
+    """
+    lines = (text or "").strip().splitlines()
+    if not lines:
+        return CommandDoc(summary="")
+
+    details, usages, examples = [], [], []
+    for line in lines[1:]:
+        line = line.strip()
+        if line.startswith(USAGE):
+            usages.append(line.removeprefix(USAGE).strip())
+        elif line.startswith(EXAMPLE):
+            examples.append(line.removeprefix(EXAMPLE).strip())
+        else:
+            details.append(line)
+
+    return CommandDoc(
+        summary=lines[0].strip(),
+        # The usage lines were separated from the text by blank lines
+        details=re.sub(r"\n{3,}", "\n\n", "\n".join(details)).strip(),
+        usages=usages,
+        examples=examples,
+    )
+
+
+def command_usages(doc: CommandDoc, prefix: str, name: str, signature: str) -> list[str]:
+    """Usages to show for a command, falling back to its signature.
+
+    Documented usages are written with `!`, so they're shown with the prefix
+    the member used instead.
+    """
+    if not doc.usages:
+        return [f"{prefix}{name} {signature}".strip()]
+    return [
+        _COMMAND_START.sub(lambda match: match.group(1) + prefix, usage) for usage in doc.usages
+    ]
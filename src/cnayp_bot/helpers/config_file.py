@@ -0,0 +1,130 @@
+"""The TOML config file: bot settings and schedules in one place.
+
+Settings go in a `[settings]` table, with the names of the environment
+variables in lowercase; everything else is the schedules file's content:
+
+    digest_time = "08:00"
+    digest_channel = "events"
+
+    [settings]
+    discord_guild_id = 123456789012345678
+    discord_bot_token_file = "/run/secrets/discord-bot-token"
+    log_level = "INFO"
+
+    [[schedules]]
+    name = "KCNA Study"
+    ...
+
+The bot writes schedule changes back to the file, so `dump_toml` turns the
+JSON form of the config into TOML. Comments aren't kept when it does.
+"""
+
+import json
+import os
+import re
+import tomllib
+from pathlib import Path
+from typing import Any
+
+# Table of the config file holding the bot settings
+SETTINGS_TABLE = "settings"
+
+_BARE_KEY = re.compile(r"^[A-Za-z0-9_-]+$")
+
+
+def is_toml(path: Path) -> bool:
+    """Check whether a config or schedules file is TOML rather than JSON."""
+    return path.suffix.lower() == ".toml"
+
+
+def load_toml(path: Path) -> dict[str, Any]:
+    """Read a TOML file.
+
+    Raises:
+        OSError: If the file can't be read.
+        ValueError: If it isn't valid TOML, saying where.
+    """
+    with path.open("rb") as f:
+        return tomllib.load(f)
+
+
+def read_config_file(path: Path) -> dict[str, Any]:
+    """Read a schedules file, JSON or TOML by its suffix, without the `[settings]` table.
+
+    Raises:
+        OSError: If the file can't be read.
+        ValueError: If it isn't valid JSON or TOML.
+    """
+    if not is_toml(path):
+        with path.open(encoding="utf-8") as f:
+            return json.load(f)
+    data = load_toml(path)
+    data.pop(SETTINGS_TABLE, None)
+    return data
+
+
+def write_config_file(path: Path, data: dict[str, Any]) -> None:
+    """Write a schedules file atomically, keeping the config file's `[settings]` table."""
+    if is_toml(path) and path.exists() and SETTINGS_TABLE in (existing := load_toml(path)):
+        data = {SETTINGS_TABLE: existing[SETTINGS_TABLE], **data}
+    path.parent.mkdir(parents=True, exist_ok=True)
+    tmp_path = path.with_suffix(path.suffix + ".tmp")
+    with tmp_path.open("w", encoding="utf-8") as f:
+        if is_toml(path):
+            f.write(dump_toml(data))
+        else:
+            json.dump(data, f, indent=2, ensure_ascii=False)
+            f.write("\n")
+    os.replace(tmp_path, path)
+
+
+def dump_toml(data: dict[str, Any]) -> str:
+    """Write JSON-like data as TOML, dropping None values, which TOML can't express."""
+    lines: list[str] = []
+    _write_table(lines, [], data)
+    return "\n".join(lines).lstrip("\n") + "\n"
+
+
+def _write_table(
+    lines: list[str], path: list[str], table: dict[str, Any], array: bool = False
+) -> None:
+    """Write a table's plain values under its header, then its subtables."""
+    if path:
+        name = ".".join(_key(part) for part in path)
+        lines += ["", f"[[{name}]]" if array else f"[{name}]"]
+    for key, value in table.items():
+        if value is not None and not _is_table(value) and not _is_table_array(value):
+            lines.append(f"{_key(key)} = {_value(value)}")
+    for key, value in table.items():
+        if _is_table(value):
+            _write_table(lines, [*path, key], value)
+        elif _is_table_array(value):
+            for item in value:
+                _write_table(lines, [*path, key], item, array=True)
+
+
+def _is_table(value: Any) -> bool:
+    return isinstance(value, dict)
+
+
+def _is_table_array(value: Any) -> bool:
+    return isinstance(value, list) and bool(value) and all(isinstance(v, dict) for v in value)
+
+
+def _key(key: str) -> str:
+    return key if _BARE_KEY.match(key) else json.dumps(key, ensure_ascii=False)
+
+
+def _value(value: Any) -> str:
+    """Format a plain value, or a list or table inside a list, inline."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, int | float):
+        return repr(value)
+    if isinstance(value, list):
+        return f"[{', '.join(_value(item) for item in value if item is not None)}]"
+    if isinstance(value, dict):
+        pairs = (f"{_key(k)} = {_value(v)}" for k, v in value.items() if v is not None)
+        return f"{{ {', '.join(pairs)} }}"
+    # JSON's string escapes are also TOML's
+    return json.dumps(str(value), ensure_ascii=False)
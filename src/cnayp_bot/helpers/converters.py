@@ -0,0 +1,39 @@
+"""Command argument converters, so commands get parsed values or a usage reply.
+
+Use them with `Annotated`, e.g. `length: Annotated[timedelta, Duration]`; a
+value that doesn't parse raises `commands.BadArgument`, which the errors cog
+answers with the message and the command's usage.
+"""
+
+from datetime import timedelta
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+from discord.ext import commands
+
+from .timeparse import parse_duration
+
+
+class Duration(commands.Converter[timedelta]):
+    """A duration such as `45m`, `1h30m`, or `7d`."""
+
+    async def convert(self, ctx: commands.Context, argument: str) -> timedelta:
+        """Parse the duration."""
+        try:
+            return parse_duration(argument)
+        except ValueError as e:
+            raise commands.BadArgument(
+                f"Invalid duration `{argument}`. Try `45m`, `12h`, or `7d`."
+            ) from e
+
+
+class Timezone(commands.Converter[ZoneInfo]):
+    """An IANA timezone name such as `America/Lima`."""
+
+    async def convert(self, ctx: commands.Context, argument: str) -> ZoneInfo:
+        """Look up the timezone."""
+        try:
+            return ZoneInfo(argument)
+        except (ZoneInfoNotFoundError, ValueError) as e:
+            raise commands.BadArgument(
+                f"Unknown timezone `{argument}`. Use a name like `America/Lima`."
+            ) from e
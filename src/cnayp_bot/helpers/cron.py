@@ -0,0 +1,133 @@
+"""Standard five-field cron expressions, for schedules that aren't weekly.
+
+Fields are minute, hour, day of month, month, and day of week, each `*`, a
+number or name, a range (`1-5`), a step (`*/15`, `8-18/2`), or a list of
+those. Days of the week run from 0 (Sunday) to 7 (Sunday again), and
+`TUE#1` picks the first Tuesday of the month. As in cron, when both the day
+of the month and the day of the week are restricted, either matching is
+enough; a field starting with `*`, such as `*/2`, doesn't count as restricted.
+"""
+
+from dataclasses import dataclass
+from datetime import date, time
+from functools import cache
+
+_MONTHS = ["jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"]
+_DAYS = ["sun", "mon", "tue", "wed", "thu", "fri", "sat"]
+
+# Field name, lowest and highest value, and the names standing for values
+_FIELDS = [
+    ("minute", 0, 59, {}),
+    ("hour", 0, 23, {}),
+    ("day of month", 1, 31, {}),
+    ("month", 1, 12, {name: number for number, name in enumerate(_MONTHS, start=1)}),
+    ("day of week", 0, 7, {name: number for number, name in enumerate(_DAYS)}),
+]
+
+
+@dataclass(frozen=True)
+class Cron:
+    """A parsed cron expression, with days of the week as Python weekdays (Monday is 0)."""
+
+    minutes: frozenset[int]
+    hours: frozenset[int]
+    days: frozenset[int]
+    months: frozenset[int]
+    weekdays: frozenset[int]
+    # (weekday, n) for the nth such weekday of the month, from `#`
+    nth_weekdays: frozenset[tuple[int, int]]
+    any_day: bool
+    any_weekday: bool
+
+    def matches(self, day: date) -> bool:
+        """Check whether the expression runs on a day."""
+        if day.month not in self.months:
+            return False
+        on_day = day.day in self.days
+        on_weekday = day.weekday() in self.weekdays or (
+            (day.weekday(), (day.day - 1) // 7 + 1) in self.nth_weekdays
+        )
+        if self.any_day or self.any_weekday:
+            return on_day and on_weekday
+        return on_day or on_weekday
+
+    def times(self) -> list[time]:
+        """Return the times of day the expression runs at, earliest first."""
+        return [
+            time(hour, minute) for hour in sorted(self.hours) for minute in sorted(self.minutes)
+        ]
+
+
+@cache
+def parse_cron(expression: str) -> Cron:
+    """Parse a five-field cron expression.
+
+    Raises:
+        ValueError: If the expression isn't valid.
+    """
+    parts = expression.lower().split()
+    if len(parts) != len(_FIELDS):
+        raise ValueError(
+            f"Cron expressions have 5 fields (minute hour day month weekday), not {len(parts)}"
+        )
+
+    minutes, hours, days, months = (
+        _parse_field(part, *field)[0] for part, field in zip(parts[:4], _FIELDS[:4], strict=True)
+    )
+    weekdays, nth_weekdays = _parse_field(parts[4], *_FIELDS[4])
+    return Cron(
+        minutes=minutes,
+        hours=hours,
+        days=days,
+        months=months,
+        # Cron counts from Sunday, Python from Monday
+        weekdays=frozenset((day - 1) % 7 for day in weekdays),
+        nth_weekdays=frozenset(((day - 1) % 7, n) for day, n in nth_weekdays),
+        # As in Vixie cron, steps over every day, e.g. "*/2", aren't restrictions
+        any_day=parts[2].startswith("*"),
+        any_weekday=parts[4].startswith("*"),
+    )
+
+
+def _parse_field(
+    text: str, name: str, low: int, high: int, names: dict[str, int]
+) -> tuple[frozenset[int], frozenset[tuple[int, int]]]:
+    """Parse one field into its values, and the `#` picks of the day of week field."""
+    values: set[int] = set()
+    nth: set[tuple[int, int]] = set()
+    for item in text.split(","):
+        if "#" in item and name == "day of week":
+            day, _, n = item.partition("#")
+            if not n.isdigit() or not 1 <= int(n) <= 5:
+                raise ValueError(f"Invalid {name} {item!r}, use e.g. TUE#1 for the first Tuesday")
+            nth.add((_value(day, name, low, high, names), int(n)))
+            continue
+
+        span, slash, step = item.partition("/")
+        if slash and (not step.isdigit() or int(step) == 0):
+            raise ValueError(f"Invalid step in {name} {item!r}")
+        if span == "*":
+            start, end = low, high
+        elif "-" in span:
+            first, _, last = span.partition("-")
+            start, end = _value(first, name, low, high, names), _value(last, name, low, high, names)
+        else:
+            start = _value(span, name, low, high, names)
+            end = high if step else start
+        if start > end:
+            raise ValueError(f"Invalid range in {name} {item!r}")
+        values.update(range(start, end + 1, int(step) if step else 1))
+    return frozenset(values), frozenset(nth)
+
+
+def _value(text: str, name: str, low: int, high: int, names: dict[str, int]) -> int:
+    """Read a single number or name of a field."""
+    if text.isalpha() and text[:3] in names:
+        value = names[text[:3]]
+    elif text.isdigit():
+        value = int(text)
+    else:
+        raise ValueError(f"Invalid {name} {text!r}")
+    if not low <= value <= high:
+        raise ValueError(f"The {name} must be between {low} and {high}, not {value}")
+    return value
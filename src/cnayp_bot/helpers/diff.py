@@ -0,0 +1,29 @@
+"""Line diffs of edited messages, rendered for Discord."""
+
+import difflib
+from itertools import islice
+
+# Lines around each change kept for context
+CONTEXT_LINES = 1
+
+
+def render_diff(before: str, after: str, limit: int) -> str:
+    """Render the changed lines between two texts as a `diff` code block.
+
+    Removed lines start with `-` and added ones with `+`. The block is cut to
+    fit in `limit` characters, marking the cut.
+    """
+    lines = difflib.unified_diff(
+        before.splitlines(), after.splitlines(), n=CONTEXT_LINES, lineterm=""
+    )
+    # Skip the file headers and hunk markers, which mean nothing for a message,
+    # and break up fences that would end the block early
+    body = "\n".join(
+        line.replace("```", "`\u200b``")
+        for line in islice(lines, 2, None)
+        if not line.startswith("@@")
+    )
+    fence_length = len("```diff\n\n```")
+    if len(body) > limit - fence_length:
+        body = body[: limit - fence_length - 1] + "…"
+    return f"```diff\n{body}\n```"
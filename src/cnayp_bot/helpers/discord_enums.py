@@ -0,0 +1,32 @@
+"""Typed names for the Discord API values the bot stores or sends as raw numbers and text.
+
+discord.py has enums for what it sends itself (`discord.EntityType`,
+`discord.PrivacyLevel`, `discord.ChannelType`); these cover the rest, e.g.
+the fields of raw requests and the statuses kept in the store.
+"""
+
+from enum import IntEnum, StrEnum
+
+
+class EventStatus(StrEnum):
+    """A scheduled event's status as tracked in the store, named as in `discord.EventStatus`.
+
+    Being strings, statuses written by earlier versions compare equal.
+    """
+
+    SCHEDULED = "scheduled"
+    ACTIVE = "active"
+    COMPLETED = "completed"
+    CANCELED = "canceled"
+
+
+class RecurrenceFrequency(IntEnum):
+    """How often a recurring scheduled event repeats, as numbered in its recurrence rule."""
+
+    YEARLY = 0
+    MONTHLY = 1
+    WEEKLY = 2
+    DAILY = 3
+
+    def __str__(self) -> str:
+        return self.name.lower()
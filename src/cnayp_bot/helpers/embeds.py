@@ -0,0 +1,179 @@
+"""Fluent embed builder that enforces Discord's embed limits."""
+
+from dataclasses import dataclass
+from datetime import datetime
+from typing import Self
+
+import discord
+
+from .chunking import split_message
+
+# https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+TITLE_LIMIT = 256
+DESCRIPTION_LIMIT = 4096
+FIELD_COUNT_LIMIT = 25
+FIELD_NAME_LIMIT = 256
+FIELD_VALUE_LIMIT = 1024
+FOOTER_LIMIT = 2048
+TOTAL_LIMIT = 6000
+
+
+class EmbedLimitError(ValueError):
+    """Raised when an embed exceeds one of Discord's limits."""
+
+
+@dataclass
+class _Field:
+    name: str
+    value: str
+    inline: bool
+
+
+class EmbedBuilder:
+    """Builds a `discord.Embed`, validating limits before it is sent.
+
+    Discord rejects the whole message when an embed is too large, so `build()`
+    raises `EmbedLimitError` listing every violated limit instead.
+    """
+
+    def __init__(self) -> None:
+        self._title = ""
+        self._url: str | None = None
+        self._description = ""
+        self._color: discord.Color | None = None
+        self._timestamp: datetime | None = None
+        self._footer = ""
+        self._fields: list[_Field] = []
+
+    def set_title(self, title: str, url: str | None = None) -> Self:
+        """Set the embed title, optionally linking it."""
+        self._title = title
+        self._url = url
+        return self
+
+    def set_description(self, description: str) -> Self:
+        """Set the embed description."""
+        self._description = description
+        return self
+
+    def set_color(self, color: discord.Color) -> Self:
+        """Set the embed color."""
+        self._color = color
+        return self
+
+    def set_timestamp(self, timestamp: datetime) -> Self:
+        """Set the embed timestamp."""
+        self._timestamp = timestamp
+        return self
+
+    def set_footer(self, text: str) -> Self:
+        """Set the footer text."""
+        self._footer = text
+        return self
+
+    def add_field(self, name: str, value: str, inline: bool = False) -> Self:
+        """Add a field."""
+        self._fields.append(_Field(name, value, inline))
+        return self
+
+    @property
+    def field_count(self) -> int:
+        """Number of fields added so far."""
+        return len(self._fields)
+
+    def total_length(self) -> int:
+        """Characters counted against the 6000 character embed total."""
+        fields = sum(len(field.name) + len(field.value) for field in self._fields)
+        return len(self._title) + len(self._description) + len(self._footer) + fields
+
+    def can_add_field(self, name: str, value: str) -> bool:
+        """Check whether a field would still fit within the limits."""
+        return (
+            len(self._fields) < FIELD_COUNT_LIMIT
+            and len(name) <= FIELD_NAME_LIMIT
+            and len(value) <= FIELD_VALUE_LIMIT
+            and self.total_length() + len(name) + len(value) <= TOTAL_LIMIT
+        )
+
+    def errors(self) -> list[str]:
+        """List every limit the embed currently violates."""
+        errors = []
+        if len(self._title) > TITLE_LIMIT:
+            errors.append(f"title is {len(self._title)} chars (max {TITLE_LIMIT})")
+        if len(self._description) > DESCRIPTION_LIMIT:
+            errors.append(
+                f"description is {len(self._description)} chars (max {DESCRIPTION_LIMIT})"
+            )
+        if len(self._footer) > FOOTER_LIMIT:
+            errors.append(f"footer is {len(self._footer)} chars (max {FOOTER_LIMIT})")
+        if len(self._fields) > FIELD_COUNT_LIMIT:
+            errors.append(f"{len(self._fields)} fields (max {FIELD_COUNT_LIMIT})")
+
+        for index, field in enumerate(self._fields):
+            if not field.name or not field.value:
+                errors.append(f"field {index} has an empty name or value")
+            if len(field.name) > FIELD_NAME_LIMIT:
+                errors.append(f"field {index} name is {len(field.name)} chars")
+            if len(field.value) > FIELD_VALUE_LIMIT:
+                errors.append(f"field {index} value is {len(field.value)} chars")
+
+        if self.total_length() > TOTAL_LIMIT:
+            errors.append(f"embed is {self.total_length()} chars in total (max {TOTAL_LIMIT})")
+
+        return errors
+
+    def validate(self) -> None:
+        """Raise `EmbedLimitError` if any limit is violated."""
+        errors = self.errors()
+        if errors:
+            raise EmbedLimitError("Embed exceeds Discord limits: " + "; ".join(errors))
+
+    def build(self) -> discord.Embed:
+        """Validate and build the embed."""
+        self.validate()
+
+        embed = discord.Embed(
+            title=self._title or None,
+            url=self._url,
+            description=self._description or None,
+            color=self._color,
+            timestamp=self._timestamp,
+        )
+        for field in self._fields:
+            embed.add_field(name=field.name, value=field.value, inline=field.inline)
+        if self._footer:
+            embed.set_footer(text=self._footer)
+
+        return embed
+
+    def build_pages(self) -> list[discord.Embed]:
+        """Build as many embeds as the content needs, to send one after another.
+
+        The description is split at line breaks, and fields move on to the next
+        embed once one is full. A field value too long for one field continues
+        in a "(cont.)" field. Only the first embed has the title, and only the
+        last the footer and timestamp.
+
+        Raises:
+            EmbedLimitError: If the title, footer, or a field name is too long.
+        """
+        pages = [EmbedBuilder().set_title(self._title, self._url)]
+        for index, part in enumerate(split_message(self._description, DESCRIPTION_LIMIT)):
+            if index:
+                pages.append(EmbedBuilder())
+            pages[-1].set_description(part)
+
+        for field in self._fields:
+            for index, part in enumerate(split_message(field.value, FIELD_VALUE_LIMIT)):
+                name = f"{field.name} (cont.)" if index else field.name
+                # The footer is counted on every page, so the last one still fits it
+                if not pages[-1].set_footer(self._footer).can_add_field(name, part):
+                    pages.append(EmbedBuilder())
+                pages[-1].add_field(name, part, field.inline)
+
+        for page in pages:
+            page.set_color(self._color).set_footer("")
+        pages[-1].set_footer(self._footer)
+        if self._timestamp:
+            pages[-1].set_timestamp(self._timestamp)
+        return [page.build() for page in pages]
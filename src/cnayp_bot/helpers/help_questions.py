@@ -0,0 +1,85 @@
+"""Picking the unanswered questions out of a help channel's messages."""
+
+from datetime import datetime, timedelta
+
+import discord
+
+from .embeds import DESCRIPTION_LIMIT
+
+MAX_DIGEST_ENTRIES = 15
+SNIPPET_LENGTH = 80
+
+
+def help_digest_due(now: datetime, last_run: datetime | None, hours: int) -> bool:
+    """Check whether the unanswered questions digest is due, `hours` after the last one.
+
+    Without a last run, e.g. on the first start, the first digest waits a
+    full interval, so restarts never repost it.
+    """
+    return last_run is not None and now - last_run >= timedelta(hours=hours)
+
+
+def question_window(
+    now: datetime, last_run: datetime, unanswered_minutes: int
+) -> tuple[datetime, datetime]:
+    """Return when the questions a digest covers were asked, after and up to.
+
+    Questions need `unanswered_minutes` without an answer to count, so the
+    window trails the runs by that much, and each run picks up where the last
+    one stopped.
+    """
+    wait = timedelta(minutes=unanswered_minutes)
+    return last_run - wait, now - wait
+
+
+def unanswered_messages(
+    history: list[discord.Message], answered_before: datetime
+) -> list[discord.Message]:
+    """Return the questions in a channel's history nobody replied or reacted to.
+
+    `history` runs from the start of the window up to now, so a question asked
+    inside the window still counts as answered if someone replied after the
+    window closed. Threads on the questions are checked separately.
+    """
+    replied_ids = {
+        message.reference.message_id
+        for message in history
+        if message.reference and message.reference.message_id
+    }
+    return [
+        message
+        for message in history
+        if not message.author.bot
+        and message.type is discord.MessageType.default
+        and message.created_at <= answered_before
+        and message.id not in replied_ids
+        and not message.reactions
+    ]
+
+
+def has_reply(
+    thread_messages: list[discord.Message], thread_id: int, author_id: int | None
+) -> bool:
+    """Check whether anyone other than the question author posted in a thread."""
+    return any(
+        message.id != thread_id and not message.author.bot and message.author.id != author_id
+        for message in thread_messages
+    )
+
+
+def digest_lines(questions: list[discord.Message]) -> list[str]:
+    """List questions as snippets linking to them, as many as fit in an embed description."""
+    lines: list[str] = []
+    length = 0
+    for message in questions[:MAX_DIGEST_ENTRIES]:
+        snippet = message.content.replace("\n", " ")
+        if len(snippet) > SNIPPET_LENGTH:
+            snippet = snippet[: SNIPPET_LENGTH - 1] + "…"
+        label = snippet or "Attachment"
+        line = f"• [{label}]({message.jump_url}) by {message.author.mention}"
+
+        length += len(line) + 1
+        if length > DESCRIPTION_LIMIT:
+            break
+        lines.append(line)
+    return lines
@@ -0,0 +1,53 @@
+"""Translations of slash commands and their replies for members' Discord languages."""
+
+import discord
+from discord import app_commands
+
+# Spanish text, keyed by the English it replaces: command names, parameter
+# names, and descriptions (the first line of the command's docstring), then
+# replies, which are filled in with `str.format` after translating
+SPANISH = {
+    "events": "horario",
+    "List upcoming events from Google Calendar, the schedules file, and submissions.": (
+        "Lista los próximos eventos de Google Calendar, el archivo de horarios y las propuestas."
+    ),
+    "days": "dias",
+    "Upcoming Events ({days} days)": "Próximos eventos ({days} días)",
+    "No events scheduled in the next {days} days.": (
+        "No hay eventos programados en los próximos {days} días."
+    ),
+    "Duration: {minutes} min": "Duración: {minutes} min",
+    "Showing {shown} of {total} events": "Mostrando {shown} de {total} eventos",
+}
+
+CATALOG: dict[discord.Locale, dict[str, str]] = {
+    discord.Locale.spain_spanish: SPANISH,
+    discord.Locale.latin_american_spanish: SPANISH,
+}
+
+
+def translate(text: str, locale: discord.Locale | None) -> str:
+    """Return text in a language from the catalog, or unchanged if there's no translation."""
+    return CATALOG.get(locale, {}).get(text, text)
+
+
+def context_locale(ctx) -> discord.Locale | None:
+    """Return the Discord language of whoever ran a command, or None for prefix commands."""
+    return ctx.interaction.locale if ctx.interaction else None
+
+
+class CatalogTranslator(app_commands.Translator):
+    """Registers slash commands with `name_localizations` and `description_localizations`.
+
+    Discord shows members the command in their client's language, e.g.
+    `/horario` for `/events` in Spanish, and falls back to English.
+    """
+
+    async def translate(
+        self,
+        string: app_commands.locale_str,
+        locale: discord.Locale,
+        context: app_commands.TranslationContext,
+    ) -> str | None:
+        """Return the catalog's translation, or None to leave it out."""
+        return CATALOG.get(locale, {}).get(string.message)
@@ -0,0 +1,63 @@
+"""Log output as text or JSON lines, with structured fields to query logs by."""
+
+import json
+import logging
+from datetime import UTC, datetime
+
+# Fields log calls attach with `extra`, e.g.
+# `logger.info("Created Discord event", extra={"event_id": event.id})`
+FIELDS = ("guild_id", "schedule", "event_id", "endpoint", "status")
+
+
+def record_fields(record: logging.LogRecord) -> dict:
+    """Return the structured fields set on a log record."""
+    return {
+        field: getattr(record, field)
+        for field in FIELDS
+        if getattr(record, field, None) is not None
+    }
+
+
+class TextFormatter(logging.Formatter):
+    """The usual one-line format, followed by the record's fields as key=value.
+
+    Values with spaces are quoted, as in logfmt.
+    """
+
+    def __init__(self) -> None:
+        super().__init__("%(asctime)s - %(name)s - %(levelname)s - %(message)s")
+
+    def format(self, record: logging.LogRecord) -> str:
+        text = super().format(record)
+        fields = " ".join(
+            f"{key}={json.dumps(value) if ' ' in str(value) else value}"
+            for key, value in record_fields(record).items()
+        )
+        if not fields:
+            return text
+        # After the first line, so tracebacks stay below
+        first, newline, rest = text.partition("\n")
+        return f"{first} {fields}{newline}{rest}"
+
+
+class JsonFormatter(logging.Formatter):
+    """One JSON object per line, e.g. for Loki to index the fields."""
+
+    def format(self, record: logging.LogRecord) -> str:
+        entry = {
+            "time": datetime.fromtimestamp(record.created, UTC).isoformat(),
+            "level": record.levelname,
+            "logger": record.name,
+            "message": record.getMessage(),
+            **record_fields(record),
+        }
+        if record.exc_info:
+            entry["exception"] = self.formatException(record.exc_info)
+        return json.dumps(entry, default=str, ensure_ascii=False)
+
+
+def configure_logging(level: str, output: str) -> None:
+    """Send logs at `level` and above to stderr, as "text" or "json"."""
+    handler = logging.StreamHandler()
+    handler.setFormatter(JsonFormatter() if output == "json" else TextFormatter())
+    logging.basicConfig(level=level, handlers=[handler], force=True)
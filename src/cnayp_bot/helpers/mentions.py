@@ -0,0 +1,65 @@
+"""Parsing of Discord message links and mentions in command arguments."""
+
+import re
+from dataclasses import dataclass
+
+from .snowflake import parse_snowflake
+
+CHANNEL_MENTION = re.compile(r"<#(\d+)>")
+USER_MENTION = re.compile(r"<@!?(\d+)>")
+ROLE_MENTION = re.compile(r"<@&(\d+)>")
+MESSAGE_LINK = re.compile(
+    r"https?://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/"
+    r"(?P<guild>\d+|@me)/(?P<channel>\d+)/(?P<message>\d+)"
+)
+
+
+@dataclass(frozen=True)
+class MessageLink:
+    """A link to a message; `guild_id` is None for direct messages."""
+
+    guild_id: int | None
+    channel_id: int
+    message_id: int
+
+
+def _parse_id(pattern: re.Pattern[str], text: str) -> int | None:
+    """Parse a mention matching `pattern`, or a bare ID, into a valid ID."""
+    text = text.strip()
+    match = pattern.fullmatch(text)
+    try:
+        return parse_snowflake(match.group(1) if match else text)
+    except ValueError:
+        return None
+
+
+def parse_channel_mention(text: str) -> int | None:
+    """Parse `<#id>` or a bare ID into a channel ID."""
+    return _parse_id(CHANNEL_MENTION, text)
+
+
+def parse_user_mention(text: str) -> int | None:
+    """Parse `<@id>`, `<@!id>`, or a bare ID into a user ID."""
+    return _parse_id(USER_MENTION, text)
+
+
+def parse_role_mention(text: str) -> int | None:
+    """Parse `<@&id>` or a bare ID into a role ID."""
+    return _parse_id(ROLE_MENTION, text)
+
+
+def parse_message_link(text: str) -> MessageLink | None:
+    """Parse a message link from any Discord client, or None if it isn't one."""
+    match = MESSAGE_LINK.fullmatch(text.strip().strip("<>"))
+    if not match:
+        return None
+
+    try:
+        guild = match.group("guild")
+        return MessageLink(
+            guild_id=None if guild == "@me" else parse_snowflake(guild),
+            channel_id=parse_snowflake(match.group("channel")),
+            message_id=parse_snowflake(match.group("message")),
+        )
+    except ValueError:
+        return None
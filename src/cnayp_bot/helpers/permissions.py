@@ -0,0 +1,101 @@
+"""Preflight checks of the bot's own permissions."""
+
+import discord
+
+
+class MissingPermissionsError(Exception):
+    """Raised when the bot lacks permissions an operation needs."""
+
+    def __init__(self, missing: list[str], where: str) -> None:
+        self.missing = missing
+        self.where = where
+        plural = "s" if len(missing) > 1 else ""
+        super().__init__(f"missing permission{plural} {', '.join(missing)} in {where}")
+
+
+def permission_name(flag: str) -> str:
+    """Turn a permission flag like `manage_events` into its UI name, `Manage Events`."""
+    return flag.replace("_", " ").title()
+
+
+def missing_permissions(permissions: discord.Permissions, *flags: str) -> list[str]:
+    """Return the UI names of the flags not granted by `permissions`, in order."""
+    return [permission_name(flag) for flag in flags if not getattr(permissions, flag)]
+
+
+def check_channel_permissions(channel: discord.abc.GuildChannel, *flags: str) -> None:
+    """Check the bot's effective permissions in a channel.
+
+    Effective permissions start from the bot's roles and apply the channel's
+    @everyone, role, and member overwrites, as `permissions_for` computes them.
+
+    Raises:
+        MissingPermissionsError: If any of `flags` is denied.
+    """
+    missing = missing_permissions(channel.permissions_for(channel.guild.me), *flags)
+    if missing:
+        raise MissingPermissionsError(missing, f"#{channel.name}")
+
+
+class PublicChannelError(Exception):
+    """Raised when a private event would be shown in a channel everyone can see."""
+
+    def __init__(self, channel: discord.abc.GuildChannel | discord.Thread) -> None:
+        self.channel = channel
+        super().__init__(f"#{channel.name} is visible to @everyone, and the event is private")
+
+
+def is_public(channel: discord.abc.GuildChannel | discord.Thread) -> bool:
+    """Check whether @everyone can see a channel, which a private thread never is."""
+    if isinstance(channel, discord.Thread) and channel.is_private():
+        return False
+    return channel.permissions_for(channel.guild.default_role).view_channel
+
+
+def check_private_channel(channel: discord.abc.GuildChannel | discord.Thread) -> None:
+    """Check that a channel is hidden from @everyone, before a private event uses it.
+
+    Raises:
+        PublicChannelError: If @everyone can see the channel.
+    """
+    if is_public(channel):
+        raise PublicChannelError(channel)
+
+
+def check_can_send(channel: discord.abc.GuildChannel, *, embeds: bool = False) -> None:
+    """Check that the bot can post in a channel, optionally with embeds."""
+    flags = ["view_channel", "send_messages"]
+    if embeds:
+        flags.append("embed_links")
+    check_channel_permissions(channel, *flags)
+
+
+def check_can_manage_roles(guild: discord.Guild, *roles: discord.Role) -> None:
+    """Check that the bot can give and remove roles, which must rank below its own."""
+    missing = missing_permissions(guild.me.guild_permissions, "manage_roles")
+    if missing:
+        raise MissingPermissionsError(missing, guild.name)
+    for role in roles:
+        if role >= guild.me.top_role:
+            raise MissingPermissionsError(
+                [permission_name("manage_roles")], f"@{role.name}, which ranks above the bot"
+            )
+
+
+def check_can_manage_automod(guild: discord.Guild, *, timeouts: bool = False) -> None:
+    """Check that the bot can manage AutoMod rules, optionally ones timing members out."""
+    flags = ["manage_guild", "moderate_members"] if timeouts else ["manage_guild"]
+    missing = missing_permissions(guild.me.guild_permissions, *flags)
+    if missing:
+        raise MissingPermissionsError(missing, guild.name)
+
+
+def check_can_manage_events(
+    guild: discord.Guild, channel: discord.VoiceChannel | None = None
+) -> None:
+    """Check that the bot can create scheduled events, optionally in a voice channel."""
+    missing = missing_permissions(guild.me.guild_permissions, "manage_events")
+    if missing:
+        raise MissingPermissionsError(missing, guild.name)
+    if channel:
+        check_channel_permissions(channel, "view_channel", "connect", "manage_events")
@@ -0,0 +1,55 @@
+"""Presence text built from upcoming events."""
+
+from datetime import datetime, timedelta
+
+from ..services.calendar import CalendarEvent
+
+# Placeholders available in presence messages
+PRESENCE_FIELDS = {"week_count", "next_event", "next_in"}
+
+# Leading words that pick the activity type; anything else is a custom status
+ACTIVITY_PREFIXES = {
+    "playing": "playing",
+    "watching": "watching",
+    "listening to": "listening",
+    "competing in": "competing",
+}
+
+PRESENCE_LIMIT = 128
+
+
+def format_countdown(delta: timedelta) -> str:
+    """Format a time span compactly in its largest unit, e.g. `2d`, `3h`, or `45m`."""
+    minutes = max(1, round(delta.total_seconds() / 60))
+    if minutes >= 24 * 60:
+        return f"{minutes // (24 * 60)}d"
+    if minutes >= 60:
+        return f"{minutes // 60}h"
+    return f"{minutes}m"
+
+
+def render_presence(
+    template: str, events: list[CalendarEvent], now: datetime, week_end: datetime
+) -> tuple[str, str] | None:
+    """Fill a presence message from upcoming events.
+
+    Returns:
+        The activity type (`playing`, `watching`, `listening`, `competing`, or
+        `custom`) and text, or None if the message mentions the next event and
+        there is none.
+    """
+    upcoming = sorted(
+        (event for event in events if event.start_time > now), key=lambda e: e.start_time
+    )
+    if not upcoming and ("{next_event}" in template or "{next_in}" in template):
+        return None
+
+    text = template.format(
+        week_count=sum(1 for event in upcoming if event.start_time < week_end),
+        next_event=upcoming[0].name if upcoming else "",
+        next_in=format_countdown(upcoming[0].start_time - now) if upcoming else "",
+    )
+    for prefix, kind in ACTIVITY_PREFIXES.items():
+        if text.lower().startswith(f"{prefix} "):
+            return kind, text[len(prefix) + 1 :][:PRESENCE_LIMIT]
+    return "custom", text[:PRESENCE_LIMIT]
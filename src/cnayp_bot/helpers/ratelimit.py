@@ -0,0 +1,35 @@
+"""Sliding window limiter for rate-limited Discord edits."""
+
+from collections import defaultdict, deque
+from collections.abc import Hashable
+from datetime import datetime, timedelta
+
+
+class SlidingWindowLimiter:
+    """Allows at most `max_actions` per key within a rolling time window.
+
+    Discord applies stricter limits to some edits than its regular REST
+    buckets (e.g. channel renames are capped at 2 per 10 minutes), so callers
+    ask for the remaining delay before acting instead of hitting a 429. An
+    action can be recorded at the time it's delayed to, before waiting, so
+    the callers asking meanwhile are queued behind it.
+    """
+
+    def __init__(self, max_actions: int, window: timedelta) -> None:
+        self.max_actions = max_actions
+        self.window = window
+        self._actions: dict[Hashable, deque[datetime]] = defaultdict(deque)
+
+    def delay(self, key: Hashable, now: datetime) -> timedelta:
+        """Return how long to wait before the next action for `key` is allowed."""
+        actions = self._actions[key]
+        while actions and now - actions[0] >= self.window:
+            actions.popleft()
+
+        if len(actions) < self.max_actions:
+            return timedelta(0)
+        return actions[-self.max_actions] + self.window - now
+
+    def record(self, key: Hashable, now: datetime) -> None:
+        """Record an action for `key`."""
+        self._actions[key].append(now)
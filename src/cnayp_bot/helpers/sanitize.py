@@ -0,0 +1,53 @@
+"""Sanitizing of organizer- and member-written text before it's posted by the bot."""
+
+import re
+
+# Discord's limits for scheduled events, also used for announcements
+EVENT_NAME_LIMIT = 100
+EVENT_DESCRIPTION_LIMIT = 1000
+
+ZERO_WIDTH_SPACE = "\u200b"
+
+MASS_MENTION = re.compile(r"@(everyone|here)")
+MENTION = re.compile(r"<@[!&]?\d+>")
+MASKED_LINK = re.compile(r"\[([^\]\n]*)\]\(<?(https?://[^\s)>]+)>?\)")
+HEADING = re.compile(r"^(?:#{1,3}|-#)[ \t]+", re.MULTILINE)
+BLANK_LINES = re.compile(r"\n{3,}")
+# Control characters other than tabs and newlines, and bidirectional overrides
+CONTROL = re.compile(r"[\x00-\x08\x0b-\x1f\x7f\u202a-\u202e\u2066-\u2069]")
+
+
+def neutralize_mentions(text: str) -> str:
+    """Break @everyone, @here, and user and role mentions so they show as text."""
+    text = MASS_MENTION.sub(f"@{ZERO_WIDTH_SPACE}\\1", text)
+    return MENTION.sub(lambda match: f"<@{ZERO_WIDTH_SPACE}{match[0][2:]}", text)
+
+
+def unmask_links(text: str) -> str:
+    """Show the URL of masked links (`[text](url)`), so their text can't disguise them."""
+    return MASKED_LINK.sub(lambda match: f"{match[1]} ({match[2]})", text)
+
+
+def sanitize_text(text: str, limit: int) -> str:
+    """Make text from a schedule, the calendar, or a submission safe to put in a message.
+
+    Mentions are neutralized, masked links show their URL, headings become
+    plain lines, control characters are dropped, runs of blank lines are
+    collapsed, and text over `limit` characters is cut, ending with "…".
+    """
+    text = CONTROL.sub("", text)
+    text = HEADING.sub("", text)
+    text = BLANK_LINES.sub("\n\n", text)
+    text = neutralize_mentions(unmask_links(text)).strip()
+    if len(text) <= limit:
+        return text
+    return text[: limit - 1].rstrip() + "…"
+
+
+def sanitize_template(template: str) -> str:
+    """Neutralize the mentions and masked links of an announcement template.
+
+    Templates keep their layout, so headings and blank lines are left alone,
+    and their length is checked when schedules are loaded.
+    """
+    return neutralize_mentions(unmask_links(CONTROL.sub("", template)))
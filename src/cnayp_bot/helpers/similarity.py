@@ -0,0 +1,46 @@
+"""Token similarity for matching questions against stored answers."""
+
+import math
+import re
+from collections import Counter
+
+_WORD = re.compile(r"[a-z0-9ñáéíóúü][a-z0-9ñáéíóúü+#.-]*")
+
+# Words too common to say anything about what a question is about
+STOPWORDS = {
+    # English
+    "a", "an", "and", "are", "as", "at", "be", "but", "by", "can", "do", "does", "for", "from",
+    "how", "i", "if", "in", "is", "it", "its", "me", "my", "of", "on", "or", "so", "that",
+    "the", "this", "to", "what", "when", "where", "which", "who", "why", "with", "you",
+    "anyone", "help", "please", "thanks", "hi", "hello",
+    # Spanish
+    "al", "como", "cómo", "con", "cual", "de", "del", "el", "en", "es", "la", "las", "lo",
+    "los", "me", "mi", "para", "por", "que", "qué", "se", "si", "un", "una", "y", "o",
+    "alguien", "ayuda", "hola", "gracias",
+}  # fmt: skip
+
+
+def tokenize(text: str) -> Counter[str]:
+    """Count the meaningful lowercase words in a text."""
+    words = (word.rstrip(".-") for word in _WORD.findall(text.lower()))
+    return Counter(word for word in words if word and word not in STOPWORDS)
+
+
+def cosine_similarity(a: Counter[str], b: Counter[str]) -> float:
+    """Cosine similarity of two token counts, from 0 (unrelated) to 1 (identical)."""
+    dot = sum(count * b[word] for word, count in a.items())
+    if not dot:
+        return 0.0
+    norm_a = math.sqrt(sum(count * count for count in a.values()))
+    norm_b = math.sqrt(sum(count * count for count in b.values()))
+    return dot / (norm_a * norm_b)
+
+
+def best_match(text: str, documents: dict[str, str]) -> tuple[str, float] | None:
+    """Return the key of the document most similar to `text` and its score."""
+    tokens = tokenize(text)
+    scored = [(cosine_similarity(tokens, tokenize(doc)), key) for key, doc in documents.items()]
+    if not scored:
+        return None
+    score, key = max(scored)
+    return (key, score) if score > 0 else None
@@ -0,0 +1,44 @@
+"""Discord snowflake IDs: parsing, validation, and creation times."""
+
+from datetime import datetime, timedelta
+from typing import Annotated
+from zoneinfo import ZoneInfo
+
+from pydantic import BeforeValidator, PlainSerializer
+
+# Snowflake timestamps count milliseconds from the first second of 2015
+DISCORD_EPOCH = datetime(2015, 1, 1, tzinfo=ZoneInfo("UTC"))
+MAX_SNOWFLAKE = 2**64 - 1
+
+
+def snowflake_time(snowflake: int) -> datetime:
+    """Return when the object with this ID was created."""
+    return DISCORD_EPOCH + timedelta(milliseconds=snowflake >> 22)
+
+
+def parse_snowflake(value: int | str) -> int:
+    """Parse a Discord ID from an int or a string of digits.
+
+    Raises:
+        ValueError: If the value isn't a valid ID, including IDs created in
+            the future, which usually means a mistyped digit.
+    """
+    if isinstance(value, bool) or not isinstance(value, int | str):
+        raise ValueError(f"Discord IDs are numbers, got {value!r}")
+    if isinstance(value, str):
+        if not value.strip().isdigit():
+            raise ValueError(f"Discord IDs are numbers, got {value!r}")
+        value = int(value)
+
+    if not 0 < value <= MAX_SNOWFLAKE:
+        raise ValueError(f"{value} is out of range for a Discord ID")
+    if snowflake_time(value) > datetime.now(ZoneInfo("UTC")):
+        raise ValueError(f"{value} isn't a Discord ID yet (it would be created in the future)")
+    return value
+
+
+# An ID validated on input and written to JSON as a string, like Discord's API
+# does, so JavaScript readers don't lose precision
+Snowflake = Annotated[
+    int, BeforeValidator(parse_snowflake), PlainSerializer(str, when_used="json")
+]
@@ -0,0 +1,206 @@
+"""Natural language time parsing for command arguments.
+
+Understands English and Spanish phrases such as "in 45 min", "tomorrow 7pm",
+"next friday 18:30", "mañana a las 19:00", or "2025-03-14 18:00". All results
+are resolved against `now`, which carries the invoking user's timezone.
+"""
+
+import re
+from datetime import date, datetime, time, timedelta
+
+# Time of day used when only a date is given (e.g. "next friday")
+DEFAULT_TIME = time(9, 0)
+
+_UNITS = {
+    "m": "minutes",
+    "min": "minutes",
+    "mins": "minutes",
+    "minute": "minutes",
+    "minutes": "minutes",
+    "minuto": "minutes",
+    "minutos": "minutes",
+    "h": "hours",
+    "hr": "hours",
+    "hrs": "hours",
+    "hour": "hours",
+    "hours": "hours",
+    "hora": "hours",
+    "horas": "hours",
+    "d": "days",
+    "day": "days",
+    "days": "days",
+    "dia": "days",
+    "dias": "days",
+    "día": "days",
+    "días": "days",
+    "w": "weeks",
+    "week": "weeks",
+    "weeks": "weeks",
+    "semana": "weeks",
+    "semanas": "weeks",
+}
+
+_WEEKDAYS = {
+    "monday": 0,
+    "tuesday": 1,
+    "wednesday": 2,
+    "thursday": 3,
+    "friday": 4,
+    "saturday": 5,
+    "sunday": 6,
+    "lunes": 0,
+    "martes": 1,
+    "miercoles": 2,
+    "miércoles": 2,
+    "jueves": 3,
+    "viernes": 4,
+    "sabado": 5,
+    "sábado": 5,
+    "domingo": 6,
+}
+
+_TODAY = {"today", "tonight", "hoy"}
+_TOMORROW = {"tomorrow", "mañana", "manana"}
+_NEXT = {"next", "proximo", "próximo", "proxima", "próxima"}
+
+_DURATION_PART = re.compile(r"(\d+)\s*([a-zñáéíóú]+)")
+_RELATIVE = re.compile(r"^(?:in|en|dentro de)\s+(.+)$")
+_TIME = re.compile(
+    r"(?:^|\s)(?:at\s+|a\s+las\s+|a\s+la\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm|a\.m\.|p\.m\.)?$"
+)
+
+
+def parse_duration(text: str) -> timedelta:
+    """Parse durations such as "45m", "1h30m", "2 hours and 15 minutes", or "30d".
+
+    Raises:
+        ValueError: If the text is not a duration.
+    """
+    remainder = re.sub(r"\b(and|y)\b|,", " ", text.strip().lower())
+    total = timedelta(0)
+    matched = False
+
+    position = 0
+    for match in _DURATION_PART.finditer(remainder):
+        if remainder[position : match.start()].strip():
+            raise ValueError(f"Invalid duration: {text!r}")
+        unit = _UNITS.get(match.group(2))
+        if unit is None:
+            raise ValueError(f"Unknown time unit {match.group(2)!r} in {text!r}")
+        total += timedelta(**{unit: int(match.group(1))})
+        matched = True
+        position = match.end()
+
+    if not matched or remainder[position:].strip():
+        raise ValueError(f"Invalid duration: {text!r}")
+    return total
+
+
+def parse_time(text: str, now: datetime) -> datetime:
+    """Parse a human time expression relative to `now`.
+
+    Args:
+        text: The expression to parse.
+        now: Current time in the user's timezone.
+
+    Returns:
+        An aware datetime in the same timezone as `now`.
+
+    Raises:
+        ValueError: If the expression can't be understood.
+    """
+    text = " ".join(text.strip().lower().split())
+    if not text:
+        raise ValueError("No time given")
+
+    if relative := _RELATIVE.match(text):
+        return now + parse_duration(relative.group(1))
+
+    day_text, time_of_day = _split_time(text)
+    if not day_text:
+        if time_of_day is None:
+            raise ValueError(f"Could not understand time: {text!r}")
+        result = _combine(now.date(), time_of_day, now)
+        return result if result > now else result + timedelta(days=1)
+
+    day, fixed = _parse_day(day_text, now.date())
+    result = _combine(day, time_of_day or DEFAULT_TIME, now)
+    if not fixed and result <= now:
+        result += timedelta(days=7)
+    return result
+
+
+def parse_time_prefix(text: str, now: datetime) -> tuple[datetime, str]:
+    """Parse the longest leading time expression and return the remaining text.
+
+    Used for commands like "!remindme in 45 min check the oven".
+
+    Raises:
+        ValueError: If no prefix of the text is a time expression.
+    """
+    words = text.split()
+    for length in range(len(words), 0, -1):
+        try:
+            when = parse_time(" ".join(words[:length]), now)
+        except ValueError:
+            continue
+        return when, " ".join(words[length:])
+
+    raise ValueError(f"Could not find a time in {text!r}")
+
+
+def _split_time(text: str) -> tuple[str, time | None]:
+    """Split a trailing time of day ("7pm", "at 18:30") from the day part."""
+    match = _TIME.search(text)
+    if not match:
+        return text, None
+
+    hour = int(match.group(1))
+    minute = int(match.group(2) or 0)
+    meridiem = (match.group(3) or "").replace(".", "")
+
+    # A bare number ("friday 7") is too ambiguous to be a time
+    explicit = match.group(2) or meridiem or re.search(r"\b(at|a las|a la)\s", match.group(0))
+    if not explicit:
+        return text, None
+
+    if meridiem:
+        if not 1 <= hour <= 12:
+            raise ValueError(f"Invalid hour: {hour}{meridiem}")
+        hour = hour % 12 + (12 if meridiem == "pm" else 0)
+    if hour > 23 or minute > 59:
+        raise ValueError(f"Invalid time: {hour}:{minute:02d}")
+
+    return text[: match.start()].strip(), time(hour, minute)
+
+
+def _parse_day(text: str, today: date) -> tuple[date, bool]:
+    """Parse the day part of an expression.
+
+    Returns the date and whether it is fixed. A plain weekday is not: if the
+    time has already passed today, it rolls over to the following week.
+    """
+    if text in _TODAY:
+        return today, True
+    if text in _TOMORROW:
+        return today + timedelta(days=1), True
+
+    try:
+        return date.fromisoformat(text), True
+    except ValueError:
+        pass
+
+    words = text.split()
+    is_next = len(words) == 2 and words[0] in _NEXT
+    weekday = _WEEKDAYS.get(words[-1]) if len(words) == 1 or is_next else None
+    if weekday is None:
+        raise ValueError(f"Could not understand day: {text!r}")
+
+    days_ahead = (weekday - today.weekday()) % 7
+    if is_next and days_ahead == 0:
+        days_ahead = 7
+    return today + timedelta(days=days_ahead), is_next
+
+
+def _combine(day: date, time_of_day: time, now: datetime) -> datetime:
+    return datetime.combine(day, time_of_day, tzinfo=now.tzinfo)
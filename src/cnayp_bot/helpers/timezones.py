@@ -0,0 +1,25 @@
+"""Event times in the reference timezones of a community spread over several."""
+
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+
+def city(zone: ZoneInfo) -> str:
+    """Return the city naming a timezone, e.g. "New York" for America/New_York."""
+    return zone.key.rsplit("/", 1)[-1].replace("_", " ")
+
+
+def reference_times(moment: datetime, zones: list[str], home: ZoneInfo) -> str:
+    """Return a time in each reference timezone, e.g. "Lima 18:00 · Madrid 00:00 (Tue)".
+
+    The weekday is added where the date isn't the one in `home`, so a late
+    evening session shows as the next morning elsewhere.
+    """
+    home_date = moment.astimezone(home).date()
+    times = []
+    for name in zones:
+        zone = ZoneInfo(name)
+        local = moment.astimezone(zone)
+        day = f" ({local:%a})" if local.date() != home_date else ""
+        times.append(f"{city(zone)} {local:%H:%M}{day}")
+    return " · ".join(times)
@@ -0,0 +1,35 @@
+"""Channel topics built from upcoming events, the week's theme, and the digest."""
+
+from datetime import datetime
+
+from ..services.calendar import CalendarEvent
+
+# Placeholders available in channel topic templates
+TOPIC_FIELDS = {"next_event", "next_time", "week_theme", "digest_link"}
+
+TOPIC_LIMIT = 1024
+
+
+def render_topic(
+    template: str,
+    events: list[CalendarEvent],
+    now: datetime,
+    week_theme: str = "",
+    digest_link: str = "",
+) -> str:
+    """Fill a channel topic template.
+
+    `{next_time}` becomes a Discord timestamp, so each member sees it in their
+    own timezone.
+    """
+    upcoming = sorted(
+        (event for event in events if event.start_time > now), key=lambda e: e.start_time
+    )
+    next_event = upcoming[0] if upcoming else None
+    topic = template.format(
+        next_event=next_event.name if next_event else "No upcoming events",
+        next_time=f"<t:{int(next_event.start_time.timestamp())}:F>" if next_event else "",
+        week_theme=week_theme,
+        digest_link=digest_link,
+    )
+    return topic[:TOPIC_LIMIT]
@@ -0,0 +1,71 @@
+"""Channel transcripts rendered as JSON or standalone HTML."""
+
+import html
+import json
+from dataclasses import asdict, dataclass, field
+from datetime import datetime
+
+
+@dataclass
+class TranscriptMessage:
+    """A message as it appears in a transcript."""
+
+    id: int
+    author: str
+    author_id: int
+    created_at: datetime
+    content: str
+    attachments: list[str] = field(default_factory=list)
+    reply_to: int | None = None
+
+
+def render_json(channel_name: str, since: datetime, messages: list[TranscriptMessage]) -> str:
+    """Render a transcript as JSON, oldest message first."""
+    return json.dumps(
+        {
+            "channel": channel_name,
+            "since": since.isoformat(),
+            "message_count": len(messages),
+            "messages": [
+                asdict(message) | {"created_at": message.created_at.isoformat()}
+                for message in messages
+            ],
+        },
+        indent=2,
+        ensure_ascii=False,
+    )
+
+
+def render_html(channel_name: str, since: datetime, messages: list[TranscriptMessage]) -> str:
+    """Render a transcript as a standalone HTML page, oldest message first."""
+    title = html.escape(f"#{channel_name} since {since:%Y-%m-%d}")
+    rows = []
+    for message in messages:
+        reply = ""
+        if message.reply_to:
+            reply = f'<div class="reply">↪ reply to {message.reply_to}</div>'
+        attachments = "".join(
+            f'<div><a href="{html.escape(url)}">{html.escape(url)}</a></div>'
+            for url in message.attachments
+        )
+        rows.append(
+            f'<div class="message" id="m{message.id}">{reply}'
+            f'<span class="author">{html.escape(message.author)}</span> '
+            f'<span class="time">{message.created_at:%Y-%m-%d %H:%M} UTC</span>'
+            f'<div class="content">{html.escape(message.content)}</div>{attachments}</div>'
+        )
+
+    return (
+        "<!DOCTYPE html>\n"
+        '<html><head><meta charset="utf-8">'
+        f"<title>{title}</title>"
+        "<style>"
+        "body{font-family:sans-serif;max-width:50rem;margin:auto}"
+        ".message{padding:.5rem 0;border-bottom:1px solid #ddd}"
+        ".author{font-weight:bold}.time,.reply{color:#777;font-size:.85rem}"
+        ".content{white-space:pre-wrap}"
+        "</style></head><body>"
+        f"<h1>{title}</h1><p>{len(messages)} messages</p>"
+        + "\n".join(rows)
+        + "</body></html>\n"
+    )
@@ -4,19 +4,20 @@ import signal
 
 from .bot import create_bot
 from .config import settings
+from .helpers.log_format import configure_logging
+from .services.crash_reports import handle_loop_exception, init_crash_reports
 
-logging.basicConfig(
-    level=logging.INFO,
-    format="%(asctime)s - %(name)s - %(levelname)s - %(message)s",
-)
+configure_logging(settings.log_level, settings.log_format)
 logging.getLogger("google_auth_httplib2").setLevel(logging.ERROR)
 logger = logging.getLogger(__name__)
 
 
 async def main() -> None:
+    init_crash_reports()
     bot = create_bot()
 
     loop = asyncio.get_running_loop()
+    loop.set_exception_handler(handle_loop_exception)
     stop_event = asyncio.Event()
 
     def signal_handler() -> None:
@@ -27,8 +28,10 @@ async def main() -> None:
         loop.add_signal_handler(sig, signal_handler)
 
     async def run_bot() -> None:
+        # discord.py reconnects with backoff and resumes the session on its own;
+        # start() only returns or raises once the gateway can't be rejoined
         try:
-            await bot.start(settings.discord_bot_token)
+            await bot.start(settings.discord_bot_token, reconnect=True)
         except asyncio.CancelledError:
             pass
         finally:
@@ -36,10 +39,25 @@ async def main() -> None:
                 await bot.close()
 
     bot_task = asyncio.create_task(run_bot())
+    stop_task = asyncio.create_task(stop_event.wait())
 
-    await stop_event.wait()
+    await asyncio.wait((bot_task, stop_task), return_when=asyncio.FIRST_COMPLETED)
+
+    if bot_task.done():
+        # Exit instead of idling without a gateway connection, so the
+        # container is restarted
+        stop_task.cancel()
+        error = bot_task.exception()
+        logger.critical("Bot disconnected for good, exiting: %s", error or "gateway closed")
+        raise SystemExit(1)
 
     logger.info("Shutting down bot...")
+    # Let reminders, digests, and event creation already running finish, so
+    # none is cut off between sending and being recorded as sent
+    await bot.in_flight.drain(settings.shutdown_timeout_seconds)
+    scheduler = bot.get_cog("SchedulerCog")
+    if scheduler and scheduler.retries:
+        logger.warning("Dropping %d notifications waiting for a retry", len(scheduler.retries))
     bot_task.cancel()
 
     try:
@@ -1,5 +1,26 @@
 """Pydantic models for the CNAYP bot."""
 
-from .schedule import Schedule, ScheduleConfig
+from .automod import AutomodConfig, AutomodRule
+from .schedule import (
+    PartnerCommunity,
+    Schedule,
+    ScheduleConfig,
+    ScheduleFollowup,
+    ScheduleMirror,
+    Sponsor,
+    SponsorConfig,
+)
+from .submission import EventSubmission
 
-__all__ = ["Schedule", "ScheduleConfig"]
+__all__ = [
+    "AutomodConfig",
+    "AutomodRule",
+    "EventSubmission",
+    "PartnerCommunity",
+    "Schedule",
+    "ScheduleConfig",
+    "ScheduleFollowup",
+    "ScheduleMirror",
+    "Sponsor",
+    "SponsorConfig",
+]
@@ -0,0 +1,67 @@
+"""Discord AutoMod rule models, for rules kept in version control."""
+
+from typing import Literal
+
+from pydantic import BaseModel, Field, field_validator, model_validator
+
+# Longest timeout Discord's AutoMod can give, in minutes (28 days)
+MAX_TIMEOUT_MINUTES = 28 * 24 * 60
+
+
+class AutomodRule(BaseModel):
+    """A native AutoMod rule, matched to the guild's rules by name."""
+
+    name: str = Field(min_length=1, max_length=100)
+    trigger: Literal["keyword", "mention_spam"]
+    enabled: bool = True
+    # Keyword rules: words (`*` as a wildcard, e.g. "free nitro*"), regular
+    # expressions, and words that never trigger the rule
+    keywords: list[str] = Field(default_factory=list, max_length=1000)
+    regex_patterns: list[str] = Field(default_factory=list, max_length=10)
+    allow_list: list[str] = Field(default_factory=list, max_length=100)
+    # Mention spam rules: most unique user and role mentions in one message
+    mention_limit: int = Field(default=5, ge=1, le=50)
+    mention_raid_protection: bool = False
+    # Actions: block the message, showing the author `block_message` if set,
+    # alert a channel, and time the author out
+    block: bool = True
+    block_message: str = Field(default="", max_length=150)
+    alert_channel: str = ""
+    timeout_minutes: int = Field(default=0, ge=0, le=MAX_TIMEOUT_MINUTES)
+    # Names of the roles and channels the rule doesn't apply to
+    exempt_roles: list[str] = Field(default_factory=list, max_length=20)
+    exempt_channels: list[str] = Field(default_factory=list, max_length=50)
+
+    @field_validator("keywords", "allow_list")
+    @classmethod
+    def check_keyword_length(cls, keywords: list[str]) -> list[str]:
+        """Reject keywords longer than Discord allows."""
+        for keyword in keywords:
+            if not 0 < len(keyword) <= 60:
+                raise ValueError(f"Keywords must be 1 to 60 characters: {keyword!r}")
+        return keywords
+
+    @model_validator(mode="after")
+    def check_trigger_and_actions(self) -> "AutomodRule":
+        """Require something to match on and something to do."""
+        if self.trigger == "keyword" and not (self.keywords or self.regex_patterns):
+            raise ValueError(f"Keyword rule {self.name!r} needs keywords or regex_patterns")
+        if not (self.block or self.alert_channel or self.timeout_minutes):
+            raise ValueError(f"Rule {self.name!r} needs block, alert_channel, or timeout_minutes")
+        return self
+
+
+class AutomodConfig(BaseModel):
+    """Root configuration for the AutoMod rules file."""
+
+    rules: list[AutomodRule] = Field(default_factory=list)
+
+    @field_validator("rules")
+    @classmethod
+    def check_unique_names(cls, rules: list[AutomodRule]) -> list[AutomodRule]:
+        """Reject two rules with the same name, since rules are matched by name."""
+        names = [rule.name for rule in rules]
+        for name in names:
+            if names.count(name) > 1:
+                raise ValueError(f"Duplicate AutoMod rule name: {name!r}")
+        return rules
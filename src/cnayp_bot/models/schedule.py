@@ -1,6 +1,121 @@
 """Schedule configuration models."""
 
-from pydantic import BaseModel, Field
+import datetime
+import re
+from string import Formatter
+from typing import Annotated, Literal
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+from pydantic import BaseModel, Field, field_validator, model_validator
+
+from ..helpers.cron import parse_cron
+from ..helpers.snowflake import Snowflake
+
+# Placeholders available in announcement templates
+ANNOUNCEMENT_FIELDS = {
+    "name",
+    "description",
+    "when",
+    "relative",
+    "timezone",
+    "duration",
+    "where",
+    "link",
+    "meeting",
+    "local_times",
+}
+
+# Placeholders available in the digest's event line and title
+DIGEST_FIELDS = {"name", "description", "time", "relative", "duration", "local_times"}
+DIGEST_TITLE_FIELDS = {"date"}
+
+# A shared snippet from the templates directory included in a template, e.g. {>footer}
+PARTIAL_REFERENCE = re.compile(r"\{>\s*([\w-]+)\s*\}")
+
+# A guild's value set with /config kv set included in a template, e.g. {$meeting_link}
+VALUE_REFERENCE = re.compile(r"\{\$\s*([\w-]+)\s*\}")
+
+# Longest announcement template, leaving room in the message for the fields and a sponsor
+TEMPLATE_LIMIT = 1500
+
+# A 24-hour time of day, e.g. 19:00
+TIME_OF_DAY = re.compile(r"^([01]?\d|2[0-3]):[0-5]\d$")
+
+_WORKWEEK = {"monday", "tuesday", "wednesday", "thursday", "friday"}
+_WEEK = _WORKWEEK | {"saturday", "sunday"}
+
+# Day sets Discord can repeat an event on daily; a single day repeats weekly
+NATIVE_RECURRENCE_DAYS = [
+    _WORKWEEK,
+    _WORKWEEK - {"monday"} | {"saturday"},
+    _WORKWEEK - {"friday"} | {"sunday"},
+    {"friday", "saturday"},
+    {"saturday", "sunday"},
+    {"sunday", "monday"},
+    _WEEK,
+]
+
+
+def _check_template_fields(template: str, fields: set[str] = ANNOUNCEMENT_FIELDS) -> None:
+    """Reject a template that's too long or has placeholders the message can't fill."""
+    if len(template) > TEMPLATE_LIMIT:
+        raise ValueError(f"Templates can be at most {TEMPLATE_LIMIT} characters")
+    references = VALUE_REFERENCE.sub("", PARTIAL_REFERENCE.sub("", template))
+    for _, field, _, _ in Formatter().parse(references):
+        if field is not None and field not in fields:
+            allowed = ", ".join(f"{{{name}}}" for name in sorted(fields))
+            raise ValueError(f"Unknown placeholder {{{field}}}, use one of {allowed}")
+
+
+def _check_time(value: str) -> str:
+    """Reject a time of day that isn't 24-hour HH:MM; empty means unset."""
+    if value and not TIME_OF_DAY.match(value):
+        raise ValueError(f"{value!r} isn't a 24-hour time like 19:00")
+    return value
+
+
+def _check_days(days: list[str]) -> list[str]:
+    """Reject weekday names that aren't English, in any case."""
+    for day in days:
+        if day.lower() not in _WEEK:
+            raise ValueError(f"{day!r} isn't a day of the week, e.g. monday")
+    return days
+
+
+class ScheduleMirror(BaseModel):
+    """Another guild a schedule's events are also created and announced in."""
+
+    guild_id: Snowflake
+    voice_channel: str
+    notify_channel: str
+    # Localized name, description, and announcement; empty falls back to the schedule's
+    name: str = ""
+    description: str = ""
+    announcement_template: str = ""
+
+    @field_validator("announcement_template")
+    @classmethod
+    def check_template_fields(cls, template: str) -> str:
+        """Reject a template with placeholders the announcement can't fill."""
+        _check_template_fields(template)
+        return template
+
+
+class ScheduleFollowup(BaseModel):
+    """A message posted in the notify channel a while after each occurrence ends."""
+
+    # Minutes after the end, e.g. so the recording has time to upload
+    delay_minutes: int = Field(default=15, ge=0, le=7 * 24 * 60)
+    # Takes the announcement placeholders; links to the recording, meeting notes, or a
+    # feedback form are written in the template
+    template: str = Field(min_length=1)
+
+    @field_validator("template")
+    @classmethod
+    def check_template_fields(cls, template: str) -> str:
+        """Reject a template with placeholders the follow-up can't fill."""
+        _check_template_fields(template)
+        return template
 
 
 class Schedule(BaseModel):
@@ -10,10 +125,213 @@ class Schedule(BaseModel):
     description: str
     voice_channel: str
     notify_channel: str
-    days: list[str]
-    time: str
+    # Weekdays a weekly schedule runs on at `time`, the date of a one-off event, or a
+    # five-field cron expression (in `timezone`) for anything else, e.g. "0 19 * * TUE#1"
+    days: list[str] = Field(default_factory=list)
+    date: datetime.date | None = None
+    cron: str = ""
+    time: str = ""
+    # A weekly schedule runs every `every_weeks` weeks from the week of `starting`, and
+    # never before it, e.g. 2 with a first Friday for every other Friday
+    every_weeks: int = Field(default=1, ge=1)
+    starting: datetime.date | None = None
     timezone: str
     duration_minutes: int
+    enabled: bool = True
+    # One template replaces the default announcement; two alternate as an A/B experiment
+    announcement_templates: list[str] = Field(default_factory=list, max_length=2)
+    # Discord user IDs of the organizers who host the events, and of those who stand in for them
+    owners: list[Snowflake] = Field(default_factory=list)
+    co_hosts: list[Snowflake] = Field(default_factory=list)
+    # Guild the events are created and announced in, when not the primary guild
+    guild_id: Snowflake | None = None
+    # Hybrid events get a new Zoom or Google Meet meeting for every occurrence, linked
+    # in the Discord event and the announcement
+    meeting: Literal["zoom", "meet"] | None = None
+    # Notices about the events sent as @silent messages, without push notifications
+    # (`SILENT_MESSAGES` if unset), e.g. ["announcement"] so only reminders notify
+    silent: list[Literal["announcement", "reminder", "start"]] | None = None
+    # Minutes before each occurrence reminders are sent (`REMINDER_MINUTES` if unset),
+    # who they ping: "role" (`NOTIFICATION_ROLE`), "everyone", "here", "none", or a
+    # role ID (`REMINDER_PING` if unset), and text replacing the reminder card, which
+    # takes the announcement placeholders
+    reminder_minutes: list[Annotated[int, Field(gt=0)]] | None = None
+    reminder_ping: Literal["role", "everyone", "here", "none"] | Snowflake | None = None
+    reminder_template: str = ""
+    # Private events, e.g. organizer planning meetings, are only announced to
+    # `audience_role`, in voice and notify channels (or a private thread) @everyone can't see
+    visibility: Literal["public", "private"] = "public"
+    audience_role: str = ""
+    # Other guilds that co-host the events, e.g. a Spanish and an English server
+    mirrors: list[ScheduleMirror] = Field(default_factory=list)
+    # Create one recurring Discord event instead of a new one for every occurrence
+    native_recurrence: bool = False
+    # Seats per occurrence, taken with RSVP buttons on the announcement; then a waitlist
+    capacity: int | None = Field(default=None, gt=0)
+    # Slowmode in seconds while events run, on the slowmode channel or else the notify channel
+    slowmode_seconds: int = Field(default=0, ge=0, le=21600)
+    slowmode_channel: str = ""
+    # Tasks posted to the owners `checklist_days` before each occurrence, with a button each
+    checklist: list[str] = Field(default_factory=list, max_length=25)
+    checklist_days: int = Field(default=3, gt=0)
+    # Thank-you or feedback message posted in the notify channel after each occurrence
+    followup: ScheduleFollowup | None = None
+    # Open a thread after each occurrence collecting attendees' photos and links for 48 hours
+    recap: bool = False
+    # Days (in `timezone`) the schedule doesn't run, e.g. a week off
+    exclude_dates: list[datetime.date] = Field(default_factory=list)
+    # Cover image of the Discord events: a PNG, JPEG, GIF, or WebP file (relative to the
+    # schedules file) or an http(s) URL, e.g. the community's artwork for the series
+    image: str = ""
+
+    @field_validator("days")
+    @classmethod
+    def check_days(cls, days: list[str]) -> list[str]:
+        """Reject unknown weekdays."""
+        return _check_days(days)
+
+    @field_validator("time")
+    @classmethod
+    def check_time(cls, time: str) -> str:
+        """Reject malformed times."""
+        return _check_time(time)
+
+    @field_validator("timezone")
+    @classmethod
+    def check_timezone(cls, timezone: str) -> str:
+        """Reject timezones that aren't in the IANA database."""
+        try:
+            ZoneInfo(timezone)
+        except (ZoneInfoNotFoundError, ValueError):
+            raise ValueError(
+                f"Unknown timezone {timezone!r}, use an IANA name like America/Lima"
+            ) from None
+        return timezone
+
+    @field_validator("announcement_templates")
+    @classmethod
+    def check_template_fields(cls, templates: list[str]) -> list[str]:
+        """Reject templates with placeholders the announcement can't fill."""
+        for template in templates:
+            _check_template_fields(template)
+        return templates
+
+    @field_validator("reminder_template")
+    @classmethod
+    def check_reminder_template_fields(cls, template: str) -> str:
+        """Reject a reminder template with placeholders the reminder can't fill."""
+        _check_template_fields(template)
+        return template
+
+    @field_validator("image")
+    @classmethod
+    def check_image(cls, image: str) -> str:
+        """Reject cover images that are neither a file path nor an http(s) URL."""
+        if "://" in image and not image.startswith(("http://", "https://")):
+            raise ValueError(f"Cover image {image!r} must be a file path or an http(s) URL")
+        return image
+
+    @field_validator("cron")
+    @classmethod
+    def check_cron(cls, cron: str) -> str:
+        """Reject cron expressions that can't be parsed."""
+        if cron:
+            parse_cron(cron)
+        return cron
+
+    @model_validator(mode="after")
+    def check_when(self) -> "Schedule":
+        """Require exactly one of weekdays, a date, or a cron expression, and a time."""
+        if [bool(self.days), self.date is not None, bool(self.cron)].count(True) != 1:
+            raise ValueError(
+                "Set one of days, for a weekly schedule, date, for a one-off event, or cron"
+            )
+        if not self.cron and not self.time:
+            raise ValueError("Weekly schedules and one-off events need a time")
+        if self.cron and self.time:
+            raise ValueError(
+                "Cron schedules take their times from the expression, leave time empty"
+            )
+        if not self.days and (self.every_weeks > 1 or self.starting):
+            raise ValueError("Only weekly schedules can skip weeks with every_weeks and starting")
+        if self.every_weeks > 1 and self.starting is None:
+            raise ValueError("Schedules repeating every few weeks need a starting date")
+        if not self.days and self.native_recurrence:
+            raise ValueError("Only weekly schedules can be recurring Discord events")
+        return self
+
+    @model_validator(mode="after")
+    def check_visibility(self) -> "Schedule":
+        """Require private schedules to name their audience, and keep them in one guild."""
+        if self.visibility == "private" and not self.audience_role:
+            raise ValueError("Private schedules need an audience_role to announce them to")
+        if self.visibility == "private" and self.mirrors:
+            raise ValueError("Private schedules can't be mirrored to other guilds")
+        return self
+
+    @model_validator(mode="after")
+    def check_native_recurrence(self) -> "Schedule":
+        """Reject recurring Discord events on days Discord can't repeat them on."""
+        days = {day.lower() for day in self.days}
+        if self.native_recurrence and len(days) > 1 and days not in NATIVE_RECURRENCE_DAYS:
+            raise ValueError(
+                "Discord can only repeat an event on one day a week, every day, Monday to "
+                "Friday, Tuesday to Saturday, Sunday to Thursday, or on Friday and Saturday, "
+                "Saturday and Sunday, or Sunday and Monday"
+            )
+        if self.native_recurrence and self.every_weeks > 1 and len(days) > 1:
+            raise ValueError("Discord can only repeat an event every few weeks on one day")
+        return self
+
+
+class Sponsor(BaseModel):
+    """A community sponsor and the blurb shown for them."""
+
+    name: str
+    blurb: str
+
+
+class SponsorConfig(BaseModel):
+    """Sponsor blurbs shown in turn in announcements and scheduled posts."""
+
+    sponsors: list[Sponsor] = Field(default_factory=list)
+    # Append the next blurb to every event announcement
+    announcements: bool = True
+    # Also post the next blurb in `channel` on these days at `time` (24-hour HH:MM)
+    channel: str = ""
+    days: list[str] = Field(default_factory=list)
+    time: str = ""
+
+    @field_validator("days")
+    @classmethod
+    def check_days(cls, days: list[str]) -> list[str]:
+        """Reject unknown weekdays."""
+        return _check_days(days)
+
+    @field_validator("time")
+    @classmethod
+    def check_time(cls, time: str) -> str:
+        """Reject malformed times."""
+        return _check_time(time)
+
+
+class PartnerCommunity(BaseModel):
+    """An allied guild some public schedules are announced in, without creating events."""
+
+    name: str
+    guild_id: Snowflake
+    channel: str
+    # Names of the schedules announced there
+    schedules: list[str] = Field(min_length=1)
+    # Announcement replacing the default, with the announcement placeholders
+    announcement_template: str = ""
+
+    @field_validator("announcement_template")
+    @classmethod
+    def check_template_fields(cls, template: str) -> str:
+        """Reject a template with placeholders the announcement can't fill."""
+        _check_template_fields(template)
+        return template
 
 
 class ScheduleConfig(BaseModel):
@@ -23,3 +341,67 @@ class ScheduleConfig(BaseModel):
     digest_time: str = ""
     digest_channel: str = ""
     reminder_minutes: list[int] = Field(default_factory=lambda: [45, 10])
+    # Digest text replacing the English defaults, e.g. to translate it: each event's
+    # line, the title, and what's shown on a day without events
+    digest_line_template: str = ""
+    digest_title_template: str = Field(default="", max_length=200)
+    digest_empty_text: str = Field(default="", max_length=200)
+    # Overview of the coming 7 days, posted on `weekly_digest_day` at `weekly_digest_time`
+    # in `weekly_digest_channel`, or the daily digest's channel if unset
+    weekly_digest_day: (
+        Literal["monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"]
+        | None
+    ) = None
+    weekly_digest_time: str = ""
+    weekly_digest_channel: str = ""
+    sponsorship: SponsorConfig = Field(default_factory=SponsorConfig)
+    # Days no schedule runs, e.g. national holidays, besides those of the iCal calendar
+    # at `holiday_calendar_url`
+    holidays: list[datetime.date] = Field(default_factory=list)
+    holiday_calendar_url: str = ""
+    # Monday of a week -> that week's theme, shown in channel topics
+    week_themes: dict[datetime.date, str] = Field(default_factory=dict)
+    # Partner communities that opted in to seeing our public sessions announced
+    partners: list[PartnerCommunity] = Field(default_factory=list)
+
+    @field_validator("digest_time", "weekly_digest_time")
+    @classmethod
+    def check_time(cls, time: str) -> str:
+        """Reject malformed digest times."""
+        return _check_time(time)
+
+    @field_validator("digest_line_template")
+    @classmethod
+    def check_digest_line_fields(cls, template: str) -> str:
+        """Reject a digest line with placeholders the digest can't fill."""
+        _check_template_fields(template, DIGEST_FIELDS)
+        return template
+
+    @field_validator("digest_title_template")
+    @classmethod
+    def check_digest_title_fields(cls, template: str) -> str:
+        """Reject a digest title with placeholders the digest can't fill."""
+        _check_template_fields(template, DIGEST_TITLE_FIELDS)
+        return template
+
+    @field_validator("week_themes")
+    @classmethod
+    def check_week_starts(cls, themes: dict[datetime.date, str]) -> dict[datetime.date, str]:
+        """Reject themes keyed by a day other than a Monday."""
+        for day in themes:
+            if day.weekday() != 0:
+                raise ValueError(f"Week themes must start on a Monday, {day} is a {day:%A}")
+        return themes
+
+    @model_validator(mode="after")
+    def check_partner_schedules(self) -> "ScheduleConfig":
+        """Reject partners announcing schedules that don't exist or are private."""
+        schedules = {schedule.name.lower(): schedule for schedule in self.schedules}
+        for partner in self.partners:
+            for name in partner.schedules:
+                schedule = schedules.get(name.lower())
+                if schedule is None:
+                    raise ValueError(f"Partner {partner.name} announces unknown schedule {name}")
+                if schedule.visibility == "private":
+                    raise ValueError(f"Private schedule {name} can't be announced to partners")
+        return self
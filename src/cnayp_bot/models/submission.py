@@ -0,0 +1,14 @@
+"""Event submission models."""
+
+from pydantic import AwareDatetime, BaseModel, Field
+
+
+class EventSubmission(BaseModel):
+    """An event proposed by an external system, such as the website's talk form."""
+
+    name: str = Field(min_length=1, max_length=100)
+    time: AwareDatetime
+    duration: int = Field(gt=0, le=24 * 60)  # Minutes
+    channel: str = Field(min_length=1)  # Voice channel name
+    description: str = Field(default="", max_length=1000)
+    submitted_by: str = Field(default="", max_length=100)
@@ -0,0 +1,241 @@
+"""Timing rules shared by the scheduler cog and the simulator."""
+
+from collections.abc import Callable, Hashable, Mapping
+from datetime import date, datetime, time, timedelta
+from zoneinfo import ZoneInfo
+
+from .helpers.discord_enums import EventStatus
+from .services.calendar import CalendarEvent
+from .services.schedules import WEEKDAYS
+
+# How far ahead the scheduler looks for events in the calendar
+LOOKAHEAD_HOURS = 48
+
+# Discord scheduled events are created this long before the event starts
+CREATE_AHEAD = timedelta(hours=24)
+
+# Backoff between attempts to create a Discord event after a failure
+RETRY_BASE_DELAY = timedelta(minutes=1)
+RETRY_MAX_DELAY = timedelta(hours=1)
+
+# How late an expected action may be before the watchdog alerts
+WATCHDOG_GRACE = timedelta(minutes=5)
+
+# How far ahead overlapping events are looked for when the schedules change
+CONFLICT_LOOKAHEAD = timedelta(days=14)
+
+# Follow-ups due longer ago than this, e.g. while the bot was down, are dropped as stale
+FOLLOWUP_GRACE = timedelta(hours=12)
+
+
+def minutes_until(event: CalendarEvent, now: datetime) -> int:
+    """Minutes until the event starts, rounded to the nearest minute."""
+    return round((event.start_time - now).total_seconds() / 60)
+
+
+def should_create_discord_event(event: CalendarEvent, now: datetime) -> bool:
+    """Check whether the Discord scheduled event should exist by now."""
+    time_until_event = event.start_time - now
+    return timedelta(0) <= time_until_event <= CREATE_AHEAD
+
+
+def reminder_offsets(event: CalendarEvent, reminder_minutes: list[int]) -> list[int]:
+    """Return the minutes before an event its reminders are sent, its schedule's if it sets them."""
+    if event.schedule and event.schedule.reminder_minutes is not None:
+        return event.schedule.reminder_minutes
+    return reminder_minutes
+
+
+def due_reminders(event: CalendarEvent, now: datetime, reminder_minutes: list[int]) -> list[int]:
+    """Return the reminder offsets that are due at `now`.
+
+    A reminder triggers when at or just past its threshold (within a 1 minute
+    window), so callers must remember which reminders they already sent.
+    """
+    until = minutes_until(event, now)
+    return [minutes for minutes in reminder_minutes if 0 <= minutes - until <= 1]
+
+
+def reminder_batches(
+    events: list[CalendarEvent],
+    now: datetime,
+    reminder_minutes: list[int],
+    sent: set[str],
+    channel_of: Callable[[CalendarEvent], Hashable],
+    timezone: ZoneInfo,
+) -> dict[tuple[Hashable, int], list[CalendarEvent]]:
+    """Group due reminders into one batch per notify channel and offset.
+
+    When a reminder is due, later events on the same day in the same channel
+    whose reminder at that offset is still ahead join the batch, so a channel
+    gets a single ping per offset per day. `sent` holds "event_id:minutes"
+    keys of reminders already sent. `channel_of` can return any key naming
+    the channel, e.g. its guild and name. Events whose schedule sets its own
+    offsets use them instead of `reminder_minutes`.
+    """
+    batches: dict[tuple[Hashable, int], list[CalendarEvent]] = {}
+    for event in events:
+        for minutes in due_reminders(event, now, reminder_offsets(event, reminder_minutes)):
+            if f"{event.id}:{minutes}" not in sent:
+                batches.setdefault((channel_of(event), minutes), []).append(event)
+
+    for (channel, minutes), batch in batches.items():
+        days = {event.start_time.astimezone(timezone).date() for event in batch}
+        for event in events:
+            if (
+                event not in batch
+                and f"{event.id}:{minutes}" not in sent
+                and minutes in reminder_offsets(event, reminder_minutes)
+                and channel_of(event) == channel
+                and minutes_until(event, now) > minutes
+                and event.start_time.astimezone(timezone).date() in days
+            ):
+                batch.append(event)
+        batch.sort(key=lambda event: event.start_time)
+
+    return batches
+
+
+def held_reminder_batches(
+    held: Mapping[str, datetime],
+    events: Mapping[str, CalendarEvent],
+    now: datetime,
+    channel_of: Callable[[CalendarEvent], Hashable],
+    quiet: Callable[[CalendarEvent, datetime], bool],
+) -> tuple[dict[Hashable, list[CalendarEvent]], list[str]]:
+    """Group the reminders held back by quiet hours that can go out now, per channel.
+
+    `held` maps the held events' IDs to their start times, and `quiet` checks
+    whether it's still quiet hours for an event. Events that started while
+    held are returned as dropped instead. Held events missing from `events`,
+    e.g. right after a restart, wait until their start passes.
+    """
+    batches: dict[Hashable, list[CalendarEvent]] = {}
+    dropped = []
+    for event_id, start in held.items():
+        event = events.get(event_id)
+        if (event.start_time if event else start) <= now:
+            dropped.append(event_id)
+        elif event and not quiet(event, now):
+            batches.setdefault(channel_of(event), []).append(event)
+
+    for batch in batches.values():
+        batch.sort(key=lambda event: event.start_time)
+    return batches, dropped
+
+
+def in_quiet_hours(now: datetime, start: time | None, end: time | None) -> bool:
+    """Check whether it's within the quiet hours from `start` to `end`.
+
+    `now` must be in the quiet hours' timezone. They may span midnight, e.g.
+    23:00 to 08:00, and there are none unless both are set.
+    """
+    if start is None or end is None or start == end:
+        return False
+    if start < end:
+        return start <= now.time() < end
+    return now.time() >= start or now.time() < end
+
+
+def overlaps(event: CalendarEvent, other: CalendarEvent) -> bool:
+    """Check whether two events are on at the same time; back-to-back events aren't."""
+    return event.start_time < other.end_time and other.start_time < event.end_time
+
+
+def find_conflicts(
+    events: list[CalendarEvent], room_of: Callable[[CalendarEvent], Hashable]
+) -> list[tuple[CalendarEvent, CalendarEvent]]:
+    """Return the pairs of events overlapping in the same voice channel, by start time.
+
+    `room_of` can return any key naming an event's voice channel, e.g. its
+    guild and name.
+    """
+    events = sorted(events, key=lambda event: event.start_time)
+    conflicts = []
+    for index, event in enumerate(events):
+        for other in events[index + 1 :]:
+            if other.start_time >= event.end_time:
+                break
+            if room_of(other) == room_of(event):
+                conflicts.append((event, other))
+    return conflicts
+
+
+def retry_delay(attempts: int) -> timedelta:
+    """Return how long to wait before retrying after `attempts` failed attempts."""
+    return min(RETRY_BASE_DELAY * 2 ** (attempts - 1), RETRY_MAX_DELAY)
+
+
+def dm_reminder_due(event: CalendarEvent, now: datetime, minutes: int) -> bool:
+    """Check whether members should be DMed about an event: within `minutes` of its start.
+
+    Reminders missed while the bot was down are still sent until the event
+    starts, so callers must remember whom they already reminded.
+    """
+    return 0 < minutes_until(event, now) <= minutes
+
+
+def has_started(event: CalendarEvent, now: datetime) -> bool:
+    """Check whether the event has started."""
+    return minutes_until(event, now) <= 0
+
+
+def host_check_due(event: CalendarEvent, now: datetime, minutes: int) -> bool:
+    """Check whether a host should have joined the event by now, while it's still on."""
+    return event.start_time + timedelta(minutes=minutes) <= now < event.end_time
+
+
+def followup_time(event: CalendarEvent, delay_minutes: int) -> datetime:
+    """Return when an event's follow-up is posted, `delay_minutes` after it ends."""
+    return event.end_time + timedelta(minutes=delay_minutes)
+
+
+def followup_stale(due: datetime, now: datetime) -> bool:
+    """Check whether a follow-up was due so long ago, e.g. while the bot was down, it's dropped."""
+    return now - due > FOLLOWUP_GRACE
+
+
+def next_status(event: CalendarEvent, now: datetime, status: str) -> EventStatus | None:
+    """Return the status a Discord scheduled event should move to, if any.
+
+    Discord only allows scheduled -> active -> completed, so an event the bot
+    missed entirely is started first and completed on the next check.
+    """
+    if status == EventStatus.SCHEDULED and now >= event.start_time:
+        return EventStatus.ACTIVE
+    if status == EventStatus.ACTIVE and now >= event.end_time:
+        return EventStatus.COMPLETED
+    return None
+
+
+def digest_due(now: datetime, digest_time: str, last_posted: date | None) -> bool:
+    """Check whether today's schedule digest should be posted.
+
+    `now` must be in the digest's timezone. A digest missed while the bot was
+    down is posted late rather than skipped.
+    """
+    hour, minute = (int(part) for part in digest_time.split(":"))
+    return last_posted != now.date() and now.time() >= time(hour, minute)
+
+
+def weekly_digest_due(now: datetime, day: str, digest_time: str, last_posted: date | None) -> bool:
+    """Check whether this week's overview should be posted, on its day from its time on.
+
+    `now` must be in the digest's timezone.
+    """
+    return WEEKDAYS[now.weekday()] == day and digest_due(now, digest_time, last_posted)
+
+
+def digest_overdue(now: datetime, digest_time: str, last_posted: date | None) -> bool:
+    """Check whether today's digest should have been posted by now but wasn't.
+
+    `now` must be in the digest's timezone.
+    """
+    hour, minute = (int(part) for part in digest_time.split(":"))
+    deadline = datetime.combine(now.date(), time(hour, minute), tzinfo=now.tzinfo)
+    return last_posted != now.date() and now >= deadline + WATCHDOG_GRACE
+
+
+def discord_event_overdue(event: CalendarEvent, now: datetime) -> bool:
+    """Check whether the event's Discord scheduled event should have been created by now."""
+    return now < event.start_time <= now + CREATE_AHEAD - WATCHDOG_GRACE
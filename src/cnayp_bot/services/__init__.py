@@ -1,6 +1,17 @@
 """Services for the CNAYP bot."""
 
 from .calendar import CalendarEvent, CalendarService, WatchChannel
+from .components import ComponentRouter
+from .errors import report_error
+from .store import Store
 from .webhook import WebhookServer
 
-__all__ = ["CalendarEvent", "CalendarService", "WatchChannel", "WebhookServer"]
+__all__ = [
+    "CalendarEvent",
+    "CalendarService",
+    "ComponentRouter",
+    "Store",
+    "WatchChannel",
+    "WebhookServer",
+    "report_error",
+]
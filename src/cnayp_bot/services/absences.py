@@ -0,0 +1,59 @@
+"""Away notices from schedule owners."""
+
+from datetime import datetime
+
+from ..models import Schedule
+from .store import Store
+
+# User ID -> {"start": ..., "end": ..., "reason": ...}
+ABSENCES = "absences"
+
+
+class Absences:
+    """Keeps when schedule owners are away, one notice per owner.
+
+    A new notice replaces the owner's previous one, and notices are dropped
+    once they've ended.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def set(self, user_id: int, start: datetime, end: datetime, reason: str) -> None:
+        """Record that a member is away between `start` and `end`."""
+        self.forget_past(start)
+        self._store.set(
+            ABSENCES,
+            str(user_id),
+            {"start": start.isoformat(), "end": end.isoformat(), "reason": reason},
+        )
+
+    def clear(self, user_id: int) -> bool:
+        """Drop a member's notice.
+
+        Returns:
+            True if the member had one.
+        """
+        if self._store.get(ABSENCES, str(user_id)) is None:
+            return False
+        self._store.delete(ABSENCES, str(user_id))
+        return True
+
+    def get(self, user_id: int, at: datetime) -> dict | None:
+        """Return the member's notice if they're away at `at`."""
+        absence = self._store.get(ABSENCES, str(user_id))
+        if absence is None:
+            return None
+        start = datetime.fromisoformat(absence["start"])
+        end = datetime.fromisoformat(absence["end"])
+        return absence if start <= at < end else None
+
+    def away_owners(self, schedule: Schedule, at: datetime) -> list[int]:
+        """Return the schedule's owners who are away at `at`."""
+        return [owner for owner in schedule.owners if self.get(owner, at)]
+
+    def forget_past(self, now: datetime) -> None:
+        """Drop notices that have ended."""
+        for user_id, absence in self._store.items(ABSENCES).items():
+            if datetime.fromisoformat(absence["end"]) <= now:
+                self._store.delete(ABSENCES, user_id)
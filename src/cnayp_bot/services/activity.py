@@ -0,0 +1,142 @@
+"""Per-channel activity aggregated by day, and the day each member was last active."""
+
+import re
+from collections import Counter
+from collections.abc import Iterable
+from dataclasses import dataclass, field
+from datetime import date, timedelta
+from typing import Any
+
+from .store import Store
+
+ACTIVITY = "activity"
+# Day each member last posted, reacted, or joined a voice channel ("members"),
+# and the day tracking it started ("since"); members are one entry, so a flush
+# writes the store once
+LAST_ACTIVE = "last_active"
+
+# Days of activity kept in the store
+RETENTION_DAYS = 90
+
+_CUSTOM_EMOJI = re.compile(r"<a?:\w+:\d+>")
+_UNICODE_EMOJI = re.compile("[\U0001F300-\U0001FAFF\u2600-\u27BF]")
+
+
+def extract_emoji(content: str) -> list[str]:
+    """Return the custom and unicode emoji used in a message, in order of appearance."""
+    found = [(match.start(), match.group()) for match in _CUSTOM_EMOJI.finditer(content)]
+    plain = _CUSTOM_EMOJI.sub(lambda match: " " * len(match.group()), content)
+    found += [(match.start(), match.group()) for match in _UNICODE_EMOJI.finditer(plain)]
+    return [emoji for _, emoji in sorted(found)]
+
+
+def _empty_day() -> dict[str, Any]:
+    return {"channels": {}, "emoji": {}, "reactions": {}}
+
+
+@dataclass
+class ActivityReport:
+    """Activity totals over a range of days."""
+
+    messages: Counter[int] = field(default_factory=Counter)  # channel ID -> messages
+    channel_users: dict[int, set[int]] = field(default_factory=dict)  # channel ID -> users
+    emoji: Counter[str] = field(default_factory=Counter)
+    reactions: Counter[str] = field(default_factory=Counter)
+
+    @property
+    def active_users(self) -> int:
+        """Members who posted in any channel."""
+        return len(set().union(*self.channel_users.values()))
+
+
+class ActivityTracker:
+    """Counts messages, posters, emoji, and reactions per day.
+
+    Counts are buffered in memory and written to the store by `flush()`, so a
+    busy channel doesn't rewrite the store file on every message.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+        self._pending: dict[str, dict[str, Any]] = {}
+        self._last_active: dict[int, str] = {}
+
+    def record_message(self, day: date, channel_id: int, user_id: int, content: str) -> None:
+        """Count a message, its author, and the emoji in it."""
+        self._last_active[user_id] = day.isoformat()
+        entry = self._pending.setdefault(day.isoformat(), _empty_day())
+        channel = entry["channels"].setdefault(str(channel_id), {"messages": 0, "users": []})
+        channel["messages"] += 1
+        if user_id not in channel["users"]:
+            channel["users"].append(user_id)
+        for emoji in extract_emoji(content):
+            entry["emoji"][emoji] = entry["emoji"].get(emoji, 0) + 1
+
+    def record_reaction(self, day: date, emoji: str, user_id: int | None = None) -> None:
+        """Count a reaction added to any message, by a member if known."""
+        if user_id is not None:
+            self._last_active[user_id] = day.isoformat()
+        entry = self._pending.setdefault(day.isoformat(), _empty_day())
+        entry["reactions"][emoji] = entry["reactions"].get(emoji, 0) + 1
+
+    def record_voice(self, day: date, user_id: int) -> None:
+        """Remember that a member joined a voice channel."""
+        self._last_active[user_id] = day.isoformat()
+
+    def flush(self, today: date) -> None:
+        """Merge buffered counts into the store and drop days past retention."""
+        if self._store.get(LAST_ACTIVE, "since") is None:
+            self._store.set(LAST_ACTIVE, "since", today.isoformat())
+        if self._last_active:
+            members = self._store.get(LAST_ACTIVE, "members", {})
+            for user_id, day in self._last_active.items():
+                members[str(user_id)] = max(day, members.get(str(user_id), day))
+            self._store.set(LAST_ACTIVE, "members", members)
+            self._last_active.clear()
+
+        for day, pending in self._pending.items():
+            stored = self._store.get(ACTIVITY, day) or _empty_day()
+            for channel_id, counts in pending["channels"].items():
+                channel = stored["channels"].setdefault(channel_id, {"messages": 0, "users": []})
+                channel["messages"] += counts["messages"]
+                channel["users"] = sorted(set(channel["users"]) | set(counts["users"]))
+            for kind in ("emoji", "reactions"):
+                stored[kind] = dict(Counter(stored[kind]) + Counter(pending[kind]))
+            self._store.set(ACTIVITY, day, stored)
+        self._pending.clear()
+
+        cutoff = (today - timedelta(days=RETENTION_DAYS)).isoformat()
+        for day in self._store.items(ACTIVITY):
+            if day < cutoff:
+                self._store.delete(ACTIVITY, day)
+
+    def last_active_since(self) -> date | None:
+        """Return the day tracking when members were last active started."""
+        day = self._store.get(LAST_ACTIVE, "since")
+        return date.fromisoformat(day) if day else None
+
+    def inactive(self, member_ids: Iterable[int], cutoff: date) -> dict[int, date | None]:
+        """Return the members not active since `cutoff`, with the day they last were.
+
+        Members never seen active map to None.
+        """
+        members = self._store.get(LAST_ACTIVE, "members", {})
+        inactive = {}
+        for member_id in member_ids:
+            day = members.get(str(member_id))
+            if day is None or day < cutoff.isoformat():
+                inactive[member_id] = date.fromisoformat(day) if day else None
+        return inactive
+
+    def report(self, start: date, end: date) -> ActivityReport:
+        """Total the stored activity for days in [start, end]."""
+        report = ActivityReport()
+        for day, entry in self._store.items(ACTIVITY).items():
+            if not start.isoformat() <= day <= end.isoformat():
+                continue
+            for channel_id, counts in entry["channels"].items():
+                report.messages[int(channel_id)] += counts["messages"]
+                report.channel_users.setdefault(int(channel_id), set()).update(counts["users"])
+            report.emoji.update(entry["emoji"])
+            report.reactions.update(entry["reactions"])
+        return report
@@ -0,0 +1,83 @@
+"""Alertmanager notifications posted in Discord, and silences created from them."""
+
+import hashlib
+import logging
+from datetime import datetime
+
+import aiohttp
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# Group ID -> {"channel_id", "message_id", "matchers"}
+ALERT_GROUPS = "alert_groups"
+
+
+def group_id(group_key: str) -> str:
+    """Shorten Alertmanager's group key to fit in a button's custom ID."""
+    return hashlib.sha256(group_key.encode()).hexdigest()[:16]
+
+
+class AlertGroups:
+    """Remembers the message each alert group is shown in.
+
+    Alertmanager notifies again when alerts join, leave, or resolve in a
+    group; each notification edits the group's message instead of posting
+    another. The group's labels are kept for the silence buttons.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def get(self, group: str) -> dict | None:
+        """Return a group's message and matchers."""
+        return self._store.get(ALERT_GROUPS, group)
+
+    def set(self, group: str, channel_id: int, message_id: int, matchers: dict[str, str]) -> None:
+        """Record the message a group is shown in."""
+        self._store.set(
+            ALERT_GROUPS,
+            group,
+            {"channel_id": channel_id, "message_id": message_id, "matchers": matchers},
+        )
+
+    def remove(self, group: str) -> None:
+        """Forget a resolved group; it gets a new message if it fires again."""
+        self._store.delete(ALERT_GROUPS, group)
+
+
+class AlertmanagerClient:
+    """Creates silences through the Alertmanager v2 API."""
+
+    def __init__(self, url: str) -> None:
+        self._url = url.rstrip("/")
+
+    async def silence(
+        self, matchers: dict[str, str], start: datetime, end: datetime, author: str, comment: str
+    ) -> str:
+        """Silence alerts with all of the given labels until `end`.
+
+        Returns:
+            The silence's ID.
+
+        Raises:
+            aiohttp.ClientError: If Alertmanager can't be reached or refuses the silence.
+        """
+        body = {
+            "matchers": [
+                {"name": name, "value": value, "isRegex": False, "isEqual": True}
+                for name, value in matchers.items()
+            ],
+            "startsAt": start.isoformat(),
+            "endsAt": end.isoformat(),
+            "createdBy": author,
+            "comment": comment,
+        }
+        timeout = aiohttp.ClientTimeout(total=10)
+        async with aiohttp.ClientSession(timeout=timeout) as session:
+            async with session.post(f"{self._url}/api/v2/silences", json=body) as response:
+                response.raise_for_status()
+                silence_id = (await response.json())["silenceID"]
+        logger.info("Created silence %s for %s until %s", silence_id, matchers, end)
+        return silence_id
@@ -0,0 +1,281 @@
+"""HTTP API for event submissions, GitHub and Alertmanager webhooks, peer bots, and iCal feeds."""
+
+import asyncio
+import hashlib
+import hmac
+import json
+import logging
+from collections.abc import Callable, Coroutine, Mapping
+from datetime import datetime
+from typing import Any
+from zoneinfo import ZoneInfo
+
+from aiohttp import web
+from pydantic import ValidationError
+
+from ..config import settings
+from ..models import EventSubmission
+from .crash_reports import watch_task
+from .peers import TASKS_PATH, PeerAuthError, PeerError
+
+logger = logging.getLogger(__name__)
+
+SubmissionHandler = Callable[[EventSubmission], Coroutine[Any, Any, str]]
+ReleaseHandler = Callable[[str, dict[str, Any]], Coroutine[Any, Any, None]]
+AlertHandler = Callable[[dict[str, Any]], Coroutine[Any, Any, None]]
+PeerTaskHandler = Callable[[Mapping[str, str], bytes, datetime], Coroutine[Any, Any, dict]]
+CalendarFeed = Callable[[int], str | None]
+
+
+@web.middleware
+async def _log_requests(request: web.Request, handler: Callable) -> web.StreamResponse:
+    """Log each request at debug level with its endpoint and status, e.g. metrics scrapes."""
+    resource = request.match_info.route.resource
+    endpoint = f"{request.method} {resource.canonical if resource else request.path}"
+    # Unexpected errors are answered with a 500 by aiohttp
+    status = 500
+    try:
+        response = await handler(request)
+        status = response.status
+        return response
+    except web.HTTPException as e:
+        status = e.status
+        raise
+    finally:
+        logger.debug("%s: %d", endpoint, status, extra={"endpoint": endpoint, "status": status})
+
+
+class ApiServer:
+    """HTTP server for event submissions, webhooks, peer tasks, metrics, and iCal feeds."""
+
+    def __init__(
+        self,
+        on_event_submission: SubmissionHandler,
+        on_github_release: ReleaseHandler,
+        on_alerts: AlertHandler,
+        on_peer_task: PeerTaskHandler,
+        metrics: Callable[[], str],
+        calendar_feed: CalendarFeed | None = None,
+        is_ready: Callable[[], bool] = lambda: True,
+    ) -> None:
+        """Initialize the API server.
+
+        Args:
+            on_event_submission: Async callback that queues a submission and
+                returns its ID. It raises ValueError to reject the submission.
+            on_github_release: Async callback taking a repository's full name
+                and a release published there.
+            on_alerts: Async callback taking an Alertmanager webhook notification.
+            on_peer_task: Async callback taking a peer bot's request headers,
+                body, and the current time, and returning the task's result.
+            metrics: Callback rendering metrics in the Prometheus text format.
+            calendar_feed: Callback rendering a guild's schedules as an iCal
+                calendar, or None for guilds the bot isn't in. Without it,
+                there's no feed.
+            is_ready: Callback telling whether this replica handles requests.
+                Standby replicas answer submissions, webhooks, peer tasks, and
+                `/ready` with a 503, so they're sent to the leader.
+        """
+        self._on_event_submission = on_event_submission
+        self._on_github_release = on_github_release
+        self._on_alerts = on_alerts
+        self._on_peer_task = on_peer_task
+        self._metrics = metrics
+        self._calendar_feed = calendar_feed
+        self._is_ready = is_ready
+        self._app = web.Application(middlewares=[_log_requests, self._refuse_on_standby])
+        self._runner: web.AppRunner | None = None
+        self._setup_routes()
+
+    def _setup_routes(self) -> None:
+        """Set up HTTP routes."""
+        self._app.router.add_post("/api/events", self._handle_submission)
+        self._app.router.add_post("/api/github", self._handle_github)
+        self._app.router.add_post("/api/alertmanager", self._handle_alertmanager)
+        self._app.router.add_post(TASKS_PATH, self._handle_peer_task)
+        self._app.router.add_get("/health", self._handle_health)
+        self._app.router.add_get("/ready", self._handle_ready)
+        self._app.router.add_get("/metrics", self._handle_metrics)
+        if self._calendar_feed:
+            self._app.router.add_get(r"/calendar/{guild_id:\d+}.ics", self._handle_calendar)
+
+    @web.middleware
+    async def _refuse_on_standby(
+        self, request: web.Request, handler: Callable
+    ) -> web.StreamResponse:
+        """Answer POSTs with a 503 on standby replicas, which leave them to the leader."""
+        if request.method == "POST" and not self._is_ready():
+            return web.json_response({"error": "Standby replica, not the leader"}, status=503)
+        return await handler(request)
+
+    def _is_authorized(self, request: web.Request) -> bool:
+        """Check the request's bearer token against API_TOKEN."""
+        if not settings.api_token:
+            return False
+        expected = f"Bearer {settings.api_token}"
+        return hmac.compare_digest(request.headers.get("Authorization", ""), expected)
+
+    async def _handle_submission(self, request: web.Request) -> web.Response:
+        """Handle an event submission.
+
+        Expects a JSON body like:
+        {"name": "...", "time": "2025-03-10T19:00:00-05:00", "duration": 60, "channel": "..."}
+        """
+        if not self._is_authorized(request):
+            logger.warning("Rejected event submission with a bad token from %s", request.remote)
+            return web.json_response({"error": "Unauthorized"}, status=401)
+
+        try:
+            submission = EventSubmission.model_validate_json(await request.read())
+        except ValidationError as e:
+            errors = [
+                f"{'.'.join(str(part) for part in error['loc'])}: {error['msg']}"
+                for error in e.errors()
+            ]
+            return web.json_response({"error": "Invalid event", "details": errors}, status=400)
+
+        if submission.time <= datetime.now(ZoneInfo("UTC")):
+            return web.json_response({"error": "Event time is in the past"}, status=400)
+
+        try:
+            submission_id = await self._on_event_submission(submission)
+        except ValueError as e:
+            return web.json_response({"error": str(e)}, status=400)
+
+        logger.info("Queued event submission %s: %s", submission_id, submission.name)
+        return web.json_response({"id": submission_id, "status": "pending"}, status=202)
+
+    def _has_github_signature(self, request: web.Request, body: bytes) -> bool:
+        """Check the delivery's signature against GITHUB_WEBHOOK_SECRET."""
+        if not settings.github_webhook_secret:
+            return False
+        digest = hmac.new(settings.github_webhook_secret.encode(), body, hashlib.sha256)
+        expected = f"sha256={digest.hexdigest()}"
+        return hmac.compare_digest(request.headers.get("X-Hub-Signature-256", ""), expected)
+
+    async def _handle_github(self, request: web.Request) -> web.Response:
+        """Handle a GitHub webhook delivery, passing on published releases.
+
+        GitHub expects an answer within 10 seconds, so releases are posted in
+        the background.
+        """
+        body = await request.read()
+        if not self._has_github_signature(request, body):
+            logger.warning("Rejected GitHub webhook with a bad signature from %s", request.remote)
+            return web.json_response({"error": "Unauthorized"}, status=401)
+
+        event = request.headers.get("X-GitHub-Event", "")
+        if event == "ping":
+            return web.json_response({"status": "pong"})
+
+        try:
+            payload = json.loads(body)
+        except ValueError:
+            return web.json_response({"error": "Invalid JSON"}, status=400)
+        if not isinstance(payload, dict):
+            return web.json_response({"error": "Not a GitHub webhook payload"}, status=400)
+        if event != "release" or payload.get("action") != "published":
+            return web.json_response({"status": "ignored"}, status=202)
+
+        repository, release = payload.get("repository"), payload.get("release")
+        if (
+            not isinstance(repository, dict)
+            or not isinstance(repository.get("full_name"), str)
+            or not isinstance(release, dict)
+        ):
+            return web.json_response({"error": "Not a GitHub release payload"}, status=400)
+
+        repo = repository["full_name"]
+        channels = {name.lower() for name in settings.github_release_channels}
+        if repo.lower() not in channels:
+            logger.info("Ignoring release of unconfigured repository %s", repo)
+            return web.json_response({"status": "ignored"}, status=202)
+
+        logger.info("Received release %s of %s", release.get("tag_name"), repo)
+        watch_task(
+            asyncio.create_task(self._on_github_release(repo, release)),
+            "github_releases",
+            {"repository": repo, "tag": release.get("tag_name")},
+        )
+        return web.json_response({"status": "accepted"}, status=202)
+
+    async def _handle_alertmanager(self, request: web.Request) -> web.Response:
+        """Handle an Alertmanager webhook notification, authorized with API_TOKEN.
+
+        Alertmanager retries failed deliveries, so alerts are posted in the
+        background once the notification is accepted.
+        """
+        if not self._is_authorized(request):
+            logger.warning("Rejected Alertmanager webhook with a bad token from %s", request.remote)
+            return web.json_response({"error": "Unauthorized"}, status=401)
+
+        try:
+            payload = await request.json()
+        except ValueError:
+            return web.json_response({"error": "Invalid JSON"}, status=400)
+        if not isinstance(payload, dict) or not isinstance(payload.get("alerts"), list):
+            return web.json_response({"error": "Not an Alertmanager notification"}, status=400)
+
+        logger.info(
+            "Received %d %s alerts for group %s",
+            len(payload["alerts"]),
+            payload.get("status"),
+            payload.get("groupKey"),
+        )
+        watch_task(
+            asyncio.create_task(self._on_alerts(payload)),
+            "alertmanager",
+            {"groupKey": payload.get("groupKey"), "status": payload.get("status")},
+        )
+        return web.json_response({"status": "accepted"}, status=202)
+
+    async def _handle_peer_task(self, request: web.Request) -> web.Response:
+        """Handle a task sent by another bot of the fleet."""
+        body = await request.read()
+        try:
+            result = await self._on_peer_task(
+                request.headers, body, datetime.now(ZoneInfo("UTC"))
+            )
+        except PeerAuthError as e:
+            logger.warning("Rejected peer task from %s: %s", request.remote, e)
+            return web.json_response({"error": "Unauthorized"}, status=401)
+        except PeerError as e:
+            return web.json_response({"error": str(e)}, status=400)
+        return web.json_response(result)
+
+    async def _handle_health(self, request: web.Request) -> web.Response:
+        """Health check endpoint."""
+        return web.Response(text="OK", status=200)
+
+    async def _handle_ready(self, request: web.Request) -> web.Response:
+        """Readiness endpoint, failing on standby replicas so traffic goes to the leader."""
+        if not self._is_ready():
+            return web.Response(text="Standby", status=503)
+        return web.Response(text="OK", status=200)
+
+    async def _handle_metrics(self, request: web.Request) -> web.Response:
+        """Prometheus metrics endpoint."""
+        return web.Response(text=self._metrics(), content_type="text/plain", charset="utf-8")
+
+    async def _handle_calendar(self, request: web.Request) -> web.Response:
+        """iCal feed of a guild's schedules, public so calendar apps can subscribe."""
+        feed = self._calendar_feed(int(request.match_info["guild_id"]))
+        if feed is None:
+            return web.json_response({"error": "Not found"}, status=404)
+        return web.Response(text=feed, content_type="text/calendar", charset="utf-8")
+
+    async def start(self) -> None:
+        """Start the API server."""
+        self._runner = web.AppRunner(self._app)
+        await self._runner.setup()
+
+        site = web.TCPSite(self._runner, host=settings.api_host, port=settings.api_port)
+        await site.start()
+
+        logger.info("API server started on %s:%d", settings.api_host, settings.api_port)
+
+    async def stop(self) -> None:
+        """Stop the API server."""
+        if self._runner:
+            await self._runner.cleanup()
+            logger.info("API server stopped")
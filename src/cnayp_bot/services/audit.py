@@ -0,0 +1,54 @@
+"""Audit trail of privileged operations, e.g. those run in the debug console."""
+
+import logging
+import uuid
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# Timestamped key -> {"time", "user_id", "user", "action", "outcome"}
+AUDIT_LOG = "audit_log"
+
+# Entries kept; older ones are dropped first
+MAX_ENTRIES = 500
+
+
+class AuditTrail:
+    """Records who ran what and how it went, in the store and the logs.
+
+    Denied attempts are recorded too, so probing the console leaves a trace.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def record(self, user_id: int, user: str, action: str, outcome: str) -> None:
+        """Record an operation a member ran or tried to run."""
+        now = datetime.now(ZoneInfo("UTC"))
+        logger.warning("[audit] %s (%d) ran %r: %s", user, user_id, action, outcome)
+
+        # Timestamped keys keep entries in chronological order when sorted
+        key = f"{now.isoformat()}-{uuid.uuid4().hex[:6]}"
+        self._store.set(
+            AUDIT_LOG,
+            key,
+            {
+                "time": now.isoformat(),
+                "user_id": user_id,
+                "user": user,
+                "action": action,
+                "outcome": outcome,
+            },
+        )
+
+        entries = self._store.items(AUDIT_LOG)
+        for old_key in sorted(entries)[: max(0, len(entries) - MAX_ENTRIES)]:
+            self._store.delete(AUDIT_LOG, old_key)
+
+    def recent(self, limit: int) -> list[dict]:
+        """Return the latest entries, newest first."""
+        entries = self._store.items(AUDIT_LOG)
+        return [entries[key] for key in sorted(entries, reverse=True)[:limit]]
@@ -0,0 +1,172 @@
+"""Native Discord AutoMod rules kept in a file and reconciled with the guild's."""
+
+import json
+import logging
+from dataclasses import dataclass
+from datetime import timedelta
+from pathlib import Path
+
+import discord
+
+from ..models.automod import AutomodConfig, AutomodRule
+
+logger = logging.getLogger(__name__)
+
+_TRIGGERS = {
+    "keyword": discord.AutoModRuleTriggerType.keyword,
+    "mention_spam": discord.AutoModRuleTriggerType.mention_spam,
+}
+
+
+@dataclass(frozen=True)
+class RuleState:
+    """What an AutoMod rule does, comparable between the rules file and Discord."""
+
+    trigger: str
+    enabled: bool
+    keywords: frozenset[str]
+    regex_patterns: frozenset[str]
+    allow_list: frozenset[str]
+    mention_limit: int | None
+    mention_raid_protection: bool
+    block: bool
+    block_message: str
+    alert_channel_id: int | None
+    timeout_minutes: int
+    exempt_role_ids: frozenset[int]
+    exempt_channel_ids: frozenset[int]
+
+
+@dataclass
+class RulePlan:
+    """Names of the rules to create, edit, and replace to match the rules file."""
+
+    create: list[str]
+    edit: list[str]
+    # Rules whose trigger changed, which Discord can't edit
+    replace: list[str]
+
+
+def load_automod_config(path: Path) -> AutomodConfig:
+    """Load the AutoMod rules file, or an empty config if it doesn't exist."""
+    if not path.exists():
+        logger.info("AutoMod rules file not found, not managing AutoMod: %s", path)
+        return AutomodConfig()
+
+    with path.open(encoding="utf-8") as f:
+        return AutomodConfig.model_validate(json.load(f))
+
+
+def desired_state(rule: AutomodRule, guild: discord.Guild) -> RuleState:
+    """Return what a rule from the file should do in a guild, finding its roles and channels.
+
+    Raises:
+        ValueError: If a role or channel the rule names doesn't exist.
+    """
+
+    def find(items, name: str, kind: str) -> int:
+        item = discord.utils.get(items, name=name)
+        if item is None:
+            raise ValueError(f"Unknown {kind} {name!r} in AutoMod rule {rule.name!r}")
+        return item.id
+
+    keyword = rule.trigger == "keyword"
+    return RuleState(
+        trigger=rule.trigger,
+        enabled=rule.enabled,
+        keywords=frozenset(rule.keywords if keyword else []),
+        regex_patterns=frozenset(rule.regex_patterns if keyword else []),
+        allow_list=frozenset(rule.allow_list if keyword else []),
+        mention_limit=None if keyword else rule.mention_limit,
+        mention_raid_protection=not keyword and rule.mention_raid_protection,
+        block=rule.block,
+        block_message=rule.block_message if rule.block else "",
+        alert_channel_id=(
+            find(guild.text_channels, rule.alert_channel, "channel") if rule.alert_channel else None
+        ),
+        timeout_minutes=rule.timeout_minutes,
+        exempt_role_ids=frozenset(find(guild.roles, name, "role") for name in rule.exempt_roles),
+        exempt_channel_ids=frozenset(
+            find(guild.channels, name, "channel") for name in rule.exempt_channels
+        ),
+    )
+
+
+def current_state(rule: discord.AutoModRule) -> RuleState | None:
+    """Return what a guild's rule does, or None for triggers the file can't describe."""
+    trigger = next((name for name, kind in _TRIGGERS.items() if kind == rule.trigger.type), None)
+    if trigger is None:
+        return None
+
+    actions = {action.type: action for action in rule.actions}
+    block = actions.get(discord.AutoModRuleActionType.block_message)
+    alert = actions.get(discord.AutoModRuleActionType.send_alert_message)
+    timeout = actions.get(discord.AutoModRuleActionType.timeout)
+    keyword = trigger == "keyword"
+    return RuleState(
+        trigger=trigger,
+        enabled=rule.enabled,
+        keywords=frozenset(rule.trigger.keyword_filter if keyword else []),
+        regex_patterns=frozenset(rule.trigger.regex_patterns if keyword else []),
+        allow_list=frozenset(rule.trigger.allow_list if keyword else []),
+        mention_limit=None if keyword else rule.trigger.mention_limit,
+        mention_raid_protection=not keyword and bool(rule.trigger.mention_raid_protection),
+        block=block is not None,
+        block_message=(block.custom_message or "") if block else "",
+        alert_channel_id=alert.channel_id if alert else None,
+        timeout_minutes=int(timeout.duration.total_seconds() // 60) if timeout else 0,
+        exempt_role_ids=frozenset(rule.exempt_role_ids),
+        exempt_channel_ids=frozenset(rule.exempt_channel_ids),
+    )
+
+
+def plan_changes(
+    desired: dict[str, RuleState], current: dict[str, RuleState | None]
+) -> RulePlan:
+    """Compare the file's rules with the guild's, by name.
+
+    Rules only in the guild are left alone, so moderators can still add their
+    own in Discord; a rule that should be gone is removed from Discord by hand.
+    """
+    plan = RulePlan(create=[], edit=[], replace=[])
+    for name, state in desired.items():
+        if name not in current:
+            plan.create.append(name)
+        elif current[name] is None or current[name].trigger != state.trigger:
+            plan.replace.append(name)
+        elif current[name] != state:
+            plan.edit.append(name)
+    return plan
+
+
+def rule_trigger(state: RuleState) -> discord.AutoModTrigger:
+    """Return the Discord trigger of a rule."""
+    if state.trigger == "keyword":
+        return discord.AutoModTrigger(
+            type=_TRIGGERS["keyword"],
+            keyword_filter=sorted(state.keywords),
+            regex_patterns=sorted(state.regex_patterns),
+            allow_list=sorted(state.allow_list),
+        )
+    return discord.AutoModTrigger(
+        type=_TRIGGERS["mention_spam"],
+        mention_limit=state.mention_limit,
+        mention_raid_protection=state.mention_raid_protection,
+    )
+
+
+def rule_actions(state: RuleState) -> list[discord.AutoModRuleAction]:
+    """Return the Discord actions of a rule."""
+    actions = []
+    if state.block:
+        actions.append(
+            discord.AutoModRuleAction(
+                type=discord.AutoModRuleActionType.block_message,
+                custom_message=state.block_message or None,
+            )
+        )
+    if state.alert_channel_id:
+        actions.append(discord.AutoModRuleAction(channel_id=state.alert_channel_id))
+    if state.timeout_minutes:
+        actions.append(discord.AutoModRuleAction(duration=timedelta(minutes=state.timeout_minutes)))
+    return actions
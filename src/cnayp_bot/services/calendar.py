@@ -12,13 +12,14 @@ from google.oauth2 import service_account
 from googleapiclient.discovery import build
 
 from ..config import settings
+from ..models import Schedule
 
 logger = logging.getLogger(__name__)
 
 SCOPES = ["https://www.googleapis.com/auth/calendar.readonly"]
 
 
-def _get_credentials():
+def get_credentials(scopes: list[str] = SCOPES):
     """Get Google credentials using service account file or ADC.
 
     Priority:
@@ -30,12 +31,12 @@ def _get_credentials():
         if creds_path.exists():
             logger.info("Using service account file: %s", creds_path)
             return service_account.Credentials.from_service_account_file(
-                str(creds_path), scopes=SCOPES
+                str(creds_path), scopes=scopes
             )
         logger.warning("Service account file not found: %s, falling back to ADC", creds_path)
 
     logger.info("Using Application Default Credentials (ADC)")
-    credentials, project = google.auth.default(scopes=SCOPES)
+    credentials, project = google.auth.default(scopes=scopes)
     return credentials
 
 
@@ -49,6 +50,8 @@ class CalendarEvent:
     start_time: datetime
     end_time: datetime
     timezone: str
+    schedule: Schedule | None = None  # Set for occurrences of a schedules.json entry
+    voice_channel: str | None = None  # Set for approved event submissions
 
     @property
     def duration_minutes(self) -> int:
@@ -77,7 +80,7 @@ class CalendarService:
     def _get_service(self):
         """Get or create the Google Calendar service."""
         if self._service is None:
-            credentials = _get_credentials()
+            credentials = get_credentials()
             self._service = build("calendar", "v3", credentials=credentials)
 
         return self._service
@@ -91,29 +94,46 @@ class CalendarService:
         Returns:
             List of CalendarEvent objects.
         """
-        service = self._get_service()
-
         now = datetime.now(ZoneInfo("UTC"))
-        time_min = now.isoformat()
-        time_max = (now + timedelta(hours=hours_ahead)).isoformat()
+        return self.get_events_between(now, now + timedelta(hours=hours_ahead))
+
+    def get_events_between(self, start: datetime, end: datetime) -> list[CalendarEvent]:
+        """Fetch events starting within a time range, following result pages.
+
+        Args:
+            start: Start of the range (inclusive).
+            end: End of the range (exclusive).
+
+        Returns:
+            List of CalendarEvent objects ordered by start time.
+        """
+        service = self._get_service()
+        events = []
+        page_token = None
 
         try:
-            events_result = (
-                service.events()
-                .list(
-                    calendarId=settings.google_calendar_id,
-                    timeMin=time_min,
-                    timeMax=time_max,
-                    singleEvents=True,
-                    orderBy="startTime",
+            while True:
+                events_result = (
+                    service.events()
+                    .list(
+                        calendarId=settings.google_calendar_id,
+                        timeMin=start.isoformat(),
+                        timeMax=end.isoformat(),
+                        singleEvents=True,
+                        orderBy="startTime",
+                        pageToken=page_token,
+                    )
+                    .execute()
                 )
-                .execute()
-            )
+                events.extend(events_result.get("items", []))
+
+                page_token = events_result.get("nextPageToken")
+                if not page_token:
+                    break
         except Exception as e:
             logger.error("Failed to fetch calendar events: %s", e)
             return []
 
-        events = events_result.get("items", [])
         return [self._parse_event(event) for event in events]
 
     def setup_watch(self, webhook_url: str) -> WatchChannel | None:
@@ -189,16 +209,17 @@ class CalendarService:
         """Get the current watch channel."""
         return self._watch_channel
 
-    def get_changes(self) -> list[CalendarEvent]:
+    def get_changes(self) -> tuple[list[CalendarEvent], list[str]]:
         """Get calendar changes since last sync.
 
         Uses sync tokens to efficiently fetch only changed events.
 
         Returns:
-            List of new/updated CalendarEvent objects.
+            New/updated CalendarEvent objects and the IDs of cancelled events.
         """
         service = self._get_service()
         events = []
+        cancelled = []
 
         try:
             request_params = {
@@ -221,8 +242,9 @@ class CalendarService:
                 response = service.events().list(**request_params).execute()
 
                 for item in response.get("items", []):
-                    # Skip cancelled events
+                    # Cancelled events may only carry their ID, so they can't be parsed
                     if item.get("status") == "cancelled":
+                        cancelled.append(item["id"])
                         continue
                     events.append(self._parse_event(item))
 
@@ -231,8 +253,8 @@ class CalendarService:
                     self._sync_token = response.get("nextSyncToken")
                     break
 
-            logger.info("Fetched %d changed events", len(events))
-            return events
+            logger.info("Fetched %d changed and %d cancelled events", len(events), len(cancelled))
+            return events, cancelled
 
         except Exception as e:
             # Sync token might be invalid, reset and do full sync
@@ -242,7 +264,28 @@ class CalendarService:
                 return self.get_changes()
 
             logger.error("Failed to fetch changes: %s", e)
-            return []
+            return [], []
+
+    def is_cancelled(self, event_id: str) -> bool:
+        """Check whether an event was cancelled or deleted from the calendar.
+
+        Returns False when the calendar can't be reached, so a network error
+        never cancels anything.
+        """
+        service = self._get_service()
+        try:
+            event = (
+                service.events()
+                .get(calendarId=settings.google_calendar_id, eventId=event_id)
+                .execute()
+            )
+        except Exception as e:
+            if "404" in str(e) or "410" in str(e):
+                return True
+            logger.error("Failed to fetch event %s: %s", event_id, e)
+            return False
+
+        return event.get("status") == "cancelled"
 
     def _parse_event(self, event: dict) -> CalendarEvent:
         """Parse a Google Calendar event into a CalendarEvent object."""
@@ -0,0 +1,56 @@
+"""Canary mode, soft-launching a feature's output in a test channel first."""
+
+import logging
+from datetime import datetime, timedelta
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# Feature -> {"started": ISO time of its first canary output, "ends": ISO time}
+CANARY = "canary"
+
+# Outputs that can be soft-launched
+FEATURES = ("announcements", "digest")
+
+
+class Canary:
+    """Routes the output of features in canary mode to the canary channel.
+
+    A feature's canary period starts with its first output and lasts `period`;
+    after that, or once it's promoted by hand, the feature posts in its real
+    channels again. Features that aren't listed, or every feature when there's
+    no canary channel, always post in their real channels.
+    """
+
+    def __init__(
+        self, store: Store, channel: str | None, features: list[str], period: timedelta
+    ) -> None:
+        self._store = store
+        self.channel = channel
+        self.features = [feature for feature in features if feature in FEATURES]
+        self.period = period
+        for feature in set(features) - set(FEATURES):
+            logger.warning("Unknown canary feature %s, use one of: %s", feature, FEATURES)
+
+    def route(self, feature: str, channel: str, now: datetime) -> str:
+        """Return the channel a feature's output goes to, starting its canary period."""
+        if not self.channel or feature not in self.features:
+            return channel
+
+        entry = self._store.get(CANARY, feature)
+        if entry is None:
+            entry = {"started": now.isoformat(), "ends": (now + self.period).isoformat()}
+            self._store.set(CANARY, feature, entry)
+            logger.info("Canary period of %s started in #%s", feature, self.channel)
+        return self.channel if now < datetime.fromisoformat(entry["ends"]) else channel
+
+    def until(self, feature: str) -> datetime | None:
+        """Return when a feature moves to its real channels, or None if it hasn't started."""
+        entry = self._store.get(CANARY, feature)
+        return datetime.fromisoformat(entry["ends"]) if entry else None
+
+    def promote(self, feature: str, now: datetime) -> None:
+        """End a feature's canary period now."""
+        entry = self._store.get(CANARY, feature) or {"started": now.isoformat()}
+        self._store.set(CANARY, feature, entry | {"ends": now.isoformat()})
@@ -0,0 +1,111 @@
+"""Pre-event checklists for schedule owners, with who ticked each item."""
+
+import uuid
+from datetime import datetime, timedelta
+
+from .store import Store
+
+# Checklist ID -> {"event_id", "name", "start", "items": [{"text", "done_by"}],
+# "thread_id", "message_id", "nagged"}
+CHECKLISTS = "checklists"
+
+# Schedule name -> ID of the thread its checklists are posted in
+CHECKLIST_THREADS = "checklist_threads"
+
+# How long checklists are kept after their event started
+RETENTION = timedelta(days=1)
+
+
+class Checklists:
+    """Tracks the checklist of each upcoming occurrence and the items done.
+
+    Checklists have short IDs of their own, since event IDs can be too long
+    for the buttons that tick their items.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def open(self, event_id: str, name: str, start: datetime, items: list[str]) -> str:
+        """Start an occurrence's checklist and return its ID."""
+        checklist_id = uuid.uuid4().hex[:8]
+        self._store.set(
+            CHECKLISTS,
+            checklist_id,
+            {
+                "event_id": event_id,
+                "name": name,
+                "start": start.isoformat(),
+                "items": [{"text": text, "done_by": None} for text in items],
+                "thread_id": None,
+                "message_id": None,
+                "nagged": False,
+            },
+        )
+        return checklist_id
+
+    def discard(self, checklist_id: str) -> None:
+        """Drop a checklist that couldn't be posted, so it's tried again."""
+        self._store.delete(CHECKLISTS, checklist_id)
+
+    def get(self, checklist_id: str) -> dict | None:
+        """Return a checklist, or None if it's finished or unknown."""
+        return self._store.get(CHECKLISTS, checklist_id)
+
+    def find(self, event_id: str) -> str | None:
+        """Return the ID of an occurrence's checklist, if it was posted."""
+        for checklist_id, checklist in self._store.items(CHECKLISTS).items():
+            if checklist["event_id"] == event_id:
+                return checklist_id
+        return None
+
+    def set_message(self, checklist_id: str, thread_id: int, message_id: int) -> None:
+        """Record the message the checklist was posted in."""
+        checklist = self._store.get(CHECKLISTS, checklist_id)
+        self._store.set(
+            CHECKLISTS, checklist_id, checklist | {"thread_id": thread_id, "message_id": message_id}
+        )
+
+    def toggle(self, checklist_id: str, index: int, user_id: int) -> bool:
+        """Tick an item as done by a user, or untick it.
+
+        Returns:
+            True if the item is now done.
+        """
+        checklist = self._store.get(CHECKLISTS, checklist_id)
+        item = checklist["items"][index]
+        item["done_by"] = None if item["done_by"] else user_id
+        self._store.set(CHECKLISTS, checklist_id, checklist)
+        return item["done_by"] is not None
+
+    def due_nags(self, now: datetime, lead: timedelta) -> list[str]:
+        """Return the posted checklists with items left `lead` before their event."""
+        return [
+            checklist_id
+            for checklist_id, checklist in self._store.items(CHECKLISTS).items()
+            if checklist["message_id"]
+            and not checklist["nagged"]
+            and any(not item["done_by"] for item in checklist["items"])
+            and datetime.fromisoformat(checklist["start"]) - lead
+            <= now
+            < datetime.fromisoformat(checklist["start"])
+        ]
+
+    def mark_nagged(self, checklist_id: str) -> None:
+        """Record that the owners were reminded of the items left."""
+        checklist = self._store.get(CHECKLISTS, checklist_id)
+        self._store.set(CHECKLISTS, checklist_id, checklist | {"nagged": True})
+
+    def thread(self, schedule: str) -> int | None:
+        """Return the ID of the thread a schedule's checklists go in."""
+        return self._store.get(CHECKLIST_THREADS, schedule)
+
+    def set_thread(self, schedule: str, thread_id: int) -> None:
+        """Record the thread a schedule's checklists go in."""
+        self._store.set(CHECKLIST_THREADS, schedule, thread_id)
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop the checklists of events that started a while ago."""
+        for checklist_id, checklist in self._store.items(CHECKLISTS).items():
+            if datetime.fromisoformat(checklist["start"]) < now - RETENTION:
+                self._store.delete(CHECKLISTS, checklist_id)
@@ -0,0 +1,108 @@
+"""Restart-safe routing for button and select interactions."""
+
+import base64
+import hashlib
+import hmac
+import logging
+from collections.abc import Awaitable, Callable
+
+import discord
+
+from .errors import report_error
+
+logger = logging.getLogger(__name__)
+
+CUSTOM_ID_LIMIT = 100
+SIGNATURE_LENGTH = 12
+
+ComponentHandler = Callable[[discord.Interaction, str], Awaitable[None]]
+
+
+class ComponentRouter:
+    """Routes component interactions by handler name encoded in `custom_id`.
+
+    A custom ID looks like `rsvp:12345:<signature>`: the handler name, an
+    opaque payload, and a truncated HMAC of both. Nothing is kept in memory
+    per message, so buttons on long-lived announcements keep working after a
+    restart, and the signature stops users from forging payloads.
+    """
+
+    def __init__(self, secret: bytes) -> None:
+        self._secret = secret
+        self._handlers: dict[str, ComponentHandler] = {}
+
+    def register(self, name: str, handler: ComponentHandler) -> None:
+        """Register a handler for custom IDs created with `name`."""
+        if ":" in name:
+            raise ValueError(f"Handler name can't contain ':': {name!r}")
+        self._handlers[name] = handler
+
+    def custom_id(self, name: str, payload: str = "") -> str:
+        """Encode a handler name and payload into a signed custom ID."""
+        custom_id = f"{name}:{payload}:{self._sign(name, payload)}"
+        if len(custom_id) > CUSTOM_ID_LIMIT:
+            raise ValueError(f"Custom ID exceeds {CUSTOM_ID_LIMIT} chars: {custom_id!r}")
+        return custom_id
+
+    def decode(self, custom_id: str) -> tuple[str, str] | None:
+        """Decode a custom ID, returning None if it's malformed or forged."""
+        name, sep, rest = custom_id.partition(":")
+        payload, sep2, signature = rest.rpartition(":")
+        if not sep or not sep2:
+            return None
+        if not hmac.compare_digest(signature, self._sign(name, payload)):
+            return None
+        return name, payload
+
+    def button(
+        self,
+        name: str,
+        payload: str = "",
+        *,
+        label: str | None = None,
+        emoji: str | None = None,
+        style: discord.ButtonStyle = discord.ButtonStyle.secondary,
+    ) -> discord.ui.Button:
+        """Create a button routed to the `name` handler."""
+        return discord.ui.Button(
+            custom_id=self.custom_id(name, payload), label=label, emoji=emoji, style=style
+        )
+
+    async def dispatch(self, interaction: discord.Interaction) -> bool:
+        """Route a component interaction to its handler.
+
+        Returns:
+            True if a registered handler took the interaction.
+        """
+        custom_id = (interaction.data or {}).get("custom_id", "")
+        decoded = self.decode(custom_id)
+        if decoded is None:
+            return False
+
+        name, payload = decoded
+        handler = self._handlers.get(name)
+        if handler is None:
+            logger.warning("No component handler registered for %s", name)
+            return False
+
+        try:
+            await handler(interaction, payload)
+        except Exception as e:
+            correlation_id = await report_error(
+                interaction.client,
+                e,
+                operation=f"component {name}",
+                context={"User": f"{interaction.user} ({interaction.user.id})", "Payload": payload},
+                subsystem="components",
+            )
+            message = f"Something went wrong. Reference: `{correlation_id}`"
+            if interaction.response.is_done():
+                await interaction.followup.send(message, ephemeral=True)
+            else:
+                await interaction.response.send_message(message, ephemeral=True)
+
+        return True
+
+    def _sign(self, name: str, payload: str) -> str:
+        digest = hmac.new(self._secret, f"{name}:{payload}".encode(), hashlib.sha256).digest()
+        return base64.urlsafe_b64encode(digest).decode()[:SIGNATURE_LENGTH]
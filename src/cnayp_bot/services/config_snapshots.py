@@ -0,0 +1,64 @@
+"""Earlier versions of the schedules file, saved before the bot changes it."""
+
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from ..models import ScheduleConfig
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# Snapshot ID -> {"at": ISO time, "reason": what changed the file, "config": the file before}
+SNAPSHOTS = "config_snapshots"
+
+# Snapshots kept, the oldest dropped first
+MAX_SNAPSHOTS = 20
+
+
+class ConfigSnapshots:
+    """Keeps the last versions of the schedules config, to roll a change back.
+
+    A snapshot is taken before every change the bot makes to the file, from
+    commands, the admin API, or sheet syncs. Hand edits aren't snapshotted,
+    but a rollback snapshots the config it replaces, so it can be undone too.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def save(self, config: ScheduleConfig, reason: str) -> int:
+        """Snapshot a config about to be replaced, returning the snapshot's ID."""
+        snapshots = self._store.items(SNAPSHOTS)
+        snapshot_id = max((int(key) for key in snapshots), default=0) + 1
+        self._store.set(
+            SNAPSHOTS,
+            str(snapshot_id),
+            {
+                "at": datetime.now(ZoneInfo("UTC")).isoformat(),
+                "reason": reason,
+                "config": config.model_dump(mode="json"),
+            },
+        )
+        for key in sorted(snapshots, key=int)[: max(0, len(snapshots) + 1 - MAX_SNAPSHOTS)]:
+            self._store.delete(SNAPSHOTS, key)
+        return snapshot_id
+
+    def list(self) -> list[tuple[int, dict]]:
+        """Return the snapshots by ID, newest first."""
+        snapshots = self._store.items(SNAPSHOTS)
+        return sorted(
+            ((int(key), snapshot) for key, snapshot in snapshots.items()), reverse=True
+        )
+
+    def config(self, snapshot_id: int) -> ScheduleConfig | None:
+        """Return a snapshot's config, or None if there's no such snapshot.
+
+        Raises:
+            ValueError: If the snapshot isn't a valid config anymore, e.g.
+                after an upgrade changed the schedules format.
+        """
+        snapshot = self._store.get(SNAPSHOTS, str(snapshot_id))
+        if snapshot is None:
+            return None
+        return ScheduleConfig.model_validate(snapshot["config"])
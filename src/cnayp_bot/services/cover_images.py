@@ -0,0 +1,113 @@
+"""Cover images of schedules' Discord events, read from a file or downloaded."""
+
+import base64
+import logging
+from pathlib import Path
+
+import aiohttp
+
+logger = logging.getLogger(__name__)
+
+# Discord rejects larger event covers
+MAX_IMAGE_BYTES = 10 * 1024 * 1024
+
+# Leading bytes of the image formats Discord accepts as event covers
+_SIGNATURES = {
+    b"\x89PNG\r\n\x1a\n": "image/png",
+    b"\xff\xd8\xff": "image/jpeg",
+    b"GIF87a": "image/gif",
+    b"GIF89a": "image/gif",
+}
+
+
+class CoverImageError(Exception):
+    """Raised when a cover image can't be read, downloaded, or isn't a supported image."""
+
+
+def is_url(source: str) -> bool:
+    """Check whether a cover image is downloaded rather than read from a file."""
+    return source.startswith(("http://", "https://"))
+
+
+def image_type(data: bytes) -> str:
+    """Return the MIME type of an image from its leading bytes.
+
+    Raises:
+        CoverImageError: If it isn't a PNG, JPEG, GIF, or WebP image.
+    """
+    for signature, mime_type in _SIGNATURES.items():
+        if data.startswith(signature):
+            return mime_type
+    if data[:4] == b"RIFF" and data[8:12] == b"WEBP":
+        return "image/webp"
+    raise CoverImageError("not a PNG, JPEG, GIF, or WebP image")
+
+
+def data_uri(data: bytes) -> str:
+    """Encode an image as the data URI Discord takes for event covers.
+
+    Raises:
+        CoverImageError: If it isn't a supported image.
+    """
+    return f"data:{image_type(data)};base64,{base64.b64encode(data).decode('ascii')}"
+
+
+class CoverImages:
+    """Loads cover images, keeping downloaded ones so each URL is fetched once.
+
+    Files are read every time, so a replaced image is used for the next event
+    without a restart. Relative paths are relative to `base_dir`, the schedules
+    file's directory.
+    """
+
+    def __init__(self, base_dir: Path) -> None:
+        self.base_dir = base_dir
+        self._downloaded: dict[str, bytes] = {}
+
+    def path(self, source: str) -> Path:
+        """Return where a cover image file is read from."""
+        return self.base_dir / Path(source).expanduser()
+
+    async def load(self, source: str) -> bytes:
+        """Return a cover image's bytes, checking it's an image Discord accepts.
+
+        Raises:
+            CoverImageError: If it can't be read or downloaded, or isn't a supported image.
+        """
+        if is_url(source):
+            data = self._downloaded.get(source)
+            if data is None:
+                data = await self._download(source)
+        else:
+            try:
+                data = self.path(source).read_bytes()
+            except OSError as e:
+                raise CoverImageError(f"can't read {source}: {e.strerror or e}") from e
+
+        if len(data) > MAX_IMAGE_BYTES:
+            raise CoverImageError(f"{source} is over {MAX_IMAGE_BYTES // (1024 * 1024)} MB")
+        image_type(data)
+        if is_url(source):
+            self._downloaded[source] = data
+        return data
+
+    async def _download(self, url: str) -> bytes:
+        """Download a cover image.
+
+        Raises:
+            CoverImageError: If the download fails.
+        """
+        timeout = aiohttp.ClientTimeout(total=30)
+        try:
+            async with aiohttp.ClientSession(timeout=timeout) as session:
+                async with session.get(url) as response:
+                    response.raise_for_status()
+                    if (response.content_length or 0) > MAX_IMAGE_BYTES:
+                        raise CoverImageError(
+                            f"{url} is over {MAX_IMAGE_BYTES // (1024 * 1024)} MB"
+                        )
+                    data = await response.read()
+        except (aiohttp.ClientError, TimeoutError) as e:
+            raise CoverImageError(f"can't download {url}: {e}") from e
+        logger.info("Downloaded cover image %s (%d bytes)", url, len(data))
+        return data
@@ -0,0 +1,101 @@
+"""Optional crash reporting to Sentry, tagged with the subsystem that failed."""
+
+import asyncio
+import logging
+from typing import Any
+
+from ..config import settings
+from .updates import build_info
+
+logger = logging.getLogger(__name__)
+
+_enabled = False
+
+
+def init_crash_reports() -> bool:
+    """Start sending errors to Sentry when `SENTRY_DSN` is set.
+
+    Errors logged at ERROR level or above become Sentry events, which covers
+    background loops and aiohttp handlers; commands, buttons, and background
+    tasks also attach the payload that caused them. `sentry-sdk` is an
+    optional dependency (`pip install .[sentry]`).
+
+    Returns:
+        Whether crash reports are sent.
+    """
+    global _enabled
+    if not settings.sentry_dsn:
+        return False
+
+    try:
+        import sentry_sdk
+        from sentry_sdk.integrations.logging import LoggingIntegration
+    except ImportError:
+        logger.warning("SENTRY_DSN is set but sentry-sdk isn't installed, not reporting crashes")
+        return False
+
+    info = build_info()
+    sentry_sdk.init(
+        dsn=settings.sentry_dsn,
+        environment=settings.sentry_environment,
+        release=f"cnayp-bot@{info['version']}+{info['commit']}",
+        integrations=[LoggingIntegration(level=logging.INFO, event_level=logging.ERROR)],
+        before_send=_tag_subsystem,
+        send_default_pii=False,
+    )
+    sentry_sdk.set_tag("instance", settings.instance_id)
+    _enabled = True
+    logger.info("Reporting crashes to Sentry (%s)", settings.sentry_environment)
+    return True
+
+
+def capture_exception(
+    error: BaseException, subsystem: str, payload: dict[str, Any] | None = None
+) -> None:
+    """Send an error to Sentry with its subsystem and the payload that caused it."""
+    if not _enabled:
+        return
+
+    import sentry_sdk
+
+    with sentry_sdk.new_scope() as scope:
+        scope.set_tag("subsystem", subsystem)
+        if payload:
+            scope.set_context("payload", payload)
+        sentry_sdk.capture_exception(error)
+
+
+def watch_task(
+    task: asyncio.Task, subsystem: str, payload: dict[str, Any] | None = None
+) -> asyncio.Task:
+    """Report a background task that fails, instead of letting it die silently.
+
+    Returns:
+        The task, so it can be watched where it's created.
+    """
+
+    def done(task: asyncio.Task) -> None:
+        if task.cancelled() or task.exception() is None:
+            return
+        error = task.exception()
+        capture_exception(error, subsystem, payload)
+        logger.error("Background task %s failed: %s", task.get_name(), error, exc_info=error)
+
+    task.add_done_callback(done)
+    return task
+
+
+def handle_loop_exception(loop: asyncio.AbstractEventLoop, context: dict[str, Any]) -> None:
+    """Report exceptions the event loop catches, such as in unwatched tasks."""
+    error = context.get("exception")
+    if error is not None:
+        capture_exception(error, "asyncio", {"message": context.get("message", "")})
+    loop.default_exception_handler(context)
+
+
+def _tag_subsystem(event: dict[str, Any], hint: dict[str, Any]) -> dict[str, Any]:
+    """Tag events without a subsystem with the module that logged them."""
+    tags = event.setdefault("tags", {})
+    if isinstance(tags, dict) and "subsystem" not in tags:
+        tags["subsystem"] = (event.get("logger") or "unknown").rsplit(".", 1)[-1]
+    return event
@@ -0,0 +1,39 @@
+"""The Discord REST API version and user agent of the bot's requests."""
+
+import logging
+from typing import Any
+
+import discord
+
+logger = logging.getLogger(__name__)
+
+# The API version discord.py is written against
+DEFAULT_API_VERSION = 10
+
+
+def api_base(version: int) -> str:
+    """Return the base URL of a version of Discord's REST API."""
+    return f"https://discord.com/api/v{version}"
+
+
+def route(method: str, path: str, **parameters: Any) -> discord.http.Route:
+    """Return a route for a raw request, e.g. to endpoints discord.py doesn't cover yet.
+
+    Routes are sent to the configured API version, the same as discord.py's own.
+    """
+    return discord.http.Route(method, path, **parameters)
+
+
+def configure_http(http: discord.http.HTTPClient, version: int, user_agent: str | None) -> None:
+    """Send the bot's REST requests to an API version, with its own user agent if set.
+
+    The version applies to every route, including discord.py's, so it's set
+    once on startup; the gateway stays on discord.py's version.
+    """
+    discord.http.Route.BASE = api_base(version)
+    if user_agent:
+        http.user_agent = user_agent
+    if version != DEFAULT_API_VERSION:
+        logger.warning(
+            "Using Discord API v%d, discord.py is written for v%d", version, DEFAULT_API_VERSION
+        )
@@ -0,0 +1,54 @@
+"""DM reminders to members interested in or going to an event, and who opted out."""
+
+from datetime import datetime
+
+from .store import Store
+
+# User ID -> True for members who turned the DMs off
+DM_REMINDER_OPTOUTS = "dm_reminder_optouts"
+
+# Event ID -> {"end": ..., "users": [...]} already reminded, so restarts don't DM them twice
+DM_REMINDERS_SENT = "dm_reminders_sent"
+
+
+class DmReminders:
+    """Tracks who was DMed about each occurrence, and who doesn't want DMs."""
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def opted_out(self, user_id: int) -> bool:
+        """Check whether a member turned the DMs off."""
+        return self._store.get(DM_REMINDER_OPTOUTS, str(user_id), False)
+
+    def set_opted_out(self, user_id: int, opted_out: bool) -> None:
+        """Turn the DMs off for a member, or back on."""
+        if opted_out:
+            self._store.set(DM_REMINDER_OPTOUTS, str(user_id), True)
+        else:
+            self._store.delete(DM_REMINDER_OPTOUTS, str(user_id))
+
+    def recipients(self, event_id: str, candidates: list[int]) -> list[int]:
+        """Return the candidates still to remind, once each, leaving out those who opted out."""
+        entry = self._store.get(DM_REMINDERS_SENT, event_id)
+        sent = set(entry["users"]) if entry else set()
+        recipients = []
+        for user_id in candidates:
+            if user_id not in sent and user_id not in recipients and not self.opted_out(user_id):
+                recipients.append(user_id)
+        return recipients
+
+    def mark_sent(self, event_id: str, end: datetime, user_id: int) -> None:
+        """Record that a member was reminded of an occurrence."""
+        entry = self._store.get(DM_REMINDERS_SENT, event_id) or {
+            "end": end.isoformat(),
+            "users": [],
+        }
+        entry["users"].append(user_id)
+        self._store.set(DM_REMINDERS_SENT, event_id, entry)
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop who was reminded of occurrences that ended."""
+        for event_id, entry in self._store.items(DM_REMINDERS_SENT).items():
+            if datetime.fromisoformat(entry["end"]) <= now:
+                self._store.delete(DM_REMINDERS_SENT, event_id)
@@ -0,0 +1,50 @@
+"""Edit history of messages in moderated channels."""
+
+from datetime import datetime, timedelta
+
+from .store import Store
+
+# message ID -> {"channel_id", "author_id", "edits": [{"at", "before", "after"}]}
+EDITS = "message_edits"
+
+# Edits kept per message; older ones are dropped first
+MAX_EDITS = 20
+
+
+class EditHistory:
+    """Records each edit of a message, forgetting messages not edited in a while."""
+
+    def __init__(self, store: Store, retention: timedelta) -> None:
+        self._store = store
+        self.retention = retention
+
+    def record(
+        self,
+        message_id: int,
+        channel_id: int,
+        author_id: int,
+        before: str,
+        after: str,
+        at: datetime,
+    ) -> None:
+        """Record an edit of a message."""
+        key = str(message_id)
+        entry = self._store.get(EDITS, key) or {
+            "channel_id": channel_id,
+            "author_id": author_id,
+            "edits": [],
+        }
+        entry["edits"].append({"at": at.isoformat(), "before": before, "after": after})
+        entry["edits"] = entry["edits"][-MAX_EDITS:]
+        self._store.set(EDITS, key, entry)
+        self._forget_old(at)
+
+    def get(self, message_id: int) -> dict | None:
+        """Return a message's recorded edits, oldest first."""
+        return self._store.get(EDITS, str(message_id))
+
+    def _forget_old(self, now: datetime) -> None:
+        """Drop messages whose last edit is past the retention period."""
+        for key, entry in self._store.items(EDITS).items():
+            if now - datetime.fromisoformat(entry["edits"][-1]["at"]) > self.retention:
+                self._store.delete(EDITS, key)
@@ -0,0 +1,105 @@
+"""Error reporting with correlation IDs."""
+
+import logging
+import traceback
+import uuid
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.embeds import DESCRIPTION_LIMIT, FIELD_VALUE_LIMIT, EmbedBuilder
+from .crash_reports import capture_exception
+
+logger = logging.getLogger(__name__)
+
+
+def new_correlation_id() -> str:
+    """Generate a short ID users can quote when reporting a failure."""
+    return uuid.uuid4().hex[:8]
+
+
+async def report_error(
+    bot: commands.Bot,
+    error: BaseException,
+    operation: str,
+    context: dict[str, str] | None = None,
+    subsystem: str = "commands",
+) -> str:
+    """Log an error and post its full context to the private errors channel.
+
+    Args:
+        bot: The bot instance.
+        error: The exception that was raised.
+        operation: Short description of what failed (e.g. "!events").
+        context: Extra details such as user, channel, or message IDs.
+        subsystem: What the error is tagged with in crash reports.
+
+    Returns:
+        The correlation ID to show to the user.
+    """
+    correlation_id = new_correlation_id()
+    context = dict(context or {})
+
+    if isinstance(error, discord.HTTPException):
+        context["Discord error"] = f"HTTP {error.status}, code {error.code}: {error.text}"
+        cf_ray = error.response.headers.get("CF-Ray") if error.response else None
+        if cf_ray:
+            context["Request ID"] = cf_ray
+
+    # Sent before logging, so the tagged report isn't dropped as a duplicate of the log's
+    capture_exception(
+        error, subsystem, context | {"Operation": operation, "Reference": correlation_id}
+    )
+    logger.error(
+        "[%s] %s failed: %s (%s)",
+        correlation_id,
+        operation,
+        error,
+        ", ".join(f"{key}={value}" for key, value in context.items()),
+        exc_info=error,
+    )
+
+    if settings.discord_errors_channel and not settings.observer_mode:
+        await _post_to_errors_channel(bot, correlation_id, error, operation, context)
+
+    return correlation_id
+
+
+async def _post_to_errors_channel(
+    bot: commands.Bot,
+    correlation_id: str,
+    error: BaseException,
+    operation: str,
+    context: dict[str, str],
+) -> None:
+    """Post the error report to the configured errors channel."""
+    guild = bot.get_guild(settings.discord_guild_id)
+    if not guild:
+        logger.error("Guild not found: %d", settings.discord_guild_id)
+        return
+
+    channel = discord.utils.get(guild.text_channels, name=settings.discord_errors_channel)
+    if not channel:
+        logger.error("Errors channel not found: %s", settings.discord_errors_channel)
+        return
+
+    stack = "".join(traceback.format_exception(error))
+    # Keep the end of the stack trace, which has the innermost frames
+    stack = stack[-(DESCRIPTION_LIMIT - 10) :]
+
+    builder = (
+        EmbedBuilder()
+        .set_title(f"Error {correlation_id}: {operation}"[:256])
+        .set_description(f"```\n{stack}\n```")
+        .set_color(discord.Color.red())
+        .set_timestamp(discord.utils.utcnow())
+    )
+    for key, value in context.items():
+        if builder.can_add_field(key, value[:FIELD_VALUE_LIMIT]):
+            builder.add_field(key, value[:FIELD_VALUE_LIMIT], inline=True)
+
+    try:
+        await channel.send(embed=builder.build())
+    except discord.HTTPException as e:
+        logger.error("Failed to post error report %s: %s", correlation_id, e)
@@ -0,0 +1,113 @@
+"""A/B experiments comparing a schedule's two announcement templates."""
+
+import logging
+from dataclasses import dataclass
+from typing import Any
+
+from ..models import Schedule
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+ANNOUNCEMENTS = "announcements"
+VARIANT_LABELS = "AB"
+
+
+def is_experiment(schedule: Schedule | None) -> bool:
+    """Check whether a schedule alternates between two announcement templates."""
+    return schedule is not None and len(schedule.announcement_templates) == 2
+
+
+@dataclass
+class VariantStats:
+    """Engagement with one announcement variant of a schedule."""
+
+    variant: int
+    announcements: int = 0
+    measured: int = 0
+    reactions: int = 0
+    rsvps: int = 0
+
+    @property
+    def label(self) -> str:
+        """Letter identifying the variant."""
+        return VARIANT_LABELS[self.variant]
+
+    @property
+    def reaction_rate(self) -> float:
+        """Average reactions per measured announcement."""
+        return self.reactions / self.measured if self.measured else 0.0
+
+    @property
+    def rsvp_rate(self) -> float:
+        """Average RSVPs per measured announcement."""
+        return self.rsvps / self.measured if self.measured else 0.0
+
+
+class AnnouncementExperiments:
+    """Tracks which template each announcement used and how members responded.
+
+    Announcements are keyed by event ID. Reactions and RSVPs are measured once,
+    when the event starts, so every variant is compared over the same window.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def next_variant(self, schedule: Schedule) -> int:
+        """Return the variant for the schedule's next announcement, alternating A and B."""
+        sent = sum(
+            1
+            for entry in self._store.items(ANNOUNCEMENTS).values()
+            if entry["schedule"] == schedule.name
+        )
+        return sent % len(VARIANT_LABELS)
+
+    def record_announcement(
+        self,
+        event_id: str,
+        schedule: Schedule,
+        variant: int,
+        channel_id: int,
+        message_id: int,
+        discord_event_id: int,
+    ) -> None:
+        """Remember which variant announced an event."""
+        self._store.set(
+            ANNOUNCEMENTS,
+            event_id,
+            {
+                "schedule": schedule.name,
+                "variant": variant,
+                "channel_id": channel_id,
+                "message_id": message_id,
+                "discord_event_id": discord_event_id,
+            },
+        )
+        logger.info("Announced %s with variant %s", event_id, VARIANT_LABELS[variant])
+
+    def get(self, event_id: str) -> dict[str, Any] | None:
+        """Return the announcement recorded for an event, if any."""
+        return self._store.get(ANNOUNCEMENTS, event_id)
+
+    def record_results(self, event_id: str, reactions: int, rsvps: int) -> None:
+        """Store the reactions and RSVPs an announcement got by the time the event started."""
+        entry = self.get(event_id)
+        if entry is None:
+            return
+        self._store.set(ANNOUNCEMENTS, event_id, entry | {"reactions": reactions, "rsvps": rsvps})
+
+    def summary(self) -> dict[str, list[VariantStats]]:
+        """Aggregate engagement per schedule and variant."""
+        summary: dict[str, list[VariantStats]] = {}
+        for entry in self._store.items(ANNOUNCEMENTS).values():
+            variants = summary.setdefault(
+                entry["schedule"], [VariantStats(i) for i in range(len(VARIANT_LABELS))]
+            )
+            stats = variants[entry["variant"]]
+            stats.announcements += 1
+            if "reactions" in entry:
+                stats.measured += 1
+                stats.reactions += entry["reactions"]
+                stats.rsvps += entry["rsvps"]
+        return summary
@@ -0,0 +1,121 @@
+"""RSS and Atom feeds members subscribe to, and which of their items are new."""
+
+import logging
+import xml.etree.ElementTree as ET
+from dataclasses import dataclass
+
+import aiohttp
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# User ID -> names of the feeds they subscribed to
+FEED_SUBSCRIPTIONS = "feed_subscriptions"
+
+# Feed name -> IDs of the items in its last fetch, so only newer ones are delivered
+FEED_ITEMS = "feed_items"
+
+ATOM = "{http://www.w3.org/2005/Atom}"
+
+
+@dataclass
+class FeedItem:
+    """An item of a feed, normalized across RSS and Atom."""
+
+    id: str
+    title: str
+    link: str = ""
+
+
+def parse_feed(text: str) -> list[FeedItem]:
+    """Read the items of an RSS 2.0 or Atom feed, newest first as feeds list them.
+
+    Raises:
+        ValueError: If the feed isn't XML.
+    """
+    try:
+        root = ET.fromstring(text)
+    except ET.ParseError as e:
+        raise ValueError(f"Not an RSS or Atom feed: {e}") from None
+
+    items = []
+    for item in root.iter("item"):
+        link = item.findtext("link", "").strip()
+        guid = item.findtext("guid", "").strip() or link
+        if guid:
+            items.append(FeedItem(guid, item.findtext("title", "").strip(), link))
+    for entry in root.iter(f"{ATOM}entry"):
+        link = entry.find(f"{ATOM}link")
+        href = link.get("href", "") if link is not None else ""
+        entry_id = entry.findtext(f"{ATOM}id", "").strip() or href
+        if entry_id:
+            items.append(FeedItem(entry_id, entry.findtext(f"{ATOM}title", "").strip(), href))
+    return items
+
+
+async def fetch_feed(url: str) -> list[FeedItem]:
+    """Fetch a feed's items.
+
+    Raises:
+        aiohttp.ClientError: If the feed can't be fetched.
+        ValueError: If it isn't a feed.
+    """
+    timeout = aiohttp.ClientTimeout(total=10)
+    async with aiohttp.ClientSession(timeout=timeout) as session:
+        async with session.get(url) as response:
+            response.raise_for_status()
+            return parse_feed(await response.text())
+
+
+class FeedSubscriptions:
+    """Keeps each member's feed subscriptions, and finds the items they haven't seen.
+
+    A feed's items are all taken as seen the first time it's fetched for
+    its subscribers, so they don't get its whole history, nor what was
+    published while nobody was subscribed.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def subscriptions(self, user_id: int) -> list[str]:
+        """Return the feeds a member subscribed to."""
+        return self._store.get(FEED_SUBSCRIPTIONS, str(user_id), [])
+
+    def subscribe(self, user_id: int, feed: str) -> bool:
+        """Subscribe a member to a feed, returning False if they already were."""
+        feeds = self.subscriptions(user_id)
+        if feed in feeds:
+            return False
+        if not self.subscribers(feed):
+            self._store.delete(FEED_ITEMS, feed)
+        self._store.set(FEED_SUBSCRIPTIONS, str(user_id), [*feeds, feed])
+        return True
+
+    def unsubscribe(self, user_id: int, feed: str) -> bool:
+        """Unsubscribe a member from a feed, returning False if they weren't subscribed."""
+        if feed not in self.subscriptions(user_id):
+            return False
+        feeds = [name for name in self.subscriptions(user_id) if name != feed]
+        if feeds:
+            self._store.set(FEED_SUBSCRIPTIONS, str(user_id), feeds)
+        else:
+            self._store.delete(FEED_SUBSCRIPTIONS, str(user_id))
+        return True
+
+    def subscribers(self, feed: str) -> list[int]:
+        """Return the members subscribed to a feed."""
+        return [
+            int(user_id)
+            for user_id, feeds in self._store.items(FEED_SUBSCRIPTIONS).items()
+            if feed in feeds
+        ]
+
+    def new_items(self, feed: str, items: list[FeedItem]) -> list[FeedItem]:
+        """Record a feed's latest items, returning those not seen before, oldest first."""
+        seen = self._store.get(FEED_ITEMS, feed)
+        self._store.set(FEED_ITEMS, feed, [item.id for item in items])
+        if seen is None:
+            return []
+        return [item for item in reversed(items) if item.id not in seen]
@@ -0,0 +1,357 @@
+"""Global REST rate limit headroom, adaptive throttling, and a circuit breaker."""
+
+import asyncio
+import logging
+from collections import Counter, deque
+from collections.abc import Callable
+from contextvars import ContextVar
+from dataclasses import dataclass
+from datetime import datetime, timedelta
+from enum import IntEnum
+from types import SimpleNamespace
+from typing import Any
+from zoneinfo import ZoneInfo
+
+import aiohttp
+import discord
+
+logger = logging.getLogger(__name__)
+
+# Discord's global limit, and the invalid requests (401, 403, 429) that get an IP banned
+GLOBAL_LIMIT = 50
+GLOBAL_WINDOW = timedelta(seconds=1)
+INVALID_LIMIT = 10000
+INVALID_WINDOW = timedelta(minutes=10)
+INVALID_STATUSES = {401, 403, 429}
+
+# Longest a throttled request waits before going ahead anyway
+MAX_DELAY = timedelta(seconds=60)
+
+
+class Priority(IntEnum):
+    """How important a subsystem's requests are when headroom runs low."""
+
+    BACKGROUND = 0  # Renames, exports: throttled from half usage
+    NORMAL = 1  # Announcements and commands: throttled near the limit
+    CRITICAL = 2  # Never throttled
+
+
+# Usage (0-1) of either limit above which requests of each priority are delayed
+THROTTLE_AT = {Priority.BACKGROUND: 0.5, Priority.NORMAL: 0.8}
+
+# Circuit breaker states: requests flow, non-critical ones are held back, or
+# the cooldown ended and the next request's result decides
+CLOSED = "closed"
+OPEN = "open"
+HALF_OPEN = "half-open"
+
+_subsystem: ContextVar[tuple[str, Priority]] = ContextVar(
+    "subsystem", default=("other", Priority.NORMAL)
+)
+
+
+def _log_fields(route: discord.http.Route) -> dict:
+    """Return the structured log fields of a request: its endpoint and guild."""
+    return {
+        "endpoint": f"{route.method} {route.path}",
+        "guild_id": getattr(route, "guild_id", None),
+    }
+
+
+class CircuitOpenError(discord.HTTPException):
+    """Raised instead of sending a background request while the circuit is open.
+
+    It's an `HTTPException` with status 503, so callers handle a shed request
+    like any other failed one.
+    """
+
+    def __init__(self, route: discord.http.Route) -> None:
+        response = SimpleNamespace(status=503, reason="Circuit open")
+        super().__init__(response, f"Not sent, circuit open: {route.method} {route.path}")
+
+
+class CircuitBreaker:
+    """Holds back non-critical requests while Discord's API keeps failing.
+
+    After `threshold` failures in a row (server errors, 429s, or network
+    errors), the circuit opens for `cooldown`: background requests, such as
+    channel renames and digest edits, are dropped, and normal ones wait for
+    the cooldown to end. Critical requests are always sent. Once the cooldown
+    ends, the next request's result closes the circuit or opens it again.
+
+    `on_change` is called with the new state whenever it changes.
+    """
+
+    def __init__(
+        self,
+        threshold: int,
+        cooldown: timedelta,
+        on_change: Callable[[str], None] | None = None,
+    ) -> None:
+        self.threshold = threshold
+        self.cooldown = cooldown
+        self.on_change = on_change
+        self.state = CLOSED
+        self.failures = 0
+        self.opened_at: datetime | None = None
+        self.opened_total = 0
+
+    def allows(self, now: datetime, priority: Priority) -> bool:
+        """Check whether a request of `priority` can be sent now."""
+        if self.state == OPEN and now - self.opened_at >= self.cooldown:
+            self._set(HALF_OPEN)
+        return self.state != OPEN or priority == Priority.CRITICAL
+
+    def retry_in(self, now: datetime) -> timedelta:
+        """Return how long until the cooldown of an open circuit ends."""
+        if self.state != OPEN:
+            return timedelta(0)
+        return max(timedelta(0), self.opened_at + self.cooldown - now)
+
+    def record_success(self) -> None:
+        """Close the circuit after a request went through."""
+        self.failures = 0
+        if self.state != CLOSED:
+            self._set(CLOSED)
+
+    def record_failure(self, now: datetime) -> None:
+        """Count a failed request, opening the circuit at the threshold."""
+        self.failures += 1
+        if self.state == OPEN:
+            # A critical request failed during the cooldown, so it starts over
+            self.opened_at = now
+        elif self.state == HALF_OPEN or (self.threshold and self.failures >= self.threshold):
+            self.opened_at = now
+            self.opened_total += 1
+            self._set(OPEN)
+
+    def _set(self, state: str) -> None:
+        """Move to a state, and tell `on_change`."""
+        logger.warning("Discord API circuit is now %s (%d failures)", state, self.failures)
+        self.state = state
+        if self.on_change:
+            self.on_change(state)
+
+
+@dataclass
+class Headroom:
+    """Current usage of the global and invalid request limits."""
+
+    requests_per_second: int
+    invalid_requests: int  # In the last 10 minutes
+
+    @property
+    def global_usage(self) -> float:
+        """Fraction of the global limit used in the last second."""
+        return self.requests_per_second / GLOBAL_LIMIT
+
+    @property
+    def invalid_usage(self) -> float:
+        """Fraction of the invalid request ban threshold used."""
+        return self.invalid_requests / INVALID_LIMIT
+
+
+class RateGovernor:
+    """Tracks the bot's aggregate REST usage and slows low-priority work.
+
+    discord.py already follows per-route buckets; this watches the limits that
+    span every route. Subsystems tag their requests with `tag()`, and as
+    usage of either limit rises, background work waits first, then normal
+    work. Interaction responses go through discord.py's webhook adapter rather
+    than the HTTP client, so they're never delayed.
+
+    429s that discord.py retries internally never reach the governor, so the
+    invalid count is a lower bound.
+
+    With a `breaker`, requests are also shed while Discord's API is failing.
+    """
+
+    def __init__(self, breaker: CircuitBreaker | None = None) -> None:
+        self.breaker = breaker
+        self._requests: deque[datetime] = deque()
+        self._invalid: deque[datetime] = deque()
+        self.requests = Counter[str]()  # Subsystem -> requests since startup
+        self.throttled = Counter[str]()  # Subsystem -> delayed requests since startup
+        self.shed = Counter[str]()  # Subsystem -> requests dropped by the breaker
+        self.invalid_total = 0
+
+    def tag(self, name: str, priority: Priority = Priority.NORMAL) -> None:
+        """Attribute the current task's REST requests to a subsystem and priority.
+
+        Each asyncio task has its own context, so tagging a loop or command only
+        affects the requests that task makes.
+        """
+        _subsystem.set((name, priority))
+
+    def record_request(self, now: datetime, subsystem: str = "other") -> None:
+        """Count a request sent to Discord."""
+        self._requests.append(now)
+        self.requests[subsystem] += 1
+
+    def record_invalid(self, now: datetime) -> None:
+        """Count a request that counts toward the invalid request ban."""
+        self._invalid.append(now)
+        self.invalid_total += 1
+
+    def headroom(self, now: datetime) -> Headroom:
+        """Return current usage of both limits."""
+        self._expire(now)
+        return Headroom(len(self._requests), len(self._invalid))
+
+    def delay(self, now: datetime, priority: Priority) -> timedelta:
+        """Return how long a request of `priority` should wait before being sent."""
+        if priority not in THROTTLE_AT:
+            return timedelta(0)
+
+        headroom = self.headroom(now)
+        threshold = THROTTLE_AT[priority]
+        delay = timedelta(0)
+        if headroom.global_usage >= threshold:
+            # Wait for enough of the last second's requests to age out
+            excess = len(self._requests) - int(GLOBAL_LIMIT * threshold)
+            delay = max(delay, self._requests[excess] + GLOBAL_WINDOW - now)
+        if headroom.invalid_usage >= threshold:
+            delay = max(delay, MAX_DELAY)
+        return min(delay, MAX_DELAY)
+
+    def install(self, http: discord.http.HTTPClient) -> None:
+        """Route every request of the bot's HTTP client through the governor."""
+        request = http.request
+
+        async def governed_request(route: discord.http.Route, **kwargs: Any) -> Any:
+            name, priority = _subsystem.get()
+            await self._wait_for_circuit(route, name, priority)
+            delay = self.delay(datetime.now(ZoneInfo("UTC")), priority)
+            if delay > timedelta(0):
+                self.throttled[name] += 1
+                logger.info(
+                    "Throttling %s %s from %s by %s",
+                    route.method,
+                    route.path,
+                    name,
+                    delay,
+                    extra=_log_fields(route),
+                )
+                await asyncio.sleep(delay.total_seconds())
+
+            self.record_request(datetime.now(ZoneInfo("UTC")), name)
+            try:
+                response = await request(route, **kwargs)
+            except discord.HTTPException as e:
+                logger.debug(
+                    "%s %s from %s failed: %s",
+                    route.method,
+                    route.path,
+                    name,
+                    e,
+                    extra=_log_fields(route) | {"status": e.status},
+                )
+                if e.status in INVALID_STATUSES:
+                    self.record_invalid(datetime.now(ZoneInfo("UTC")))
+                if self.breaker and (e.status == 429 or e.status >= 500):
+                    self.breaker.record_failure(datetime.now(ZoneInfo("UTC")))
+                elif self.breaker:
+                    # Discord answered, it just refused this request
+                    self.breaker.record_success()
+                raise
+            except (aiohttp.ClientError, OSError, TimeoutError):
+                if self.breaker:
+                    self.breaker.record_failure(datetime.now(ZoneInfo("UTC")))
+                raise
+            if self.breaker:
+                self.breaker.record_success()
+            return response
+
+        http.request = governed_request
+
+    async def _wait_for_circuit(
+        self, route: discord.http.Route, name: str, priority: Priority
+    ) -> None:
+        """Drop a background request while the circuit is open, or hold a normal one back.
+
+        Raises:
+            CircuitOpenError: If the request is dropped.
+        """
+        now = datetime.now(ZoneInfo("UTC"))
+        if not self.breaker or self.breaker.allows(now, priority):
+            return
+
+        if priority == Priority.BACKGROUND:
+            self.shed[name] += 1
+            logger.info(
+                "Dropping %s %s from %s, circuit open",
+                route.method,
+                route.path,
+                name,
+                extra=_log_fields(route),
+            )
+            raise CircuitOpenError(route)
+
+        wait = self.breaker.retry_in(now)
+        self.throttled[name] += 1
+        logger.info(
+            "Holding %s %s from %s for %s, circuit open",
+            route.method,
+            route.path,
+            name,
+            wait,
+            extra=_log_fields(route),
+        )
+        await asyncio.sleep(wait.total_seconds())
+
+    def metrics(self) -> str:
+        """Render current usage in the Prometheus text format."""
+        headroom = self.headroom(datetime.now(ZoneInfo("UTC")))
+        lines = [
+            "# HELP cnayp_rest_requests_per_second REST requests sent in the last second.",
+            "# TYPE cnayp_rest_requests_per_second gauge",
+            f"cnayp_rest_requests_per_second {headroom.requests_per_second}",
+            "# HELP cnayp_rest_global_headroom Fraction of the global rate limit left.",
+            "# TYPE cnayp_rest_global_headroom gauge",
+            f"cnayp_rest_global_headroom {max(0.0, 1 - headroom.global_usage):.3f}",
+            "# HELP cnayp_rest_invalid_requests Invalid requests in the last 10 minutes.",
+            "# TYPE cnayp_rest_invalid_requests gauge",
+            f"cnayp_rest_invalid_requests {headroom.invalid_requests}",
+            "# HELP cnayp_rest_invalid_headroom Fraction of the invalid request limit left.",
+            "# TYPE cnayp_rest_invalid_headroom gauge",
+            f"cnayp_rest_invalid_headroom {max(0.0, 1 - headroom.invalid_usage):.3f}",
+            "# HELP cnayp_rest_requests_total REST requests sent, by subsystem.",
+            "# TYPE cnayp_rest_requests_total counter",
+        ]
+        lines += [
+            f'cnayp_rest_requests_total{{subsystem="{name}"}} {count}'
+            for name, count in sorted(self.requests.items())
+        ]
+        lines += [
+            "# HELP cnayp_rest_throttled_total Requests delayed by the governor, by subsystem.",
+            "# TYPE cnayp_rest_throttled_total counter",
+        ]
+        lines += [
+            f'cnayp_rest_throttled_total{{subsystem="{name}"}} {count}'
+            for name, count in sorted(self.throttled.items())
+        ]
+        if not self.breaker:
+            return "\n".join(lines) + "\n"
+
+        lines += [
+            "# HELP cnayp_rest_circuit_open Whether the Discord API circuit is open (1) or not.",
+            "# TYPE cnayp_rest_circuit_open gauge",
+            f"cnayp_rest_circuit_open {int(self.breaker.state == OPEN)}",
+            "# HELP cnayp_rest_circuit_opened_total Times the Discord API circuit opened.",
+            "# TYPE cnayp_rest_circuit_opened_total counter",
+            f"cnayp_rest_circuit_opened_total {self.breaker.opened_total}",
+            "# HELP cnayp_rest_shed_total Requests dropped while the circuit was open.",
+            "# TYPE cnayp_rest_shed_total counter",
+        ]
+        lines += [
+            f'cnayp_rest_shed_total{{subsystem="{name}"}} {count}'
+            for name, count in sorted(self.shed.items())
+        ]
+        return "\n".join(lines) + "\n"
+
+    def _expire(self, now: datetime) -> None:
+        """Drop requests that fell out of their windows."""
+        while self._requests and now - self._requests[0] >= GLOBAL_WINDOW:
+            self._requests.popleft()
+        while self._invalid and now - self._invalid[0] >= INVALID_WINDOW:
+            self._invalid.popleft()
@@ -0,0 +1,98 @@
+"""Guild data kept current by gateway events, with REST as the fallback."""
+
+import logging
+from collections.abc import Iterable
+from datetime import datetime, timedelta
+
+import discord
+
+logger = logging.getLogger(__name__)
+
+# How long a guild's channel names are trusted before they're read again, in
+# case a gateway event was missed
+CHANNEL_CACHE_TTL = timedelta(minutes=10)
+
+
+class ChannelDirectory:
+    """Resolves channel names to IDs, per guild.
+
+    Names are read from the guild's channels and threads, then kept current
+    by the gateway's channel create, update, and delete events, so a renamed
+    or deleted channel stops resolving right away. Each guild's names are
+    read again after `ttl`, or when a name isn't among them. A name that's
+    still not found is looked up over REST, at most once per `ttl` per guild,
+    so a misconfigured name doesn't fetch the channel list on every lookup.
+    """
+
+    def __init__(self, ttl: timedelta = CHANNEL_CACHE_TTL) -> None:
+        self.ttl = ttl
+        self._names: dict[int, dict[str, int]] = {}  # guild_id -> {name: channel_id}
+        self._read_at: dict[int, datetime] = {}
+        self._fetched_at: dict[int, datetime] = {}
+
+    async def resolve(self, guild: discord.Guild, name: str, now: datetime) -> int | None:
+        """Return the ID of the channel or thread with a name, or None if there's none."""
+        read_at = self._read_at.get(guild.id)
+        names = self._names.get(guild.id, {})
+        if read_at is None or now - read_at >= self.ttl or name not in names:
+            # Threads too, so private events can be announced in a private thread
+            self._read(guild.id, [*guild.channels, *guild.threads], now)
+        channel_id = self._names[guild.id].get(name)
+        if channel_id is not None:
+            return channel_id
+
+        fetched_at = self._fetched_at.get(guild.id)
+        if fetched_at is not None and now - fetched_at < self.ttl:
+            return None
+        self._fetched_at[guild.id] = now
+        try:
+            channels = await guild.fetch_channels()
+        except discord.HTTPException as e:
+            logger.warning("Failed to fetch the channels of guild %d: %s", guild.id, e)
+            return None
+        self._read(guild.id, [*channels, *guild.threads], now)
+        return self._names[guild.id].get(name)
+
+    def update(self, channel: discord.abc.GuildChannel | discord.Thread) -> None:
+        """Follow a channel that was created or renamed, dropping its old name."""
+        names = self._names.get(channel.guild.id)
+        if names is None:
+            return
+        _drop(names, channel.id)
+        names[channel.name] = channel.id
+
+    def remove(self, channel: discord.abc.GuildChannel | discord.Thread) -> None:
+        """Forget a deleted channel."""
+        names = self._names.get(channel.guild.id)
+        if names is not None:
+            _drop(names, channel.id)
+
+    def _read(
+        self,
+        guild_id: int,
+        channels: Iterable[discord.abc.GuildChannel | discord.Thread],
+        now: datetime,
+    ) -> None:
+        """Replace a guild's names with those of `channels`."""
+        self._names[guild_id] = {channel.name: channel.id for channel in channels}
+        self._read_at[guild_id] = now
+
+
+def _drop(names: dict[str, int], channel_id: int) -> None:
+    """Remove every name pointing at a channel."""
+    for name in [name for name, known_id in names.items() if known_id == channel_id]:
+        del names[name]
+
+
+async def guild_members(guild: discord.Guild) -> list[discord.Member]:
+    """Return every member of a guild, paging through REST if the member cache is incomplete.
+
+    The gateway sends every member once the guild is chunked at startup; until
+    then, or if chunking failed, members are fetched 1000 per request.
+    """
+    if guild.chunked:
+        return list(guild.members)
+
+    members = [member async for member in guild.fetch_members(limit=None)]
+    logger.info("Fetched %d members of guild %d over REST", len(members), guild.id)
+    return members
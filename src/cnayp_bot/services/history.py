@@ -0,0 +1,195 @@
+"""History of event occurrences, their RSVPs, and who attended."""
+
+import csv
+import io
+import logging
+from datetime import datetime, timedelta
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+HISTORY = "event_history"
+
+# Occurrences older than this are dropped, keeping a year of reports and then some
+RETENTION = timedelta(days=400)
+
+COLUMNS = [
+    "event_id",
+    "name",
+    "start",
+    "end",
+    "duration_minutes",
+    "interested",
+    "capacity",
+    "rsvp_going",
+    "rsvp_waitlist",
+    "attendees",
+]
+
+
+class EventHistory:
+    """Records each occurrence when it starts, and members who join its voice channel.
+
+    An attendee is anyone in the event's voice channel at some point between
+    its start and end. Each of their visits is kept as join and leave times,
+    so organizers can see how long people stayed. Interest and RSVPs are
+    counted when the event starts.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def start(
+        self,
+        event_id: str,
+        name: str,
+        start: datetime,
+        end: datetime,
+        voice_channel_id: int,
+        *,
+        interested: int,
+        rsvps: dict | None,
+        present: list[int],
+    ) -> None:
+        """Record an occurrence that's starting, with the members already in its channel."""
+        entry = self._store.get(HISTORY, event_id)
+        if entry is None:
+            self._forget_old(start)
+            entry = {
+                "name": name,
+                "start": start.isoformat(),
+                "end": end.isoformat(),
+                "voice_channel_id": voice_channel_id,
+                "interested": interested,
+                "capacity": rsvps["capacity"] if rsvps else None,
+                "going": len(rsvps["going"]) if rsvps else None,
+                "waitlist": len(rsvps["waitlist"]) if rsvps else None,
+                "attendees": [],
+                "visits": {},
+            }
+        entry["attendees"] = sorted(set(entry["attendees"]) | set(present))
+        for member_id in present:
+            _join(entry, member_id, start)
+        self._store.set(HISTORY, event_id, entry)
+
+    def get(self, event_id: str) -> dict | None:
+        """Return an occurrence, or None if it wasn't recorded."""
+        return self._store.get(HISTORY, event_id)
+
+    def find(self, query: str) -> tuple[str, dict] | None:
+        """Return the latest occurrence with this event ID, or whose name contains `query`."""
+        query = query.strip().lower()
+        matches = [
+            (event_id, entry)
+            for event_id, entry in self._store.items(HISTORY).items()
+            if event_id.lower() == query or query in entry["name"].lower()
+        ]
+        return max(matches, key=lambda item: item[1]["start"], default=None)
+
+    def attend(self, voice_channel_id: int, member_id: int, now: datetime) -> None:
+        """Count a member who joined a voice channel toward the events running in it."""
+        for event_id, entry in self._running(voice_channel_id, now):
+            if member_id not in entry["attendees"]:
+                entry["attendees"].append(member_id)
+            _join(entry, member_id, now)
+            self._store.set(HISTORY, event_id, entry)
+
+    def leave(self, voice_channel_id: int, member_id: int, now: datetime) -> None:
+        """Record a member leaving a voice channel during the events running in it."""
+        for event_id, entry in self._running(voice_channel_id, now):
+            visits = entry.get("visits", {}).get(str(member_id), [])
+            if visits and visits[-1][1] is None:
+                visits[-1][1] = now.isoformat()
+                self._store.set(HISTORY, event_id, entry)
+
+    def _running(self, voice_channel_id: int, now: datetime) -> list[tuple[str, dict]]:
+        """Return the occurrences in a voice channel that are on at `now`."""
+        return [
+            (event_id, entry)
+            for event_id, entry in self._store.items(HISTORY).items()
+            if entry["voice_channel_id"] == voice_channel_id
+            and datetime.fromisoformat(entry["start"]) <= now
+            and now < datetime.fromisoformat(entry["end"])
+        ]
+
+    def occurrences(self, since: datetime) -> list[tuple[str, dict]]:
+        """Return the occurrences that started since `since`, oldest first."""
+        entries = [
+            (event_id, entry)
+            for event_id, entry in self._store.items(HISTORY).items()
+            if datetime.fromisoformat(entry["start"]) >= since
+        ]
+        return sorted(entries, key=lambda item: item[1]["start"])
+
+    def _forget_old(self, now: datetime) -> None:
+        """Drop occurrences past the retention period."""
+        for event_id, entry in self._store.items(HISTORY).items():
+            if now - datetime.fromisoformat(entry["start"]) > RETENTION:
+                self._store.delete(HISTORY, event_id)
+
+
+def _join(entry: dict, member_id: int, now: datetime) -> None:
+    """Start a visit, unless the member's last one is still open."""
+    # Occurrences recorded before visits were tracked don't have them
+    visits = entry.setdefault("visits", {}).setdefault(str(member_id), [])
+    if not visits or visits[-1][1] is not None:
+        visits.append([now.isoformat(), None])
+
+
+def minutes_attended(entry: dict) -> dict[int, int]:
+    """Return how many minutes each attendee spent in the call, within the event's time.
+
+    Visits still open when the event ended count until the end.
+    """
+    start = datetime.fromisoformat(entry["start"])
+    end = datetime.fromisoformat(entry["end"])
+    minutes = {}
+    for member_id, visits in entry.get("visits", {}).items():
+        seconds = sum(
+            max(
+                0,
+                (
+                    min(end, datetime.fromisoformat(left) if left else end)
+                    - max(start, datetime.fromisoformat(joined))
+                ).total_seconds(),
+            )
+            for joined, left in visits
+        )
+        minutes[int(member_id)] = int(seconds // 60)
+    return minutes
+
+
+def attendance_rows(occurrences: list[tuple[str, dict]]) -> list[list]:
+    """Turn occurrences into a table with a header row, for CSV files and sheets."""
+    rows: list[list] = [COLUMNS]
+    for event_id, entry in occurrences:
+        start = datetime.fromisoformat(entry["start"])
+        end = datetime.fromisoformat(entry["end"])
+        rows.append(
+            [
+                event_id,
+                entry["name"],
+                start.isoformat(),
+                end.isoformat(),
+                int((end - start).total_seconds() // 60),
+                entry["interested"],
+                _blank(entry["capacity"]),
+                _blank(entry["going"]),
+                _blank(entry["waitlist"]),
+                len(entry["attendees"]),
+            ]
+        )
+    return rows
+
+
+def _blank(value: int | None) -> int | str:
+    """Show counts that don't apply, such as RSVPs of uncapped events, as empty cells."""
+    return "" if value is None else value
+
+
+def render_csv(rows: list[list]) -> str:
+    """Render a table as CSV."""
+    output = io.StringIO()
+    csv.writer(output).writerows(rows)
+    return output.getvalue()
@@ -0,0 +1,62 @@
+"""Holidays read from an iCal calendar, on which no schedule runs."""
+
+import logging
+from datetime import date, timedelta
+
+import aiohttp
+
+logger = logging.getLogger(__name__)
+
+# Longest holiday taken from one calendar event, so a mistyped end date
+# doesn't cancel months of sessions
+MAX_HOLIDAY_DAYS = 31
+
+
+def parse_holidays(ical: str) -> set[date]:
+    """Return the days covered by the events of an iCal calendar.
+
+    All-day events cover every day up to their (exclusive) end; timed events
+    cover the day they start. Repeating events only count their first day, as
+    holiday calendars list each year's dates.
+    """
+    # Long lines are folded onto lines starting with a space or a tab
+    lines = ical.replace("\r\n", "\n").replace("\n ", "").replace("\n\t", "").split("\n")
+
+    holidays = set()
+    start = end = None
+    for line in lines:
+        name, _, value = line.partition(":")
+        key = name.split(";")[0].upper()
+        if key == "BEGIN" and value.upper() == "VEVENT":
+            start = end = None
+        elif key in ("DTSTART", "DTEND"):
+            try:
+                day = date(int(value[:4]), int(value[4:6]), int(value[6:8]))
+            except ValueError:
+                logger.warning("Skipping holiday with an invalid date: %s", line)
+                continue
+            # Only all-day events end on the day after their last one
+            if key == "DTSTART":
+                start = day
+            elif "T" not in value:
+                end = day
+        elif key == "END" and value.upper() == "VEVENT" and start:
+            days = (end - start).days if end and end > start else 1
+            if days > MAX_HOLIDAY_DAYS:
+                logger.warning("Holiday on %s is %d days long, using its first day", start, days)
+                days = 1
+            holidays.update(start + timedelta(days=offset) for offset in range(days))
+    return holidays
+
+
+async def fetch_holidays(url: str) -> set[date]:
+    """Download an iCal calendar of holidays and return its days.
+
+    Raises:
+        aiohttp.ClientError: If the download fails.
+    """
+    timeout = aiohttp.ClientTimeout(total=30)
+    async with aiohttp.ClientSession(timeout=timeout) as session:
+        async with session.get(url) as response:
+            response.raise_for_status()
+            return parse_holidays(await response.text(encoding="utf-8"))
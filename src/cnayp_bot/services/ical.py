@@ -0,0 +1,187 @@
+"""iCal feed of the schedules, for members subscribing from Google or Apple Calendar."""
+
+from collections.abc import Iterable, Set
+from datetime import date, datetime, time, timedelta
+from zoneinfo import ZoneInfo
+
+from ..models import Schedule
+from .schedules import WEEKDAYS, one_off_end, schedule_occurrences, schedule_slug
+
+# Days of cron schedules' occurrences listed one by one, since expressions
+# like "TUE#1" or a day of the month or a weekday have no exact RRULE
+CRON_DAYS = 90
+# Years of daylight saving time changes written for each timezone
+TIMEZONE_YEARS = 2
+
+UID_DOMAIN = "cnayp-bot"
+# Longest line in octets; longer ones are folded
+LINE_LIMIT = 75
+
+_BYDAY = ["MO", "TU", "WE", "TH", "FR", "SA", "SU"]
+
+
+def schedules_ical(
+    schedules: Iterable[Schedule], holidays: Set[date], now: datetime, name: str
+) -> str:
+    """Return an iCal calendar of the events of the enabled, public schedules.
+
+    Weekly schedules are one repeating event each, leaving out their excluded
+    dates and holidays; one-off schedules are their event, and cron schedules
+    their occurrences in the next `CRON_DAYS` days.
+    """
+    stamp = f"DTSTAMP:{now.astimezone(ZoneInfo('UTC')):%Y%m%dT%H%M%SZ}"
+    events: list[str] = []
+    timezones: set[str] = set()
+    for schedule in schedules:
+        if not schedule.enabled or schedule.visibility == "private":
+            continue
+
+        if schedule.days:
+            lines = _weekly_event(schedule, holidays, now, stamp)
+        else:
+            end = one_off_end(schedule) or now + timedelta(days=CRON_DAYS)
+            lines = [
+                line
+                for event in schedule_occurrences(schedule, now, end, holidays)
+                for line in _event(schedule, f"{event.id}@{UID_DOMAIN}", event.start_time, stamp)
+            ]
+        if lines:
+            events += lines
+            timezones.add(schedule.timezone)
+
+    lines = [
+        "BEGIN:VCALENDAR",
+        "VERSION:2.0",
+        "PRODID:-//CNAYP//Events bot//EN",
+        "CALSCALE:GREGORIAN",
+        "METHOD:PUBLISH",
+        f"X-WR-CALNAME:{_escape(name)}",
+        # Asks subscribers to refresh hourly; Google Calendar refreshes on its own schedule
+        "X-PUBLISHED-TTL:PT1H",
+        *(line for timezone in sorted(timezones) for line in _timezone(timezone, now)),
+        *events,
+        "END:VCALENDAR",
+    ]
+    return "".join(f"{_fold(line)}\r\n" for line in lines)
+
+
+def _weekly_event(
+    schedule: Schedule, holidays: Set[date], now: datetime, stamp: str
+) -> list[str]:
+    """Return a weekly schedule's repeating event, starting at its next occurrence."""
+    # Schedules skipping weeks may not run this week, or may not have started yet
+    since = now
+    if schedule.starting:
+        since = max(now, datetime.combine(schedule.starting, time(), ZoneInfo(schedule.timezone)))
+    occurrences = schedule_occurrences(
+        schedule, since, since + timedelta(weeks=schedule.every_weeks)
+    )
+    if not occurrences:
+        return []
+
+    tz = ZoneInfo(schedule.timezone)
+    first = occurrences[0].start_time.astimezone(tz)
+    days = {day.lower() for day in schedule.days}
+    byday = ",".join(_BYDAY[index] for index, day in enumerate(WEEKDAYS) if day in days)
+    skipped = sorted(
+        day
+        for day in set(schedule.exclude_dates) | set(holidays)
+        if day >= first.date() and WEEKDAYS[day.weekday()] in days
+    )
+    interval = f";INTERVAL={schedule.every_weeks}" if schedule.every_weeks > 1 else ""
+    rules = [f"RRULE:FREQ=WEEKLY{interval};BYDAY={byday}"] + [
+        f"EXDATE;TZID={schedule.timezone}:{day:%Y%m%d}T{first:%H%M%S}" for day in skipped
+    ]
+    uid = f"schedule-{schedule_slug(schedule)}@{UID_DOMAIN}"
+    return _event(schedule, uid, first, stamp, rules)
+
+
+def _event(
+    schedule: Schedule, uid: str, start: datetime, stamp: str, rules: list[str] | None = None
+) -> list[str]:
+    """Return the lines of an event of a schedule, in the schedule's timezone."""
+    local = start.astimezone(ZoneInfo(schedule.timezone))
+    return [
+        "BEGIN:VEVENT",
+        f"UID:{uid}",
+        stamp,
+        f"DTSTART;TZID={schedule.timezone}:{local:%Y%m%dT%H%M%S}",
+        f"DURATION:PT{schedule.duration_minutes}M",
+        *(rules or []),
+        f"SUMMARY:{_escape(schedule.name)}",
+        f"DESCRIPTION:{_escape(schedule.description)}",
+        f"LOCATION:{_escape(f'Discord: {schedule.voice_channel}')}",
+        "END:VEVENT",
+    ]
+
+
+def _timezone(name: str, now: datetime) -> list[str]:
+    """Describe a timezone's UTC offsets for `TIMEZONE_YEARS`, as events' TZIDs need.
+
+    Each daylight saving time change is its own observance, found day by day
+    and then narrowed down to the minute.
+    """
+    tz = ZoneInfo(name)
+    # From a day back, covering occurrences already in progress
+    day = now.astimezone(ZoneInfo("UTC")).replace(second=0, microsecond=0) - timedelta(days=1)
+    end = day + timedelta(days=366 * TIMEZONE_YEARS)
+    offset = day.astimezone(tz).utcoffset()
+    lines = ["BEGIN:VTIMEZONE", f"TZID:{name}", *_observance(tz, day, offset, offset)]
+    while day < end:
+        after = day + timedelta(days=1)
+        if after.astimezone(tz).utcoffset() != offset:
+            low, high = 0, 24 * 60
+            while high - low > 1:
+                middle = (low + high) // 2
+                if (day + timedelta(minutes=middle)).astimezone(tz).utcoffset() == offset:
+                    low = middle
+                else:
+                    high = middle
+            change = day + timedelta(minutes=high)
+            lines += _observance(tz, change, offset, change.astimezone(tz).utcoffset())
+            offset = change.astimezone(tz).utcoffset()
+        day = after
+    return [*lines, "END:VTIMEZONE"]
+
+
+def _observance(
+    tz: ZoneInfo, moment: datetime, before: timedelta, after: timedelta
+) -> list[str]:
+    """Return the observance of the UTC offset a timezone changes to at `moment`."""
+    kind = "DAYLIGHT" if moment.astimezone(tz).dst() else "STANDARD"
+    # Observances start at the local time before the change
+    local = (moment + before).replace(tzinfo=None)
+    return [
+        f"BEGIN:{kind}",
+        f"DTSTART:{local:%Y%m%dT%H%M%S}",
+        f"TZOFFSETFROM:{_offset(before)}",
+        f"TZOFFSETTO:{_offset(after)}",
+        f"TZNAME:{moment.astimezone(tz).tzname()}",
+        f"END:{kind}",
+    ]
+
+
+def _offset(offset: timedelta) -> str:
+    """Format a UTC offset as iCal does, e.g. -0500."""
+    minutes = int(offset.total_seconds()) // 60
+    sign = "-" if minutes < 0 else "+"
+    return f"{sign}{abs(minutes) // 60:02d}{abs(minutes) % 60:02d}"
+
+
+def _escape(text: str) -> str:
+    """Escape text for an iCal property value."""
+    for char in ("\\", ";", ","):
+        text = text.replace(char, f"\\{char}")
+    return text.replace("\r\n", "\\n").replace("\n", "\\n")
+
+
+def _fold(line: str) -> str:
+    """Split a line longer than `LINE_LIMIT` octets onto lines starting with a space."""
+    parts = [""]
+    for char in line:
+        # Continuation lines start with a space, which counts towards their limit
+        limit = LINE_LIMIT if len(parts) == 1 else LINE_LIMIT - 1
+        if len((parts[-1] + char).encode()) > limit:
+            parts.append("")
+        parts[-1] += char
+    return "\r\n ".join(parts)
@@ -0,0 +1,81 @@
+"""Work the bot lets finish before shutting down."""
+
+import asyncio
+import functools
+import logging
+from collections import Counter
+from collections.abc import AsyncIterator, Awaitable, Callable
+from contextlib import asynccontextmanager
+from typing import Any
+
+logger = logging.getLogger(__name__)
+
+
+class InFlightWork:
+    """Counts the work running, such as a pass of the reminder loop, like a wait group.
+
+    On shutdown, `drain()` stops new work from starting and waits for the
+    running work to finish, so a pass isn't cut off between creating a
+    Discord event and recording it, or between sending a reminder and
+    marking it sent. Work still running after the timeout is abandoned.
+    """
+
+    def __init__(self) -> None:
+        self.draining = False
+        self._running: Counter[str] = Counter()
+        self._idle = asyncio.Event()
+        self._idle.set()
+
+    @asynccontextmanager
+    async def track(self, name: str) -> AsyncIterator[None]:
+        """Count the work in the block as running, under `name` for the logs."""
+        self._running[name] += 1
+        self._idle.clear()
+        try:
+            yield
+        finally:
+            self._running[name] -= 1
+            if not +self._running:
+                self._idle.set()
+
+    def running(self) -> list[str]:
+        """Return the names of the work running."""
+        return sorted(+self._running)
+
+    async def drain(self, timeout: float) -> bool:
+        """Stop new work from starting, and wait up to `timeout` seconds for the running work.
+
+        Returns:
+            True if all of it finished in time.
+        """
+        self.draining = True
+        if not self.running():
+            return True
+
+        logger.info("Waiting up to %gs for %s to finish", timeout, ", ".join(self.running()))
+        try:
+            await asyncio.wait_for(self._idle.wait(), timeout)
+        except TimeoutError:
+            logger.warning("Shutting down before %s finished", ", ".join(self.running()))
+            return False
+        return True
+
+
+def drained(name: str) -> Callable:
+    """Track each run of a cog's loop as in-flight work, and skip it once the bot is draining.
+
+    Goes under `@tasks.loop()`, on a method of a cog with a `bot`.
+    """
+
+    def decorator(coro: Callable[..., Awaitable[Any]]) -> Callable[..., Awaitable[Any]]:
+        @functools.wraps(coro)
+        async def wrapper(cog: Any, *args: Any, **kwargs: Any) -> Any:
+            work = cog.bot.in_flight
+            if work.draining:
+                return None
+            async with work.track(name):
+                return await coro(cog, *args, **kwargs)
+
+        return wrapper
+
+    return decorator
@@ -0,0 +1,69 @@
+"""Members interested in Discord scheduled events, tracked per series."""
+
+import logging
+from datetime import datetime
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+INTEREST = "interest"
+
+# Occurrences kept per series; older ones are dropped when a new one starts
+MAX_OCCURRENCES = 26
+
+
+class InterestTracker:
+    """Keeps who marked each event occurrence as interested.
+
+    Entries are keyed by calendar event ID and grouped by series (the event or
+    schedule name), so interest can be compared across occurrences and the
+    interested members can be reached before an event starts.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def add(self, event_id: str, series: str, start: datetime, user_id: int) -> None:
+        """Record that a member is interested in an occurrence."""
+        entry = self._store.get(INTEREST, event_id)
+        if entry is None:
+            entry = {"series": series, "start": start.isoformat(), "users": []}
+            self._forget_old_occurrences(series)
+        if user_id not in entry["users"]:
+            entry["users"].append(user_id)
+        self._store.set(INTEREST, event_id, entry)
+
+    def remove(self, event_id: str, user_id: int) -> None:
+        """Record that a member is no longer interested in an occurrence."""
+        entry = self._store.get(INTEREST, event_id)
+        if entry is None or user_id not in entry["users"]:
+            return
+        entry["users"].remove(user_id)
+        self._store.set(INTEREST, event_id, entry)
+
+    def users(self, event_id: str) -> list[int]:
+        """Return the IDs of members interested in an occurrence."""
+        entry = self._store.get(INTEREST, event_id)
+        return list(entry["users"]) if entry else []
+
+    def series_counts(self) -> dict[str, list[tuple[datetime, int]]]:
+        """Return interest per occurrence for each series, oldest first."""
+        counts: dict[str, list[tuple[datetime, int]]] = {}
+        for entry in self._store.items(INTEREST).values():
+            counts.setdefault(entry["series"], []).append(
+                (datetime.fromisoformat(entry["start"]), len(entry["users"]))
+            )
+        for occurrences in counts.values():
+            occurrences.sort()
+        return counts
+
+    def _forget_old_occurrences(self, series: str) -> None:
+        """Drop the oldest occurrences of a series to make room for a new one."""
+        entries = [
+            (entry["start"], event_id)
+            for event_id, entry in self._store.items(INTEREST).items()
+            if entry["series"] == series
+        ]
+        for _, event_id in sorted(entries)[: max(0, len(entries) - MAX_OCCURRENCES + 1)]:
+            self._store.delete(INTEREST, event_id)
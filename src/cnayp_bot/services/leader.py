@@ -0,0 +1,160 @@
+"""Lease-based leader election between replicas sharing a volume."""
+
+import json
+import logging
+import os
+from contextlib import contextmanager
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+logger = logging.getLogger(__name__)
+
+# A lock file older than this was left behind by a crashed replica
+STALE_LOCK = timedelta(seconds=10)
+
+
+def owns_guild(guild_id: int, shard_id: int | None, shard_count: int | None) -> bool:
+    """Check whether a shard receives the guild's events (every instance does unsharded)."""
+    if shard_id is None or not shard_count:
+        return True
+    return (guild_id >> 22) % shard_count == shard_id
+
+
+class LeaseBusyError(Exception):
+    """Raised when another replica is updating the lease right now."""
+
+
+class LeaderElection:
+    """Elects one replica to run the scheduler and digests.
+
+    Replicas take turns holding a lease file on a shared volume. The leader
+    renews its lease well before it expires; if it dies, another replica takes
+    over once the lease runs out. Updates are serialized with an exclusively
+    created lock file, so two replicas never both take a free lease.
+
+    Replicas waiting for the lease are standbys: they leave commands,
+    interactions, and Discord events to the leader too.
+
+    Without a lease path there's nothing to share, and this instance always
+    leads. Instances that aren't `eligible`, such as shards that don't hold the
+    guild, never lead.
+    """
+
+    def __init__(
+        self, path: Path | None, instance_id: str, ttl: timedelta, eligible: bool = True
+    ) -> None:
+        self._path = path
+        self.instance_id = instance_id
+        self._ttl = ttl
+        self._eligible = eligible
+        self._expires: datetime | None = None
+
+    @property
+    def is_leader(self) -> bool:
+        """Whether this instance holds an unexpired lease."""
+        if not self._eligible:
+            return False
+        if self._path is None:
+            return True
+        return self._expires is not None and datetime.now(ZoneInfo("UTC")) < self._expires
+
+    @property
+    def is_standby(self) -> bool:
+        """Whether this replica shares a lease and waits for it, rather than leading."""
+        return self._path is not None and not self.is_leader
+
+    def renew(self, now: datetime) -> bool:
+        """Take or extend the lease if it's free, expired, or already ours.
+
+        Returns:
+            True if this instance holds the lease afterwards.
+        """
+        if not self._eligible:
+            return False
+        if self._path is None:
+            return True
+
+        try:
+            with self._locked(now):
+                lease = self._read()
+                if lease is None or self._is_free(lease, now):
+                    self._expires = now + self._ttl
+                    self._write({"holder": self.instance_id, "expires": self._expires.isoformat()})
+                    return True
+                self._expires = None
+                return False
+        except LeaseBusyError:
+            # Keep the current lease, if any, until the next attempt
+            return self._expires is not None and now < self._expires
+        except OSError as e:
+            logger.error("Failed to update leader lease %s: %s", self._path, e)
+            return self._expires is not None and now < self._expires
+
+    def release(self, now: datetime) -> None:
+        """Give up the lease so another replica can take over right away."""
+        if self._path is None or self._expires is None:
+            return
+
+        self._expires = None
+        try:
+            with self._locked(now):
+                lease = self._read()
+                if lease and lease.get("holder") == self.instance_id:
+                    self._path.unlink()
+        except (LeaseBusyError, OSError) as e:
+            logger.warning("Failed to release leader lease, it will expire instead: %s", e)
+
+    @contextmanager
+    def _locked(self, now: datetime):
+        """Hold the lease lock file, clearing it first if a crashed replica left it."""
+        self._path.parent.mkdir(parents=True, exist_ok=True)
+        lock = self._path.with_suffix(self._path.suffix + ".lock")
+        try:
+            modified = datetime.fromtimestamp(lock.stat().st_mtime, tz=ZoneInfo("UTC"))
+            if now - modified > STALE_LOCK:
+                logger.warning("Removing stale leader lock %s", lock)
+                lock.unlink(missing_ok=True)
+        except FileNotFoundError:
+            pass
+
+        try:
+            fd = os.open(lock, os.O_CREAT | os.O_EXCL | os.O_WRONLY)
+        except FileExistsError as e:
+            raise LeaseBusyError(str(lock)) from e
+
+        try:
+            yield
+        finally:
+            os.close(fd)
+            lock.unlink(missing_ok=True)
+
+    def _is_free(self, lease: dict, now: datetime) -> bool:
+        """Check whether a lease is ours or expired, treating a corrupt one as free."""
+        try:
+            return (
+                lease["holder"] == self.instance_id
+                or datetime.fromisoformat(lease["expires"]) <= now
+            )
+        except (KeyError, TypeError, ValueError) as e:
+            logger.warning("Ignoring corrupt leader lease %s: %s", self._path, e)
+            return True
+
+    def _read(self) -> dict | None:
+        """Read the current lease, if any."""
+        try:
+            with self._path.open(encoding="utf-8") as f:
+                lease = json.load(f)
+        except FileNotFoundError:
+            return None
+        except ValueError as e:
+            logger.warning("Ignoring unreadable leader lease %s: %s", self._path, e)
+            return None
+        return lease if isinstance(lease, dict) else None
+
+    def _write(self, lease: dict) -> None:
+        """Write the lease atomically."""
+        tmp_path = self._path.with_suffix(self._path.suffix + f".{self.instance_id}.tmp")
+        with tmp_path.open("w", encoding="utf-8") as f:
+            json.dump(lease, f)
+        os.replace(tmp_path, self._path)
@@ -0,0 +1,158 @@
+"""URL reputation checks for links posted in the guild."""
+
+import logging
+import re
+from dataclasses import dataclass
+from datetime import datetime, timedelta
+from pathlib import Path
+from typing import Protocol
+from urllib.parse import urlsplit
+
+import aiohttp
+
+logger = logging.getLogger(__name__)
+
+URL = re.compile(r"https?://[^\s<>|]+", re.IGNORECASE)
+
+SAFE_BROWSING_URL = "https://safebrowsing.googleapis.com/v4/threatMatches:find"
+SAFE_BROWSING_THREATS = [
+    "MALWARE",
+    "SOCIAL_ENGINEERING",
+    "UNWANTED_SOFTWARE",
+    "POTENTIALLY_HARMFUL_APPLICATION",
+]
+
+# How long a verdict is reused for the same URL
+VERDICT_TTL = timedelta(hours=1)
+
+
+def extract_urls(text: str) -> list[str]:
+    """Return the distinct http(s) URLs in a message, in order."""
+    # Markdown links and sentences often end a URL with punctuation
+    urls = (match.rstrip(".,;:!?)]*_~'\"") for match in URL.findall(text))
+    return list(dict.fromkeys(urls))
+
+
+def url_domain(url: str) -> str:
+    """Return the lowercase host of a URL, without a leading www."""
+    host = (urlsplit(url).hostname or "").lower()
+    return host.removeprefix("www.")
+
+
+@dataclass
+class Verdict:
+    """A provider's finding that a URL is dangerous."""
+
+    url: str
+    provider: str
+    reason: str
+
+
+class LinkProvider(Protocol):
+    """A source of URL reputation."""
+
+    name: str
+
+    async def check(self, urls: list[str]) -> list[Verdict]:
+        """Return verdicts for the URLs found dangerous."""
+        ...
+
+
+class BlocklistProvider:
+    """Flags URLs whose domain, or a parent domain, is on a local blocklist."""
+
+    name = "blocklist"
+
+    def __init__(self, domains: set[str]) -> None:
+        self.domains = {domain.lower().removeprefix("www.") for domain in domains}
+
+    @classmethod
+    def from_file(cls, path: Path) -> "BlocklistProvider":
+        """Load one domain per line, ignoring blank lines and # comments."""
+        with path.open(encoding="utf-8") as f:
+            lines = (line.split("#", 1)[0].strip() for line in f)
+            return cls({line for line in lines if line})
+
+    async def check(self, urls: list[str]) -> list[Verdict]:
+        """Return verdicts for URLs on blocked domains."""
+        verdicts = []
+        for url in urls:
+            parts = url_domain(url).split(".")
+            parents = {".".join(parts[i:]) for i in range(len(parts) - 1)}
+            if blocked := parents & self.domains:
+                verdicts.append(Verdict(url, self.name, f"{min(blocked, key=len)} is blocklisted"))
+        return verdicts
+
+
+class SafeBrowsingProvider:
+    """Looks URLs up in the Google Safe Browsing Lookup API."""
+
+    name = "safe_browsing"
+
+    def __init__(self, api_key: str) -> None:
+        self._api_key = api_key
+
+    async def check(self, urls: list[str]) -> list[Verdict]:
+        """Return verdicts for URLs Safe Browsing lists as threats.
+
+        Raises:
+            aiohttp.ClientError: If the lookup fails.
+        """
+        body = {
+            "client": {"clientId": "cnayp-bot", "clientVersion": "1.0"},
+            "threatInfo": {
+                "threatTypes": SAFE_BROWSING_THREATS,
+                "platformTypes": ["ANY_PLATFORM"],
+                "threatEntryTypes": ["URL"],
+                "threatEntries": [{"url": url} for url in urls],
+            },
+        }
+        timeout = aiohttp.ClientTimeout(total=10)
+        async with aiohttp.ClientSession(timeout=timeout) as session:
+            async with session.post(
+                SAFE_BROWSING_URL, params={"key": self._api_key}, json=body
+            ) as response:
+                response.raise_for_status()
+                data = await response.json()
+
+        return [
+            Verdict(
+                match["threat"]["url"], self.name, match["threatType"].lower().replace("_", " ")
+            )
+            for match in data.get("matches", [])
+        ]
+
+
+class LinkScanner:
+    """Checks URLs with every configured provider, caching verdicts.
+
+    A provider that fails is skipped, so an outage never blocks messages, and
+    the URLs are checked again next time.
+    """
+
+    def __init__(self, providers: list[LinkProvider]) -> None:
+        self.providers = providers
+        self._cache: dict[str, tuple[datetime, list[Verdict]]] = {}
+
+    async def scan(self, urls: list[str], now: datetime) -> list[Verdict]:
+        """Return verdicts for the dangerous URLs among `urls`."""
+        self._cache = {
+            url: cached for url, cached in self._cache.items() if cached[0] + VERDICT_TTL > now
+        }
+        unknown = [url for url in urls if url not in self._cache]
+
+        found: dict[str, list[Verdict]] = {url: [] for url in unknown}
+        failed = False
+        for provider in self.providers if unknown else []:
+            try:
+                for verdict in await provider.check(unknown):
+                    found.setdefault(verdict.url, []).append(verdict)
+            except Exception as e:
+                logger.error("Link provider %s failed: %s", provider.name, e)
+                failed = True
+
+        verdicts = [verdict for url in urls for verdict in self._cache.get(url, (now, []))[1]]
+        verdicts += [verdict for url in unknown for verdict in found.get(url, [])]
+        if not failed:
+            self._cache.update((url, (now, found[url])) for url in unknown)
+        return verdicts
@@ -0,0 +1,49 @@
+"""Maintenance mode state, kept across restarts."""
+
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from discord.ext import commands
+
+from .store import Store
+
+MAINTENANCE = "maintenance"
+CURRENT = "current"
+
+
+class MaintenanceError(commands.CheckFailure):
+    """Raised when a non-admin runs a command during maintenance."""
+
+
+class Maintenance:
+    """Whether the bot is in maintenance mode, and the notice shown meanwhile."""
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    @property
+    def active(self) -> bool:
+        """Whether maintenance mode is on."""
+        return self._store.get(MAINTENANCE, CURRENT) is not None
+
+    @property
+    def message(self) -> str | None:
+        """The maintenance notice, or None when maintenance mode is off."""
+        current = self._store.get(MAINTENANCE, CURRENT)
+        return current["message"] if current else None
+
+    def start(self, message: str, user_id: int) -> None:
+        """Turn maintenance mode on with a notice for members."""
+        self._store.set(
+            MAINTENANCE,
+            CURRENT,
+            {
+                "message": message,
+                "user_id": user_id,
+                "since": datetime.now(ZoneInfo("UTC")).isoformat(),
+            },
+        )
+
+    def stop(self) -> None:
+        """Turn maintenance mode off."""
+        self._store.delete(MAINTENANCE, CURRENT)
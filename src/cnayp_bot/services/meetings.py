@@ -0,0 +1,122 @@
+"""Video meeting links for hybrid events, created with Zoom or Google Meet."""
+
+import asyncio
+import logging
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import aiohttp
+from googleapiclient.discovery import build
+from googleapiclient.errors import HttpError
+
+from ..config import settings
+from .calendar import CalendarEvent, get_credentials
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# Calendar event ID -> {"url": join link, "provider": "zoom" or "meet", "end": ISO end time}
+MEETINGS = "meetings"
+
+MEET_SCOPES = ["https://www.googleapis.com/auth/meetings.space.created"]
+ZOOM_TOKEN_URL = "https://zoom.us/oauth/token"
+ZOOM_API_URL = "https://api.zoom.us/v2"
+
+
+class MeetingError(Exception):
+    """Raised when a meeting can't be created."""
+
+
+class MeetingLinks:
+    """Creates a video meeting for each occurrence of a hybrid event.
+
+    Every occurrence gets its own meeting, so a link shared for one session
+    can't be used to join the next. Links are remembered until the event
+    ends, so retries and restarts reuse the meeting instead of creating another.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+        self._meet = None
+
+    async def link(self, event: CalendarEvent) -> str | None:
+        """Return the join link of an event's meeting, creating it the first time.
+
+        Returns:
+            The link, or None if the event's schedule doesn't have a meeting.
+
+        Raises:
+            MeetingError: If the meeting can't be created.
+        """
+        provider = event.schedule.meeting if event.schedule else None
+        if provider is None:
+            return None
+
+        meeting = self._store.get(MEETINGS, event.id)
+        if meeting:
+            return meeting["url"]
+
+        try:
+            if provider == "zoom":
+                url = await self._create_zoom_meeting(event)
+            else:
+                url = await asyncio.to_thread(self._create_meet_space)
+        except (aiohttp.ClientError, TimeoutError, HttpError, KeyError) as e:
+            raise MeetingError(f"Failed to create a {provider} meeting: {e}") from e
+
+        self._store.set(
+            MEETINGS,
+            event.id,
+            {"url": url, "provider": provider, "end": event.end_time.isoformat()},
+        )
+        logger.info("Created a %s meeting for %s at %s", provider, event.name, event.start_time)
+        return url
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop the links of events that ended."""
+        for event_id, meeting in self._store.items(MEETINGS).items():
+            if datetime.fromisoformat(meeting["end"]) <= now:
+                self._store.delete(MEETINGS, event_id)
+
+    async def _create_zoom_meeting(self, event: CalendarEvent) -> str:
+        """Schedule a Zoom meeting with a Server-to-Server OAuth app."""
+        app = (settings.zoom_account_id, settings.zoom_client_id, settings.zoom_client_secret)
+        if not all(app):
+            raise MeetingError("ZOOM_ACCOUNT_ID, ZOOM_CLIENT_ID, or ZOOM_CLIENT_SECRET isn't set")
+
+        start = event.start_time.astimezone(ZoneInfo("UTC"))
+        body = {
+            "topic": event.name,
+            "type": 2,  # Scheduled meeting
+            "start_time": start.strftime("%Y-%m-%dT%H:%M:%SZ"),
+            "duration": event.duration_minutes,
+            "timezone": event.timezone,
+            "agenda": event.description[:2000],
+        }
+        auth = aiohttp.BasicAuth(settings.zoom_client_id, settings.zoom_client_secret)
+        params = {"grant_type": "account_credentials", "account_id": settings.zoom_account_id}
+        timeout = aiohttp.ClientTimeout(total=10)
+        async with aiohttp.ClientSession(timeout=timeout) as session:
+            async with session.post(ZOOM_TOKEN_URL, params=params, auth=auth) as response:
+                if response.status != 200:
+                    raise MeetingError(f"Zoom refused the app credentials: {response.status}")
+                token = (await response.json())["access_token"]
+
+            url = f"{ZOOM_API_URL}/users/{settings.zoom_user}/meetings"
+            headers = {"Authorization": f"Bearer {token}"}
+            async with session.post(url, json=body, headers=headers) as response:
+                if response.status != 201:
+                    raise MeetingError(
+                        f"Zoom refused the meeting: {response.status} {await response.text()}"
+                    )
+                return (await response.json())["join_url"]
+
+    def _create_meet_space(self) -> str:
+        """Create a Google Meet meeting space, acting as `google_meet_user` when set."""
+        if self._meet is None:
+            credentials = get_credentials(MEET_SCOPES)
+            if settings.google_meet_user:
+                credentials = credentials.with_subject(settings.google_meet_user)
+            self._meet = build("meet", "v2", credentials=credentials)
+
+        return self._meet.spaces().create(body={}).execute()["meetingUri"]
@@ -0,0 +1,71 @@
+"""Snapshots of recent messages, kept to report on them after they're deleted."""
+
+from collections import OrderedDict
+from dataclasses import dataclass, field
+from datetime import datetime, timedelta
+
+# A message deleted this soon after mentioning someone is a ghost ping
+GHOST_PING_WINDOW = timedelta(minutes=10)
+
+
+@dataclass
+class MessageSnapshot:
+    """What a message said and who it mentioned, when it was sent."""
+
+    id: int
+    channel_id: int
+    author_id: int
+    author: str
+    content: str
+    created_at: datetime
+    user_mentions: list[int] = field(default_factory=list)
+    role_mentions: list[int] = field(default_factory=list)
+    mentions_everyone: bool = False
+    attachments: list[str] = field(default_factory=list)
+
+    @property
+    def pinged(self) -> list[int]:
+        """Members the message mentioned, other than its author."""
+        return [user_id for user_id in self.user_mentions if user_id != self.author_id]
+
+    def is_ghost_ping(self, deleted_at: datetime) -> bool:
+        """Check whether deleting the message now leaves its mentions unexplained."""
+        if deleted_at - self.created_at > GHOST_PING_WINDOW:
+            return False
+        return bool(self.pinged or self.role_mentions or self.mentions_everyone)
+
+
+class MessageCache:
+    """Bounded LRU of message snapshots, keyed by message ID.
+
+    Discord's delete events only carry the message ID, so the messages are
+    snapshotted as they're sent and edited. The least recently touched ones are
+    dropped once the cache is full.
+    """
+
+    def __init__(self, size: int) -> None:
+        self.size = size
+        self._messages: OrderedDict[int, MessageSnapshot] = OrderedDict()
+
+    def __len__(self) -> int:
+        return len(self._messages)
+
+    def add(self, snapshot: MessageSnapshot) -> None:
+        """Cache a message, replacing an earlier snapshot of it."""
+        if self.size <= 0:
+            return
+        self._messages[snapshot.id] = snapshot
+        self._messages.move_to_end(snapshot.id)
+        while len(self._messages) > self.size:
+            self._messages.popitem(last=False)
+
+    def get(self, message_id: int) -> MessageSnapshot | None:
+        """Return a cached message, marking it recently used."""
+        snapshot = self._messages.get(message_id)
+        if snapshot is not None:
+            self._messages.move_to_end(message_id)
+        return snapshot
+
+    def pop(self, message_id: int) -> MessageSnapshot | None:
+        """Remove a message from the cache, returning its snapshot."""
+        return self._messages.pop(message_id, None)
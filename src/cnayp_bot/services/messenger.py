@@ -0,0 +1,273 @@
+"""Outgoing message delivery with a mass-mention safety guard and a per-channel throttle."""
+
+import asyncio
+import logging
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import discord
+from discord.ext import commands
+
+from ..config import settings
+from ..helpers.chunking import split_message
+from ..helpers.mentions import ROLE_MENTION
+from ..helpers.permissions import MissingPermissionsError, check_can_send
+from ..helpers.ratelimit import SlidingWindowLimiter
+
+logger = logging.getLogger(__name__)
+
+# Store namespace holding a pause on mass pings, e.g. during an incident
+PING_PAUSE = "ping_pause"
+CURRENT = "current"
+
+
+def is_mass_ping(content: str | None, allowed_mentions: discord.AllowedMentions | None) -> bool:
+    """Check whether a message would ping @everyone, @here, or a role."""
+    if not content or allowed_mentions is None:
+        return False
+
+    if allowed_mentions.everyone and ("@everyone" in content or "@here" in content):
+        return True
+    return bool(allowed_mentions.roles) and ROLE_MENTION.search(content) is not None
+
+
+class Messenger:
+    """Sends messages on behalf of every bot feature.
+
+    Pings to @everyone, @here, or roles are counted per channel per hour. Once
+    a channel exceeds `mention_limit_per_hour`, further pings are downgraded to
+    plain text (or blocked entirely) and an alert is logged, so a misconfigured
+    schedule can't ping the whole server over and over.
+
+    Messages are also counted per channel per minute, and past
+    `channel_messages_per_minute` they wait for a slot, so many schedules
+    firing together trickle into a channel instead of flooding it.
+    """
+
+    def __init__(self, bot: commands.Bot) -> None:
+        self.bot = bot
+        self._pings = SlidingWindowLimiter(settings.mention_limit_per_hour, timedelta(hours=1))
+        self._sends = SlidingWindowLimiter(
+            settings.channel_messages_per_minute, timedelta(minutes=1)
+        )
+
+    async def send(
+        self,
+        channel: discord.abc.Messageable,
+        content: str | None = None,
+        *,
+        embed: discord.Embed | None = None,
+        embeds: list[discord.Embed] | None = None,
+        allowed_mentions: discord.AllowedMentions | None = None,
+        view: discord.ui.View | None = None,
+        reference: discord.Message | None = None,
+        silent: bool = False,
+        nonce: str | None = None,
+    ) -> discord.Message | None:
+        """Send a message, applying the mention guard.
+
+        Silent messages (`@silent` in the Discord client) still ping, but don't
+        send push or desktop notifications. `embeds` sends up to 10 embeds in
+        the one message instead of `embed`, e.g. images sharing a URL, which
+        Discord shows as a gallery. Sending again with the same `nonce` returns
+        the message already posted instead of posting it twice.
+
+        Returns:
+            The sent message, or None if it was blocked, the bot can't post in
+            the channel, or observer mode is on.
+        """
+        if isinstance(channel, discord.abc.GuildChannel):
+            try:
+                check_can_send(channel, embeds=embed is not None or bool(embeds))
+            except MissingPermissionsError as e:
+                logger.error("Can't send a message: %s", e)
+                return None
+
+        if is_mass_ping(content, allowed_mentions):
+            channel_id = getattr(channel, "id", 0)
+            now = datetime.now(ZoneInfo("UTC"))
+
+            if self.pings_paused_until(now):
+                logger.info("Pings are paused, sending without them in #%s", channel)
+                allowed_mentions = discord.AllowedMentions.none()
+            elif self._pings.delay(channel_id, now) > timedelta(0):
+                if settings.mention_guard_action == "block":
+                    await self._alert(channel, "blocked a message")
+                    return None
+
+                await self._alert(channel, "removed pings from a message")
+                allowed_mentions = discord.AllowedMentions.none()
+            else:
+                self._pings.record(channel_id, now)
+
+        if settings.observer_mode:
+            self.bot.observer.record(
+                "send message",
+                channel=str(getattr(channel, "name", channel)),
+                content=content,
+                embed=embed.title if embed else None,
+                pings=is_mass_ping(content, allowed_mentions),
+                silent=silent,
+            )
+            return None
+
+        await self._throttle(channel)
+        return await channel.send(
+            content,
+            **({"embeds": embeds} if embeds else {"embed": embed}),
+            allowed_mentions=allowed_mentions,
+            view=view,
+            reference=reference,
+            silent=silent,
+            **({"nonce": nonce, "enforce_nonce": True} if nonce else {}),
+        )
+
+    async def _throttle(self, channel: discord.abc.Messageable) -> None:
+        """Wait until a message can be sent in a channel without exceeding its per-minute limit."""
+        if not settings.channel_messages_per_minute:
+            return
+
+        channel_id = getattr(channel, "id", 0)
+        now = datetime.now(ZoneInfo("UTC"))
+        delay = self._sends.delay(channel_id, now)
+        # Taken before waiting, so messages sent meanwhile queue up behind this one
+        self._sends.record(channel_id, now + delay)
+        if delay > timedelta(0):
+            logger.info("Throttling messages in #%s for %.1fs", channel, delay.total_seconds())
+            await asyncio.sleep(delay.total_seconds())
+
+    def pause_pings(self, until: datetime, reason: str) -> None:
+        """Send every message without @everyone, @here, or role pings until `until`."""
+        self.bot.store.set(PING_PAUSE, CURRENT, {"until": until.isoformat(), "reason": reason})
+        logger.warning("Pings paused until %s: %s", until, reason)
+
+    def resume_pings(self) -> None:
+        """Lift a pause on pings early."""
+        self.bot.store.delete(PING_PAUSE, CURRENT)
+        logger.warning("Pings resumed")
+
+    def pings_paused_until(self, now: datetime) -> datetime | None:
+        """Return when the current pause on pings ends, if pings are paused."""
+        pause = self.bot.store.get(PING_PAUSE, CURRENT)
+        until = datetime.fromisoformat(pause["until"]) if pause else None
+        return until if until and until > now else None
+
+    async def send_parts(
+        self,
+        channel: discord.abc.Messageable,
+        content: str | None = None,
+        *,
+        embeds: list[discord.Embed] | None = None,
+        allowed_mentions: discord.AllowedMentions | None = None,
+        silent: bool = False,
+        view: discord.ui.View | None = None,
+    ) -> list[discord.Message]:
+        """Send content too long for one message as several, in order.
+
+        Text is split at line breaks into messages of up to 2000 characters,
+        then each embed, e.g. from `EmbedBuilder.build_pages()`, gets its own
+        message, and `view` goes on the last one. Sending stops at the first
+        part that fails, so readers never see a gap; in observer mode every
+        part is recorded.
+
+        Returns:
+            The messages sent.
+        """
+        parts: list[tuple[str | None, discord.Embed | None]] = [
+            (chunk, None) for chunk in split_message(content or "")
+        ]
+        parts += [(None, embed) for embed in embeds or []]
+
+        messages = []
+        for index, (chunk, embed) in enumerate(parts):
+            message = await self.send(
+                channel,
+                chunk,
+                embed=embed,
+                allowed_mentions=allowed_mentions,
+                view=view if index == len(parts) - 1 else None,
+                silent=silent,
+            )
+            if message:
+                messages.append(message)
+            elif not settings.observer_mode:
+                break
+        return messages
+
+    async def edit(
+        self,
+        message: discord.Message,
+        *,
+        embed: discord.Embed,
+        view: discord.ui.View | None = discord.utils.MISSING,
+    ) -> None:
+        """Replace the embed, and optionally the buttons, of a message the bot sent earlier.
+
+        Edits don't notify anyone, so the mention guard doesn't apply.
+        """
+        if settings.observer_mode:
+            self.bot.observer.record("edit message", message=message.id, embed=embed.title)
+            return
+
+        await message.edit(embed=embed, view=view)
+
+    async def alert_ops(self, message: str) -> None:
+        """Post an alert for organizers to the ops channel, or the errors channel."""
+        name = settings.discord_ops_channel or settings.discord_errors_channel
+        if not name:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Ops channel not found: %s", name)
+            return
+
+        await self.send(channel, message)
+
+    async def audit_log(self, message: str) -> None:
+        """Post an entry on something the bot did to the audit log channel, if one is set.
+
+        Entries never ping, long ones are split over several messages, and
+        failing to post one doesn't fail the action it's about.
+        """
+        name = settings.audit_log_channel
+        if not name:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        channel = guild and discord.utils.get(guild.text_channels, name=name)
+        if not channel:
+            logger.error("Audit log channel not found: %s", name)
+            return
+
+        try:
+            await self.send_parts(
+                channel, message, allowed_mentions=discord.AllowedMentions.none()
+            )
+        except discord.HTTPException as e:
+            logger.error("Failed to post to the audit log channel: %s", e)
+
+    async def _alert(self, channel: discord.abc.Messageable, action: str) -> None:
+        """Log and report that the mention guard intervened."""
+        name = getattr(channel, "name", channel)
+        logger.warning(
+            "Mention guard %s in #%s: more than %d mass pings in the last hour",
+            action,
+            name,
+            settings.mention_limit_per_hour,
+        )
+
+        if not settings.discord_errors_channel:
+            return
+
+        guild = self.bot.get_guild(settings.discord_guild_id)
+        errors_channel = guild and discord.utils.get(
+            guild.text_channels, name=settings.discord_errors_channel
+        )
+        if errors_channel:
+            await self.send(
+                errors_channel,
+                f"⚠️ Mention guard {action} in #{name}: more than "
+                f"{settings.mention_limit_per_hour} mass pings in the last hour.",
+            )
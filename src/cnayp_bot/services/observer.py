@@ -0,0 +1,66 @@
+"""Read-only observer mode for shadow-running the bot against production."""
+
+import logging
+import uuid
+from datetime import datetime
+from typing import Any
+from zoneinfo import ZoneInfo
+
+import discord
+
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+OBSERVED = "observed"
+MAX_OBSERVED = 1000
+
+
+class ObserverModeError(Exception):
+    """Raised when a Discord write slips past the explicit observer checks."""
+
+
+class Observer:
+    """Records the writes the bot would make instead of making them.
+
+    Features check `settings.observer_mode` before writing and call `record()`
+    instead. As a backstop, `guard_http()` rejects any other non-GET request so
+    a forgotten check can't touch production.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def record(self, action: str, **details: Any) -> None:
+        """Log and persist an action that was skipped."""
+        now = datetime.now(ZoneInfo("UTC"))
+        logger.info(
+            "[observer] Would %s: %s",
+            action,
+            ", ".join(f"{key}={value}" for key, value in details.items()),
+        )
+
+        # Timestamped keys keep entries in chronological order when sorted
+        key = f"{now.isoformat()}-{uuid.uuid4().hex[:6]}"
+        self._store.set(OBSERVED, key, {"action": action, "time": now.isoformat(), **details})
+
+        observed = self._store.items(OBSERVED)
+        for old_key in sorted(observed)[: max(0, len(observed) - MAX_OBSERVED)]:
+            self._store.delete(OBSERVED, old_key)
+
+    def entries(self) -> list[dict[str, Any]]:
+        """Return recorded actions, oldest first."""
+        observed = self._store.items(OBSERVED)
+        return [observed[key] for key in sorted(observed)]
+
+    def guard_http(self, http: discord.http.HTTPClient) -> None:
+        """Make the bot's HTTP client refuse every request that isn't a GET."""
+        request = http.request
+
+        async def guarded_request(route: discord.http.Route, **kwargs: Any) -> Any:
+            if route.method != "GET":
+                self.record("http request", method=route.method, path=route.path)
+                raise ObserverModeError(f"{route.method} {route.path} blocked in observer mode")
+            return await request(route, **kwargs)
+
+        http.request = guarded_request
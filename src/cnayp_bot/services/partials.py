@@ -0,0 +1,76 @@
+"""Named snippets shared between announcement templates, kept in a directory."""
+
+import logging
+from pathlib import Path
+from string import Formatter
+
+from ..models.schedule import (
+    ANNOUNCEMENT_FIELDS,
+    PARTIAL_REFERENCE,
+    TEMPLATE_LIMIT,
+    VALUE_REFERENCE,
+)
+
+logger = logging.getLogger(__name__)
+
+
+class TemplatePartials:
+    """Fills `{>name}` in templates with the text of `name.md` in the templates directory.
+
+    Partials such as a code of conduct footer or how-to-join instructions are
+    written once and shared by every schedule's templates. Files are read
+    again when they change, so edits show up in the next announcement without
+    a restart.
+    """
+
+    def __init__(self, directory: Path) -> None:
+        self.directory = directory
+        self._cache: dict[str, tuple[float, str | None]] = {}  # name -> (mtime, text)
+
+    def get(self, name: str) -> str | None:
+        """Return a partial's text, or None if it doesn't exist or isn't valid."""
+        path = self.directory / f"{name}.md"
+        try:
+            modified = path.stat().st_mtime
+        except FileNotFoundError:
+            return None
+        cached = self._cache.get(name)
+        if cached and cached[0] == modified:
+            return cached[1]
+
+        try:
+            text = _check_partial(path.read_text(encoding="utf-8").strip())
+        except (OSError, ValueError) as e:
+            logger.error("Ignoring template partial %s: %s", path, e)
+            text = None
+        self._cache[name] = (modified, text)
+        return text
+
+    def expand(self, template: str) -> str:
+        """Fill in a template's partials, dropping the ones that don't exist.
+
+        Partials are left out altogether when they'd make the template longer
+        than `TEMPLATE_LIMIT`, so the announcement still fits in a message.
+        """
+
+        def partial(match) -> str:
+            text = self.get(match[1])
+            if text is None:
+                logger.warning("Leaving out template partial {>%s}, missing or invalid", match[1])
+            return text or ""
+
+        expanded = PARTIAL_REFERENCE.sub(partial, template)
+        if len(expanded) > TEMPLATE_LIMIT:
+            logger.error("Template is over %d characters with its partials", TEMPLATE_LIMIT)
+            return PARTIAL_REFERENCE.sub("", template)
+        return expanded
+
+
+def _check_partial(text: str) -> str:
+    """Reject a partial including other partials or with placeholders templates can't fill."""
+    if PARTIAL_REFERENCE.search(text):
+        raise ValueError("Partials can't include other partials")
+    for _, field, _, _ in Formatter().parse(VALUE_REFERENCE.sub("", text)):
+        if field is not None and field not in ANNOUNCEMENT_FIELDS:
+            raise ValueError(f"Unknown placeholder {{{field}}}")
+    return text
@@ -0,0 +1,66 @@
+"""Announcements of our public sessions in partner communities, and their opt-outs."""
+
+from datetime import datetime, timedelta
+
+from ..models import PartnerCommunity, Schedule
+from .store import Store
+
+# Calendar event ID + "@guild ID" -> {"message_id", "end"}, so each occurrence is
+# announced once per partner
+PARTNER_ANNOUNCEMENTS = "partner_announcements"
+
+# Partner guild ID -> True, for partners who asked not to get announcements anymore
+PARTNER_OPT_OUTS = "partner_opt_outs"
+
+# How long announcements are remembered after their event ended
+RETENTION = timedelta(days=1)
+
+
+def partners_of(partners: list[PartnerCommunity], schedule: Schedule) -> list[PartnerCommunity]:
+    """Return the partners a schedule's events are announced to."""
+    name = schedule.name.lower()
+    return [
+        partner
+        for partner in partners
+        if name in {listed.lower() for listed in partner.schedules}
+    ]
+
+
+class PartnerAnnouncements:
+    """Tracks which occurrences were announced to which partners, and who opted out.
+
+    Partners opt out from their own guild, so they don't need to ask us to
+    edit the schedules file; the opt-out outlives changes to the file.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def opted_out(self, guild_id: int) -> bool:
+        """Check whether a partner guild asked not to get announcements."""
+        return bool(self._store.get(PARTNER_OPT_OUTS, str(guild_id)))
+
+    def set_opted_out(self, guild_id: int, opted_out: bool) -> None:
+        """Stop or resume announcements in a partner guild."""
+        if opted_out:
+            self._store.set(PARTNER_OPT_OUTS, str(guild_id), True)
+        else:
+            self._store.delete(PARTNER_OPT_OUTS, str(guild_id))
+
+    def announced(self, event_id: str, guild_id: int) -> bool:
+        """Check whether an occurrence was announced in a partner guild."""
+        return self._store.get(PARTNER_ANNOUNCEMENTS, f"{event_id}@{guild_id}") is not None
+
+    def record(self, event_id: str, guild_id: int, message_id: int | None, end: datetime) -> None:
+        """Remember an occurrence's announcement in a partner guild."""
+        self._store.set(
+            PARTNER_ANNOUNCEMENTS,
+            f"{event_id}@{guild_id}",
+            {"message_id": message_id, "end": end.isoformat()},
+        )
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop the announcements of events that ended over `RETENTION` ago."""
+        for key, announcement in self._store.items(PARTNER_ANNOUNCEMENTS).items():
+            if datetime.fromisoformat(announcement["end"]) + RETENTION < now:
+                self._store.delete(PARTNER_ANNOUNCEMENTS, key)
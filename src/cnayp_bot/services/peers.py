@@ -0,0 +1,161 @@
+"""Signed task requests between the bots of the CNAYP fleet."""
+
+import hashlib
+import hmac
+import json
+import logging
+import uuid
+from collections.abc import Callable, Coroutine, Mapping
+from datetime import datetime, timedelta
+from typing import Any
+from zoneinfo import ZoneInfo
+
+import aiohttp
+from pydantic import BaseModel, Field, ValidationError
+
+from ..config import PeerBot
+
+logger = logging.getLogger(__name__)
+
+TASKS_PATH = "/api/peer/tasks"
+# Requests signed longer ago than this are rejected, and task IDs are remembered as long
+MAX_AGE = timedelta(minutes=5)
+
+TaskHandler = Callable[[str, dict[str, Any]], Coroutine[Any, Any, dict[str, Any]]]
+
+
+class PeerError(Exception):
+    """Raised when a task can't be sent to a peer or run for one."""
+
+
+class PeerAuthError(PeerError):
+    """Raised when a request isn't from a known peer or isn't signed correctly."""
+
+
+class PeerTask(BaseModel):
+    """A task one bot asks another to run."""
+
+    id: str = Field(default_factory=lambda: uuid.uuid4().hex)
+    action: str
+    params: dict[str, Any] = Field(default_factory=dict)
+
+
+def sign(secret: str, timestamp: str, body: bytes) -> str:
+    """Sign a request body and its timestamp with a peer's shared secret."""
+    message = timestamp.encode() + b"." + body
+    return hmac.new(secret.encode(), message, hashlib.sha256).hexdigest()
+
+
+class PeerNetwork:
+    """Sends tasks to the other bots of the fleet and runs the tasks they send.
+
+    Each peer shares a secret with this bot. Requests carry the sender's name,
+    a timestamp, and an HMAC of both with the body. Requests signed more than
+    five minutes ago, and tasks already run, are rejected, so a captured
+    request can't be replayed.
+    """
+
+    def __init__(self, name: str, peers: dict[str, PeerBot]) -> None:
+        self.name = name
+        self.peers = peers
+        self._handlers: dict[str, TaskHandler] = {}
+        self._seen: dict[str, datetime] = {}  # Task ID -> when it was received
+
+    def register(self, action: str, handler: TaskHandler) -> None:
+        """Run `handler` with the sender's name and the params for tasks of `action`."""
+        self._handlers[action] = handler
+
+    @property
+    def actions(self) -> list[str]:
+        """The actions peers can ask this bot to run."""
+        return sorted(self._handlers)
+
+    def verify(self, headers: Mapping[str, str], body: bytes, now: datetime) -> str:
+        """Check a request's signature and age.
+
+        Returns:
+            The name of the peer that sent it.
+
+        Raises:
+            PeerAuthError: If the sender is unknown, or the signature is wrong or stale.
+        """
+        sender = headers.get("X-Peer-Name", "")
+        peer = self.peers.get(sender)
+        if peer is None:
+            raise PeerAuthError(f"Unknown peer {sender!r}")
+
+        timestamp = headers.get("X-Peer-Timestamp", "")
+        try:
+            signed_at = datetime.fromtimestamp(int(timestamp), tz=ZoneInfo("UTC"))
+        except ValueError as e:
+            raise PeerAuthError(f"Invalid timestamp from {sender}") from e
+        if abs(now - signed_at) > MAX_AGE:
+            raise PeerAuthError(f"Stale request from {sender}")
+
+        expected = sign(peer.secret, timestamp, body)
+        if not hmac.compare_digest(headers.get("X-Peer-Signature", ""), expected):
+            raise PeerAuthError(f"Bad signature from {sender}")
+        return sender
+
+    async def receive(self, headers: Mapping[str, str], body: bytes, now: datetime) -> dict:
+        """Verify and run a task sent by a peer.
+
+        Returns:
+            The handler's result, sent back to the peer.
+
+        Raises:
+            PeerAuthError: If the request isn't authentic.
+            PeerError: If the task is malformed, was already run, or has an unknown action.
+        """
+        sender = self.verify(headers, body, now)
+        try:
+            task = PeerTask.model_validate_json(body)
+        except ValidationError as e:
+            raise PeerError(f"Invalid task from {sender}") from e
+
+        self._seen = {key: seen for key, seen in self._seen.items() if now - seen <= MAX_AGE}
+        if task.id in self._seen:
+            raise PeerAuthError(f"Task {task.id} from {sender} was already run")
+        self._seen[task.id] = now
+
+        handler = self._handlers.get(task.action)
+        if handler is None:
+            raise PeerError(f"Unknown action {task.action!r}")
+
+        logger.info("Running %s for %s (task %s)", task.action, sender, task.id)
+        return await handler(sender, task.params)
+
+    async def send(self, peer_name: str, action: str, params: dict[str, Any] | None = None) -> dict:
+        """Ask a peer to run a task.
+
+        Returns:
+            The peer's result.
+
+        Raises:
+            PeerError: If the peer is unknown, unreachable, or refused the task.
+        """
+        peer = self.peers.get(peer_name)
+        if peer is None:
+            raise PeerError(f"Unknown peer {peer_name!r}")
+
+        body = PeerTask(action=action, params=params or {}).model_dump_json().encode()
+        timestamp = str(int(datetime.now(ZoneInfo("UTC")).timestamp()))
+        headers = {
+            "Content-Type": "application/json",
+            "X-Peer-Name": self.name,
+            "X-Peer-Timestamp": timestamp,
+            "X-Peer-Signature": sign(peer.secret, timestamp, body),
+        }
+        url = peer.url.rstrip("/") + TASKS_PATH
+        timeout = aiohttp.ClientTimeout(total=10)
+        try:
+            async with (
+                aiohttp.ClientSession(timeout=timeout) as session,
+                session.post(url, data=body, headers=headers) as response,
+            ):
+                text = await response.text()
+                if response.status >= 400:
+                    raise PeerError(f"{peer_name} refused {action}: {response.status} {text}")
+                return json.loads(text) if text else {}
+        except (aiohttp.ClientError, TimeoutError, ValueError) as e:
+            raise PeerError(f"Failed to reach {peer_name}: {e}") from e
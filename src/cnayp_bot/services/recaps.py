@@ -0,0 +1,130 @@
+"""Photos, screenshots, and links attendees share in an event's recap thread."""
+
+import re
+from datetime import datetime, timedelta
+
+from .store import Store
+
+# Event ID -> {"name", "thread_id", "prompt_id", "closes_at", "items": [{"user_id",
+# "message_id", "images", "files", "links"}]}
+RECAPS = "recaps"
+
+# Schedule name -> ID of the thread its recaps are collected in
+RECAP_THREADS = "recap_threads"
+
+# How long after an event ends shared photos and links are collected
+COLLECT_FOR = timedelta(hours=48)
+
+# Messages collected per recap, so a busy thread doesn't grow the store without bound
+MAX_ITEMS = 200
+
+_LINK = re.compile(r"https?://[^\s<>]+")
+
+
+def extract_links(content: str) -> list[str]:
+    """Return the links in a message, in order, without duplicates."""
+    return list(dict.fromkeys(_LINK.findall(content)))
+
+
+class RecapCollection:
+    """Tracks each occurrence's open recap and what was shared in it.
+
+    Messages are remembered by ID rather than by their attachments' URLs,
+    which expire, so the summary fetches them fresh and leaves out what was
+    deleted in the meantime.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def thread(self, schedule: str) -> int | None:
+        """Return the ID of the thread a schedule's recaps are collected in."""
+        return self._store.get(RECAP_THREADS, schedule.lower())
+
+    def set_thread(self, schedule: str, thread_id: int) -> None:
+        """Remember the thread a schedule's recaps are collected in, to reuse it."""
+        self._store.set(RECAP_THREADS, schedule.lower(), thread_id)
+
+    def open(
+        self, event_id: str, name: str, thread_id: int, prompt_id: int, ended: datetime
+    ) -> None:
+        """Start collecting an occurrence's recap in a thread."""
+        self._store.set(
+            RECAPS,
+            event_id,
+            {
+                "name": name,
+                "thread_id": thread_id,
+                "prompt_id": prompt_id,
+                "closes_at": (ended + COLLECT_FOR).isoformat(),
+                "items": [],
+            },
+        )
+
+    def get(self, event_id: str) -> dict | None:
+        """Return an occurrence's recap, or None if it isn't open."""
+        return self._store.get(RECAPS, event_id)
+
+    def collecting(self, thread_id: int, now: datetime) -> str | None:
+        """Return the event ID of the recap collected in a thread now, if any.
+
+        When a schedule's occurrences are less than 48 hours apart, that's
+        the latest occurrence's.
+        """
+        open_recaps = {
+            event_id: datetime.fromisoformat(recap["closes_at"])
+            for event_id, recap in self._store.items(RECAPS).items()
+            if recap["thread_id"] == thread_id
+        }
+        latest = max(open_recaps, key=open_recaps.get, default=None)
+        return latest if latest and now < open_recaps[latest] else None
+
+    def add(
+        self,
+        event_id: str,
+        user_id: int,
+        message_id: int,
+        images: int,
+        files: int,
+        links: list[str],
+    ) -> bool:
+        """Remember a message sharing photos, files, or links in a recap.
+
+        Returns:
+            False if the recap is closed or full.
+        """
+        recap = self.get(event_id)
+        if recap is None or len(recap["items"]) >= MAX_ITEMS:
+            return False
+        recap["items"].append(
+            {
+                "user_id": user_id,
+                "message_id": message_id,
+                "images": images,
+                "files": files,
+                "links": links,
+            }
+        )
+        self._store.set(RECAPS, event_id, recap)
+        return True
+
+    def remove(self, event_id: str, message_id: int) -> None:
+        """Forget a message deleted from a recap thread."""
+        recap = self.get(event_id)
+        if recap is None:
+            return
+        items = [item for item in recap["items"] if item["message_id"] != message_id]
+        if len(items) != len(recap["items"]):
+            self._store.set(RECAPS, event_id, recap | {"items": items})
+
+    def due(self, now: datetime) -> list[str]:
+        """Return the event IDs of the recaps whose collection period is over."""
+        return [
+            event_id
+            for event_id, recap in self._store.items(RECAPS).items()
+            if datetime.fromisoformat(recap["closes_at"]) <= now
+        ]
+
+    def close(self, event_id: str) -> None:
+        """Stop tracking a recap once its summary is posted."""
+        self._store.delete(RECAPS, event_id)
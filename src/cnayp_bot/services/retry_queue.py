@@ -0,0 +1,117 @@
+"""Notifications sent again after a transient failure, e.g. a 5xx or a network blip."""
+
+import random
+import secrets
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass
+from datetime import datetime, timedelta
+
+import aiohttp
+import discord
+
+# Backoff between attempts, before jitter
+RETRY_BASE_DELAY = timedelta(seconds=10)
+RETRY_MAX_DELAY = timedelta(minutes=5)
+
+
+def is_transient(error: Exception) -> bool:
+    """Check whether a failed request may succeed if sent again.
+
+    Discord's server errors and network errors are; anything else, such as a
+    missing permission or an invalid message, fails the same way every time.
+    A timeout may come after Discord posted the message, so retried messages
+    are sent with the same `new_nonce()` to avoid posting them twice.
+    """
+    return isinstance(
+        error, discord.DiscordServerError | aiohttp.ClientError | ConnectionError | TimeoutError
+    )
+
+
+def new_nonce() -> str:
+    """Return a nonce for a message, which Discord posts once however often it's sent.
+
+    Discord only remembers nonces for a few minutes, so a message retried
+    later than that may still be posted twice.
+    """
+    # Discord takes nonces of up to 25 characters
+    return secrets.token_hex(12)
+
+
+@dataclass
+class PendingNotification:
+    """A notification waiting for its next attempt."""
+
+    description: str
+    send: Callable[[], Awaitable[object]]
+    first_failure: datetime
+    retry_at: datetime
+    attempts: int = 1
+
+
+class RetryQueue:
+    """Bounded queue of failed notifications, retried with backoff and jitter.
+
+    Each is retried until `window` after its first failure, then given up.
+    The queue holds at most `max_size`, so an outage can't grow it without
+    end. It's kept in memory: the sends are callables, and a reminder retried
+    after a restart would be late anyway.
+    """
+
+    def __init__(
+        self,
+        max_size: int,
+        window: timedelta,
+        jitter: Callable[[], float] = random.random,
+    ) -> None:
+        self.max_size = max_size
+        self.window = window
+        self._jitter = jitter
+        self._pending: list[PendingNotification] = []
+
+    def __len__(self) -> int:
+        return len(self._pending)
+
+    def add(self, description: str, send: Callable[[], Awaitable[object]], now: datetime) -> bool:
+        """Queue a notification whose first attempt failed.
+
+        Returns:
+            False if it's given up instead: the queue is full, or the window
+            is shorter than the first delay.
+        """
+        pending = PendingNotification(description, send, now, now)
+        if len(self._pending) >= self.max_size:
+            return False
+        return self._schedule(pending, now)
+
+    def retry_later(self, pending: PendingNotification, now: datetime) -> bool:
+        """Queue a notification again after another failed attempt.
+
+        Returns:
+            False if its window is over, so it's given up.
+        """
+        pending.attempts += 1
+        return self._schedule(pending, now)
+
+    def due(self, now: datetime) -> list[PendingNotification]:
+        """Take the notifications due for another attempt out of the queue."""
+        due = [pending for pending in self._pending if pending.retry_at <= now]
+        self._pending = [pending for pending in self._pending if pending.retry_at > now]
+        return due
+
+    def _schedule(self, pending: PendingNotification, now: datetime) -> bool:
+        """Set the next attempt after the backoff, unless that's past the window."""
+        retry_at = now + self.delay(pending.attempts)
+        if retry_at > pending.first_failure + self.window:
+            return False
+        pending.retry_at = retry_at
+        self._pending.append(pending)
+        return True
+
+    def delay(self, attempts: int) -> timedelta:
+        """Return how long to wait after `attempts` failed attempts.
+
+        The delay doubles with each attempt, and up to half of it is taken off
+        at random, so notifications that failed together don't retry together.
+        """
+        delay = min(RETRY_BASE_DELAY * 2 ** (attempts - 1), RETRY_MAX_DELAY)
+        return delay * (1 - self._jitter() / 2)
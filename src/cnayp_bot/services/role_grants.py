@@ -0,0 +1,74 @@
+"""Temporary role grants that are revoked when they expire."""
+
+from dataclasses import dataclass
+from datetime import datetime
+
+from .store import Store
+
+# "<member ID>:<role ID>" -> {"expires": ..., "granted_by": ..., "guild_id": ...}
+ROLE_GRANTS = "role_grants"
+
+
+@dataclass
+class RoleGrant:
+    """A role given to a member until it expires."""
+
+    member_id: int
+    role_id: int
+    expires: datetime
+    granted_by: int
+    # None for grants recorded before guilds were, all in the primary guild
+    guild_id: int | None = None
+
+
+class RoleGrants:
+    """Tracks temporary roles and when to take them back.
+
+    Granting a role a member already holds temporarily replaces the old
+    expiry, so grants can be extended or shortened.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def grant(
+        self, member_id: int, role_id: int, expires: datetime, granted_by: int, guild_id: int
+    ) -> None:
+        """Record a role given in a guild until `expires`."""
+        self._store.set(
+            ROLE_GRANTS,
+            f"{member_id}:{role_id}",
+            {"expires": expires.isoformat(), "granted_by": granted_by, "guild_id": guild_id},
+        )
+
+    def remove(self, member_id: int, role_id: int) -> bool:
+        """Forget a grant, after it's revoked or the role was removed another way.
+
+        Returns:
+            True if there was such a grant.
+        """
+        key = f"{member_id}:{role_id}"
+        if self._store.get(ROLE_GRANTS, key) is None:
+            return False
+        self._store.delete(ROLE_GRANTS, key)
+        return True
+
+    def all(self) -> list[RoleGrant]:
+        """Return every grant, soonest to expire first."""
+        grants = []
+        for key, grant in self._store.items(ROLE_GRANTS).items():
+            member_id, _, role_id = key.partition(":")
+            grants.append(
+                RoleGrant(
+                    member_id=int(member_id),
+                    role_id=int(role_id),
+                    expires=datetime.fromisoformat(grant["expires"]),
+                    granted_by=grant["granted_by"],
+                    guild_id=grant.get("guild_id"),
+                )
+            )
+        return sorted(grants, key=lambda grant: grant.expires)
+
+    def expired(self, now: datetime) -> list[RoleGrant]:
+        """Return the grants due to be revoked."""
+        return [grant for grant in self.all() if grant.expires <= now]
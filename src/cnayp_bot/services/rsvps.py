@@ -0,0 +1,139 @@
+"""RSVPs for scheduled events, with limited seats and a waitlist for capped ones."""
+
+from datetime import datetime
+from typing import Literal
+
+from .store import Store
+
+# Event ID -> {"message_id", "channel_id", "name", "capacity", "end", "going": [...],
+# "waitlist": [...], "maybe": [...], "declined": [...]}
+RSVPS = "rsvps"
+
+Answer = Literal["maybe", "declined"]
+
+
+class RsvpList:
+    """Tracks who's going to each event, and who's waiting for a seat.
+
+    The list is opened when the event is announced with RSVP buttons, and the
+    buttons find it again by the announcement's message ID. Events without a
+    capacity have unlimited seats. When someone who had a seat leaves, the
+    first member on the waitlist takes it. Members can also answer maybe or
+    that they can't go, which leaves their seat the same way.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def open(
+        self,
+        event_id: str,
+        message_id: int,
+        name: str,
+        capacity: int | None,
+        end: datetime,
+        channel_id: int | None = None,
+    ) -> None:
+        """Start taking RSVPs for an event announced in a message."""
+        self._store.set(
+            RSVPS,
+            event_id,
+            {
+                "message_id": message_id,
+                "channel_id": channel_id,
+                "name": name,
+                "capacity": capacity,
+                "end": end.isoformat(),
+                "going": [],
+                "waitlist": [],
+                "maybe": [],
+                "declined": [],
+            },
+        )
+
+    def find(self, message_id: int) -> str | None:
+        """Return the event ID announced in a message."""
+        for event_id, rsvps in self._store.items(RSVPS).items():
+            if rsvps["message_id"] == message_id:
+                return event_id
+        return None
+
+    def get(self, event_id: str) -> dict | None:
+        """Return an event's RSVPs."""
+        rsvps = self._store.get(RSVPS, event_id)
+        # Lists opened before maybe and can't-go answers were taken
+        return rsvps and {"maybe": [], "declined": []} | rsvps
+
+    def join(self, event_id: str, member_id: int) -> int:
+        """RSVP a member, taking a free seat or joining the waitlist.
+
+        Returns:
+            0 if the member has a seat, otherwise their place on the waitlist.
+        """
+        rsvps = self.get(event_id)
+        if member_id in rsvps["going"]:
+            return 0
+        if member_id in rsvps["waitlist"]:
+            return rsvps["waitlist"].index(member_id) + 1
+
+        _withdraw_answer(rsvps, member_id)
+        if rsvps["capacity"] is None or len(rsvps["going"]) < rsvps["capacity"]:
+            rsvps["going"].append(member_id)
+            place = 0
+        else:
+            rsvps["waitlist"].append(member_id)
+            place = len(rsvps["waitlist"])
+        self._store.set(RSVPS, event_id, rsvps)
+        return place
+
+    def leave(self, event_id: str, member_id: int) -> int | None:
+        """Withdraw a member's RSVP, waitlist place, or answer.
+
+        Returns:
+            The waitlisted member promoted to the freed seat, if any.
+        """
+        rsvps = self.get(event_id)
+        promoted = _withdraw_seat(rsvps, member_id)
+        _withdraw_answer(rsvps, member_id)
+        self._store.set(RSVPS, event_id, rsvps)
+        return promoted
+
+    def answer(self, event_id: str, member_id: int, answer: Answer) -> int | None:
+        """Record that a member may go or can't go, giving up any seat they had.
+
+        Returns:
+            The waitlisted member promoted to the freed seat, if any.
+        """
+        rsvps = self.get(event_id)
+        promoted = _withdraw_seat(rsvps, member_id)
+        _withdraw_answer(rsvps, member_id)
+        rsvps[answer].append(member_id)
+        self._store.set(RSVPS, event_id, rsvps)
+        return promoted
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop the RSVPs of events that ended."""
+        for event_id, rsvps in self._store.items(RSVPS).items():
+            if datetime.fromisoformat(rsvps["end"]) <= now:
+                self._store.delete(RSVPS, event_id)
+
+
+def _withdraw_seat(rsvps: dict, member_id: int) -> int | None:
+    """Take a member off the seats or the waitlist, returning who got their seat."""
+    if member_id in rsvps["waitlist"]:
+        rsvps["waitlist"].remove(member_id)
+    if member_id not in rsvps["going"]:
+        return None
+    rsvps["going"].remove(member_id)
+    if not rsvps["waitlist"]:
+        return None
+    promoted = rsvps["waitlist"].pop(0)
+    rsvps["going"].append(promoted)
+    return promoted
+
+
+def _withdraw_answer(rsvps: dict, member_id: int) -> None:
+    """Take back a member's maybe or can't-go answer."""
+    for answer in ("maybe", "declined"):
+        if member_id in rsvps[answer]:
+            rsvps[answer].remove(member_id)
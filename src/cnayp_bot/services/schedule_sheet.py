@@ -0,0 +1,325 @@
+"""Schedules imported from a Google Sheet or CSV kept by organizers."""
+
+import csv
+import io
+import logging
+import re
+from dataclasses import dataclass, field
+from datetime import date, datetime
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+import aiohttp
+from pydantic import ValidationError
+
+from ..config import settings
+from ..models import Schedule, ScheduleConfig
+from .schedules import WEEKDAYS, schedule_when
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+NAMESPACE = "schedule_sheet"
+
+# Header (lowercase, words joined by "_") -> Schedule field
+COLUMNS = {
+    "name": "name",
+    "event": "name",
+    "description": "description",
+    "voice_channel": "voice_channel",
+    "notify_channel": "notify_channel",
+    "days": "days",
+    "date": "date",
+    "cron": "cron",
+    "time": "time",
+    "every_weeks": "every_weeks",
+    "starting": "starting",
+    "timezone": "timezone",
+    "duration": "duration_minutes",
+    "duration_minutes": "duration_minutes",
+    "enabled": "enabled",
+    "capacity": "capacity",
+    "owners": "owners",
+    "co_hosts": "co_hosts",
+    "guild": "guild_id",
+    "guild_id": "guild_id",
+}
+REQUIRED = {"name", "days", "time", "duration_minutes"}
+
+TRUE = {"yes", "y", "true", "1", "x", "si", "sí"}
+FALSE = {"no", "n", "false", "0"}
+TIME_FORMATS = ("%H:%M", "%H:%M:%S", "%I:%M %p", "%I %p")
+
+_ISO_DATE = re.compile(r"\d{4}-\d{2}-\d{2}")
+_SHEET_URL = re.compile(r"https://docs\.google\.com/spreadsheets/d/([\w-]+)")
+
+
+def csv_url(url: str) -> str:
+    """Turn a Google Sheet's link into its CSV export URL; other URLs are kept.
+
+    The sheet must be shared with anyone with the link. Links to a tab (`#gid=`)
+    export that tab, published (`/pub`) links are already CSV.
+    """
+    match = _SHEET_URL.match(url)
+    if not match or "/pub" in url:
+        return url
+    gid = re.search(r"gid=(\d+)", url)
+    export = f"https://docs.google.com/spreadsheets/d/{match[1]}/export?format=csv"
+    return export + (f"&gid={gid[1]}" if gid else "")
+
+
+@dataclass
+class SheetImport:
+    """The valid rows of a sheet, the fields its columns set, and what was wrong."""
+
+    schedules: list[Schedule] = field(default_factory=list)
+    columns: set[str] = field(default_factory=set)
+    errors: list[str] = field(default_factory=list)
+
+
+def parse_schedule_sheet(text: str) -> SheetImport:
+    """Read schedules from CSV, one per row, with headers naming Schedule fields.
+
+    Days are separated by commas or spaces; one-off events have an ISO date in
+    a date column or in place of their days, and other schedules a cron
+    expression in a cron column, with no time. Times can be 24-hour or with
+    AM/PM, and owners and co-hosts are Discord user IDs. Missing voice and notify
+    channels and timezones fall back to the bot's defaults. Unknown columns
+    are ignored, so organizers can keep notes next to their schedules.
+    """
+    result = SheetImport()
+    reader = csv.reader(io.StringIO(text))
+    headers = next(reader, [])
+    fields = [COLUMNS.get(_column(header)) for header in headers]
+    result.columns = {name for name in fields if name}
+
+    missing = REQUIRED - result.columns
+    if missing:
+        result.errors.append(f"Missing columns: {', '.join(sorted(missing))}")
+        return result
+
+    names: set[str] = set()
+    for number, row in enumerate(reader, start=2):
+        cells = {name: value.strip() for name, value in zip(fields, row, strict=False) if name}
+        if not any(cells.values()):
+            continue
+        try:
+            schedule = parse_row(cells)
+        except ValueError as e:
+            result.errors.append(f"Row {number}: {e}")
+            continue
+
+        if schedule.name.lower() in names:
+            result.errors.append(f"Row {number}: {schedule.name} is listed twice")
+            continue
+        names.add(schedule.name.lower())
+        result.schedules.append(schedule)
+    return result
+
+
+def _column(header: str) -> str:
+    """Normalize a header, e.g. "Voice channel" to "voice_channel"."""
+    return re.sub(r"\W+", "_", header.strip().lower()).strip("_")
+
+
+def parse_row(cells: dict[str, str]) -> Schedule:
+    """Build a schedule from one row's cells.
+
+    Raises:
+        ValueError: If a cell can't be read or the schedule is invalid.
+    """
+    on, days_cell = cells.get("date", ""), cells["days"]
+    if _ISO_DATE.fullmatch(days_cell.strip()):
+        on, days_cell = days_cell.strip(), ""
+    cron = cells.get("cron", "")
+    days = [day.lower() for day in re.split(r"[\s,/;]+", days_cell) if day]
+    unknown = [day for day in days if day not in WEEKDAYS]
+    if (not days and not on and not cron) or unknown:
+        raise ValueError(f"Unknown days {', '.join(unknown) or '(none)'}, use e.g. monday")
+    if [bool(days), bool(on), bool(cron)].count(True) > 1:
+        raise ValueError("Set only one of days, a date, or a cron expression")
+    if cron and cells.get("time"):
+        raise ValueError("Cron schedules take their times from the expression, leave time empty")
+    on_date = _parse_date(on)
+    starting = _parse_date(cells.get("starting", ""))
+
+    timezone = cells.get("timezone") or settings.default_timezone
+    try:
+        ZoneInfo(timezone)
+    except (ZoneInfoNotFoundError, ValueError) as e:
+        raise ValueError(f"Unknown timezone {timezone}") from e
+
+    data = {
+        "name": cells["name"],
+        "description": cells.get("description", ""),
+        "voice_channel": cells.get("voice_channel") or settings.discord_voice_channel,
+        "notify_channel": cells.get("notify_channel") or settings.discord_notify_channel,
+        "days": days,
+        "date": on_date,
+        "cron": cron,
+        "time": _parse_time(cells.get("time", "")) if not cron else "",
+        "every_weeks": cells.get("every_weeks") or 1,
+        "starting": starting,
+        "timezone": timezone,
+        "duration_minutes": cells["duration_minutes"],
+        "enabled": _parse_bool(cells.get("enabled", "yes") or "yes"),
+        "capacity": cells.get("capacity") or None,
+        "owners": _split_ids(cells.get("owners", "")),
+        "co_hosts": _split_ids(cells.get("co_hosts", "")),
+        "guild_id": cells.get("guild_id") or None,
+    }
+    if not data["name"]:
+        raise ValueError("Name is empty")
+
+    try:
+        return Schedule.model_validate(data)
+    except ValidationError as e:
+        error = e.errors()[0]
+        raise ValueError(f"{'.'.join(str(part) for part in error['loc'])}: {error['msg']}") from e
+
+
+def schedule_cells(schedule: Schedule) -> dict[str, str]:
+    """Write a schedule as the cells of a row, the reverse of `parse_row`."""
+    return {
+        "name": schedule.name,
+        "description": schedule.description,
+        "voice_channel": schedule.voice_channel,
+        "notify_channel": schedule.notify_channel,
+        "days": ",".join(schedule.days),
+        "date": schedule.date.isoformat() if schedule.date else "",
+        "cron": schedule.cron,
+        "time": schedule.time,
+        "every_weeks": str(schedule.every_weeks),
+        "starting": schedule.starting.isoformat() if schedule.starting else "",
+        "timezone": schedule.timezone,
+        "duration_minutes": str(schedule.duration_minutes),
+        "enabled": "yes" if schedule.enabled else "no",
+        "capacity": str(schedule.capacity) if schedule.capacity else "",
+        "owners": ",".join(str(owner) for owner in schedule.owners),
+        "co_hosts": ",".join(str(co_host) for co_host in schedule.co_hosts),
+        "guild_id": str(schedule.guild_id) if schedule.guild_id else "",
+    }
+
+
+def _parse_date(text: str) -> date | None:
+    """Read an optional ISO date."""
+    try:
+        return date.fromisoformat(text) if text else None
+    except ValueError as e:
+        raise ValueError(f"Unknown date {text!r}, use e.g. 2025-03-14") from e
+
+
+def _parse_time(text: str) -> str:
+    """Normalize a time of day to 24-hour HH:MM."""
+    for time_format in TIME_FORMATS:
+        try:
+            return datetime.strptime(text.upper(), time_format).strftime("%H:%M")
+        except ValueError:
+            continue
+    raise ValueError(f"Unknown time {text!r}, use e.g. 19:00 or 7:00 PM")
+
+
+def _parse_bool(text: str) -> bool:
+    """Read a yes/no cell."""
+    value = text.lower()
+    if value not in TRUE | FALSE:
+        raise ValueError(f"Enabled must be yes or no, not {text!r}")
+    return value in TRUE
+
+
+def _split_ids(text: str) -> list[str]:
+    """Split a cell of user IDs separated by commas or spaces."""
+    return [part for part in re.split(r"[\s,;]+", text) if part]
+
+
+def merge_schedules(
+    config: ScheduleConfig, sheet: SheetImport, imported: set[str]
+) -> ScheduleConfig:
+    """Apply a sheet to a schedules config.
+
+    Schedules in the sheet replace the fields of the same-named schedule the
+    sheet has columns for, keeping the rest (templates, mirrors, etc.), or are
+    added. Schedules imported before but gone from the sheet are removed;
+    schedules only ever defined in the file are kept.
+    """
+    by_name = {schedule.name.lower(): schedule for schedule in sheet.schedules}
+    schedules = []
+    for schedule in config.schedules:
+        row = by_name.pop(schedule.name.lower(), None)
+        if row is not None:
+            schedules.append(schedule.model_copy(update=row.model_dump(include=sheet.columns)))
+        elif schedule.name.lower() not in imported:
+            schedules.append(schedule)
+    schedules += by_name.values()
+    return config.model_copy(update={"schedules": schedules})
+
+
+def describe_changes(old: list[Schedule], new: list[Schedule]) -> list[str]:
+    """List schedules added, removed, or changed, with the changed fields."""
+    before = {schedule.name.lower(): schedule for schedule in old}
+    after = {schedule.name.lower(): schedule for schedule in new}
+
+    changes = []
+    for key, schedule in after.items():
+        if key not in before:
+            changes.append(f"➕ **{schedule.name}** added ({schedule_when(schedule)})")
+            continue
+        previous = before[key].model_dump()
+        current = schedule.model_dump()
+        changed = [
+            f"{name} `{_format(previous[name])}` → `{_format(value)}`"
+            for name, value in current.items()
+            if previous[name] != value
+        ]
+        if changed:
+            changes.append(f"✏️ **{schedule.name}**: {'; '.join(changed)}")
+    changes += [
+        f"➖ **{schedule.name}** removed" for key, schedule in before.items() if key not in after
+    ]
+    return changes
+
+
+def _format(value: object) -> str:
+    """Show a field's value in a change summary."""
+    if isinstance(value, list):
+        return ", ".join(str(item) for item in value) or "none"
+    return "none" if value is None or value == "" else str(value)
+
+
+async def fetch_sheet(url: str) -> str:
+    """Download a sheet as CSV.
+
+    Raises:
+        aiohttp.ClientError: If the download fails.
+    """
+    timeout = aiohttp.ClientTimeout(total=30)
+    async with aiohttp.ClientSession(timeout=timeout) as session:
+        async with session.get(csv_url(url)) as response:
+            response.raise_for_status()
+            return await response.text(encoding="utf-8")
+
+
+class ImportedSchedules:
+    """Remembers which schedules came from the sheet, and the last errors reported.
+
+    Schedules are only removed when they disappear from the sheet if they were
+    imported from it, so schedules written into the file by hand are safe.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    @property
+    def names(self) -> set[str]:
+        """Lowercase names of the schedules imported from the sheet."""
+        return set(self._store.get(NAMESPACE, "imported") or [])
+
+    def set_names(self, names: set[str]) -> None:
+        """Record the schedules the sheet now has."""
+        self._store.set(NAMESPACE, "imported", sorted(names))
+
+    def errors_changed(self, errors: list[str]) -> bool:
+        """Record the sheet's errors, returning whether they differ from last time."""
+        if (self._store.get(NAMESPACE, "errors") or []) == errors:
+            return False
+        self._store.set(NAMESPACE, "errors", errors)
+        return True
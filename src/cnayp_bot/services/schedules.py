@@ -0,0 +1,278 @@
+"""Recurring events defined in the schedules file."""
+
+import logging
+import re
+from collections.abc import Set
+from datetime import date, datetime, time, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from ..helpers.config_file import read_config_file, write_config_file
+from ..helpers.cron import parse_cron
+from ..helpers.discord_enums import RecurrenceFrequency
+from ..models import Schedule, ScheduleConfig
+from .calendar import CalendarEvent
+from .config_snapshots import ConfigSnapshots
+
+logger = logging.getLogger(__name__)
+
+WEEKDAYS = ["monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"]
+
+
+def load_schedule_config(path: Path) -> ScheduleConfig:
+    """Load the schedules file, JSON or the TOML config file, or an empty config if missing."""
+    if not path.exists():
+        logger.info("Schedules file not found, no recurring events: %s", path)
+        return ScheduleConfig()
+
+    return ScheduleConfig.model_validate(read_config_file(path))
+
+
+def save_schedule_config(config: ScheduleConfig, path: Path) -> None:
+    """Write the schedules file atomically."""
+    write_config_file(path, config.model_dump(mode="json"))
+
+
+def starter_schedule_config(
+    notify_channel: str, voice_channel: str, timezone: str
+) -> ScheduleConfig:
+    """Return a starter config with one disabled example schedule to edit."""
+    return ScheduleConfig(
+        digest_channel=notify_channel,
+        schedules=[
+            Schedule(
+                name="Weekly Study Session",
+                description="Edit this schedule and set enabled to true to start announcing it.",
+                voice_channel=voice_channel,
+                notify_channel=notify_channel,
+                days=["wednesday"],
+                time="19:00",
+                timezone=timezone,
+                duration_minutes=90,
+                enabled=False,
+            )
+        ],
+    )
+
+
+def schedule_occurrences(
+    schedule: Schedule, start: datetime, end: datetime, holidays: Set[date] = frozenset()
+) -> list[CalendarEvent]:
+    """Expand a schedule into occurrences overlapping [start, end).
+
+    Like Google Calendar, this includes occurrences that are already in
+    progress at `start`. One-off schedules have at most their dated occurrence.
+    Nothing runs on the schedule's `exclude_dates` or on `holidays`.
+    """
+    tz = ZoneInfo(schedule.timezone)
+    duration = timedelta(minutes=schedule.duration_minutes)
+
+    events = []
+    day = (start - duration).astimezone(tz).date()
+    while day <= end.astimezone(tz).date():
+        skipped = day in holidays or day in schedule.exclude_dates
+        times = [] if skipped else _times_on(schedule, day)
+        for at in times:
+            occurrence = datetime.combine(day, at, tzinfo=tz)
+            if occurrence + duration > start and occurrence < end:
+                # Cron schedules can run several times a day, each needing its own ID
+                key = f"{day.isoformat()}-{at:%H%M}" if len(times) > 1 else day.isoformat()
+                events.append(_to_event(schedule, key, occurrence))
+        day += timedelta(days=1)
+    return events
+
+
+def _times_on(schedule: Schedule, day: date) -> list[time]:
+    """Return the local times a schedule runs at on a day, if any."""
+    if schedule.cron:
+        cron = parse_cron(schedule.cron)
+        return cron.times() if cron.matches(day) else []
+
+    if schedule.date:
+        runs = day == schedule.date
+    else:
+        runs = WEEKDAYS[day.weekday()] in {
+            weekday.lower() for weekday in schedule.days
+        } and _runs_in_week(schedule, day)
+    hour, minute = (int(part) for part in schedule.time.split(":"))
+    return [time(hour, minute)] if runs else []
+
+
+def _runs_in_week(schedule: Schedule, day: date) -> bool:
+    """Check whether a weekly schedule runs in a day's week, counted from its `starting` date."""
+    if schedule.starting is None:
+        return True
+    if day < schedule.starting:
+        return False
+    weeks = (_monday(day) - _monday(schedule.starting)).days // 7
+    return weeks % schedule.every_weeks == 0
+
+
+def _monday(day: date) -> date:
+    """Return the Monday starting a day's week."""
+    return day - timedelta(days=day.weekday())
+
+
+def schedule_when(schedule: Schedule) -> str:
+    """Describe when a schedule runs, e.g. "Monday, Wednesday at 19:00"."""
+    if schedule.cron:
+        return f"cron `{schedule.cron}`"
+    if schedule.date:
+        return f"{schedule.date.isoformat()} at {schedule.time}"
+    when = f"{', '.join(day.capitalize() for day in schedule.days)} at {schedule.time}"
+    if schedule.every_weeks > 1:
+        when += f", every {schedule.every_weeks} weeks from {schedule.starting.isoformat()}"
+    return when
+
+
+def is_private(event: CalendarEvent) -> bool:
+    """Check whether an event comes from a private schedule, shown only to its audience."""
+    return event.schedule is not None and event.schedule.visibility == "private"
+
+
+def is_visible_to(event: CalendarEvent, role_names: Set[str]) -> bool:
+    """Check whether a member holding `role_names` may see an event: public, or its audience."""
+    return not is_private(event) or event.schedule.audience_role in role_names
+
+
+def one_off_end(schedule: Schedule) -> datetime | None:
+    """Return when a one-off schedule's event ends, or None for a weekly schedule."""
+    if schedule.date is None:
+        return None
+    hour, minute = (int(part) for part in schedule.time.split(":"))
+    start = datetime.combine(schedule.date, time(hour, minute), tzinfo=ZoneInfo(schedule.timezone))
+    return start + timedelta(minutes=schedule.duration_minutes)
+
+
+def recurrence_rule(schedule: Schedule, start: datetime) -> dict:
+    """Return the Discord recurrence rule repeating a schedule from its occurrence at `start`."""
+    weekdays = sorted({WEEKDAYS.index(day.lower()) for day in schedule.days})
+    rule = {"start": start.isoformat(), "interval": 1}
+    if len(weekdays) == 1:
+        rule["interval"] = schedule.every_weeks
+        return rule | {"frequency": RecurrenceFrequency.WEEKLY, "by_weekday": weekdays}
+    if len(weekdays) == len(WEEKDAYS):
+        return rule | {"frequency": RecurrenceFrequency.DAILY}
+    return rule | {"frequency": RecurrenceFrequency.DAILY, "by_weekday": weekdays}
+
+
+def recurrence_pattern(schedule: Schedule) -> str:
+    """Describe when a schedule repeats, to notice changes that need a new recurring event."""
+    days = ",".join(sorted(day.lower() for day in schedule.days))
+    pattern = f"{days} {schedule.time} {schedule.timezone} {schedule.duration_minutes}"
+    if schedule.every_weeks > 1:
+        pattern += f" every {schedule.every_weeks} weeks from {schedule.starting.isoformat()}"
+    return pattern
+
+
+def schedule_slug(schedule: Schedule) -> str:
+    """Return a schedule's name in lowercase letters, digits, and dashes, for IDs."""
+    return re.sub(r"[^a-z0-9]+", "-", schedule.name.lower()).strip("-")
+
+
+def _to_event(schedule: Schedule, key: str, start_time: datetime) -> CalendarEvent:
+    return CalendarEvent(
+        id=f"schedule-{schedule_slug(schedule)}-{key}",
+        name=schedule.name,
+        description=schedule.description,
+        start_time=start_time,
+        end_time=start_time + timedelta(minutes=schedule.duration_minutes),
+        timezone=schedule.timezone,
+        schedule=schedule,
+    )
+
+
+class ScheduleService:
+    """Provides schedules-file occurrences alongside Google Calendar events."""
+
+    def __init__(self, path: Path, snapshots: ConfigSnapshots | None = None) -> None:
+        self.path = path
+        self.snapshots = snapshots
+        self.config = load_schedule_config(path)
+        self._mtime = self._modified()
+        # Days of the holiday calendar, downloaded by the schedules cog
+        self.calendar_holidays: set[date] = set()
+
+    @property
+    def holidays(self) -> set[date]:
+        """Return the days no schedule runs on, from the schedules file and holiday calendar."""
+        return set(self.config.holidays) | self.calendar_holidays
+
+    def replace(self, config: ScheduleConfig, reason: str) -> None:
+        """Save a new config to the schedules file and use it from now on.
+
+        The config it replaces is snapshotted first, with `reason` saying what
+        changed it, so the change can be rolled back.
+        """
+        if self.snapshots:
+            self.snapshots.save(self.config, reason)
+        save_schedule_config(config, self.path)
+        self.config = config
+        self._mtime = self._modified()
+
+    def consume_finished(self, now: datetime) -> list[str]:
+        """Disable the one-off schedules whose event is over, saving the file.
+
+        Returns:
+            The names of the schedules disabled.
+        """
+        finished = [
+            schedule
+            for schedule in self.config.schedules
+            if schedule.enabled and schedule.date and one_off_end(schedule) <= now
+        ]
+        if not finished:
+            return []
+
+        self.replace(
+            self.config.model_copy(
+                update={
+                    "schedules": [
+                        s.model_copy(update={"enabled": False}) if s in finished else s
+                        for s in self.config.schedules
+                    ]
+                }
+            ),
+            "one-off schedules ended",
+        )
+        return [schedule.name for schedule in finished]
+
+    def changed_on_disk(self) -> bool:
+        """Check whether the file was edited since it was last loaded or saved."""
+        return self._modified() != self._mtime
+
+    def reload(self) -> None:
+        """Load the schedules file again, keeping the current config if it's invalid.
+
+        A file that fails to load isn't reported as changed again until it's
+        edited once more.
+
+        Raises:
+            OSError: If the file can't be read.
+            ValueError: If the file isn't valid JSON or a valid config.
+        """
+        self._mtime = self._modified()
+        self.config = load_schedule_config(self.path)
+
+    def _modified(self) -> float | None:
+        """Return when the file was last modified, or None if it doesn't exist."""
+        try:
+            return self.path.stat().st_mtime
+        except FileNotFoundError:
+            return None
+
+    def get_upcoming_events(self, hours_ahead: int = 24) -> list[CalendarEvent]:
+        """Return enabled schedule occurrences in the next `hours_ahead` hours."""
+        now = datetime.now(ZoneInfo("UTC"))
+        return self.get_events_between(now, now + timedelta(hours=hours_ahead))
+
+    def get_events_between(self, start: datetime, end: datetime) -> list[CalendarEvent]:
+        """Return enabled schedule occurrences overlapping [start, end), by start time."""
+        holidays = self.holidays
+        events = [
+            event
+            for schedule in self.config.schedules
+            if schedule.enabled
+            for event in schedule_occurrences(schedule, start, end, holidays)
+        ]
+        return sorted(events, key=lambda event: event.start_time)
@@ -0,0 +1,44 @@
+"""Google Sheets service for writing report tables."""
+
+import logging
+
+from googleapiclient.discovery import build
+
+from .calendar import get_credentials
+
+logger = logging.getLogger(__name__)
+
+SCOPES = ["https://www.googleapis.com/auth/spreadsheets"]
+
+
+class SheetsService:
+    """Writes tables to Google Sheets with the bot's Google credentials.
+
+    The service account (or ADC identity) needs edit access to the sheet.
+    """
+
+    def __init__(self) -> None:
+        self._service = None
+
+    def _get_service(self):
+        """Get or create the Google Sheets service."""
+        if self._service is None:
+            self._service = build("sheets", "v4", credentials=get_credentials(SCOPES))
+
+        return self._service
+
+    def replace_values(self, spreadsheet_id: str, tab: str, rows: list[list]) -> None:
+        """Replace everything in a tab with `rows`.
+
+        Raises:
+            googleapiclient.errors.HttpError: If the sheet can't be written.
+        """
+        values = self._get_service().spreadsheets().values()
+        values.clear(spreadsheetId=spreadsheet_id, range=tab, body={}).execute()
+        values.update(
+            spreadsheetId=spreadsheet_id,
+            range=f"{tab}!A1",
+            valueInputOption="RAW",
+            body={"values": rows},
+        ).execute()
+        logger.info("Wrote %d rows to sheet tab %s", len(rows), tab)
@@ -0,0 +1,136 @@
+"""Time slot polls that find when the members of a role can meet."""
+
+import uuid
+from collections import Counter
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+from .store import Store
+
+# Poll ID -> {"name", "role_id", "organizer_id", "channel_id", "message_id", "duration",
+# "slots": [ISO start], "available": [[user ID]], "submission_id"}
+SLOT_POLLS = "slot_polls"
+
+# Local hours a slot must fall within to suit a participant: starting at 9:00,
+# ending by 22:00
+DAY_START = 9
+DAY_END = 22
+
+# Most slots proposed on a single day, so the poll covers more than one day
+SLOTS_PER_DAY = 3
+
+
+def suits(start: datetime, duration: timedelta, tz: ZoneInfo) -> bool:
+    """Check whether a slot falls within waking hours in a timezone."""
+    local_start = start.astimezone(tz)
+    local_end = (start + duration).astimezone(tz)
+    day_end = local_start.replace(hour=DAY_END, minute=0, second=0, microsecond=0)
+    return local_start.hour >= DAY_START and local_end <= day_end
+
+
+def propose_slots(
+    timezones: list[ZoneInfo], now: datetime, duration: timedelta, days: int, count: int
+) -> list[datetime]:
+    """Propose the slots that suit the most participants, earliest first.
+
+    Candidates start on the hour over the next `days` days. Ties go to the
+    earlier slot, slots that suit nobody are left out, and at most
+    `SLOTS_PER_DAY` are picked on one UTC day.
+    """
+    first = now.replace(minute=0, second=0, microsecond=0) + timedelta(hours=1)
+    scores = Counter(timezones)
+    candidates = []
+    for hour in range(days * 24):
+        start = first + timedelta(hours=hour)
+        score = sum(n for tz, n in scores.items() if suits(start, duration, tz))
+        if score:
+            candidates.append((score, start))
+
+    picked: list[datetime] = []
+    per_day: Counter = Counter()
+    for _, start in sorted(candidates, key=lambda candidate: (-candidate[0], candidate[1])):
+        if len(picked) == count:
+            break
+        if per_day[start.date()] < SLOTS_PER_DAY:
+            per_day[start.date()] += 1
+            picked.append(start)
+    return sorted(picked)
+
+
+def rank_slots(available: list[list[int]]) -> list[int]:
+    """Return the indexes of the slots someone can make, most available first.
+
+    Ties go to the earlier slot.
+    """
+    ranked = sorted(range(len(available)), key=lambda i: (-len(available[i]), i))
+    return [i for i in ranked if available[i]]
+
+
+class SlotFinder:
+    """Tracks the slot polls and who said they can make each slot."""
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def create(
+        self,
+        name: str,
+        role_id: int,
+        organizer_id: int,
+        channel_id: int,
+        duration: timedelta,
+        slots: list[datetime],
+    ) -> str:
+        """Start a poll over the proposed slots for an event, and return its ID."""
+        poll_id = uuid.uuid4().hex[:8]
+        self._store.set(
+            SLOT_POLLS,
+            poll_id,
+            {
+                "name": name,
+                "role_id": role_id,
+                "organizer_id": organizer_id,
+                "channel_id": channel_id,
+                "message_id": None,
+                "duration": int(duration.total_seconds() // 60),
+                "slots": [slot.isoformat() for slot in slots],
+                "available": [[] for _ in slots],
+                "submission_id": None,
+            },
+        )
+        return poll_id
+
+    def get(self, poll_id: str) -> dict | None:
+        """Return a poll, or None if it's finished or unknown."""
+        return self._store.get(SLOT_POLLS, poll_id)
+
+    def set_message(self, poll_id: str, message_id: int) -> None:
+        """Record the message the poll was posted in."""
+        poll = self._store.get(SLOT_POLLS, poll_id)
+        self._store.set(SLOT_POLLS, poll_id, poll | {"message_id": message_id})
+
+    def toggle(self, poll_id: str, index: int, user_id: int) -> bool:
+        """Mark a member as available for a slot, or unmark them.
+
+        Returns:
+            True if the member is now available for the slot.
+        """
+        poll = self._store.get(SLOT_POLLS, poll_id)
+        available = poll["available"][index]
+        if user_id in available:
+            available.remove(user_id)
+        else:
+            available.append(user_id)
+        self._store.set(SLOT_POLLS, poll_id, poll)
+        return user_id in available
+
+    def set_submission(self, poll_id: str, submission_id: str) -> None:
+        """Record the event created from the poll's winning slot."""
+        poll = self._store.get(SLOT_POLLS, poll_id)
+        self._store.set(SLOT_POLLS, poll_id, poll | {"submission_id": submission_id})
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop the polls whose slots have all passed."""
+        for poll_id, poll in self._store.items(SLOT_POLLS).items():
+            if all(datetime.fromisoformat(slot) < now for slot in poll["slots"]):
+                self._store.delete(SLOT_POLLS, poll_id)
@@ -0,0 +1,61 @@
+"""Slowmode set on channels while events run, and the settings to restore."""
+
+from datetime import datetime
+
+from .store import Store
+
+# Channel ID -> {"original": seconds, "delay": seconds, "until": ...}
+SLOWMODE = "slowmode"
+
+
+class SlowmodeOverrides:
+    """Tracks channels slowed down for live events.
+
+    The channel's own slowmode is kept when an event starts and restored once
+    the last overlapping event in that channel ends. Overlapping events share
+    one override at the highest delay any of them asks for.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def start(self, channel_id: int, current: int, delay: int, until: datetime) -> bool:
+        """Record an event's slowmode on a channel.
+
+        Args:
+            current: The channel's slowmode right now, restored afterwards.
+            delay: The slowmode the event asks for, in seconds.
+            until: When the event ends.
+
+        Returns:
+            True if the channel's slowmode needs to change.
+        """
+        override = self._store.get(SLOWMODE, str(channel_id))
+        if override is None:
+            self._store.set(
+                SLOWMODE,
+                str(channel_id),
+                {"original": current, "delay": delay, "until": until.isoformat()},
+            )
+            return current != delay
+
+        changed = delay > override["delay"]
+        latest = max(until, datetime.fromisoformat(override["until"]))
+        self._store.set(
+            SLOWMODE,
+            str(channel_id),
+            override | {"delay": max(delay, override["delay"]), "until": latest.isoformat()},
+        )
+        return changed
+
+    def expired(self, now: datetime) -> dict[int, int]:
+        """Return the original slowmode of channels whose events all ended."""
+        return {
+            int(channel_id): override["original"]
+            for channel_id, override in self._store.items(SLOWMODE).items()
+            if datetime.fromisoformat(override["until"]) <= now
+        }
+
+    def remove(self, channel_id: int) -> None:
+        """Forget a channel's override once its slowmode is restored."""
+        self._store.delete(SLOWMODE, str(channel_id))
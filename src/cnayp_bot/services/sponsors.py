@@ -0,0 +1,42 @@
+"""Sponsor blurb rotation and impression counts."""
+
+from ..models import Sponsor
+from .store import Store
+
+SPONSORS = "sponsors"
+ROTATION = "rotation"  # Index of the next sponsor
+IMPRESSIONS = "impressions"  # Sponsor name -> times shown
+LAST_POST = "last_post"  # Date of the last scheduled post
+
+
+def sponsor_line(sponsor: Sponsor) -> str:
+    """Format a sponsor's blurb for a message."""
+    return f"🤝 Thanks to our sponsor **{sponsor.name}**: {sponsor.blurb}"
+
+
+class SponsorRotation:
+    """Shows sponsors in turn and counts how often each one was shown.
+
+    The rotation only advances when a blurb is actually posted, so every
+    sponsor gets the same share of messages.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def next(self, sponsors: list[Sponsor]) -> Sponsor | None:
+        """Return the sponsor whose turn it is, or None without sponsors."""
+        if not sponsors:
+            return None
+        return sponsors[self._store.get(SPONSORS, ROTATION, 0) % len(sponsors)]
+
+    def record_impression(self, sponsor: Sponsor, sponsors: list[Sponsor]) -> None:
+        """Count a posted blurb and pass the turn to the following sponsor."""
+        impressions = self._store.get(SPONSORS, IMPRESSIONS, {})
+        impressions[sponsor.name] = impressions.get(sponsor.name, 0) + 1
+        self._store.set(SPONSORS, IMPRESSIONS, impressions)
+        self._store.set(SPONSORS, ROTATION, (sponsors.index(sponsor) + 1) % len(sponsors))
+
+    def impressions(self) -> dict[str, int]:
+        """Return how often each sponsor was shown."""
+        return dict(self._store.get(SPONSORS, IMPRESSIONS, {}))
@@ -0,0 +1,176 @@
+"""Incidents from public status pages, such as Discord's own."""
+
+import logging
+from dataclasses import dataclass, field
+from typing import Any, Literal
+
+import aiohttp
+
+from ..config import StatusPage
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# "page:incident ID" -> {"name", "status", "update_id", "url", "components"}
+STATUS_INCIDENTS = "status_incidents"
+
+RESOLVED = {"resolved", "postmortem", "completed"}
+
+# Incidents on this host with these components mean Discord's API is degraded
+DISCORD_STATUS_HOST = "discordstatus.com"
+DISCORD_API_COMPONENTS = ("api", "gateway")
+
+Change = Literal["started", "updated", "resolved"]
+
+
+@dataclass
+class Incident:
+    """An incident, normalized across status page providers."""
+
+    id: str
+    name: str
+    status: str
+    impact: str = "none"
+    url: str = ""
+    update: str = ""  # Text of the latest update
+    update_id: str = ""
+    components: list[str] = field(default_factory=list)
+
+    @property
+    def resolved(self) -> bool:
+        """Whether the incident is over."""
+        return self.status in RESOLVED
+
+
+def parse_statuspage(data: dict[str, Any]) -> list[Incident]:
+    """Read the recent incidents of a Statuspage `/api/v2/incidents.json`."""
+    incidents = []
+    for incident in data.get("incidents", []):
+        updates = incident.get("incident_updates") or []
+        latest = updates[0] if updates else {}
+        incidents.append(
+            Incident(
+                id=incident["id"],
+                name=incident["name"],
+                status=incident["status"],
+                impact=incident.get("impact") or "none",
+                url=incident.get("shortlink") or "",
+                update=latest.get("body", ""),
+                update_id=latest.get("id", incident["status"]),
+                components=[component["name"] for component in incident.get("components", [])],
+            )
+        )
+    return incidents
+
+
+def parse_instatus(data: dict[str, Any]) -> list[Incident]:
+    """Read the active incidents of an Instatus `/summary.json`."""
+    return [
+        Incident(
+            id=incident["id"],
+            name=incident["name"],
+            status=incident["status"].lower(),
+            impact=(incident.get("impact") or "none").lower(),
+            url=incident.get("url") or "",
+            update_id=incident["status"].lower(),
+        )
+        for incident in data.get("activeIncidents", [])
+    ]
+
+
+async def fetch_incidents(page: StatusPage) -> list[Incident]:
+    """Fetch a status page's incidents.
+
+    Raises:
+        aiohttp.ClientError: If the page can't be fetched.
+    """
+    base = page.url.rstrip("/")
+    if page.provider == "instatus":
+        url, parse = f"{base}/summary.json", parse_instatus
+    else:
+        url, parse = f"{base}/api/v2/incidents.json", parse_statuspage
+
+    timeout = aiohttp.ClientTimeout(total=10)
+    async with aiohttp.ClientSession(timeout=timeout) as session:
+        async with session.get(url) as response:
+            response.raise_for_status()
+            return parse(await response.json(content_type=None))
+
+
+def is_discord_page(page: StatusPage) -> bool:
+    """Check whether a status page is Discord's own."""
+    return DISCORD_STATUS_HOST in page.url
+
+
+def is_api_incident(incident: Incident) -> bool:
+    """Check whether an unresolved incident on Discord's status page affects its API.
+
+    Incidents that don't list components might affect anything, so they count.
+    """
+    if incident.resolved:
+        return False
+    if not incident.components:
+        return True
+    return any(
+        part in component.lower()
+        for component in incident.components
+        for part in DISCORD_API_COMPONENTS
+    )
+
+
+class IncidentTracker:
+    """Finds the incidents that started, changed, or resolved since the last poll.
+
+    Incidents already resolved the first time they're seen are skipped, so
+    adding a page doesn't replay its history. An incident that disappears from
+    a page is taken as resolved, since some providers only list active ones.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+        self.discord_incident: Incident | None = None  # Set while Discord's API is degraded
+
+    def changes(self, page: str, incidents: list[Incident]) -> list[tuple[Change, Incident]]:
+        """Record a page's incidents, returning the changes since the last poll."""
+        changes: list[tuple[Change, Incident]] = []
+        current = set()
+        for incident in incidents:
+            key = f"{page}:{incident.id}"
+            current.add(key)
+            known = self._store.get(STATUS_INCIDENTS, key)
+            if known is None and incident.resolved:
+                continue
+            if known and known["update_id"] == incident.update_id:
+                continue
+
+            if incident.resolved:
+                changes.append(("resolved", incident))
+                self._store.delete(STATUS_INCIDENTS, key)
+                continue
+            changes.append(("started" if known is None else "updated", incident))
+            self._store.set(
+                STATUS_INCIDENTS,
+                key,
+                {
+                    "name": incident.name,
+                    "status": incident.status,
+                    "update_id": incident.update_id,
+                    "url": incident.url,
+                    "components": incident.components,
+                },
+            )
+
+        prefix = f"{page}:"
+        for key, known in self._store.items(STATUS_INCIDENTS).items():
+            if not key.startswith(prefix) or key in current:
+                continue
+            incident = Incident(
+                id=key.removeprefix(prefix),
+                name=known["name"],
+                status="resolved",
+                url=known["url"],
+                components=known["components"],
+            )
+            changes.append(("resolved", incident))
+            self._store.delete(STATUS_INCIDENTS, key)
+        return changes
@@ -0,0 +1,179 @@
+"""Persistent key-value store backed by a SQLite database or a JSON file."""
+
+import json
+import logging
+import os
+import sqlite3
+from datetime import datetime
+from pathlib import Path
+from typing import Any, Protocol
+from zoneinfo import ZoneInfo
+
+logger = logging.getLogger(__name__)
+
+# Store paths with these suffixes are SQLite databases; any other is a JSON file
+SQLITE_SUFFIXES = {".db", ".sqlite", ".sqlite3"}
+
+Data = dict[str, dict[str, Any]]
+
+
+class StoreBackend(Protocol):
+    """Where a store's values are persisted."""
+
+    def load(self) -> Data:
+        """Read every namespace's values."""
+        ...
+
+    def save(self, data: Data, namespace: str, key: str) -> None:
+        """Persist `data[namespace][key]`, or that it was deleted if it's gone."""
+        ...
+
+
+class JsonBackend:
+    """Keeps the whole store in a JSON file.
+
+    Every write rewrites the file through a temporary file and an atomic
+    rename, so a crash never leaves it truncated.
+    """
+
+    def __init__(self, path: Path) -> None:
+        self.path = path
+
+    def load(self) -> Data:
+        """Read the file, or nothing if it doesn't exist yet."""
+        if not self.path.exists():
+            logger.info("Store file not found, starting empty: %s", self.path)
+            return {}
+
+        with self.path.open(encoding="utf-8") as f:
+            data = json.load(f)
+        logger.info("Loaded store from %s", self.path)
+        return data
+
+    def save(self, data: Data, namespace: str, key: str) -> None:
+        """Write the whole store to the file."""
+        self.path.parent.mkdir(parents=True, exist_ok=True)
+        tmp_path = self.path.with_suffix(self.path.suffix + ".tmp")
+        with tmp_path.open("w", encoding="utf-8") as f:
+            json.dump(data, f, indent=2, sort_keys=True)
+        os.replace(tmp_path, self.path)
+
+
+class SqliteBackend:
+    """Keeps each value in its own row of a SQLite database.
+
+    Writes only touch the value that changed, in a transaction, and each row
+    records when it was last updated. A database created next to an existing
+    JSON store (`store.db` beside `store.json`) imports it on first use.
+    """
+
+    def __init__(self, path: Path) -> None:
+        self.path = path
+        path.parent.mkdir(parents=True, exist_ok=True)
+        self._connection = sqlite3.connect(path, isolation_level=None)
+        # Readers, e.g. `sqlite3` in a debug session, don't block the bot's writes
+        self._connection.execute("PRAGMA journal_mode=WAL")
+        self._connection.execute(
+            "CREATE TABLE IF NOT EXISTS store ("
+            " namespace TEXT NOT NULL,"
+            " key TEXT NOT NULL,"
+            " value TEXT NOT NULL,"
+            " updated_at TEXT NOT NULL,"
+            " PRIMARY KEY (namespace, key))"
+        )
+
+    def load(self) -> Data:
+        """Read every row, importing the JSON store beside the database if it's empty."""
+        rows = self._connection.execute("SELECT namespace, key, value FROM store").fetchall()
+        if not rows:
+            return self._import_json(self.path.with_suffix(".json"))
+
+        data: Data = {}
+        for namespace, key, value in rows:
+            data.setdefault(namespace, {})[key] = json.loads(value)
+        logger.info("Loaded store from %s", self.path)
+        return data
+
+    def save(self, data: Data, namespace: str, key: str) -> None:
+        """Write or delete the row of one value."""
+        values = data.get(namespace, {})
+        if key not in values:
+            self._connection.execute(
+                "DELETE FROM store WHERE namespace = ? AND key = ?", (namespace, key)
+            )
+            return
+
+        updated_at = datetime.now(ZoneInfo("UTC")).isoformat()
+        self._connection.execute(
+            "INSERT INTO store (namespace, key, value, updated_at) VALUES (?, ?, ?, ?)"
+            " ON CONFLICT (namespace, key)"
+            " DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at",
+            (namespace, key, json.dumps(values[key]), updated_at),
+        )
+
+    def _import_json(self, path: Path) -> Data:
+        """Copy a JSON store into the database, e.g. when switching to SQLite."""
+        if not path.exists():
+            logger.info("Store database is empty, starting empty: %s", self.path)
+            return {}
+
+        data = JsonBackend(path).load()
+        now = datetime.now(ZoneInfo("UTC")).isoformat()
+        with self._connection:
+            self._connection.execute("BEGIN")
+            self._connection.executemany(
+                "INSERT INTO store (namespace, key, value, updated_at) VALUES (?, ?, ?, ?)",
+                [
+                    (namespace, key, json.dumps(value), now)
+                    for namespace, values in data.items()
+                    for key, value in values.items()
+                ],
+            )
+        logger.warning("Imported %s into %s; it's no longer updated", path, self.path)
+        return data
+
+
+def open_backend(path: Path) -> StoreBackend:
+    """Return the backend for a store path: SQLite for `.db` files, else JSON."""
+    if path.suffix in SQLITE_SUFFIXES:
+        return SqliteBackend(path)
+    return JsonBackend(path)
+
+
+class Store:
+    """Small namespaced key-value store that survives restarts.
+
+    Values must be JSON serializable. They're all kept in memory, and each
+    write is persisted right away to the backend `path` picks: a SQLite
+    database for `.db` paths, otherwise a JSON file.
+    """
+
+    def __init__(self, path: Path) -> None:
+        self._backend = open_backend(path)
+        self._data: Data = self._backend.load()
+
+    def reload(self) -> None:
+        """Read every value again, e.g. those another replica wrote meanwhile."""
+        self._data = self._backend.load()
+
+    def get(self, namespace: str, key: str, default: Any = None) -> Any:
+        """Get a value, or `default` if it doesn't exist."""
+        return self._data.get(namespace, {}).get(key, default)
+
+    def set(self, namespace: str, key: str, value: Any) -> None:
+        """Set a value and persist it."""
+        self._data.setdefault(namespace, {})[key] = value
+        self._backend.save(self._data, namespace, key)
+
+    def delete(self, namespace: str, key: str) -> None:
+        """Delete a value if it exists."""
+        if self._data.get(namespace, {}).pop(key, None) is not None:
+            self._backend.save(self._data, namespace, key)
+
+    def namespaces(self) -> dict[str, int]:
+        """Return each namespace with how many keys it has."""
+        return {namespace: len(values) for namespace, values in sorted(self._data.items())}
+
+    def items(self, namespace: str) -> dict[str, Any]:
+        """Return a copy of all values in a namespace."""
+        return dict(self._data.get(namespace, {}))
@@ -0,0 +1,127 @@
+"""Approval queue for events submitted through the API."""
+
+import uuid
+from datetime import datetime, timedelta
+from typing import Any
+from zoneinfo import ZoneInfo
+
+from ..models import EventSubmission
+from .calendar import CalendarEvent
+from .store import Store
+
+SUBMISSIONS = "event_submissions"
+
+# Prefix of event IDs for approved submissions, so they never clash with calendar IDs
+SUBMISSION_PREFIX = "submission-"
+
+# How long decided submissions are kept after their event ends
+RETENTION = timedelta(days=1)
+
+
+def is_submission(event: CalendarEvent) -> bool:
+    """Check whether an event comes from an approved submission."""
+    return event.id.startswith(SUBMISSION_PREFIX)
+
+
+class SubmissionQueue:
+    """Holds submitted events until an organizer approves or rejects them.
+
+    Approved submissions are served as events alongside the calendar and the
+    schedules file.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def add(self, submission: EventSubmission) -> str:
+        """Queue a submission for review and return its ID."""
+        submission_id = uuid.uuid4().hex[:8]
+        self._store.set(
+            SUBMISSIONS,
+            submission_id,
+            {
+                "submission": submission.model_dump(mode="json"),
+                "status": "pending",
+                "message_id": None,
+            },
+        )
+        return submission_id
+
+    def get(self, submission_id: str) -> dict[str, Any] | None:
+        """Return a queued submission's entry, with its status and review message ID."""
+        return self._store.get(SUBMISSIONS, submission_id)
+
+    def set_message(self, submission_id: str, message_id: int) -> None:
+        """Remember the organizer message a submission is reviewed in."""
+        entry = self.get(submission_id)
+        if entry:
+            self._store.set(SUBMISSIONS, submission_id, entry | {"message_id": message_id})
+
+    def decide(self, submission_id: str, approved: bool) -> EventSubmission | None:
+        """Approve or reject a pending submission.
+
+        Returns:
+            The submission, or None if it doesn't exist or was already decided.
+        """
+        entry = self.get(submission_id)
+        if not entry or entry["status"] != "pending":
+            return None
+
+        status = "approved" if approved else "rejected"
+        self._store.set(SUBMISSIONS, submission_id, entry | {"status": status})
+        return EventSubmission.model_validate(entry["submission"])
+
+    def find_approved(self, submission: EventSubmission) -> str | None:
+        """Return the ID of an approved submission with the same name and start, if any."""
+        for submission_id, entry in self._store.items(SUBMISSIONS).items():
+            approved = EventSubmission.model_validate(entry["submission"])
+            if (
+                entry["status"] == "approved"
+                and approved.name == submission.name
+                and approved.time == submission.time
+            ):
+                return submission_id
+        return None
+
+    def event(self, submission_id: str) -> CalendarEvent | None:
+        """Return an approved submission's event, or None if it isn't approved."""
+        entry = self.get(submission_id)
+        if not entry or entry["status"] != "approved":
+            return None
+        return _to_event(submission_id, EventSubmission.model_validate(entry["submission"]))
+
+    def get_upcoming_events(self, hours_ahead: int = 24) -> list[CalendarEvent]:
+        """Return approved events in the next `hours_ahead` hours."""
+        now = datetime.now(ZoneInfo("UTC"))
+        return self.get_events_between(now, now + timedelta(hours=hours_ahead))
+
+    def get_events_between(self, start: datetime, end: datetime) -> list[CalendarEvent]:
+        """Return approved events overlapping [start, end), by start time."""
+        events = []
+        for submission_id, entry in self._store.items(SUBMISSIONS).items():
+            if entry["status"] != "approved":
+                continue
+            event = _to_event(submission_id, EventSubmission.model_validate(entry["submission"]))
+            if event.end_time > start and event.start_time < end:
+                events.append(event)
+        return sorted(events, key=lambda event: event.start_time)
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop submissions whose event ended more than `RETENTION` ago."""
+        for submission_id, entry in self._store.items(SUBMISSIONS).items():
+            submission = EventSubmission.model_validate(entry["submission"])
+            end = submission.time + timedelta(minutes=submission.duration)
+            if end < now - RETENTION:
+                self._store.delete(SUBMISSIONS, submission_id)
+
+
+def _to_event(submission_id: str, submission: EventSubmission) -> CalendarEvent:
+    return CalendarEvent(
+        id=f"{SUBMISSION_PREFIX}{submission_id}",
+        name=submission.name,
+        description=submission.description,
+        start_time=submission.time,
+        end_time=submission.time + timedelta(minutes=submission.duration),
+        timezone=submission.time.tzname() or "UTC",
+        voice_channel=submission.channel,
+    )
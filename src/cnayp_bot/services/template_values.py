@@ -0,0 +1,69 @@
+"""Per-guild values filled into templates, for links that change between sessions."""
+
+import logging
+
+from ..models.schedule import TEMPLATE_LIMIT, VALUE_REFERENCE
+from .store import Store
+
+logger = logging.getLogger(__name__)
+
+# Guild ID -> {value name: text}
+TEMPLATE_VALUES = "template_values"
+
+# Longest value, so a template using a few still fits in a message
+VALUE_LIMIT = 300
+MAX_VALUE_NAME_LENGTH = 32
+
+
+class TemplateValues:
+    """Fills `{$name}` in templates with the text set for the guild with `/config kv set`.
+
+    Values such as the Zoom link or the HackMD doc change from one session to
+    the next, so they're kept in the store and edited from Discord instead of
+    in every schedule's templates.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def all(self, guild_id: int) -> dict[str, str]:
+        """Return a guild's values by name."""
+        return self._store.get(TEMPLATE_VALUES, str(guild_id)) or {}
+
+    def set(self, guild_id: int, name: str, text: str) -> None:
+        """Set a guild's value, replacing it if it's already set."""
+        values = self.all(guild_id)
+        values[name] = text
+        self._store.set(TEMPLATE_VALUES, str(guild_id), values)
+
+    def delete(self, guild_id: int, name: str) -> bool:
+        """Remove a guild's value, returning whether it was set."""
+        values = self.all(guild_id)
+        if values.pop(name, None) is None:
+            return False
+        if values:
+            self._store.set(TEMPLATE_VALUES, str(guild_id), values)
+        else:
+            self._store.delete(TEMPLATE_VALUES, str(guild_id))
+        return True
+
+    def expand(self, template: str, guild_id: int) -> str:
+        """Fill in a template's values for a guild, dropping the ones that aren't set.
+
+        Values are left out altogether when they'd make the template longer
+        than `TEMPLATE_LIMIT`, so the message still fits.
+        """
+        values = self.all(guild_id)
+
+        def value(match) -> str:
+            text = values.get(match[1])
+            if text is None:
+                logger.warning("Leaving out template value {$%s}, not set", match[1])
+            # The template is filled in with str.format next, so braces are kept as text
+            return (text or "").replace("{", "{{").replace("}", "}}")
+
+        expanded = VALUE_REFERENCE.sub(value, template)
+        if len(expanded) > TEMPLATE_LIMIT:
+            logger.error("Template is over %d characters with its values", TEMPLATE_LIMIT)
+            return VALUE_REFERENCE.sub("", template)
+        return expanded
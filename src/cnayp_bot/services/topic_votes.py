@@ -0,0 +1,133 @@
+"""Topic suggestions for upcoming events, and the votes that pick one."""
+
+from datetime import datetime, timedelta
+from typing import Literal
+
+from .store import Store
+
+# Event ID -> {"name", "start", "channel_id", "message_id", "suggestions": [{"author_id",
+# "text"}], "vote_message_id", "emoji", "closed", "winner"}
+TOPIC_VOTES = "topic_votes"
+
+# Longest topic suggestion, so the vote fits in one embed
+TOPIC_LENGTH = 200
+
+# How long votes are kept after their event started, for late lookups
+RETENTION = timedelta(days=1)
+
+Suggestion = Literal["added", "replaced", "full", "closed"]
+
+
+def pick_winner(suggestions: list[dict], votes: list[int]) -> dict | None:
+    """Return the suggestion with the most votes, or None if nobody voted.
+
+    Ties go to the earliest suggestion.
+    """
+    if not suggestions or not any(votes):
+        return None
+    best = max(range(len(suggestions)), key=lambda i: (votes[i], -i))
+    return suggestions[best]
+
+
+class TopicVotes:
+    """Tracks the topics suggested for each event and the vote between them.
+
+    Suggestions are taken as replies to the message that opened them, one per
+    member; a later reply replaces the member's earlier one. Once the vote is
+    posted, no more suggestions are taken.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def open(
+        self, event_id: str, name: str, start: datetime, channel_id: int, message_id: int
+    ) -> None:
+        """Start taking suggestions for an event."""
+        self._store.set(
+            TOPIC_VOTES,
+            event_id,
+            {
+                "name": name,
+                "start": start.isoformat(),
+                "channel_id": channel_id,
+                "message_id": message_id,
+                "suggestions": [],
+                "vote_message_id": None,
+                "emoji": [],
+                "closed": False,
+                "winner": None,
+            },
+        )
+
+    def get(self, event_id: str) -> dict | None:
+        """Return an event's suggestions and vote."""
+        return self._store.get(TOPIC_VOTES, event_id)
+
+    def find(self, message_id: int) -> str | None:
+        """Return the event whose suggestions were opened in a message."""
+        for event_id, votes in self._store.items(TOPIC_VOTES).items():
+            if votes["message_id"] == message_id:
+                return event_id
+        return None
+
+    def suggest(self, event_id: str, author_id: int, text: str, limit: int) -> Suggestion:
+        """Record a member's suggestion, replacing their earlier one.
+
+        Args:
+            limit: The most suggestions taken, one per vote emoji.
+        """
+        votes = self._store.get(TOPIC_VOTES, event_id)
+        if votes["vote_message_id"] or votes["closed"]:
+            return "closed"
+
+        suggestion = {"author_id": author_id, "text": text[:TOPIC_LENGTH]}
+        for i, existing in enumerate(votes["suggestions"]):
+            if existing["author_id"] == author_id:
+                votes["suggestions"][i] = suggestion
+                self._store.set(TOPIC_VOTES, event_id, votes)
+                return "replaced"
+
+        if len(votes["suggestions"]) >= limit:
+            return "full"
+        votes["suggestions"].append(suggestion)
+        self._store.set(TOPIC_VOTES, event_id, votes)
+        return "added"
+
+    def due_votes(self, now: datetime, lead: timedelta) -> list[str]:
+        """Return the events whose vote should be posted, `lead` before they start."""
+        return [
+            event_id
+            for event_id, votes in self._store.items(TOPIC_VOTES).items()
+            if not votes["vote_message_id"]
+            and not votes["closed"]
+            and datetime.fromisoformat(votes["start"]) - lead <= now
+        ]
+
+    def due_results(self, now: datetime, lead: timedelta) -> list[str]:
+        """Return the events whose vote should be counted, `lead` before they start."""
+        return [
+            event_id
+            for event_id, votes in self._store.items(TOPIC_VOTES).items()
+            if votes["vote_message_id"]
+            and not votes["closed"]
+            and datetime.fromisoformat(votes["start"]) - lead <= now
+        ]
+
+    def start_vote(self, event_id: str, message_id: int, emoji: list[str]) -> None:
+        """Record the vote message and the emoji standing for each suggestion."""
+        votes = self._store.get(TOPIC_VOTES, event_id)
+        self._store.set(
+            TOPIC_VOTES, event_id, votes | {"vote_message_id": message_id, "emoji": emoji}
+        )
+
+    def close(self, event_id: str, winner: str | None) -> None:
+        """Close an event's vote with its winning topic, if any."""
+        votes = self._store.get(TOPIC_VOTES, event_id)
+        self._store.set(TOPIC_VOTES, event_id, votes | {"closed": True, "winner": winner})
+
+    def forget_finished(self, now: datetime) -> None:
+        """Drop the votes of events that started a while ago."""
+        for event_id, votes in self._store.items(TOPIC_VOTES).items():
+            if datetime.fromisoformat(votes["start"]) < now - RETENTION:
+                self._store.delete(TOPIC_VOTES, event_id)
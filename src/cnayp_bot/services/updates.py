@@ -0,0 +1,66 @@
+"""Build info of the running bot, and newer releases of it on GitHub."""
+
+import re
+
+import aiohttp
+
+from .. import __version__
+from ..config import settings
+
+GITHUB_API = "https://api.github.com"
+
+# Release tag of the newest version the ops channel was told about
+UPDATES = "updates"
+
+_NUMBERS = re.compile(r"\d+")
+
+
+def build_info() -> dict[str, str]:
+    """Return the version, commit, and build date of the running bot.
+
+    The commit and date are baked into the Docker image as build arguments;
+    runs outside an image report them as unknown.
+    """
+    return {
+        "version": __version__,
+        "commit": settings.build_commit or "unknown",
+        "date": settings.build_date or "unknown",
+    }
+
+
+def parse_version(tag: str) -> tuple[int, ...]:
+    """Turn a release tag such as "v1.2.0" into comparable numbers.
+
+    Raises:
+        ValueError: If the tag has no version numbers.
+    """
+    numbers = tuple(int(number) for number in _NUMBERS.findall(tag.split("-")[0]))
+    if not numbers:
+        raise ValueError(f"Not a version: {tag!r}")
+    return numbers
+
+
+def is_newer(tag: str, current: str) -> bool:
+    """Check whether a release tag is a later version than `current`."""
+    try:
+        return parse_version(tag) > parse_version(current)
+    except ValueError:
+        return False
+
+
+async def fetch_latest_release(repo: str) -> dict | None:
+    """Fetch the latest published release of a GitHub repository, if it has one.
+
+    Drafts and prereleases are never the latest release.
+
+    Raises:
+        aiohttp.ClientError: If GitHub can't be reached.
+    """
+    timeout = aiohttp.ClientTimeout(total=10)
+    headers = {"Accept": "application/vnd.github+json"}
+    async with aiohttp.ClientSession(timeout=timeout, headers=headers) as session:
+        async with session.get(f"{GITHUB_API}/repos/{repo}/releases/latest") as response:
+            if response.status == 404:
+                return None
+            response.raise_for_status()
+            return await response.json()
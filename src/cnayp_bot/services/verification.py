@@ -0,0 +1,72 @@
+"""New members waiting to pass the verification gate."""
+
+from datetime import datetime, timedelta
+
+from .store import Store
+
+# Member ID -> {"joined": ..., "reminded": bool}
+UNVERIFIED = "unverified"
+# Guild ID -> {"channel_id": ..., "message_id": ...} of the gate message
+GATE = "verification_gate"
+
+
+class VerificationGate:
+    """Tracks unverified members and the message they verify with.
+
+    Members are added when they join and removed once they verify or leave.
+    Those who don't verify in time are reminded once, then kicked.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def add(self, member_id: int, joined: datetime) -> None:
+        """Hold a member who just joined at the gate."""
+        self._store.set(
+            UNVERIFIED, str(member_id), {"joined": joined.isoformat(), "reminded": False}
+        )
+
+    def remove(self, member_id: int) -> bool:
+        """Let a member through, or forget one who left.
+
+        Returns:
+            True if the member was waiting at the gate.
+        """
+        if self._store.get(UNVERIFIED, str(member_id)) is None:
+            return False
+        self._store.delete(UNVERIFIED, str(member_id))
+        return True
+
+    def is_pending(self, member_id: int) -> bool:
+        """Whether a member still has to verify."""
+        return self._store.get(UNVERIFIED, str(member_id)) is not None
+
+    def due_reminders(self, now: datetime, timeout: timedelta) -> list[int]:
+        """Return members unverified for longer than `timeout` who weren't reminded yet."""
+        return [
+            int(member_id)
+            for member_id, entry in self._store.items(UNVERIFIED).items()
+            if not entry["reminded"] and datetime.fromisoformat(entry["joined"]) + timeout <= now
+        ]
+
+    def mark_reminded(self, member_id: int) -> None:
+        """Record that a member was reminded to verify."""
+        pending = self._store.get(UNVERIFIED, str(member_id))
+        if pending is not None:
+            self._store.set(UNVERIFIED, str(member_id), {**pending, "reminded": True})
+
+    def expired(self, now: datetime, kick_after: timedelta) -> list[int]:
+        """Return members unverified for longer than `kick_after`."""
+        return [
+            int(member_id)
+            for member_id, entry in self._store.items(UNVERIFIED).items()
+            if datetime.fromisoformat(entry["joined"]) + kick_after <= now
+        ]
+
+    def message(self, guild_id: int) -> dict | None:
+        """Return the channel and message IDs of a guild's gate message."""
+        return self._store.get(GATE, str(guild_id))
+
+    def set_message(self, guild_id: int, channel_id: int, message_id: int) -> None:
+        """Remember a guild's gate message."""
+        self._store.set(GATE, str(guild_id), {"channel_id": channel_id, "message_id": message_id})
@@ -8,6 +8,7 @@ from typing import Any
 from aiohttp import web
 
 from ..config import settings
+from .crash_reports import watch_task
 
 logger = logging.getLogger(__name__)
 
@@ -50,7 +51,11 @@ class WebhookServer:
 
         if resource_state == "exists":
             # Calendar has changes
-            asyncio.create_task(self._on_calendar_change())
+            watch_task(
+                asyncio.create_task(self._on_calendar_change()),
+                "calendar_webhook",
+                {"channel": channel_id, "state": resource_state},
+            )
             return web.Response(status=200)
 
         return web.Response(status=200)
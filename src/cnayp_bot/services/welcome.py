@@ -0,0 +1,57 @@
+"""Progress of new members through the welcome DM sequence."""
+
+from datetime import datetime, timedelta
+
+from .store import Store
+
+# Member ID -> {"joined": ..., "sent": [days of the steps already sent]}
+WELCOME = "welcome_dms"
+
+
+class WelcomeSequence:
+    """Tracks which welcome DMs each new member has been sent.
+
+    Steps are keyed by the number of days after joining they're due. Members
+    leave the sequence once every step was sent, or when they leave the guild.
+    """
+
+    def __init__(self, store: Store) -> None:
+        self._store = store
+
+    def start(self, member_id: int, joined: datetime) -> None:
+        """Enroll a member who just joined."""
+        self._store.set(WELCOME, str(member_id), {"joined": joined.isoformat(), "sent": []})
+
+    def stop(self, member_id: int) -> None:
+        """Remove a member from the sequence."""
+        self._store.delete(WELCOME, str(member_id))
+
+    def due(self, now: datetime, steps: dict[int, str]) -> list[tuple[int, int]]:
+        """Return (member ID, day) pairs of steps that are due and not yet sent.
+
+        Only the latest due step is returned per member, so a member whose
+        earlier steps were missed while the bot was down gets one DM, not a burst.
+        """
+        due = []
+        for member_id, progress in self._store.items(WELCOME).items():
+            joined = datetime.fromisoformat(progress["joined"])
+            pending = [
+                day
+                for day in steps
+                if day not in progress["sent"] and joined + timedelta(days=day) <= now
+            ]
+            if pending:
+                due.append((int(member_id), max(pending)))
+        return due
+
+    def mark_sent(self, member_id: int, day: int, steps: dict[int, str]) -> None:
+        """Record a sent step, skipping earlier ones, and finish the sequence after the last."""
+        progress = self._store.get(WELCOME, str(member_id))
+        if progress is None:
+            return
+
+        progress["sent"] = sorted(step for step in steps if step <= day)
+        if len(progress["sent"]) == len(steps):
+            self.stop(member_id)
+        else:
+            self._store.set(WELCOME, str(member_id), progress)
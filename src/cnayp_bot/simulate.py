@@ -0,0 +1,266 @@
+"""Run the scheduler against a simulated clock.
+
+Prints every event creation, reminder, start notification, host check,
+follow-up, and digest the bot would produce in a date range, so config changes
+can be checked without waiting for real time to pass.
+
+Each loop of the scheduler, digest, and help digest cogs has a tick here that
+runs once per simulated minute, or as often as the loop does, and makes its
+decisions with the same timing rules from `scheduling.py` and the same
+channel and quiet hours helpers as the cogs. Instead of Discord, messages go
+to a stand-in messenger that records them.
+"""
+
+import logging
+from dataclasses import dataclass, field
+from datetime import date, datetime, time, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from .cogs.scheduler import _guild_id, _in_quiet_hours, _notify_channel, _reminder_channel
+from .config import settings
+from .helpers.help_questions import help_digest_due
+from .models import ScheduleConfig
+from .scheduling import (
+    LOOKAHEAD_HOURS,
+    digest_due,
+    followup_stale,
+    followup_time,
+    has_started,
+    held_reminder_batches,
+    host_check_due,
+    in_quiet_hours,
+    minutes_until,
+    reminder_batches,
+    should_create_discord_event,
+    weekly_digest_due,
+)
+from .services.calendar import CalendarEvent, CalendarService
+from .services.schedules import ScheduleService, recurrence_pattern
+
+logger = logging.getLogger(__name__)
+
+STEP = timedelta(minutes=1)
+
+# How often the help digest cog's loop runs
+HELP_DIGEST_STEP = timedelta(minutes=10)
+
+
+@dataclass
+class SimulatedAction:
+    """Something the bot would have done at a point in time."""
+
+    time: datetime
+    kind: str
+    description: str
+
+
+@dataclass
+class Simulator:
+    """The scheduler's state, advanced by its loops' ticks at the simulated `now`.
+
+    The sets and dicts stand in for the cogs' memory and store namespaces of
+    the same names, and `post` for the messenger.
+    """
+
+    events: list[CalendarEvent]
+    reminder_minutes: list[int]
+    config: ScheduleConfig
+    now: datetime
+    actions: list[SimulatedAction] = field(default_factory=list)
+    known_events: dict[str, CalendarEvent] = field(default_factory=dict)
+    discord_events: set[str] = field(default_factory=set)
+    recurring_events: dict[str, str] = field(default_factory=dict)
+    sent_reminders: set[str] = field(default_factory=set)
+    held_reminders: dict[str, datetime] = field(default_factory=dict)
+    sent_start_notifications: set[str] = field(default_factory=set)
+    host_checks: set[str] = field(default_factory=set)
+    followups: dict[str, tuple[datetime, str]] = field(default_factory=dict)
+    digest_posted: date | None = None
+    weekly_posted: date | None = None
+    help_digest_run: datetime | None = None
+
+    def post(self, kind: str, description: str) -> None:
+        """Record a message the bot would send."""
+        self.actions.append(SimulatedAction(self.now, kind, description))
+
+    def scheduler_tick(self) -> None:
+        """Pick up the events coming up, and create their Discord events when due."""
+        # The calendar returns events that haven't ended yet
+        lookahead = self.now + timedelta(hours=LOOKAHEAD_HOURS)
+        for event in self.events:
+            if event.end_time > self.now and event.start_time < lookahead:
+                self.known_events[event.id] = event
+
+        for event in self.known_events.values():
+            if should_create_discord_event(event, self.now):
+                self._create_discord_event(event, _guild_id(event))
+                for mirror in event.schedule.mirrors if event.schedule else []:
+                    self._create_discord_event(event, mirror.guild_id)
+
+    def _create_discord_event(self, event: CalendarEvent, guild_id: int) -> None:
+        """Create and announce an occurrence in a guild, once."""
+        key = f"{event.id}@{guild_id}"
+        if key in self.discord_events:
+            return
+        self.discord_events.add(key)
+
+        where = f"(starts {event.start_time}) in guild {guild_id}"
+        if not event.schedule or not event.schedule.native_recurrence:
+            self.post("create", f"{event.name} {where}")
+            return
+        # Recurring Discord events are created once, and again when their schedule changes
+        series = f"{event.schedule.name}@{guild_id}"
+        pattern = recurrence_pattern(event.schedule)
+        if self.recurring_events.get(series) != pattern:
+            self.recurring_events[series] = pattern
+            self.post("create", f"{event.name}, recurring, {where}")
+        else:
+            self.post("announce", f"{event.name} {where}")
+
+    def reminder_tick(self) -> None:
+        """Send due reminders, follow-ups, start notifications, and host checks."""
+        events = list(self.known_events.values())
+        self._send_due_reminders(events)
+        self._send_due_followups()
+        for event in events:
+            if event.id not in self.sent_start_notifications and has_started(event, self.now):
+                self.sent_start_notifications.add(event.id)
+                self.post("start", f"{event.name} in #{_notify_channel(event)}")
+                self._schedule_followup(event)
+            self._check_host_joined(event)
+
+    def _send_due_reminders(self, events: list[CalendarEvent]) -> None:
+        """Send reminders per channel and offset, holding back those due in quiet hours."""
+        batches = reminder_batches(
+            events,
+            self.now,
+            self.reminder_minutes,
+            self.sent_reminders,
+            _reminder_channel,
+            ZoneInfo(settings.default_timezone),
+        )
+        for (channel, minutes), batch in batches.items():
+            for event in batch:
+                if _in_quiet_hours(event, self.now):
+                    self.held_reminders[event.id] = event.start_time
+            due = [event for event in batch if not _in_quiet_hours(event, self.now)]
+            if due:
+                self._remind(channel, due, f"{minutes} min before")
+            self.sent_reminders.update(f"{event.id}:{minutes}" for event in batch)
+
+        batches, dropped = held_reminder_batches(
+            self.held_reminders, self.known_events, self.now, _reminder_channel, _in_quiet_hours
+        )
+        for event_id in dropped:
+            del self.held_reminders[event_id]
+        for channel, due in batches.items():
+            minutes = minutes_until(due[0], self.now)
+            self._remind(channel, due, f"{minutes} min before, after quiet hours")
+            for event in due:
+                del self.held_reminders[event.id]
+
+    def _remind(self, channel: tuple, events: list[CalendarEvent], when: str) -> None:
+        """Send one reminder of `events` in their reminder channel."""
+        guild_id, channel_name, audience_role, ping, _ = channel
+        names = ", ".join(event.name for event in events)
+        # As in `_ping`, private events ping their audience unless reminders ping nobody
+        mention = audience_role if audience_role and ping != "none" else ping
+        self.post(
+            "reminder", f"{names} ({when}) in #{channel_name} of guild {guild_id}, ping {mention}"
+        )
+
+    def _schedule_followup(self, event: CalendarEvent) -> None:
+        """Remember the schedule's follow-up of an event that started."""
+        followup = event.schedule.followup if event.schedule else None
+        if followup and event.id not in self.followups:
+            due = followup_time(event, followup.delay_minutes)
+            self.followups[event.id] = (due, f"{event.name} in #{_notify_channel(event)}")
+
+    def _send_due_followups(self) -> None:
+        """Post the follow-ups whose events have been over for their delay."""
+        for event_id, (due, description) in list(self.followups.items()):
+            if due > self.now:
+                continue
+            del self.followups[event_id]
+            if not followup_stale(due, self.now):
+                self.post("followup", description)
+
+    def _check_host_joined(self, event: CalendarEvent) -> None:
+        """Check once, a while after the start, whether a host joined an event with owners."""
+        owners = event.schedule.owners if event.schedule else []
+        if not owners or not settings.host_check_minutes or event.id in self.host_checks:
+            return
+        if not host_check_due(event, self.now, settings.host_check_minutes):
+            return
+        self.host_checks.add(event.id)
+        self.post("host", f"{event.name}: owners told if none of them joined")
+
+    def digest_tick(self) -> None:
+        """Post the daily digest and the weekly overview when due outside quiet hours."""
+        now = self.now.astimezone(ZoneInfo(settings.default_timezone))
+        quiet = in_quiet_hours(now, settings.quiet_hours_start, settings.quiet_hours_end)
+        config = self.config
+
+        if config.digest_time and config.digest_channel and not quiet:
+            if digest_due(now, config.digest_time, self.digest_posted):
+                self.digest_posted = now.date()
+                self.post("digest", f"Today's events in #{config.digest_channel}")
+
+        weekly_channel = config.weekly_digest_channel or config.digest_channel
+        day, digest_time = config.weekly_digest_day, config.weekly_digest_time
+        if day and digest_time and weekly_channel and not quiet:
+            if weekly_digest_due(now, day, digest_time, self.weekly_posted):
+                self.weekly_posted = now.date()
+                self.post("digest", f"The week's events in #{weekly_channel}")
+
+    def help_digest_tick(self) -> None:
+        """Post the unanswered questions digest every `HELP_DIGEST_HOURS`."""
+        if self.help_digest_run is None:
+            self.help_digest_run = self.now
+            return
+        if help_digest_due(self.now, self.help_digest_run, settings.help_digest_hours):
+            self.help_digest_run = self.now
+            self.post("digest", f"Unanswered questions in #{settings.help_channel}")
+
+
+def simulate(
+    events: list[CalendarEvent],
+    start: datetime,
+    end: datetime,
+    reminder_minutes: list[int],
+    config: ScheduleConfig | None = None,
+) -> list[SimulatedAction]:
+    """Step through [start, end) one minute at a time, running each loop when it would run."""
+    simulator = Simulator(events, reminder_minutes, config or ScheduleConfig(), start)
+    while simulator.now < end:
+        simulator.scheduler_tick()
+        simulator.reminder_tick()
+        simulator.digest_tick()
+        if settings.help_channel and (simulator.now - start) % HELP_DIGEST_STEP == timedelta(0):
+            simulator.help_digest_tick()
+        simulator.now += STEP
+
+    return simulator.actions
+
+
+def run(start: date, end: date, timezone: ZoneInfo) -> None:
+    """Fetch calendar and schedule events for the range and print the simulated actions.
+
+    Both dates are inclusive and interpreted in `timezone`.
+    """
+    start_time = datetime.combine(start, time.min, tzinfo=timezone)
+    end_time = datetime.combine(end + timedelta(days=1), time.min, tzinfo=timezone)
+
+    fetch_end = end_time + timedelta(hours=LOOKAHEAD_HOURS)
+    events = CalendarService().get_events_between(start_time, fetch_end)
+    schedules = ScheduleService(Path(settings.schedules_file))
+    events += schedules.get_events_between(start_time, fetch_end)
+    logger.info("Simulating %d events from %s to %s", len(events), start, end)
+
+    actions = simulate(events, start_time, end_time, settings.reminder_minutes, schedules.config)
+    for action in actions:
+        timestamp = action.time.astimezone(timezone).strftime("%Y-%m-%d %H:%M")
+        print(f"{timestamp}  {action.kind:<8}  {action.description}")
+
+    print(f"\n{len(actions)} actions for {len(events)} events")
@@ -0,0 +1,9 @@
+"""Shared test configuration."""
+
+import os
+
+# Settings are loaded when first imported from cnayp_bot.config, so required values
+# must be present before any test module imports the package.
+os.environ.setdefault("DISCORD_BOT_TOKEN", "test-token")
+os.environ.setdefault("DISCORD_GUILD_ID", "1")
+os.environ.setdefault("GOOGLE_CALENDAR_ID", "test@group.calendar.google.com")
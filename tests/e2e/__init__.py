@@ -0,0 +1 @@
+"""End-to-end scenarios run against a fake Discord server on a simulated clock."""
@@ -0,0 +1,43 @@
+"""A clock the scenarios move forward by hand, standing in for `datetime.now()`."""
+
+import sys
+from datetime import datetime, timedelta, tzinfo
+from types import ModuleType
+
+
+class SimulatedClock:
+    """The time every `cnayp_bot` module sees while the clock is installed.
+
+    The package reads the time with `datetime.now(tz)` from `from datetime
+    import datetime`, so installing the clock swaps that name in each loaded
+    module for a subclass whose `now()` returns the simulated time.
+    Uninstalling puts the real class back.
+    """
+
+    def __init__(self, now: datetime) -> None:
+        self.now = now
+        self._patched: list[ModuleType] = []
+
+    def advance(self, delta: timedelta) -> None:
+        """Move the clock forward."""
+        self.now += delta
+
+    def install(self) -> None:
+        """Make `datetime.now()` in the package return the simulated time."""
+        clock = self
+
+        class SimulatedDatetime(datetime):
+            @classmethod
+            def now(cls, tz: tzinfo | None = None) -> datetime:  # type: ignore[override]
+                return clock.now.astimezone(tz) if tz else clock.now.replace(tzinfo=None)
+
+        for name, module in list(sys.modules.items()):
+            if name.startswith("cnayp_bot") and getattr(module, "datetime", None) is datetime:
+                module.datetime = SimulatedDatetime
+                self._patched.append(module)
+
+    def uninstall(self) -> None:
+        """Put the real `datetime` back."""
+        for module in self._patched:
+            module.datetime = datetime
+        self._patched.clear()
@@ -0,0 +1,254 @@
+"""Runs the bot's loops and commands against the fake Discord server on a simulated clock.
+
+A `Scenario` builds the primary guild, a partner guild, the schedules file,
+and a bot with the real services over a store in a temporary directory, then
+ticks the scheduler, reminder, digest, and schedules-watch loops once per
+simulated minute, as they run in production. Whatever reaches Discord ends
+up in the server's transcript, which `assert_golden` compares with
+`golden/<name>.txt`. Run with `UPDATE_GOLDEN=1` to rewrite the golden files
+after an intended change, and review their diff.
+"""
+
+import json
+import os
+from datetime import datetime, timedelta
+from pathlib import Path
+from types import TracebackType
+from typing import Any
+
+import discord
+
+from cnayp_bot.cogs.digest import DigestCog
+from cnayp_bot.cogs.partners import PartnersCog
+from cnayp_bot.cogs.scheduler import SchedulerCog
+from cnayp_bot.cogs.schedules import SchedulesCog
+from cnayp_bot.services.absences import Absences
+from cnayp_bot.services.canary import Canary
+from cnayp_bot.services.components import ComponentRouter
+from cnayp_bot.services.config_snapshots import ConfigSnapshots
+from cnayp_bot.services.dm_reminders import DmReminders
+from cnayp_bot.services.experiments import AnnouncementExperiments
+from cnayp_bot.services.governor import RateGovernor
+from cnayp_bot.services.history import EventHistory
+from cnayp_bot.services.in_flight import InFlightWork
+from cnayp_bot.services.interest import InterestTracker
+from cnayp_bot.services.leader import LeaderElection
+from cnayp_bot.services.maintenance import Maintenance
+from cnayp_bot.services.meetings import MeetingLinks
+from cnayp_bot.services.messenger import Messenger
+from cnayp_bot.services.observer import Observer
+from cnayp_bot.services.partials import TemplatePartials
+from cnayp_bot.services.partners import PartnerAnnouncements
+from cnayp_bot.services.role_grants import RoleGrants
+from cnayp_bot.services.rsvps import RsvpList
+from cnayp_bot.services.schedule_sheet import ImportedSchedules
+from cnayp_bot.services.schedules import ScheduleService
+from cnayp_bot.services.slowmode import SlowmodeOverrides
+from cnayp_bot.services.sponsors import SponsorRotation
+from cnayp_bot.services.statuspage import IncidentTracker
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.submissions import SubmissionQueue
+from cnayp_bot.services.template_values import TemplateValues
+
+from .clock import SimulatedClock
+from .server import FakeDiscordServer, ServerChannel, ServerGuild, ServerMember
+
+GOLDEN = Path(__file__).parent / "golden"
+
+# How often the loops run, as `tasks.loop(minutes=1)` does
+TICK = timedelta(minutes=1)
+
+PRIMARY_GUILD = 1  # settings.discord_guild_id in tests
+PARTNER_GUILD = 2
+OWNER = 42
+
+
+class FakeCalendar:
+    """A Google Calendar without events, so the schedules file drives the scenarios."""
+
+    def get_upcoming_events(self, hours_ahead: int = 24) -> list:
+        return []
+
+    def is_cancelled(self, event_id: str) -> bool:
+        return False
+
+
+class E2EBot:
+    """The services of `CNAYPBot` over a temporary store, in the fake server's guilds."""
+
+    def __init__(self, server: FakeDiscordServer, tmp_path: Path, schedules_path: Path) -> None:
+        self.server = server
+        self.guilds = server.guilds
+        self.calendar = FakeCalendar()
+        self.store = Store(tmp_path / "store.json")
+        self.schedules = ScheduleService(schedules_path, ConfigSnapshots(self.store))
+        self.partials = TemplatePartials(tmp_path / "templates")
+        self.messenger = Messenger(self)
+        self.governor = RateGovernor()
+        self.observer = Observer(self.store)
+        self.maintenance = Maintenance(self.store)
+        self.in_flight = InFlightWork()
+        self.leader = LeaderElection(None, "e2e", timedelta(minutes=1))
+        self.experiments = AnnouncementExperiments(self.store)
+        self.interest = InterestTracker(self.store)
+        self.dm_reminders = DmReminders(self.store)
+        self.submissions = SubmissionQueue(self.store)
+        self.absences = Absences(self.store)
+        self.role_grants = RoleGrants(self.store)
+        self.rsvps = RsvpList(self.store)
+        self.slowmode = SlowmodeOverrides(self.store)
+        self.sponsors = SponsorRotation(self.store)
+        self.imported_schedules = ImportedSchedules(self.store)
+        self.history = EventHistory(self.store)
+        self.incidents = IncidentTracker(self.store)
+        self.partners = PartnerAnnouncements(self.store)
+        self.meetings = MeetingLinks(self.store)
+        self.template_values = TemplateValues(self.store)
+        self.canary = Canary(self.store, None, [], timedelta(days=7))
+        self.components = ComponentRouter(b"e2e-secret")
+        self.cogs: dict[str, Any] = {}
+
+    async def wait_until_ready(self) -> None:
+        pass
+
+    def get_cog(self, name: str) -> Any:
+        return self.cogs.get(name)
+
+    def get_guild(self, guild_id: int) -> ServerGuild | None:
+        return next((guild for guild in self.guilds if guild.id == guild_id), None)
+
+    def get_channel(self, channel_id: int) -> ServerChannel | None:
+        channels = (guild.get_channel(channel_id) for guild in self.guilds)
+        return next((channel for channel in channels if channel), None)
+
+    def get_user(self, user_id: int) -> ServerMember | None:
+        members = (guild.get_member(user_id) for guild in self.guilds)
+        return next((member for member in members if member), None)
+
+    async def fetch_user(self, user_id: int) -> ServerMember:
+        self.server.record("GET", f"/users/{user_id}")
+        return ServerMember(self.server, user_id, f"user{user_id}")
+
+
+class FakeContext:
+    """A command invoked by a member in a channel; replies are sent there."""
+
+    def __init__(self, author: ServerMember, channel: ServerChannel) -> None:
+        self.author = author
+        self.channel = channel
+        self.guild = channel.guild
+        self.interaction = None
+
+    async def send(self, content: str | None = None, **kwargs: Any) -> Any:
+        return await self.channel.send(content, **kwargs)
+
+
+class Scenario:
+    """A bot in a primary and a partner guild, driven a minute at a time from `start`.
+
+    Use it as an async context manager: entering installs the simulated
+    clock and connects the bot, leaving uninstalls the clock.
+    """
+
+    def __init__(self, tmp_path: Path, config: dict, start: datetime) -> None:
+        self.tmp_path = tmp_path
+        self.clock = SimulatedClock(start)
+        self.server = FakeDiscordServer(self.clock)
+
+        self.guild = self.server.add_guild(PRIMARY_GUILD, "CNAYP")
+        self.owner = self.guild.add_member("ana", OWNER)
+        for name in ("events", "digest", "staff"):
+            self.guild.add_channel(name)
+        for name in ("K8s | KCNA", "K8s | CKA"):
+            self.guild.add_channel(name, "voice")
+        partner = self.server.add_guild(PARTNER_GUILD, "Partner Community")
+        self.partner_admin = partner.add_member("lu")
+        partner.add_channel("community-events")
+
+        self.schedules_path = tmp_path / "schedules.json"
+        self.write_schedules(config)
+        self.bot = E2EBot(self.server, tmp_path, self.schedules_path)
+        self.loops: list[tuple[Any, Any]] = []
+
+    async def __aenter__(self) -> "Scenario":
+        self.clock.install()
+        await self.connect()
+        return self
+
+    async def __aexit__(
+        self,
+        exc_type: type[BaseException] | None,
+        exc: BaseException | None,
+        traceback: TracebackType | None,
+    ) -> None:
+        self.clock.uninstall()
+
+    async def connect(self) -> None:
+        """Load the cogs as on startup; calling it again is a reconnect, losing their memory.
+
+        What the cogs keep in memory, like the known events and the reminders
+        sent, starts over, while the store and the server's state carry on.
+        """
+        scheduler = SchedulerCog(self.bot)
+        scheduler.calendar = self.bot.calendar
+        digest = DigestCog(self.bot)
+        schedules = SchedulesCog(self.bot)
+        self.bot.cogs = {
+            "SchedulerCog": scheduler,
+            "DigestCog": digest,
+            "SchedulesCog": schedules,
+            "PartnersCog": PartnersCog(self.bot),
+        }
+        await scheduler.before_scheduler_loop()
+        self.loops = [
+            (schedules, schedules.watch_loop),
+            (scheduler, scheduler.scheduler_loop),
+            (scheduler, scheduler.reminder_loop),
+            (digest, digest.digest_loop),
+            (digest, digest.weekly_loop),
+        ]
+
+    async def run_until(self, until: datetime) -> None:
+        """Advance the clock to `until`, running every loop each minute."""
+        while self.clock.now < until:
+            self.clock.advance(TICK)
+            for cog, loop in self.loops:
+                await loop.coro(cog)
+
+    async def reconnect(self) -> None:
+        """Drop the connection and connect again, as after a gateway outage or restart."""
+        self.server.note("reconnect")
+        await self.connect()
+
+    def write_schedules(self, config: dict) -> None:
+        """Write the schedules file, as an organizer editing it by hand."""
+        self.schedules_path.write_text(json.dumps(config, indent=2), encoding="utf-8")
+        # The watch loop compares modification times, which may not tick between writes
+        stamp = self.clock.now.timestamp()
+        os.utime(self.schedules_path, (stamp, stamp))
+
+    async def command(
+        self, cog: str, command: str, *args: Any, author: ServerMember | None = None
+    ) -> None:
+        """Invoke a cog's command, e.g. `schedules_skip`, as `author` (the owner by default).
+
+        It's invoked in #staff, or in a partner's channel for partners.
+        """
+        author = author or self.owner
+        guild = next(guild for guild in self.server.guilds if author in guild.members)
+        channel = discord.utils.get(guild.text_channels, name="staff") or guild.text_channels[0]
+        self.server.note(f"{author} runs {command} {' '.join(map(str, args))}".rstrip())
+        instance = self.bot.get_cog(cog)
+        await getattr(instance, command).callback(instance, FakeContext(author, channel), *args)
+
+    def assert_golden(self, name: str) -> None:
+        """Check the transcript against `golden/<name>.txt`, or rewrite it with UPDATE_GOLDEN."""
+        transcript = "\n".join(self.server.transcript) + "\n"
+        path = GOLDEN / f"{name}.txt"
+        if os.environ.get("UPDATE_GOLDEN"):
+            path.parent.mkdir(exist_ok=True)
+            path.write_text(transcript, encoding="utf-8")
+        assert transcript == path.read_text(encoding="utf-8"), (
+            f"The transcript differs from {path.name}; if the change is intended, "
+            "run the e2e tests with UPDATE_GOLDEN=1 and review the diff"
+        )
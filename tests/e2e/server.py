@@ -0,0 +1,368 @@
+"""A fake Discord server that keeps the state the bot changes and logs every request.
+
+Guilds, channels, messages, and scheduled events are plain objects with the
+methods the cogs call on discord.py's. Each call that would reach the API is
+appended to the server's transcript as one line, `<time> <METHOD> <route>`,
+followed by what it sends, so a scenario's transcript can be compared with a
+golden file.
+"""
+
+from datetime import datetime
+from itertools import count
+from typing import Any
+from zoneinfo import ZoneInfo
+
+import discord
+
+from cnayp_bot.services.messenger import is_mass_ping
+
+from .clock import SimulatedClock
+
+
+class FakeDiscordServer:
+    """The guilds the bot is in, with the transcript of requests made to them."""
+
+    def __init__(self, clock: SimulatedClock) -> None:
+        self.clock = clock
+        self.guilds: list[ServerGuild] = []
+        self.transcript: list[str] = []
+        # IDs count up from the same number every run, so transcripts are stable
+        self._ids = count(1000)
+
+    def next_id(self) -> int:
+        """Return a new snowflake."""
+        return next(self._ids)
+
+    def add_guild(self, guild_id: int, name: str) -> "ServerGuild":
+        """Add a guild the bot is in."""
+        guild = ServerGuild(self, guild_id, name)
+        self.guilds.append(guild)
+        return guild
+
+    def record(self, method: str, route: str, *details: str) -> None:
+        """Log a request, with its details on indented lines."""
+        self.transcript.append(f"{_when(self.clock.now)}  {method} {route}")
+        self.transcript.extend(
+            f"    {line}".rstrip() for detail in details for line in detail.split("\n")
+        )
+
+    def note(self, text: str) -> None:
+        """Log something the scenario did, e.g. a restart, between requests."""
+        self.transcript.append(f"{_when(self.clock.now)}  -- {text}")
+
+
+class ServerMember:
+    """A member or user; DMs sent to them are logged."""
+
+    def __init__(
+        self, server: FakeDiscordServer, member_id: int, name: str, bot: bool = False
+    ) -> None:
+        self.server = server
+        self.id = member_id
+        self.name = name
+        self.bot = bot
+        self.guild_permissions = discord.Permissions.all()
+
+    @property
+    def mention(self) -> str:
+        return f"<@{self.id}>"
+
+    async def send(self, content: str | None = None, **kwargs: Any) -> "ServerMessage":
+        self.server.record("POST", f"/users/{self.id}/messages (DM)", *_render(content, kwargs))
+        return ServerMessage(self.server, self.server.next_id(), None, content, kwargs)
+
+    def __str__(self) -> str:
+        return self.name
+
+
+class ServerRole:
+    def __init__(self, role_id: int, name: str) -> None:
+        self.id = role_id
+        self.name = name
+
+    @property
+    def mention(self) -> str:
+        return f"<@&{self.id}>"
+
+
+class ServerGuild:
+    """A guild with its channels, roles, and scheduled events."""
+
+    def __init__(self, server: FakeDiscordServer, guild_id: int, name: str) -> None:
+        self.server = server
+        self.id = guild_id
+        self.name = name
+        self.channels: list[ServerChannel] = []
+        self.threads: list[ServerChannel] = []
+        self.default_role = ServerRole(guild_id, "@everyone")
+        self.roles = [self.default_role]
+        self.members: list[ServerMember] = []
+        self.scheduled_events: list[ServerScheduledEvent] = []
+        self.me = ServerMember(server, 1, "CNAYP Bot", bot=True)
+
+    @property
+    def text_channels(self) -> list["ServerChannel"]:
+        return [channel for channel in self.channels if channel.type == "text"]
+
+    @property
+    def voice_channels(self) -> list["ServerChannel"]:
+        return [channel for channel in self.channels if channel.type == "voice"]
+
+    def add_channel(self, name: str, type: str = "text", public: bool = True) -> "ServerChannel":
+        channel = ServerChannel(self, self.server.next_id(), name, type, public)
+        self.channels.append(channel)
+        return channel
+
+    def add_role(self, name: str) -> ServerRole:
+        role = ServerRole(self.server.next_id(), name)
+        self.roles.append(role)
+        return role
+
+    def add_member(self, name: str, member_id: int | None = None) -> ServerMember:
+        member = ServerMember(self.server, member_id or self.server.next_id(), name)
+        self.members.append(member)
+        return member
+
+    def get_channel(self, channel_id: int) -> "ServerChannel | None":
+        return next((c for c in [*self.channels, *self.threads] if c.id == channel_id), None)
+
+    async def fetch_channels(self) -> list["ServerChannel"]:
+        self.server.record("GET", f"/guilds/{self.id}/channels")
+        return list(self.channels)
+
+    def get_role(self, role_id: int) -> ServerRole | None:
+        return next((role for role in self.roles if role.id == role_id), None)
+
+    def get_member(self, member_id: int) -> ServerMember | None:
+        return next((member for member in self.members if member.id == member_id), None)
+
+    def get_scheduled_event(self, event_id: int) -> "ServerScheduledEvent | None":
+        return next((event for event in self.scheduled_events if event.id == event_id), None)
+
+    async def fetch_scheduled_event(self, event_id: int, **kwargs: Any) -> "ServerScheduledEvent":
+        self.server.record("GET", f"/guilds/{self.id}/scheduled-events/{event_id}")
+        event = self.get_scheduled_event(event_id)
+        if event is None:
+            raise discord.NotFound(_Response(404), "Unknown Guild Scheduled Event")
+        return event
+
+    async def create_scheduled_event(
+        self,
+        *,
+        name: str,
+        start_time: datetime,
+        end_time: datetime,
+        channel: "ServerChannel",
+        description: str = "",
+        **kwargs: Any,
+    ) -> "ServerScheduledEvent":
+        event = ServerScheduledEvent(
+            self, self.server.next_id(), name, start_time, end_time, channel
+        )
+        self.scheduled_events.append(event)
+        self.server.record(
+            "POST",
+            f"/guilds/{self.id}/scheduled-events",
+            f"{name} in #{channel.name}, {_when(start_time)} to {_when(end_time)}",
+            description,
+        )
+        return event
+
+    def __str__(self) -> str:
+        return self.name
+
+
+class ServerChannel:
+    """A text or voice channel, or a thread; `members` are who's in a call."""
+
+    def __init__(
+        self, guild: ServerGuild, channel_id: int, name: str, type: str, public: bool
+    ) -> None:
+        self.guild = guild
+        self.id = channel_id
+        self.name = name
+        self.type = type
+        self.public = public
+        self.members: list[ServerMember] = []
+        self.messages: dict[int, ServerMessage] = {}
+        self.slowmode_delay = 0
+
+    @property
+    def server(self) -> FakeDiscordServer:
+        return self.guild.server
+
+    @property
+    def mention(self) -> str:
+        return f"<#{self.id}>"
+
+    def permissions_for(self, target: Any) -> discord.Permissions:
+        if target is self.guild.default_role and not self.public:
+            return discord.Permissions.none()
+        return discord.Permissions.all()
+
+    async def send(self, content: str | None = None, **kwargs: Any) -> "ServerMessage":
+        message = ServerMessage(self.server, self.server.next_id(), self, content, kwargs)
+        self.messages[message.id] = message
+        self.server.record(
+            "POST", f"/channels/{self.id}/messages (#{self.name})", *_render(content, kwargs)
+        )
+        return message
+
+    async def fetch_message(self, message_id: int) -> "ServerMessage":
+        self.server.record("GET", f"/channels/{self.id}/messages/{message_id}")
+        if message_id not in self.messages:
+            raise discord.NotFound(_Response(404), "Unknown Message")
+        return self.messages[message_id]
+
+    def get_partial_message(self, message_id: int) -> "ServerMessage":
+        return self.messages.get(message_id) or ServerMessage(
+            self.server, message_id, self, None, {}
+        )
+
+    async def edit(self, *, slowmode_delay: int, **kwargs: Any) -> None:
+        self.slowmode_delay = slowmode_delay
+        self.server.record(
+            "PATCH", f"/channels/{self.id} (#{self.name})", f"slowmode {slowmode_delay}s"
+        )
+
+    async def create_thread(self, *, name: str, **kwargs: Any) -> "ServerChannel":
+        thread = ServerChannel(self.guild, self.server.next_id(), name, "thread", self.public)
+        self.guild.threads.append(thread)
+        self.server.record("POST", f"/channels/{self.id}/threads (#{self.name})", name)
+        return thread
+
+    def __str__(self) -> str:
+        return self.name
+
+
+class ServerMessage:
+    """A message the bot sent, edited in place."""
+
+    def __init__(
+        self,
+        server: FakeDiscordServer,
+        message_id: int,
+        channel: ServerChannel | None,
+        content: str | None,
+        kwargs: dict[str, Any],
+    ) -> None:
+        self.server = server
+        self.id = message_id
+        self.channel = channel
+        self.content = content
+        self.embeds = _embeds(kwargs)
+        self.reactions: list[Any] = []
+        self.attachments: list[Any] = []
+
+    @property
+    def route(self) -> str:
+        return f"/channels/{self.channel.id}/messages/{self.id}"
+
+    @property
+    def jump_url(self) -> str:
+        guild_id = self.channel.guild.id if self.channel else "@me"
+        channel_id = self.channel.id if self.channel else 0
+        return f"https://discord.com/channels/{guild_id}/{channel_id}/{self.id}"
+
+    async def edit(self, **kwargs: Any) -> None:
+        if kwargs.get("embed"):
+            self.embeds = [kwargs["embed"]]
+        self.server.record("PATCH", self.route, *_render(kwargs.get("content"), kwargs))
+
+    async def delete(self, **kwargs: Any) -> None:
+        self.channel.messages.pop(self.id, None)
+        self.server.record("DELETE", self.route)
+
+    async def pin(self, **kwargs: Any) -> None:
+        self.server.record("PUT", f"/channels/{self.channel.id}/pins/{self.id}")
+
+
+class ServerScheduledEvent:
+    """A Discord scheduled event, changed in place."""
+
+    def __init__(
+        self,
+        guild: ServerGuild,
+        event_id: int,
+        name: str,
+        start_time: datetime,
+        end_time: datetime,
+        channel: ServerChannel,
+    ) -> None:
+        self.guild = guild
+        self.id = event_id
+        self.name = name
+        self.start_time = start_time
+        self.end_time = end_time
+        self.channel = channel
+        self.status = "scheduled"
+        self.user_count = 0
+
+    @property
+    def route(self) -> str:
+        return f"/guilds/{self.guild.id}/scheduled-events/{self.id}"
+
+    async def start(self, **kwargs: Any) -> None:
+        self._set_status("active")
+
+    async def end(self, **kwargs: Any) -> None:
+        self._set_status("completed")
+
+    async def cancel(self, **kwargs: Any) -> None:
+        self._set_status("canceled")
+
+    async def edit(self, *, start_time: datetime, end_time: datetime, **kwargs: Any) -> None:
+        self.start_time, self.end_time = start_time, end_time
+        self.guild.server.record(
+            "PATCH", self.route, f"{self.name}: {_when(start_time)} to {_when(end_time)}"
+        )
+
+    async def delete(self, **kwargs: Any) -> None:
+        self.guild.scheduled_events.remove(self)
+        self.guild.server.record("DELETE", self.route)
+
+    def _set_status(self, status: str) -> None:
+        self.status = status
+        self.guild.server.record("PATCH", self.route, f"{self.name}: {status}")
+
+
+class _Response:
+    """The part of an aiohttp response discord.py's HTTP errors read."""
+
+    def __init__(self, status: int) -> None:
+        self.status = status
+        self.reason = "Not Found"
+
+
+def _when(moment: datetime) -> str:
+    """Format a time in UTC for the transcript."""
+    return f"{moment.astimezone(ZoneInfo("UTC")):%a %Y-%m-%d %H:%M} UTC"
+
+
+def _embeds(kwargs: dict[str, Any]) -> list[discord.Embed]:
+    """Return the embeds of a send or edit, given as `embed` or `embeds`."""
+    return kwargs.get("embeds") or ([kwargs["embed"]] if kwargs.get("embed") else [])
+
+
+def _render(content: str | None, kwargs: dict[str, Any]) -> list[str]:
+    """Return the lines a message is logged with: its text, embeds, and components."""
+    lines = [content] if content else []
+    for embed in _embeds(kwargs):
+        lines.append(f"[embed] {embed.title or ''}".rstrip())
+        if embed.description:
+            lines.append(embed.description)
+        lines.extend(f"[field] {field.name}: {field.value}" for field in embed.fields)
+    view = kwargs.get("view")
+    for item in getattr(view, "children", []):
+        label = getattr(item, "placeholder", None) or getattr(item, "label", None)
+        lines.append(f"[component] {label}")
+    flags = []
+    if is_mass_ping(content, kwargs.get("allowed_mentions")):
+        flags.append("pings")
+    if kwargs.get("silent"):
+        flags.append("silent")
+    if kwargs.get("ephemeral"):
+        flags.append("ephemeral")
+    if flags:
+        lines.append(f"({', '.join(flags)})")
+    return lines
@@ -0,0 +1,102 @@
+"""End-to-end scenarios of the trigger, reminder, and digest pipeline, against golden files."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from .harness import OWNER, PARTNER_GUILD, Scenario
+
+LIMA = ZoneInfo("America/Lima")
+
+# Monday, midnight in Lima, the schedules' and digest's timezone
+MONDAY = datetime(2025, 3, 10, 0, 0, tzinfo=LIMA)
+DAY = timedelta(days=1)
+
+
+def week_config() -> dict:
+    """Return a schedules file with two weekly schedules, the digests, and a partner."""
+    return {
+        "schedules": [
+            {
+                "name": "KCNA Study",
+                "description": "Weekly KCNA study group",
+                "voice_channel": "K8s | KCNA",
+                "notify_channel": "events",
+                "days": ["monday", "wednesday"],
+                "time": "18:00",
+                "timezone": "America/Lima",
+                "duration_minutes": 60,
+                "owners": [OWNER],
+            },
+            {
+                "name": "CKA Labs",
+                "description": "Hands-on CKA labs",
+                "voice_channel": "K8s | CKA",
+                "notify_channel": "events",
+                "days": ["saturday"],
+                "time": "10:00",
+                "timezone": "America/Lima",
+                "duration_minutes": 90,
+                "capacity": 10,
+            },
+        ],
+        "digest_time": "08:00",
+        "digest_channel": "digest",
+        "weekly_digest_day": "monday",
+        "weekly_digest_time": "09:00",
+        "partners": [
+            {
+                "name": "Partner Community",
+                "guild_id": PARTNER_GUILD,
+                "channel": "community-events",
+                "schedules": ["CKA Labs"],
+            }
+        ],
+    }
+
+
+async def test_week_of_schedules(tmp_path: Path):
+    """Test a week of events: creation, announcements, reminders, starts, and digests."""
+    async with Scenario(tmp_path, week_config(), MONDAY) as scenario:
+        await scenario.run_until(MONDAY + 7 * DAY)
+
+    scenario.assert_golden("week")
+
+
+async def test_reconnect_mid_week(tmp_path: Path):
+    """Test that reconnecting doesn't announce or post the digest twice."""
+    async with Scenario(tmp_path, week_config(), MONDAY) as scenario:
+        # Between the reminders and the start of Wednesday's session
+        await scenario.run_until(MONDAY + 2 * DAY + timedelta(hours=17, minutes=30))
+        await scenario.reconnect()
+        await scenario.run_until(MONDAY + 4 * DAY)
+
+    scenario.assert_golden("reconnect")
+
+
+async def test_config_reload(tmp_path: Path):
+    """Test that editing the schedules file moves, cancels, and adds events from then on."""
+    config = week_config()
+    async with Scenario(tmp_path, config, MONDAY) as scenario:
+        await scenario.run_until(MONDAY + timedelta(hours=12))
+
+        # Monday's session moves an hour later; Wednesday's is dropped for Tuesday's
+        config["schedules"][0] |= {"time": "19:00", "days": ["monday", "tuesday"]}
+        scenario.write_schedules(config)
+        scenario.server.note("schedules file edited")
+        await scenario.run_until(MONDAY + 3 * DAY)
+
+    scenario.assert_golden("config_reload")
+
+
+async def test_command_interactions(tmp_path: Path):
+    """Test commands changing what the loops do: skipping a day, opting out, the digest."""
+    async with Scenario(tmp_path, week_config(), MONDAY + 4 * DAY) as scenario:
+        await scenario.command("SchedulesCog", "schedules_skip", "KCNA Study", "2025-03-17")
+        await scenario.command("PartnersCog", "optout", True, author=scenario.partner_admin)
+        await scenario.command("PartnersCog", "list_partners")
+        await scenario.run_until(MONDAY + 6 * DAY)
+        await scenario.command("DigestCog", "digest_now")
+        await scenario.run_until(MONDAY + 8 * DAY)
+
+    scenario.assert_golden("commands")
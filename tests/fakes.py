@@ -0,0 +1,212 @@
+"""Test doubles for the Discord client, so cogs can be tested without the API.
+
+Cogs only reach Discord through the bot's caches (`get_guild`, `get_channel`,
+`get_user`) and its messenger, so `FakeBot` stands in for both: channels and
+members are plain objects, and every message is recorded instead of sent.
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from types import SimpleNamespace
+from typing import Any
+
+import discord
+
+from cnayp_bot.models import ScheduleConfig
+from cnayp_bot.services.components import ComponentRouter
+from cnayp_bot.services.dm_reminders import DmReminders
+from cnayp_bot.services.experiments import AnnouncementExperiments
+from cnayp_bot.services.history import EventHistory
+from cnayp_bot.services.interest import InterestTracker
+from cnayp_bot.services.meetings import MeetingLinks
+from cnayp_bot.services.partners import PartnerAnnouncements
+from cnayp_bot.services.partials import TemplatePartials
+from cnayp_bot.services.rsvps import RsvpList
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.template_values import TemplateValues
+
+
+@dataclass
+class SentMessage:
+    """A message the bot would have sent."""
+
+    channel: Any
+    content: str | None
+    embed: discord.Embed | None = None
+    allowed_mentions: discord.AllowedMentions | None = None
+    silent: bool = False
+
+
+@dataclass
+class FakeRole:
+    id: int
+    name: str
+
+    @property
+    def mention(self) -> str:
+        return f"<@&{self.id}>"
+
+
+@dataclass
+class FakeMember:
+    id: int
+    name: str = "member"
+    bot: bool = False
+
+
+@dataclass
+class FakeChannel:
+    """A text or voice channel; members are who's in the call."""
+
+    id: int
+    name: str
+    guild: "FakeGuild"
+    public: bool = True
+    members: list[FakeMember] = field(default_factory=list)
+
+    def permissions_for(self, target: Any) -> discord.Permissions:
+        if target is self.guild.default_role and not self.public:
+            return discord.Permissions.none()
+        return discord.Permissions.all()
+
+
+@dataclass
+class FakeGuild:
+    id: int
+    channels: list[FakeChannel] = field(default_factory=list)
+    roles: list[FakeRole] = field(default_factory=list)
+    threads: list[Any] = field(default_factory=list)
+    scheduled_events: list["FakeScheduledEvent"] = field(default_factory=list)
+    default_role: FakeRole = field(default_factory=lambda: FakeRole(0, "@everyone"))
+    me: FakeMember = field(default_factory=lambda: FakeMember(1, "bot", bot=True))
+    members: list[Any] = field(default_factory=list)
+    chunked: bool = True
+
+    @property
+    def text_channels(self) -> list[FakeChannel]:
+        return self.channels
+
+    def get_role(self, role_id: int) -> FakeRole | None:
+        return next((role for role in self.roles if role.id == role_id), None)
+
+    async def fetch_channels(self) -> list[FakeChannel]:
+        return list(self.channels)
+
+    def get_scheduled_event(self, event_id: int) -> "FakeScheduledEvent | None":
+        return next((event for event in self.scheduled_events if event.id == event_id), None)
+
+    def add_channel(self, channel_id: int, name: str, **kwargs: Any) -> FakeChannel:
+        channel = FakeChannel(channel_id, name, self, **kwargs)
+        self.channels.append(channel)
+        return channel
+
+
+@dataclass
+class FakeScheduledEvent:
+    """A Discord scheduled event, changed in place when edited or cancelled."""
+
+    id: int
+    start_time: Any = None
+    end_time: Any = None
+    status: str = "scheduled"
+
+    async def edit(self, *, start_time: Any, end_time: Any, **kwargs: Any) -> None:
+        self.start_time, self.end_time = start_time, end_time
+
+    async def cancel(self, **kwargs: Any) -> None:
+        self.status = "canceled"
+
+
+class FakeMessenger:
+    """Messenger recording messages, ops alerts, audit entries, and DMs instead of sending them."""
+
+    def __init__(self) -> None:
+        self.sent: list[SentMessage] = []
+        self.alerts: list[str] = []
+        self.audit_entries: list[str] = []
+
+    async def send(
+        self,
+        channel: Any,
+        content: str | None = None,
+        *,
+        embed: discord.Embed | None = None,
+        allowed_mentions: discord.AllowedMentions | None = None,
+        silent: bool = False,
+        **kwargs: Any,
+    ) -> None:
+        self.sent.append(SentMessage(channel, content, embed, allowed_mentions, silent))
+
+    async def alert_ops(self, message: str) -> None:
+        self.alerts.append(message)
+
+    async def audit_log(self, message: str) -> None:
+        self.audit_entries.append(message)
+
+    def sent_to(self, target: Any) -> list[SentMessage]:
+        """Return the messages sent in a channel or to a user."""
+        return [message for message in self.sent if message.channel is target]
+
+
+class FakeResponse:
+    """Interaction response remembering the ephemeral replies sent."""
+
+    def __init__(self) -> None:
+        self.replies: list[str] = []
+
+    async def send_message(self, content: str, *, ephemeral: bool = False, **kwargs: Any) -> None:
+        self.replies.append(content)
+
+
+class FakeInteraction:
+    """A member pressing a button or picking menu `values`, on the message `message_id`."""
+
+    def __init__(
+        self, user_id: int, values: list[str] | None = None, message_id: int | None = None
+    ) -> None:
+        self.user = FakeMember(user_id)
+        self.data = {"values": values or []}
+        self.message = SimpleNamespace(id=message_id, jump_url="") if message_id else None
+        self.response = FakeResponse()
+
+
+class FakeBot:
+    """The parts of `CNAYPBot` cogs use, backed by fake guilds and a real store."""
+
+    def __init__(self, tmp_path: Path, *guilds: FakeGuild) -> None:
+        self.guilds = list(guilds)
+        self.store = Store(tmp_path / "store.json")
+        self.schedules = SimpleNamespace(config=ScheduleConfig())
+        self.rsvps = RsvpList(self.store)
+        self.interest = InterestTracker(self.store)
+        self.dm_reminders = DmReminders(self.store)
+        self.experiments = AnnouncementExperiments(self.store)
+        self.components = ComponentRouter(b"test-secret")
+        self.history = EventHistory(self.store)
+        self.meetings = MeetingLinks(self.store)
+        self.partners = PartnerAnnouncements(self.store)
+        self.partials = TemplatePartials(tmp_path / "templates")
+        self.template_values = TemplateValues(self.store)
+        self.messenger = FakeMessenger()
+        self.users: dict[int, SimpleNamespace] = {}
+        self.cogs: dict[str, Any] = {}
+        self.dispatched: list[tuple[str, tuple]] = []
+
+    def get_cog(self, name: str) -> Any:
+        return self.cogs.get(name)
+
+    def dispatch(self, event: str, *args: Any) -> None:
+        self.dispatched.append((event, args))
+
+    def get_guild(self, guild_id: int) -> FakeGuild | None:
+        return next((guild for guild in self.guilds if guild.id == guild_id), None)
+
+    def get_channel(self, channel_id: int) -> FakeChannel | None:
+        channels = (channel for guild in self.guilds for channel in guild.channels)
+        return next((channel for channel in channels if channel.id == channel_id), None)
+
+    def get_user(self, user_id: int) -> SimpleNamespace:
+        return self.users.setdefault(user_id, SimpleNamespace(id=user_id))
+
+    async def fetch_user(self, user_id: int) -> SimpleNamespace:
+        return self.get_user(user_id)
@@ -0,0 +1,59 @@
+"""Tests for schedule owner away notices."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.models import Schedule
+from cnayp_bot.services.absences import Absences
+from cnayp_bot.services.store import Store
+
+START = datetime(2025, 3, 10, tzinfo=ZoneInfo("America/Lima"))
+END = START + timedelta(days=7)
+
+SCHEDULE = Schedule(
+    name="KCNA Session",
+    description="Study session",
+    voice_channel="K8s | KCNA",
+    notify_channel="events",
+    days=["monday"],
+    time="18:00",
+    timezone="America/Lima",
+    duration_minutes=120,
+    owners=[1, 2],
+    co_hosts=[3],
+)
+
+
+def test_away_owners_during_notice(tmp_path: Path):
+    """Test that owners are away only within their notice, and it survives a restart."""
+    path = tmp_path / "store.json"
+    Absences(Store(path)).set(1, START, END, "Vacation")
+    absences = Absences(Store(path))
+
+    assert absences.away_owners(SCHEDULE, START + timedelta(hours=18)) == [1]
+    assert absences.away_owners(SCHEDULE, START - timedelta(minutes=1)) == []
+    assert absences.away_owners(SCHEDULE, END) == []
+    assert absences.get(1, START)["reason"] == "Vacation"
+
+
+def test_clear_and_replace(tmp_path: Path):
+    """Test that a new notice replaces the old one and clearing removes it."""
+    absences = Absences(Store(tmp_path / "store.json"))
+    absences.set(2, START, END, "Vacation")
+    absences.set(2, END, END + timedelta(days=1), "Conference")
+
+    assert absences.get(2, START) is None
+    assert absences.get(2, END)["reason"] == "Conference"
+    assert absences.clear(2)
+    assert not absences.clear(2)
+
+
+def test_ended_notices_are_forgotten(tmp_path: Path):
+    """Test that setting a notice drops the ones that already ended."""
+    store = Store(tmp_path / "store.json")
+    absences = Absences(store)
+    absences.set(1, START, END, "Vacation")
+    absences.set(2, END + timedelta(days=1), END + timedelta(days=2), "Trip")
+
+    assert store.items("absences").keys() == {"2"}
@@ -0,0 +1,76 @@
+"""Tests for channel activity tracking."""
+
+from datetime import date, timedelta
+from pathlib import Path
+
+from cnayp_bot.services.activity import RETENTION_DAYS, ActivityTracker, extract_emoji
+from cnayp_bot.services.store import Store
+
+MONDAY = date(2025, 3, 3)
+
+
+def test_extract_emoji_in_order():
+    """Test that custom and unicode emoji are found in order of appearance."""
+    content = "great session 🎉 <:kubernetes:123456> see you 👋 <a:party:42>"
+
+    assert extract_emoji(content) == ["🎉", "<:kubernetes:123456>", "👋", "<a:party:42>"]
+    assert extract_emoji("no emoji here :)") == []
+
+
+def test_report_totals_flushed_days(tmp_path: Path):
+    """Test that counts from several flushes add up per channel over the range."""
+    path = tmp_path / "store.json"
+    tracker = ActivityTracker(Store(path))
+    tracker.record_message(MONDAY, 1, 10, "hi 🎉")
+    tracker.record_message(MONDAY, 1, 11, "hello")
+    tracker.record_reaction(MONDAY, "👍")
+    tracker.flush(MONDAY)
+    tracker.record_message(MONDAY + timedelta(days=1), 1, 10, "🎉🎉")
+    tracker.record_message(MONDAY + timedelta(days=1), 2, 12, "question")
+    tracker.flush(MONDAY + timedelta(days=1))
+
+    report = ActivityTracker(Store(path)).report(MONDAY, MONDAY + timedelta(days=6))
+    assert report.messages == {1: 3, 2: 1}
+    assert report.channel_users == {1: {10, 11}, 2: {12}}
+    assert report.active_users == 3
+    assert report.emoji == {"🎉": 3}
+    assert report.reactions == {"👍": 1}
+
+
+def test_report_excludes_days_outside_range(tmp_path: Path):
+    """Test that only days within the range are counted."""
+    tracker = ActivityTracker(Store(tmp_path / "store.json"))
+    tracker.record_message(MONDAY - timedelta(days=1), 1, 10, "old")
+    tracker.record_message(MONDAY, 1, 10, "new")
+    tracker.flush(MONDAY)
+
+    assert tracker.report(MONDAY, MONDAY).messages == {1: 1}
+
+
+def test_flush_drops_days_past_retention(tmp_path: Path):
+    """Test that old days are removed from the store."""
+    tracker = ActivityTracker(Store(tmp_path / "store.json"))
+    tracker.record_message(MONDAY, 1, 10, "hi")
+    tracker.flush(MONDAY)
+    tracker.flush(MONDAY + timedelta(days=RETENTION_DAYS + 1))
+
+    assert tracker.report(MONDAY, MONDAY).messages == {}
+
+
+def test_inactive_members_since_their_last_activity(tmp_path: Path):
+    """Test that members not seen since the cutoff are listed with the day they last were."""
+    path = tmp_path / "store.json"
+    tracker = ActivityTracker(Store(path))
+    tracker.record_message(MONDAY, 1, 10, "hi")
+    tracker.record_reaction(MONDAY, "👍", 11)
+    tracker.flush(MONDAY)
+    tracker.record_voice(MONDAY + timedelta(days=20), 11)
+    tracker.record_message(MONDAY + timedelta(days=20), 1, 12, "back")
+    tracker.flush(MONDAY + timedelta(days=20))
+
+    tracker = ActivityTracker(Store(path))
+    assert tracker.last_active_since() == MONDAY
+    assert tracker.inactive([10, 11, 12, 13], MONDAY + timedelta(days=10)) == {
+        10: MONDAY,
+        13: None,
+    }
@@ -0,0 +1,86 @@
+"""Tests for rendering Alertmanager notifications."""
+
+import discord
+
+from cnayp_bot.helpers.alerts import MAX_ALERTS, alert_line, render_alerts, silence_matchers
+from cnayp_bot.services.alertmanager import group_id
+
+
+def _alert(name: str, status: str = "firing", severity: str = "warning", **annotations) -> dict:
+    return {
+        "status": status,
+        "labels": {"alertname": name, "instance": "node-1:9100", "severity": severity},
+        "annotations": annotations,
+        "startsAt": "2025-03-03T19:00:00.123456789Z",
+        "endsAt": "0001-01-01T00:00:00Z",
+        "generatorURL": "http://prometheus:9090/graph",
+    }
+
+
+def _payload(*alerts: dict, status: str = "firing") -> dict:
+    return {
+        "status": status,
+        "groupKey": '{}:{alertname="HighLatency"}',
+        "groupLabels": {"alertname": "HighLatency"},
+        "commonLabels": {"alertname": "HighLatency", "job": "api"},
+        "commonAnnotations": {"summary": "API latency is high"},
+        "externalURL": "http://alertmanager:9093",
+        "alerts": list(alerts),
+    }
+
+
+def test_firing_group_takes_its_most_severe_color():
+    """Test the title and color of a firing group with mixed severities."""
+    payload = _payload(_alert("HighLatency"), _alert("HighLatency", severity="critical"))
+
+    embed = render_alerts(payload)
+
+    assert embed.title == "🔥 FIRING (2): HighLatency"
+    assert embed.color == discord.Color.red()
+    assert embed.footer.text == "alertname=HighLatency"
+
+
+def test_resolved_group_is_green():
+    """Test that a resolved notification is green and lists resolved alerts."""
+    alert = _alert("HighLatency", status="resolved", summary="p99 over 2s")
+    alert["endsAt"] = "2025-03-03T19:30:00Z"
+
+    embed = render_alerts(_payload(alert, status="resolved"))
+
+    assert embed.title == "✅ RESOLVED: HighLatency"
+    assert embed.color == discord.Color.green()
+    assert embed.fields[0].value.startswith("p99 over 2s\nResolved <t:1741030200:R>")
+
+
+def test_alert_line_names_the_target():
+    """Test the field describing one alert."""
+    name, value = alert_line(_alert("DiskFull", severity="critical", description="Disk at 95%"))
+
+    assert name == "🔴 DiskFull · node-1:9100"
+    assert value == "Disk at 95%\nSince <t:1741028400:R>\n[Source](http://prometheus:9090/graph)"
+
+
+def test_large_groups_are_counted_in_the_footer():
+    """Test that alerts past the limit are summarized instead of listed."""
+    embed = render_alerts(_payload(*[_alert("HighLatency") for _ in range(MAX_ALERTS + 3)]))
+
+    assert len(embed.fields) == MAX_ALERTS
+    assert embed.footer.text == "…and 3 more alerts · alertname=HighLatency"
+
+
+def test_silences_match_the_group_labels():
+    """Test that silences use the group labels, or the common labels without grouping."""
+    payload = _payload(_alert("HighLatency"))
+
+    assert silence_matchers(payload) == {"alertname": "HighLatency"}
+    payload["groupLabels"] = {}
+    assert silence_matchers(payload) == {"alertname": "HighLatency", "job": "api"}
+
+
+def test_group_ids_are_short_and_stable():
+    """Test that group keys become IDs that fit in a button."""
+    key = '{}:{alertname="HighLatency"}'
+
+    assert group_id(key) == group_id(key)
+    assert len(group_id(key)) == 16
+    assert group_id(key) != group_id('{}:{alertname="DiskFull"}')
@@ -0,0 +1,28 @@
+"""Tests for the audit trail."""
+
+from pathlib import Path
+
+import pytest
+
+from cnayp_bot.services import audit
+from cnayp_bot.services.audit import AUDIT_LOG, AuditTrail
+from cnayp_bot.services.store import Store
+
+
+def test_latest_entries_come_first_and_old_ones_are_dropped(
+    tmp_path: Path, monkeypatch: pytest.MonkeyPatch
+):
+    """Test that entries are listed newest first and capped at `MAX_ENTRIES`."""
+    monkeypatch.setattr(audit, "MAX_ENTRIES", 2)
+    store = Store(tmp_path / "store.json")
+    trail = AuditTrail(store)
+
+    trail.record(1, "ana", "state namespaces", "ok")
+    trail.record(1, "ana", "state get rsvps", "ok")
+    trail.record(2, "luis", "trigger schedule 1", "denied: not a maintainer")
+
+    assert [entry["action"] for entry in trail.recent(5)] == [
+        "trigger schedule 1",
+        "state get rsvps",
+    ]
+    assert len(store.items(AUDIT_LOG)) == 2
@@ -0,0 +1,75 @@
+"""Tests for AutoMod rules kept in a file and compared with the guild's."""
+
+from types import SimpleNamespace
+
+import pytest
+
+from cnayp_bot.models import AutomodConfig, AutomodRule
+from cnayp_bot.services.automod_rules import desired_state, plan_changes
+
+GUILD = SimpleNamespace(
+    roles=[SimpleNamespace(id=10, name="Moderators")],
+    text_channels=[SimpleNamespace(id=20, name="mod-log")],
+    channels=[SimpleNamespace(id=20, name="mod-log"), SimpleNamespace(id=21, name="memes")],
+)
+
+
+def make_rule(**overrides) -> AutomodRule:
+    data = {"name": "Scam phrases", "trigger": "keyword", "keywords": ["free nitro*"]}
+    return AutomodRule.model_validate(data | overrides)
+
+
+def test_rules_need_a_trigger_and_an_action():
+    """Test that keyword rules need keywords and every rule needs an action."""
+    with pytest.raises(ValueError, match="needs keywords"):
+        make_rule(keywords=[])
+    with pytest.raises(ValueError, match="needs block"):
+        make_rule(block=False)
+    with pytest.raises(ValueError, match="1 to 60"):
+        make_rule(keywords=["x" * 61])
+
+    assert make_rule(trigger="mention_spam", keywords=[]).mention_limit == 5
+
+
+def test_rule_names_are_unique():
+    """Test that two rules can't share a name, since rules are matched by name."""
+    rule = {"name": "Scam phrases", "trigger": "keyword", "keywords": ["scam"]}
+    with pytest.raises(ValueError, match="Duplicate"):
+        AutomodConfig.model_validate({"rules": [rule, rule]})
+
+
+def test_desired_state_finds_roles_and_channels():
+    """Test that role and channel names become IDs, and unknown names are rejected."""
+    rule = make_rule(
+        alert_channel="mod-log", exempt_roles=["Moderators"], exempt_channels=["memes"]
+    )
+
+    state = desired_state(rule, GUILD)
+
+    assert state.alert_channel_id == 20
+    assert state.exempt_role_ids == {10}
+    assert state.exempt_channel_ids == {21}
+    assert state.mention_limit is None
+    with pytest.raises(ValueError, match="Unknown role 'Admins'"):
+        desired_state(make_rule(exempt_roles=["Admins"]), GUILD)
+
+
+def test_plan_creates_edits_and_replaces_by_name():
+    """Test that only rules differing from the file change, and other rules are left alone."""
+    scams = desired_state(make_rule(), GUILD)
+    spam = desired_state(make_rule(name="Mentions", trigger="mention_spam"), GUILD)
+    links = desired_state(make_rule(name="Links", keywords=["bit.ly"]), GUILD)
+    invites = desired_state(make_rule(name="Invites", keywords=["discord.gg"]), GUILD)
+    desired = {"Scam phrases": scams, "Mentions": spam, "Links": links, "Invites": invites}
+    current = {
+        "Scam phrases": scams,
+        "Mentions": links,
+        "Links": desired_state(make_rule(name="Links", keywords=["tinyurl"]), GUILD),
+        "Made in Discord": spam,
+    }
+
+    plan = plan_changes(desired, current)
+
+    assert plan.create == ["Invites"]
+    assert plan.edit == ["Links"]
+    assert plan.replace == ["Mentions"]
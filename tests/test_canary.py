@@ -0,0 +1,40 @@
+"""Tests for soft-launching features in the canary channel."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.canary import Canary
+from cnayp_bot.services.store import Store
+
+NOW = datetime(2025, 3, 3, 12, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def test_features_move_to_their_channels_after_the_period(tmp_path: Path):
+    """Test that a canary feature posts in the canary channel until its period ends."""
+    canary = Canary(Store(tmp_path / "store.json"), "canary", ["digest"], timedelta(days=7))
+
+    assert canary.until("digest") is None
+    assert canary.route("digest", "events", NOW) == "canary"
+    assert canary.until("digest") == NOW + timedelta(days=7)
+    assert canary.route("digest", "events", NOW + timedelta(days=6)) == "canary"
+    assert canary.route("digest", "events", NOW + timedelta(days=7)) == "events"
+    assert canary.route("announcements", "events", NOW) == "events"
+
+
+def test_promoted_features_move_right_away(tmp_path: Path):
+    """Test that promoting a feature ends its canary period."""
+    canary = Canary(Store(tmp_path / "store.json"), "canary", ["digest"], timedelta(days=7))
+    canary.route("digest", "events", NOW)
+
+    canary.promote("digest", NOW + timedelta(hours=1))
+
+    assert canary.route("digest", "events", NOW + timedelta(hours=1)) == "events"
+
+
+def test_no_canary_channel_means_real_channels(tmp_path: Path):
+    """Test that features post in their real channels without a canary channel."""
+    canary = Canary(Store(tmp_path / "store.json"), None, ["digest"], timedelta(days=7))
+
+    assert canary.route("digest", "events", NOW) == "events"
+    assert canary.until("digest") is None
@@ -0,0 +1,32 @@
+"""Tests for release notes rendering."""
+
+from cnayp_bot.helpers.changelog import render_changelog
+
+REPO = "kenesparta/discord-cnayp-bots"
+
+
+def test_render_changelog():
+    """Test that GitHub markdown becomes Discord-friendly."""
+    body = (
+        "<!-- Release notes generated by GitHub -->\n"
+        "## What's Changed\n"
+        "* Add waitlists by @ana-k in https://github.com/kenesparta/discord-cnayp-bots/pull/42\n"
+        "* Bump deps in https://github.com/other/repo/issues/7\n"
+        "![screenshot](https://example.com/shot.png)\n\n\n\n"
+        "```python\n# not a heading @nobody\n```"
+    )
+    assert render_changelog(body, REPO) == (
+        "__**What's Changed**__\n"
+        "* Add waitlists by [@ana-k](https://github.com/ana-k) in "
+        "[#42](https://github.com/kenesparta/discord-cnayp-bots/pull/42)\n"
+        "* Bump deps in [other/repo#7](https://github.com/other/repo/issues/7)\n"
+        "[screenshot](https://example.com/shot.png)\n\n"
+        "```python\n# not a heading @nobody\n```"
+    )
+
+
+def test_render_keeps_existing_links():
+    """Test that references already inside a markdown link aren't linked twice."""
+    body = "See [the PR](https://github.com/kenesparta/discord-cnayp-bots/pull/42)"
+    assert render_changelog(body, REPO) == body
+
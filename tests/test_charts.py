@@ -0,0 +1,23 @@
+"""Tests for text bar charts."""
+
+from cnayp_bot.helpers.charts import bar, bar_chart
+
+
+def test_bar_scales_to_maximum():
+    """Test that bars scale to the width with partial blocks."""
+    assert bar(10, 10, width=4) == "████"
+    assert bar(5, 10, width=4) == "██"
+    assert bar(3, 16, width=4) == "▊"
+    assert bar(0, 10) == ""
+
+
+def test_bar_never_hides_small_values():
+    """Test that any positive value gets at least a sliver."""
+    assert bar(1, 1000, width=4) == "▏"
+
+
+def test_bar_chart_rows():
+    """Test that every row is padded to the same width and labelled."""
+    lines = bar_chart([("#general", 4), ("#help", 2)], width=4)
+
+    assert lines == ["`████` 4 #general", "`██  ` 2 #help"]
@@ -0,0 +1,60 @@
+"""Tests for schedule checklists."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.checklists import Checklists
+from cnayp_bot.services.store import Store
+
+START = datetime(2025, 3, 10, 19, 0, tzinfo=ZoneInfo("UTC"))
+DAY = timedelta(days=1)
+
+
+def _checklists(tmp_path: Path) -> tuple[Checklists, str]:
+    checklists = Checklists(Store(tmp_path / "store.json"))
+    checklist_id = checklists.open(
+        "schedule-kcna-study-2025-03-10", "KCNA Study", START, ["Book speaker", "Prepare slides"]
+    )
+    checklists.set_message(checklist_id, 10, 20)
+    return checklists, checklist_id
+
+
+def test_items_are_ticked_and_unticked(tmp_path: Path):
+    """Test that pressing an item twice ticks it off and back on."""
+    checklists, checklist_id = _checklists(tmp_path)
+
+    assert checklists.toggle(checklist_id, 0, 100) is True
+    assert checklists.get(checklist_id)["items"][0]["done_by"] == 100
+    assert checklists.toggle(checklist_id, 0, 200) is False
+    assert checklists.get(checklist_id)["items"][0]["done_by"] is None
+    assert checklists.find("schedule-kcna-study-2025-03-10") == checklist_id
+
+
+def test_owners_are_nagged_once_about_open_items(tmp_path: Path):
+    """Test that reminders are due the day before, only while items are open."""
+    checklists, checklist_id = _checklists(tmp_path)
+
+    assert checklists.due_nags(START - 2 * DAY, DAY) == []
+    assert checklists.due_nags(START - DAY, DAY) == [checklist_id]
+    checklists.mark_nagged(checklist_id)
+    assert checklists.due_nags(START - DAY, DAY) == []
+
+
+def test_finished_checklists_are_not_nagged(tmp_path: Path):
+    """Test that checklists with every item done get no reminder."""
+    checklists, checklist_id = _checklists(tmp_path)
+
+    checklists.toggle(checklist_id, 0, 100)
+    checklists.toggle(checklist_id, 1, 100)
+    assert checklists.due_nags(START - DAY, DAY) == []
+
+
+def test_checklists_are_forgotten_after_their_event(tmp_path: Path):
+    """Test that checklists are dropped a day after their event started."""
+    checklists, checklist_id = _checklists(tmp_path)
+
+    checklists.forget_finished(START + timedelta(hours=2))
+    assert checklists.get(checklist_id) is not None
+    checklists.forget_finished(START + 2 * DAY)
+    assert checklists.get(checklist_id) is None
@@ -0,0 +1,27 @@
+"""Tests for splitting long messages."""
+
+from cnayp_bot.helpers.chunking import split_message
+
+
+def test_split_message_at_line_breaks():
+    """Test that chunks stay under the limit and only break between lines."""
+    text = "\n".join(f"line {i:02d}" for i in range(10))
+
+    chunks = split_message(text, limit=30)
+    assert all(len(chunk) <= 30 for chunk in chunks)
+    assert "\n".join(chunks) == text
+
+
+def test_split_message_reopens_code_blocks():
+    """Test that a code block split in two is closed and reopened."""
+    text = "Intro\n```yaml\n" + "\n".join(f"key{i}: value" for i in range(6)) + "\n```\nDone"
+
+    chunks = split_message(text, limit=50)
+    assert all(len(chunk) <= 50 for chunk in chunks)
+    assert all(chunk.count("```") % 2 == 0 for chunk in chunks)
+    assert chunks[1].startswith("```yaml\n")
+
+
+def test_split_message_cuts_long_lines():
+    """Test that a line longer than the limit is cut into pieces."""
+    assert split_message("x" * 25, limit=10) == ["x" * 10, "x" * 10, "x" * 5]
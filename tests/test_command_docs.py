@@ -0,0 +1,34 @@
+"""Tests for reading usage and examples from command docstrings."""
+
+from cnayp_bot.helpers.command_docs import CommandDoc, command_usages, parse_command_doc
+
+
+def test_docstring_parts():
+    """Test that the summary, details, usages, and examples are told apart."""
+    doc = parse_command_doc(
+        """Give a member a role that's revoked automatically.
+
+        Grants survive restarts.
+
+        Usage: !role grant @user <role> --for <duration>
+        Example: !role grant @ana Speaker --for 7d
+        Example: !role grant @lu Mentor --for 2w
+        """
+    )
+
+    assert doc == CommandDoc(
+        summary="Give a member a role that's revoked automatically.",
+        details="Grants survive restarts.",
+        usages=["!role grant @user <role> --for <duration>"],
+        examples=["!role grant @ana Speaker --for 7d", "!role grant @lu Mentor --for 2w"],
+    )
+    assert parse_command_doc(None) == CommandDoc(summary="")
+
+
+def test_usages_use_the_members_prefix():
+    """Test that every command in a usage line gets the prefix, and the signature is a fallback."""
+    doc = parse_command_doc("Grant roles.\n\nUsage: !role grant @user | !role grants")
+
+    assert command_usages(doc, "?", "role", "") == ["?role grant @user | ?role grants"]
+    assert command_usages(CommandDoc("Ping."), "!", "ping", "") == ["!ping"]
+    assert command_usages(CommandDoc("Say."), "!", "say", "<text>") == ["!say <text>"]
@@ -0,0 +1,53 @@
+"""Tests for component custom ID encoding."""
+
+import pytest
+
+from cnayp_bot.services.components import ComponentRouter
+
+
+def test_round_trip():
+    """Test that a custom ID decodes back to its name and payload."""
+    router = ComponentRouter(b"secret")
+    custom_id = router.custom_id("rsvp", "event123:yes")
+
+    assert router.decode(custom_id) == ("rsvp", "event123:yes")
+
+
+def test_empty_payload():
+    """Test custom IDs without a payload."""
+    router = ComponentRouter(b"secret")
+
+    assert router.decode(router.custom_id("refresh")) == ("refresh", "")
+
+
+def test_rejects_tampered_payload():
+    """Test that editing the payload invalidates the signature."""
+    router = ComponentRouter(b"secret")
+    name, payload, signature = router.custom_id("rsvp", "event123").split(":")
+
+    assert router.decode(f"{name}:event999:{signature}") is None
+
+
+def test_rejects_other_secret():
+    """Test that IDs signed with another secret are rejected."""
+    custom_id = ComponentRouter(b"secret").custom_id("rsvp", "event123")
+
+    assert ComponentRouter(b"other").decode(custom_id) is None
+
+
+@pytest.mark.parametrize("custom_id", ["", "rsvp", "rsvp:event123", "persistent_view:abc"])
+def test_rejects_malformed(custom_id: str):
+    """Test that foreign or malformed IDs are ignored."""
+    assert ComponentRouter(b"secret").decode(custom_id) is None
+
+
+def test_rejects_too_long():
+    """Test that IDs over Discord's limit raise."""
+    with pytest.raises(ValueError):
+        ComponentRouter(b"secret").custom_id("rsvp", "x" * 100)
+
+
+def test_rejects_colon_in_name():
+    """Test that handler names can't contain the separator."""
+    with pytest.raises(ValueError):
+        ComponentRouter(b"secret").register("a:b", lambda interaction, payload: None)
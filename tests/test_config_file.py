@@ -0,0 +1,132 @@
+"""Tests for the TOML config file."""
+
+import tomllib
+from pathlib import Path
+
+import pytest
+
+from cnayp_bot.check import check
+from cnayp_bot.config import Settings
+from cnayp_bot.helpers.config_file import dump_toml
+from cnayp_bot.models import Schedule
+from cnayp_bot.services.schedules import load_schedule_config, save_schedule_config
+
+CONFIG = """\
+digest_time = "08:00"
+digest_channel = "events"
+
+[settings]
+discord_guild_id = 123
+google_calendar_id = "calendar@example.com"
+log_level = "DEBUG"
+templates_dir = "config/templates"
+
+[[schedules]]
+name = "KCNA Study"
+description = "Weekly \\"KCNA\\" study group"
+voice_channel = "K8s | KCNA"
+notify_channel = "events"
+days = ["monday", "wednesday"]
+time = "19:00"
+timezone = "America/Lima"
+duration_minutes = 90
+owners = [42]
+"""
+
+
+def test_dump_toml_round_trips():
+    """Test that dumped TOML reads back as the same data, without None values."""
+    data = {
+        "name": "Study",
+        "enabled": True,
+        "ratio": 0.5,
+        "days": ["monday"],
+        "note": None,
+        "followup": {"minutes": 30, "template": "Thanks, {title}!"},
+        "mirrors": [{"guild_id": "2", "voice_channel": "Voz"}],
+        "week_themes": {"2025-03-10": "Helm"},
+        "items": [{"a": 1}, {"b": [1, 2]}],
+    }
+
+    dumped = tomllib.loads(dump_toml(data))
+
+    assert dumped == {key: value for key, value in data.items() if value is not None}
+
+
+def test_toml_config_keeps_settings_on_save(tmp_path: Path):
+    """Test that schedules load from the config file and saving keeps its settings."""
+    path = tmp_path / "bot.toml"
+    path.write_text(CONFIG, encoding="utf-8")
+
+    config = load_schedule_config(path)
+    assert config.digest_time == "08:00"
+    assert config.schedules[0].description == 'Weekly "KCNA" study group'
+
+    config.schedules.append(
+        Schedule(
+            name="CKA Labs",
+            description="Labs",
+            voice_channel="K8s | CKA",
+            notify_channel="events",
+            days=["saturday"],
+            time="10:00",
+            timezone="America/Lima",
+            duration_minutes=60,
+        )
+    )
+    save_schedule_config(config, path)
+
+    assert load_schedule_config(path) == config
+    assert tomllib.loads(path.read_text(encoding="utf-8"))["settings"]["log_level"] == "DEBUG"
+
+
+def test_settings_from_config_file(tmp_path: Path, monkeypatch: pytest.MonkeyPatch):
+    """Test that the config file's settings apply under the environment's, with a token file."""
+    monkeypatch.chdir(tmp_path)
+    (tmp_path / "bot.toml").write_text(CONFIG, encoding="utf-8")
+    (tmp_path / "token").write_text("file-token\n", encoding="utf-8")
+    monkeypatch.setenv("CONFIG_FILE", "bot.toml")
+    monkeypatch.setenv("DISCORD_BOT_TOKEN_FILE", "token")
+    monkeypatch.setenv("LOG_LEVEL", "WARNING")
+
+    settings = Settings()
+
+    assert settings.templates_dir == "config/templates"
+    assert settings.log_level == "WARNING"
+    assert settings.discord_bot_token == "file-token"
+    assert settings.schedules_file == "bot.toml"
+
+
+def test_check_reports_every_problem(tmp_path: Path, monkeypatch: pytest.MonkeyPatch):
+    """Test that the check lists unknown settings and each malformed value at once."""
+    monkeypatch.chdir(tmp_path)
+    config = (
+        CONFIG.replace('"DEBUG"', '"LOUD"\ntypo = 1')
+        .replace('"19:00"', '"7pm"')
+        .replace('"America/Lima"', '"Lima"')
+        .replace('"wednesday"', '"wed"')
+    )
+    (tmp_path / "bot.toml").write_text(config, encoding="utf-8")
+    monkeypatch.setenv("CONFIG_FILE", "bot.toml")
+
+    problems = check(offline=True)
+
+    assert problems == [
+        "bot.toml [settings]: unknown setting typo",
+        "settings: log_level: Input should be 'DEBUG', 'INFO', 'WARNING' or 'ERROR'",
+        "bot.toml: schedules.0.days: 'wed' isn't a day of the week, e.g. monday",
+        "bot.toml: schedules.0.time: '7pm' isn't a 24-hour time like 19:00",
+        "bot.toml: schedules.0.timezone: Unknown timezone 'Lima', "
+        "use an IANA name like America/Lima",
+    ]
+
+
+def test_check_reports_toml_syntax_errors(tmp_path: Path, monkeypatch: pytest.MonkeyPatch):
+    """Test that a config file that doesn't parse is reported with where it fails."""
+    monkeypatch.chdir(tmp_path)
+    (tmp_path / "bot.toml").write_text('digest_time = "08:00\n', encoding="utf-8")
+    monkeypatch.setenv("CONFIG_FILE", "bot.toml")
+
+    [problem] = check(offline=True)
+
+    assert problem.startswith("bot.toml: ") and "line 1" in problem
@@ -0,0 +1,35 @@
+"""Tests for the snapshots of the schedules file taken before the bot changes it."""
+
+from pathlib import Path
+
+from cnayp_bot.models import ScheduleConfig
+from cnayp_bot.services.config_snapshots import MAX_SNAPSHOTS, ConfigSnapshots
+from cnayp_bot.services.schedules import ScheduleService
+from cnayp_bot.services.store import Store
+
+
+def test_replacing_the_config_snapshots_the_previous_one(tmp_path: Path):
+    """Test that the config in use is snapshotted with the reason it's replaced."""
+    snapshots = ConfigSnapshots(Store(tmp_path / "store.json"))
+    service = ScheduleService(tmp_path / "schedules.json", snapshots)
+
+    service.replace(ScheduleConfig(digest_channel="events"), "first")
+    service.replace(ScheduleConfig(digest_channel="general"), "second")
+
+    [(newest, snapshot), (oldest, _)] = snapshots.list()
+    assert (newest, oldest) == (2, 1)
+    assert snapshot["reason"] == "second"
+    assert snapshots.config(newest).digest_channel == "events"
+    assert snapshots.config(oldest) == ScheduleConfig()
+    assert snapshots.config(3) is None
+
+
+def test_oldest_snapshots_are_dropped(tmp_path: Path):
+    """Test that only the latest snapshots are kept, and IDs keep counting up."""
+    snapshots = ConfigSnapshots(Store(tmp_path / "store.json"))
+
+    for _ in range(MAX_SNAPSHOTS + 2):
+        snapshots.save(ScheduleConfig(), "change")
+
+    ids = [snapshot_id for snapshot_id, _ in snapshots.list()]
+    assert ids == list(range(MAX_SNAPSHOTS + 2, 2, -1))
@@ -0,0 +1,32 @@
+"""Tests for the debug console's operations."""
+
+from pathlib import Path
+
+import pytest
+
+from cnayp_bot.cogs.console import ConsoleCog, ConsoleError, parse_operation
+
+from .fakes import FakeBot, FakeGuild
+
+
+def test_operations_are_split_like_a_shell():
+    """Test that quotes group words and `--dry-run` is a flag anywhere."""
+    assert parse_operation('trigger schedule "KCNA Study" --dry-run') == (
+        ["trigger", "schedule", "KCNA Study"],
+        True,
+    )
+    assert parse_operation("state get rsvps") == (["state", "get", "rsvps"], False)
+    with pytest.raises(ConsoleError):
+        parse_operation('state get "rsvps')
+
+
+async def test_state_is_read_from_the_store(tmp_path: Path):
+    """Test that values are shown as JSON, and unknown operations list the valid ones."""
+    guild = FakeGuild(1)
+    cog = ConsoleCog(FakeBot(tmp_path, guild))
+    cog.bot.store.set("rsvps", "evt1", {"seats": 3})
+
+    assert await cog.run(["state", "get", "rsvps", "evt1"], False, guild) == '{\n  "seats": 3\n}'
+    assert await cog.run(["state", "namespaces"], False, guild) == '{\n  "rsvps": 1\n}'
+    with pytest.raises(ConsoleError, match="Unknown operation"):
+        await cog.run(["eval", "1 + 1"], False, guild)
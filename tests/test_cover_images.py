@@ -0,0 +1,47 @@
+"""Tests for loading cover images of Discord events."""
+
+from pathlib import Path
+
+import pytest
+
+from cnayp_bot.services.cover_images import (
+    MAX_IMAGE_BYTES,
+    CoverImageError,
+    CoverImages,
+    data_uri,
+    image_type,
+)
+
+PNG = b"\x89PNG\r\n\x1a\n" + b"\x00" * 16
+
+
+def test_image_types_are_told_by_their_leading_bytes():
+    """Test that the formats Discord takes are recognized, and others rejected."""
+    assert image_type(PNG) == "image/png"
+    assert image_type(b"\xff\xd8\xff\xe0rest") == "image/jpeg"
+    assert image_type(b"GIF89a...") == "image/gif"
+    assert image_type(b"RIFF\x00\x00\x00\x00WEBPVP8 ") == "image/webp"
+    with pytest.raises(CoverImageError):
+        image_type(b"<svg xmlns='http://www.w3.org/2000/svg'/>")
+
+
+def test_data_uri():
+    """Test that images are encoded as base64 data URIs with their type."""
+    assert data_uri(b"GIF89a") == "data:image/gif;base64,R0lGODlh"
+
+
+async def test_files_are_read_relative_to_the_schedules_file(tmp_path: Path):
+    """Test that relative paths are read from the base directory and checked."""
+    (tmp_path / "covers").mkdir()
+    (tmp_path / "covers" / "kcna.png").write_bytes(PNG)
+    (tmp_path / "notes.txt").write_text("not an image")
+    (tmp_path / "huge.png").write_bytes(PNG + b"\x00" * MAX_IMAGE_BYTES)
+    images = CoverImages(tmp_path)
+
+    assert await images.load("covers/kcna.png") == PNG
+    with pytest.raises(CoverImageError, match="can't read"):
+        await images.load("covers/missing.png")
+    with pytest.raises(CoverImageError, match="not a PNG"):
+        await images.load("notes.txt")
+    with pytest.raises(CoverImageError, match="over 10 MB"):
+        await images.load("huge.png")
@@ -0,0 +1,31 @@
+"""Tests for crash reports of background tasks."""
+
+import asyncio
+
+import pytest
+
+from cnayp_bot.services import crash_reports as crash_reports_module
+from cnayp_bot.services.crash_reports import watch_task
+
+
+async def test_failed_background_tasks_are_reported(monkeypatch: pytest.MonkeyPatch):
+    """Test that a watched task that fails is reported with its subsystem and payload."""
+    captured = []
+    monkeypatch.setattr(
+        crash_reports_module,
+        "capture_exception",
+        lambda error, subsystem, payload=None: captured.append((str(error), subsystem, payload)),
+    )
+
+    async def fail() -> None:
+        raise RuntimeError("boom")
+
+    async def succeed() -> None:
+        return None
+
+    failing = watch_task(asyncio.create_task(fail()), "alertmanager", {"groupKey": "g"})
+    passing = watch_task(asyncio.create_task(succeed()), "alertmanager")
+    await asyncio.gather(failing, passing, return_exceptions=True)
+    await asyncio.sleep(0)
+
+    assert captured == [("boom", "alertmanager", {"groupKey": "g"})]
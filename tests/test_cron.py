@@ -0,0 +1,50 @@
+"""Tests for cron expressions."""
+
+from datetime import date, time
+
+import pytest
+
+from cnayp_bot.helpers.cron import parse_cron
+
+MARCH = [date(2025, 3, day) for day in range(1, 32)]
+
+
+def test_nth_weekday_of_the_month():
+    """Test that TUE#1 runs on the first Tuesday only."""
+    cron = parse_cron("0 19 * * TUE#1")
+
+    assert [day for day in MARCH if cron.matches(day)] == [date(2025, 3, 4)]
+    assert cron.times() == [time(19, 0)]
+
+
+def test_days_of_the_month_or_week():
+    """Test that a restricted day of the month and day of the week either match."""
+    cron = parse_cron("30 18 1,15 * 5")
+
+    assert [day.day for day in MARCH if cron.matches(day)] == [1, 7, 14, 15, 21, 28]
+
+
+def test_ranges_steps_and_names():
+    """Test ranges, steps, and month and weekday names."""
+    cron = parse_cron("*/30 9-10 * jan-mar mon-fri")
+
+    assert cron.times() == [time(9, 0), time(9, 30), time(10, 0), time(10, 30)]
+    assert cron.matches(date(2025, 3, 3))
+    assert not cron.matches(date(2025, 3, 1))
+    assert not cron.matches(date(2025, 4, 1))
+
+
+def test_steps_over_every_day_are_not_restrictions():
+    """Test that a day of the month like */2 doesn't widen a day of the week to either."""
+    cron = parse_cron("0 19 */2 * FRI")
+
+    assert [day.day for day in MARCH if cron.matches(day)] == [7, 21]
+
+
+@pytest.mark.parametrize(
+    "expression", ["0 19 * *", "61 * * * *", "0 0 * * 2#6", "*/0 * * * *", "0 0 5-1 * *"]
+)
+def test_invalid_expressions_are_rejected(expression: str):
+    """Test that malformed fields and out of range values are rejected."""
+    with pytest.raises(ValueError):
+        parse_cron(expression)
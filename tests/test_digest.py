@@ -0,0 +1,145 @@
+"""Tests for the RSVP and reminder menus under the daily digest."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from types import SimpleNamespace
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.cogs.digest import DigestCog
+from cnayp_bot.cogs.reminders import REMINDERS
+from cnayp_bot.cogs.scheduler import SchedulerCog
+from cnayp_bot.models import Schedule, ScheduleConfig
+from cnayp_bot.services.calendar import CalendarEvent
+
+from .fakes import FakeBot, FakeInteraction
+
+NOW = datetime(2025, 3, 3, 12, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def make_event(event_id: str, start: datetime, **schedule) -> CalendarEvent:
+    """Create an event, of a schedule with the given fields if any."""
+    data = {
+        "name": f"Event {event_id}",
+        "voice_channel": "Voice",
+        "notify_channel": "events",
+        "days": ["monday"],
+        "time": "18:00",
+        "timezone": "UTC",
+        "duration_minutes": 60,
+    }
+    return CalendarEvent(
+        id=event_id,
+        name=f"Event {event_id}",
+        description="",
+        start_time=start,
+        end_time=start + timedelta(hours=1),
+        timezone="UTC",
+        schedule=Schedule.model_validate(data | schedule) if schedule else None,
+    )
+
+
+def make_digest(tmp_path: Path, *events: CalendarEvent) -> DigestCog:
+    bot = FakeBot(tmp_path)
+    scheduler = SchedulerCog(bot)
+    scheduler.known_events = {event.id: event for event in events}
+    bot.cogs["SchedulerCog"] = scheduler
+    return DigestCog(bot)
+
+
+def test_menus_list_the_events_still_to_come(tmp_path: Path):
+    """Test that started events are left out, and only announced events take RSVPs."""
+    past = make_event("past", NOW - timedelta(hours=2))
+    talk = make_event("talk", NOW + timedelta(hours=2))
+    meetup = make_event("meetup", NOW + timedelta(hours=4))
+    digest = make_digest(tmp_path, past, talk, meetup)
+    digest.bot.rsvps.open("talk", 100, "Event talk", None, talk.end_time)
+
+    rsvp, remind = digest._build_view(NOW, [past, talk, meetup]).children
+
+    assert [option.value for option in rsvp.options] == ["talk"]
+    assert [option.value for option in remind.options] == ["talk", "meetup"]
+
+
+async def test_digest_rsvp_joins_the_event(tmp_path: Path):
+    """Test that picking an event in the RSVP menu RSVPs the member going."""
+    talk = make_event("talk", datetime.now(ZoneInfo("UTC")) + timedelta(hours=2))
+    digest = make_digest(tmp_path, talk)
+    digest.bot.rsvps.open("talk", 100, "Event talk", 1, talk.end_time)
+    interaction = FakeInteraction(5, ["talk"])
+
+    await digest.digest_action(interaction, "rsvp")
+
+    assert digest.bot.rsvps.get("talk")["going"] == [5]
+    assert interaction.response.replies == ["You have a seat at **Event talk**. See you there!"]
+
+
+async def test_digest_reminder_is_set_once(tmp_path: Path):
+    """Test that picking an event in the reminder menu DMs the member before it, once."""
+    talk = make_event("talk", datetime.now(ZoneInfo("UTC")) + timedelta(hours=2))
+    digest = make_digest(tmp_path, talk)
+
+    await digest.digest_action(FakeInteraction(5, ["talk"]), "remind")
+    await digest.digest_action(FakeInteraction(5, ["talk"]), "remind")
+
+    [reminder] = digest.bot.store.items(REMINDERS).values()
+    assert reminder["user_id"] == 5
+    assert reminder["channel_id"] is None
+    assert datetime.fromisoformat(reminder["due"]) == talk.start_time - timedelta(minutes=10)
+
+
+def test_digest_templates_replace_the_defaults(tmp_path: Path):
+    """Test that the digest's title, lines, and empty day text come from the templates."""
+    talk = make_event("talk", NOW + timedelta(hours=2))
+    digest = make_digest(tmp_path, talk)
+    digest.bot.schedules.config = ScheduleConfig(
+        digest_line_template="- {time} {name} ({duration} min)",
+        digest_title_template="Eventos de hoy, {date}",
+        digest_empty_text="No hay eventos hoy.",
+    )
+
+    [page] = digest._build_embeds(NOW, [talk])
+    [empty] = digest._build_embeds(NOW, [])
+
+    assert page.title == "Eventos de hoy, 2025-03-03"
+    assert page.description == f"- <t:{int(talk.start_time.timestamp())}:t> Event talk (60 min)"
+    assert empty.description == "No hay eventos hoy."
+
+
+def test_weekly_digest_groups_events_by_day(tmp_path: Path):
+    """Test that the weekly overview lists each day's events under its own heading."""
+    talk = make_event("talk", NOW + timedelta(hours=2))
+    meetup = make_event("meetup", NOW + timedelta(days=2))
+    digest = make_digest(tmp_path, talk, meetup)
+
+    [page] = digest._build_weekly_embeds(NOW, [talk, meetup])
+
+    assert page.title == "This Week's Events — March 3 to March 9"
+    assert page.description == (
+        f"**Monday, March 3**\n{digest.digest_line(talk)}\n\n"
+        f"**Wednesday, March 5**\n{digest.digest_line(meetup)}"
+    )
+
+
+def test_weekly_digest_looks_past_the_scheduler(tmp_path: Path):
+    """Test that the weekly overview lists events further out than the scheduler fetched."""
+    talk = make_event("talk", NOW + timedelta(hours=2))
+    later = make_event("later", NOW + timedelta(days=5))
+    too_late = make_event("too-late", NOW + timedelta(days=8))
+    digest = make_digest(tmp_path, talk)
+    digest.bot.calendar = SimpleNamespace(get_upcoming_events=lambda hours_ahead: [later, too_late])
+    digest.bot.schedules.get_events_between = lambda start, end: []
+    digest.bot.submissions = SimpleNamespace(get_events_between=lambda start, end: [talk])
+
+    assert digest._digest_events(NOW, 7) == [talk, later]
+
+
+def test_digest_lists_the_guilds_events(tmp_path: Path):
+    """Test that each guild's digest lists its own events and those mirrored there."""
+    mirror = {"guild_id": 1, "voice_channel": "Voice", "notify_channel": "events"}
+    primary = make_event("primary", NOW + timedelta(hours=1))
+    other = make_event("other", NOW + timedelta(hours=2), guild_id=2)
+    mirrored = make_event("mirrored", NOW + timedelta(hours=3), guild_id=2, mirrors=[mirror])
+    digest = make_digest(tmp_path, primary, other, mirrored)
+
+    assert digest._digest_events(NOW) == [primary, mirrored]
+    assert digest._digest_events(NOW, guild_id=2) == [other, mirrored]
@@ -0,0 +1,23 @@
+"""Tests for the Discord API version and user agent of the bot's requests."""
+
+from types import SimpleNamespace
+
+import discord
+
+from cnayp_bot.services.discord_api import DEFAULT_API_VERSION, configure_http, route
+
+
+def test_routes_use_the_configured_version_and_user_agent():
+    """Test that routes go to the configured API version and the user agent is replaced."""
+    http = SimpleNamespace(user_agent="DiscordBot (https://github.com/Rapptz/discord.py 2.4)")
+    try:
+        configure_http(http, 11, "DiscordBot (https://example.com, 1.0) staging")
+
+        assert route("GET", "/guilds/{guild_id}", guild_id=1).url == (
+            "https://discord.com/api/v11/guilds/1"
+        )
+        assert http.user_agent == "DiscordBot (https://example.com, 1.0) staging"
+    finally:
+        configure_http(http, DEFAULT_API_VERSION, None)
+
+    assert discord.http.Route("GET", "/gateway").url == "https://discord.com/api/v10/gateway"
@@ -0,0 +1,21 @@
+"""Tests for the typed Discord API values."""
+
+import json
+
+import discord
+
+from cnayp_bot.helpers.discord_enums import EventStatus, RecurrenceFrequency
+
+
+def test_statuses_are_named_as_in_discord_py():
+    """Test that each status is the name of the same `discord.EventStatus`."""
+    for status in EventStatus:
+        assert getattr(discord.EventStatus, status.value).name == status
+
+
+def test_values_print_as_names_and_serialize_as_the_api_expects():
+    """Test that frequencies log by name but go to Discord and the store as numbers."""
+    assert str(RecurrenceFrequency.WEEKLY) == "weekly"
+    assert str(EventStatus.CANCELED) == "canceled"
+    assert json.dumps({"frequency": RecurrenceFrequency.DAILY}) == '{"frequency": 3}'
+    assert json.dumps({"status": EventStatus.ACTIVE}) == '{"status": "active"}'
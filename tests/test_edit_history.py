@@ -0,0 +1,69 @@
+"""Tests for the edit history of moderated channels."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.helpers.diff import render_diff
+from cnayp_bot.services.edit_history import MAX_EDITS, EditHistory
+from cnayp_bot.services.store import Store
+
+NOW = datetime(2025, 3, 3, 19, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def _history(tmp_path: Path) -> EditHistory:
+    return EditHistory(Store(tmp_path / "store.json"), timedelta(days=30))
+
+
+def test_edits_are_recorded_in_order(tmp_path: Path):
+    """Test that each edit of a message is kept with its author and channel."""
+    history = _history(tmp_path)
+
+    history.record(1, 10, 100, "helo", "hello", NOW)
+    history.record(1, 10, 100, "hello", "hello there", NOW + timedelta(minutes=1))
+
+    entry = history.get(1)
+    assert entry["channel_id"] == 10
+    assert entry["author_id"] == 100
+    assert [edit["after"] for edit in entry["edits"]] == ["hello", "hello there"]
+    assert history.get(2) is None
+
+
+def test_only_the_latest_edits_are_kept(tmp_path: Path):
+    """Test that messages edited over and over keep their latest edits."""
+    history = _history(tmp_path)
+
+    for i in range(MAX_EDITS + 5):
+        history.record(1, 10, 100, str(i), str(i + 1), NOW)
+
+    edits = history.get(1)["edits"]
+    assert len(edits) == MAX_EDITS
+    assert edits[0]["before"] == "5"
+
+
+def test_messages_not_edited_in_a_while_are_forgotten(tmp_path: Path):
+    """Test that the retention period counts from a message's last edit."""
+    history = _history(tmp_path)
+
+    history.record(1, 10, 100, "a", "b", NOW)
+    history.record(2, 10, 100, "a", "b", NOW)
+    history.record(2, 10, 100, "b", "c", NOW + timedelta(days=20))
+    history.record(3, 10, 100, "a", "b", NOW + timedelta(days=31))
+
+    assert history.get(1) is None
+    assert history.get(2) is not None
+
+
+def test_diffs_show_changed_lines():
+    """Test that a diff lists removed and added lines with some context."""
+    diff = render_diff("intro\nold line\noutro", "intro\nnew line\noutro", 4096)
+
+    assert diff == "```diff\n intro\n-old line\n+new line\n outro\n```"
+
+
+def test_long_diffs_are_cut():
+    """Test that a diff fits in the given length, fences included."""
+    diff = render_diff("a" * 2000, "b" * 2000, 1024)
+
+    assert len(diff) <= 1024
+    assert diff.endswith("…\n```")
@@ -0,0 +1,108 @@
+"""Tests for the embed builder."""
+
+import discord
+import pytest
+
+from cnayp_bot.helpers.embeds import (
+    DESCRIPTION_LIMIT,
+    FIELD_COUNT_LIMIT,
+    FIELD_VALUE_LIMIT,
+    TITLE_LIMIT,
+    TOTAL_LIMIT,
+    EmbedBuilder,
+    EmbedLimitError,
+)
+
+
+def test_build_embed():
+    """Test building an embed with the fluent API."""
+    embed = (
+        EmbedBuilder()
+        .set_title("Upcoming Events")
+        .set_description("This week")
+        .set_color(discord.Color.blue())
+        .add_field("Go Study", "Monday 18:00")
+        .set_footer("Showing 1 of 1 events")
+        .build()
+    )
+
+    assert embed.title == "Upcoming Events"
+    assert embed.description == "This week"
+    assert embed.fields[0].name == "Go Study"
+    assert embed.footer.text == "Showing 1 of 1 events"
+
+
+def test_title_too_long():
+    """Test that an oversized title is rejected."""
+    builder = EmbedBuilder().set_title("x" * (TITLE_LIMIT + 1))
+
+    with pytest.raises(EmbedLimitError, match="title"):
+        builder.build()
+
+
+def test_total_length_limit():
+    """Test that the 6000 character total is enforced across fields."""
+    builder = EmbedBuilder()
+    for index in range(6):
+        builder.add_field(f"Field {index}", "x" * 1000)
+
+    assert builder.total_length() > TOTAL_LIMIT
+    with pytest.raises(EmbedLimitError, match="in total"):
+        builder.validate()
+
+
+def test_can_add_field():
+    """Test field capacity checks."""
+    builder = EmbedBuilder()
+    for index in range(FIELD_COUNT_LIMIT):
+        assert builder.can_add_field(f"Field {index}", "value")
+        builder.add_field(f"Field {index}", "value")
+
+    assert not builder.can_add_field("One more", "value")
+
+
+def test_errors_lists_every_violation():
+    """Test that all violations are reported at once."""
+    builder = EmbedBuilder().set_title("x" * 300).add_field("", "value")
+
+    assert len(builder.errors()) == 2
+
+
+def test_build_pages_splits_long_descriptions():
+    """Test that a long description is spread over embeds at line breaks."""
+    lines = [f"• Event {i:04d} at 18:00" for i in range(400)]
+    pages = (
+        EmbedBuilder()
+        .set_title("Today's Events")
+        .set_description("\n".join(lines))
+        .set_footer("Updated hourly")
+        .build_pages()
+    )
+
+    assert len(pages) > 1
+    assert all(len(page.description) <= DESCRIPTION_LIMIT for page in pages)
+    assert "\n".join(page.description for page in pages) == "\n".join(lines)
+    assert [page.title for page in pages] == ["Today's Events"] + [None] * (len(pages) - 1)
+    assert pages[-1].footer.text == "Updated hourly"
+    assert pages[0].footer.text is None
+
+
+def test_build_pages_moves_and_continues_fields():
+    """Test that fields overflow to the next embed, and long values continue."""
+    builder = EmbedBuilder()
+    for i in range(FIELD_COUNT_LIMIT + 1):
+        builder.add_field(f"Field {i}", "value")
+    builder.add_field("Long", "\n".join(["x" * 100] * 15))
+
+    first, second = builder.build_pages()
+    assert len(first.fields) == FIELD_COUNT_LIMIT
+    assert [field.name for field in second.fields] == ["Field 25", "Long", "Long (cont.)"]
+    assert all(len(field.value) <= FIELD_VALUE_LIMIT for field in second.fields)
+
+
+def test_build_pages_fits_in_one_embed():
+    """Test that content within the limits stays a single embed."""
+    pages = EmbedBuilder().set_title("Stats").add_field("Events", "3").build_pages()
+
+    assert len(pages) == 1
+    assert pages[0].title == "Stats"
@@ -0,0 +1,73 @@
+"""Tests for announcement A/B experiments."""
+
+from pathlib import Path
+
+import pytest
+from pydantic import ValidationError
+
+from cnayp_bot.models import Schedule
+from cnayp_bot.services.experiments import AnnouncementExperiments, is_experiment
+from cnayp_bot.services.store import Store
+
+
+def make_schedule(**overrides) -> Schedule:
+    """Create a schedule for tests."""
+    data = {
+        "name": "KCNA Session",
+        "description": "Study session",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "events",
+        "days": ["monday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 120,
+        "announcement_templates": ["**{name}** {relative}", "Join us {when} in {where}! {link}"],
+    }
+    return Schedule.model_validate(data | overrides)
+
+
+def test_is_experiment_needs_two_templates():
+    """Test that only schedules with two templates run an experiment."""
+    assert is_experiment(make_schedule())
+    assert not is_experiment(make_schedule(announcement_templates=["{name}"]))
+    assert not is_experiment(None)
+
+
+def test_unknown_placeholder_is_rejected():
+    """Test that templates can only use placeholders the scheduler fills."""
+    with pytest.raises(ValidationError, match="Unknown placeholder"):
+        make_schedule(announcement_templates=["{name} at {venue}"])
+
+
+def test_variants_alternate_per_schedule(tmp_path: Path):
+    """Test that announcements alternate A, B, A independently for each schedule."""
+    experiments = AnnouncementExperiments(Store(tmp_path / "store.json"))
+    schedule = make_schedule()
+    other = make_schedule(name="Go Study")
+
+    variants = []
+    for day in range(3):
+        variant = experiments.next_variant(schedule)
+        experiments.record_announcement(f"evt{day}", schedule, variant, 1, 100 + day, 200 + day)
+        variants.append(variant)
+
+    assert variants == [0, 1, 0]
+    assert experiments.next_variant(other) == 0
+
+
+def test_summary_averages_measured_announcements(tmp_path: Path):
+    """Test that rates only count announcements whose event already started."""
+    experiments = AnnouncementExperiments(Store(tmp_path / "store.json"))
+    schedule = make_schedule()
+    experiments.record_announcement("evt1", schedule, 0, 1, 101, 201)
+    experiments.record_announcement("evt2", schedule, 1, 1, 102, 202)
+    experiments.record_announcement("evt3", schedule, 0, 1, 103, 203)
+    experiments.record_results("evt1", reactions=4, rsvps=10)
+    experiments.record_results("evt2", reactions=2, rsvps=6)
+    experiments.record_results("missing", reactions=9, rsvps=9)
+
+    a, b = experiments.summary()["KCNA Session"]
+    assert (a.label, a.announcements, a.measured) == ("A", 2, 1)
+    assert (a.reaction_rate, a.rsvp_rate) == (4.0, 10.0)
+    assert (b.label, b.announcements, b.measured) == ("B", 1, 1)
+    assert (b.reaction_rate, b.rsvp_rate) == (2.0, 6.0)
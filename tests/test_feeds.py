@@ -0,0 +1,72 @@
+"""Tests for feed subscriptions."""
+
+from pathlib import Path
+
+import pytest
+
+from cnayp_bot.services.feeds import FeedItem, FeedSubscriptions, parse_feed
+from cnayp_bot.services.store import Store
+
+RSS = """\
+<rss version="2.0"><channel>
+  <title>Kubernetes Blog</title>
+  <item><title>Kubernetes v1.33</title><link>https://kubernetes.io/blog/v1-33/</link>
+    <guid>https://kubernetes.io/blog/v1-33/</guid></item>
+  <item><title>No guid</title><link>https://kubernetes.io/blog/older/</link></item>
+</channel></rss>
+"""
+
+ATOM = """\
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>CNCF</title>
+  <entry><id>tag:cncf.io,2025:1</id><title>KubeCon recap</title>
+    <link href="https://cncf.io/recap/"/></entry>
+</feed>
+"""
+
+
+def test_parse_rss_and_atom():
+    """Test reading the items of RSS and Atom feeds, using links when there's no ID."""
+    release, older = "https://kubernetes.io/blog/v1-33/", "https://kubernetes.io/blog/older/"
+    assert parse_feed(RSS) == [
+        FeedItem(release, "Kubernetes v1.33", release),
+        FeedItem(older, "No guid", older),
+    ]
+    assert parse_feed(ATOM) == [
+        FeedItem("tag:cncf.io,2025:1", "KubeCon recap", "https://cncf.io/recap/")
+    ]
+    with pytest.raises(ValueError):
+        parse_feed("<html>")
+
+
+def test_subscriptions_are_kept_per_member(tmp_path: Path):
+    """Test subscribing and unsubscribing, once each."""
+    feeds = FeedSubscriptions(Store(tmp_path / "store.json"))
+
+    assert feeds.subscribe(1, "kubernetes-blog")
+    assert not feeds.subscribe(1, "kubernetes-blog")
+    assert feeds.subscribe(1, "cncf")
+    assert feeds.subscribe(2, "cncf")
+
+    assert feeds.subscriptions(1) == ["kubernetes-blog", "cncf"]
+    assert feeds.subscribers("cncf") == [1, 2]
+    assert feeds.unsubscribe(1, "cncf")
+    assert not feeds.unsubscribe(1, "cncf")
+    assert feeds.subscribers("cncf") == [2]
+
+
+def test_only_items_published_since_the_last_fetch_are_new(tmp_path: Path):
+    """Test that a feed's history isn't delivered, and newer items are, oldest first."""
+    feeds = FeedSubscriptions(Store(tmp_path / "store.json"))
+    feeds.subscribe(1, "blog")
+    old = FeedItem("1", "Old")
+    assert feeds.new_items("blog", [old]) == []
+
+    second, third = FeedItem("2", "Second"), FeedItem("3", "Third")
+    assert feeds.new_items("blog", [third, second, old]) == [second, third]
+    assert feeds.new_items("blog", [third, second, old]) == []
+
+    # Nobody was subscribed meanwhile, so what was published then isn't delivered
+    feeds.unsubscribe(1, "blog")
+    feeds.subscribe(2, "blog")
+    assert feeds.new_items("blog", [FeedItem("4", "Fourth"), third]) == []
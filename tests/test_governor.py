@@ -0,0 +1,127 @@
+"""Tests for the rate limit headroom governor."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.governor import (
+    CLOSED,
+    HALF_OPEN,
+    MAX_DELAY,
+    OPEN,
+    CircuitBreaker,
+    Priority,
+    RateGovernor,
+)
+
+NOW = datetime(2025, 3, 10, 18, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def test_headroom_counts_recent_requests():
+    """Test that requests and invalid requests age out of their windows."""
+    governor = RateGovernor()
+    governor.record_request(NOW - timedelta(seconds=2), "scheduler")
+    for _ in range(10):
+        governor.record_request(NOW, "scheduler")
+    governor.record_invalid(NOW - timedelta(minutes=11))
+    governor.record_invalid(NOW)
+
+    headroom = governor.headroom(NOW)
+
+    assert headroom.requests_per_second == 10
+    assert headroom.invalid_requests == 1
+    assert governor.requests["scheduler"] == 11
+    assert governor.invalid_total == 2
+
+
+def test_background_throttles_before_normal():
+    """Test that background work waits first and critical work never does."""
+    governor = RateGovernor()
+    for i in range(30):
+        governor.record_request(NOW - timedelta(milliseconds=500 - i), "scheduler")
+
+    assert governor.delay(NOW, Priority.BACKGROUND) > timedelta(0)
+    assert governor.delay(NOW, Priority.NORMAL) == timedelta(0)
+
+    for _ in range(15):
+        governor.record_request(NOW, "scheduler")
+
+    assert governor.delay(NOW, Priority.NORMAL) > timedelta(0)
+    assert governor.delay(NOW, Priority.CRITICAL) == timedelta(0)
+    assert governor.delay(NOW + timedelta(seconds=1), Priority.NORMAL) == timedelta(0)
+
+
+def test_invalid_requests_pause_background_work():
+    """Test that nearing the invalid request ban delays background work the longest."""
+    governor = RateGovernor()
+    for _ in range(5000):
+        governor.record_invalid(NOW)
+
+    assert governor.delay(NOW, Priority.BACKGROUND) == MAX_DELAY
+    assert governor.delay(NOW, Priority.NORMAL) == timedelta(0)
+
+
+def test_metrics_by_subsystem():
+    """Test that metrics include per-subsystem counters."""
+    governor = RateGovernor()
+    governor.record_request(datetime.now(ZoneInfo("UTC")), "voice_names")
+    governor.throttled["voice_names"] += 1
+
+    metrics = governor.metrics()
+
+    assert 'cnayp_rest_requests_total{subsystem="voice_names"} 1' in metrics
+    assert 'cnayp_rest_throttled_total{subsystem="voice_names"} 1' in metrics
+    assert "cnayp_rest_requests_per_second 1" in metrics
+
+
+def test_circuit_opens_after_failures_in_a_row():
+    """Test that the circuit holds back non-critical requests until a request goes through."""
+    states = []
+    breaker = CircuitBreaker(3, timedelta(seconds=60), on_change=states.append)
+
+    breaker.record_failure(NOW)
+    breaker.record_failure(NOW)
+    breaker.record_success()
+    breaker.record_failure(NOW)
+    breaker.record_failure(NOW)
+    assert breaker.allows(NOW, Priority.BACKGROUND)
+
+    breaker.record_failure(NOW)
+    assert breaker.state == OPEN
+    assert not breaker.allows(NOW, Priority.BACKGROUND)
+    assert not breaker.allows(NOW, Priority.NORMAL)
+    assert breaker.allows(NOW, Priority.CRITICAL)
+    assert breaker.retry_in(NOW + timedelta(seconds=20)) == timedelta(seconds=40)
+
+    later = NOW + timedelta(seconds=60)
+    assert breaker.allows(later, Priority.BACKGROUND)
+    assert breaker.state == HALF_OPEN
+    breaker.record_success()
+
+    assert states == [OPEN, HALF_OPEN, CLOSED]
+
+
+def test_half_open_circuit_reopens_on_failure():
+    """Test that a failure right after the cooldown opens the circuit again."""
+    breaker = CircuitBreaker(1, timedelta(seconds=60))
+    breaker.record_failure(NOW)
+    later = NOW + timedelta(seconds=61)
+    breaker.allows(later, Priority.NORMAL)
+
+    breaker.record_failure(later)
+
+    assert breaker.state == OPEN
+    assert breaker.opened_total == 2
+    assert not breaker.allows(later + timedelta(seconds=30), Priority.NORMAL)
+
+
+def test_circuit_metrics():
+    """Test that the circuit's state and shed requests are in the metrics."""
+    governor = RateGovernor(CircuitBreaker(1, timedelta(seconds=60)))
+    governor.breaker.record_failure(NOW)
+    governor.shed["digest"] += 2
+
+    metrics = governor.metrics()
+
+    assert "cnayp_rest_circuit_open 1" in metrics
+    assert "cnayp_rest_circuit_opened_total 1" in metrics
+    assert 'cnayp_rest_shed_total{subsystem="digest"} 2' in metrics
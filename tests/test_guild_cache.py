@@ -0,0 +1,64 @@
+"""Tests for channel name resolution kept current by gateway events."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.guild_cache import CHANNEL_CACHE_TTL, ChannelDirectory
+
+from .fakes import FakeGuild
+
+NOW = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("UTC"))
+
+
+class CountingGuild(FakeGuild):
+    """A guild counting how often its channels are fetched over REST."""
+
+    fetches = 0
+
+    async def fetch_channels(self) -> list:
+        self.fetches += 1
+        return await super().fetch_channels()
+
+
+async def test_renamed_and_deleted_channels_stop_resolving():
+    """Test that channel events update the names, instead of keeping the old ones forever."""
+    guild = FakeGuild(1)
+    channel = guild.add_channel(10, "events")
+    directory = ChannelDirectory()
+    assert await directory.resolve(guild, "events", NOW) == 10
+
+    channel.name = "announcements"
+    directory.update(channel)
+    assert await directory.resolve(guild, "announcements", NOW) == 10
+    guild.channels.remove(channel)
+    directory.remove(channel)
+
+    assert await directory.resolve(guild, "announcements", NOW) is None
+    assert await directory.resolve(guild, "events", NOW) is None
+
+
+async def test_names_are_read_again_after_the_ttl():
+    """Test that a rename whose gateway event was missed is picked up after the TTL."""
+    guild = FakeGuild(1)
+    channel = guild.add_channel(10, "events")
+    directory = ChannelDirectory()
+    await directory.resolve(guild, "events", NOW)
+
+    channel.name = "announcements"
+
+    assert await directory.resolve(guild, "events", NOW + timedelta(minutes=1)) == 10
+    assert await directory.resolve(guild, "events", NOW + CHANNEL_CACHE_TTL) is None
+
+
+async def test_unknown_names_are_fetched_once_per_ttl():
+    """Test that a misconfigured name doesn't fetch the channel list on every lookup."""
+    guild = CountingGuild(1)
+    guild.add_channel(10, "events")
+    directory = ChannelDirectory()
+
+    assert await directory.resolve(guild, "evnets", NOW) is None
+    assert await directory.resolve(guild, "evnets", NOW + timedelta(minutes=1)) is None
+    assert guild.fetches == 1
+
+    assert await directory.resolve(guild, "evnets", NOW + CHANNEL_CACHE_TTL) is None
+    assert guild.fetches == 2
@@ -0,0 +1,100 @@
+"""Tests for picking unanswered questions out of the help channel."""
+
+from datetime import datetime, timedelta
+from types import SimpleNamespace
+from zoneinfo import ZoneInfo
+
+import discord
+
+from cnayp_bot.helpers.help_questions import (
+    MAX_DIGEST_ENTRIES,
+    digest_lines,
+    has_reply,
+    help_digest_due,
+    question_window,
+    unanswered_messages,
+)
+
+NOW = datetime(2025, 3, 10, 18, 0, tzinfo=ZoneInfo("UTC"))
+ASKER = SimpleNamespace(id=5, bot=False, mention="<@5>")
+HELPER = SimpleNamespace(id=6, bot=False, mention="<@6>")
+BOT = SimpleNamespace(id=1, bot=True, mention="<@1>")
+
+
+def make_message(message_id: int, author=ASKER, minutes_ago: int = 180, **overrides):
+    """Create a message sent `minutes_ago` before `NOW`."""
+    data = {
+        "id": message_id,
+        "author": author,
+        "content": f"Question {message_id}?",
+        "created_at": NOW - timedelta(minutes=minutes_ago),
+        "type": discord.MessageType.default,
+        "reference": None,
+        "reactions": [],
+        "jump_url": f"https://discord.com/channels/1/2/{message_id}",
+    }
+    return SimpleNamespace(**(data | overrides))
+
+
+def test_digest_is_due_an_interval_after_the_last_run():
+    """Test that the first run only starts the clock, then digests come every interval."""
+    assert not help_digest_due(NOW, None, 24)
+    assert not help_digest_due(NOW, NOW - timedelta(hours=23, minutes=50), 24)
+    assert help_digest_due(NOW, NOW - timedelta(hours=24), 24)
+
+
+def test_window_picks_up_where_the_last_run_stopped():
+    """Test that consecutive windows trail the runs by the wait, without gaps or overlaps."""
+    last_run = NOW - timedelta(hours=24)
+
+    asked_after, answered_before = question_window(NOW, last_run, 120)
+    next_after, _ = question_window(NOW + timedelta(hours=24), NOW, 120)
+
+    assert asked_after == last_run - timedelta(hours=2)
+    assert answered_before == NOW - timedelta(hours=2)
+    assert next_after == answered_before
+
+
+def test_replied_reacted_recent_and_bot_messages_are_not_unanswered():
+    """Test that only old questions nobody replied or reacted to are picked."""
+    answered = make_message(2)
+    history = [
+        make_message(1),
+        answered,
+        make_message(3, reactions=["👍"]),
+        make_message(4, minutes_ago=30),
+        make_message(5, author=BOT),
+        make_message(6, type=discord.MessageType.pins_add),
+        # A reply after the window closed still answers the question
+        make_message(7, author=HELPER, minutes_ago=5, reference=SimpleNamespace(message_id=2)),
+    ]
+
+    unanswered = unanswered_messages(history, NOW - timedelta(hours=2))
+
+    assert [message.id for message in unanswered] == [1]
+
+
+def test_thread_reply_only_counts_from_someone_else():
+    """Test that the author's own follow-ups and bots don't answer a thread."""
+    starter = make_message(10)
+    own = make_message(11, minutes_ago=100)
+    bot = make_message(12, author=BOT)
+
+    assert not has_reply([starter, own, bot], 10, ASKER.id)
+    assert has_reply([starter, own, make_message(13, author=HELPER)], 10, ASKER.id)
+
+
+def test_digest_lines_shorten_snippets_and_stop_at_the_limit():
+    """Test that long questions are cut short, attachments labelled, and the list capped."""
+    questions = [
+        make_message(1, content="How do I\nconfigure " + "x" * 100),
+        make_message(2, content=""),
+        *(make_message(index) for index in range(3, MAX_DIGEST_ENTRIES + 5)),
+    ]
+
+    lines = digest_lines(questions)
+
+    assert len(lines) == MAX_DIGEST_ENTRIES
+    assert lines[0].startswith("• [How do I configure xxx")
+    assert "…](https://discord.com/channels/1/2/1) by <@5>" in lines[0]
+    assert lines[1] == "• [Attachment](https://discord.com/channels/1/2/2) by <@5>"
@@ -0,0 +1,131 @@
+"""Tests for the event history and attendance export."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.history import (
+    COLUMNS,
+    EventHistory,
+    attendance_rows,
+    minutes_attended,
+    render_csv,
+)
+from cnayp_bot.services.store import Store
+
+START = datetime(2025, 3, 3, 19, 0, tzinfo=ZoneInfo("UTC"))
+END = START + timedelta(minutes=90)
+
+
+def _history(tmp_path: Path) -> EventHistory:
+    history = EventHistory(Store(tmp_path / "store.json"))
+    history.start(
+        "study-1",
+        "KCNA Study",
+        START,
+        END,
+        10,
+        interested=12,
+        rsvps={"capacity": 5, "going": [1, 2, 3, 4, 5], "waitlist": [6]},
+        present=[1, 2],
+    )
+    return history
+
+
+def test_attendees_are_counted_while_the_event_runs(tmp_path: Path):
+    """Test that joins during the event count once, and other joins don't."""
+    history = _history(tmp_path)
+
+    history.attend(10, 3, START + timedelta(minutes=5))
+    history.attend(10, 3, START + timedelta(minutes=50))
+    history.attend(10, 1, START + timedelta(minutes=10))
+    history.attend(11, 4, START + timedelta(minutes=5))
+    history.attend(10, 5, END)
+
+    [(event_id, entry)] = history.occurrences(START - timedelta(days=1))
+    assert event_id == "study-1"
+    assert entry["attendees"] == [1, 2, 3]
+
+
+def test_join_and_leave_times_are_kept(tmp_path: Path):
+    """Test that visits are timed, and ones still open at the end count until then."""
+    history = _history(tmp_path)
+
+    history.leave(10, 1, START + timedelta(minutes=30))
+    history.attend(10, 3, START + timedelta(minutes=10))
+    history.leave(10, 3, START + timedelta(minutes=20))
+    history.attend(10, 3, START + timedelta(minutes=60))
+    history.attend(10, 3, START + timedelta(minutes=70))
+
+    entry = history.get("study-1")
+    assert len(entry["visits"]["3"]) == 2
+    assert minutes_attended(entry) == {1: 30, 2: 90, 3: 40}
+
+
+def test_latest_occurrence_is_found_by_name_or_id(tmp_path: Path):
+    """Test that attendance lookups match part of the name, or the event ID."""
+    history = _history(tmp_path)
+    later = START + timedelta(days=7)
+    history.start(
+        "study-2",
+        "KCNA Study",
+        later,
+        later + timedelta(hours=1),
+        10,
+        interested=0,
+        rsvps=None,
+        present=[],
+    )
+
+    assert history.find("kcna")[0] == "study-2"
+    assert history.find("study-1")[0] == "study-1"
+    assert history.find("CKA") is None
+
+
+def test_restarted_event_keeps_its_attendees(tmp_path: Path):
+    """Test that recording the start again after a restart doesn't reset attendance."""
+    history = _history(tmp_path)
+    history.attend(10, 3, START + timedelta(minutes=5))
+
+    history.start(
+        "study-1", "KCNA Study", START, END, 10, interested=0, rsvps=None, present=[4]
+    )
+
+    [(_, entry)] = history.occurrences(START)
+    assert entry["attendees"] == [1, 2, 3, 4]
+    assert entry["interested"] == 12
+
+
+def test_occurrences_are_filtered_and_old_ones_forgotten(tmp_path: Path):
+    """Test the since filter and the retention period."""
+    history = _history(tmp_path)
+    later = START + timedelta(days=500)
+    history.start(
+        "study-2",
+        "KCNA Study",
+        later,
+        later + timedelta(hours=1),
+        10,
+        interested=0,
+        rsvps=None,
+        present=[],
+    )
+
+    assert [event_id for event_id, _ in history.occurrences(START)] == ["study-2"]
+
+
+def test_rows_render_as_csv(tmp_path: Path):
+    """Test the exported table, with empty cells for RSVPs of uncapped events."""
+    history = _history(tmp_path)
+    history.start(
+        "talk-1", "Talk", END, END + timedelta(hours=1), 11, interested=3, rsvps=None, present=[7]
+    )
+
+    rows = attendance_rows(history.occurrences(START))
+
+    assert rows[0] == COLUMNS
+    assert rows[1][1:] == ["KCNA Study", START.isoformat(), END.isoformat(), 90, 12, 5, 5, 1, 2]
+    assert rows[2][6:] == ["", "", "", 1]
+    assert render_csv(rows).splitlines()[2] == (
+        "talk-1,Talk,2025-03-03T20:30:00+00:00,2025-03-03T21:30:00+00:00,60,3,,,,1"
+    )
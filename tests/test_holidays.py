@@ -0,0 +1,36 @@
+"""Tests for reading holidays from an iCal calendar."""
+
+from datetime import date
+
+from cnayp_bot.services.holidays import parse_holidays
+
+CALENDAR = """BEGIN:VCALENDAR\r
+BEGIN:VEVENT\r
+DTSTART;VALUE=DATE:20251224\r
+DTEND;VALUE=DATE:20251227\r
+SUMMARY:Year-end\r
+  break\r
+END:VEVENT\r
+BEGIN:VEVENT\r
+DTSTART:20250728T050000Z\r
+DTEND:20250729T050000Z\r
+SUMMARY:Fiestas Patrias\r
+END:VEVENT\r
+BEGIN:VEVENT\r
+DTSTART;VALUE=DATE:20250101\r
+DTEND;VALUE=DATE:20250601\r
+SUMMARY:Mistyped end\r
+END:VEVENT\r
+END:VCALENDAR\r
+"""
+
+
+def test_holidays_cover_each_day_of_their_events():
+    """Test that all-day events cover every day before their end, and others their start."""
+    assert parse_holidays(CALENDAR) == {
+        date(2025, 12, 24),
+        date(2025, 12, 25),
+        date(2025, 12, 26),
+        date(2025, 7, 28),
+        date(2025, 1, 1),
+    }
@@ -0,0 +1,29 @@
+"""Tests for command and reply translations."""
+
+import discord
+from discord import app_commands
+
+from cnayp_bot.helpers.i18n import CatalogTranslator, translate
+
+
+def test_translate_falls_back_to_english():
+    """Test that text is translated for Spanish clients and left alone otherwise."""
+    text = "No events scheduled in the next {days} days."
+
+    assert translate(text, discord.Locale.latin_american_spanish).format(days=3) == (
+        "No hay eventos programados en los próximos 3 días."
+    )
+    assert translate(text, discord.Locale.french) == text
+    assert translate(text, None) == text
+
+
+async def test_translator_localizes_command_names():
+    """Test that /events is registered as /horario for Spanish clients only."""
+    translator = CatalogTranslator()
+    context = app_commands.TranslationContext(
+        app_commands.TranslationContextLocation.command_name, None
+    )
+    events = app_commands.locale_str("events")
+
+    assert await translator.translate(events, discord.Locale.spain_spanish, context) == "horario"
+    assert await translator.translate(events, discord.Locale.french, context) is None
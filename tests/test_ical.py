@@ -0,0 +1,91 @@
+"""Tests for the iCal feed of the schedules."""
+
+from datetime import date, datetime
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.models import Schedule
+from cnayp_bot.services.ical import schedules_ical
+
+# A Sunday, before New York's daylight saving time starts on 2025-03-09
+NOW = datetime(2025, 3, 2, 12, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def make_schedule(**overrides) -> Schedule:
+    """Create a schedule for tests."""
+    data = {
+        "name": "KCNA Session",
+        "description": "Study session; bring questions",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "events",
+        "days": ["wednesday", "monday"],
+        "time": "18:00",
+        "timezone": "America/New_York",
+        "duration_minutes": 90,
+    }
+    return Schedule.model_validate(data | overrides)
+
+
+def test_weekly_schedule_repeats_without_skipped_days():
+    """Test that a weekly schedule is one repeating event without its excluded days and holidays."""
+    schedule = make_schedule(exclude_dates=[date(2025, 3, 10)])
+
+    feed = schedules_ical([schedule], {date(2025, 3, 12), date(2025, 3, 13)}, NOW, "CNAYP")
+    lines = feed.split("\r\n")
+
+    assert "UID:schedule-kcna-session@cnayp-bot" in lines
+    assert "DTSTART;TZID=America/New_York:20250303T180000" in lines
+    assert "DURATION:PT90M" in lines
+    assert "RRULE:FREQ=WEEKLY;BYDAY=MO,WE" in lines
+    assert "EXDATE;TZID=America/New_York:20250310T180000" in lines
+    assert "EXDATE;TZID=America/New_York:20250312T180000" in lines
+    # Holidays on days the schedule doesn't run aren't listed
+    assert not any("20250313" in line for line in lines)
+    assert r"DESCRIPTION:Study session\; bring questions" in lines
+
+
+def test_schedules_skipping_weeks_repeat_at_an_interval():
+    """Test that a schedule running every few weeks starts on an on week, not just next week."""
+    # The week of 2025-03-03 is off, so the feed starts on the week of 2025-03-10
+    schedule = make_schedule(every_weeks=2, starting=date(2025, 2, 24))
+    lines = schedules_ical([schedule], set(), NOW, "CNAYP").split("\r\n")
+
+    assert "DTSTART;TZID=America/New_York:20250310T180000" in lines
+    assert "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE" in lines
+
+
+def test_timezone_includes_daylight_saving_time_changes():
+    """Test that the feed describes the UTC offsets of the schedules' timezones."""
+    lines = schedules_ical([make_schedule()], set(), NOW, "CNAYP").split("\r\n")
+
+    start = lines.index("BEGIN:DAYLIGHT")
+    assert lines[start : start + 5] == [
+        "BEGIN:DAYLIGHT",
+        "DTSTART:20250309T020000",
+        "TZOFFSETFROM:-0500",
+        "TZOFFSETTO:-0400",
+        "TZNAME:EDT",
+    ]
+
+
+def test_private_disabled_and_past_schedules_are_left_out():
+    """Test that only enabled, public, upcoming events are in the feed."""
+    schedules = [
+        make_schedule(name="Planning", visibility="private", audience_role="Organizers"),
+        make_schedule(name="Paused", enabled=False),
+        make_schedule(name="Launch", days=[], date=date(2025, 3, 1)),
+        make_schedule(name="Monthly", days=[], time="", cron="0 19 * * TUE#1"),
+    ]
+
+    feed = schedules_ical(schedules, set(), NOW, "CNAYP")
+
+    assert "SUMMARY:Monthly" in feed
+    assert "UID:schedule-monthly-2025-03-04@cnayp-bot" in feed
+    assert "Planning" not in feed and "Paused" not in feed and "Launch" not in feed
+
+
+def test_long_lines_are_folded():
+    """Test that lines longer than 75 octets continue on lines starting with a space."""
+    feed = schedules_ical([make_schedule(description="ñ" * 100)], set(), NOW, "CNAYP")
+
+    assert all(len(line.encode()) <= 75 for line in feed.split("\r\n"))
+    assert "\r\n ñ" in feed
@@ -0,0 +1,51 @@
+"""Tests for the in-flight work the shutdown waits for."""
+
+import asyncio
+from types import SimpleNamespace
+
+from cnayp_bot.services.in_flight import InFlightWork, drained
+
+
+class LoopCog:
+    """A cog whose loop takes a while, and counts its runs."""
+
+    def __init__(self, work: InFlightWork) -> None:
+        self.bot = SimpleNamespace(in_flight=work)
+        self.runs = 0
+
+    @drained("reminders")
+    async def loop(self) -> None:
+        await asyncio.sleep(0.01)
+        self.runs += 1
+
+
+async def test_drain_waits_for_running_work_and_stops_new_work():
+    """Test that a loop already running finishes, and doesn't run again once draining."""
+    work = InFlightWork()
+    cog = LoopCog(work)
+    running = asyncio.create_task(cog.loop())
+    await asyncio.sleep(0)
+
+    assert work.running() == ["reminders"]
+    assert await work.drain(timeout=1)
+    await running
+    await cog.loop()
+
+    assert cog.runs == 1
+    assert work.running() == []
+
+
+async def test_drain_gives_up_after_the_timeout():
+    """Test that work still running after the timeout doesn't hold up the shutdown."""
+    work = InFlightWork()
+
+    async def stuck() -> None:
+        async with work.track("digest"):
+            await asyncio.sleep(10)
+
+    task = asyncio.create_task(stuck())
+    await asyncio.sleep(0)
+
+    assert not await work.drain(timeout=0.01)
+    assert work.running() == ["digest"]
+    task.cancel()
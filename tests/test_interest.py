@@ -0,0 +1,54 @@
+"""Tests for scheduled event interest tracking."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.services import interest as interest_module
+from cnayp_bot.services.interest import InterestTracker
+from cnayp_bot.services.store import Store
+
+START = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("America/Lima"))
+
+
+def test_add_and_remove_users(tmp_path: Path):
+    """Test that interest follows adds and removes and survives a restart."""
+    path = tmp_path / "store.json"
+    tracker = InterestTracker(Store(path))
+    tracker.add("evt1", "KCNA Session", START, 1)
+    tracker.add("evt1", "KCNA Session", START, 2)
+    tracker.add("evt1", "KCNA Session", START, 2)
+    tracker.remove("evt1", 1)
+    tracker.remove("evt1", 99)
+    tracker.remove("missing", 1)
+
+    assert InterestTracker(Store(path)).users("evt1") == [2]
+    assert tracker.users("missing") == []
+
+
+def test_series_counts_oldest_first(tmp_path: Path):
+    """Test that counts are grouped by series and ordered by occurrence."""
+    tracker = InterestTracker(Store(tmp_path / "store.json"))
+    next_week = START + timedelta(days=7)
+    tracker.add("evt2", "KCNA Session", next_week, 1)
+    tracker.add("evt1", "KCNA Session", START, 1)
+    tracker.add("evt1", "KCNA Session", START, 2)
+    tracker.add("go1", "Go Study", START, 3)
+
+    assert tracker.series_counts() == {
+        "KCNA Session": [(START, 2), (next_week, 1)],
+        "Go Study": [(START, 1)],
+    }
+
+
+def test_old_occurrences_are_dropped(tmp_path: Path, monkeypatch: pytest.MonkeyPatch):
+    """Test that only the most recent occurrences of a series are kept."""
+    monkeypatch.setattr(interest_module, "MAX_OCCURRENCES", 2)
+    tracker = InterestTracker(Store(tmp_path / "store.json"))
+    for week in range(3):
+        tracker.add(f"evt{week}", "KCNA Session", START + timedelta(days=7 * week), 1)
+
+    starts = [start for start, _ in tracker.series_counts()["KCNA Session"]]
+    assert starts == [START + timedelta(days=7), START + timedelta(days=14)]
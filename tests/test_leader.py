@@ -0,0 +1,99 @@
+"""Tests for leader election between replicas."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.config import Settings
+from cnayp_bot.services.leader import LeaderElection, owns_guild
+
+NOW = datetime(2025, 3, 10, 18, 0, tzinfo=ZoneInfo("UTC"))
+TTL = timedelta(seconds=30)
+
+
+def test_one_replica_holds_the_lease(tmp_path: Path):
+    """Test that a second replica waits until the leader's lease expires."""
+    path = tmp_path / "leader.json"
+    first = LeaderElection(path, "pod-0", TTL)
+    second = LeaderElection(path, "pod-1", TTL)
+
+    assert first.renew(NOW)
+    assert not second.renew(NOW + timedelta(seconds=10))
+    assert first.renew(NOW + timedelta(seconds=20))
+    assert not second.renew(NOW + timedelta(seconds=40))
+
+    # The leader stopped renewing, so its lease runs out
+    assert second.renew(NOW + timedelta(seconds=51))
+    assert not first.renew(NOW + timedelta(seconds=60))
+
+
+def test_release_hands_over_immediately(tmp_path: Path):
+    """Test that a released lease can be taken before it would have expired."""
+    path = tmp_path / "leader.json"
+    first = LeaderElection(path, "pod-0", TTL)
+    second = LeaderElection(path, "pod-1", TTL)
+    first.renew(NOW)
+
+    first.release(NOW + timedelta(seconds=1))
+
+    assert second.renew(NOW + timedelta(seconds=2))
+
+
+def test_corrupt_lease_is_free(tmp_path: Path):
+    """Test that a truncated or malformed lease file doesn't block every replica."""
+    path = tmp_path / "leader.json"
+    election = LeaderElection(path, "pod-0", TTL)
+
+    for contents in ['{"holder": "pod-1", "exp', '{"holder": "pod-1"}', '{"expires": "x"}', "[]"]:
+        path.write_text(contents, encoding="utf-8")
+        assert election.renew(NOW)
+
+    path.write_text('{"expires": "x"}', encoding="utf-8")
+    election.release(NOW)
+
+
+def test_replicas_waiting_for_the_lease_stand_by(tmp_path: Path):
+    """Test that only replicas sharing a lease they don't hold are standbys."""
+    path = tmp_path / "leader.json"
+    first = LeaderElection(path, "pod-0", TTL)
+    second = LeaderElection(path, "pod-1", TTL)
+    now = datetime.now(ZoneInfo("UTC"))
+    first.renew(now)
+    second.renew(now)
+
+    assert not first.is_standby
+    assert second.is_standby
+    assert not LeaderElection(None, "pod-0", TTL, eligible=False).is_standby
+
+
+def test_lease_needs_a_shared_store(monkeypatch: pytest.MonkeyPatch):
+    """Test that replicas sharing a lease must share a SQLite store beside it, unsharded."""
+    monkeypatch.setenv("LEADER_LEASE_PATH", "/shared/leader.json")
+
+    for store_path in ["data/store.db", "/shared/store.json"]:
+        monkeypatch.setenv("STORE_PATH", store_path)
+        with pytest.raises(ValueError, match="STORE_PATH"):
+            Settings()
+
+    monkeypatch.setenv("STORE_PATH", "/shared/store.db")
+    assert Settings().store_path == "/shared/store.db"
+
+    monkeypatch.setenv("SHARD_COUNT", "2")
+    with pytest.raises(ValueError, match="sharding"):
+        Settings()
+
+
+def test_without_lease_path_always_leads():
+    """Test that a single instance leads unless its shard doesn't hold the guild."""
+    assert LeaderElection(None, "pod-0", TTL).is_leader
+    assert not LeaderElection(None, "pod-0", TTL, eligible=False).is_leader
+
+
+def test_owns_guild_by_shard():
+    """Test that exactly one shard holds a guild."""
+    guild_id = 1234567890123456789
+    owners = [shard for shard in range(4) if owns_guild(guild_id, shard, 4)]
+    assert owners == [(guild_id >> 22) % 4]
+    assert owns_guild(guild_id, None, None)
@@ -0,0 +1,77 @@
+"""Tests for the link scanner."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.linkscan import (
+    VERDICT_TTL,
+    BlocklistProvider,
+    LinkScanner,
+    Verdict,
+    extract_urls,
+)
+
+NOW = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("UTC"))
+
+
+class CountingProvider:
+    """Flags every URL containing "bad", counting how many URLs it's asked about."""
+
+    name = "counting"
+
+    def __init__(self, fail: bool = False) -> None:
+        self.fail = fail
+        self.checked: list[str] = []
+
+    async def check(self, urls: list[str]) -> list[Verdict]:
+        self.checked += urls
+        if self.fail:
+            raise RuntimeError("provider down")
+        return [Verdict(url, self.name, "bad") for url in urls if "bad" in url]
+
+
+def test_extract_urls():
+    """Test that URLs are found once each, without trailing punctuation."""
+    text = "See https://example.com/a, and (http://bad.example/x). Again: https://example.com/a"
+    assert extract_urls(text) == ["https://example.com/a", "http://bad.example/x"]
+    assert extract_urls("no links here") == []
+
+
+async def test_blocklist_matches_subdomains(tmp_path: Path):
+    """Test that blocked domains also block their subdomains, and comments are ignored."""
+    path = tmp_path / "blocklist.txt"
+    path.write_text("# scams\nfree-nitro.gift\n\n", encoding="utf-8")
+    provider = BlocklistProvider.from_file(path)
+
+    verdicts = await provider.check(
+        ["https://cdn.free-nitro.gift/claim", "https://nitro.gift", "https://kubernetes.io"]
+    )
+    assert [verdict.url for verdict in verdicts] == ["https://cdn.free-nitro.gift/claim"]
+
+
+async def test_scanner_caches_verdicts():
+    """Test that verdicts are reused until they expire."""
+    provider = CountingProvider()
+    scanner = LinkScanner([provider])
+    urls = ["https://bad.example", "https://good.example"]
+
+    assert [verdict.url for verdict in await scanner.scan(urls, NOW)] == ["https://bad.example"]
+    assert [verdict.url for verdict in await scanner.scan(urls, NOW)] == ["https://bad.example"]
+    assert len(provider.checked) == 2
+
+    await scanner.scan(urls, NOW + VERDICT_TTL + timedelta(seconds=1))
+    assert len(provider.checked) == 4
+
+
+async def test_scanner_fails_open():
+    """Test that a failing provider doesn't block others or poison the cache."""
+    broken = CountingProvider(fail=True)
+    working = CountingProvider()
+    scanner = LinkScanner([broken, working])
+
+    verdicts = await scanner.scan(["https://bad.example"], NOW)
+    assert [verdict.url for verdict in verdicts] == ["https://bad.example"]
+
+    await scanner.scan(["https://bad.example"], NOW)
+    assert len(broken.checked) == 2
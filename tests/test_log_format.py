@@ -0,0 +1,36 @@
+"""Tests for text and JSON log lines with structured fields."""
+
+import json
+import logging
+
+from cnayp_bot.helpers.log_format import JsonFormatter, TextFormatter
+
+
+def make_record(**fields) -> logging.LogRecord:
+    """Create a log record with structured fields, as `extra` sets them."""
+    record = logging.LogRecord(
+        "cnayp_bot.cogs.scheduler", logging.INFO, __file__, 1, "Created %s", ("KCNA",), None
+    )
+    record.__dict__.update(fields)
+    return record
+
+
+def test_json_lines_include_fields():
+    """Test that JSON lines have the message and the fields that are set."""
+    line = JsonFormatter().format(make_record(event_id="evt1", guild_id=None, status=201))
+
+    entry = json.loads(line)
+    assert entry["message"] == "Created KCNA"
+    assert entry["level"] == "INFO"
+    assert entry["event_id"] == "evt1"
+    assert entry["status"] == 201
+    assert "guild_id" not in entry
+
+
+def test_text_lines_end_with_fields():
+    """Test that text lines keep the usual format with the fields appended as key=value."""
+    line = TextFormatter().format(make_record(schedule="KCNA Study", event_id="evt1"))
+
+    assert line.endswith(
+        ' - cnayp_bot.cogs.scheduler - INFO - Created KCNA schedule="KCNA Study" event_id=evt1'
+    )
@@ -0,0 +1,22 @@
+"""Tests for maintenance mode state."""
+
+from pathlib import Path
+
+from cnayp_bot.services.maintenance import Maintenance
+from cnayp_bot.services.store import Store
+
+
+def test_maintenance_survives_restart(tmp_path: Path):
+    """Test that maintenance mode and its notice persist until turned off."""
+    path = tmp_path / "store.json"
+    maintenance = Maintenance(Store(path))
+    assert not maintenance.active
+    assert maintenance.message is None
+
+    maintenance.start("Migrating schedules", 1)
+    restored = Maintenance(Store(path))
+    assert restored.active
+    assert restored.message == "Migrating schedules"
+
+    restored.stop()
+    assert not Maintenance(Store(path)).active
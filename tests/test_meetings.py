@@ -0,0 +1,60 @@
+"""Tests for the meeting links of hybrid events."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.models import Schedule
+from cnayp_bot.services.calendar import CalendarEvent
+from cnayp_bot.services.meetings import MeetingLinks
+from cnayp_bot.services.store import Store
+
+START = datetime(2025, 3, 10, 18, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def make_event(event_id: str, meeting: str | None = "zoom") -> CalendarEvent:
+    schedule = Schedule(
+        name="Hybrid meetup",
+        description="",
+        voice_channel="voice",
+        notify_channel="events",
+        days=["monday"],
+        time="18:00",
+        timezone="UTC",
+        duration_minutes=60,
+        meeting=meeting,
+    )
+    return CalendarEvent(
+        id=event_id,
+        name=schedule.name,
+        description="",
+        start_time=START,
+        end_time=START + timedelta(hours=1),
+        timezone="UTC",
+        schedule=schedule,
+    )
+
+
+async def test_each_occurrence_gets_its_own_meeting(
+    tmp_path: Path, monkeypatch: pytest.MonkeyPatch
+):
+    """Test that occurrences get separate links, reused until the event ends."""
+    meetings = MeetingLinks(Store(tmp_path / "store.json"))
+    created = []
+
+    async def create(event: CalendarEvent) -> str:
+        created.append(event.id)
+        return f"https://zoom.us/j/{len(created)}"
+
+    monkeypatch.setattr(meetings, "_create_zoom_meeting", create)
+
+    assert await meetings.link(make_event("a")) == "https://zoom.us/j/1"
+    assert await meetings.link(make_event("a")) == "https://zoom.us/j/1"
+    assert await meetings.link(make_event("b")) == "https://zoom.us/j/2"
+    assert await meetings.link(make_event("c", meeting=None)) is None
+
+    meetings.forget_finished(START + timedelta(hours=1))
+    assert await meetings.link(make_event("a")) == "https://zoom.us/j/3"
+    assert created == ["a", "b", "a"]
@@ -0,0 +1,48 @@
+"""Tests for message link and mention parsing."""
+
+from cnayp_bot.helpers.mentions import (
+    MessageLink,
+    parse_channel_mention,
+    parse_message_link,
+    parse_role_mention,
+    parse_user_mention,
+)
+
+ID = 175928847299117063
+
+
+def test_parse_mentions():
+    """Test that each mention type and bare IDs parse to IDs."""
+    assert parse_channel_mention(f"<#{ID}>") == ID
+    assert parse_user_mention(f"<@{ID}>") == ID
+    assert parse_user_mention(f"<@!{ID}>") == ID
+    assert parse_role_mention(f"<@&{ID}>") == ID
+    assert parse_channel_mention(f" {ID} ") == ID
+
+
+def test_parse_mentions_rejects_other_kinds():
+    """Test that a mention of one kind isn't accepted as another."""
+    assert parse_channel_mention(f"<@{ID}>") is None
+    assert parse_user_mention(f"<@&{ID}>") is None
+    assert parse_role_mention(f"<#{ID}>") is None
+    assert parse_user_mention("everyone") is None
+    assert parse_channel_mention("<#0>") is None
+
+
+def test_parse_message_link():
+    """Test links from every client, with or without embed suppression."""
+    expected = MessageLink(guild_id=1, channel_id=ID, message_id=ID + 1)
+
+    assert parse_message_link(f"https://discord.com/channels/1/{ID}/{ID + 1}") == expected
+    assert parse_message_link(f"<https://ptb.discord.com/channels/1/{ID}/{ID + 1}>") == expected
+    assert parse_message_link(f"https://discordapp.com/channels/1/{ID}/{ID + 1}") == expected
+    assert parse_message_link(f"https://discord.com/channels/@me/{ID}/{ID + 1}") == MessageLink(
+        guild_id=None, channel_id=ID, message_id=ID + 1
+    )
+
+
+def test_parse_message_link_rejects_other_urls():
+    """Test that non-message links aren't parsed."""
+    assert parse_message_link(f"https://discord.com/channels/1/{ID}") is None
+    assert parse_message_link(f"https://example.com/channels/1/{ID}/{ID}") is None
+    assert parse_message_link("not a link") is None
@@ -0,0 +1,54 @@
+"""Tests for the recent message cache and ghost ping detection."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.message_cache import MessageCache, MessageSnapshot
+
+SENT = datetime(2025, 3, 3, 19, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def _message(message_id: int, **kwargs) -> MessageSnapshot:
+    return MessageSnapshot(
+        id=message_id,
+        channel_id=10,
+        author_id=1,
+        author="member",
+        content="hello",
+        created_at=SENT,
+        **kwargs,
+    )
+
+
+def test_least_recently_used_messages_are_dropped():
+    """Test that the cache keeps its size, dropping the least recently used message."""
+    cache = MessageCache(2)
+    cache.add(_message(1))
+    cache.add(_message(2))
+    cache.get(1)
+    cache.add(_message(3))
+
+    assert len(cache) == 2
+    assert cache.get(2) is None
+    assert cache.pop(1).id == 1
+    assert cache.pop(1) is None
+
+
+def test_empty_cache_remembers_nothing():
+    """Test that a size of 0 disables the cache."""
+    cache = MessageCache(0)
+    cache.add(_message(1))
+
+    assert cache.get(1) is None
+
+
+def test_ghost_pings_mention_someone_else_and_are_deleted_soon():
+    """Test which deleted messages are ghost pings."""
+    soon = SENT + timedelta(minutes=2)
+
+    assert _message(1, user_mentions=[2]).is_ghost_ping(soon)
+    assert _message(1, role_mentions=[5]).is_ghost_ping(soon)
+    assert _message(1, mentions_everyone=True).is_ghost_ping(soon)
+    assert not _message(1).is_ghost_ping(soon)
+    assert not _message(1, user_mentions=[1]).is_ghost_ping(soon)
+    assert not _message(1, user_mentions=[2]).is_ghost_ping(SENT + timedelta(hours=1))
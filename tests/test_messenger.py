@@ -0,0 +1,27 @@
+"""Tests for the messenger mention guard."""
+
+import discord
+
+from cnayp_bot.services.messenger import is_mass_ping
+
+
+def test_everyone_ping_counts_when_allowed():
+    """Test that @everyone only counts when the mention is allowed."""
+    allowed = discord.AllowedMentions(everyone=True)
+
+    assert is_mass_ping("Starting now @everyone", allowed)
+    assert is_mass_ping("@here reminder", allowed)
+    assert not is_mass_ping("Starting now @everyone", discord.AllowedMentions.none())
+
+
+def test_role_ping_counts_when_roles_allowed():
+    """Test role mention detection."""
+    allowed = discord.AllowedMentions(roles=True)
+
+    assert is_mass_ping("<@&123456789> new questions", allowed)
+    assert not is_mass_ping("<@123456789> your reminder", allowed)
+
+
+def test_no_content_is_not_a_ping():
+    """Test messages without content."""
+    assert not is_mass_ping(None, discord.AllowedMentions(everyone=True))
@@ -92,3 +92,232 @@ def test_load_schedules_json(tmp_path: Path):
     config = ScheduleConfig.model_validate(data)
     assert len(config.schedules) == 1
     assert config.schedules[0].name == "Test Event"
+
+
+def test_schedule_mirrors():
+    """Test parsing mirrors and rejecting unknown placeholders in their templates."""
+    data = {
+        "name": "Sesión de estudio",
+        "description": "Grupo de estudio",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "eventos",
+        "days": ["monday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 120,
+        "mirrors": [
+            {
+                "guild_id": "123456789012345678",
+                "voice_channel": "Study Room",
+                "notify_channel": "events",
+                "name": "Study Session",
+                "announcement_template": "**{name}** starts {relative}!",
+            }
+        ],
+    }
+    schedule = Schedule.model_validate(data)
+
+    assert schedule.mirrors[0].guild_id == 123456789012345678
+    assert schedule.mirrors[0].description == ""
+
+    data["mirrors"][0]["announcement_template"] = "{host} is streaming"
+    with pytest.raises(ValueError, match="Unknown placeholder"):
+        Schedule.model_validate(data)
+
+
+def test_malformed_times_days_and_timezones_are_rejected():
+    """Test that schedules with a bad time, weekday, or timezone don't load."""
+    data = {
+        "name": "Office hours",
+        "description": "",
+        "voice_channel": "general",
+        "notify_channel": "events",
+        "days": ["Monday"],
+        "time": "9:30",
+        "timezone": "Europe/Madrid",
+        "duration_minutes": 60,
+    }
+    assert Schedule.model_validate(data).time == "9:30"
+
+    with pytest.raises(ValueError, match="24-hour time"):
+        Schedule.model_validate(data | {"time": "24:00"})
+    with pytest.raises(ValueError, match="day of the week"):
+        Schedule.model_validate(data | {"days": ["mon"]})
+    with pytest.raises(ValueError, match="IANA"):
+        Schedule.model_validate(data | {"timezone": "CET+1"})
+    with pytest.raises(ValueError, match="24-hour time"):
+        ScheduleConfig.model_validate({"digest_time": "8am"})
+
+
+def test_private_schedules_need_an_audience():
+    """Test that private schedules name their audience role and aren't mirrored."""
+    data = {
+        "name": "Organizer sync",
+        "description": "",
+        "voice_channel": "organizers-voice",
+        "notify_channel": "organizers",
+        "days": ["thursday"],
+        "time": "20:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 30,
+        "visibility": "private",
+    }
+    with pytest.raises(ValueError, match="audience_role"):
+        Schedule.model_validate(data)
+
+    schedule = Schedule.model_validate(data | {"audience_role": "Organizers"})
+    assert schedule.visibility == "private"
+
+    mirror = {"guild_id": "123456789012345678", "voice_channel": "v", "notify_channel": "n"}
+    with pytest.raises(ValueError, match="mirrored"):
+        Schedule.model_validate(data | {"audience_role": "Organizers", "mirrors": [mirror]})
+
+
+def test_schedule_silent_notices():
+    """Test that schedules can silence their own notices, but not the digest."""
+    data = {
+        "name": "Office hours",
+        "description": "",
+        "voice_channel": "general",
+        "notify_channel": "events",
+        "days": ["friday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+    }
+
+    assert Schedule.model_validate(data).silent is None
+    assert Schedule.model_validate(data | {"silent": ["announcement"]}).silent == ["announcement"]
+    with pytest.raises(ValueError):
+        Schedule.model_validate(data | {"silent": ["digest"]})
+
+
+def test_long_templates_are_rejected():
+    """Test that templates too long to announce with are rejected when loaded."""
+    data = {
+        "name": "Talk",
+        "description": "",
+        "voice_channel": "general",
+        "notify_channel": "events",
+        "days": ["friday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+        "announcement_templates": ["{name} " * 300],
+    }
+    with pytest.raises(ValueError, match="at most 1500"):
+        Schedule.model_validate(data)
+
+
+def test_templates_can_include_partials():
+    """Test that {>name} partial references aren't taken for placeholders."""
+    data = {
+        "name": "Talk",
+        "description": "",
+        "voice_channel": "general",
+        "notify_channel": "events",
+        "days": ["friday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+        "announcement_templates": ["**{name}** {when}\n{>how-to-join}\n{> footer}"],
+    }
+    assert Schedule.model_validate(data).announcement_templates
+
+    data["announcement_templates"] = ["{>bad name}"]
+    with pytest.raises(ValueError, match="Unknown placeholder"):
+        Schedule.model_validate(data)
+
+
+def test_followup_templates_are_checked():
+    """Test that follow-ups take the announcement placeholders and a default delay."""
+    data = {
+        "name": "Talk",
+        "description": "",
+        "voice_channel": "general",
+        "notify_channel": "events",
+        "days": ["friday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+        "followup": {"template": "Thanks for joining {name}! Recording: https://r"},
+    }
+    assert Schedule.model_validate(data).followup.delay_minutes == 15
+
+    data["followup"] = {"template": "Recording: {recording}"}
+    with pytest.raises(ValueError, match="Unknown placeholder"):
+        Schedule.model_validate(data)
+
+
+def test_schedule_reminder_overrides():
+    """Test that reminders can ping a role by ID, or no one, and reject other targets."""
+    data = {
+        "name": "Study group",
+        "description": "",
+        "voice_channel": "general",
+        "notify_channel": "events",
+        "days": ["friday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+        "reminder_minutes": [30],
+    }
+
+    assert Schedule.model_validate(data).reminder_ping is None
+    assert Schedule.model_validate(data | {"reminder_ping": "none"}).reminder_ping == "none"
+    role_id = "123456789012345678"
+    assert Schedule.model_validate(data | {"reminder_ping": role_id}).reminder_ping == int(role_id)
+    with pytest.raises(ValueError):
+        Schedule.model_validate(data | {"reminder_ping": "admins"})
+    with pytest.raises(ValueError, match="Unknown placeholder"):
+        Schedule.model_validate(data | {"reminder_template": "{minutes} to go"})
+
+
+def test_partners_announce_known_public_schedules():
+    """Test that partners can only announce schedules that exist and are public."""
+    schedule = {
+        "name": "KCNA Study",
+        "description": "",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "events",
+        "days": ["monday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+    }
+    partner = {"name": "Partner", "guild_id": "123456789012345678", "channel": "events"}
+
+    config = ScheduleConfig.model_validate(
+        {"schedules": [schedule], "partners": [partner | {"schedules": ["kcna study"]}]}
+    )
+    assert config.partners[0].guild_id == 123456789012345678
+
+    with pytest.raises(ValueError, match="unknown schedule"):
+        ScheduleConfig.model_validate(
+            {"schedules": [schedule], "partners": [partner | {"schedules": ["CKA Study"]}]}
+        )
+    private = schedule | {"visibility": "private", "audience_role": "Organizers"}
+    with pytest.raises(ValueError, match="Private schedule"):
+        ScheduleConfig.model_validate(
+            {"schedules": [private], "partners": [partner | {"schedules": ["KCNA Study"]}]}
+        )
+
+
+def test_schedule_cover_images():
+    """Test that cover images are file paths or http(s) URLs."""
+    data = {
+        "name": "KCNA Study",
+        "description": "",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "events",
+        "days": ["monday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+    }
+
+    assert Schedule.model_validate(data | {"image": "covers/kcna.png"}).image == "covers/kcna.png"
+    url = "https://example.com/kcna.png"
+    assert Schedule.model_validate(data | {"image": url}).image == url
+    with pytest.raises(ValueError, match="file path or an http"):
+        Schedule.model_validate(data | {"image": "ftp://example.com/kcna.png"})
@@ -0,0 +1,57 @@
+"""Tests for observer mode recording."""
+
+from pathlib import Path
+from types import SimpleNamespace
+
+import pytest
+
+from cnayp_bot.services import observer as observer_module
+from cnayp_bot.services.observer import Observer, ObserverModeError
+from cnayp_bot.services.store import Store
+
+
+class FakeHTTP:
+    """HTTP client that remembers the requests it actually performed."""
+
+    def __init__(self) -> None:
+        self.performed: list[str] = []
+
+    async def request(self, route: SimpleNamespace, **kwargs) -> dict:
+        self.performed.append(f"{route.method} {route.path}")
+        return {}
+
+
+def test_record_persists(tmp_path: Path):
+    """Test that recorded actions survive a restart, oldest first."""
+    path = tmp_path / "store.json"
+    observer = Observer(Store(path))
+    observer.record("send message", channel="events", content="hello")
+    observer.record("rename channel", channel="voice", name="🎤 voice — 2 in call")
+
+    entries = Observer(Store(path)).entries()
+    assert [entry["action"] for entry in entries] == ["send message", "rename channel"]
+    assert entries[0]["content"] == "hello"
+
+
+def test_record_keeps_most_recent(tmp_path: Path, monkeypatch: pytest.MonkeyPatch):
+    """Test that old entries are dropped past the limit."""
+    monkeypatch.setattr(observer_module, "MAX_OBSERVED", 2)
+    observer = Observer(Store(tmp_path / "store.json"))
+    for i in range(3):
+        observer.record("send message", content=str(i))
+
+    assert [entry["content"] for entry in observer.entries()] == ["1", "2"]
+
+
+async def test_guard_http_blocks_writes(tmp_path: Path):
+    """Test that only GET requests reach Discord once guarded."""
+    observer = Observer(Store(tmp_path / "store.json"))
+    http = FakeHTTP()
+    observer.guard_http(http)
+
+    await http.request(SimpleNamespace(method="GET", path="/guilds/1"))
+    with pytest.raises(ObserverModeError):
+        await http.request(SimpleNamespace(method="POST", path="/channels/1/messages"))
+
+    assert http.performed == ["GET /guilds/1"]
+    assert observer.entries()[0]["path"] == "/channels/1/messages"
@@ -0,0 +1,47 @@
+"""Tests for template partials shared between announcement templates."""
+
+import os
+from pathlib import Path
+
+from cnayp_bot.services.partials import TemplatePartials
+
+
+def test_partials_are_filled_in(tmp_path: Path):
+    """Test that {>name} is replaced with name.md, and unknown partials are dropped."""
+    (tmp_path / "footer.md").write_text("Read our code of conduct: {link}\n", encoding="utf-8")
+    partials = TemplatePartials(tmp_path)
+
+    assert partials.expand("**{name}**\n{>footer}{>missing}") == (
+        "**{name}**\nRead our code of conduct: {link}"
+    )
+
+
+def test_edited_partials_are_reloaded(tmp_path: Path):
+    """Test that a partial edited on disk is used in the next template."""
+    path = tmp_path / "footer.md"
+    path.write_text("Old footer", encoding="utf-8")
+    partials = TemplatePartials(tmp_path)
+    assert partials.get("footer") == "Old footer"
+
+    path.write_text("New footer", encoding="utf-8")
+    os.utime(path, (path.stat().st_atime, path.stat().st_mtime + 1))
+
+    assert partials.get("footer") == "New footer"
+
+
+def test_invalid_partials_are_ignored(tmp_path: Path):
+    """Test that partials with unknown placeholders or nested partials aren't used."""
+    (tmp_path / "host.md").write_text("Hosted by {host}", encoding="utf-8")
+    (tmp_path / "nested.md").write_text("{>host}", encoding="utf-8")
+    partials = TemplatePartials(tmp_path)
+
+    assert partials.get("host") is None
+    assert partials.expand("{name} {>nested}") == "{name} "
+
+
+def test_partials_making_templates_too_long_are_left_out(tmp_path: Path):
+    """Test that a template stays within the limit with its partials."""
+    (tmp_path / "rules.md").write_text("x" * 1600, encoding="utf-8")
+    partials = TemplatePartials(tmp_path)
+
+    assert partials.expand("{name}\n{>rules}") == "{name}\n"
@@ -0,0 +1,85 @@
+"""Tests for signed tasks between peer bots."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.config import PeerBot
+from cnayp_bot.services.peers import PeerAuthError, PeerError, PeerNetwork, PeerTask, sign
+
+NOW = datetime(2025, 3, 3, 20, 0, tzinfo=ZoneInfo("UTC"))
+SECRET = "test-secret"
+
+
+def _request(task: PeerTask, signed_at: datetime = NOW, secret: str = SECRET) -> tuple:
+    """Build the headers and body the moderation bot would send."""
+    body = task.model_dump_json().encode()
+    timestamp = str(int(signed_at.timestamp()))
+    headers = {
+        "X-Peer-Name": "moderation",
+        "X-Peer-Timestamp": timestamp,
+        "X-Peer-Signature": sign(secret, timestamp, body),
+    }
+    return headers, body
+
+
+def _network() -> PeerNetwork:
+    network = PeerNetwork("events", {"moderation": PeerBot(url="http://mod:8080", secret=SECRET)})
+
+    async def pause(sender: str, params: dict) -> dict:
+        return {"sender": sender, "minutes": params["minutes"]}
+
+    network.register("pause_pings", pause)
+    return network
+
+
+async def test_signed_task_runs_its_handler():
+    """Test that a correctly signed task reaches the handler for its action."""
+    network = _network()
+    headers, body = _request(PeerTask(action="pause_pings", params={"minutes": 30}))
+
+    assert await network.receive(headers, body, NOW) == {"sender": "moderation", "minutes": 30}
+    assert network.actions == ["pause_pings"]
+
+
+@pytest.mark.parametrize(
+    ("secret", "signed_at"),
+    [
+        ("wrong-secret", NOW),
+        (SECRET, NOW - timedelta(minutes=10)),
+    ],
+)
+async def test_bad_or_stale_signatures_are_rejected(secret: str, signed_at: datetime):
+    """Test that tasks signed with the wrong secret or too long ago are rejected."""
+    headers, body = _request(PeerTask(action="pause_pings"), signed_at, secret)
+
+    with pytest.raises(PeerAuthError):
+        await _network().receive(headers, body, NOW)
+
+
+async def test_unknown_peers_are_rejected():
+    """Test that tasks from bots missing from the registry are rejected."""
+    headers, body = _request(PeerTask(action="pause_pings"))
+    headers["X-Peer-Name"] = "stranger"
+
+    with pytest.raises(PeerAuthError):
+        await _network().receive(headers, body, NOW)
+
+
+async def test_replayed_tasks_are_rejected():
+    """Test that the same signed task can't be run twice."""
+    network = _network()
+    headers, body = _request(PeerTask(action="pause_pings", params={"minutes": 30}))
+    await network.receive(headers, body, NOW)
+
+    with pytest.raises(PeerAuthError):
+        await network.receive(headers, body, NOW + timedelta(seconds=1))
+
+
+async def test_unknown_actions_are_refused():
+    """Test that authentic tasks for actions without a handler are refused."""
+    headers, body = _request(PeerTask(action="launch_rockets"))
+
+    with pytest.raises(PeerError, match="Unknown action"):
+        await _network().receive(headers, body, NOW)
@@ -0,0 +1,95 @@
+"""Tests for bot permission preflight checks."""
+
+from dataclasses import dataclass, field
+from types import SimpleNamespace
+
+import discord
+import pytest
+
+from cnayp_bot.helpers.permissions import (
+    MissingPermissionsError,
+    PublicChannelError,
+    check_can_manage_roles,
+    check_can_send,
+    check_channel_permissions,
+    check_private_channel,
+    missing_permissions,
+    permission_name,
+)
+
+
+@dataclass(order=True)
+class Role:
+    position: int
+    name: str = field(compare=False)
+
+
+def make_channel(permissions: discord.Permissions) -> SimpleNamespace:
+    return SimpleNamespace(
+        name="events",
+        guild=SimpleNamespace(me=object()),
+        permissions_for=lambda member: permissions,
+    )
+
+
+def test_permission_name_matches_discord_ui():
+    """Test that flags are shown the way Discord's settings name them."""
+    assert permission_name("manage_events") == "Manage Events"
+    assert permission_name("send_messages") == "Send Messages"
+
+
+def test_missing_permissions_in_order():
+    """Test that only denied flags are reported, in the order asked."""
+    permissions = discord.Permissions(view_channel=True)
+
+    assert missing_permissions(permissions, "embed_links", "view_channel", "send_messages") == [
+        "Embed Links",
+        "Send Messages",
+    ]
+
+
+def test_check_channel_permissions_names_the_channel():
+    """Test that the error says what's missing and where."""
+    channel = make_channel(discord.Permissions(view_channel=True))
+
+    with pytest.raises(MissingPermissionsError, match="permission Send Messages in #events"):
+        check_can_send(channel)
+
+    check_channel_permissions(channel, "view_channel")
+
+
+def test_check_can_send_with_embeds():
+    """Test that embeds also need Embed Links."""
+    channel = make_channel(discord.Permissions(view_channel=True, send_messages=True))
+
+    check_can_send(channel)
+    with pytest.raises(MissingPermissionsError, match="Embed Links"):
+        check_can_send(channel, embeds=True)
+
+
+def test_check_can_manage_roles_respects_hierarchy():
+    """Test that the bot needs Manage Roles, and can't manage roles ranking above it."""
+    bot_role = Role(5, "CNAYP Bot")
+    guild = SimpleNamespace(
+        name="CNAYP",
+        me=SimpleNamespace(guild_permissions=discord.Permissions(), top_role=bot_role),
+    )
+    with pytest.raises(MissingPermissionsError, match="Manage Roles in CNAYP"):
+        check_can_manage_roles(guild)
+
+    guild.me.guild_permissions = discord.Permissions(manage_roles=True)
+    check_can_manage_roles(guild, Role(1, "Unverified"))
+    with pytest.raises(MissingPermissionsError, match="@Admins, which ranks above the bot"):
+        check_can_manage_roles(guild, Role(1, "Unverified"), Role(9, "Admins"))
+
+
+def test_private_events_need_hidden_channels():
+    """Test that a channel @everyone can see is refused for private events."""
+    hidden = make_channel(discord.Permissions(view_channel=False))
+    hidden.guild.default_role = object()
+    check_private_channel(hidden)
+
+    visible = make_channel(discord.Permissions(view_channel=True))
+    visible.guild.default_role = object()
+    with pytest.raises(PublicChannelError, match="#events is visible to @everyone"):
+        check_private_channel(visible)
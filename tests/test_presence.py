@@ -0,0 +1,58 @@
+"""Tests for presence messages."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.helpers.presence import format_countdown, render_presence
+from cnayp_bot.services.calendar import CalendarEvent
+
+UTC = ZoneInfo("UTC")
+NOW = datetime(2025, 3, 12, 15, 0, tzinfo=UTC)  # Wednesday
+WEEK_END = datetime(2025, 3, 17, 0, 0, tzinfo=UTC)
+
+
+def make_event(name: str, start: datetime) -> CalendarEvent:
+    return CalendarEvent(
+        id=name,
+        name=name,
+        description="",
+        start_time=start,
+        end_time=start + timedelta(hours=1),
+        timezone="UTC",
+    )
+
+
+def test_format_countdown_uses_largest_unit():
+    """Test that countdowns are shown in days, hours, or minutes."""
+    assert format_countdown(timedelta(days=2, hours=5)) == "2d"
+    assert format_countdown(timedelta(hours=3, minutes=40)) == "3h"
+    assert format_countdown(timedelta(minutes=45)) == "45m"
+    assert format_countdown(timedelta(seconds=10)) == "1m"
+
+
+def test_render_presence_counts_and_next_event():
+    """Test that past and next-week events are left out and the prefix sets the type."""
+    events = [
+        make_event("Earlier", NOW - timedelta(hours=1)),
+        make_event("K8s Study", NOW + timedelta(days=1)),
+        make_event("Go Study", NOW + timedelta(hours=3)),
+        make_event("Next Week", WEEK_END + timedelta(days=1)),
+    ]
+
+    assert render_presence("Watching {week_count} events this week", events, NOW, WEEK_END) == (
+        "watching",
+        "2 events this week",
+    )
+    assert render_presence("Next: {next_event} in {next_in}", events, NOW, WEEK_END) == (
+        "custom",
+        "Next: Go Study in 3h",
+    )
+
+
+def test_render_presence_skips_next_event_without_events():
+    """Test that messages about the next event don't apply when nothing is coming up."""
+    assert render_presence("Next: {next_event}", [], NOW, WEEK_END) is None
+    assert render_presence("Playing {week_count} events", [], NOW, WEEK_END) == (
+        "playing",
+        "0 events",
+    )
@@ -0,0 +1,46 @@
+"""Tests for the sliding window limiter."""
+
+from datetime import datetime, timedelta
+
+from cnayp_bot.helpers.ratelimit import SlidingWindowLimiter
+
+START = datetime(2025, 3, 1, 12, 0)
+
+
+def test_allows_actions_under_limit():
+    """Test that actions under the limit need no delay."""
+    limiter = SlidingWindowLimiter(max_actions=2, window=timedelta(minutes=10))
+
+    assert limiter.delay("channel", START) == timedelta(0)
+    limiter.record("channel", START)
+    assert limiter.delay("channel", START) == timedelta(0)
+
+
+def test_delays_until_oldest_action_expires():
+    """Test that the delay lasts until the oldest action leaves the window."""
+    limiter = SlidingWindowLimiter(max_actions=2, window=timedelta(minutes=10))
+    limiter.record("channel", START)
+    limiter.record("channel", START + timedelta(minutes=3))
+
+    assert limiter.delay("channel", START + timedelta(minutes=4)) == timedelta(minutes=6)
+    assert limiter.delay("channel", START + timedelta(minutes=10)) == timedelta(0)
+
+
+def test_keys_are_independent():
+    """Test that each key has its own window."""
+    limiter = SlidingWindowLimiter(max_actions=1, window=timedelta(minutes=10))
+    limiter.record("a", START)
+
+    assert limiter.delay("a", START) == timedelta(minutes=10)
+    assert limiter.delay("b", START) == timedelta(0)
+
+
+def test_actions_recorded_ahead_queue_up():
+    """Test that callers waiting for a slot each get the next one, not the same one."""
+    limiter = SlidingWindowLimiter(max_actions=1, window=timedelta(minutes=1))
+    limiter.record("channel", START)
+
+    delay = limiter.delay("channel", START)
+    limiter.record("channel", START + delay)
+
+    assert limiter.delay("channel", START) == timedelta(minutes=2)
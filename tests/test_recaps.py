@@ -0,0 +1,59 @@
+"""Tests for event recap collection."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.recaps import RecapCollection, extract_links
+from cnayp_bot.services.store import Store
+
+ENDED = datetime(2025, 3, 10, 20, 0, tzinfo=ZoneInfo("UTC"))
+HOUR = timedelta(hours=1)
+
+
+def _recaps(tmp_path: Path) -> RecapCollection:
+    recaps = RecapCollection(Store(tmp_path / "store.json"))
+    recaps.open("kcna-study-2025-03-10", "KCNA Study", 10, 20, ENDED)
+    return recaps
+
+
+def test_shared_messages_are_collected_for_48_hours(tmp_path: Path):
+    """Test that messages count towards the recap until it's due, then not."""
+    recaps = _recaps(tmp_path)
+
+    assert recaps.collecting(10, ENDED + HOUR) == "kcna-study-2025-03-10"
+    assert recaps.collecting(11, ENDED + HOUR) is None
+    assert recaps.add("kcna-study-2025-03-10", 100, 30, 2, 0, []) is True
+    assert recaps.add("kcna-study-2025-03-10", 200, 31, 0, 0, ["https://example.com"]) is True
+    assert recaps.due(ENDED + 47 * HOUR) == []
+
+    assert recaps.collecting(10, ENDED + 48 * HOUR) is None
+    assert recaps.due(ENDED + 48 * HOUR) == ["kcna-study-2025-03-10"]
+    recaps.close("kcna-study-2025-03-10")
+    assert recaps.add("kcna-study-2025-03-10", 100, 32, 1, 0, []) is False
+
+
+def test_deleted_messages_are_left_out(tmp_path: Path):
+    """Test that removing a message drops only that message."""
+    recaps = _recaps(tmp_path)
+    recaps.add("kcna-study-2025-03-10", 100, 30, 2, 0, [])
+    recaps.add("kcna-study-2025-03-10", 200, 31, 1, 1, [])
+
+    recaps.remove("kcna-study-2025-03-10", 30)
+
+    items = recaps.get("kcna-study-2025-03-10")["items"]
+    assert [item["message_id"] for item in items] == [31]
+
+
+def test_the_latest_occurrence_collects_in_a_shared_thread(tmp_path: Path):
+    """Test that a daily schedule's messages go to the newest recap."""
+    recaps = _recaps(tmp_path)
+    recaps.open("kcna-study-2025-03-11", "KCNA Study", 10, 21, ENDED + 24 * HOUR)
+
+    assert recaps.collecting(10, ENDED + 25 * HOUR) == "kcna-study-2025-03-11"
+
+
+def test_links_are_extracted_once_in_order():
+    """Test that links are found in text without duplicates or trailing brackets."""
+    text = "Slides: https://example.com/a and <https://example.com/b>, again https://example.com/a"
+    assert extract_links(text) == ["https://example.com/a", "https://example.com/b"]
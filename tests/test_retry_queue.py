@@ -0,0 +1,55 @@
+"""Tests for the queue of notifications retried after transient failures."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import aiohttp
+
+from cnayp_bot.services.retry_queue import RetryQueue, is_transient
+
+NOW = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("UTC"))
+
+
+async def send() -> None:
+    pass
+
+
+def test_failed_notifications_back_off_until_the_window_ends():
+    """Test that retries double their delay and are given up once past the window."""
+    queue = RetryQueue(10, timedelta(minutes=2), jitter=lambda: 0)
+
+    assert queue.add("the reminder", send, NOW)
+    assert queue.due(NOW + timedelta(seconds=9)) == []
+    [pending] = queue.due(NOW + timedelta(seconds=10))
+
+    assert queue.retry_later(pending, NOW + timedelta(seconds=10))
+    assert pending.retry_at == NOW + timedelta(seconds=30)
+    [pending] = queue.due(NOW + timedelta(seconds=30))
+    assert queue.retry_later(pending, NOW + timedelta(seconds=30))
+    [pending] = queue.due(NOW + timedelta(seconds=70))
+
+    # The next attempt would be 150 seconds after the first failure
+    assert not queue.retry_later(pending, NOW + timedelta(seconds=70))
+    assert len(queue) == 0
+
+
+def test_jitter_takes_up_to_half_the_delay_off():
+    """Test that jitter spreads retries out without ever delaying them longer."""
+    assert RetryQueue(10, timedelta(hours=1), jitter=lambda: 1).delay(1) == timedelta(seconds=5)
+    assert RetryQueue(10, timedelta(hours=1), jitter=lambda: 0).delay(10) == timedelta(minutes=5)
+
+
+def test_queue_is_bounded():
+    """Test that failures beyond the queue size, or with no window, are given up right away."""
+    queue = RetryQueue(1, timedelta(minutes=10))
+
+    assert queue.add("the reminder", send, NOW)
+    assert not queue.add("the start notification", send, NOW)
+    assert not RetryQueue(10, timedelta(0)).add("the reminder", send, NOW)
+
+
+def test_only_server_and_network_errors_are_retried():
+    """Test that errors that fail the same way every time aren't retried."""
+    assert is_transient(aiohttp.ClientConnectionError())
+    assert is_transient(TimeoutError())
+    assert not is_transient(ValueError("invalid message"))
@@ -0,0 +1,54 @@
+"""Tests for temporary role grants."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.role_grants import ROLE_GRANTS, RoleGrants
+from cnayp_bot.services.store import Store
+
+NOW = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def test_grants_expire_and_survive_restart(tmp_path: Path):
+    """Test that grants are due once they expire, across restarts."""
+    path = tmp_path / "store.json"
+    grants = RoleGrants(Store(path))
+    grants.grant(1, 10, NOW + timedelta(days=7), granted_by=99, guild_id=1)
+    grants.grant(2, 10, NOW + timedelta(days=1), granted_by=99, guild_id=2)
+
+    grants = RoleGrants(Store(path))
+    assert [grant.member_id for grant in grants.all()] == [2, 1]
+    assert grants.expired(NOW) == []
+    assert [grant.member_id for grant in grants.expired(NOW + timedelta(days=1))] == [2]
+    assert grants.expired(NOW + timedelta(days=7))[1].granted_by == 99
+    assert [grant.guild_id for grant in grants.all()] == [2, 1]
+
+
+def test_regranting_replaces_expiry(tmp_path: Path):
+    """Test that granting the same role again moves its expiry."""
+    grants = RoleGrants(Store(tmp_path / "store.json"))
+    grants.grant(1, 10, NOW + timedelta(days=7), granted_by=99, guild_id=1)
+    grants.grant(1, 10, NOW + timedelta(days=1), granted_by=99, guild_id=1)
+
+    assert [grant.expires for grant in grants.all()] == [NOW + timedelta(days=1)]
+
+
+def test_removed_grants_are_not_revoked(tmp_path: Path):
+    """Test that removing a grant drops it, and only that member's role."""
+    grants = RoleGrants(Store(tmp_path / "store.json"))
+    grants.grant(1, 10, NOW, granted_by=99, guild_id=1)
+    grants.grant(1, 11, NOW, granted_by=99, guild_id=1)
+
+    assert grants.remove(1, 10)
+    assert not grants.remove(1, 10)
+    assert [grant.role_id for grant in grants.expired(NOW)] == [11]
+
+
+def test_grants_recorded_without_a_guild_have_none(tmp_path: Path):
+    """Test that grants stored before guilds were recorded still load, with no guild."""
+    store = Store(tmp_path / "store.json")
+    store.set(ROLE_GRANTS, "1:10", {"expires": NOW.isoformat(), "granted_by": 99})
+
+    [grant] = RoleGrants(store).all()
+    assert grant.guild_id is None
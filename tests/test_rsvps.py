@@ -0,0 +1,87 @@
+"""Tests for RSVPs with a capacity and waitlist."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.rsvps import RsvpList
+from cnayp_bot.services.store import Store
+
+END = datetime(2025, 3, 3, 20, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def make_rsvps(tmp_path: Path, capacity: int = 2) -> RsvpList:
+    rsvps = RsvpList(Store(tmp_path / "store.json"))
+    rsvps.open("event-1", 100, "Study group", capacity, END)
+    return rsvps
+
+
+def test_members_past_capacity_are_waitlisted(tmp_path: Path):
+    """Test that seats fill up first, then members queue in order."""
+    rsvps = make_rsvps(tmp_path)
+
+    assert [rsvps.join("event-1", member) for member in (1, 2, 3, 4)] == [0, 0, 1, 2]
+    assert rsvps.join("event-1", 2) == 0
+    assert rsvps.join("event-1", 4) == 2
+    assert rsvps.get("event-1")["going"] == [1, 2]
+
+
+def test_leaving_promotes_the_next_waitlisted_member(tmp_path: Path):
+    """Test that a freed seat goes to the first on the waitlist, and persists."""
+    rsvps = make_rsvps(tmp_path)
+    for member in (1, 2, 3, 4):
+        rsvps.join("event-1", member)
+
+    assert rsvps.leave("event-1", 1) == 3
+    reloaded = RsvpList(Store(tmp_path / "store.json"))
+    assert reloaded.get("event-1")["going"] == [2, 3]
+    assert reloaded.get("event-1")["waitlist"] == [4]
+
+
+def test_leaving_the_waitlist_promotes_nobody(tmp_path: Path):
+    """Test that waitlisted or unknown members leaving don't change the seats."""
+    rsvps = make_rsvps(tmp_path, capacity=1)
+    for member in (1, 2, 3):
+        rsvps.join("event-1", member)
+
+    assert rsvps.leave("event-1", 2) is None
+    assert rsvps.leave("event-1", 9) is None
+    assert rsvps.get("event-1")["waitlist"] == [3]
+
+
+def test_lists_are_found_by_message_and_forgotten_after_the_event(tmp_path: Path):
+    """Test that buttons find their event, and finished events are dropped."""
+    rsvps = make_rsvps(tmp_path)
+
+    assert rsvps.find(100) == "event-1"
+    assert rsvps.find(101) is None
+    rsvps.forget_finished(END - timedelta(minutes=1))
+    assert rsvps.get("event-1") is not None
+    rsvps.forget_finished(END)
+    assert rsvps.get("event-1") is None
+
+
+def test_events_without_a_capacity_have_unlimited_seats(tmp_path: Path):
+    """Test that nobody is waitlisted when an event has no capacity."""
+    rsvps = RsvpList(Store(tmp_path / "store.json"))
+    rsvps.open("event-1", 100, "Study group", None, END)
+
+    assert [rsvps.join("event-1", member) for member in range(1, 6)] == [0] * 5
+    assert rsvps.get("event-1")["waitlist"] == []
+
+
+def test_answering_maybe_or_no_gives_up_the_seat(tmp_path: Path):
+    """Test that maybe and can't-go answers free a seat and replace each other."""
+    rsvps = make_rsvps(tmp_path, capacity=1)
+    rsvps.join("event-1", 1)
+    rsvps.join("event-1", 2)
+
+    assert rsvps.answer("event-1", 1, "maybe") == 2
+    assert rsvps.answer("event-1", 1, "declined") is None
+    entry = rsvps.get("event-1")
+    assert entry["going"] == [2]
+    assert entry["maybe"] == []
+    assert entry["declined"] == [1]
+
+    assert rsvps.join("event-1", 1) == 1
+    assert rsvps.get("event-1")["declined"] == []
@@ -0,0 +1,36 @@
+"""Tests for sanitizing text before it's posted."""
+
+from cnayp_bot.helpers.sanitize import (
+    ZERO_WIDTH_SPACE,
+    neutralize_mentions,
+    sanitize_template,
+    sanitize_text,
+)
+
+
+def test_mentions_never_ping():
+    """Test that mass, user, and role mentions are broken up but stay readable."""
+    text = neutralize_mentions("@everyone @here <@123> <@!456> <@&789> <#42>")
+
+    assert "@everyone" not in text
+    assert "@here" not in text
+    assert "<@123>" not in text and "<@&789>" not in text
+    assert "<#42>" in text
+    assert text.replace(ZERO_WIDTH_SPACE, "") == "@everyone @here <@123> <@!456> <@&789> <#42>"
+
+
+def test_descriptions_are_cleaned_and_cut():
+    """Test that masked links, headings, control characters, and length are handled."""
+    description = "# Big news\n[docs](https://evil.example)\n\n\n\nBye\x07 @everyone"
+
+    assert sanitize_text(description, 1000) == (
+        f"Big news\ndocs (https://evil.example)\n\nBye @{ZERO_WIDTH_SPACE}everyone"
+    )
+    assert sanitize_text("a" * 50, 10) == "a" * 9 + "…"
+
+
+def test_templates_keep_their_layout():
+    """Test that templates keep headings and placeholders but lose their pings."""
+    template = "# {name}\n\n\n@here {link}"
+
+    assert sanitize_template(template) == f"# {{name}}\n\n\n@{ZERO_WIDTH_SPACE}here {{link}}"
@@ -0,0 +1,173 @@
+"""Tests for importing schedules from a sheet."""
+
+from datetime import date
+from pathlib import Path
+
+import pytest
+
+from cnayp_bot.models import Schedule, ScheduleConfig
+from cnayp_bot.services.schedule_sheet import (
+    ImportedSchedules,
+    csv_url,
+    describe_changes,
+    merge_schedules,
+    parse_row,
+    parse_schedule_sheet,
+    schedule_cells,
+)
+from cnayp_bot.services.store import Store
+
+SHEET = """Name,Days,Time,Duration,Voice channel,Notify channel,Enabled,Notes
+KCNA Study,"Monday, Wednesday",7:00 PM,90,study-voice,events,yes,ask Ana
+Office Hours,friday,18:30,60,,,no,
+
+"""
+
+
+def _schedule(name: str, **fields) -> Schedule:
+    data = {
+        "name": name,
+        "description": "",
+        "voice_channel": "voice",
+        "notify_channel": "events",
+        "days": ["monday"],
+        "time": "19:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+    }
+    return Schedule.model_validate(data | fields)
+
+
+def test_rows_are_mapped_to_schedules():
+    """Test that headers map to fields, values are normalized, and notes are ignored."""
+    sheet = parse_schedule_sheet(SHEET)
+
+    assert sheet.errors == []
+    study, office_hours = sheet.schedules
+    assert study.days == ["monday", "wednesday"]
+    assert study.time == "19:00"
+    assert study.duration_minutes == 90
+    assert study.voice_channel == "study-voice"
+    assert not office_hours.enabled
+    assert office_hours.timezone == "America/Lima"
+    assert "description" not in sheet.columns
+
+
+def test_invalid_rows_are_reported():
+    """Test that each bad row is reported with its row number."""
+    sheet = parse_schedule_sheet(
+        "Name,Days,Time,Duration,Timezone\n"
+        "A,funday,19:00,60,\n"
+        "B,monday,25:00,60,\n"
+        "C,monday,19:00,60,Mars/Olympus\n"
+        "D,monday,19:00,long,\n"
+        "D,monday,19:00,60,\n"
+        "D,tuesday,19:00,60,\n"
+    )
+
+    assert [error.split(":")[0] for error in sheet.errors] == [
+        "Row 2",
+        "Row 3",
+        "Row 4",
+        "Row 5",
+        "Row 7",
+    ]
+    assert [schedule.name for schedule in sheet.schedules] == ["D"]
+
+
+def test_missing_columns_are_reported():
+    """Test that a sheet without the required columns is rejected."""
+    assert parse_schedule_sheet("Name,Time\nA,19:00\n").errors == [
+        "Missing columns: days, duration_minutes"
+    ]
+
+
+def test_merge_keeps_unmapped_fields_and_hand_written_schedules():
+    """Test that the sheet updates its columns only, and only removes imported schedules."""
+    config = ScheduleConfig(
+        schedules=[
+            _schedule("KCNA Study", announcement_templates=["{name} starts {relative}"]),
+            _schedule("Retired Sheet Event"),
+            _schedule("Hand Written"),
+        ]
+    )
+
+    merged = merge_schedules(
+        config, parse_schedule_sheet(SHEET), imported={"kcna study", "retired sheet event"}
+    )
+
+    study, hand_written, office_hours = merged.schedules
+    assert study.time == "19:00"
+    assert study.days == ["monday", "wednesday"]
+    assert study.announcement_templates == ["{name} starts {relative}"]
+    assert hand_written.name == "Hand Written"
+    assert office_hours.name == "Office Hours"
+
+
+def test_changes_are_summarized():
+    """Test the change summary posted for organizers."""
+    old = [_schedule("A"), _schedule("B")]
+    new = [_schedule("A", time="20:00"), _schedule("C")]
+
+    assert describe_changes(old, new) == [
+        "✏️ **A**: time `19:00` → `20:00`",
+        "➕ **C** added (Monday at 19:00)",
+        "➖ **B** removed",
+    ]
+    assert describe_changes(old, old) == []
+
+
+def test_schedules_round_trip_through_cells():
+    """Test that a schedule written as cells reads back unchanged, as edits rely on."""
+    schedule = _schedule(
+        "A",
+        days=["monday", "friday"],
+        capacity=20,
+        owners=["123", "456"],
+        guild_id="789",
+        every_weeks=2,
+        starting="2025-03-07",
+    )
+
+    assert parse_row(schedule_cells(schedule)) == schedule
+    assert parse_row(schedule_cells(schedule) | {"capacity": ""}).capacity is None
+
+
+def test_dates_make_one_off_schedules():
+    """Test that a date in the days cell or a date column makes a one-off schedule."""
+    row = {"name": "AMA", "days": "2025-03-14", "time": "18:00", "duration_minutes": "60"}
+
+    assert parse_row(row).date == date(2025, 3, 14)
+    assert parse_row(row | {"days": "", "date": "2025-03-14"}).days == []
+    with pytest.raises(ValueError, match="only one"):
+        parse_row(row | {"days": "friday", "date": "2025-03-14"})
+
+
+def test_cron_schedules_leave_time_empty():
+    """Test that a cron row with a time is rejected rather than losing the time."""
+    row = {"name": "Monthly", "days": "", "cron": "0 19 * * TUE#1", "duration_minutes": "60"}
+
+    assert parse_row(row | {"time": ""}).cron == "0 19 * * TUE#1"
+    with pytest.raises(ValueError, match="leave time empty"):
+        parse_row(row | {"time": "19:00"})
+
+
+def test_sheet_links_are_exported_as_csv():
+    """Test that Google Sheet links become CSV export URLs."""
+    url = "https://docs.google.com/spreadsheets/d/abc_123/edit#gid=42"
+    published = "https://docs.google.com/spreadsheets/d/e/abc/pub?output=csv"
+
+    assert csv_url(url) == (
+        "https://docs.google.com/spreadsheets/d/abc_123/export?format=csv&gid=42"
+    )
+    assert csv_url(published) == published
+    assert csv_url("https://example.com/schedules.csv") == "https://example.com/schedules.csv"
+
+
+def test_errors_are_reported_once(tmp_path: Path):
+    """Test that the same errors aren't posted every sync."""
+    imported = ImportedSchedules(Store(tmp_path / "store.json"))
+
+    assert imported.errors_changed(["Row 2: bad"])
+    assert not imported.errors_changed(["Row 2: bad"])
+    assert imported.errors_changed([])
@@ -0,0 +1,512 @@
+"""Tests for the scheduler cog's notifications, against a fake Discord client."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.cogs.reminders import REMINDERS
+from cnayp_bot.cogs.scheduler import (
+    DISCORD_EVENTS,
+    FOLLOWUPS,
+    HELD_REMINDERS,
+    HOST_CHECKS,
+    REMINDER_MESSAGES,
+    SENT_REMINDERS,
+    SNOOZE,
+    START_NOTIFICATIONS,
+    SchedulerCog,
+    _ping,
+)
+from cnayp_bot.config import settings
+from cnayp_bot.models import Schedule, ScheduleConfig
+from cnayp_bot.services.calendar import CalendarEvent
+
+from .fakes import (
+    FakeBot,
+    FakeGuild,
+    FakeInteraction,
+    FakeMember,
+    FakeRole,
+    FakeScheduledEvent,
+)
+
+OWNER = 42
+
+
+def make_event(**overrides) -> CalendarEvent:
+    """Create an occurrence of a schedule hosted by `OWNER` that started 10 minutes ago."""
+    data = {
+        "name": "KCNA Session",
+        "description": "Study session",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "events",
+        "days": ["monday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 60,
+        "owners": [OWNER],
+    }
+    start = datetime.now(ZoneInfo("UTC")) - timedelta(minutes=10)
+    return CalendarEvent(
+        id="evt1",
+        name="KCNA Session",
+        description="Study session",
+        start_time=start,
+        end_time=start + timedelta(hours=1),
+        timezone="America/Lima",
+        schedule=Schedule.model_validate(data | overrides),
+    )
+
+
+def make_cog(tmp_path: Path, *, public: bool = True) -> tuple[SchedulerCog, FakeGuild]:
+    guild = FakeGuild(1, roles=[FakeRole(7, "Study Group")])
+    guild.add_channel(10, "events", public=public)
+    guild.add_channel(20, "K8s | KCNA")
+    return SchedulerCog(FakeBot(tmp_path, guild)), guild
+
+
+async def test_start_notification_pings_everyone(tmp_path: Path):
+    """Test that the start of a public event is announced with @everyone."""
+    cog, guild = make_cog(tmp_path)
+
+    await cog.send_start_notification(make_event())
+
+    [message] = cog.bot.messenger.sent_to(guild.channels[0])
+    assert message.content == "@everyone"
+    assert "KCNA Session is starting now" in message.embed.title
+    assert message.allowed_mentions.everyone
+
+
+async def test_start_notification_links_the_discord_event(tmp_path: Path):
+    """Test that the start notification links the event's Discord scheduled event."""
+    cog, guild = make_cog(tmp_path)
+    cog.bot.store.set(DISCORD_EVENTS, "evt1", {"id": 99, "status": "active", "end": ""})
+
+    await cog.send_start_notification(make_event())
+
+    [message] = cog.bot.messenger.sent_to(guild.channels[0])
+    assert message.embed.title == "🔴 KCNA Session is starting now!"
+    assert [(field.name, field.value) for field in message.embed.fields] == [
+        ("Duration", "60 minutes"),
+        ("Timezone", "America/Lima"),
+        ("Where", "<#20>"),
+        ("Discord event", "https://discord.com/events/1/99"),
+    ]
+
+
+async def test_private_start_notification_needs_a_private_channel(tmp_path: Path):
+    """Test that private events aren't announced where members can see them."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event(visibility="private", audience_role="Study Group")
+
+    await cog.send_start_notification(event)
+
+    assert cog.bot.messenger.sent == []
+
+
+async def test_private_start_notification_pings_the_audience(tmp_path: Path):
+    """Test that private events ping their audience role instead of @everyone."""
+    cog, guild = make_cog(tmp_path, public=False)
+    event = make_event(visibility="private", audience_role="Study Group")
+
+    await cog.send_start_notification(event)
+
+    [message] = cog.bot.messenger.sent_to(guild.channels[0])
+    assert message.content == "<@&7>"
+
+
+async def test_owners_told_when_no_host_joined(tmp_path: Path):
+    """Test that owners get a DM when none of them is in the call, once."""
+    cog, guild = make_cog(tmp_path)
+    guild.channels[1].members = [FakeMember(5)]
+    event = make_event()
+
+    await cog.check_host_joined(event)
+    await cog.check_host_joined(event)
+
+    [message] = cog.bot.messenger.sent_to(cog.bot.get_user(OWNER))
+    assert "no host is in <#20>" in message.content
+
+
+async def test_sent_notices_are_remembered_across_restarts(tmp_path: Path):
+    """Test that a restarted scheduler doesn't announce the start or check for hosts again."""
+    cog, guild = make_cog(tmp_path)
+    guild.channels[1].members = [FakeMember(5)]
+    event = make_event()
+
+    await cog.check_and_send_start_notification(event)
+    await cog.check_host_joined(event)
+    assert len(cog.bot.messenger.sent) == 2
+
+    restarted, guild = make_cog(tmp_path)
+    guild.channels[1].members = [FakeMember(5)]
+    await restarted.check_and_send_start_notification(event)
+    await restarted.check_host_joined(event)
+
+    assert restarted.bot.messenger.sent == []
+    assert list(restarted.bot.store.items(START_NOTIFICATIONS)) == [event.id]
+    assert list(restarted.bot.store.items(HOST_CHECKS)) == [event.id]
+
+
+async def test_sent_notices_are_forgotten_after_the_event(tmp_path: Path):
+    """Test that what was sent about an event is dropped a day after it ends."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event()
+    event.end_time -= timedelta(days=2)
+    cog.bot.store.set(SENT_REMINDERS, f"{event.id}:15", {"end": event.end_time.isoformat()})
+    cog.bot.store.set(START_NOTIFICATIONS, event.id, {"end": event.end_time.isoformat()})
+
+    cog._forget_finished_discord_events()
+
+    assert cog.bot.store.items(SENT_REMINDERS) == {}
+    assert cog.bot.store.items(START_NOTIFICATIONS) == {}
+
+
+async def test_owners_left_alone_when_a_host_joined(tmp_path: Path):
+    """Test that nobody is told when an owner is in the call."""
+    cog, guild = make_cog(tmp_path)
+    guild.channels[1].members = [FakeMember(OWNER)]
+
+    await cog.check_host_joined(make_event())
+
+    assert cog.bot.messenger.sent == []
+
+
+async def test_followup_is_posted_after_the_event(tmp_path: Path):
+    """Test that the follow-up is filled in at the start and posted once it's due."""
+    cog, guild = make_cog(tmp_path)
+    followup = {"delay_minutes": 30, "template": "Thanks for joining {name}! Feedback: https://f"}
+    event = make_event(followup=followup)
+
+    await cog.schedule_followup(event)
+    await cog.send_due_followups()
+
+    assert cog.bot.messenger.sent == []
+    stored = cog.bot.store.get(FOLLOWUPS, "evt1")
+    assert stored["due"] == (event.end_time + timedelta(minutes=30)).isoformat()
+
+    due = datetime.now(ZoneInfo("UTC")) - timedelta(minutes=1)
+    cog.bot.store.set(FOLLOWUPS, "evt1", stored | {"due": due.isoformat()})
+    cog.bot.history.start(
+        "evt1",
+        event.name,
+        event.start_time,
+        event.end_time,
+        20,
+        interested=0,
+        rsvps=None,
+        present=[7],
+    )
+    await cog.send_due_followups()
+
+    [message] = cog.bot.messenger.sent_to(guild.channels[0])
+    assert message.content == (
+        "Thanks for joining KCNA Session! Feedback: https://f\n\n👥 1 attended"
+    )
+    assert cog.bot.store.get(FOLLOWUPS, "evt1") is None
+
+
+async def test_private_followup_needs_a_private_channel(tmp_path: Path):
+    """Test that a private event's follow-up isn't posted where members can see it."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event(
+        visibility="private",
+        audience_role="Study Group",
+        followup={"template": "Thanks for joining!"},
+    )
+
+    await cog.schedule_followup(event)
+
+    assert cog.bot.store.get(FOLLOWUPS, "evt1") is None
+
+
+async def test_preview_renders_each_announcement_variant(tmp_path: Path):
+    """Test that previews fill in templates with placeholder links, without creating anything."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event(announcement_templates=["A: {name} in {where}", "B: {link}"])
+
+    previews = await cog.preview_announcements(event)
+
+    assert previews == ["A: KCNA Session in <#20>", "B: (Discord event link)"]
+    assert cog.bot.store.items(DISCORD_EVENTS) == {}
+
+
+async def test_reminder_embed_for_one_event(tmp_path: Path):
+    """Test the reminder card of a single event."""
+    cog, _ = make_cog(tmp_path)
+
+    embed = await cog.reminder_embed([make_event()], 60)
+
+    assert embed.title == "⏰ KCNA Session starts in 1 hour!"
+    assert {field.name: field.value for field in embed.fields}["Where"] == "<#20>"
+
+
+async def test_reminder_pings_the_schedule_role_with_its_template(tmp_path: Path):
+    """Test that a schedule's reminder ping and template replace the defaults."""
+    cog, guild = make_cog(tmp_path)
+    event = make_event(reminder_ping="7", reminder_template="{name} starts {relative} in {where}")
+
+    await cog.send_reminder("events", [event], 10, 1, ping=event.schedule.reminder_ping)
+
+    [message] = cog.bot.messenger.sent_to(guild.channels[0])
+    start = int(event.start_time.timestamp())
+    assert message.content == f"<@&7>\nKCNA Session starts <t:{start}:R> in <#20>"
+    assert message.embed is None
+    assert message.allowed_mentions.roles == [guild.roles[0]]
+
+
+async def test_reminder_template_includes_guild_values(tmp_path: Path):
+    """Test that a value set with /config kv set is filled into a reminder template."""
+    cog, guild = make_cog(tmp_path)
+    cog.bot.template_values.set(1, "notes", "https://hackmd.io/abc")
+    event = make_event(reminder_ping="none", reminder_template="{name} notes: {$notes}")
+
+    await cog.send_reminder("events", [event], 10, 1, ping=event.schedule.reminder_ping)
+
+    [message] = cog.bot.messenger.sent_to(guild.channels[0])
+    assert message.content == "KCNA Session notes: https://hackmd.io/abc"
+
+
+async def test_reminder_buttons_snooze_and_rsvp(tmp_path: Path):
+    """Test that a reminder's buttons DM the member again once, and RSVP them."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event()
+    event.start_time = datetime.now(ZoneInfo("UTC")) + timedelta(hours=1)
+    cog.known_events[event.id] = event
+    cog.bot.rsvps.open(event.id, 100, "KCNA Session", 1, event.end_time)
+    tracked = {"events": [event.id], "end": event.end_time.isoformat()}
+    cog.bot.store.set(REMINDER_MESSAGES, "200", tracked)
+
+    await cog.reminder_action(FakeInteraction(5, message_id=200), "snooze")
+    await cog.reminder_action(FakeInteraction(5, message_id=200), "snooze")
+    await cog.reminder_action(FakeInteraction(5, message_id=200), "going")
+    declined = FakeInteraction(6, message_id=200)
+    await cog.reminder_action(declined, "declined")
+
+    [reminder] = cog.bot.store.items(REMINDERS).values()
+    assert reminder["user_id"] == 5
+    assert datetime.fromisoformat(reminder["due"]) - datetime.now(ZoneInfo("UTC")) <= SNOOZE
+    assert cog.bot.rsvps.get(event.id)["going"] == [5]
+    assert cog.bot.rsvps.get(event.id)["declined"] == [6]
+    assert declined.response.replies == ["Got it, you can't make **KCNA Session**."]
+
+
+async def test_reminder_buttons_of_started_events_do_nothing(tmp_path: Path):
+    """Test that pressing a button on the reminder of an event that started only says so."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event()
+    cog.known_events[event.id] = event
+    tracked = {"events": [event.id], "end": event.end_time.isoformat()}
+    cog.bot.store.set(REMINDER_MESSAGES, "200", tracked)
+    interaction = FakeInteraction(5, message_id=200)
+
+    await cog.reminder_action(interaction, "snooze")
+
+    assert cog.bot.store.items(REMINDERS) == {}
+    assert interaction.response.replies == ["That event already started or was canceled."]
+
+
+async def test_discord_event_is_moved_when_its_time_changes(tmp_path: Path):
+    """Test that editing a schedule's time moves the Discord event already created for it."""
+    cog, guild = make_cog(tmp_path)
+    event = make_event()
+    guild.scheduled_events.append(FakeScheduledEvent(99, event.start_time, event.end_time))
+    cog._track_discord_event(event, 99)
+    moved = make_event()
+    moved.start_time += timedelta(hours=1)
+    moved.end_time += timedelta(hours=1)
+
+    await cog.reschedule_discord_event(moved)
+
+    [scheduled] = guild.scheduled_events
+    assert scheduled.start_time == moved.start_time
+    tracked = cog.bot.store.get(DISCORD_EVENTS, "evt1")
+    assert datetime.fromisoformat(tracked["start"]) == moved.start_time
+
+
+async def test_removed_occurrence_is_cancelled(tmp_path: Path):
+    """Test that an occurrence gone from the schedules file has its Discord event cancelled."""
+    cog, guild = make_cog(tmp_path)
+    event = make_event()
+    event.start_time += timedelta(days=1)
+    guild.scheduled_events.append(FakeScheduledEvent(99))
+    cog._track_discord_event(event, 99)
+    cog.known_events[event.id] = event
+
+    await cog._drop_removed_occurrences(set())
+
+    assert event.id not in cog.known_events
+    assert guild.scheduled_events[0].status == "canceled"
+    assert cog.bot.store.get(DISCORD_EVENTS, "evt1")["status"] == "canceled"
+    [entry] = cog.bot.messenger.audit_entries
+    assert "`evt1`" in entry
+    assert entry.endswith("Removed from the schedules file.")
+
+
+async def test_partners_get_the_announcement_once(tmp_path: Path):
+    """Test that partners get their own template once, and those who opted out get nothing."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event()
+    partner = FakeGuild(2)
+    partner.add_channel(30, "community-events")
+    opted_out = FakeGuild(3)
+    opted_out.add_channel(40, "events")
+    cog.bot.guilds += [partner, opted_out]
+    cog.bot.schedules.config = ScheduleConfig(
+        schedules=[event.schedule],
+        partners=[
+            {
+                "name": "Partner",
+                "guild_id": 2,
+                "channel": "community-events",
+                "schedules": ["kcna session"],
+                "announcement_template": "From CNAYP: {name} {when} in {where}",
+            },
+            {"name": "Other", "guild_id": 3, "channel": "events", "schedules": ["KCNA Session"]},
+        ],
+    )
+    cog.bot.partners.set_opted_out(3, True)
+    fields = {"name": "KCNA Session", "when": "<t:1:F>", "where": "#K8s | KCNA on CNAYP"}
+
+    await cog.announce_to_partners(event, fields)
+    await cog.announce_to_partners(event, fields)
+
+    [message] = cog.bot.messenger.sent
+    assert message.channel is partner.channels[0]
+    assert message.content == "From CNAYP: KCNA Session <t:1:F> in #K8s | KCNA on CNAYP"
+    assert cog.bot.messenger.sent_to(opted_out.channels[0]) == []
+
+
+async def test_interested_and_going_members_are_dmed_once(tmp_path: Path):
+    """Test that members interested or going get one DM reminder, unless they opted out."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event()
+    event.start_time = datetime.now(ZoneInfo("UTC")) + timedelta(minutes=10)
+    cog.bot.interest.add(event.id, event.name, event.start_time, 5)
+    cog.bot.rsvps.open(event.id, 100, event.name, None, event.end_time)
+    cog.bot.rsvps.join(event.id, 5)
+    cog.bot.rsvps.join(event.id, 6)
+    cog.bot.rsvps.join(event.id, 7)
+    cog.bot.dm_reminders.set_opted_out(7, True)
+
+    await cog.send_dm_reminders([event])
+    await cog.send_dm_reminders([event])
+
+    [message] = cog.bot.messenger.sent_to(cog.bot.get_user(5))
+    assert "**KCNA Session** starts" in message.content
+    assert "in <#20>" in message.content
+    assert len(cog.bot.messenger.sent_to(cog.bot.get_user(6))) == 1
+    assert cog.bot.messenger.sent_to(cog.bot.get_user(7)) == []
+
+
+async def test_retried_notification_keeps_its_nonce(tmp_path: Path):
+    """Test that a notification timing out is sent again with the same nonce."""
+    cog, _ = make_cog(tmp_path)
+    nonces = []
+
+    async def send(nonce: str) -> None:
+        nonces.append(nonce)
+        raise TimeoutError
+
+    await cog._send_notification("the reminder", send)
+    [pending] = cog.retries.due(datetime.now(ZoneInfo("UTC")) + timedelta(minutes=1))
+    try:
+        await pending.send()
+    except TimeoutError:
+        pass
+
+    assert len(nonces) == 2
+    assert nonces[0] == nonces[1]
+    assert len(nonces[0]) <= 25
+
+
+async def test_reminders_are_held_through_quiet_hours_in_the_event_timezone(
+    tmp_path: Path, monkeypatch: pytest.MonkeyPatch
+):
+    """Test that a reminder due at night where the event is waits in the store until morning."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event(reminder_ping="none", reminder_template="{name} starts soon")
+    event.start_time = datetime.now(ZoneInfo("UTC")) + timedelta(minutes=14, seconds=30)
+    cog.known_events[event.id] = event
+    lima_now = datetime.now(ZoneInfo("America/Lima"))
+    monkeypatch.setattr(settings, "reminder_minutes", [15])
+    monkeypatch.setattr(settings, "quiet_hours_start", (lima_now - timedelta(hours=1)).time())
+    monkeypatch.setattr(settings, "quiet_hours_end", (lima_now + timedelta(hours=1)).time())
+
+    await cog.send_due_reminders([event])
+
+    assert cog.bot.messenger.sent == []
+    assert list(cog.bot.store.items(HELD_REMINDERS)) == [event.id]
+
+    # After a restart, once quiet hours are over
+    restarted, guild = make_cog(tmp_path)
+    restarted.known_events[event.id] = event
+    monkeypatch.setattr(settings, "quiet_hours_start", (lima_now + timedelta(hours=2)).time())
+    monkeypatch.setattr(settings, "quiet_hours_end", (lima_now + timedelta(hours=3)).time())
+
+    await restarted.send_due_reminders([event])
+
+    [message] = restarted.bot.messenger.sent_to(guild.channels[0])
+    assert message.content == "KCNA Session starts soon"
+    assert restarted.bot.store.items(HELD_REMINDERS) == {}
+
+
+def test_ping_mentions_only_the_chosen_target(tmp_path: Path, monkeypatch: pytest.MonkeyPatch):
+    """Test each ping mode, and that private events ping their audience instead."""
+    _, guild = make_cog(tmp_path)
+    channel, role = guild.channels[0], guild.roles[0]
+    monkeypatch.setattr(settings, "notification_role", "Study Group")
+
+    assert _ping(channel, "none")[0] is None
+    assert not _ping(channel, "none")[1].everyone
+
+    mention, allowed = _ping(channel, "role")
+    assert mention == "<@&7>"
+    assert allowed.roles == [role]
+    assert not allowed.everyone
+    assert _ping(channel, 7)[0] == "<@&7>"
+    assert _ping(channel, 8)[0] is None
+
+    for mode in ("everyone", "here"):
+        mention, allowed = _ping(channel, mode)
+        assert mention == f"@{mode}"
+        assert allowed.everyone
+        assert not allowed.roles
+
+    # A private event's audience replaces @everyone, but "none" still pings nobody
+    mention, allowed = _ping(channel, "everyone", "Study Group")
+    assert mention == "<@&7>"
+    assert not allowed.everyone
+    assert _ping(channel, "none", "Study Group")[0] is None
+
+
+async def test_reminder_embed_lists_when_where_and_who_is_going(tmp_path: Path):
+    """Test the reminder card of one event, and of several in the same channel."""
+    cog, _ = make_cog(tmp_path)
+    event = make_event()
+    other = make_event()
+    other.id, other.name = "evt2", "CKA Session"
+    cog.bot.rsvps.open(event.id, 100, event.name, None, event.end_time)
+    cog.bot.rsvps.join(event.id, 5)
+
+    embed = await cog.reminder_embed([event], 15, 1)
+
+    start = int(event.start_time.timestamp())
+    assert embed.title == "⏰ KCNA Session starts in 15 minutes!"
+    assert [(field.name, field.value) for field in embed.fields] == [
+        ("When", f"<t:{start}:F> (<t:{start}:R>)"),
+        ("Duration", "60 minutes"),
+        ("Where", "<#20>"),
+        ("Going (1)", "<@5>"),
+    ]
+
+    embed = await cog.reminder_embed([event, other], 15, 1)
+
+    assert embed.title == "⏰ 2 events coming up today!"
+    assert embed.description.splitlines() == [
+        f"• **KCNA Session** <t:{start}:R> (60 min, 1 going) in <#20>",
+        f"• **CKA Session** <t:{start}:R> (60 min) in <#20>",
+    ]
+
@@ -0,0 +1,257 @@
+"""Tests for schedules-file event expansion."""
+
+import os
+from datetime import date, datetime
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.models import Schedule, ScheduleConfig
+from cnayp_bot.services.schedules import (
+    ScheduleService,
+    is_visible_to,
+    load_schedule_config,
+    recurrence_rule,
+    save_schedule_config,
+    schedule_occurrences,
+    schedule_when,
+    starter_schedule_config,
+)
+
+LIMA = ZoneInfo("America/Lima")
+
+
+def make_schedule(**overrides) -> Schedule:
+    """Create a schedule for tests."""
+    data = {
+        "name": "KCNA Session",
+        "description": "Study session",
+        "voice_channel": "K8s | KCNA",
+        "notify_channel": "events",
+        "days": ["monday", "Wednesday"],
+        "time": "18:00",
+        "timezone": "America/Lima",
+        "duration_minutes": 120,
+    }
+    return Schedule.model_validate(data | overrides)
+
+
+def test_occurrences_in_range():
+    """Test that each matching weekday yields one occurrence in the schedule's timezone."""
+    # 2025-03-03 is a Monday
+    events = schedule_occurrences(
+        make_schedule(),
+        datetime(2025, 3, 3, tzinfo=LIMA),
+        datetime(2025, 3, 10, tzinfo=LIMA),
+    )
+
+    assert [event.start_time for event in events] == [
+        datetime(2025, 3, 3, 18, 0, tzinfo=LIMA),
+        datetime(2025, 3, 5, 18, 0, tzinfo=LIMA),
+    ]
+    assert events[0].id == "schedule-kcna-session-2025-03-03"
+    assert events[0].duration_minutes == 120
+    assert events[0].schedule.voice_channel == "K8s | KCNA"
+
+
+def test_occurrences_include_in_progress():
+    """Test that an occurrence that already started but hasn't ended is included."""
+    events = schedule_occurrences(
+        make_schedule(),
+        datetime(2025, 3, 3, 19, 0, tzinfo=LIMA),
+        datetime(2025, 3, 4, tzinfo=LIMA),
+    )
+
+    assert len(events) == 1
+
+
+def test_occurrences_exclude_ended():
+    """Test that finished occurrences are skipped."""
+    events = schedule_occurrences(
+        make_schedule(),
+        datetime(2025, 3, 3, 20, 0, tzinfo=LIMA),
+        datetime(2025, 3, 4, tzinfo=LIMA),
+    )
+
+    assert events == []
+
+
+def test_disabled_schedules_are_skipped(tmp_path: Path):
+    """Test that the service ignores disabled schedules."""
+    path = tmp_path / "schedules.json"
+    config = ScheduleConfig(
+        schedules=[make_schedule(), make_schedule(name="Draft", enabled=False)]
+    )
+    save_schedule_config(config, path)
+
+    events = ScheduleService(path).get_events_between(
+        datetime(2025, 3, 3, tzinfo=LIMA), datetime(2025, 3, 10, tzinfo=LIMA)
+    )
+
+    assert {event.name for event in events} == {"KCNA Session"}
+
+
+def test_excluded_dates_and_holidays_are_skipped(tmp_path: Path):
+    """Test that nothing runs on a schedule's excluded dates or on holidays."""
+    path = tmp_path / "schedules.json"
+    config = ScheduleConfig(
+        schedules=[make_schedule(exclude_dates=[date(2025, 3, 3)])],
+        holidays=[date(2025, 3, 10)],
+    )
+    save_schedule_config(config, path)
+    service = ScheduleService(path)
+    service.calendar_holidays = {date(2025, 3, 12)}
+
+    events = service.get_events_between(
+        datetime(2025, 3, 3, tzinfo=LIMA), datetime(2025, 3, 17, tzinfo=LIMA)
+    )
+
+    assert [event.start_time.date() for event in events] == [date(2025, 3, 5)]
+
+
+def test_missing_file_is_empty(tmp_path: Path):
+    """Test that a missing schedules file means no schedules."""
+    assert load_schedule_config(tmp_path / "missing.json").schedules == []
+
+
+def test_edited_file_is_reloaded_and_invalid_edits_are_ignored(tmp_path: Path):
+    """Test that hand edits are picked up, and a broken file keeps the old schedules."""
+    path = tmp_path / "schedules.json"
+    save_schedule_config(ScheduleConfig(schedules=[make_schedule()]), path)
+    service = ScheduleService(path)
+    assert not service.changed_on_disk()
+
+    save_schedule_config(ScheduleConfig(schedules=[make_schedule(time="19:00")]), path)
+    os.utime(path, (0, 0))
+    assert service.changed_on_disk()
+    service.reload()
+    assert service.config.schedules[0].time == "19:00"
+    assert not service.changed_on_disk()
+
+    path.write_text('{"schedules": [{"name": "Broken"}]}', encoding="utf-8")
+    os.utime(path, (1, 1))
+    with pytest.raises(ValueError):
+        service.reload()
+    assert service.config.schedules[0].time == "19:00"
+    assert not service.changed_on_disk()
+
+
+def test_starter_config_round_trips(tmp_path: Path):
+    """Test that the starter config is saved and loaded intact, disabled."""
+    path = tmp_path / "schedules.json"
+    save_schedule_config(starter_schedule_config("events", "K8s | KCNA", "America/Lima"), path)
+
+    config = load_schedule_config(path)
+    assert config.digest_channel == "events"
+    assert [schedule.enabled for schedule in config.schedules] == [False]
+
+
+def test_one_off_schedules_run_on_their_date_and_are_consumed(tmp_path: Path):
+    """Test that a dated schedule has one occurrence and is disabled once it's over."""
+    path = tmp_path / "schedules.json"
+    one_off = make_schedule(name="Community Call", days=[], date="2025-03-05")
+    save_schedule_config(ScheduleConfig(schedules=[make_schedule(), one_off]), path)
+    service = ScheduleService(path)
+
+    events = service.get_events_between(
+        datetime(2025, 3, 3, tzinfo=LIMA), datetime(2025, 3, 17, tzinfo=LIMA)
+    )
+    calls = [event.start_time for event in events if event.name == "Community Call"]
+    assert calls == [datetime(2025, 3, 5, 18, 0, tzinfo=LIMA)]
+
+    assert service.consume_finished(datetime(2025, 3, 5, 19, 0, tzinfo=LIMA)) == []
+    assert service.consume_finished(datetime(2025, 3, 5, 20, 0, tzinfo=LIMA)) == ["Community Call"]
+    assert [schedule.enabled for schedule in load_schedule_config(path).schedules] == [True, False]
+
+
+def test_cron_schedules_expand_to_each_matching_time():
+    """Test that a cron schedule runs on its matching days, in its timezone."""
+    schedule = make_schedule(days=[], time="", cron="0 19 * * TUE#1")
+
+    events = schedule_occurrences(
+        schedule, datetime(2025, 3, 1, tzinfo=LIMA), datetime(2025, 5, 1, tzinfo=LIMA)
+    )
+
+    assert [event.start_time for event in events] == [
+        datetime(2025, 3, 4, 19, 0, tzinfo=LIMA),
+        datetime(2025, 4, 1, 19, 0, tzinfo=LIMA),
+    ]
+    assert events[0].id == "schedule-kcna-session-2025-03-04"
+
+
+def test_schedules_need_days_or_a_date():
+    """Test that a schedule has either weekdays or a date."""
+    with pytest.raises(ValueError):
+        make_schedule(days=[])
+    with pytest.raises(ValueError):
+        make_schedule(date="2025-03-05")
+    with pytest.raises(ValueError):
+        make_schedule(days=[], cron="0 19 * * 8")
+    with pytest.raises(ValueError, match="leave time empty"):
+        make_schedule(days=[], cron="0 19 * * 1")
+
+
+def test_schedules_can_skip_weeks():
+    """Test that a schedule runs every few weeks from the week of its starting date."""
+    # 2025-03-05 is a Wednesday, so its week starts on Monday 2025-03-03
+    schedule = make_schedule(every_weeks=2, starting=date(2025, 3, 5))
+    events = schedule_occurrences(
+        schedule, datetime(2025, 2, 24, tzinfo=LIMA), datetime(2025, 3, 24, tzinfo=LIMA)
+    )
+
+    assert [event.start_time.date() for event in events] == [
+        date(2025, 3, 5),
+        date(2025, 3, 17),
+        date(2025, 3, 19),
+    ]
+    assert schedule_when(schedule) == "Monday, Wednesday at 18:00, every 2 weeks from 2025-03-05"
+
+    with pytest.raises(ValueError, match="starting date"):
+        make_schedule(every_weeks=2)
+    with pytest.raises(ValueError, match="Only weekly"):
+        make_schedule(days=[], date="2025-03-05", starting=date(2025, 3, 5))
+    with pytest.raises(ValueError, match="every few weeks on one day"):
+        make_schedule(
+            days=["monday", "tuesday", "wednesday", "thursday", "friday"],
+            every_weeks=2,
+            starting=date(2025, 3, 3),
+            native_recurrence=True,
+        )
+
+
+def test_recurrence_rule_weekly_and_daily():
+    """Test that one day repeats weekly and supported day sets repeat daily."""
+    start = datetime(2025, 3, 3, 18, 0, tzinfo=LIMA)
+
+    weekly = recurrence_rule(make_schedule(days=["Monday"], native_recurrence=True), start)
+    assert weekly == {"start": start.isoformat(), "interval": 1, "frequency": 2, "by_weekday": [0]}
+
+    weekend = make_schedule(days=["sunday", "saturday"], native_recurrence=True)
+    assert recurrence_rule(weekend, start)["by_weekday"] == [5, 6]
+
+    fortnightly = make_schedule(
+        days=["Monday"], every_weeks=2, starting=date(2025, 3, 3), native_recurrence=True
+    )
+    assert recurrence_rule(fortnightly, start)["interval"] == 2
+
+
+def test_native_recurrence_rejects_unsupported_days():
+    """Test that day sets Discord can't repeat on are rejected."""
+    with pytest.raises(ValueError, match="Discord can only repeat"):
+        make_schedule(native_recurrence=True)
+
+    assert not make_schedule().native_recurrence
+
+
+def test_private_events_are_visible_to_their_audience():
+    """Test that a private event is only visible to members holding its audience role."""
+    public = make_schedule()
+    private = make_schedule(visibility="private", audience_role="Organizers")
+    start, end = datetime(2025, 3, 1, tzinfo=LIMA), datetime(2025, 3, 8, tzinfo=LIMA)
+    [public_event, *_] = schedule_occurrences(public, start, end)
+    [private_event, *_] = schedule_occurrences(private, start, end)
+
+    assert is_visible_to(public_event, set())
+    assert not is_visible_to(private_event, {"Members"})
+    assert is_visible_to(private_event, {"Members", "Organizers"})
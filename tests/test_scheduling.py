@@ -0,0 +1,337 @@
+"""Tests for scheduler timing rules and the simulator."""
+
+from datetime import date, datetime, time, timedelta
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.config import settings
+from cnayp_bot.models import Schedule, ScheduleConfig
+from cnayp_bot.scheduling import (
+    digest_due,
+    digest_overdue,
+    discord_event_overdue,
+    dm_reminder_due,
+    due_reminders,
+    find_conflicts,
+    followup_stale,
+    followup_time,
+    has_started,
+    held_reminder_batches,
+    host_check_due,
+    in_quiet_hours,
+    minutes_until,
+    next_status,
+    reminder_batches,
+    retry_delay,
+    should_create_discord_event,
+    weekly_digest_due,
+)
+from cnayp_bot.services.calendar import CalendarEvent
+from cnayp_bot.simulate import simulate
+
+UTC = ZoneInfo("UTC")
+START = datetime(2025, 3, 10, 18, 0, tzinfo=UTC)
+
+
+def make_event(event_id: str = "evt1", start: datetime = START) -> CalendarEvent:
+    return CalendarEvent(
+        id=event_id,
+        name="Go Study",
+        description="",
+        start_time=start,
+        end_time=start + timedelta(hours=2),
+        timezone="UTC",
+    )
+
+
+def test_minutes_until_rounds():
+    """Test that minutes until start are rounded."""
+    event = make_event()
+
+    assert minutes_until(event, START - timedelta(minutes=15, seconds=29)) == 15
+    assert minutes_until(event, START - timedelta(minutes=15, seconds=31)) == 16
+
+
+def test_should_create_within_24_hours():
+    """Test the Discord event creation window."""
+    event = make_event()
+
+    assert not should_create_discord_event(event, START - timedelta(hours=25))
+    assert should_create_discord_event(event, START - timedelta(hours=24))
+    assert should_create_discord_event(event, START)
+    assert not should_create_discord_event(event, START + timedelta(minutes=1))
+
+
+def test_due_reminders_window():
+    """Test that reminders trigger at or just past their threshold."""
+    event = make_event()
+
+    assert due_reminders(event, START - timedelta(minutes=46), [45, 10]) == []
+    assert due_reminders(event, START - timedelta(minutes=45), [45, 10]) == [45]
+    assert due_reminders(event, START - timedelta(minutes=44), [45, 10]) == [45]
+    assert due_reminders(event, START - timedelta(minutes=43), [45, 10]) == []
+
+
+def test_dm_reminders_are_due_until_the_start():
+    """Test that DM reminders are due within their minutes of the start, until it starts."""
+    event = make_event()
+
+    assert not dm_reminder_due(event, START - timedelta(minutes=16), 15)
+    assert dm_reminder_due(event, START - timedelta(minutes=15), 15)
+    assert dm_reminder_due(event, START - timedelta(minutes=3), 15)
+    assert not dm_reminder_due(event, START, 15)
+
+
+def test_conflicts_are_overlapping_events_in_the_same_room():
+    """Test that only events on at the same time in the same voice channel conflict."""
+    first = make_event("evt1")
+    overlapping = make_event("evt2", START + timedelta(hours=1))
+    elsewhere = make_event("evt3", START + timedelta(minutes=30))
+    back_to_back = make_event("evt4", START + timedelta(hours=3))
+    rooms = {"evt1": "kcna", "evt2": "kcna", "evt3": "cka", "evt4": "kcna"}
+
+    conflicts = find_conflicts(
+        [back_to_back, elsewhere, overlapping, first], lambda event: rooms[event.id]
+    )
+
+    assert conflicts == [(first, overlapping)]
+
+
+def test_has_started():
+    """Test event start detection."""
+    event = make_event()
+
+    assert not has_started(event, START - timedelta(minutes=1))
+    assert has_started(event, START)
+
+
+def test_host_check_due_while_the_event_is_on():
+    """Test that hosts are checked for a few minutes after the start, until the end."""
+    event = make_event()
+
+    assert not host_check_due(event, START + timedelta(minutes=4), 5)
+    assert host_check_due(event, START + timedelta(minutes=5), 5)
+    assert not host_check_due(event, START + timedelta(hours=2), 5)
+
+
+def test_quiet_hours_may_span_midnight():
+    """Test that quiet hours from 23:00 to 08:00 cover the night, and nothing without an end."""
+    night = (time(23, 0), time(8, 0))
+
+    assert in_quiet_hours(START.replace(hour=23, minute=30), *night)
+    assert in_quiet_hours(START.replace(hour=7, minute=59), *night)
+    assert not in_quiet_hours(START.replace(hour=8, minute=0), *night)
+    assert in_quiet_hours(START.replace(hour=13), time(12, 0), time(14, 0))
+    assert not in_quiet_hours(START.replace(hour=23, minute=30), time(23, 0), None)
+
+
+def test_simulate_single_event():
+    """Test that the simulator produces each action exactly once."""
+    event = make_event()
+
+    actions = simulate([event], START - timedelta(days=2), START + timedelta(hours=1), [45, 10])
+
+    assert [action.kind for action in actions] == ["create", "reminder", "reminder", "start"]
+    assert actions[0].time == START - timedelta(hours=24)
+    assert actions[1].time == START - timedelta(minutes=45)
+    assert actions[2].time == START - timedelta(minutes=10)
+    assert actions[3].time == START
+
+
+def test_simulate_follows_the_schedule_channels_and_follow_ups(monkeypatch: pytest.MonkeyPatch):
+    """Test that the simulator reminds per schedule ping, and posts follow-ups and digests."""
+    monkeypatch.setattr(settings, "default_timezone", "UTC")
+    event = make_event()
+    event.schedule = Schedule(
+        name="Study",
+        description="",
+        voice_channel="study",
+        notify_channel="study-chat",
+        days=["monday"],
+        time="18:00",
+        timezone="UTC",
+        duration_minutes=120,
+        reminder_ping="here",
+        followup={"delay_minutes": 30, "template": "Thanks for coming!"},
+    )
+    config = ScheduleConfig(digest_time="8:00", digest_channel="events")
+
+    start, end = START - timedelta(hours=12), START + timedelta(hours=3)
+    actions = simulate([event], start, end, [10], config)
+
+    assert [(action.kind, action.time) for action in actions] == [
+        ("create", start),
+        ("digest", datetime(2025, 3, 10, 8, 0, tzinfo=UTC)),
+        ("reminder", START - timedelta(minutes=10)),
+        ("start", START),
+        ("followup", START + timedelta(hours=2, minutes=30)),
+    ]
+    assert "#study-chat" in actions[2].description
+    assert actions[2].description.endswith("ping here")
+
+
+def test_simulate_ignores_events_outside_range():
+    """Test that events after the simulated range produce no actions."""
+    event = make_event(start=START + timedelta(days=5))
+
+    assert simulate([event], START - timedelta(days=1), START, [45, 10]) == []
+
+
+def test_next_status_follows_discord_transitions():
+    """Test that events go active at start and completed at end, one step at a time."""
+    event = make_event()
+    end = START + timedelta(hours=2)
+
+    assert next_status(event, START - timedelta(minutes=1), "scheduled") is None
+    assert next_status(event, START, "scheduled") == "active"
+    assert next_status(event, START + timedelta(hours=1), "active") is None
+    assert next_status(event, end, "active") == "completed"
+    assert next_status(event, end, "scheduled") == "active"
+    assert next_status(event, end, "canceled") is None
+
+
+def test_digest_due_once_per_day():
+    """Test that the digest is due from its time of day until posted that day."""
+    morning = datetime(2025, 3, 10, 8, 0, tzinfo=UTC)
+
+    assert not digest_due(morning - timedelta(minutes=1), "8:00", None)
+    assert digest_due(morning, "8:00", None)
+    assert digest_due(morning + timedelta(hours=3), "08:00", date(2025, 3, 9))
+    assert not digest_due(morning + timedelta(hours=3), "8:00", date(2025, 3, 10))
+
+
+def test_weekly_digest_due_on_its_day():
+    """Test that the weekly digest is due on its weekday from its time, once."""
+    monday = datetime(2025, 3, 10, 9, 0, tzinfo=UTC)
+
+    assert weekly_digest_due(monday, "monday", "9:00", date(2025, 3, 3))
+    assert not weekly_digest_due(monday - timedelta(minutes=1), "monday", "9:00", None)
+    assert not weekly_digest_due(monday, "monday", "9:00", date(2025, 3, 10))
+    assert not weekly_digest_due(monday + timedelta(days=1), "monday", "9:00", date(2025, 3, 3))
+
+
+def test_reminder_batches_combine_same_day_events():
+    """Test that same-day events in one channel share a reminder, other days don't."""
+    first = make_event("evt1", START)
+    second = make_event("evt2", START + timedelta(hours=3))
+    next_day = make_event("evt3", START + timedelta(days=1))
+    now = START - timedelta(minutes=15)
+
+    batches = reminder_batches(
+        [second, next_day, first], now, [15], set(), lambda event: "events", UTC
+    )
+    assert batches == {("events", 15): [first, second]}
+
+    sent = {"evt1:15", "evt2:15"}
+    later = second.start_time - timedelta(minutes=15)
+    events = [first, second, next_day]
+    assert reminder_batches(events, later, [15], sent, lambda event: "events", UTC) == {}
+
+
+def test_schedule_reminder_offsets_replace_the_default():
+    """Test that a schedule's own offsets are used, and its events don't join other batches."""
+    first = make_event("evt1", START)
+    second = make_event("evt2", START + timedelta(hours=3))
+    second.schedule = Schedule(
+        name="Study",
+        description="",
+        voice_channel="study",
+        notify_channel="events",
+        days=["monday"],
+        time="21:00",
+        timezone="UTC",
+        duration_minutes=120,
+        reminder_minutes=[60],
+    )
+
+    batches = reminder_batches(
+        [first, second], START - timedelta(minutes=15), [15], set(), lambda event: "events", UTC
+    )
+    assert batches == {("events", 15): [first]}
+
+    later = second.start_time - timedelta(minutes=60)
+    assert reminder_batches([second], later, [15], set(), lambda event: "events", UTC) == {
+        ("events", 60): [second]
+    }
+
+
+def test_reminder_batches_keep_guilds_apart():
+    """Test that same-named channels of different guilds get their own reminders."""
+    first = make_event("evt1", START)
+    second = make_event("evt2", START + timedelta(hours=3))
+    guilds = {"evt1": 1, "evt2": 2}
+
+    batches = reminder_batches(
+        [first, second],
+        START - timedelta(minutes=15),
+        [15],
+        set(),
+        lambda event: (guilds[event.id], "events"),
+        UTC,
+    )
+
+    assert batches == {((1, "events"), 15): [first]}
+
+
+def test_held_reminders_go_out_after_quiet_hours():
+    """Test that held reminders are sent per channel once quiet, and dropped once started."""
+    first = make_event("evt1", START)
+    second = make_event("evt2", START + timedelta(hours=1))
+    held = {"evt1": first.start_time, "evt2": second.start_time, "evt3": START}
+    events = {"evt1": first, "evt2": second}
+
+    def quiet(event: CalendarEvent, now: datetime) -> bool:
+        return False
+
+    batches, dropped = held_reminder_batches(
+        held, events, START - timedelta(minutes=5), lambda event: "events", quiet
+    )
+    assert batches == {"events": [first, second]}
+    assert dropped == []
+
+    batches, dropped = held_reminder_batches(held, events, START, lambda event: "events", quiet)
+    assert batches == {"events": [second]}
+    assert dropped == ["evt1", "evt3"]
+
+    assert held_reminder_batches(
+        held, events, START - timedelta(minutes=5), lambda event: "events", lambda *_: True
+    ) == ({}, [])
+
+
+def test_followups_are_due_after_the_end_until_stale():
+    """Test that a follow-up is due its delay after the end, and dropped 12 hours later."""
+    event = make_event()
+    due = followup_time(event, 15)
+
+    assert due == START + timedelta(hours=2, minutes=15)
+    assert not followup_stale(due, due + timedelta(hours=12))
+    assert followup_stale(due, due + timedelta(hours=12, minutes=1))
+
+
+def test_digest_overdue_after_grace():
+    """Test that a digest is overdue once the grace period after its time passes."""
+    morning = datetime(2025, 3, 10, 8, 0, tzinfo=UTC)
+
+    assert not digest_overdue(morning + timedelta(minutes=4), "8:00", None)
+    assert digest_overdue(morning + timedelta(minutes=5), "8:00", date(2025, 3, 9))
+    assert not digest_overdue(morning + timedelta(hours=3), "8:00", date(2025, 3, 10))
+    assert not digest_overdue(morning - timedelta(hours=1), "8:00", date(2025, 3, 9))
+
+
+def test_discord_event_overdue_after_grace():
+    """Test that an event is overdue a grace period into its creation window."""
+    event = make_event()
+
+    assert not discord_event_overdue(event, START - timedelta(hours=24))
+    assert discord_event_overdue(event, START - timedelta(hours=23, minutes=55))
+    assert discord_event_overdue(event, START - timedelta(minutes=1))
+    assert not discord_event_overdue(event, START)
+
+
+def test_retry_delay_backs_off_to_an_hour():
+    """Test that retries double their delay up to an hour."""
+    assert retry_delay(1) == timedelta(minutes=1)
+    assert retry_delay(3) == timedelta(minutes=4)
+    assert retry_delay(7) == timedelta(hours=1)
+    assert retry_delay(20) == timedelta(hours=1)
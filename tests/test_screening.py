@@ -0,0 +1,56 @@
+"""Tests for passing on members once they accept the rules."""
+
+from pathlib import Path
+from types import SimpleNamespace
+
+from cnayp_bot.cogs.screening import PENDING_MEMBERS, ScreeningCog
+
+from .fakes import FakeBot, FakeGuild
+
+
+def make_member(guild: FakeGuild, member_id: int, *, pending: bool) -> SimpleNamespace:
+    """Create a member of `guild`, who hasn't accepted the rules if `pending`."""
+    return SimpleNamespace(id=member_id, guild=guild, bot=False, pending=pending, joined_at=None)
+
+
+def screened(cog: ScreeningCog) -> list[int]:
+    """Return the IDs of the members passed on so far."""
+    return [args[0].id for event, args in cog.bot.dispatched if event == "member_screened"]
+
+
+async def test_members_are_passed_on_once_they_accept(tmp_path: Path):
+    """Test that pending members are held back until they accept, others pass right away."""
+    guild = FakeGuild(1)
+    cog = ScreeningCog(FakeBot(tmp_path, guild))
+    pending = make_member(guild, 5, pending=True)
+
+    await cog.on_member_join(make_member(guild, 4, pending=False))
+    await cog.on_member_join(pending)
+
+    assert screened(cog) == [4]
+    assert list(cog.bot.store.items(PENDING_MEMBERS)) == ["5"]
+
+    await cog.on_member_update(pending, make_member(guild, 5, pending=False))
+
+    assert screened(cog) == [4, 5]
+    assert cog.bot.store.items(PENDING_MEMBERS) == {}
+
+
+async def test_members_who_accepted_while_offline_are_passed_on_when_ready(tmp_path: Path):
+    """Test that the startup sweep passes on accepted members and remembers new pending ones."""
+    guild = FakeGuild(1)
+    cog = ScreeningCog(FakeBot(tmp_path, guild))
+    for member_id in (5, 6, 7):
+        await cog.on_member_join(make_member(guild, member_id, pending=True))
+    # 5 accepted, 6 is still pending, 7 left, and 8 joined while the bot was offline
+    guild.members = [
+        make_member(guild, 5, pending=False),
+        make_member(guild, 6, pending=True),
+        make_member(guild, 8, pending=True),
+    ]
+
+    await cog.on_ready()
+    await cog.on_ready()
+
+    assert screened(cog) == [5]
+    assert sorted(cog.bot.store.items(PENDING_MEMBERS)) == ["6", "8"]
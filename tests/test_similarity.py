@@ -0,0 +1,40 @@
+"""Tests for question similarity."""
+
+import pytest
+
+from cnayp_bot.helpers.similarity import best_match, cosine_similarity, tokenize
+
+TAGS = {
+    "kubectl-install": "kubectl install: download kubectl for linux, macos, or windows",
+    "kcna-exam": "KCNA exam: registration, price, and the exam curriculum",
+}
+
+
+def test_tokenize_drops_stopwords():
+    """Test that common English and Spanish words are ignored."""
+    tokens = tokenize("How do I install kubectl on Linux?")
+
+    assert tokens == {"install": 1, "kubectl": 1, "linux": 1}
+    assert tokenize("¿Cómo instalo kubectl?") == {"instalo": 1, "kubectl": 1}
+
+
+def test_cosine_similarity_bounds():
+    """Test identical and unrelated token sets."""
+    tokens = tokenize("kcna exam price")
+
+    assert cosine_similarity(tokens, tokens) == pytest.approx(1.0)
+    assert cosine_similarity(tokens, tokenize("helm charts")) == 0.0
+
+
+def test_best_match_picks_closest_tag():
+    """Test that the most similar document wins."""
+    key, score = best_match("how much is the kcna exam?", TAGS)
+
+    assert key == "kcna-exam"
+    assert 0 < score < 1
+
+
+def test_best_match_without_overlap():
+    """Test that no match is returned when nothing overlaps."""
+    assert best_match("anyone up for pizza", TAGS) is None
+    assert best_match("kubectl", {}) is None
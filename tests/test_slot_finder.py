@@ -0,0 +1,74 @@
+"""Tests for /findtime slot proposals and polls."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.slot_finder import (
+    SLOTS_PER_DAY,
+    SlotFinder,
+    propose_slots,
+    rank_slots,
+    suits,
+)
+from cnayp_bot.services.store import Store
+
+NOW = datetime(2025, 3, 10, 12, 30, tzinfo=ZoneInfo("UTC"))
+LIMA = ZoneInfo("America/Lima")
+MADRID = ZoneInfo("Europe/Madrid")
+HOUR = timedelta(hours=1)
+
+
+def test_slots_must_fit_in_waking_hours():
+    """Test that slots starting too early or ending too late don't suit."""
+    lima = datetime(2025, 3, 10, tzinfo=LIMA)
+
+    assert suits(lima.replace(hour=9), HOUR, LIMA)
+    assert suits(lima.replace(hour=21), HOUR, LIMA)
+    assert not suits(lima.replace(hour=8), HOUR, LIMA)
+    assert not suits(lima.replace(hour=21, minute=30), HOUR, LIMA)
+    assert not suits(lima.replace(hour=23), 2 * HOUR, LIMA)
+
+
+def test_slots_that_suit_everyone_come_first():
+    """Test that slots suiting both timezones are proposed before the rest."""
+    slots = propose_slots([LIMA, LIMA, MADRID], NOW, HOUR, days=1, count=3)
+
+    # 14:00 UTC is 9:00 in Lima and 15:00 in Madrid
+    assert slots == [NOW.replace(hour=hour, minute=0) for hour in (14, 15, 16)]
+    assert not suits(NOW.replace(hour=13, minute=0), HOUR, LIMA)
+
+
+def test_slots_are_spread_over_days():
+    """Test that no day gets more than its share of the slots."""
+    slots = propose_slots([LIMA], NOW, HOUR, days=3, count=9)
+
+    assert len(slots) == 9
+    assert slots == sorted(slots)
+    for day in {slot.date() for slot in slots}:
+        assert sum(slot.date() == day for slot in slots) <= SLOTS_PER_DAY
+
+
+def test_slots_rank_by_availability_then_time():
+    """Test ranking the slots, leaving out those nobody can make."""
+    assert rank_slots([[1], [1, 2], [], [2, 3]]) == [1, 3, 0]
+    assert rank_slots([[], []]) == []
+
+
+def test_members_toggle_their_availability(tmp_path: Path):
+    """Test that pressing a slot twice takes the answer back."""
+    finder = SlotFinder(Store(tmp_path / "store.json"))
+    slots = [NOW + HOUR, NOW + 2 * HOUR]
+    poll_id = finder.create("SIG sync", 5, 1, 10, HOUR, slots)
+
+    assert finder.toggle(poll_id, 1, 100) is True
+    assert finder.toggle(poll_id, 1, 200) is True
+    assert finder.toggle(poll_id, 1, 100) is False
+    poll = finder.get(poll_id)
+    assert poll["available"] == [[], [200]]
+    assert poll["duration"] == 60
+
+    finder.forget_finished(NOW + 2 * HOUR)
+    assert finder.get(poll_id) is not None
+    finder.forget_finished(NOW + 3 * HOUR)
+    assert finder.get(poll_id) is None
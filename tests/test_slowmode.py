@@ -0,0 +1,44 @@
+"""Tests for event slowmode overrides."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.slowmode import SlowmodeOverrides
+from cnayp_bot.services.store import Store
+
+END = datetime(2025, 3, 3, 20, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def test_original_slowmode_is_restored_after_the_event(tmp_path: Path):
+    """Test that the channel's own slowmode is kept until the event ends, across restarts."""
+    path = tmp_path / "store.json"
+    overrides = SlowmodeOverrides(Store(path))
+
+    assert overrides.start(1, current=0, delay=5, until=END)
+    assert not overrides.start(1, current=5, delay=5, until=END)
+
+    overrides = SlowmodeOverrides(Store(path))
+    assert overrides.expired(END - timedelta(minutes=1)) == {}
+    assert overrides.expired(END) == {1: 0}
+    overrides.remove(1)
+    assert overrides.expired(END) == {}
+
+
+def test_overlapping_events_share_the_override(tmp_path: Path):
+    """Test that overlapping events extend the override and raise its delay."""
+    overrides = SlowmodeOverrides(Store(tmp_path / "store.json"))
+    overrides.start(1, current=2, delay=5, until=END)
+
+    assert overrides.start(1, current=5, delay=10, until=END + timedelta(hours=1))
+    assert not overrides.start(1, current=10, delay=5, until=END)
+    assert overrides.expired(END) == {}
+    assert overrides.expired(END + timedelta(hours=1)) == {1: 2}
+
+
+def test_no_change_needed_when_already_slow(tmp_path: Path):
+    """Test that a channel already at the event's slowmode isn't edited."""
+    overrides = SlowmodeOverrides(Store(tmp_path / "store.json"))
+
+    assert not overrides.start(1, current=5, delay=5, until=END)
+    assert overrides.expired(END) == {1: 5}
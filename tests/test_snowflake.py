@@ -0,0 +1,49 @@
+"""Tests for Discord snowflake IDs."""
+
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+import pytest
+from pydantic import TypeAdapter, ValidationError
+
+from cnayp_bot.helpers.snowflake import Snowflake, parse_snowflake, snowflake_time
+
+# Example ID from Discord's API reference
+EXAMPLE_ID = 175928847299117063
+
+
+def test_snowflake_time():
+    """Test that the creation time is read from the ID."""
+    assert snowflake_time(EXAMPLE_ID) == datetime(
+        2016, 4, 30, 11, 18, 25, 796000, tzinfo=ZoneInfo("UTC")
+    )
+
+
+def test_snowflakes_sort_by_creation_time():
+    """Test that newer IDs are larger, so sorting IDs sorts by creation time."""
+    ids = [EXAMPLE_ID, 41771983423143937, 1234567890123456789]
+
+    assert sorted(ids) == sorted(ids, key=snowflake_time)
+
+
+def test_parse_snowflake_accepts_ints_and_strings():
+    """Test that IDs from JSON strings and ints parse the same."""
+    assert parse_snowflake("175928847299117063") == EXAMPLE_ID
+    assert parse_snowflake(EXAMPLE_ID) == EXAMPLE_ID
+
+
+@pytest.mark.parametrize("value", ["", "abc", "-5", 0, 2**64, True, 1.5, "9" * 19])
+def test_parse_snowflake_rejects_invalid(value):
+    """Test that non-numbers, out of range, and future IDs are rejected."""
+    with pytest.raises(ValueError):
+        parse_snowflake(value)
+
+
+def test_snowflake_json_round_trip():
+    """Test that IDs are written to JSON as strings and read back as ints."""
+    adapter = TypeAdapter(list[Snowflake])
+
+    assert adapter.dump_json([EXAMPLE_ID]) == b'["175928847299117063"]'
+    assert adapter.validate_json(b'["175928847299117063"]') == [EXAMPLE_ID]
+    with pytest.raises(ValidationError):
+        adapter.validate_python(["not-an-id"])
@@ -0,0 +1,43 @@
+"""Tests for sponsor blurb rotation."""
+
+from pathlib import Path
+
+from cnayp_bot.models import Sponsor
+from cnayp_bot.services.sponsors import SponsorRotation
+from cnayp_bot.services.store import Store
+
+SPONSORS = [
+    Sponsor(name="Acme Cloud", blurb="Free credits for study groups"),
+    Sponsor(name="K8s Books", blurb="20% off with code CNAYP"),
+]
+
+
+def test_rotation_advances_on_impressions(tmp_path: Path):
+    """Test that sponsors take turns only as blurbs are posted, across restarts."""
+    path = tmp_path / "store.json"
+    rotation = SponsorRotation(Store(path))
+
+    assert rotation.next(SPONSORS) == SPONSORS[0]
+    assert rotation.next(SPONSORS) == SPONSORS[0]
+    rotation.record_impression(SPONSORS[0], SPONSORS)
+    assert SponsorRotation(Store(path)).next(SPONSORS) == SPONSORS[1]
+    rotation.record_impression(SPONSORS[1], SPONSORS)
+    assert rotation.next(SPONSORS) == SPONSORS[0]
+
+
+def test_impressions_per_sponsor(tmp_path: Path):
+    """Test that impressions are counted per sponsor."""
+    rotation = SponsorRotation(Store(tmp_path / "store.json"))
+    for sponsor in [SPONSORS[0], SPONSORS[1], SPONSORS[0]]:
+        rotation.record_impression(sponsor, SPONSORS)
+
+    assert rotation.impressions() == {"Acme Cloud": 2, "K8s Books": 1}
+
+
+def test_no_sponsors(tmp_path: Path):
+    """Test that there's no sponsor to show without sponsors, even after removing some."""
+    rotation = SponsorRotation(Store(tmp_path / "store.json"))
+    rotation.record_impression(SPONSORS[0], SPONSORS)
+
+    assert rotation.next([]) is None
+    assert rotation.next(SPONSORS[:1]) == SPONSORS[0]
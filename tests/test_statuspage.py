@@ -0,0 +1,100 @@
+"""Tests for status page incidents."""
+
+from pathlib import Path
+
+from cnayp_bot.config import StatusPage
+from cnayp_bot.services.statuspage import (
+    Incident,
+    IncidentTracker,
+    is_api_incident,
+    is_discord_page,
+    parse_instatus,
+    parse_statuspage,
+)
+from cnayp_bot.services.store import Store
+
+
+def _incident(status: str = "investigating", update_id: str = "u1", **kwargs) -> Incident:
+    return Incident(id="abc", name="API errors", status=status, update_id=update_id, **kwargs)
+
+
+def test_parse_statuspage_uses_the_latest_update():
+    """Test reading a Statuspage incident list."""
+    data = {
+        "incidents": [
+            {
+                "id": "abc",
+                "name": "Elevated API errors",
+                "status": "monitoring",
+                "impact": "major",
+                "shortlink": "https://stspg.io/abc",
+                "incident_updates": [
+                    {"id": "u2", "body": "A fix has been deployed."},
+                    {"id": "u1", "body": "We are investigating."},
+                ],
+                "components": [{"name": "API"}, {"name": "Gateway"}],
+            }
+        ]
+    }
+
+    [incident] = parse_statuspage(data)
+
+    assert incident.name == "Elevated API errors"
+    assert incident.impact == "major"
+    assert incident.update == "A fix has been deployed."
+    assert incident.update_id == "u2"
+    assert incident.components == ["API", "Gateway"]
+    assert not incident.resolved
+
+
+def test_parse_instatus_lowercases_statuses():
+    """Test reading an Instatus summary."""
+    data = {
+        "activeIncidents": [
+            {"id": "xyz", "name": "Outage", "status": "INVESTIGATING", "impact": "MAJOROUTAGE"}
+        ]
+    }
+
+    [incident] = parse_instatus(data)
+
+    assert incident.status == "investigating"
+    assert incident.impact == "majoroutage"
+    assert incident.update_id == "investigating"
+
+
+def test_tracker_reports_start_update_and_resolve(tmp_path: Path):
+    """Test that an incident is reported once per update, then resolved."""
+    tracker = IncidentTracker(Store(tmp_path / "store.json"))
+
+    assert [change for change, _ in tracker.changes("Discord", [_incident()])] == ["started"]
+    assert tracker.changes("Discord", [_incident()]) == []
+    updated = _incident("identified", "u2")
+    assert tracker.changes("Discord", [updated]) == [("updated", updated)]
+    resolved = _incident("resolved", "u3")
+    assert tracker.changes("Discord", [resolved]) == [("resolved", resolved)]
+    assert tracker.changes("Discord", [resolved]) == []
+
+
+def test_tracker_skips_past_incidents_and_resolves_missing_ones(tmp_path: Path):
+    """Test that old incidents aren't replayed and vanished ones count as resolved."""
+    tracker = IncidentTracker(Store(tmp_path / "store.json"))
+
+    assert tracker.changes("Discord", [_incident("resolved")]) == []
+    tracker.changes("Discord", [_incident()])
+    tracker.changes("GitHub", [_incident()])
+
+    [(change, incident)] = tracker.changes("Discord", [])
+    assert change == "resolved"
+    assert incident.name == "API errors"
+    assert tracker.changes("GitHub", [_incident()]) == []
+
+
+def test_api_incidents_on_discords_page():
+    """Test which incidents suppress alerting."""
+    assert is_discord_page(StatusPage(url="https://discordstatus.com"))
+    assert not is_discord_page(StatusPage(url="https://www.githubstatus.com"))
+
+    assert is_api_incident(_incident(components=["API"]))
+    assert is_api_incident(_incident())
+    assert not is_api_incident(_incident(components=["Media Proxy"]))
+    assert not is_api_incident(_incident("resolved", components=["API"]))
@@ -0,0 +1,69 @@
+"""Tests for the persistent store."""
+
+from pathlib import Path
+
+from cnayp_bot.services.store import Store
+
+
+def test_set_and_get(tmp_path: Path):
+    """Test that values round-trip through the store."""
+    store = Store(tmp_path / "store.json")
+    store.set("timezones", "42", "America/Lima")
+
+    assert store.get("timezones", "42") == "America/Lima"
+    assert store.get("timezones", "missing", "UTC") == "UTC"
+    assert store.get("unknown", "42") is None
+
+
+def test_persists_across_instances(tmp_path: Path):
+    """Test that values survive a restart."""
+    path = tmp_path / "data" / "store.json"
+    Store(path).set("reminders", "abc", {"due": "2025-03-01T10:00:00+00:00"})
+
+    assert Store(path).items("reminders") == {"abc": {"due": "2025-03-01T10:00:00+00:00"}}
+
+
+def test_reload_reads_other_instances_writes(tmp_path: Path):
+    """Test that a store reloaded picks up what another instance wrote to the same database."""
+    path = tmp_path / "store.db"
+    store = Store(path)
+    Store(path).set("sent_reminders", "evt1:15", {"end": "2025-03-01T11:00:00+00:00"})
+    assert store.get("sent_reminders", "evt1:15") is None
+
+    store.reload()
+
+    assert store.get("sent_reminders", "evt1:15") == {"end": "2025-03-01T11:00:00+00:00"}
+
+
+def test_delete(tmp_path: Path):
+    """Test deleting values."""
+    store = Store(tmp_path / "store.json")
+    store.set("reminders", "abc", 1)
+    store.delete("reminders", "abc")
+    store.delete("reminders", "missing")
+
+    assert store.items("reminders") == {}
+
+
+def test_sqlite_store_persists_each_value(tmp_path: Path):
+    """Test that `.db` stores keep values across restarts, including deletions."""
+    path = tmp_path / "data" / "store.db"
+    store = Store(path)
+    store.set("rsvps", "evt1", {"going": [5]})
+    store.set("rsvps", "evt2", {"going": []})
+    store.delete("rsvps", "evt2")
+
+    assert Store(path).items("rsvps") == {"evt1": {"going": [5]}}
+    assert Store(path).namespaces() == {"rsvps": 1}
+
+
+def test_sqlite_store_imports_the_json_store(tmp_path: Path):
+    """Test that an empty database imports the JSON store beside it, once."""
+    Store(tmp_path / "store.json").set("timezones", "42", "America/Lima")
+
+    store = Store(tmp_path / "store.db")
+    assert store.get("timezones", "42") == "America/Lima"
+
+    store.set("timezones", "42", "Europe/Madrid")
+    assert Store(tmp_path / "store.db").get("timezones", "42") == "Europe/Madrid"
+    assert Store(tmp_path / "store.json").get("timezones", "42") == "America/Lima"
@@ -0,0 +1,73 @@
+"""Tests for the event submission approval queue."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+import pytest
+from pydantic import ValidationError
+
+from cnayp_bot.models import EventSubmission
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.submissions import SubmissionQueue, is_submission
+
+UTC = ZoneInfo("UTC")
+START = datetime(2025, 3, 10, 18, 0, tzinfo=UTC)
+
+
+def make_submission(name: str = "Intro to eBPF", start: datetime = START) -> EventSubmission:
+    return EventSubmission(name=name, time=start, duration=60, channel="Talks")
+
+
+def test_submission_requires_timezone():
+    """Test that a submitted time without an offset is rejected."""
+    with pytest.raises(ValidationError):
+        EventSubmission(name="Talk", time=datetime(2025, 3, 10, 18, 0), duration=60, channel="x")
+
+
+def test_only_approved_submissions_become_events(tmp_path: Path):
+    """Test that pending and rejected submissions aren't served as events."""
+    queue = SubmissionQueue(Store(tmp_path / "store.json"))
+    approved = queue.add(make_submission("Approved talk"))
+    rejected = queue.add(make_submission("Rejected talk"))
+    queue.add(make_submission("Pending talk"))
+
+    assert queue.decide(approved, approved=True).name == "Approved talk"
+    assert queue.decide(rejected, approved=False).name == "Rejected talk"
+    assert queue.decide(approved, approved=False) is None
+    assert queue.decide("missing", approved=True) is None
+
+    events = queue.get_events_between(START - timedelta(hours=1), START + timedelta(hours=1))
+    assert [event.name for event in events] == ["Approved talk"]
+    assert is_submission(events[0])
+    assert events[0].voice_channel == "Talks"
+    assert events[0].duration_minutes == 60
+
+
+def test_forget_finished_drops_old_submissions(tmp_path: Path):
+    """Test that submissions are dropped a day after their event ends."""
+    path = tmp_path / "store.json"
+    queue = SubmissionQueue(Store(path))
+    old = queue.add(make_submission("Last week", START - timedelta(days=7)))
+    upcoming = queue.add(make_submission("Today", START))
+
+    queue.forget_finished(START)
+
+    restored = SubmissionQueue(Store(path))
+    assert restored.get(old) is None
+    assert restored.get(upcoming)["status"] == "pending"
+
+
+def test_find_approved_dedups_one_off_events(tmp_path: Path):
+    """Test that an approved submission is found again by its name and start."""
+    queue = SubmissionQueue(Store(tmp_path / "store.json"))
+    pending = queue.add(make_submission("Office hours"))
+
+    assert queue.find_approved(make_submission("Office hours")) is None
+    assert queue.event(pending) is None
+
+    queue.decide(pending, approved=True)
+
+    assert queue.find_approved(make_submission("Office hours")) == pending
+    assert queue.find_approved(make_submission("Office hours", START + timedelta(days=1))) is None
+    assert queue.event(pending).name == "Office hours"
@@ -0,0 +1,36 @@
+"""Tests for the per-guild values templates include with {$name}."""
+
+from pathlib import Path
+
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.template_values import TemplateValues
+
+
+def test_values_are_filled_in_per_guild(tmp_path: Path):
+    """Test that {$name} is replaced with the guild's value, and unset values are dropped."""
+    values = TemplateValues(Store(tmp_path / "store.json"))
+    values.set(1, "notes", "https://hackmd.io/abc")
+    values.set(2, "notes", "https://hackmd.io/other")
+
+    assert values.expand("**{name}** notes: {$notes}{$zoom}", 1) == (
+        "**{name}** notes: https://hackmd.io/abc"
+    )
+
+
+def test_value_braces_are_kept_as_text(tmp_path: Path):
+    """Test that braces in a value aren't read as placeholders when the template is filled."""
+    values = TemplateValues(Store(tmp_path / "store.json"))
+    values.set(1, "passcode", "{name}")
+
+    assert values.expand("Passcode: {$passcode}", 1).format(name="x") == "Passcode: {name}"
+
+
+def test_removed_values_are_left_out(tmp_path: Path):
+    """Test that a removed value isn't included, and removing it again reports it's gone."""
+    values = TemplateValues(Store(tmp_path / "store.json"))
+    values.set(1, "zoom", "https://zoom.us/j/1")
+
+    assert values.delete(1, "zoom")
+    assert not values.delete(1, "zoom")
+    assert values.all(1) == {}
+    assert values.expand("Join: {$zoom}", 1) == "Join: "
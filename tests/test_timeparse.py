@@ -0,0 +1,72 @@
+"""Tests for natural language time parsing."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from cnayp_bot.helpers.timeparse import parse_duration, parse_time, parse_time_prefix
+
+LIMA = ZoneInfo("America/Lima")
+# Wednesday
+NOW = datetime(2025, 3, 12, 15, 30, tzinfo=LIMA)
+
+
+@pytest.mark.parametrize(
+    "text, expected",
+    [
+        ("45m", timedelta(minutes=45)),
+        ("1h30m", timedelta(hours=1, minutes=30)),
+        ("2 hours and 15 minutes", timedelta(hours=2, minutes=15)),
+        ("30d", timedelta(days=30)),
+        ("1 semana", timedelta(weeks=1)),
+    ],
+)
+def test_parse_duration(text, expected):
+    """Test duration parsing."""
+    assert parse_duration(text) == expected
+
+
+@pytest.mark.parametrize("text", ["", "soon", "10 parsecs", "5m later"])
+def test_parse_duration_invalid(text):
+    """Test that invalid durations are rejected."""
+    with pytest.raises(ValueError):
+        parse_duration(text)
+
+
+@pytest.mark.parametrize(
+    "text, expected",
+    [
+        ("in 45 min", datetime(2025, 3, 12, 16, 15, tzinfo=LIMA)),
+        ("en 2 horas", datetime(2025, 3, 12, 17, 30, tzinfo=LIMA)),
+        ("tomorrow 7pm", datetime(2025, 3, 13, 19, 0, tzinfo=LIMA)),
+        ("mañana a las 19:00", datetime(2025, 3, 13, 19, 0, tzinfo=LIMA)),
+        ("today 18:00", datetime(2025, 3, 12, 18, 0, tzinfo=LIMA)),
+        ("8pm", datetime(2025, 3, 12, 20, 0, tzinfo=LIMA)),
+        ("10am", datetime(2025, 3, 13, 10, 0, tzinfo=LIMA)),
+        ("friday 7pm", datetime(2025, 3, 14, 19, 0, tzinfo=LIMA)),
+        ("next friday", datetime(2025, 3, 14, 9, 0, tzinfo=LIMA)),
+        ("wednesday 10am", datetime(2025, 3, 19, 10, 0, tzinfo=LIMA)),
+        ("next wednesday 6pm", datetime(2025, 3, 19, 18, 0, tzinfo=LIMA)),
+        ("próximo viernes 18:30", datetime(2025, 3, 14, 18, 30, tzinfo=LIMA)),
+        ("2025-04-01 18:00", datetime(2025, 4, 1, 18, 0, tzinfo=LIMA)),
+    ],
+)
+def test_parse_time(text, expected):
+    """Test parsing of natural language times."""
+    assert parse_time(text, NOW) == expected
+
+
+@pytest.mark.parametrize("text", ["", "someday", "friday 7", "25:00", "13pm"])
+def test_parse_time_invalid(text):
+    """Test that unparseable times are rejected."""
+    with pytest.raises(ValueError):
+        parse_time(text, NOW)
+
+
+def test_parse_time_prefix():
+    """Test splitting a time expression from the rest of a command."""
+    when, rest = parse_time_prefix("in 45 min check the oven", NOW)
+
+    assert when == datetime(2025, 3, 12, 16, 15, tzinfo=LIMA)
+    assert rest == "check the oven"
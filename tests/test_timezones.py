@@ -0,0 +1,23 @@
+"""Tests for event times shown in reference timezones."""
+
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.helpers.timezones import reference_times
+
+LIMA = ZoneInfo("America/Lima")
+
+
+def test_reference_times_name_the_day_where_it_differs():
+    """Test that each zone shows its local time, with the weekday where it's another day."""
+    start = datetime(2025, 3, 10, 18, 0, tzinfo=LIMA)
+    zones = ["America/Lima", "Europe/Madrid", "America/New_York"]
+
+    assert reference_times(start, zones, LIMA) == (
+        "Lima 18:00 · Madrid 00:00 (Tue) · New York 19:00"
+    )
+
+
+def test_no_reference_times_without_zones():
+    """Test that nothing is shown when no reference timezones are configured."""
+    assert reference_times(datetime(2025, 3, 10, 18, 0, tzinfo=LIMA), [], LIMA) == ""
@@ -0,0 +1,75 @@
+"""Tests for topic suggestions and votes."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.topic_votes import TOPIC_LENGTH, TopicVotes, pick_winner
+
+START = datetime(2025, 3, 10, 19, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def _votes(tmp_path: Path) -> TopicVotes:
+    votes = TopicVotes(Store(tmp_path / "store.json"))
+    votes.open("study-1", "KCNA Study", START, 10, 500)
+    return votes
+
+
+def test_members_get_one_suggestion_each(tmp_path: Path):
+    """Test that replying again replaces a member's suggestion."""
+    votes = _votes(tmp_path)
+
+    assert votes.suggest("study-1", 1, "Helm", limit=3) == "added"
+    assert votes.suggest("study-1", 2, "Operators", limit=3) == "added"
+    assert votes.suggest("study-1", 1, "Kustomize", limit=3) == "replaced"
+    assert votes.suggest("study-1", 3, "x" * 500, limit=3) == "added"
+    assert votes.suggest("study-1", 4, "Too many", limit=3) == "full"
+
+    suggestions = votes.get("study-1")["suggestions"]
+    assert [suggestion["text"][:9] for suggestion in suggestions] == [
+        "Kustomize",
+        "Operators",
+        "x" * 9,
+    ]
+    assert len(suggestions[2]["text"]) == TOPIC_LENGTH
+    assert votes.find(500) == "study-1"
+
+
+def test_votes_are_posted_then_counted_before_the_event(tmp_path: Path):
+    """Test when votes are due, and that suggestions close once the vote is up."""
+    votes = _votes(tmp_path)
+    day, hour = timedelta(days=1), timedelta(hours=1)
+
+    assert votes.due_votes(START - 2 * day, day) == []
+    assert votes.due_votes(START - day, day) == ["study-1"]
+
+    votes.start_vote("study-1", 600, ["1️⃣"])
+    assert votes.due_votes(START - day, day) == []
+    assert votes.suggest("study-1", 1, "Helm", limit=3) == "closed"
+    assert votes.due_results(START - 2 * hour, hour) == []
+    assert votes.due_results(START - hour, hour) == ["study-1"]
+
+    votes.close("study-1", "Helm")
+    assert votes.due_results(START, hour) == []
+    assert votes.get("study-1")["winner"] == "Helm"
+
+
+def test_finished_votes_are_forgotten(tmp_path: Path):
+    """Test that votes are dropped a day after their event started."""
+    votes = _votes(tmp_path)
+
+    votes.forget_finished(START + timedelta(hours=2))
+    assert votes.get("study-1") is not None
+    votes.forget_finished(START + timedelta(days=2))
+    assert votes.get("study-1") is None
+
+
+def test_most_votes_win_and_ties_go_to_the_earliest():
+    """Test picking the winning suggestion."""
+    suggestions = [{"text": "Helm"}, {"text": "Operators"}, {"text": "eBPF"}]
+
+    assert pick_winner(suggestions, [1, 3, 2])["text"] == "Operators"
+    assert pick_winner(suggestions, [2, 2, 1])["text"] == "Helm"
+    assert pick_winner(suggestions, [0, 0, 0]) is None
+    assert pick_winner([], []) is None
@@ -0,0 +1,40 @@
+"""Tests for channel topic rendering."""
+
+from datetime import datetime, timedelta
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.helpers.topics import TOPIC_LIMIT, render_topic
+from cnayp_bot.services.calendar import CalendarEvent
+
+NOW = datetime(2025, 3, 10, 12, 0, tzinfo=ZoneInfo("UTC"))
+
+
+def make_event(name: str, start: datetime) -> CalendarEvent:
+    return CalendarEvent(
+        id=name,
+        name=name,
+        description="",
+        start_time=start,
+        end_time=start + timedelta(hours=1),
+        timezone="UTC",
+    )
+
+
+def test_render_next_event_and_theme():
+    """Test that the next upcoming event, theme, and digest link are filled in."""
+    events = [
+        make_event("Go Study", NOW + timedelta(days=2)),
+        make_event("Past", NOW - timedelta(hours=1)),
+        make_event("KCNA Session", NOW + timedelta(hours=6)),
+    ]
+    template = "Next: {next_event} {next_time} | Theme: {week_theme} | {digest_link}"
+    topic = render_topic(template, events, NOW, "Networking", "https://discord.com/x")
+
+    start = int((NOW + timedelta(hours=6)).timestamp())
+    assert topic == f"Next: KCNA Session <t:{start}:F> | Theme: Networking | https://discord.com/x"
+
+
+def test_render_without_events():
+    """Test the fallback when nothing is scheduled, and the length limit."""
+    assert render_topic("Next: {next_event}", [], NOW) == "Next: No upcoming events"
+    assert len(render_topic("x" * 2000, [], NOW)) == TOPIC_LIMIT
@@ -0,0 +1,44 @@
+"""Tests for channel transcript rendering."""
+
+import json
+from datetime import datetime
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.helpers.transcript import TranscriptMessage, render_html, render_json
+
+UTC = ZoneInfo("UTC")
+SINCE = datetime(2025, 3, 1, tzinfo=UTC)
+
+MESSAGES = [
+    TranscriptMessage(1, "ana", 10, datetime(2025, 3, 2, 18, 0, tzinfo=UTC), "Plan for <Friday>?"),
+    TranscriptMessage(
+        2,
+        "luis",
+        11,
+        datetime(2025, 3, 2, 18, 5, tzinfo=UTC),
+        "Agenda attached",
+        attachments=["https://cdn.example.com/agenda.pdf"],
+        reply_to=1,
+    ),
+]
+
+
+def test_render_json():
+    """Test that the JSON transcript keeps every message field in order."""
+    data = json.loads(render_json("planning", SINCE, MESSAGES))
+
+    assert data["channel"] == "planning"
+    assert data["message_count"] == 2
+    assert data["messages"][0]["created_at"] == "2025-03-02T18:00:00+00:00"
+    assert data["messages"][1]["reply_to"] == 1
+    assert data["messages"][1]["attachments"] == ["https://cdn.example.com/agenda.pdf"]
+
+
+def test_render_html_escapes_content():
+    """Test that message content can't inject markup into the HTML transcript."""
+    page = render_html("planning", SINCE, MESSAGES)
+
+    assert "Plan for &lt;Friday&gt;?" in page
+    assert "<Friday>" not in page
+    assert "↪ reply to 1" in page
+    assert page.index("ana") < page.index("luis")
@@ -0,0 +1,23 @@
+"""Tests for release version comparisons."""
+
+import pytest
+
+from cnayp_bot.services.updates import is_newer, parse_version
+
+
+def test_release_tags_parse_to_numbers():
+    """Test that tags with a leading v or a prerelease suffix parse."""
+    assert parse_version("v1.2.0") == (1, 2, 0)
+    assert parse_version("0.10.3") == (0, 10, 3)
+    assert parse_version("v2.0.0-rc.1") == (2, 0, 0)
+    with pytest.raises(ValueError):
+        parse_version("latest")
+
+
+def test_only_later_versions_are_newer():
+    """Test comparing release tags with the running version."""
+    assert is_newer("v0.2.0", "0.1.0")
+    assert is_newer("v0.10.0", "0.9.1")
+    assert not is_newer("v0.1.0", "0.1.0")
+    assert not is_newer("v0.0.9", "0.1.0")
+    assert not is_newer("nightly", "0.1.0")
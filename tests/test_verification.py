@@ -0,0 +1,55 @@
+"""Tests for the verification gate."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.verification import VerificationGate
+
+JOINED = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("UTC"))
+TIMEOUT = timedelta(hours=24)
+KICK_AFTER = timedelta(days=7)
+
+
+def test_members_are_reminded_once(tmp_path: Path):
+    """Test that a reminder is due after the timeout, once, across restarts."""
+    path = tmp_path / "store.json"
+    VerificationGate(Store(path)).add(1, JOINED)
+    gate = VerificationGate(Store(path))
+
+    assert gate.due_reminders(JOINED + timedelta(hours=23), TIMEOUT) == []
+    assert gate.due_reminders(JOINED + TIMEOUT, TIMEOUT) == [1]
+    gate.mark_reminded(1)
+    assert VerificationGate(Store(path)).due_reminders(JOINED + TIMEOUT, TIMEOUT) == []
+
+
+def test_unverified_members_expire(tmp_path: Path):
+    """Test that members are due for a kick only after the deadline."""
+    gate = VerificationGate(Store(tmp_path / "store.json"))
+    gate.add(1, JOINED)
+    gate.add(2, JOINED + timedelta(days=2))
+
+    assert gate.expired(JOINED + KICK_AFTER, KICK_AFTER) == [1]
+
+
+def test_verified_members_leave_the_gate(tmp_path: Path):
+    """Test that removed members are no longer pending, reminded, or kicked."""
+    gate = VerificationGate(Store(tmp_path / "store.json"))
+    gate.add(1, JOINED)
+
+    assert gate.is_pending(1)
+    assert gate.remove(1)
+    assert not gate.remove(1)
+    assert not gate.is_pending(1)
+    assert gate.due_reminders(JOINED + KICK_AFTER, TIMEOUT) == []
+    assert gate.expired(JOINED + KICK_AFTER, KICK_AFTER) == []
+
+
+def test_gate_message_per_guild(tmp_path: Path):
+    """Test that the gate message is remembered per guild."""
+    gate = VerificationGate(Store(tmp_path / "store.json"))
+    gate.set_message(10, 20, 30)
+
+    assert gate.message(10) == {"channel_id": 20, "message_id": 30}
+    assert gate.message(11) is None
@@ -0,0 +1,44 @@
+"""Tests for the welcome DM sequence."""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from zoneinfo import ZoneInfo
+
+from cnayp_bot.services.store import Store
+from cnayp_bot.services.welcome import WelcomeSequence
+
+JOINED = datetime(2025, 3, 3, 18, 0, tzinfo=ZoneInfo("UTC"))
+STEPS = {0: "Welcome!", 2: "Here's how events work", 7: "Introduce yourself"}
+
+
+def test_steps_become_due_and_survive_restart(tmp_path: Path):
+    """Test that each step is due once, after its delay, across restarts."""
+    path = tmp_path / "store.json"
+    WelcomeSequence(Store(path)).start(1, JOINED)
+    sequence = WelcomeSequence(Store(path))
+
+    assert sequence.due(JOINED, STEPS) == [(1, 0)]
+    sequence.mark_sent(1, 0, STEPS)
+    assert sequence.due(JOINED + timedelta(days=1), STEPS) == []
+    assert WelcomeSequence(Store(path)).due(JOINED + timedelta(days=2), STEPS) == [(1, 2)]
+
+
+def test_missed_steps_are_skipped(tmp_path: Path):
+    """Test that only the latest due step is sent after downtime, and the sequence ends."""
+    store = Store(tmp_path / "store.json")
+    sequence = WelcomeSequence(store)
+    sequence.start(1, JOINED)
+
+    assert sequence.due(JOINED + timedelta(days=8), STEPS) == [(1, 7)]
+    sequence.mark_sent(1, 7, STEPS)
+    assert store.items("welcome_dms") == {}
+
+
+def test_members_who_leave_are_removed(tmp_path: Path):
+    """Test that stopping a member's sequence drops their pending steps."""
+    sequence = WelcomeSequence(Store(tmp_path / "store.json"))
+    sequence.start(1, JOINED)
+    sequence.start(2, JOINED)
+    sequence.stop(1)
+
+    assert sequence.due(JOINED, STEPS) == [(2, 0)]